@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // uploading a single file
@@ -85,11 +86,70 @@ func (server *Server) uploadSingleFile(file multipart.File, header *multipart.Fi
 	return files, nil
 }
 
+// defaultAssignmentMaxAttempts keeps one submission per student unless more attempts are allowed
+const defaultAssignmentMaxAttempts = 1
+
+// assignmentDeadline holds the parsed due date and late policy fields of an assignment form
+type assignmentDeadline struct {
+	DueDate            pgtype.Timestamptz
+	LatePolicy         string
+	LatePenaltyPercent int64
+	LateCutoff         pgtype.Timestamptz
+}
+
+// parseAssignmentDeadline checks the due date and late policy sent with an assignment.
+// An empty due date means the assignment has no deadline and the policy defaults to accept.
+func parseAssignmentDeadline(dueDate, latePolicy string, latePenaltyPercent int64, lateCutoff string) (assignmentDeadline, error) {
+	deadline := assignmentDeadline{
+		LatePolicy:         latePolicy,
+		LatePenaltyPercent: latePenaltyPercent,
+	}
+	if deadline.LatePolicy == "" {
+		deadline.LatePolicy = util.LatePolicyAccept
+	}
+
+	policy := util.LatePolicy{Policy: deadline.LatePolicy, PenaltyPercent: latePenaltyPercent}
+	if err := policy.Validate(); err != nil {
+		return deadline, err
+	}
+	if deadline.LatePolicy != util.LatePolicyPenalty {
+		deadline.LatePenaltyPercent = 0
+	}
+
+	if strings.TrimSpace(dueDate) != "" {
+		t, err := util.ParseDueDate(dueDate, time.Local)
+		if err != nil {
+			return deadline, err
+		}
+		deadline.DueDate = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	if strings.TrimSpace(lateCutoff) != "" {
+		t, err := util.ParseDueDate(lateCutoff, time.Local)
+		if err != nil {
+			return deadline, err
+		}
+		if deadline.DueDate.Valid && t.Before(deadline.DueDate.Time) {
+			return deadline, errors.New("late cutoff cannot be before the due date")
+		}
+		deadline.LateCutoff = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	return deadline, nil
+}
+
 type createAssignmentRequest struct {
-	Title          string `form:"title"`
-	CourseID       int64  `form:"course_id"`
-	DueDate        string `form:"due_date"`
-	AssignmentFile string `json:"assignment_file"`
+	Title              string `form:"title"`
+	CourseID           int64  `form:"course_id"`
+	DueDate            string `form:"due_date"`
+	AssignmentFile     string `json:"assignment_file"`
+	LatePolicy         string `form:"late_policy"`
+	LatePenaltyPercent int64  `form:"late_penalty_percent"`
+	LateCutoff         string `form:"late_cutoff"`
+	// MaxAttempts limits the submission attempts per student, zero means unlimited
+	MaxAttempts *int64 `form:"max_attempts" binding:"omitempty,min=0"`
+	// GroupSubmission takes one submission per course group instead of one per student
+	GroupSubmission *bool `form:"group_submission"`
 }
 
 // @Summary Create a new assignment
@@ -132,16 +192,27 @@ func (server *Server) CreateAssignment(ctx *gin.Context) {
 	// Update request struct with filename (if uploaded)
 	req.AssignmentFile = assignmentFile
 
+	deadline, err := parseAssignmentDeadline(req.DueDate, req.LatePolicy, req.LatePenaltyPercent, req.LateCutoff)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid due date format: " + err.Error()})
 		return
 	}
 
 	arg := db.CreateAssignmentParams{
-		Title:          req.Title,
-		DueDate:        req.DueDate,
-		AssignmentFile: assignmentFile,
-		CourseID:       req.CourseID,
+		Title:              req.Title,
+		DueDate:            deadline.DueDate,
+		AssignmentFile:     assignmentFile,
+		CourseID:           req.CourseID,
+		LatePolicy:         deadline.LatePolicy,
+		LatePenaltyPercent: deadline.LatePenaltyPercent,
+		LateCutoff:         deadline.LateCutoff,
+		MaxAttempts:        defaultAssignmentMaxAttempts,
+	}
+	if req.MaxAttempts != nil {
+		arg.MaxAttempts = *req.MaxAttempts
+	}
+	if req.GroupSubmission != nil {
+		arg.GroupSubmission = *req.GroupSubmission
 	}
 
 	assignment, err := server.store.CreateAssignment(ctx, db.CreateAssignmentParams(arg))
@@ -150,6 +221,8 @@ func (server *Server) CreateAssignment(ctx *gin.Context) {
 		return
 	}
 
+	server.recomputeCourseProgress(ctx, assignment.CourseID)
+
 	ctx.JSON(http.StatusOK, assignment)
 }
 
@@ -164,6 +237,7 @@ type GetAssignmentRequest struct {
 // @Param assignment_id path int true "Assignment ID"
 // @Success 200
 // @Failure 400
+// @Failure 401
 // @Failure 404
 // @Failure 500
 // @Router /assignment/get [get]
@@ -184,16 +258,28 @@ func (server *Server) getAssignment(ctx *gin.Context) {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.requireCourseAccess(ctx, authPayload, assignment.CourseID) {
+		return
+	}
+
 	ctx.JSON(http.StatusOK, assignment)
 }
 
 // UpdateAssignmentReques contains the input parameters of update a Assignment
 type UpdateAssignmentRequest struct {
-	AssignmentID   int64  `form:"assignment_id"`
-	Title          string `form:"title"`
-	DueDate        string `form:"due_date"`
-	AssignmentFile string `json:"assignment_file"`
-	CourseID       int64  `form:"course_id"`
+	AssignmentID       int64  `form:"assignment_id"`
+	Title              string `form:"title"`
+	DueDate            string `form:"due_date"`
+	AssignmentFile     string `json:"assignment_file"`
+	CourseID           int64  `form:"course_id"`
+	LatePolicy         string `form:"late_policy"`
+	LatePenaltyPercent int64  `form:"late_penalty_percent"`
+	LateCutoff         string `form:"late_cutoff"`
+	// MaxAttempts limits the submission attempts per student, zero means unlimited
+	MaxAttempts *int64 `form:"max_attempts" binding:"omitempty,min=0"`
+	// GroupSubmission takes one submission per course group instead of one per student
+	GroupSubmission *bool `form:"group_submission"`
 }
 
 // @Summary Update Asssignment
@@ -222,8 +308,21 @@ func (server *Server) UpdateAssignment(ctx *gin.Context) {
 		return
 	}
 
-	// Handle image upload (if included in the request)
-	var assignmentF string
+	deadline, err := parseAssignmentDeadline(req.DueDate, req.LatePolicy, req.LatePenaltyPercent, req.LateCutoff)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	//previous details to keep or remove the file
+	getAssignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Handle file upload (if included in the request)
+	assignmentF := getAssignment.AssignmentFile
 	file, header, err := ctx.Request.FormFile("assignment_file")
 	if err == nil {
 		assignmentF, err = server.uploadSingleFile(file, header)
@@ -232,27 +331,26 @@ func (server *Server) UpdateAssignment(ctx *gin.Context) {
 			return
 		}
 	}
-	// Update request struct with image filename (if uploaded)
+	// Update request struct with filename (if uploaded)
 	req.AssignmentFile = assignmentF
 
-	//previous details to remove file
-	getAssignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
-
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-	//assign file path
-	value := getAssignment.AssignmentFile
-	//remove exiting file
-	util.DeleteFileByURL(value)
-
 	arg := db.UpdateAssignmentParams{
-		AssignmentID:   req.AssignmentID,
-		Title:          req.Title,
-		DueDate:        req.DueDate,
-		AssignmentFile: req.AssignmentFile,
-		CourseID:       req.CourseID,
+		AssignmentID:       req.AssignmentID,
+		Title:              req.Title,
+		DueDate:            deadline.DueDate,
+		AssignmentFile:     req.AssignmentFile,
+		CourseID:           req.CourseID,
+		LatePolicy:         deadline.LatePolicy,
+		LatePenaltyPercent: deadline.LatePenaltyPercent,
+		LateCutoff:         deadline.LateCutoff,
+		MaxAttempts:        getAssignment.MaxAttempts,
+		GroupSubmission:    getAssignment.GroupSubmission,
+	}
+	if req.MaxAttempts != nil {
+		arg.MaxAttempts = *req.MaxAttempts
+	}
+	if req.GroupSubmission != nil {
+		arg.GroupSubmission = *req.GroupSubmission
 	}
 	assignment, err := server.store.UpdateAssignment(ctx, db.UpdateAssignmentParams(arg))
 
@@ -261,6 +359,11 @@ func (server *Server) UpdateAssignment(ctx *gin.Context) {
 		return
 	}
 
+	//remove the replaced file
+	if getAssignment.AssignmentFile != "" && getAssignment.AssignmentFile != assignment.AssignmentFile {
+		util.DeleteFileByURL(getAssignment.AssignmentFile)
+	}
+
 	ctx.JSON(http.StatusOK, assignment)
 }
 
@@ -292,12 +395,191 @@ func (server *Server) deleteAssignment(ctx *gin.Context) {
 		return
 	}
 
-	err := server.store.DeleteAssignment(ctx, req.AssignmentID)
+	assignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.DeleteAssignment(ctx, req.AssignmentID)
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	server.recomputeCourseProgress(ctx, assignment.CourseID)
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Assignment deleted successfully"})
 }
+
+// ListAssignmentsRequest contains the input parameters for listing the assignments of a course
+type ListAssignmentsRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List assignments
+// @Description List the assignments of a course ordered by due date, assignments without a due date come last
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /assignments/list [get]
+func (server *Server) ListAssignments(ctx *gin.Context) {
+	var req ListAssignmentsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.requireCourseAccess(ctx, authPayload, req.CourseID) {
+		return
+	}
+
+	assignments, err := server.store.ListAssignmentsByCourse(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assignments)
+}
+
+// CreateAssignmentExtensionRequest defines the request body structure for extending a student's due date
+type CreateAssignmentExtensionRequest struct {
+	AssignmentID int64  `json:"assignment_id" binding:"required,min=1"`
+	UserID       int64  `json:"user_id" binding:"required,min=1"`
+	DueDate      string `json:"due_date" binding:"required"`
+}
+
+// @Summary Extend an assignment due date for a student
+// @Description Give a student a later due date, an existing extension is replaced
+// @Accept json
+// @Produce json
+// @Param request body CreateAssignmentExtensionRequest true "Assignment Extension Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /assignment/extension [post]
+func (server *Server) CreateAssignmentExtension(ctx *gin.Context) {
+	var req CreateAssignmentExtensionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an admin of this system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	dueDate, err := util.ParseDueDate(req.DueDate, time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	extension, err := server.store.CreateAssignmentExtension(ctx, db.CreateAssignmentExtensionParams{
+		AssignmentID: req.AssignmentID,
+		UserID:       req.UserID,
+		DueDate:      dueDate,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, extension)
+}
+
+// ListAssignmentExtensionsRequest contains the input parameters for listing due date extensions
+type ListAssignmentExtensionsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary List due date extensions
+// @Description List the students with an extended due date for an assignment
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /assignment/extensions [get]
+func (server *Server) ListAssignmentExtensions(ctx *gin.Context) {
+	var req ListAssignmentExtensionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an admin of this system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	extensions, err := server.store.ListAssignmentExtensions(ctx, req.AssignmentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, extensions)
+}
+
+// DeleteAssignmentExtensionRequest defines the request structure for removing a due date extension
+type DeleteAssignmentExtensionRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+	UserID       int64 `form:"user_id" binding:"required,min=1"`
+}
+
+// @Summary Remove a due date extension
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Param user_id query int true "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /assignment/extension/delete [delete]
+func (server *Server) DeleteAssignmentExtension(ctx *gin.Context) {
+	var req DeleteAssignmentExtensionRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an admin of this system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteAssignmentExtension(ctx, db.DeleteAssignmentExtensionParams{
+		AssignmentID: req.AssignmentID,
+		UserID:       req.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Extension removed successfully"})
+}
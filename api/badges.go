@@ -0,0 +1,336 @@
+package api
+
+import (
+	"eduApp/credential"
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCredentialSize bounds the body of a verification request
+const maxCredentialSize = 1 << 20
+
+// requireBadgeIssuer answers 503 when verifiable credentials are not configured
+func (server *Server) requireBadgeIssuer(ctx *gin.Context) bool {
+	if server.badgeIssuer == nil {
+		ctx.JSON(http.StatusServiceUnavailable, errorResponse(credential.ErrNotConfigured))
+		return false
+	}
+	return true
+}
+
+// @Summary Get the badge issuer
+// @Description Issuer profile of the Open Badges credentials, with the public key that verifies them
+// @ID get-badge-issuer
+// @Produce json
+// @Success 200
+// @Failure 503
+// @Router /badges/issuer [get]
+func (server *Server) GetBadgeIssuer(ctx *gin.Context) {
+	if !server.requireBadgeIssuer(ctx) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.badgeIssuer.Profile())
+}
+
+// GetBadgeClassRequest contains the course whose badge class is requested
+type GetBadgeClassRequest struct {
+	CourseID int64 `uri:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Get a badge class
+// @Description The Open Badges achievement earned by completing a course
+// @ID get-badge-class
+// @Produce json
+// @Param course_id path int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Router /badges/class/{course_id} [get]
+func (server *Server) GetBadgeClass(ctx *gin.Context) {
+	var req GetBadgeClassRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !server.requireBadgeIssuer(ctx) {
+		return
+	}
+
+	course, err := server.store.GetCourses(ctx, req.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	achievement := server.badgeIssuer.Achievement(course.CourseID, course.Title, course.Description)
+	achievement.Context = server.badgeIssuer.Profile().Context
+	ctx.JSON(http.StatusOK, achievement)
+}
+
+// @Summary Get the badge revocation list
+// @Description The 1EdTech revocation list linked from the credentialStatus of every credential
+// @ID get-badge-revocations
+// @Produce json
+// @Success 200
+// @Failure 500
+// @Failure 503
+// @Router /badges/revocations [get]
+func (server *Server) GetBadgeRevocations(ctx *gin.Context) {
+	if !server.requireBadgeIssuer(ctx) {
+		return
+	}
+
+	rows, err := server.store.ListRevokedCredentials(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	lastModified := time.Unix(0, 0)
+	revoked := make([]credential.RevokedCredential, 0, len(rows))
+	for _, row := range rows {
+		revoked = append(revoked, credential.RevokedCredential{
+			ID:               row.CredentialUri,
+			RevocationReason: row.RevocationReason,
+		})
+		if row.RevokedAt.Time.After(lastModified) {
+			lastModified = row.RevokedAt.Time
+		}
+	}
+
+	ctx.JSON(http.StatusOK, server.badgeIssuer.RevocationList(revoked, lastModified))
+}
+
+// GetBadgeCredentialRequest contains the id of a credential, a urn:uuid
+type GetBadgeCredentialRequest struct {
+	ID string `form:"id" binding:"required"`
+}
+
+// @Summary Get a badge credential
+// @Description The signed credential document, as shared by the student it was issued to
+// @ID get-badge-credential
+// @Produce json
+// @Param id query string true "Credential ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /badges/credential [get]
+func (server *Server) GetBadgeCredential(ctx *gin.Context) {
+	var req GetBadgeCredentialRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	issued, err := server.store.GetCredentialByURI(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/vc+ld+json", issued.Document)
+}
+
+// badgeVerification is the outcome of verifying a credential
+type badgeVerification struct {
+	Verified         bool       `json:"verified"`
+	ID               string     `json:"id,omitempty"`
+	Revoked          bool       `json:"revoked"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+	Error            string     `json:"error,omitempty"`
+}
+
+// @Summary Verify a badge credential
+// @Description Check the signature of a credential document and whether it was revoked
+// @ID verify-badge-credential
+// @Accept json
+// @Produce json
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Failure 503
+// @Router /badges/verify [post]
+func (server *Server) VerifyBadgeCredential(ctx *gin.Context) {
+	if !server.requireBadgeIssuer(ctx) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxCredentialSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(body) > maxCredentialSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(errors.New("credential is too large")))
+		return
+	}
+	if !json.Valid(body) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(credential.ErrMalformed))
+		return
+	}
+
+	id, err := server.badgeIssuer.Verify(body)
+	if err != nil {
+		if errors.Is(err, credential.ErrMalformed) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusOK, badgeVerification{ID: id, Error: err.Error()})
+		return
+	}
+
+	// the signature is ours, so the credential must be on record unless it was deleted with its course or student
+	issued, err := server.store.GetCredentialByURI(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusOK, badgeVerification{ID: id, Error: "credential is no longer on record"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result := badgeVerification{Verified: true, ID: id}
+	if issued.RevokedAt.Valid {
+		result.Verified = false
+		result.Revoked = true
+		result.RevokedAt = &issued.RevokedAt.Time
+		result.RevocationReason = issued.RevocationReason
+		result.Error = "credential was revoked"
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ListMyBadgeCredentialsRequest contains the input parameters for listing the credentials of the signed in student
+type ListMyBadgeCredentialsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// badgeCredentialResponse is a credential with its document as JSON
+type badgeCredentialResponse struct {
+	ID               string          `json:"id"`
+	CourseID         int64           `json:"course_id"`
+	IssuedAt         time.Time       `json:"issued_at"`
+	Revoked          bool            `json:"revoked"`
+	RevocationReason string          `json:"revocation_reason,omitempty"`
+	Document         json.RawMessage `json:"document"`
+}
+
+// @Summary List my badge credentials
+// @Description List the verifiable credentials issued to the signed in student, newest first
+// @ID list-my-badge-credentials
+// @Produce json
+// @Param page_id query int true "Page ID"
+// @Param page_size query int true "Page size"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /badges [get]
+func (server *Server) ListMyBadgeCredentials(ctx *gin.Context) {
+	var req ListMyBadgeCredentialsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	credentials, err := server.store.ListCredentialsByUser(ctx, db.ListCredentialsByUserParams{
+		UserID: authPayload.UserID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]badgeCredentialResponse, 0, len(credentials))
+	for _, issued := range credentials {
+		rsp = append(rsp, badgeCredentialResponse{
+			ID:               issued.CredentialUri,
+			CourseID:         issued.CourseID,
+			IssuedAt:         issued.IssuedAt,
+			Revoked:          issued.RevokedAt.Valid,
+			RevocationReason: issued.RevocationReason,
+			Document:         json.RawMessage(issued.Document),
+		})
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// RevokeBadgeCredentialRequest contains the credential to revoke and why
+type RevokeBadgeCredentialRequest struct {
+	ID     string `json:"id" binding:"required"`
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// @Summary Revoke a badge credential
+// @Description Put a credential on the revocation list, it then fails verification
+// @ID revoke-badge-credential
+// @Accept json
+// @Produce json
+// @Param request body RevokeBadgeCredentialRequest true "Credential to revoke"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /badges/revoke [put]
+func (server *Server) RevokeBadgeCredential(ctx *gin.Context) {
+	var req RevokeBadgeCredentialRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	revoked, err := server.store.RevokeCredential(ctx, db.RevokeCredentialParams{
+		CredentialUri:    req.ID,
+		RevocationReason: req.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err = errors.New("credential doesn't exist or is revoked already")
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, badgeCredentialResponse{
+		ID:               revoked.CredentialUri,
+		CourseID:         revoked.CourseID,
+		IssuedAt:         revoked.IssuedAt,
+		Revoked:          true,
+		RevocationReason: revoked.RevocationReason,
+		Document:         json.RawMessage(revoked.Document),
+	})
+}
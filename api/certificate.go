@@ -0,0 +1,103 @@
+package api
+
+import (
+	"eduApp/certificate"
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyCertificateRequest contains the code printed on a certificate
+type VerifyCertificateRequest struct {
+	Code string `uri:"code" binding:"required"`
+}
+
+// certificateVerification is what anyone holding a certificate code may see, it leaves out the student's account
+type certificateVerification struct {
+	Valid          bool      `json:"valid"`
+	Code           string    `json:"code"`
+	StudentName    string    `json:"student_name"`
+	CourseTitle    string    `json:"course_title"`
+	InstructorName string    `json:"instructor_name"`
+	IssuedAt       time.Time `json:"issued_at"`
+	File           string    `json:"file"`
+}
+
+// @Summary Verify a certificate
+// @Description Confirm a completion certificate is genuine and show who it was issued to
+// @ID verify-certificate
+// @Produce json
+// @Param code path string true "Certificate code"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /certificates/verify/{code} [get]
+func (server *Server) VerifyCertificate(ctx *gin.Context) {
+	var req VerifyCertificateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	cert, err := server.store.GetCertificateByCode(ctx, certificate.NormalizeCode(req.Code))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("no certificate was issued with this code")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, certificateVerification{
+		Valid:          true,
+		Code:           cert.Code,
+		StudentName:    cert.StudentName,
+		CourseTitle:    cert.CourseTitle,
+		InstructorName: cert.InstructorName,
+		IssuedAt:       cert.IssuedAt,
+		File:           cert.File,
+	})
+}
+
+// ListMyCertificatesRequest contains the input parameters for listing the certificates of the signed in student
+type ListMyCertificatesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// @Summary List my certificates
+// @Description List the completion certificates issued to the signed in student, newest first
+// @ID list-my-certificates
+// @Produce json
+// @Param page_id query int true "Page ID"
+// @Param page_size query int true "Page size"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /certificates [get]
+func (server *Server) ListMyCertificates(ctx *gin.Context) {
+	var req ListMyCertificatesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	certificates, err := server.store.ListCertificatesByUser(ctx, db.ListCertificatesByUserParams{
+		UserID: authPayload.UserID,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, certificates)
+}
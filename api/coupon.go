@@ -0,0 +1,242 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/payments"
+	"eduApp/token"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	errCouponNotFound     = errors.New("coupon not found")
+	errCouponExpired      = errors.New("coupon has expired")
+	errCouponUsedUp       = errors.New("coupon has no uses left")
+	errCouponOtherCourse  = errors.New("coupon is not valid for this course")
+	errCouponCurrency     = errors.New("coupon is in a different currency than the course price")
+	errCourseNotForSale   = errors.New("this course is free, enroll with a subscription instead")
+	errPaymentsNotEnabled = errors.New("payments are not enabled")
+)
+
+// isCouponRefused reports whether a coupon cannot be applied to an order
+func isCouponRefused(err error) bool {
+	return errors.Is(err, errCouponNotFound) ||
+		errors.Is(err, errCouponExpired) ||
+		errors.Is(err, errCouponUsedUp) ||
+		errors.Is(err, errCouponOtherCourse) ||
+		errors.Is(err, errCouponCurrency)
+}
+
+// orderQuote is what a student pays for a course, amounts are in the smallest unit of the currency
+type orderQuote struct {
+	CourseID       int64      `json:"course_id"`
+	Currency       string     `json:"currency"`
+	ListAmount     int64      `json:"list_amount"`
+	DiscountAmount int64      `json:"discount_amount"`
+	Amount         int64      `json:"amount"`
+	Coupon         *db.Coupon `json:"coupon,omitempty"`
+}
+
+// quoteCourse prices a course for a student with an optional coupon. Coupon uses are only counted once an order is paid,
+// so the limit is checked here and a coupon can end up a little over its limit when several checkouts race.
+func (server *Server) quoteCourse(ctx *gin.Context, courseID int64, couponCode string, now time.Time) (orderQuote, error) {
+	price, err := server.store.GetCoursePrice(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return orderQuote{}, errCourseNotForSale
+		}
+		return orderQuote{}, err
+	}
+
+	quote := orderQuote{
+		CourseID:   courseID,
+		Currency:   price.Currency,
+		ListAmount: price.Amount,
+		Amount:     price.Amount,
+	}
+
+	couponCode = payments.NormalizeCouponCode(couponCode)
+	if couponCode == "" {
+		return quote, nil
+	}
+
+	coupon, err := server.store.GetCouponByCode(ctx, couponCode)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return orderQuote{}, errCouponNotFound
+		}
+		return orderQuote{}, err
+	}
+
+	switch {
+	case coupon.CourseID.Valid && coupon.CourseID.Int64 != courseID:
+		return orderQuote{}, errCouponOtherCourse
+	case coupon.ExpiresAt.Valid && !now.Before(coupon.ExpiresAt.Time):
+		return orderQuote{}, errCouponExpired
+	case coupon.MaxUses > 0 && coupon.Uses >= coupon.MaxUses:
+		return orderQuote{}, errCouponUsedUp
+	case coupon.Kind == payments.CouponFixed && coupon.Currency != price.Currency:
+		return orderQuote{}, errCouponCurrency
+	}
+
+	quote.DiscountAmount = payments.Discount(price.Amount, coupon.Kind, coupon.Value)
+	quote.Amount = price.Amount - quote.DiscountAmount
+	quote.Coupon = &coupon
+	return quote, nil
+}
+
+// CreateCouponRequest defines the request body structure for creating a coupon
+type CreateCouponRequest struct {
+	Code      string `json:"code" binding:"required,alphanum,max=32"`
+	CourseID  int64  `json:"course_id" binding:"min=0"`
+	Kind      string `json:"kind" binding:"required,oneof=percent fixed"`
+	Value     int64  `json:"value" binding:"required,min=1"`
+	Currency  string `json:"currency"`
+	MaxUses   int64  `json:"max_uses" binding:"min=0"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// @Summary Create a coupon
+// @Description Create a discount code. A percent coupon takes value percent off, a fixed coupon takes value off in the
+// @Description smallest unit of its currency. A course_id of 0 makes the coupon valid for every course, max_uses of 0
+// @Description allows any number of paid orders and an empty expires_at never expires.
+// @ID create-coupon
+// @Accept json
+// @Produce json
+// @Param request body CreateCouponRequest true "Create Coupon Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /payment/coupon [post]
+func (server *Server) CreateCoupon(ctx *gin.Context) {
+	var req CreateCouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	var currency string
+	switch req.Kind {
+	case payments.CouponPercent:
+		if req.Value > 100 {
+			err := errors.New("a percent coupon cannot take more than 100 percent off")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	case payments.CouponFixed:
+		var err error
+		currency, err = payments.NormalizeCurrency(req.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	expiresAt, err := parseEnrollmentTime(req.ExpiresAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateCouponParams{
+		Code:      payments.NormalizeCouponCode(req.Code),
+		CourseID:  pgtype.Int8{Int64: req.CourseID, Valid: req.CourseID > 0},
+		Kind:      req.Kind,
+		Value:     req.Value,
+		Currency:  currency,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: expiresAt,
+		CreatedBy: authPayload.UserID,
+	}
+
+	coupon, err := server.store.CreateCoupon(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolations {
+			err := errors.New("a coupon with this code already exists")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupon)
+}
+
+// @Summary List coupons
+// @Description List every coupon with how often it was used on paid orders, newest first
+// @ID list-coupons
+// @Produce json
+// @Success 200
+// @Failure 403
+// @Failure 500
+// @Router /payment/coupons [get]
+func (server *Server) ListCoupons(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	coupons, err := server.store.ListCoupons(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, coupons)
+}
+
+// DeleteCouponRequest contains the input parameters for deleting a coupon
+type DeleteCouponRequest struct {
+	CouponID int64 `form:"coupon_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a coupon
+// @Description Delete a coupon, orders that used it keep their discount
+// @ID delete-coupon
+// @Produce json
+// @Param coupon_id query int true "Coupon ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /payment/coupon [delete]
+func (server *Server) DeleteCoupon(ctx *gin.Context) {
+	var req DeleteCouponRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteCoupon(ctx, req.CouponID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Coupon deleted successfully"})
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/enrollment"
+	"eduApp/token"
+	"eduApp/worker"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxInviteCodeAttempts is how often a new invite code is drawn when it collides with an existing one
+const maxInviteCodeAttempts = 3
+
+// CreateCourseInviteRequest defines the request body structure for creating a course invite code
+type CreateCourseInviteRequest struct {
+	CourseID  int64  `json:"course_id" binding:"required,min=1"`
+	MaxUses   int64  `json:"max_uses" binding:"min=0"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// @Summary Create an invite code
+// @Description Create a shareable code that enrolls the student who redeems it, also in invite only courses and outside
+// @Description the enrollment window. max_uses of 0 allows any number of students, an empty expires_at never expires.
+// @ID create-course-invite
+// @Accept json
+// @Produce json
+// @Param request body CreateCourseInviteRequest true "Create Course Invite Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /enrollment/invite [post]
+func (server *Server) CreateCourseInvite(ctx *gin.Context) {
+	var req CreateCourseInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	expiresAt, err := parseEnrollmentTime(req.ExpiresAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if expiresAt.Valid && !expiresAt.Time.After(time.Now()) {
+		err := errors.New("the invite would already be expired")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var invite db.CourseInvite
+	for attempt := 1; ; attempt++ {
+		code, err := enrollment.NewInviteCode()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		invite, err = server.store.CreateCourseInvite(ctx, db.CreateCourseInviteParams{
+			CourseID:  req.CourseID,
+			Code:      code,
+			MaxUses:   req.MaxUses,
+			ExpiresAt: expiresAt,
+			CreatedBy: authPayload.UserID,
+		})
+		if err == nil {
+			break
+		}
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) != db.UniqueViolations || attempt == maxInviteCodeAttempts {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, invite)
+}
+
+// ListCourseInvitesRequest contains the input parameters for listing the invite codes of a course
+type ListCourseInvitesRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List invite codes
+// @Description List the invite codes of a course with how often they were used, newest first
+// @ID list-course-invites
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /enrollment/invites [get]
+func (server *Server) ListCourseInvites(ctx *gin.Context) {
+	var req ListCourseInvitesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	invites, err := server.store.ListCourseInvites(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invites)
+}
+
+// RevokeCourseInviteRequest defines the request body structure for revoking an invite code
+type RevokeCourseInviteRequest struct {
+	InviteID int64 `json:"invite_id" binding:"required,min=1"`
+}
+
+// @Summary Revoke an invite code
+// @Description Stop an invite code from enrolling more students, students it already enrolled stay enrolled
+// @ID revoke-course-invite
+// @Accept json
+// @Produce json
+// @Param request body RevokeCourseInviteRequest true "Revoke Course Invite Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /enrollment/invite/revoke [put]
+func (server *Server) RevokeCourseInvite(ctx *gin.Context) {
+	var req RevokeCourseInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	invite, err := server.store.RevokeCourseInvite(ctx, req.InviteID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invite)
+}
+
+// RedeemCourseInviteRequest defines the request body structure for joining a course with an invite code
+type RedeemCourseInviteRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// @Summary Redeem an invite code
+// @Description Join the course of an invite code. The student is enrolled right away, or waitlisted when the course is full
+// @ID redeem-course-invite
+// @Accept json
+// @Produce json
+// @Param request body RedeemCourseInviteRequest true "Redeem Course Invite Request"
+// @Success 201
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /enrollment/invite/redeem [post]
+func (server *Server) RedeemCourseInvite(ctx *gin.Context) {
+	var req RedeemCourseInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.RedeemCourseInviteTx(ctx, db.RedeemCourseInviteTxParams{
+		Code:        enrollment.NormalizeInviteCode(req.Code),
+		UserID:      authPayload.UserID,
+		Now:         time.Now(),
+		AfterCreate: server.distributeCreateSubscription(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInviteNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInviteRevoked) || errors.Is(err, db.ErrInviteExpired) || errors.Is(err, db.ErrInviteUsedUp) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolations {
+			err := errors.New("you already have a subscription to this course")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.Subscription.WaitlistedAt.Valid {
+		server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventWaitlisted)
+	}
+
+	ctx.JSON(http.StatusCreated, result.Subscription)
+}
@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	db "eduApp/db/sqlc"
 	"eduApp/token"
+	"eduApp/worker"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
 )
 
 type createCourseProgressRequest struct {
@@ -171,3 +175,133 @@ func (server *Server) DeleteCourseProgress(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Course Progress deleted successfully"})
 }
+
+// courseCompletionHook is the AfterComplete hook of the progress transactions,
+// it enqueues the certificate and the credential of a student who just completed a course
+func (server *Server) courseCompletionHook(ctx *gin.Context) func(db.CourseProgress) error {
+	return func(courseProgress db.CourseProgress) error {
+		return worker.DistributeCourseCompletion(ctx, server.taskDistributor, server.xapiActivities(), courseProgress)
+	}
+}
+
+// recomputeCourseProgress refreshes course progress after assignments, quizzes or submissions changed.
+// The change itself is saved already, so a failure is only logged and the next recomputation catches up.
+func (server *Server) recomputeCourseProgress(ctx *gin.Context, courseID int64, userIDs ...int64) {
+	result, err := server.store.RecomputeCourseProgressTx(ctx, db.RecomputeCourseProgressTxParams{
+		CourseID:      courseID,
+		UserIDs:       userIDs,
+		AfterComplete: server.courseCompletionHook(ctx),
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("course_id", courseID).Ints64("user_ids", userIDs).Msg("failed to recompute course progress")
+		return
+	}
+	server.publishProgress(ctx, result.CourseProgress...)
+}
+
+// distributeCourseProgress queues the recomputation of every learner's progress after the course's lessons changed.
+// Courses can have many learners, so the task runs once the change is committed instead of inside its transaction.
+// It is queued from the transaction, a change whose recomputation could not be queued is rolled back.
+func (server *Server) distributeCourseProgress(ctx *gin.Context, courseID int64) error {
+	return server.taskDistributor.DistributeTaskUpdateCourseprogress(ctx, &worker.PayloadUpdateCourseprogress{
+		CourseID: courseID,
+	}, asynq.MaxRetry(10), asynq.ProcessIn(10*time.Second), asynq.Queue(worker.QueueDefault))
+}
+
+// SetCourseProgressWeightsRequest contains how much each kind of work counts towards course progress
+type SetCourseProgressWeightsRequest struct {
+	CourseID         int64 `json:"course_id" binding:"required,min=1"`
+	MaterialWeight   int64 `json:"material_weight" binding:"min=0,max=100"`
+	AssignmentWeight int64 `json:"assignment_weight" binding:"min=0,max=100"`
+	QuizWeight       int64 `json:"quiz_weight" binding:"min=0,max=100"`
+}
+
+// @Summary Set course progress weights
+// @Description Set how much lessons, assignments and quizzes count towards course progress and recompute the progress of every learner
+// @ID set-course-progress-weights
+// @Accept json
+// @Produce json
+// @Param request body SetCourseProgressWeightsRequest true "Progress weights"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /progress/weights [put]
+func (server *Server) SetCourseProgressWeights(ctx *gin.Context) {
+	var req SetCourseProgressWeightsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.MaterialWeight+req.AssignmentWeight+req.QuizWeight == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("at least one weight must be above zero")))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.SetCourseProgressWeightsTx(ctx, db.SetCourseProgressWeightsTxParams{
+		UpsertCourseProgressWeightsParams: db.UpsertCourseProgressWeightsParams{
+			CourseID:         req.CourseID,
+			MaterialWeight:   req.MaterialWeight,
+			AssignmentWeight: req.AssignmentWeight,
+			QuizWeight:       req.QuizWeight,
+		},
+		AfterComplete: server.courseCompletionHook(ctx),
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusNotFound, errorResponse(errors.New("course doesn't exist")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.publishProgress(ctx, result.CourseProgress...)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"weights":    result.Weights,
+		"recomputed": len(result.CourseProgress),
+	})
+}
+
+// GetCourseProgressWeightsRequest contains the course whose progress weights are requested
+type GetCourseProgressWeightsRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Get course progress weights
+// @Description Get how much lessons, assignments and quizzes count towards course progress, the defaults when none are set
+// @ID get-course-progress-weights
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /progress/weights [get]
+func (server *Server) GetCourseProgressWeights(ctx *gin.Context) {
+	var req GetCourseProgressWeightsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	weights, err := server.store.GetCourseProgressWeights(ctx, req.CourseID)
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		weights = db.DefaultCourseProgressWeights
+		weights.CourseID = req.CourseID
+	}
+
+	ctx.JSON(http.StatusOK, weights)
+}
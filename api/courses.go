@@ -9,13 +9,8 @@ import (
 	"eduApp/worker"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,67 +18,9 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// uploading a single file
-func (server *Server) uploadSingleImage(file multipart.File, header *multipart.FileHeader) (string, error) {
-
-	// Convert file extension to lowercase
-	fileExt := strings.ToLower(filepath.Ext(header.Filename))
-
-	// Validate file extension (optional)
-	allowedExtensions := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".jfif": true,
-	}
-	if !allowedExtensions[fileExt] {
-		return "", fmt.Errorf("unsupported file extension: %s", fileExt)
-	}
-
-	// Generate unique filename
-	originalFileName := strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
-	now := time.Now()
-	filename := strings.ReplaceAll(strings.ToLower(originalFileName), " ", "-") + "-" + fmt.Sprintf("%v", now.Unix()) + fileExt
-
-	// Create upload directory if it doesn't exist
-	uploadDir := "uploads/course"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return "", err
-	}
-
-	// Create destination file path
-	filePath := filepath.Join(uploadDir, filename)
-	// Save uploaded file
-	out, err := os.Create(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer out.Close()
-	_, err = io.Copy(out, file)
-	if err != nil {
-		return "", err
-	}
-
-	// Split the URL into parts
-	parts := strings.Split(filePath, "/")
-	println(parts)
-
-	// Replace "uploads" with "static"
-	if len(parts) > 2 && parts[0] == "uploads" {
-		parts[0] = "static"
-	}
-	println(parts)
-
-	//newUrl for load images
-	newUrl := strings.Join(parts, "/")
-	println("LINK", newUrl)
-
-	serverAddress := server.config.FileSource
-	print("server address", serverAddress)
-	Image := fmt.Sprintf("%s/%s", serverAddress, newUrl)
-	println(Image)
-	return Image, nil
-
+// uploadSingleImage stores the course cover image variants
+func (server *Server) uploadSingleImage(file multipart.File, header *multipart.FileHeader) (typetext.ImageVariants, error) {
+	return server.saveImageVariants(file, header, "uploads/course")
 }
 
 // CreateCourseRequest defines the request body structure for creating a course
@@ -132,14 +69,15 @@ func (server *Server) CreateCourse(ctx *gin.Context) {
 
 	// Handle image upload (if included in the request)
 	var imageFile string
+	var imageVariants typetext.ImageVariants
 	file, header, err := ctx.Request.FormFile("image")
 	if err == nil {
-		imageFile, err = server.uploadSingleImage(file, header)
-		println("image_files", imageFile)
+		imageVariants, err = server.uploadSingleImage(file, header)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
+		imageFile = imageVariants.Full.JPEG
 	}
 
 	// Update request struct with image filename (if uploaded)
@@ -153,6 +91,7 @@ func (server *Server) CreateCourse(ctx *gin.Context) {
 		Catagory:         req.Catagory,
 		SequentialAccess: true,
 		WhatWill:         req.WhatWill,
+		ImageVariants:    imageVariants,
 	}
 
 	course, err := server.store.CreateCourses(ctx, db.CreateCoursesParams(arg))
@@ -298,40 +237,41 @@ func (server *Server) UpdateCourse(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
+	//get course for remove exiting image
+	getCourse, err := server.store.GetCourses(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	// Handle image upload (if included in the request)
-	var updatedImage string
+	var imageVariants *typetext.ImageVariants
 	file, header, err := ctx.Request.FormFile("image")
 	if err == nil {
-		updatedImage, err = server.uploadSingleImage(file, header)
+		variants, err := server.uploadSingleImage(file, header)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
-	}
+		imageVariants = &variants
 
-	// Update request struct with image filename (if uploaded)
-	req.Image = updatedImage
+		// Update request struct with image filename (if uploaded)
+		req.Image = variants.Full.JPEG
 
-	//get course for remove exiting image
-	getCourse, err := server.store.GetCourses(ctx, req.CourseID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		//remove exiting files
+		util.DeleteFileByURL(getCourse.Image)
+		deleteImageVariants(getCourse.ImageVariants)
 	}
-	//assign value to the image path
-	value := getCourse.Image
-
-	//remove exiting file
-	util.DeleteFileByURL(value)
 
 	arg := db.UpdateCoursesParams{
 		Title:            pgtype.Text{String: req.Title, Valid: true},
 		CourseID:         req.CourseID,
 		Description:      pgtype.Text{String: req.Description, Valid: true},
-		Image:            pgtype.Text{String: req.Image, Valid: true},
+		Image:            pgtype.Text{String: req.Image, Valid: req.Image != ""},
 		Catagory:         pgtype.Text{String: req.Catagory, Valid: true},
 		SequentialAccess: pgtype.Bool{Bool: true, Valid: true},
 		WhatWill:         req.WhatWill,
+		ImageVariants:    imageVariants,
 	}
 
 	course, err := server.store.UpdateCourses(ctx, db.UpdateCoursesParams(arg))
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"eduApp/mail"
+	"eduApp/token"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List email templates
+// @Description List the names of the transactional email templates and the locales they are available in
+// @ID list-email-templates
+// @Produce json
+// @Success 200
+// @Failure 403
+// @Router /emails/templates [get]
+func (server *Server) ListEmailTemplates(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"templates":      mail.Templates,
+		"locales":        mail.Locales(),
+		"default_locale": mail.DefaultLocale,
+	})
+}
+
+// PreviewEmailRequest contains the input parameters for previewing an email template
+type PreviewEmailRequest struct {
+	Template string `form:"template" binding:"required"`
+	Locale   string `form:"locale"`
+	// Format is json for the subject with both variants, html or text to see one variant as it is sent
+	Format string `form:"format" binding:"omitempty,oneof=json html text"`
+}
+
+// @Summary Preview an email template
+// @Description Render an email template with made-up data. A locale lacking the template falls back to the default locale.
+// @ID preview-email
+// @Produce json,html,plain
+// @Param template query string true "Template name"
+// @Param locale query string false "Locale, the default locale when empty"
+// @Param format query string false "json, html or text"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /emails/preview [get]
+func (server *Server) PreviewEmail(ctx *gin.Context) {
+	var req PreviewEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	data := mail.PreviewData(req.Template)
+	if data == nil {
+		err := fmt.Errorf("%w, template must be one of %s", mail.ErrUnknownTemplate, strings.Join(mail.Templates, ", "))
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	locale := mail.ResolveLocale(req.Locale)
+	message, err := mail.Render(req.Template, locale, data)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	switch req.Format {
+	case "html":
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(message.HTML))
+	case "text":
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(message.Subject+"\n\n"+message.Text))
+	default:
+		ctx.JSON(http.StatusOK, gin.H{
+			"template": req.Template,
+			"locale":   locale,
+			"subject":  message.Subject,
+			"html":     message.HTML,
+			"text":     message.Text,
+		})
+	}
+}
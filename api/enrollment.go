@@ -0,0 +1,383 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"eduApp/util"
+	"eduApp/worker"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// isEnrollmentRefused reports whether the enrollment settings of a course turned a request away
+func isEnrollmentRefused(err error) bool {
+	return errors.Is(err, db.ErrEnrollmentClosed) ||
+		errors.Is(err, db.ErrEnrollmentInviteOnly) ||
+		errors.Is(err, db.ErrEnrollmentNotStarted) ||
+		errors.Is(err, db.ErrEnrollmentEnded)
+}
+
+// distributeEnrollmentEmail queues an email about an enrollment decision, the decision stands even if the email cannot be queued
+func (server *Server) distributeEnrollmentEmail(ctx *gin.Context, subscription db.Subscription, event string) {
+	err := server.taskDistributor.DistributeTaskSendEnrollmentEmail(ctx, &worker.PayloadSendEnrollmentEmail{
+		UserID:   subscription.UserID,
+		CourseID: subscription.CourseID,
+		Event:    event,
+	}, asynq.MaxRetry(5), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Error().Err(err).Int64("subscription_id", subscription.SubscriptionID).
+			Str("event", event).Msg("failed to enqueue enrollment email")
+	}
+}
+
+// promoteWaitlist fills the free seats of a course from its waitlist and emails the promoted students.
+// A failure is only logged, the next seat that frees up promotes the waitlist again.
+func (server *Server) promoteWaitlist(ctx *gin.Context, courseID int64) []db.Subscription {
+	result, err := server.store.PromoteWaitlistTx(ctx, courseID)
+	if err != nil {
+		log.Error().Err(err).Int64("course_id", courseID).Msg("failed to promote waitlist")
+		return []db.Subscription{}
+	}
+
+	for _, subscription := range result.Promoted {
+		server.distributeEnrollmentEmail(ctx, subscription, worker.EnrollmentEventPromoted)
+		server.distributeCourseLaunched(ctx, subscription)
+	}
+	return result.Promoted
+}
+
+// SetEnrollmentSettingsRequest defines the request body structure for the enrollment settings of a course
+type SetEnrollmentSettingsRequest struct {
+	CourseID           int64  `json:"course_id" binding:"required,min=1"`
+	Mode               string `json:"mode" binding:"required,oneof=open approval invite closed"`
+	Capacity           int64  `json:"capacity" binding:"min=0"`
+	EnrollmentStartsAt string `json:"enrollment_starts_at"`
+	EnrollmentEndsAt   string `json:"enrollment_ends_at"`
+	// AccessDays is how long a student keeps access once enrolled, zero means forever
+	AccessDays     int64  `json:"access_days" binding:"min=0,max=36500"`
+	AccessStartsAt string `json:"access_starts_at"`
+}
+
+// enrollmentSettingsResponse is the enrollment settings of a course with the students promoted by a change
+type enrollmentSettingsResponse struct {
+	Settings db.CourseEnrollmentSetting `json:"settings"`
+	Promoted []db.Subscription          `json:"promoted"`
+}
+
+// parseEnrollmentTime parses an optional enrollment window bound, an empty value leaves that side of the window open
+func parseEnrollmentTime(value string) (pgtype.Timestamptz, error) {
+	if strings.TrimSpace(value) == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	t, err := util.ParseDueDate(value, time.Local)
+	if err != nil {
+		return pgtype.Timestamptz{}, err
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// @Summary Set enrollment settings
+// @Description Set how students join a course: open, approval (the default), invite or closed. A capacity of 0 means no limit,
+// @Description once the course is full new students join a first come first served waitlist. Students can only ask to join
+// @Description between enrollment_starts_at and enrollment_ends_at when they are given. Raising the capacity promotes the waitlist.
+// @Description Students enrolled from now on get access for access_days days (0 keeps access forever), starting no earlier
+// @Description than access_starts_at when it is given.
+// @ID set-enrollment-settings
+// @Accept json
+// @Produce json
+// @Param request body SetEnrollmentSettingsRequest true "Set Enrollment Settings Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /enrollment/settings [put]
+func (server *Server) SetEnrollmentSettings(ctx *gin.Context) {
+	var req SetEnrollmentSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	startsAt, err := parseEnrollmentTime(req.EnrollmentStartsAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	endsAt, err := parseEnrollmentTime(req.EnrollmentEndsAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if startsAt.Valid && endsAt.Valid && !startsAt.Time.Before(endsAt.Time) {
+		err := errors.New("enrollment must start before it ends")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	accessStartsAt, err := parseEnrollmentTime(req.AccessStartsAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	settings, err := server.store.UpsertCourseEnrollmentSettings(ctx, db.UpsertCourseEnrollmentSettingsParams{
+		CourseID:           req.CourseID,
+		Mode:               req.Mode,
+		Capacity:           req.Capacity,
+		EnrollmentStartsAt: startsAt,
+		EnrollmentEndsAt:   endsAt,
+		AccessDays:         req.AccessDays,
+		AccessStartsAt:     accessStartsAt,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollmentSettingsResponse{
+		Settings: settings,
+		Promoted: server.promoteWaitlist(ctx, req.CourseID),
+	})
+}
+
+// GetEnrollmentSettingsRequest contains the input parameters for getting the enrollment settings of a course
+type GetEnrollmentSettingsRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Get enrollment settings
+// @Description Get how students join a course, a course that was never set up needs approval and has no capacity limit
+// @ID get-enrollment-settings
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /enrollment/settings [get]
+func (server *Server) GetEnrollmentSettings(ctx *gin.Context) {
+	var req GetEnrollmentSettingsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	settings, err := server.store.GetCourseEnrollmentSettings(ctx, req.CourseID)
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		settings = db.CourseEnrollmentSetting{
+			CourseID: req.CourseID,
+			Mode:     db.EnrollmentModeApproval,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// ListEnrollmentQueueRequest contains the input parameters for listing the students waiting to join a course
+type ListEnrollmentQueueRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List pending enrollments
+// @Description List the students waiting for approval to join a course, oldest request first
+// @ID list-pending-enrollments
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /enrollment/pending [get]
+func (server *Server) ListPendingEnrollments(ctx *gin.Context) {
+	var req ListEnrollmentQueueRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	pending, err := server.store.ListPendingSubscriptions(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pending)
+}
+
+// @Summary List the waitlist
+// @Description List the waitlist of a course in the order students will be promoted
+// @ID list-enrollment-waitlist
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /enrollment/waitlist [get]
+func (server *Server) ListEnrollmentWaitlist(ctx *gin.Context) {
+	var req ListEnrollmentQueueRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	waitlist, err := server.store.ListWaitlistedSubscriptions(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, waitlist)
+}
+
+// DecideEnrollmentRequest defines the request body structure for approving or denying an enrollment request
+type DecideEnrollmentRequest struct {
+	UserID   int64  `json:"user_id" binding:"required,min=1"`
+	CourseID int64  `json:"course_id" binding:"required,min=1"`
+	Decision string `json:"decision" binding:"required,oneof=approve deny"`
+}
+
+// @Summary Approve or deny an enrollment
+// @Description Approve or deny a pending enrollment request, the student is emailed either way. An approved student joins the
+// @Description waitlist when the course is full. Waitlisted students can also be denied.
+// @ID decide-enrollment
+// @Accept json
+// @Produce json
+// @Param request body DecideEnrollmentRequest true "Decide Enrollment Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /enrollment/decision [put]
+func (server *Server) DecideEnrollment(ctx *gin.Context) {
+	var req DecideEnrollmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.DecideSubscriptionTx(ctx, db.DecideSubscriptionTxParams{
+		UserID:   req.UserID,
+		CourseID: req.CourseID,
+		Approve:  req.Decision == "approve",
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrSubscriptionNotPending) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	switch {
+	case result.Subscription.Active:
+		server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventApproved)
+		server.distributeCourseLaunched(ctx, result.Subscription)
+	case result.Subscription.WaitlistedAt.Valid:
+		server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventWaitlisted)
+	default:
+		server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventDenied)
+	}
+
+	ctx.JSON(http.StatusOK, result.Subscription)
+}
+
+// UnenrollRequest defines the request body structure for leaving a course
+type UnenrollRequest struct {
+	UserID   int64 `json:"user_id" binding:"required,min=1"`
+	CourseID int64 `json:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Leave a course
+// @Description Remove a subscription, students can leave a course themselves and admins can remove anyone.
+// @Description The seat that frees up goes to the first student on the waitlist.
+// @ID unenroll
+// @Accept json
+// @Produce json
+// @Param request body UnenrollRequest true "Unenroll Request"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /enrollment/unenroll [delete]
+func (server *Server) Unenroll(ctx *gin.Context) {
+	var req UnenrollRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.UserID != authPayload.UserID && authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	subscription, err := server.store.DeleteCourseSubscription(ctx, db.DeleteCourseSubscriptionParams{
+		UserID:   req.UserID,
+		CourseID: req.CourseID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if subscription.Active {
+		server.promoteWaitlist(ctx, req.CourseID)
+	}
+
+	ctx.JSON(http.StatusOK, subscription)
+}
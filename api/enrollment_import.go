@@ -0,0 +1,230 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/enrollment"
+	"eduApp/token"
+	"eduApp/worker"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// enrollmentImportResponse is the progress of a bulk enrollment with the result of every finished row
+type enrollmentImportResponse struct {
+	ImportID      int64                    `json:"import_id"`
+	CourseID      int64                    `json:"course_id"`
+	Status        string                   `json:"status"`
+	TotalRows     int64                    `json:"total_rows"`
+	ProcessedRows int64                    `json:"processed_rows"`
+	Error         string                   `json:"error"`
+	CreatedAt     time.Time                `json:"created_at"`
+	StartedAt     pgtype.Timestamptz       `json:"started_at"`
+	FinishedAt    pgtype.Timestamptz       `json:"finished_at"`
+	Summary       map[string]int           `json:"summary"`
+	Results       enrollment.ImportResults `json:"results"`
+}
+
+func newEnrollmentImportResponse(job db.EnrollmentImport) enrollmentImportResponse {
+	results := make(enrollment.ImportResults, len(job.Results))
+	copy(results, job.Results)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Row < results[j].Row
+	})
+
+	summary := make(map[string]int)
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	return enrollmentImportResponse{
+		ImportID:      job.ImportID,
+		CourseID:      job.CourseID,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		Error:         job.Error,
+		CreatedAt:     job.CreatedAt,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+		Summary:       summary,
+		Results:       results,
+	}
+}
+
+// CreateEnrollmentImportRequest contains the form fields of a bulk enrollment upload
+type CreateEnrollmentImportRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Bulk enroll students
+// @Description Upload a CSV roster with an email column and optional first_name, last_name and user_name columns.
+// @Description Students with an account are enrolled, unknown emails get a new account and an invite email. The roster
+// @Description is enrolled in the background, poll GET /enrollment/import for progress and the result of every row.
+// @Description Rows that cannot be read are reported as failed right away, the rest of the file is still enrolled.
+// @Accept multipart/form-data
+// @Produce json
+// @Param course_id formData int true "Course ID"
+// @Param file formData file true "CSV file"
+// @Success 202
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /enrollment/import [post]
+func (server *Server) CreateEnrollmentImport(ctx *gin.Context) {
+	var req CreateEnrollmentImportRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	_, err := server.store.GetCourses(ctx, req.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "a CSV file is required"})
+		return
+	}
+	defer file.Close()
+
+	rows, failed, err := enrollment.ParseRosterCSV(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(rows) == 0 && len(failed) == 0 {
+		err := errors.New("the file has no students")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	job, err := server.store.CreateEnrollmentImport(ctx, db.CreateEnrollmentImportParams{
+		CourseID:  req.CourseID,
+		CreatedBy: authPayload.UserID,
+		TotalRows: int64(len(rows)),
+		Rows:      rows,
+		Results:   failed,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.taskDistributor.DistributeTaskProcessEnrollmentImport(ctx, &worker.PayloadProcessEnrollmentImport{
+		ImportID: job.ImportID,
+	}, asynq.MaxRetry(5), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		// nothing will pick the job up, so it is not left queued forever
+		_ = server.store.FinishEnrollmentImport(ctx, db.FinishEnrollmentImportParams{
+			ImportID: job.ImportID,
+			Status:   enrollment.ImportFailed,
+			Error:    err.Error(),
+		})
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, newEnrollmentImportResponse(job))
+}
+
+// GetEnrollmentImportRequest contains the input parameters for polling a bulk enrollment
+type GetEnrollmentImportRequest struct {
+	ImportID int64 `form:"import_id" binding:"required,min=1"`
+}
+
+// @Summary Get bulk enrollment progress
+// @Description Get the progress of a bulk enrollment with the result of every finished row, in row order
+// @ID get-enrollment-import
+// @Produce json
+// @Param import_id query int true "Import ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /enrollment/import [get]
+func (server *Server) GetEnrollmentImport(ctx *gin.Context) {
+	var req GetEnrollmentImportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	job, err := server.store.GetEnrollmentImport(ctx, req.ImportID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newEnrollmentImportResponse(job))
+}
+
+// ListEnrollmentImportsRequest contains the input parameters for listing the bulk enrollments of a course
+type ListEnrollmentImportsRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List bulk enrollments
+// @Description List the bulk enrollments of a course, newest first, without their row results
+// @ID list-enrollment-imports
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /enrollment/imports [get]
+func (server *Server) ListEnrollmentImports(ctx *gin.Context) {
+	var req ListEnrollmentImportsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	jobs, err := server.store.ListEnrollmentImports(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, jobs)
+}
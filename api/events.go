@@ -0,0 +1,114 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/realtime"
+	"eduApp/token"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// eventHeartbeatInterval keeps proxies from closing an idle event stream
+const eventHeartbeatInterval = 25 * time.Second
+
+// publishEvent pushes an event to the connections of a user, what caused it stands even if it cannot be published
+func (server *Server) publishEvent(ctx *gin.Context, userID int64, eventType string, data any) {
+	err := server.events.Publish(ctx, userID, eventType, data)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("event_type", eventType).Msg("failed to publish event")
+	}
+}
+
+// publishProgress tells students their course progress was recomputed
+func (server *Server) publishProgress(ctx *gin.Context, progress ...db.CourseProgress) {
+	for _, courseProgress := range progress {
+		if courseProgress.UserID == 0 {
+			continue
+		}
+		server.publishEvent(ctx, courseProgress.UserID, realtime.EventProgressChanged, realtime.ProgressChanged{
+			CourseID:  courseProgress.CourseID,
+			Progress:  courseProgress.Progress,
+			Completed: courseProgress.CompletedAt.Valid,
+		})
+	}
+}
+
+// streamTicketResponse is a ticket that opens one event stream until it expires
+type streamTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// @Summary Create stream ticket
+// @Description Issues a ticket that opens one event stream as GET /events?ticket=..., so the access token stays out of the URL.
+// @Description It expires after 30 seconds and the stream it opens ends when the access token it was issued for expires.
+// @ID create-stream-ticket
+// @Produce json
+// @Success 200 {object} streamTicketResponse
+// @Failure 401
+// @Failure 500
+// @Router /events/ticket [post]
+func (server *Server) CreateStreamTicket(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	ticket, err := server.events.IssueTicket(ctx, authPayload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, streamTicketResponse{Ticket: ticket, ExpiresAt: time.Now().Add(realtime.TicketDuration)})
+}
+
+// @Summary Stream events
+// @Description Server-Sent Events stream of what changes for the signed in user: progress_changed, submission_graded,
+// @Description notification_created and task_finished, with a ping every 25 seconds. Every event carries its type, data and time.
+// @Description An EventSource cannot set headers, so it passes a ticket from POST /events/ticket as the ticket query parameter.
+// @Description The stream ends with a token_expired event when the access token expires, reconnect with a renewed token.
+// @ID stream-events
+// @Produce text/event-stream
+// @Param ticket query string false "Stream ticket, when the authorization header is not set"
+// @Success 200
+// @Failure 401
+// @Failure 500
+// @Router /events [get]
+func (server *Server) StreamEvents(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	subscription, err := server.events.Subscribe(ctx.Request.Context(), authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer subscription.Close()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+	expiry := time.NewTimer(time.Until(authPayload.ExpiredAt))
+	defer expiry.Stop()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("ready", gin.H{"user_id": authPayload.UserID})
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event)
+			return true
+		case now := <-heartbeat.C:
+			ctx.SSEvent("ping", gin.H{"at": now})
+			return true
+		case <-expiry.C:
+			ctx.SSEvent("token_expired", gin.H{"error": token.ErrExpiredToken.Error()})
+			return false
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"eduApp/realtime"
+	"eduApp/token"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeBroker keeps tickets in memory and hands out one subscription whose events the test sends
+type fakeBroker struct {
+	realtime.Broker
+
+	mu           sync.Mutex
+	tickets      map[string]*token.Payload
+	err          error
+	subscription *fakeSubscription
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{
+		tickets: map[string]*token.Payload{},
+		subscription: &fakeSubscription{
+			events: make(chan realtime.Event),
+			closed: make(chan struct{}),
+		},
+	}
+}
+
+func (broker *fakeBroker) IssueTicket(ctx context.Context, payload *token.Payload) (string, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if broker.err != nil {
+		return "", broker.err
+	}
+	ticket := "ticket-" + payload.UserName
+	broker.tickets[ticket] = payload
+	return ticket, nil
+}
+
+func (broker *fakeBroker) RedeemTicket(ctx context.Context, ticket string) (*token.Payload, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if broker.err != nil {
+		return nil, broker.err
+	}
+	payload, ok := broker.tickets[ticket]
+	if !ok {
+		return nil, realtime.ErrInvalidTicket
+	}
+	delete(broker.tickets, ticket)
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (broker *fakeBroker) Subscribe(ctx context.Context, userID int64) (realtime.Subscription, error) {
+	if broker.err != nil {
+		return nil, broker.err
+	}
+	return broker.subscription, nil
+}
+
+type fakeSubscription struct {
+	events    chan realtime.Event
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (subscription *fakeSubscription) Events() <-chan realtime.Event {
+	return subscription.events
+}
+
+func (subscription *fakeSubscription) Close() error {
+	subscription.closeOnce.Do(func() { close(subscription.closed) })
+	return nil
+}
+
+// waitClosed fails the test unless the stream closed its subscription
+func (subscription *fakeSubscription) waitClosed(t *testing.T) {
+	t.Helper()
+	select {
+	case <-subscription.closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not closed when the stream ended")
+	}
+}
+
+func TestEventStreamAuthMiddleware(t *testing.T) {
+	tokenMaker, err := token.NewJWTMaker("12345678901234567890123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, _, err := tokenMaker.CreateToken("ana", "student", 7, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		// setup prepares the broker and the request
+		setup  func(broker *fakeBroker, request *http.Request)
+		status int
+	}{
+		{
+			name: "Header",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+accessToken)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "Ticket",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				broker.tickets["t1"] = &token.Payload{UserName: "ana", UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+				request.URL.RawQuery = "ticket=t1"
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "TicketUsed",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				broker.tickets["t1"] = &token.Payload{UserName: "ana", UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+				broker.RedeemTicket(context.Background(), "t1")
+				request.URL.RawQuery = "ticket=t1"
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "TicketUnknown",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				request.URL.RawQuery = "ticket=t2"
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "TicketOfExpiredToken",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				broker.tickets["t1"] = &token.Payload{UserName: "ana", UserID: 7, ExpiredAt: time.Now().Add(-time.Second)}
+				request.URL.RawQuery = "ticket=t1"
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "TicketStoreDown",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				broker.err = errors.New("redis is down")
+				request.URL.RawQuery = "ticket=t1"
+			},
+			status: http.StatusInternalServerError,
+		},
+		{
+			name: "AccessTokenInQuery",
+			setup: func(broker *fakeBroker, request *http.Request) {
+				request.URL.RawQuery = "access_token=" + accessToken
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "NoCredentials",
+			setup:  func(broker *fakeBroker, request *http.Request) {},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			broker := newFakeBroker()
+			router := gin.New()
+			router.GET("/events", eventStreamAuthMiddleware(tokenMaker, broker), func(ctx *gin.Context) {
+				payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+				if payload.UserID != 7 {
+					t.Errorf("user = %d, want 7", payload.UserID)
+				}
+				ctx.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/events", nil)
+			tc.setup(broker, request)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != tc.status {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tc.status, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestCreateStreamTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	broker := newFakeBroker()
+	server := &Server{events: broker}
+	payload := &token.Payload{UserName: "ana", UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+	router := gin.New()
+	router.POST("/events/ticket", func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, payload)
+	}, server.CreateStreamTicket)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/events/ticket", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+
+	var rsp streamTicketResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &rsp); err != nil {
+		t.Fatal(err)
+	}
+	if until := time.Until(rsp.ExpiresAt); until <= 0 || until > realtime.TicketDuration {
+		t.Errorf("ticket expires in %v, want within %v", until, realtime.TicketDuration)
+	}
+	redeemed, err := broker.RedeemTicket(context.Background(), rsp.Ticket)
+	if err != nil || redeemed.UserID != payload.UserID {
+		t.Errorf("RedeemTicket = %+v, %v, want the payload of user 7", redeemed, err)
+	}
+}
+
+// streamEvents opens the event stream of payload on a test server and returns the names of the SSE events read
+// until the stream ends or stop returns true
+func streamEvents(t *testing.T, ctx context.Context, server *Server, payload *token.Payload, stop func(event string) bool) []string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, payload)
+	}, server.StreamEvents)
+	httpServer := httptest.NewServer(router)
+	t.Cleanup(httpServer.Close)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return []string{rsp.Status}
+	}
+
+	var events []string
+	scanner := bufio.NewScanner(rsp.Body)
+	for scanner.Scan() {
+		event, ok := strings.CutPrefix(scanner.Text(), "event:")
+		if !ok {
+			continue
+		}
+		events = append(events, event)
+		if stop(event) {
+			break
+		}
+	}
+	return events
+}
+
+func TestStreamEvents(t *testing.T) {
+	never := func(string) bool { return false }
+
+	t.Run("TokenExpires", func(t *testing.T) {
+		broker := newFakeBroker()
+		payload := &token.Payload{UserID: 7, ExpiredAt: time.Now().Add(200 * time.Millisecond)}
+
+		events := streamEvents(t, context.Background(), &Server{events: broker}, payload, never)
+		if strings.Join(events, ",") != "ready,token_expired" {
+			t.Errorf("events = %v, want ready then token_expired", events)
+		}
+		broker.subscription.waitClosed(t)
+	})
+
+	t.Run("Delivered", func(t *testing.T) {
+		broker := newFakeBroker()
+		payload := &token.Payload{UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+		event, err := realtime.NewEvent(realtime.EventProgressChanged, realtime.ProgressChanged{CourseID: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the broker ends the subscription after one event, which ends the stream
+		go func() {
+			broker.subscription.events <- event
+			close(broker.subscription.events)
+		}()
+		events := streamEvents(t, context.Background(), &Server{events: broker}, payload, never)
+		if strings.Join(events, ",") != "ready,"+realtime.EventProgressChanged {
+			t.Errorf("events = %v, want ready then %s", events, realtime.EventProgressChanged)
+		}
+		broker.subscription.waitClosed(t)
+	})
+
+	t.Run("ClientDisconnects", func(t *testing.T) {
+		broker := newFakeBroker()
+		payload := &token.Payload{UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		streamEvents(t, ctx, &Server{events: broker}, payload, func(event string) bool {
+			cancel()
+			return true
+		})
+		broker.subscription.waitClosed(t)
+	})
+
+	t.Run("SubscribeFails", func(t *testing.T) {
+		broker := newFakeBroker()
+		broker.err = errors.New("redis is down")
+		payload := &token.Payload{UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+
+		events := streamEvents(t, context.Background(), &Server{events: broker}, payload, never)
+		if len(events) != 1 || !strings.HasPrefix(events[0], "500") {
+			t.Errorf("events = %v, want a 500 response", events)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"eduApp/token"
+	"eduApp/worker"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+)
+
+// @Summary List orphan files
+// @Description Dry-run of the orphan file collector, lists uploaded files no row refers to
+// @Produce json
+// @Success 200
+// @Failure 403
+// @Failure 500
+// @Router /admin/files/orphans [get]
+// ListOrphanFiles reports the files the garbage collector would delete
+func (server *Server) ListOrphanFiles(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an admin of this system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	gracePeriod := server.config.FileGCGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = worker.DefaultFileGCGracePeriod
+	}
+
+	report, err := worker.CollectOrphanFiles(ctx, server.store, gracePeriod, true)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// CollectOrphanFilesRequest defines the request body structure for running the file collector
+type CollectOrphanFilesRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// @Summary Collect orphan files
+// @Description Enqueues the orphan file collector
+// @Accept json
+// @Produce json
+// @Param request body CollectOrphanFilesRequest true "Collect Orphan Files Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /admin/files/gc [post]
+// CollectOrphanFiles enqueues a garbage collection run of the upload directory
+func (server *Server) CollectOrphanFiles(ctx *gin.Context) {
+	var req CollectOrphanFilesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an admin of this system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(3),
+		asynq.Queue(worker.QueueDefault),
+	}
+
+	err := server.taskDistributor.DistributeTaskCollectOrphanFiles(ctx, &worker.PayloadCollectOrphanFiles{DryRun: req.DryRun}, opts...)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Orphan file collection task enqueued successfully"})
+}
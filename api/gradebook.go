@@ -0,0 +1,846 @@
+package api
+
+import (
+	"bytes"
+	db "eduApp/db/sqlc"
+	"eduApp/gradebook"
+	"eduApp/token"
+	"eduApp/util"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// defaultGradeItemMaxPoints is used for items created without max points and assignments without a rubric
+const defaultGradeItemMaxPoints = 100
+
+// gradebookView is a course gradebook with the computed grade of each student
+type gradebookView struct {
+	CourseID   int64                `json:"course_id"`
+	Categories []db.GradeCategory   `json:"categories"`
+	Items      []db.GradeItem       `json:"items"`
+	Scale      gradebook.Scale      `json:"scale"`
+	Students   []gradebook.Student  `json:"students"`
+	categories []gradebook.Category `json:"-"`
+	items      []gradebook.Item     `json:"-"`
+}
+
+// buildGradebook loads the grade items and scores of a course and computes every student's grade.
+// A non zero userID limits the gradebook to that student and leaves out assignment grades that are not
+// published yet.
+func (server *Server) buildGradebook(ctx *gin.Context, courseID, userID int64) (gradebookView, error) {
+	view := gradebookView{CourseID: courseID}
+
+	var err error
+	view.Categories, err = server.store.ListGradeCategories(ctx, courseID)
+	if err != nil {
+		return view, err
+	}
+	view.Items, err = server.store.ListGradeItems(ctx, courseID)
+	if err != nil {
+		return view, err
+	}
+
+	view.Scale = gradebook.DefaultScale
+	scale, err := server.store.GetGradeScale(ctx, courseID)
+	if err == nil && len(scale.Letters) > 0 {
+		view.Scale = scale.Letters
+	} else if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return view, err
+	}
+	view.Scale = view.Scale.Sorted()
+
+	for _, category := range view.Categories {
+		view.categories = append(view.categories, gradebook.Category{
+			ID:         category.CategoryID,
+			Title:      category.Title,
+			Weight:     category.Weight,
+			DropLowest: int(category.DropLowest),
+		})
+	}
+	for _, item := range view.Items {
+		view.items = append(view.items, gradebook.Item{
+			ID:         item.ItemID,
+			CategoryID: item.CategoryID,
+			Title:      item.Title,
+			MaxPoints:  item.MaxPoints,
+		})
+	}
+
+	rows, err := server.store.ListGradebookScores(ctx, courseID)
+	if err != nil {
+		return view, err
+	}
+	scores := make(map[int64]gradebook.Scores)
+	for _, row := range rows {
+		if userID != 0 && (row.UserID != userID || !row.Published) {
+			continue
+		}
+		if scores[row.UserID] == nil {
+			scores[row.UserID] = gradebook.Scores{}
+		}
+		scores[row.UserID][row.ItemID] = row.Points
+	}
+
+	students, err := server.store.ListGradebookStudents(ctx, courseID)
+	if err != nil {
+		return view, err
+	}
+	view.Students = []gradebook.Student{}
+	for _, student := range students {
+		if userID != 0 && student.UserID != userID {
+			continue
+		}
+		studentScores := scores[student.UserID]
+		if studentScores == nil {
+			studentScores = gradebook.Scores{}
+		}
+		view.Students = append(view.Students, gradebook.Student{
+			UserID:    student.UserID,
+			UserName:  student.UserName,
+			FirstName: student.FirstName,
+			LastName:  student.LastName,
+			Scores:    studentScores,
+			Result:    gradebook.Compute(view.categories, view.items, studentScores, view.Scale),
+		})
+	}
+
+	return view, nil
+}
+
+// CourseGradebookRequest identifies the course of a gradebook in the URL
+type CourseGradebookRequest struct {
+	CourseID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Get the course gradebook
+// @Description Get the grade items, scores and computed final grade of every student. Students only get their own row with published grades
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /course/{id}/gradebook [get]
+func (server *Server) GetGradebook(ctx *gin.Context) {
+	var req CourseGradebookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetCourses(ctx, req.CourseID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	var userID int64
+	if authPayload.Role != "admin" {
+		userID = authPayload.UserID
+	}
+
+	view, err := server.buildGradebook(ctx, req.CourseID, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if userID != 0 && len(view.Students) == 0 {
+		err := errors.New("you are not enrolled in this course")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// ExportGradebookRequest contains the input parameters for exporting a gradebook
+type ExportGradebookRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+// @Summary Export the course gradebook
+// @Description Download the gradebook of a course as a CSV or XLSX file
+// @Produce text/csv
+// @Param id path int true "Course ID"
+// @Param format query string false "csv (default) or xlsx"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /course/{id}/gradebook/export [get]
+func (server *Server) ExportGradebook(ctx *gin.Context) {
+	var uri CourseGradebookRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req ExportGradebookRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	view, err := server.buildGradebook(ctx, uri.CourseID, 0)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows := gradebook.Sheet(view.categories, view.items, view.Students)
+	filename := fmt.Sprintf("gradebook-course-%d", uri.CourseID)
+
+	var buf bytes.Buffer
+	contentType := "text/csv"
+	if req.Format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		filename += ".xlsx"
+		err = gradebook.WriteXLSX(&buf, "Gradebook", rows)
+	} else {
+		filename += ".csv"
+		writer := csv.NewWriter(&buf)
+		err = writer.WriteAll(rows)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// @Summary Import manual scores
+// @Description Upload a CSV file with the columns user_id or email, item_id, points and an optional comment.
+// @Description Every row is checked first, when any row is invalid nothing is imported and the errors are reported per row
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param file formData file true "CSV file"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /course/{id}/gradebook/import [post]
+func (server *Server) ImportGradebookScores(ctx *gin.Context) {
+	var uri CourseGradebookRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "a CSV file is required"})
+		return
+	}
+	defer file.Close()
+
+	rows, rowErrors, err := gradebook.ParseScoresCSV(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	items, err := server.store.ListGradeItems(ctx, uri.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	itemsByID := make(map[int64]db.GradeItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ItemID] = item
+	}
+
+	students, err := server.store.ListGradebookStudents(ctx, uri.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	studentsByID := make(map[int64]bool, len(students))
+	studentsByEmail := make(map[string]int64, len(students))
+	for _, student := range students {
+		studentsByID[student.UserID] = true
+		studentsByEmail[strings.ToLower(student.Email)] = student.UserID
+	}
+
+	type scoreKey struct{ itemID, userID int64 }
+	seen := make(map[scoreKey]int, len(rows))
+	scores := make([]db.UpsertGradeItemScoreParams, 0, len(rows))
+	for _, row := range rows {
+		userID := row.UserID
+		if userID == 0 {
+			userID = studentsByEmail[row.Email]
+		}
+		if !studentsByID[userID] {
+			who := row.Email
+			if row.UserID != 0 {
+				who = fmt.Sprintf("user %d", row.UserID)
+			}
+			rowErrors = append(rowErrors, gradebook.RowError{Row: row.Row, Error: fmt.Sprintf("%s is not enrolled in the course", who)})
+			continue
+		}
+
+		item, ok := itemsByID[row.ItemID]
+		if !ok {
+			rowErrors = append(rowErrors, gradebook.RowError{Row: row.Row, Error: fmt.Sprintf("item %d is not a grade item of the course", row.ItemID)})
+			continue
+		}
+		if item.ItemType != gradebook.ItemManual {
+			rowErrors = append(rowErrors, gradebook.RowError{Row: row.Row, Error: fmt.Sprintf("item %d is graded from its %s", row.ItemID, item.ItemType)})
+			continue
+		}
+		if row.Points > item.MaxPoints {
+			rowErrors = append(rowErrors, gradebook.RowError{Row: row.Row, Error: fmt.Sprintf("points %v are more than the %v the item is worth", row.Points, item.MaxPoints)})
+			continue
+		}
+
+		key := scoreKey{itemID: item.ItemID, userID: userID}
+		if first, ok := seen[key]; ok {
+			rowErrors = append(rowErrors, gradebook.RowError{Row: row.Row, Error: fmt.Sprintf("the same score is already given on row %d", first)})
+			continue
+		}
+		seen[key] = row.Row
+
+		scores = append(scores, db.UpsertGradeItemScoreParams{
+			ItemID:  item.ItemID,
+			UserID:  userID,
+			Points:  row.Points,
+			Comment: row.Comment,
+		})
+	}
+
+	if len(rowErrors) > 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":      "the file has invalid rows, nothing was imported",
+			"row_errors": rowErrors,
+		})
+		return
+	}
+
+	result, err := server.store.ImportGradeItemScoresTx(ctx, db.ImportGradeItemScoresTxParams{Scores: scores})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"imported": len(result.Scores)})
+}
+
+// CreateGradeCategoryRequest defines the request body structure for creating a grade category
+type CreateGradeCategoryRequest struct {
+	CourseID   int64   `json:"course_id" binding:"required,min=1"`
+	Title      string  `json:"title" binding:"required"`
+	Weight     float64 `json:"weight" binding:"min=0"`
+	DropLowest int64   `json:"drop_lowest" binding:"min=0"`
+}
+
+// @Summary Create a grade category
+// @Description Create a weighted category of grade items, drop_lowest leaves out that many of the lowest scores
+// @Accept json
+// @Produce json
+// @Param request body CreateGradeCategoryRequest true "Create Grade Category Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /gradebook/category [post]
+func (server *Server) CreateGradeCategory(ctx *gin.Context) {
+	var req CreateGradeCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	category, err := server.store.CreateGradeCategory(ctx, db.CreateGradeCategoryParams{
+		CourseID:   req.CourseID,
+		Title:      strings.TrimSpace(req.Title),
+		Weight:     req.Weight,
+		DropLowest: req.DropLowest,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, category)
+}
+
+// UpdateGradeCategoryRequest defines the request body structure for updating a grade category
+type UpdateGradeCategoryRequest struct {
+	CategoryID int64   `json:"category_id" binding:"required,min=1"`
+	Title      string  `json:"title" binding:"required"`
+	Weight     float64 `json:"weight" binding:"min=0"`
+	DropLowest int64   `json:"drop_lowest" binding:"min=0"`
+}
+
+// @Summary Update a grade category
+// @Description Update the title, weight and drop lowest setting of a grade category
+// @Accept json
+// @Produce json
+// @Param request body UpdateGradeCategoryRequest true "Update Grade Category Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /gradebook/category/edit [put]
+func (server *Server) UpdateGradeCategory(ctx *gin.Context) {
+	var req UpdateGradeCategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	category, err := server.store.UpdateGradeCategory(ctx, db.UpdateGradeCategoryParams{
+		CategoryID: req.CategoryID,
+		Title:      strings.TrimSpace(req.Title),
+		Weight:     req.Weight,
+		DropLowest: req.DropLowest,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, category)
+}
+
+// DeleteGradeCategoryRequest defines the request structure for deleting a grade category
+type DeleteGradeCategoryRequest struct {
+	CategoryID int64 `form:"category_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a grade category
+// @Description Delete a grade category together with its grade items
+// @Produce json
+// @Param category_id query int true "Category ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /gradebook/category/delete [delete]
+func (server *Server) DeleteGradeCategory(ctx *gin.Context) {
+	var req DeleteGradeCategoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteGradeCategory(ctx, req.CategoryID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Grade category deleted successfully"})
+}
+
+// CreateGradeItemRequest defines the request body structure for adding an item to the gradebook
+type CreateGradeItemRequest struct {
+	CategoryID   int64   `json:"category_id" binding:"required,min=1"`
+	ItemType     string  `json:"item_type" binding:"required,oneof=assignment quiz manual"`
+	AssignmentID int64   `json:"assignment_id" binding:"omitempty,min=1"`
+	QuizID       int64   `json:"quiz_id" binding:"omitempty,min=1"`
+	Title        string  `json:"title"`
+	MaxPoints    float64 `json:"max_points" binding:"omitempty,gt=0"`
+}
+
+// @Summary Create a grade item
+// @Description Add an assignment, a quiz or a manually graded item to a grade category. Assignments default to the points of their rubric
+// @Accept json
+// @Produce json
+// @Param request body CreateGradeItemRequest true "Create Grade Item Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /gradebook/item [post]
+func (server *Server) CreateGradeItem(ctx *gin.Context) {
+	var req CreateGradeItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	category, err := server.store.GetGradeCategory(ctx, req.CategoryID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateGradeItemParams{
+		CourseID:   category.CourseID,
+		CategoryID: category.CategoryID,
+		Title:      strings.TrimSpace(req.Title),
+		ItemType:   req.ItemType,
+		MaxPoints:  req.MaxPoints,
+	}
+
+	switch req.ItemType {
+	case gradebook.ItemAssignment:
+		assignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+		if err != nil || assignment.CourseID != category.CourseID {
+			if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			err := errors.New("assignment_id must be an assignment of the course")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.AssignmentID = pgtype.Int8{Int64: assignment.AssignmentID, Valid: true}
+		if arg.Title == "" {
+			arg.Title = assignment.Title
+		}
+		if arg.MaxPoints == 0 {
+			arg.MaxPoints, err = server.assignmentMaxPoints(ctx, assignment.AssignmentID)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+	case gradebook.ItemQuiz:
+		quiz, err := server.store.GetQuiz(ctx, req.QuizID)
+		if err != nil || quiz.CourseID != category.CourseID {
+			if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			err := errors.New("quiz_id must be a quiz of the course")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.QuizID = pgtype.Int8{Int64: quiz.QuizID, Valid: true}
+		if arg.Title == "" {
+			arg.Title = quiz.Title
+		}
+	default:
+		if arg.Title == "" {
+			err := errors.New("a manual grade item needs a title")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if arg.MaxPoints == 0 {
+		arg.MaxPoints = defaultGradeItemMaxPoints
+	}
+
+	item, err := server.store.CreateGradeItem(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+// assignmentMaxPoints is the highest total of the assignment rubric, or the default when it has none
+func (server *Server) assignmentMaxPoints(ctx *gin.Context, assignmentID int64) (float64, error) {
+	rubric, err := server.store.GetRubricByAssignment(ctx, assignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return defaultGradeItemMaxPoints, nil
+		}
+		return 0, err
+	}
+
+	total := util.RubricMaxPoints(rubric.Criteria)
+	if total == 0 {
+		return defaultGradeItemMaxPoints, nil
+	}
+	return float64(total), nil
+}
+
+// UpdateGradeItemRequest defines the request body structure for updating a grade item
+type UpdateGradeItemRequest struct {
+	ItemID     int64   `json:"item_id" binding:"required,min=1"`
+	CategoryID int64   `json:"category_id" binding:"required,min=1"`
+	Title      string  `json:"title" binding:"required"`
+	MaxPoints  float64 `json:"max_points" binding:"required,gt=0"`
+}
+
+// @Summary Update a grade item
+// @Description Move a grade item to another category of the course or change its title and max points
+// @Accept json
+// @Produce json
+// @Param request body UpdateGradeItemRequest true "Update Grade Item Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /gradebook/item/edit [put]
+func (server *Server) UpdateGradeItem(ctx *gin.Context) {
+	var req UpdateGradeItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetGradeItem(ctx, req.ItemID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	category, err := server.store.GetGradeCategory(ctx, req.CategoryID)
+	if err != nil || category.CourseID != item.CourseID {
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		err := errors.New("category_id must be a grade category of the same course")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	item, err = server.store.UpdateGradeItem(ctx, db.UpdateGradeItemParams{
+		ItemID:     req.ItemID,
+		CategoryID: req.CategoryID,
+		Title:      strings.TrimSpace(req.Title),
+		MaxPoints:  req.MaxPoints,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+// DeleteGradeItemRequest defines the request structure for deleting a grade item
+type DeleteGradeItemRequest struct {
+	ItemID int64 `form:"item_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a grade item
+// @Description Remove an item from the gradebook, manual scores of the item are deleted with it
+// @Produce json
+// @Param item_id query int true "Item ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /gradebook/item/delete [delete]
+func (server *Server) DeleteGradeItem(ctx *gin.Context) {
+	var req DeleteGradeItemRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteGradeItem(ctx, req.ItemID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Grade item deleted successfully"})
+}
+
+// SetGradeItemScoreRequest defines the request body structure for scoring a manual grade item
+type SetGradeItemScoreRequest struct {
+	ItemID  int64   `json:"item_id" binding:"required,min=1"`
+	UserID  int64   `json:"user_id" binding:"required,min=1"`
+	Points  float64 `json:"points" binding:"min=0"`
+	Comment string  `json:"comment"`
+}
+
+// @Summary Score a manual grade item
+// @Description Store a student's points for a manually graded item
+// @Accept json
+// @Produce json
+// @Param request body SetGradeItemScoreRequest true "Set Grade Item Score Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /gradebook/score [put]
+func (server *Server) SetGradeItemScore(ctx *gin.Context) {
+	var req SetGradeItemScoreRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetGradeItem(ctx, req.ItemID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if item.ItemType != gradebook.ItemManual {
+		err := fmt.Errorf("the item is graded from its %s", item.ItemType)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Points > item.MaxPoints {
+		err := fmt.Errorf("points cannot be more than the %v the item is worth", item.MaxPoints)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	score, err := server.store.UpsertGradeItemScore(ctx, db.UpsertGradeItemScoreParams{
+		ItemID:  req.ItemID,
+		UserID:  req.UserID,
+		Points:  req.Points,
+		Comment: strings.TrimSpace(req.Comment),
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, score)
+}
+
+// SetGradeScaleRequest defines the request body structure for setting the letter grades of a course
+type SetGradeScaleRequest struct {
+	CourseID int64           `json:"course_id" binding:"required,min=1"`
+	Letters  gradebook.Scale `json:"letters" binding:"required"`
+}
+
+// @Summary Set the grade scale
+// @Description Set the letter grades of a course, each letter starts at min_percent and one letter has to start at 0
+// @Accept json
+// @Produce json
+// @Param request body SetGradeScaleRequest true "Set Grade Scale Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /gradebook/scale [put]
+func (server *Server) SetGradeScale(ctx *gin.Context) {
+	var req SetGradeScaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := req.Letters.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scale, err := server.store.UpsertGradeScale(ctx, db.UpsertGradeScaleParams{
+		CourseID: req.CourseID,
+		Letters:  req.Letters.Sorted(),
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scale)
+}
@@ -0,0 +1,600 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"eduApp/util"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// courseGroupResponse is a group of a course with its members
+type courseGroupResponse struct {
+	db.CourseGroup
+	Members []db.ListCourseGroupMembersRow `json:"members"`
+}
+
+// listCourseGroups returns every group of a course with its members
+func (server *Server) listCourseGroups(ctx *gin.Context, courseID int64) ([]courseGroupResponse, error) {
+	groups, err := server.store.ListCourseGroups(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := server.store.ListCourseGroupMembers(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byGroup := make(map[int64][]db.ListCourseGroupMembersRow)
+	for _, member := range members {
+		byGroup[member.GroupID] = append(byGroup[member.GroupID], member)
+	}
+
+	rsp := make([]courseGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		groupMembers := byGroup[group.GroupID]
+		if groupMembers == nil {
+			groupMembers = []db.ListCourseGroupMembersRow{}
+		}
+		rsp = append(rsp, courseGroupResponse{CourseGroup: group, Members: groupMembers})
+	}
+
+	return rsp, nil
+}
+
+// CreateCourseGroupRequest defines the request body structure for creating a group in a course
+type CreateCourseGroupRequest struct {
+	CourseID int64  `json:"course_id" binding:"required,min=1"`
+	Name     string `json:"name" binding:"required"`
+}
+
+// @Summary Create a group
+// @Description Create an empty group in a course, group names are unique within a course
+// @ID create-course-group
+// @Accept json
+// @Produce json
+// @Param request body CreateCourseGroupRequest true "Create Course Group Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /group [post]
+func (server *Server) CreateCourseGroup(ctx *gin.Context) {
+	var req CreateCourseGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	group, err := server.store.CreateCourseGroup(ctx, db.CreateCourseGroupParams{
+		CourseID: req.CourseID,
+		Name:     strings.TrimSpace(req.Name),
+	})
+	if err != nil {
+		if code := db.ErrorCode(err); code == db.ForeignKeyViolation || code == db.UniqueViolations {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+}
+
+// ListCourseGroupsRequest contains the input parameters for listing the groups of a course
+type ListCourseGroupsRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List groups
+// @Description List the groups of a course with their members
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /groups [get]
+func (server *Server) ListCourseGroups(ctx *gin.Context) {
+	var req ListCourseGroupsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	groups, err := server.listCourseGroups(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, groups)
+}
+
+// GetMyCourseGroupRequest contains the input parameters for getting the logged in student's group
+type GetMyCourseGroupRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Get my group
+// @Description Get the group of the logged in student in a course with its members
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /group/mine [get]
+func (server *Server) GetMyCourseGroup(ctx *gin.Context) {
+	var req GetMyCourseGroupRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	group, err := server.store.GetCourseGroupByMember(ctx, db.GetCourseGroupByMemberParams{
+		CourseID: req.CourseID,
+		UserID:   authPayload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	members, err := server.store.ListCourseGroupMembers(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := courseGroupResponse{CourseGroup: group, Members: []db.ListCourseGroupMembersRow{}}
+	for _, member := range members {
+		if member.GroupID == group.GroupID {
+			rsp.Members = append(rsp.Members, member)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// UpdateCourseGroupRequest defines the request body structure for renaming a group
+type UpdateCourseGroupRequest struct {
+	GroupID int64  `json:"group_id" binding:"required,min=1"`
+	Name    string `json:"name" binding:"required"`
+}
+
+// @Summary Rename a group
+// @Accept json
+// @Produce json
+// @Param request body UpdateCourseGroupRequest true "Update Course Group Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /group/edit [patch]
+func (server *Server) UpdateCourseGroup(ctx *gin.Context) {
+	var req UpdateCourseGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	group, err := server.store.UpdateCourseGroup(ctx, db.UpdateCourseGroupParams{
+		GroupID: req.GroupID,
+		Name:    strings.TrimSpace(req.Name),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolations {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+}
+
+// DeleteCourseGroupRequest defines the request structure for deleting a group
+type DeleteCourseGroupRequest struct {
+	GroupID int64 `form:"group_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a group
+// @Description Delete a group and its memberships. Submissions of the group are kept but no longer shared with its former members
+// @Produce json
+// @Param group_id query int true "Group ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /group/delete [delete]
+func (server *Server) DeleteCourseGroup(ctx *gin.Context) {
+	var req DeleteCourseGroupRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteCourseGroup(ctx, req.GroupID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
+}
+
+// AddCourseGroupMemberRequest defines the request body structure for adding a student to a group
+type AddCourseGroupMemberRequest struct {
+	GroupID int64 `json:"group_id" binding:"required,min=1"`
+	UserID  int64 `json:"user_id" binding:"required,min=1"`
+}
+
+// @Summary Add a group member
+// @Description Add a student to a group, a student can be in only one group of a course
+// @ID add-course-group-member
+// @Accept json
+// @Produce json
+// @Param request body AddCourseGroupMemberRequest true "Add Course Group Member Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /group/member [post]
+func (server *Server) AddCourseGroupMember(ctx *gin.Context) {
+	var req AddCourseGroupMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	group, err := server.store.GetCourseGroup(ctx, req.GroupID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	member, err := server.store.AddCourseGroupMember(ctx, db.AddCourseGroupMemberParams{
+		GroupID:  group.GroupID,
+		CourseID: group.CourseID,
+		UserID:   req.UserID,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolations {
+			err = errors.New("the student is already in a group of this course")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// RemoveCourseGroupMemberRequest defines the request structure for removing a student from a group
+type RemoveCourseGroupMemberRequest struct {
+	GroupID int64 `form:"group_id" binding:"required,min=1"`
+	UserID  int64 `form:"user_id" binding:"required,min=1"`
+}
+
+// @Summary Remove a group member
+// @Produce json
+// @Param group_id query int true "Group ID"
+// @Param user_id query int true "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /group/member/delete [delete]
+func (server *Server) RemoveCourseGroupMember(ctx *gin.Context) {
+	var req RemoveCourseGroupMemberRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	err := server.store.RemoveCourseGroupMember(ctx, db.RemoveCourseGroupMemberParams{
+		GroupID: req.GroupID,
+		UserID:  req.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Group member removed successfully"})
+}
+
+// GenerateCourseGroupsRequest defines the request body structure for generating the groups of a course
+type GenerateCourseGroupsRequest struct {
+	CourseID  int64  `json:"course_id" binding:"required,min=1"`
+	GroupSize int    `json:"group_size" binding:"required,min=1"`
+	Strategy  string `json:"strategy" binding:"required,oneof=random balanced"`
+	// NamePrefix names the groups NamePrefix 1, NamePrefix 2 and so on, Group by default
+	NamePrefix string `json:"name_prefix"`
+}
+
+// @Summary Generate groups
+// @Description Put every enrolled student who is not in a group yet into new groups of group_size. The random strategy fills
+// @Description groups of exactly group_size with the rest in a last smaller group, the balanced strategy keeps sizes within one of each other
+// @ID generate-course-groups
+// @Accept json
+// @Produce json
+// @Param request body GenerateCourseGroupsRequest true "Generate Course Groups Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /groups/generate [post]
+func (server *Server) GenerateCourseGroups(ctx *gin.Context) {
+	var req GenerateCourseGroupsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	students, err := server.store.ListUngroupedStudents(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	split, err := util.SplitIntoGroups(students, req.GroupSize, req.Strategy, rng)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	existing, err := server.store.ListCourseGroups(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, group := range existing {
+		taken[group.Name] = true
+	}
+
+	prefix := strings.TrimSpace(req.NamePrefix)
+	if prefix == "" {
+		prefix = "Group"
+	}
+
+	// numbering continues after the names already used in the course
+	groups := make([]db.CourseGroupTxParams, 0, len(split))
+	number := 1
+	for _, userIDs := range split {
+		name := fmt.Sprintf("%s %d", prefix, number)
+		for taken[name] {
+			number++
+			name = fmt.Sprintf("%s %d", prefix, number)
+		}
+		taken[name] = true
+		groups = append(groups, db.CourseGroupTxParams{Name: name, UserIDs: userIDs})
+	}
+
+	result, err := server.store.CreateCourseGroupsTx(ctx, db.CreateCourseGroupsTxParams{
+		CourseID: req.CourseID,
+		Groups:   groups,
+	})
+	if err != nil {
+		if code := db.ErrorCode(err); code == db.ForeignKeyViolation || code == db.UniqueViolations {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"groups":  result.Groups,
+		"members": result.Members,
+	})
+}
+
+// SetSubmissionAdjustmentRequest defines the request body structure for adjusting one member's grade of a group submission
+type SetSubmissionAdjustmentRequest struct {
+	SubmissionID int64   `json:"submission_id" binding:"required,min=1"`
+	UserID       int64   `json:"user_id" binding:"required,min=1"`
+	Points       float64 `json:"points"`
+	Reason       string  `json:"reason"`
+}
+
+// @Summary Adjust a member's grade
+// @Description Add or remove points for one member of a group submission, the group grade plus the adjustment goes to the member's gradebook
+// @ID set-submission-adjustment
+// @Accept json
+// @Produce json
+// @Param request body SetSubmissionAdjustmentRequest true "Set Submission Adjustment Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /submission/adjustment [put]
+func (server *Server) SetSubmissionAdjustment(ctx *gin.Context) {
+	var req SetSubmissionAdjustmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	submission, err := server.store.GetSubmissionByID(ctx, req.SubmissionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the grade of a group submission belongs to the current members, the uploader counts only without a group
+	if err := server.checkSubmissionMember(ctx, submission, req.UserID); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err = errors.New("the student is not a member of this submission")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	adjustment, err := server.store.UpsertSubmissionAdjustment(ctx, db.UpsertSubmissionAdjustmentParams{
+		SubmissionID: req.SubmissionID,
+		UserID:       req.UserID,
+		Points:       req.Points,
+		Reason:       strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, adjustment)
+}
+
+// checkSubmissionMember returns db.ErrRecordNotFound unless the student shares the submission
+func (server *Server) checkSubmissionMember(ctx *gin.Context, submission db.Submission, userID int64) error {
+	if !submission.GroupID.Valid {
+		if submission.UserID != userID {
+			return db.ErrRecordNotFound
+		}
+		return nil
+	}
+
+	group, err := server.store.GetCourseGroup(ctx, submission.GroupID.Int64)
+	if err != nil {
+		return err
+	}
+	member, err := server.store.GetCourseGroupByMember(ctx, db.GetCourseGroupByMemberParams{
+		CourseID: group.CourseID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return err
+	}
+	if member.GroupID != group.GroupID {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteSubmissionAdjustmentRequest defines the request structure for removing a member's grade adjustment
+type DeleteSubmissionAdjustmentRequest struct {
+	SubmissionID int64 `form:"submission_id" binding:"required,min=1"`
+	UserID       int64 `form:"user_id" binding:"required,min=1"`
+}
+
+// @Summary Remove a member's grade adjustment
+// @Produce json
+// @Param submission_id query int true "Submission ID"
+// @Param user_id query int true "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /submission/adjustment/delete [delete]
+func (server *Server) DeleteSubmissionAdjustment(ctx *gin.Context) {
+	var req DeleteSubmissionAdjustmentRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteSubmissionAdjustment(ctx, db.DeleteSubmissionAdjustmentParams{
+		SubmissionID: req.SubmissionID,
+		UserID:       req.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Adjustment removed successfully"})
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"eduApp/typetext"
+	"eduApp/util"
+	"fmt"
+	"image"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	thumbImageSize  = 150
+	mediumImageSize = 640
+	fullImageSize   = 1600
+)
+
+// allowedImageExtensions lists the image uploads that can be decoded and re-encoded, with the format their content must decode as
+var allowedImageExtensions = map[string]string{
+	".jpg":  "jpeg",
+	".jpeg": "jpeg",
+	".png":  "png",
+	".jfif": "jpeg",
+}
+
+// saveImageVariants decodes an uploaded image, strips its metadata, turns it upright and
+// stores a thumb, medium and full size copy in every registered format under uploadDir
+func (server *Server) saveImageVariants(file multipart.File, header *multipart.FileHeader, uploadDir string) (typetext.ImageVariants, error) {
+	var variants typetext.ImageVariants
+
+	fileExt := strings.ToLower(filepath.Ext(header.Filename))
+	wantFormat, ok := allowedImageExtensions[fileExt]
+	if !ok {
+		return variants, fmt.Errorf("unsupported file extension: %s", fileExt)
+	}
+
+	img, format, err := util.DecodeImage(file)
+	if err != nil {
+		return variants, err
+	}
+	if format != wantFormat {
+		return variants, fmt.Errorf("file content is %s, not the %s its extension %s claims", format, wantFormat, fileExt)
+	}
+
+	// Generate unique filename
+	originalFileName := strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	baseName := strings.ReplaceAll(strings.ToLower(originalFileName), " ", "-") + "-" + uuid.NewString()
+
+	// Create upload directory if it doesn't exist
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return variants, err
+	}
+
+	sizes := []struct {
+		name    string
+		variant *typetext.ImageVariant
+		image   image.Image
+	}{
+		{"thumb", &variants.Thumb, util.CropImage(img, thumbImageSize)},
+		{"medium", &variants.Medium, util.FitImage(img, mediumImageSize)},
+		{"full", &variants.Full, util.FitImage(img, fullImageSize)},
+	}
+
+	for _, size := range sizes {
+		size.variant.Width = size.image.Bounds().Dx()
+		size.variant.Height = size.image.Bounds().Dy()
+
+		for ext, encode := range util.ImageEncoders() {
+			filePath := filepath.Join(uploadDir, fmt.Sprintf("%s-%s.%s", baseName, size.name, ext))
+			out, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil {
+				return variants, err
+			}
+			err = encode(out, size.image)
+			out.Close()
+			if err != nil {
+				return variants, fmt.Errorf("failed to encode %s image: %w", ext, err)
+			}
+
+			switch ext {
+			case "jpg":
+				size.variant.JPEG = server.staticFileURL(filePath)
+			case "webp":
+				size.variant.WebP = server.staticFileURL(filePath)
+			}
+		}
+	}
+
+	return variants, nil
+}
+
+// staticFileURL returns the public URL of a file stored below the upload directory
+func (server *Server) staticFileURL(filePath string) string {
+	return util.StaticFileURL(server.config.FileSource, filePath)
+}
+
+// deleteImageVariants removes every stored copy of an image
+func deleteImageVariants(variants typetext.ImageVariants) {
+	for _, variant := range []typetext.ImageVariant{variants.Thumb, variants.Medium, variants.Full} {
+		for _, fileURL := range []string{variant.JPEG, variant.WebP} {
+			if fileURL != "" {
+				util.DeleteFileByURL(fileURL)
+			}
+		}
+	}
+}
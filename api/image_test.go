@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"eduApp/typetext"
+	"eduApp/util"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// uploadedFile serves bytes as a multipart upload
+type uploadedFile struct {
+	*bytes.Reader
+}
+
+func (uploadedFile) Close() error { return nil }
+
+func pngUpload(t *testing.T, w, h int) uploadedFile {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return uploadedFile{bytes.NewReader(buf.Bytes())}
+}
+
+func variantFiles(variants typetext.ImageVariants) []string {
+	var files []string
+	for _, variant := range []typetext.ImageVariant{variants.Thumb, variants.Medium, variants.Full} {
+		files = append(files, variant.JPEG, variant.WebP)
+	}
+	return files
+}
+
+func TestSaveImageVariants(t *testing.T) {
+	server := &Server{}
+	uploadDir := filepath.Join(t.TempDir(), util.UploadDir, "course")
+	header := &multipart.FileHeader{Filename: "My Course.png"}
+
+	first, err := server.saveImageVariants(pngUpload(t, 800, 400), header, uploadDir)
+	if err != nil {
+		t.Fatalf("saveImageVariants() error = %v", err)
+	}
+	// the same file name uploaded again at once must not replace the first upload
+	second, err := server.saveImageVariants(pngUpload(t, 800, 400), header, uploadDir)
+	if err != nil {
+		t.Fatalf("saveImageVariants() error = %v", err)
+	}
+
+	if first.Thumb.Width != thumbImageSize || first.Medium.Width != mediumImageSize || first.Full.Width != 800 || first.Full.Height != 400 {
+		t.Errorf("variants = %+v, want a %d thumb, %d medium and the full 800x400 image", first, thumbImageSize, mediumImageSize)
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range append(variantFiles(first), variantFiles(second)...) {
+		if file == "" || seen[file] {
+			t.Fatalf("variant file %q is missing or used twice", file)
+		}
+		seen[file] = true
+	}
+
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(seen) {
+		t.Errorf("upload directory holds %d files, want %d", len(entries), len(seen))
+	}
+}
+
+func TestSaveImageVariantsRejectsUploads(t *testing.T) {
+	server := &Server{}
+	uploadDir := t.TempDir()
+
+	testCases := map[string]struct {
+		file     uploadedFile
+		filename string
+	}{
+		"extension not allowed": {file: pngUpload(t, 10, 10), filename: "picture.gif"},
+		"png named as jpeg":     {file: pngUpload(t, 10, 10), filename: "picture.jpg"},
+		"not an image":          {file: uploadedFile{bytes.NewReader([]byte("hello"))}, filename: "picture.png"},
+	}
+	for name, tc := range testCases {
+		if _, err := server.saveImageVariants(tc.file, &multipart.FileHeader{Filename: tc.filename}, uploadDir); err == nil {
+			t.Errorf("%s: saveImageVariants() error = nil", name)
+		}
+	}
+
+	if entries, _ := os.ReadDir(uploadDir); len(entries) != 0 {
+		t.Errorf("rejected uploads left %d files", len(entries))
+	}
+}
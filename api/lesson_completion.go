@@ -3,13 +3,10 @@ package api
 import (
 	db "eduApp/db/sqlc"
 	"eduApp/token"
-	"eduApp/worker"
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -174,33 +171,22 @@ func (server Server) CreateLessonCompletion(ctx *gin.Context) {
 			MaterialID: req.MaterialID,
 			Completed:  true,
 		},
-		AfterCreate: func(LessonCompletion db.LessonCompletion) error {
-			// Use Redis for task distribution
-			taskPayload := &worker.PayloadCreateLessonCompletion{
-				UserID:     LessonCompletion.UserID,
-				CourseID:   LessonCompletion.CourseID,
-				MaterialID: LessonCompletion.MaterialID,
-			}
-
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.ProcessIn(10 * time.Second),
-				asynq.Queue(worker.QueueCritical),
-			}
-			return server.taskDistributor.DistributeTaskCreateLessonCompletion(ctx, taskPayload, opts...)
-		},
+		AfterComplete: server.courseCompletionHook(ctx),
 	}
 
 	txResult, err := server.store.CreateLessonCompletionTx(ctx, arg)
-
 	if err != nil {
-		if db.ErrorCode(err) == db.UniqueViolations {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err = errors.New("material doesn't exist in this course")
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	server.distributeLessonCompleted(ctx, txResult.LessonCompletion, authPayload.UserName)
+	server.publishProgress(ctx, txResult.CourseProgress)
+
 	ctx.JSON(http.StatusCreated, txResult)
 }
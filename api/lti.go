@@ -0,0 +1,963 @@
+package api
+
+import (
+	"crypto/subtle"
+	db "eduApp/db/sqlc"
+	"eduApp/enrollment"
+	"eduApp/lti"
+	"eduApp/token"
+	"eduApp/util"
+	"eduApp/worker"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// ltiLaunchStateTTL is how long the platform has to answer a login request with a launch
+	ltiLaunchStateTTL = 10 * time.Minute
+	// ltiDeepLinkTTL is how long an instructor has to pick the content of a deep linking request
+	ltiDeepLinkTTL = time.Hour
+	// ltiMaxUserNameAttempts is how many user names, the first without a random suffix, are tried for a platform user
+	ltiMaxUserNameAttempts = 5
+	// ltiStateCookiePrefix names the cookie binding a launch state to the browser that started the login,
+	// the state is part of the name so launches in several frames at once do not overwrite each other
+	ltiStateCookiePrefix = "lti_state_"
+)
+
+var (
+	errLTIUnknownPlatform = errors.New("platform is not registered")
+	errLTIStateExpired    = errors.New("launch state is unknown or expired")
+	errLTIStateMismatch   = errors.New("launch was not started by this browser")
+	errLTINotInstructor   = errors.New("only instructors can pick content for the platform")
+	errLTINoCourse        = errors.New("resource link is not linked to a course")
+	errLTICourseNotLinked = errors.New("the platform is not allowed to link to this course")
+	errLTINotTeacher      = errors.New("only the teacher of the course can link it to a platform")
+	errLTIEmailTaken      = errors.New("an account with this email exists already, it has to be linked to the platform by an admin")
+	errLTIUserNameTaken   = errors.New("could not find a free user name for the platform user")
+)
+
+// requireLTITool answers 503 when the LTI tool is not configured
+func (server *Server) requireLTITool(ctx *gin.Context) bool {
+	if server.ltiTool == nil {
+		ctx.JSON(http.StatusServiceUnavailable, errorResponse(lti.ErrNotConfigured))
+		return false
+	}
+	return true
+}
+
+// setLTIStateCookie binds a launch state to the browser, the launch is posted cross-site by the platform
+// so the cookie has to be SameSite=None, which browsers only accept on secure cookies
+func (server *Server) setLTIStateCookie(ctx *gin.Context, state string, maxAge int) {
+	path := "/"
+	if launchURL, err := url.Parse(server.ltiTool.LaunchURL()); err == nil && launchURL.Path != "" {
+		path = launchURL.Path
+	}
+
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     ltiStateCookiePrefix + state,
+		Value:    state,
+		Path:     path,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+// distributeLTIScore queues posting the marks of a student back to the platforms linking the course,
+// the marks stand even if the task cannot be queued
+func (server *Server) distributeLTIScore(ctx *gin.Context, courseID, userID int64) {
+	if server.ltiTool == nil {
+		return
+	}
+	if err := worker.DistributeLTIScore(ctx, server.taskDistributor, courseID, userID); err != nil {
+		log.Error().Err(err).Int64("course_id", courseID).Int64("user_id", userID).Msg("failed to enqueue LTI score")
+	}
+}
+
+func ltiPlatform(platform db.LtiPlatform) lti.Platform {
+	return lti.Platform{
+		Issuer:       platform.Issuer,
+		ClientID:     platform.ClientID,
+		DeploymentID: platform.DeploymentID,
+		AuthLoginURL: platform.AuthLoginURL,
+		AuthTokenURL: platform.AuthTokenURL,
+		JWKSURL:      platform.JwksURL,
+	}
+}
+
+// findLTIPlatform picks the registration of an issuer matching the client and deployment of a login request,
+// platforms may leave out either of them when the issuer has a single registration
+func findLTIPlatform(platforms []db.LtiPlatform, clientID, deploymentID string) (db.LtiPlatform, bool) {
+	var found []db.LtiPlatform
+	for _, platform := range platforms {
+		if clientID != "" && platform.ClientID != clientID {
+			continue
+		}
+		if deploymentID != "" && platform.DeploymentID != "" && platform.DeploymentID != deploymentID {
+			continue
+		}
+		found = append(found, platform)
+	}
+	if len(found) == 0 || (clientID == "" && len(found) > 1) {
+		return db.LtiPlatform{}, false
+	}
+	return found[0], true
+}
+
+// ltiConfigResponse is what an administrator enters in the platform when registering the tool
+type ltiConfigResponse struct {
+	LoginURL       string `json:"login_url"`
+	LaunchURL      string `json:"launch_url"`
+	DeepLinkingURL string `json:"deep_linking_url"`
+	JWKSURL        string `json:"jwks_url"`
+}
+
+// @Summary Get the LTI tool configuration
+// @Description URLs to register the tool with in Moodle, Canvas or another LTI 1.3 platform
+// @Produce json
+// @Success 200
+// @Failure 503
+// @Router /lti/config [get]
+// GetLTIConfig returns the URLs of the LTI tool
+func (server *Server) GetLTIConfig(ctx *gin.Context) {
+	if !server.requireLTITool(ctx) {
+		return
+	}
+
+	launchURL := server.ltiTool.LaunchURL()
+	ctx.JSON(http.StatusOK, ltiConfigResponse{
+		LoginURL:       server.ltiTool.LoginURL(),
+		LaunchURL:      launchURL,
+		DeepLinkingURL: launchURL,
+		JWKSURL:        strings.TrimSuffix(launchURL, "/launch") + "/jwks",
+	})
+}
+
+// @Summary Get the LTI tool keys
+// @Description Public key set platforms verify deep linking responses and grade service requests of the tool with
+// @Produce json
+// @Success 200
+// @Failure 503
+// @Router /lti/jwks [get]
+// GetLTIJWKS returns the public keys of the LTI tool
+func (server *Server) GetLTIJWKS(ctx *gin.Context) {
+	if !server.requireLTITool(ctx) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, server.ltiTool.JWKS())
+}
+
+// @Summary Start an LTI launch
+// @Description OIDC third-party initiated login. The platform sends the browser here first, the tool keeps a state and a
+// @Description nonce and redirects to the platform authorization endpoint, which posts the launch to /lti/launch.
+// @Description The state is also set in a short-lived cookie, the launch is only accepted from the same browser.
+// @Param iss query string true "Platform issuer"
+// @Param login_hint query string true "Login hint"
+// @Param target_link_uri query string false "Target link URI"
+// @Param lti_message_hint query string false "LTI message hint"
+// @Param client_id query string false "Client ID"
+// @Param lti_deployment_id query string false "Deployment ID"
+// @Success 302
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Router /lti/login [get]
+// LTILogin answers the login initiation of a platform
+func (server *Server) LTILogin(ctx *gin.Context) {
+	if !server.requireLTITool(ctx) {
+		return
+	}
+
+	var req lti.LoginRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	platforms, err := server.store.ListLTIPlatformsByIssuer(ctx, req.Issuer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	platform, ok := findLTIPlatform(platforms, req.ClientID, req.DeploymentID)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errorResponse(errLTIUnknownPlatform))
+		return
+	}
+
+	if err := server.store.DeleteExpiredLTILaunchStates(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to delete expired LTI launch states")
+	}
+
+	state := db.CreateLTILaunchStateParams{
+		State:      uuid.NewString(),
+		Nonce:      uuid.NewString(),
+		PlatformID: platform.PlatformID,
+		ExpiresAt:  time.Now().Add(ltiLaunchStateTTL),
+	}
+	if err := server.store.CreateLTILaunchState(ctx, state); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	server.setLTIStateCookie(ctx, state.State, int(ltiLaunchStateTTL.Seconds()))
+
+	redirect, err := server.ltiTool.AuthRedirect(ltiPlatform(platform), req, state.State, state.Nonce)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, redirect)
+}
+
+// LTILaunchRequest is the form a platform authorization endpoint posts
+type LTILaunchRequest struct {
+	IDToken string `form:"id_token" binding:"required"`
+	State   string `form:"state" binding:"required"`
+}
+
+// @Summary Launch the tool
+// @Description Verifies the id_token of a platform against its keys and signs the user in, creating an account on the first
+// @Description launch and asking to enroll them like any student. Resource links open their course in the front end when the
+// @Description platform was allowed to link it, deep linking requests open the content picker for instructors. The state must
+// @Description match the cookie set by the login. A new platform user whose email has an account already is refused with 409.
+// @Accept x-www-form-urlencoded
+// @Param id_token formData string true "ID token"
+// @Param state formData string true "State"
+// @Success 302
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Failure 500
+// @Failure 503
+// @Router /lti/launch [post]
+// LTILaunch handles resource link and deep linking launches
+func (server *Server) LTILaunch(ctx *gin.Context) {
+	if !server.requireLTITool(ctx) {
+		return
+	}
+
+	var req LTILaunchRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the state must come back to the browser that started the login, or anyone could log a victim in as themselves
+	cookie, err := ctx.Cookie(ltiStateCookiePrefix + req.State)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errLTIStateMismatch))
+		return
+	}
+	server.setLTIStateCookie(ctx, req.State, -1)
+
+	state, err := server.store.ConsumeLTILaunchState(ctx, req.State)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errLTIStateExpired))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	platform, err := server.store.GetLTIPlatform(ctx, state.PlatformID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errLTIUnknownPlatform))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	launch, err := server.ltiTool.VerifyLaunch(ctx, req.IDToken, ltiPlatform(platform), state.Nonce)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err := server.ltiUser(ctx, platform.PlatformID, launch)
+	if err != nil {
+		if errors.Is(err, errLTIEmailTaken) || errors.Is(err, errLTIUserNameTaken) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	fragment := url.Values{}
+	page := "/lti/launch"
+
+	switch launch.MessageType {
+	case lti.MessageDeepLinking:
+		if !launch.IsInstructor() {
+			ctx.JSON(http.StatusForbidden, errorResponse(errLTINotInstructor))
+			return
+		}
+
+		deepLink, err := server.store.CreateLTIDeepLink(ctx, db.CreateLTIDeepLinkParams{
+			DeepLinkID:   uuid.NewString(),
+			PlatformID:   platform.PlatformID,
+			UserID:       user.UserID,
+			DeploymentID: launch.DeploymentID,
+			ReturnURL:    launch.DeepLinking.ReturnURL,
+			Data:         launch.DeepLinking.Data,
+			ExpiresAt:    time.Now().Add(ltiDeepLinkTTL),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		page = "/lti/deeplink"
+		fragment.Set("deep_link_id", deepLink.DeepLinkID)
+
+	default:
+		courseID, ok := launch.CustomID("course_id")
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errLTINoCourse))
+			return
+		}
+
+		// anyone able to edit a link on the platform can name any course, only the courses granted to it are linked
+		linked, err := server.store.HasLTIPlatformCourse(ctx, db.HasLTIPlatformCourseParams{
+			PlatformID: platform.PlatformID,
+			CourseID:   courseID,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if !linked {
+			ctx.JSON(http.StatusForbidden, errorResponse(errLTICourseNotLinked))
+			return
+		}
+
+		var materialID pgtype.Int8
+		if id, ok := launch.CustomID("material_id"); ok {
+			material, err := server.store.GetMaterialByID(ctx, id)
+			if err != nil {
+				if errors.Is(err, db.ErrRecordNotFound) {
+					ctx.JSON(http.StatusNotFound, errorResponse(err))
+					return
+				}
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			if material.CourseID != courseID {
+				err := errors.New("material does not belong to the course")
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			materialID = pgtype.Int8{Int64: id, Valid: true}
+			fragment.Set("material_id", strconv.FormatInt(id, 10))
+		}
+
+		if !server.enrollLTIUser(ctx, user.UserID, courseID, fragment) {
+			return
+		}
+
+		var lineItem string
+		if launch.CanPostScores() {
+			lineItem = launch.AGS.LineItem
+		}
+
+		_, err = server.store.UpsertLTIResourceLink(ctx, db.UpsertLTIResourceLinkParams{
+			PlatformID:     platform.PlatformID,
+			ResourceLinkID: launch.ResourceLink.ID,
+			CourseID:       courseID,
+			MaterialID:     materialID,
+			LineitemURL:    lineItem,
+		})
+		if err != nil {
+			if db.ErrorCode(err) == db.ForeignKeyViolation {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		fragment.Set("course_id", strconv.FormatInt(courseID, 10))
+	}
+
+	if !server.addLTISession(ctx, user, fragment) {
+		return
+	}
+
+	// tokens travel in the fragment so they never reach server logs or the Referer header
+	ctx.Redirect(http.StatusFound, strings.TrimRight(server.config.FrontEndOrigin, "/")+page+"#"+fragment.Encode())
+}
+
+// ltiUser returns the account of a platform user, creating a student account on their first launch.
+// A platform can claim any email, so a launch is not signed in to an existing account with the same email,
+// errLTIEmailTaken is returned instead and an admin has to link the accounts.
+func (server *Server) ltiUser(ctx *gin.Context, platformID int64, launch lti.Launch) (db.User, error) {
+	ltiUser, err := server.store.GetLTIUser(ctx, db.GetLTIUserParams{
+		PlatformID: platformID,
+		Subject:    launch.Subject,
+	})
+	if err == nil {
+		return server.store.GetUserByID(ctx, ltiUser.UserID)
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return db.User{}, err
+	}
+
+	if launch.Email != "" {
+		_, err := server.store.GetUserByEmail(ctx, launch.Email)
+		if err == nil {
+			return db.User{}, errLTIEmailTaken
+		}
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			return db.User{}, err
+		}
+	}
+
+	hashedPassword, err := util.HashPassword(util.RandomString(32))
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	firstName, lastName := launch.GivenName, launch.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(launch.Name, " ")
+	}
+
+	userName := enrollment.UserNameFromEmail(launch.Email)
+	if launch.Email == "" {
+		userName = enrollment.UserNameFromEmail(firstName + lastName)
+	}
+	userName, err = server.freeLTIUserName(ctx, userName)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	user, err := server.store.CreateUser(ctx, db.CreateUserParams{
+		UserName:        userName,
+		FirstName:       firstName,
+		LastName:        lastName,
+		HashedPassword:  hashedPassword,
+		Email:           launch.Email,
+		Role:            "student",
+		IsEmailVerified: false,
+	})
+	if err != nil {
+		// another launch took the name since it was looked up
+		if db.ErrorCode(err) == db.UniqueViolations {
+			return db.User{}, errLTIUserNameTaken
+		}
+		return db.User{}, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	_, err = server.store.CreateLTIUser(ctx, db.CreateLTIUserParams{
+		PlatformID: platformID,
+		Subject:    launch.Subject,
+		UserID:     user.UserID,
+	})
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to link platform user: %w", err)
+	}
+	return user, nil
+}
+
+// freeLTIUserName returns userName, or userName with a random suffix when it is taken
+func (server *Server) freeLTIUserName(ctx *gin.Context, userName string) (string, error) {
+	candidate := userName
+	for attempt := 1; attempt <= ltiMaxUserNameAttempts; attempt++ {
+		_, err := server.store.GetUser(ctx, candidate)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = userName + util.RandomString(4)
+	}
+	return "", errLTIUserNameTaken
+}
+
+// enrollLTIUser asks to enroll a platform user in the course of a resource link like any student asking to join,
+// so the enrollment mode, capacity and price of the course apply. A student enrolled before keeps their subscription.
+// When the course turns the student away the launch still signs them in, the reason is added to the fragment so the
+// front end can offer to pay or ask to join. It answers the request and returns false on failure.
+func (server *Server) enrollLTIUser(ctx *gin.Context, userID, courseID int64, fragment url.Values) bool {
+	result, err := server.store.CreateSubscriptionTx(ctx, db.CreateSubscriptionTxParams{
+		UserID:      userID,
+		CourseID:    courseID,
+		Now:         time.Now(),
+		AfterCreate: server.distributeCreateSubscription(ctx),
+	})
+	if err == nil {
+		if result.Subscription.WaitlistedAt.Valid {
+			server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventWaitlisted)
+		}
+		return true
+	}
+	if db.ErrorCode(err) == db.UniqueViolations {
+		return true
+	}
+	if errors.Is(err, db.ErrPaymentRequired) || isEnrollmentRefused(err) {
+		fragment.Set("enrollment_error", err.Error())
+		return true
+	}
+
+	if errors.Is(err, db.ErrRecordNotFound) || db.ErrorCode(err) == db.ForeignKeyViolation {
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return false
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	return false
+}
+
+// addLTISession signs a platform user in the way loginUser does and adds the tokens to the redirect fragment.
+// It answers the request and returns false on failure.
+func (server *Server) addLTISession(ctx *gin.Context, user db.User, fragment url.Values) bool {
+	accessToken, _, err := server.tokenMaker.CreateToken(
+		user.UserName,
+		user.Role,
+		user.UserID,
+		server.config.AccessTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
+		user.UserName,
+		user.Role,
+		user.UserID,
+		server.config.RefreshTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+		SessionID: pgtype.UUID{
+			Bytes: refreshPayload.ID,
+			Valid: true,
+		},
+		UserID:       user.UserID,
+		RefreshToken: refreshToken,
+		UserAgent:    ctx.Request.UserAgent(),
+		ClientIp:     ctx.ClientIP(),
+		IsBlocked:    false,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	fragment.Set("session_id", uuid.UUID(session.SessionID.Bytes).String())
+	fragment.Set("access_token", accessToken)
+	fragment.Set("refresh_token", refreshToken)
+	return true
+}
+
+// LTIDeepLinkRequest is the content an instructor picked for a deep linking request
+type LTIDeepLinkRequest struct {
+	DeepLinkID string `json:"deep_link_id" binding:"required"`
+	CourseID   int64  `json:"course_id" binding:"required,min=1"`
+	MaterialID int64  `json:"material_id" binding:"min=0"`
+}
+
+// ltiDeepLinkResponse is what the front end posts to the platform, the JWT goes in a form field named JWT
+type ltiDeepLinkResponse struct {
+	ReturnURL string `json:"return_url"`
+	JWT       string `json:"jwt"`
+}
+
+// @Summary Answer a deep linking request
+// @Description Signs the course or material an instructor picked into a deep linking response. The front end posts the
+// @Description returned JWT to return_url. The link carries a line item, so the platform creates a gradebook column the
+// @Description marks of the course are posted back to. Only the teacher of the course or an admin can link it, linking
+// @Description allows the platform to launch the course.
+// @Accept json
+// @Produce json
+// @Param request body LTIDeepLinkRequest true "LTI Deep Link Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Router /lti/deeplink [post]
+// LTIDeepLink returns the deep linking response for the picked content
+func (server *Server) LTIDeepLink(ctx *gin.Context) {
+	if !server.requireLTITool(ctx) {
+		return
+	}
+
+	var req LTIDeepLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	course, err := server.store.GetCourses(ctx, req.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// linking a course lets the platform's users launch it, only its teacher or an admin decides that
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" && course.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(errLTINotTeacher))
+		return
+	}
+
+	title := course.Title
+	custom := map[string]string{"course_id": strconv.FormatInt(course.CourseID, 10)}
+	if req.MaterialID > 0 {
+		material, err := server.store.GetMaterialByID(ctx, req.MaterialID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if material.CourseID != course.CourseID {
+			err := errors.New("material does not belong to the course")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		title = material.Title
+		custom["material_id"] = strconv.FormatInt(material.MaterialID, 10)
+	}
+
+	deepLink, err := server.store.ConsumeLTIDeepLink(ctx, db.ConsumeLTIDeepLinkParams{
+		DeepLinkID: req.DeepLinkID,
+		UserID:     authPayload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	platform, err := server.store.GetLTIPlatform(ctx, deepLink.PlatformID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errLTIUnknownPlatform))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.CreateLTIPlatformCourse(ctx, db.CreateLTIPlatformCourseParams{
+		PlatformID: platform.PlatformID,
+		CourseID:   course.CourseID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	item := server.ltiTool.ResourceLinkItem(title, custom, &lti.LineItem{
+		Label:        course.Title,
+		ScoreMaximum: 100,
+		ResourceID:   custom["course_id"],
+	})
+	jwt, err := server.ltiTool.DeepLinkResponse(ltiPlatform(platform), deepLink.DeploymentID,
+		lti.DeepLinkingSettings{ReturnURL: deepLink.ReturnURL, Data: deepLink.Data}, []lti.ContentItem{item})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ltiDeepLinkResponse{ReturnURL: deepLink.ReturnURL, JWT: jwt})
+}
+
+// LTIPlatformRequest defines the request body structure for registering a platform
+type LTIPlatformRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Issuer       string `json:"issuer" binding:"required,url"`
+	ClientID     string `json:"client_id" binding:"required"`
+	DeploymentID string `json:"deployment_id"`
+	AuthLoginURL string `json:"auth_login_url" binding:"required,url"`
+	AuthTokenURL string `json:"auth_token_url" binding:"required,url"`
+	JWKSURL      string `json:"jwks_url" binding:"required,url"`
+}
+
+// @Summary Register an LTI platform
+// @Description Registers a Moodle, Canvas or other LTI 1.3 platform allowed to launch the tool. Leaving out the deployment
+// @Description accepts launches from every deployment of the client.
+// @Accept json
+// @Produce json
+// @Param request body LTIPlatformRequest true "LTI Platform Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /lti/platforms [post]
+// CreateLTIPlatform registers a platform
+func (server *Server) CreateLTIPlatform(ctx *gin.Context) {
+	var req LTIPlatformRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	platform, err := server.store.CreateLTIPlatform(ctx, db.CreateLTIPlatformParams{
+		Name:         req.Name,
+		Issuer:       req.Issuer,
+		ClientID:     req.ClientID,
+		DeploymentID: req.DeploymentID,
+		AuthLoginURL: req.AuthLoginURL,
+		AuthTokenURL: req.AuthTokenURL,
+		JwksURL:      req.JWKSURL,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolations {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, platform)
+}
+
+// @Summary List LTI platforms
+// @Description Lists the registered LTI platforms
+// @Produce json
+// @Success 200
+// @Failure 403
+// @Failure 500
+// @Router /lti/platforms [get]
+// ListLTIPlatforms lists the registered platforms
+func (server *Server) ListLTIPlatforms(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	platforms, err := server.store.ListLTIPlatforms(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, platforms)
+}
+
+// LTIPlatformURIRequest contains the platform a request is about
+type LTIPlatformURIRequest struct {
+	PlatformID int64 `uri:"platform_id" binding:"required,min=1"`
+}
+
+// @Summary Update an LTI platform
+// @Description Updates the registration of an LTI platform
+// @Accept json
+// @Produce json
+// @Param platform_id path int true "Platform ID"
+// @Param request body LTIPlatformRequest true "LTI Platform Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /lti/platforms/{platform_id} [put]
+// UpdateLTIPlatform updates a platform registration
+func (server *Server) UpdateLTIPlatform(ctx *gin.Context) {
+	var uri LTIPlatformURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req LTIPlatformRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	platform, err := server.store.UpdateLTIPlatform(ctx, db.UpdateLTIPlatformParams{
+		PlatformID:   uri.PlatformID,
+		Name:         req.Name,
+		Issuer:       req.Issuer,
+		ClientID:     req.ClientID,
+		DeploymentID: req.DeploymentID,
+		AuthLoginURL: req.AuthLoginURL,
+		AuthTokenURL: req.AuthTokenURL,
+		JwksURL:      req.JWKSURL,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolations {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, platform)
+}
+
+// @Summary Delete an LTI platform
+// @Description Removes a platform registration along with its users' links, resource links and pending launches.
+// @Description Accounts created by launches are kept.
+// @Param platform_id path int true "Platform ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /lti/platforms/{platform_id} [delete]
+// DeleteLTIPlatform deletes a platform registration
+func (server *Server) DeleteLTIPlatform(ctx *gin.Context) {
+	var uri LTIPlatformURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteLTIPlatform(ctx, uri.PlatformID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Platform deleted successfully"})
+}
+
+// LTIPlatformCourseRequest names a course a platform is allowed to link to
+type LTIPlatformCourseRequest struct {
+	PlatformID int64 `uri:"platform_id" binding:"required,min=1"`
+	CourseID   int64 `uri:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Allow an LTI platform to link a course
+// @Description Lets the resource links of a platform launch a course. Answering a deep linking request allows it as well.
+// @Param platform_id path int true "Platform ID"
+// @Param course_id path int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /lti/platforms/{platform_id}/courses/{course_id} [put]
+// AddLTIPlatformCourse allows a platform to link a course
+func (server *Server) AddLTIPlatformCourse(ctx *gin.Context) {
+	var req LTIPlatformCourseRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	err := server.store.CreateLTIPlatformCourse(ctx, db.CreateLTIPlatformCourseParams{
+		PlatformID: req.PlatformID,
+		CourseID:   req.CourseID,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Course linked to the platform successfully"})
+}
+
+// @Summary Stop an LTI platform linking a course
+// @Description Refuses further launches of a course from a platform and removes the resource links to it, marks are no
+// @Description longer posted back to the platform.
+// @Param platform_id path int true "Platform ID"
+// @Param course_id path int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /lti/platforms/{platform_id}/courses/{course_id} [delete]
+// DeleteLTIPlatformCourse stops a platform linking a course
+func (server *Server) DeleteLTIPlatformCourse(ctx *gin.Context) {
+	var req LTIPlatformCourseRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	err := server.store.DeleteLTIPlatformCourse(ctx, db.DeleteLTIPlatformCourseParams{
+		PlatformID: req.PlatformID,
+		CourseID:   req.CourseID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Course unlinked from the platform successfully"})
+}
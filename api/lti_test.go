@@ -0,0 +1,397 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	db "eduApp/db/sqlc"
+	"eduApp/enrollment"
+	"eduApp/lti"
+	"eduApp/token"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ltiStateStore keeps one platform and the launch states in memory
+type ltiStateStore struct {
+	db.Store
+
+	platform db.LtiPlatform
+	states   map[string]db.LtiLaunchState
+	consumed []string
+}
+
+func (store *ltiStateStore) ListLTIPlatformsByIssuer(ctx context.Context, issuer string) ([]db.LtiPlatform, error) {
+	if issuer != store.platform.Issuer {
+		return nil, nil
+	}
+	return []db.LtiPlatform{store.platform}, nil
+}
+
+// GetLTIPlatform reports the platform as removed, launches here stop once they consumed their state
+func (store *ltiStateStore) GetLTIPlatform(ctx context.Context, platformID int64) (db.LtiPlatform, error) {
+	return db.LtiPlatform{}, db.ErrRecordNotFound
+}
+
+func (store *ltiStateStore) DeleteExpiredLTILaunchStates(ctx context.Context) error {
+	return nil
+}
+
+func (store *ltiStateStore) CreateLTILaunchState(ctx context.Context, arg db.CreateLTILaunchStateParams) error {
+	store.states[arg.State] = db.LtiLaunchState{State: arg.State, Nonce: arg.Nonce, PlatformID: arg.PlatformID, ExpiresAt: arg.ExpiresAt}
+	return nil
+}
+
+func (store *ltiStateStore) ConsumeLTILaunchState(ctx context.Context, state string) (db.LtiLaunchState, error) {
+	store.consumed = append(store.consumed, state)
+	launchState, ok := store.states[state]
+	if !ok {
+		return db.LtiLaunchState{}, db.ErrRecordNotFound
+	}
+	delete(store.states, state)
+	return launchState, nil
+}
+
+func newLTITestServer(t *testing.T) (*Server, *ltiStateStore) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool, err := lti.NewTool("https://tool.example.com", string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &ltiStateStore{
+		platform: db.LtiPlatform{
+			PlatformID:   1,
+			Issuer:       "https://platform.example.com",
+			ClientID:     "tool-client",
+			AuthLoginURL: "https://platform.example.com/auth",
+			JwksURL:      "https://platform.example.com/jwks",
+		},
+		states: make(map[string]db.LtiLaunchState),
+	}
+	return &Server{store: store, ltiTool: tool}, store
+}
+
+func TestLTILaunchRequiresStateCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server, store := newLTITestServer(t)
+	router := gin.New()
+	router.GET("/lti/login", server.LTILogin)
+	router.POST("/lti/launch", server.LTILaunch)
+
+	query := url.Values{"iss": {store.platform.Issuer}, "login_hint": {"user-42"}, "client_id": {store.platform.ClientID}}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/lti/login?"+query.Encode(), nil))
+	if recorder.Code != http.StatusFound {
+		t.Fatalf("login status = %d, want %d: %s", recorder.Code, http.StatusFound, recorder.Body)
+	}
+
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("login set %d cookies, want 1", len(cookies))
+	}
+	cookie := cookies[0]
+	if _, ok := store.states[cookie.Value]; !ok || cookie.Name != ltiStateCookiePrefix+cookie.Value {
+		t.Fatalf("cookie %s=%s does not hold the launch state", cookie.Name, cookie.Value)
+	}
+	if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteNoneMode || cookie.Path != "/lti/launch" {
+		t.Errorf("cookie = %+v, want a secure, http only, SameSite=None cookie for the launch path", cookie)
+	}
+	if cookie.MaxAge <= 0 || cookie.MaxAge > int(ltiLaunchStateTTL.Seconds()) {
+		t.Errorf("cookie max age = %d, want at most the state lifetime", cookie.MaxAge)
+	}
+	state := cookie.Value
+
+	launch := func(cookie *http.Cookie) *httptest.ResponseRecorder {
+		form := url.Values{"id_token": {"token"}, "state": {state}}
+		request := httptest.NewRequest(http.MethodPost, "/lti/launch", strings.NewReader(form.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie != nil {
+			request.AddCookie(cookie)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	testCases := []struct {
+		name   string
+		cookie *http.Cookie
+	}{
+		{name: "no cookie"},
+		{name: "cookie of another state", cookie: &http.Cookie{Name: ltiStateCookiePrefix + state, Value: "other-state"}},
+		{name: "cookie named for another state", cookie: &http.Cookie{Name: ltiStateCookiePrefix + "other-state", Value: state}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := launch(tc.cookie)
+			if recorder.Code != http.StatusUnauthorized {
+				t.Fatalf("launch status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+			}
+			var body gin.H
+			json.Unmarshal(recorder.Body.Bytes(), &body)
+			if body["error"] != errLTIStateMismatch.Error() {
+				t.Errorf("launch error = %v, want %q", body["error"], errLTIStateMismatch)
+			}
+			if len(store.consumed) != 0 {
+				t.Errorf("launch consumed states %v without a matching cookie", store.consumed)
+			}
+		})
+	}
+
+	// with the cookie the launch goes on to the state, and the cookie is cleared
+	recorder = launch(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	if len(store.consumed) != 1 || store.consumed[0] != state {
+		t.Fatalf("consumed states = %v, want %s", store.consumed, state)
+	}
+	cleared := recorder.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].Name != cookie.Name || cleared[0].MaxAge >= 0 {
+		t.Errorf("launch cookies = %v, want the state cookie cleared", cleared)
+	}
+}
+
+// ltiLinkStore holds course 3 taught by user 7, a pending deep link of the platform and the users who have accounts
+type ltiLinkStore struct {
+	db.Store
+
+	platform  db.LtiPlatform
+	users     []db.User
+	created   []db.CreateUserParams
+	linked    []db.CreateLTIPlatformCourseParams
+	enrolled  []db.CreateSubscriptionTxParams
+	enrollErr error
+}
+
+func (store *ltiLinkStore) GetCourses(ctx context.Context, courseID int64) (db.Course, error) {
+	if courseID != 3 {
+		return db.Course{}, db.ErrRecordNotFound
+	}
+	return db.Course{CourseID: 3, UserID: 7, Title: "Algebra"}, nil
+}
+
+func (store *ltiLinkStore) ConsumeLTIDeepLink(ctx context.Context, arg db.ConsumeLTIDeepLinkParams) (db.LtiDeepLink, error) {
+	return db.LtiDeepLink{
+		DeepLinkID: arg.DeepLinkID,
+		PlatformID: store.platform.PlatformID,
+		UserID:     arg.UserID,
+		ReturnURL:  "https://platform.example.com/deep-link/return",
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}, nil
+}
+
+func (store *ltiLinkStore) GetLTIPlatform(ctx context.Context, platformID int64) (db.LtiPlatform, error) {
+	return store.platform, nil
+}
+
+func (store *ltiLinkStore) CreateLTIPlatformCourse(ctx context.Context, arg db.CreateLTIPlatformCourseParams) error {
+	store.linked = append(store.linked, arg)
+	return nil
+}
+
+func (store *ltiLinkStore) GetLTIUser(ctx context.Context, arg db.GetLTIUserParams) (db.LtiUser, error) {
+	return db.LtiUser{}, db.ErrRecordNotFound
+}
+
+func (store *ltiLinkStore) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	for _, user := range store.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return db.User{}, db.ErrRecordNotFound
+}
+
+func (store *ltiLinkStore) GetUser(ctx context.Context, userName string) (db.User, error) {
+	for _, user := range store.users {
+		if user.UserName == userName {
+			return user, nil
+		}
+	}
+	return db.User{}, db.ErrRecordNotFound
+}
+
+func (store *ltiLinkStore) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	store.created = append(store.created, arg)
+	return db.User{UserID: 100, UserName: arg.UserName, Email: arg.Email, Role: arg.Role}, nil
+}
+
+func (store *ltiLinkStore) CreateLTIUser(ctx context.Context, arg db.CreateLTIUserParams) (db.LtiUser, error) {
+	return db.LtiUser{PlatformID: arg.PlatformID, Subject: arg.Subject, UserID: arg.UserID}, nil
+}
+
+func (store *ltiLinkStore) CreateSubscriptionTx(ctx context.Context, arg db.CreateSubscriptionTxParams) (db.CreateSubscriptionTxResult, error) {
+	store.enrolled = append(store.enrolled, arg)
+	if store.enrollErr != nil {
+		return db.CreateSubscriptionTxResult{}, store.enrollErr
+	}
+	return db.CreateSubscriptionTxResult{Subscription: db.Subscription{UserID: arg.UserID, CourseID: arg.CourseID, Active: true}}, nil
+}
+
+func newLTILinkServer(t *testing.T) (*Server, *ltiLinkStore) {
+	t.Helper()
+	server, stateStore := newLTITestServer(t)
+	store := &ltiLinkStore{platform: stateStore.platform}
+	server.store = store
+	return server, store
+}
+
+func TestLTIDeepLinkRequiresTeacher(t *testing.T) {
+	testCases := []struct {
+		name    string
+		payload *token.Payload
+		status  int
+	}{
+		{name: "Teacher", payload: &token.Payload{UserID: 7, Role: "student"}, status: http.StatusOK},
+		{name: "Admin", payload: &token.Payload{UserID: 1, Role: "admin"}, status: http.StatusOK},
+		{name: "OtherInstructor", payload: &token.Payload{UserID: 8, Role: "student"}, status: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			server, store := newLTILinkServer(t)
+			router := gin.New()
+			router.POST("/lti/deeplink", func(ctx *gin.Context) {
+				ctx.Set(authorizationPayloadKey, tc.payload)
+			}, server.LTIDeepLink)
+
+			body := strings.NewReader(`{"deep_link_id": "link-1", "course_id": 3}`)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/lti/deeplink", body))
+			if recorder.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.status, recorder.Body)
+			}
+
+			// signing the link allows the platform to launch the course
+			wantLinked := 0
+			if tc.status == http.StatusOK {
+				wantLinked = 1
+			}
+			if len(store.linked) != wantLinked {
+				t.Fatalf("platform courses = %v, want %d", store.linked, wantLinked)
+			}
+			if wantLinked == 1 && (store.linked[0].PlatformID != store.platform.PlatformID || store.linked[0].CourseID != 3) {
+				t.Errorf("platform course = %+v, want course 3 of the platform", store.linked[0])
+			}
+		})
+	}
+}
+
+func TestLTIUser(t *testing.T) {
+	testCases := []struct {
+		name     string
+		launch   lti.Launch
+		users    []db.User
+		err      error
+		userName string
+	}{
+		{
+			name:     "NewUser",
+			launch:   lti.Launch{Subject: "sub-1", Email: "jane@school.example", GivenName: "Jane", FamilyName: "Doe"},
+			userName: enrollment.UserNameFromEmail("jane@school.example"),
+		},
+		{
+			name:   "EmailTaken",
+			launch: lti.Launch{Subject: "sub-1", Email: "jane@school.example"},
+			users:  []db.User{{UserID: 5, UserName: "jane", Email: "jane@school.example"}},
+			err:    errLTIEmailTaken,
+		},
+		{
+			name:   "UserNameTaken",
+			launch: lti.Launch{Subject: "sub-1", Email: "jane@school.example"},
+			users:  []db.User{{UserID: 5, UserName: enrollment.UserNameFromEmail("jane@school.example"), Email: "jane@elsewhere.example"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			server, store := newLTILinkServer(t)
+			store.users = tc.users
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+			user, err := server.ltiUser(ctx, store.platform.PlatformID, tc.launch)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("ltiUser error = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				if len(store.created) != 0 {
+					t.Errorf("created users %v for a refused launch", store.created)
+				}
+				return
+			}
+
+			if len(store.created) != 1 || store.created[0].Role != "student" {
+				t.Fatalf("created users = %+v, want one student", store.created)
+			}
+			if tc.userName != "" && user.UserName != tc.userName {
+				t.Errorf("user name = %q, want %q", user.UserName, tc.userName)
+			}
+			for _, existing := range tc.users {
+				if user.UserName == existing.UserName {
+					t.Errorf("user name %q is taken", user.UserName)
+				}
+			}
+		})
+	}
+}
+
+func TestEnrollLTIUser(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		ok       bool
+		status   int
+		fragment string
+	}{
+		{name: "Enrolled", ok: true},
+		{name: "AlreadyEnrolled", err: &pgconn.PgError{Code: db.UniqueViolations}, ok: true},
+		{name: "PaymentRequired", err: db.ErrPaymentRequired, ok: true, fragment: db.ErrPaymentRequired.Error()},
+		{name: "InviteOnly", err: db.ErrEnrollmentInviteOnly, ok: true, fragment: db.ErrEnrollmentInviteOnly.Error()},
+		{name: "MissingCourse", err: db.ErrRecordNotFound, status: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			server, store := newLTILinkServer(t)
+			store.enrollErr = tc.err
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+
+			fragment := url.Values{}
+			if ok := server.enrollLTIUser(ctx, 100, 3, fragment); ok != tc.ok {
+				t.Fatalf("enrollLTIUser = %v, want %v", ok, tc.ok)
+			}
+			if !tc.ok && recorder.Code != tc.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tc.status)
+			}
+			if got := fragment.Get("enrollment_error"); got != tc.fragment {
+				t.Errorf("enrollment_error = %q, want %q", got, tc.fragment)
+			}
+			// platform users ask to join like any student, the course's mode, capacity and price apply
+			if len(store.enrolled) != 1 || store.enrolled[0].Invited {
+				t.Errorf("enrollments = %+v, want one that is not invited", store.enrolled)
+			}
+		})
+	}
+}
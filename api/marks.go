@@ -55,6 +55,8 @@ func (server *Server) CreateMark(ctx *gin.Context) {
 		return
 	}
 
+	server.distributeLTIScore(ctx, mark.CourseID, mark.UserID)
+
 	ctx.JSON(http.StatusOK, mark)
 }
 
@@ -234,5 +236,7 @@ func (server *Server) UpdateMark(ctx *gin.Context) {
 		return
 	}
 
+	server.distributeLTIScore(ctx, mark.CourseID, mark.UserID)
+
 	ctx.JSON(http.StatusOK, mark)
 }
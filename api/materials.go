@@ -2,6 +2,7 @@ package api
 
 import (
 	db "eduApp/db/sqlc"
+	"eduApp/markdown"
 	"eduApp/token"
 	"eduApp/util"
 	"eduApp/worker"
@@ -10,7 +11,9 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -86,12 +89,97 @@ func (server *Server) uploadSingleMaterial(file multipart.File, header *multipar
 	return MaterialFile, nil
 }
 
+// material types supported by the material_type column
+const (
+	materialTypeFile     = "file"
+	materialTypeMarkdown = "markdown"
+	materialTypeLink     = "link"
+	materialTypeVideo    = "video"
+	materialTypeSCORM    = "scorm"
+)
+
+// videoHosts lists the hosts whose video URLs can be embedded in a lesson
+var videoHosts = map[string]bool{
+	"youtube.com":      true,
+	"www.youtube.com":  true,
+	"youtu.be":         true,
+	"vimeo.com":        true,
+	"player.vimeo.com": true,
+}
+
 // CreateMaterialRequest defines the request body structure for creating a material
 type CreateMaterialRequest struct {
 	CourseID     int64  `form:"course_id"`
 	Title        string `form:"title"`
 	MaterialFile string `json:"material_file"`
 	OrderNumber  int64  `form:"order_number"`
+	MaterialType string `form:"material_type"`
+	Content      string `form:"content"`
+	ExternalURL  string `form:"external_url"`
+	// DurationSeconds is the length of a video, watch time is measured against it
+	DurationSeconds float64 `form:"duration_seconds" binding:"min=0"`
+}
+
+// validate checks the fields required by the material type, hasFile tells whether a file was uploaded.
+// Requests without a material type are treated as file materials.
+func (req *CreateMaterialRequest) validate(hasFile bool) error {
+	if req.MaterialType == "" {
+		req.MaterialType = materialTypeFile
+	}
+
+	switch req.MaterialType {
+	case materialTypeFile:
+		if !hasFile {
+			return errors.New("material_file is required for file materials")
+		}
+	case materialTypeSCORM:
+		if !hasFile {
+			return errors.New("material_file is required for SCORM materials")
+		}
+	case materialTypeMarkdown:
+		if strings.TrimSpace(req.Content) == "" {
+			return errors.New("content is required for markdown materials")
+		}
+	case materialTypeLink:
+		if _, err := parseExternalURL(req.ExternalURL, false); err != nil {
+			return err
+		}
+	case materialTypeVideo:
+		if err := validateVideoURL(req.ExternalURL); err != nil {
+			return err
+		}
+		if req.DurationSeconds <= 0 {
+			return errors.New("duration_seconds is required for video materials")
+		}
+	default:
+		return fmt.Errorf("unsupported material type: %s", req.MaterialType)
+	}
+
+	return nil
+}
+
+// parseExternalURL accepts absolute http and https URLs, or only https when secure is set
+func parseExternalURL(rawURL string, secure bool) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Host == "" {
+		return nil, errors.New("external_url must be an absolute URL")
+	}
+	if parsed.Scheme != "https" && (secure || parsed.Scheme != "http") {
+		return nil, fmt.Errorf("unsupported URL scheme: %s", parsed.Scheme)
+	}
+	return parsed, nil
+}
+
+// validateVideoURL allows https links to known video hosts or directly to an mp4 file
+func validateVideoURL(rawURL string) error {
+	parsed, err := parseExternalURL(rawURL, true)
+	if err != nil {
+		return err
+	}
+	if videoHosts[strings.ToLower(parsed.Hostname())] || strings.ToLower(path.Ext(parsed.Path)) == ".mp4" {
+		return nil
+	}
+	return fmt.Errorf("unsupported video host: %s", parsed.Hostname())
 }
 
 // @Summary Create a new material
@@ -121,26 +209,54 @@ func (server *Server) CreateMaterial(ctx *gin.Context) {
 		return
 	}
 
-	var materialFile string
-	file, header, err := ctx.Request.FormFile("material_file")
-	if err == nil {
+	file, header, fileErr := ctx.Request.FormFile("material_file")
+	if err := req.validate(fileErr == nil); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var materialFile, contentHTML string
+	var scormPackage *db.CreateSCORMPackageParams
+	switch req.MaterialType {
+	case materialTypeFile:
+		var err error
 		materialFile, err = server.uploadSingleMaterial(file, header)
 		println("material_file", materialFile)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
+	case materialTypeMarkdown:
+		contentHTML = markdown.Render(req.Content)
+	case materialTypeSCORM:
+		pkg, err := extractSCORMPackage(file, header)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		scormPackage = &pkg
 	}
 
 	req.MaterialFile = materialFile
 
+	var durationSeconds float64
+	if req.MaterialType == materialTypeVideo {
+		durationSeconds = req.DurationSeconds
+	}
+
 	arg := db.CreateMaterialTxParams{
 		CreateMaterialParams: db.CreateMaterialParams{
-			CourseID:     req.CourseID,
-			Title:        req.Title,
-			MaterialFile: materialFile,
-			OrderNumber:  req.OrderNumber,
+			CourseID:        req.CourseID,
+			Title:           req.Title,
+			MaterialFile:    materialFile,
+			OrderNumber:     req.OrderNumber,
+			MaterialType:    req.MaterialType,
+			Content:         req.Content,
+			ContentHtml:     contentHTML,
+			ExternalUrl:     strings.TrimSpace(req.ExternalURL),
+			DurationSeconds: durationSeconds,
 		},
+		SCORMPackage: scormPackage,
 		AfterCreate: func(material db.Material) error {
 			// Use Redis for task distribution
 			taskPayload := &worker.PayloadCreateMaterials{
@@ -156,16 +272,25 @@ func (server *Server) CreateMaterial(ctx *gin.Context) {
 				asynq.ProcessIn(10 * time.Second),
 				asynq.Queue(worker.QueueCritical),
 			}
-			return server.taskDistributor.DistributeTaskCreateMaterials(ctx, taskPayload, opts...)
+			if err := server.taskDistributor.DistributeTaskCreateMaterials(ctx, taskPayload, opts...); err != nil {
+				return err
+			}
+			// a new lesson lowers the progress of everyone taking the course
+			return server.distributeCourseProgress(ctx, material.CourseID)
 		},
 	}
 
 	txResult, err := server.store.CreateMaterialTx(ctx, arg)
 	if err != nil {
+		if scormPackage != nil {
+			removeSCORMPackage(scormPackage.PackageDir)
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create material, Please try again."})
 		return
 	}
 
+	server.distributeMaterialNotification(ctx, txResult.Material)
+
 	ctx.JSON(http.StatusOK, txResult)
 }
 
@@ -196,17 +321,7 @@ func (server *Server) GetMaterial(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	subscription, err := server.store.GetSubscriptionByUser(ctx, db.GetSubscriptionByUserParams{
-		UserID:   authPayload.UserID,
-		CourseID: req.CourseID,
-	})
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if !subscription.Active {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user doesn't have an active subscription"})
+	if !server.requireCourseAccess(ctx, authPayload, req.CourseID) {
 		return
 	}
 
@@ -254,6 +369,10 @@ type UpdateMaterialRequest struct {
 	Title        string `form:"title"`
 	MaterialFile string `json:"material_file"`
 	CourseID     int64  `form:"course_id"`
+	Content      string `form:"content"`
+	ExternalURL  string `form:"external_url"`
+	// DurationSeconds sets the length of a video, it is left as it is when zero
+	DurationSeconds float64 `form:"duration_seconds" binding:"min=0"`
 }
 
 // @Summary Update a material
@@ -284,21 +403,7 @@ func (server *Server) UpdateMaterial(ctx *gin.Context) {
 		return
 	}
 
-	// Handle image upload (if included in the request)
-	var materialF string
-	file, header, err := ctx.Request.FormFile("material_file")
-	if err == nil {
-		materialF, err = server.uploadSingleFile(file, header)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, errorResponse(err))
-			return
-		}
-	}
-
-	// Update request struct with image filename (if uploaded)
-	req.MaterialFile = materialF
-
-	//previous details to remove file
+	//previous details to check the type and remove file
 	getMaterial, err := server.store.GetMaterial(ctx, db.GetMaterialParams{
 		MaterialID: req.MaterialID,
 		CourseID:   req.CourseID,
@@ -308,16 +413,76 @@ func (server *Server) UpdateMaterial(ctx *gin.Context) {
 		return
 	}
 
-	//assign value to the file path
-	value := getMaterial.MaterialFile
-	//remove exiting fil
-	util.DeleteFileByURL(value)
-
 	//pass new args
 	arg := db.UpdateMaterialParams{
-		MaterialID:   req.MaterialID,
-		Title:        pgtype.Text{String: req.Title, Valid: true},
-		MaterialFile: pgtype.Text{String: materialF, Valid: true},
+		MaterialID: req.MaterialID,
+		Title:      pgtype.Text{String: req.Title, Valid: req.Title != ""},
+	}
+
+	switch getMaterial.MaterialType {
+	case materialTypeFile:
+		// Handle file upload (if included in the request)
+		file, header, err := ctx.Request.FormFile("material_file")
+		if err == nil {
+			materialF, err := server.uploadSingleMaterial(file, header)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			req.MaterialFile = materialF
+			arg.MaterialFile = pgtype.Text{String: materialF, Valid: true}
+		}
+	case materialTypeMarkdown:
+		if strings.TrimSpace(req.Content) != "" {
+			arg.Content = pgtype.Text{String: req.Content, Valid: true}
+			arg.ContentHtml = pgtype.Text{String: markdown.Render(req.Content), Valid: true}
+		}
+	case materialTypeLink, materialTypeVideo:
+		if req.ExternalURL != "" {
+			validate := func(rawURL string) error {
+				_, err := parseExternalURL(rawURL, false)
+				return err
+			}
+			if getMaterial.MaterialType == materialTypeVideo {
+				validate = validateVideoURL
+			}
+			if err := validate(req.ExternalURL); err != nil {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			arg.ExternalUrl = pgtype.Text{String: strings.TrimSpace(req.ExternalURL), Valid: true}
+		}
+		if getMaterial.MaterialType == materialTypeVideo && req.DurationSeconds > 0 {
+			arg.DurationSeconds = pgtype.Float8{Float64: req.DurationSeconds, Valid: true}
+		}
+	case materialTypeSCORM:
+		// a new upload replaces the package, the data students stored for the old one is kept
+		file, header, err := ctx.Request.FormFile("material_file")
+		if err == nil {
+			previous, err := server.store.GetSCORMPackage(ctx, getMaterial.MaterialID)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			pkg, err := extractSCORMPackage(file, header)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			_, err = server.store.UpdateSCORMPackage(ctx, db.UpdateSCORMPackageParams{
+				MaterialID: getMaterial.MaterialID,
+				Version:    pkg.Version,
+				Title:      pkg.Title,
+				PackageDir: pkg.PackageDir,
+				LaunchPath: pkg.LaunchPath,
+			})
+			if err != nil {
+				removeSCORMPackage(pkg.PackageDir)
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			removeSCORMPackage(previous.PackageDir)
+		}
 	}
 
 	material, err := server.store.UpdateMaterial(ctx, db.UpdateMaterialParams(arg))
@@ -327,6 +492,11 @@ func (server *Server) UpdateMaterial(ctx *gin.Context) {
 		return
 	}
 
+	//remove the replaced file
+	if arg.MaterialFile.Valid && getMaterial.MaterialFile != "" {
+		util.DeleteFileByURL(getMaterial.MaterialFile)
+	}
+
 	ctx.JSON(http.StatusOK, material)
 
 }
@@ -363,28 +533,41 @@ func (server *Server) DeleteMaterial(ctx *gin.Context) {
 		return
 	}
 
-	err := server.store.DeleteMaterial(ctx, req.MaterialID)
-
+	txResult, err := server.store.DeleteMaterialTx(ctx, db.DeleteMaterialTxParams{
+		MaterialID: req.MaterialID,
+		AfterDelete: func(material db.Material) error {
+			return server.distributeCourseProgress(ctx, material.CourseID)
+		},
+	})
 	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	if txResult.SCORMPackage != nil {
+		removeSCORMPackage(txResult.SCORMPackage.PackageDir)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Material deleted successfully"})
 }
 
 // ListMaterialsByCourse contains the input parameters for list course details
 type ListMaterialsByCourse struct {
-	CourseID int64 `form:"course_id"`
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
 }
 
 // @Summary List Material
-// @Description List Material of certain course
+// @Description List Material of certain course, only students whose access to the course has not ended can see it
 // @Accept json
 // @Produce json
 // @Param request body ListMaterialsByCourse true "List Material of certain course"
 // @Success 200
 // @Failure 400
+// @Failure 401
 // @Failure 404
 // @Failure 500
 // @Router /material/list [Get]
@@ -393,7 +576,13 @@ func (server *Server) ListMaterial(ctx *gin.Context) {
 	var req ListMaterialsByCourse
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the list carries the content and files of every material
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.requireCourseAccess(ctx, authPayload, req.CourseID) {
 		return
 	}
 
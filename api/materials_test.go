@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"eduApp/worker"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// materialStore holds the materials of course 3 and the subscriptions to it by user id
+type materialStore struct {
+	db.Store
+
+	subscriptions map[int64]db.Subscription
+	// deleted lists the materials whose deletion committed
+	deleted []int64
+}
+
+func (store *materialStore) GetCourseSubscription(ctx context.Context, arg db.GetCourseSubscriptionParams) (db.Subscription, error) {
+	subscription, ok := store.subscriptions[arg.UserID]
+	if !ok || arg.CourseID != 3 {
+		return db.Subscription{}, db.ErrRecordNotFound
+	}
+	return subscription, nil
+}
+
+func (store *materialStore) ListMaterial(ctx context.Context, courseID int64) ([]db.ListMaterialRow, error) {
+	return []db.ListMaterialRow{{CourseID: courseID, Title: "Week 1", MaterialFile: "http://localhost:8080/uploads/answers.pdf"}}, nil
+}
+
+// DeleteMaterialTx commits the deletion only when AfterDelete succeeds, like the transaction does
+func (store *materialStore) DeleteMaterialTx(ctx context.Context, arg db.DeleteMaterialTxParams) (db.DeleteMaterialTxResult, error) {
+	material := db.Material{MaterialID: arg.MaterialID, CourseID: 3, MaterialType: "text"}
+	if err := arg.AfterDelete(material); err != nil {
+		return db.DeleteMaterialTxResult{}, err
+	}
+	store.deleted = append(store.deleted, arg.MaterialID)
+	return db.DeleteMaterialTxResult{Material: material}, nil
+}
+
+// progressDistributor records the courses whose progress recomputation is queued, or fails to queue it
+type progressDistributor struct {
+	worker.TaskDistributor
+
+	err     error
+	courses []int64
+}
+
+func (distributor *progressDistributor) DistributeTaskUpdateCourseprogress(ctx context.Context, payload *worker.PayloadUpdateCourseprogress, opts ...asynq.Option) error {
+	if distributor.err != nil {
+		return distributor.err
+	}
+	distributor.courses = append(distributor.courses, payload.CourseID)
+	return nil
+}
+
+func TestDeleteMaterialQueuesProgress(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		status  int
+		deleted bool
+	}{
+		{name: "Queued", status: http.StatusOK, deleted: true},
+		{name: "QueueFails", err: errors.New("redis is down"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			store := &materialStore{}
+			distributor := &progressDistributor{err: tc.err}
+			server := &Server{store: store, taskDistributor: distributor}
+			router := gin.New()
+			router.DELETE("/material/delete", func(ctx *gin.Context) {
+				ctx.Set(authorizationPayloadKey, &token.Payload{UserID: 9, Role: "admin"})
+			}, server.DeleteMaterial)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/material/delete?material_id=5", nil))
+			if recorder.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.status, recorder.Body)
+			}
+			if deleted := len(store.deleted) == 1; deleted != tc.deleted {
+				t.Errorf("deleted = %v, want %v", deleted, tc.deleted)
+			}
+			if tc.deleted && (len(distributor.courses) != 1 || distributor.courses[0] != 3) {
+				t.Errorf("queued recomputations = %v, want course 3", distributor.courses)
+			}
+		})
+	}
+}
+
+func TestListMaterialRequiresCourseAccess(t *testing.T) {
+	store := &materialStore{subscriptions: map[int64]db.Subscription{
+		1: {UserID: 1, CourseID: 3, Active: true},
+		2: {UserID: 2, CourseID: 3, Active: true, ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}},
+		3: {UserID: 3, CourseID: 3, Active: false},
+	}}
+
+	testCases := []struct {
+		name    string
+		payload *token.Payload
+		query   string
+		status  int
+	}{
+		{name: "Subscribed", payload: &token.Payload{UserID: 1, Role: "student"}, query: "course_id=3", status: http.StatusOK},
+		{name: "Admin", payload: &token.Payload{UserID: 9, Role: "admin"}, query: "course_id=3", status: http.StatusOK},
+		{name: "Expired", payload: &token.Payload{UserID: 2, Role: "student"}, query: "course_id=3", status: http.StatusUnauthorized},
+		{name: "Inactive", payload: &token.Payload{UserID: 3, Role: "student"}, query: "course_id=3", status: http.StatusUnauthorized},
+		{name: "NotSubscribed", payload: &token.Payload{UserID: 4, Role: "student"}, query: "course_id=3", status: http.StatusUnauthorized},
+		{name: "MissingCourse", payload: &token.Payload{UserID: 1, Role: "student"}, query: "", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			server := &Server{store: store}
+			router := gin.New()
+			router.GET("/material/list", func(ctx *gin.Context) {
+				ctx.Set(authorizationPayloadKey, tc.payload)
+			}, server.ListMaterial)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/material/list?"+tc.query, nil))
+			if recorder.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.status, recorder.Body)
+			}
+			if tc.status != http.StatusOK && strings.Contains(recorder.Body.String(), "answers.pdf") {
+				t.Errorf("response reveals the material files: %s", recorder.Body)
+			}
+		})
+	}
+}
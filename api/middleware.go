@@ -1,10 +1,15 @@
 package api
 
 import (
+	"crypto/subtle"
+	"eduApp/realtime"
 	"eduApp/token"
+	"eduApp/util"
+	"eduApp/xapi"
 	"errors"
 	"fmt"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -60,3 +65,78 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// xapiMiddleware guards the built-in LRS. Clients authenticate with the XAPI_KEY and XAPI_SECRET of the app
+// over basic auth and send the xAPI version they speak with every request.
+func xapiMiddleware(config util.Config) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header(xapi.VersionHeader, xapi.Version)
+
+		// an empty secret would let anyone who knows the key in
+		if config.XAPIKey == "" || config.XAPISecret == "" {
+			err := errors.New("the learning record store is not configured")
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse(err))
+			return
+		}
+
+		key, secret, ok := ctx.Request.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(key), []byte(config.XAPIKey)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(secret), []byte(config.XAPISecret)) != 1 {
+			ctx.Header("WWW-Authenticate", `Basic realm="xapi"`)
+			err := errors.New("invalid LRS credentials")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		if !strings.HasPrefix(ctx.GetHeader(xapi.VersionHeader), "1.0") {
+			err := fmt.Errorf("the %s header must name version 1.0.x", xapi.VersionHeader)
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// scormContentMiddleware keeps the SCORM packages and the app apart. Package files and the adapter are only served
+// on the content host, which serves nothing else, so a package never runs on the origin of the API.
+func scormContentMiddleware(contentHost string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		p := path.Clean("/" + ctx.Request.URL.Path)
+		content := strings.HasPrefix(p, scormStaticPrefix) || p == scormAdapterPath
+		onContentHost := contentHost != "" && strings.EqualFold(ctx.Request.Host, contentHost)
+		if content != onContentHost {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// eventStreamAuthMiddleware authenticates an event stream. A browser EventSource cannot set headers, so instead of the
+// authorization header it may pass a stream ticket from POST /events/ticket in the ticket query parameter.
+// A ticket opens a single stream shortly after it was issued, the access token itself never appears in a URL.
+func eventStreamAuthMiddleware(tokenMaker token.Maker, tickets realtime.Tickets) gin.HandlerFunc {
+	authenticate := authMiddleware(tokenMaker)
+	return func(ctx *gin.Context) {
+		ticket := ctx.Query("ticket")
+		if ticket == "" || ctx.GetHeader(authorizationHeaderKey) != "" {
+			authenticate(ctx)
+			return
+		}
+
+		payload, err := tickets.RedeemTicket(ctx, ticket)
+		if err != nil {
+			if errors.Is(err, realtime.ErrInvalidTicket) || errors.Is(err, token.ErrExpiredToken) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"eduApp/util"
+	"eduApp/xapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestXAPIMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	configured := util.Config{XAPIKey: "lrs-key", XAPISecret: "lrs-secret"}
+
+	testCases := []struct {
+		name       string
+		config     util.Config
+		key        string
+		secret     string
+		noAuth     bool
+		version    string
+		wantStatus int
+	}{
+		{"accepted", configured, "lrs-key", "lrs-secret", false, "1.0.3", http.StatusOK},
+		{"older 1.0 version", configured, "lrs-key", "lrs-secret", false, "1.0.0", http.StatusOK},
+		{"not configured", util.Config{}, "", "", false, "1.0.3", http.StatusServiceUnavailable},
+		{"key without secret", util.Config{XAPIKey: "lrs-key"}, "lrs-key", "", false, "1.0.3", http.StatusServiceUnavailable},
+		{"secret without key", util.Config{XAPISecret: "lrs-secret"}, "", "lrs-secret", false, "1.0.3", http.StatusServiceUnavailable},
+		{"no credentials", configured, "", "", true, "1.0.3", http.StatusUnauthorized},
+		{"wrong key", configured, "other", "lrs-secret", false, "1.0.3", http.StatusUnauthorized},
+		{"wrong secret", configured, "lrs-key", "other", false, "1.0.3", http.StatusUnauthorized},
+		{"empty secret", configured, "lrs-key", "", false, "1.0.3", http.StatusUnauthorized},
+		{"missing version", configured, "lrs-key", "lrs-secret", false, "", http.StatusBadRequest},
+		{"unsupported version", configured, "lrs-key", "lrs-secret", false, "0.95", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/xapi/statements", xapiMiddleware(tc.config), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/xapi/statements", nil)
+			if !tc.noAuth {
+				request.SetBasicAuth(tc.key, tc.secret)
+			}
+			if tc.version != "" {
+				request.Header.Set(xapi.VersionHeader, tc.version)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if got := recorder.Header().Get(xapi.VersionHeader); got != xapi.Version {
+				t.Errorf("%s = %q, want %q", xapi.VersionHeader, got, xapi.Version)
+			}
+			if tc.wantStatus == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header is missing")
+			}
+		})
+	}
+}
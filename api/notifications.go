@@ -0,0 +1,327 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"eduApp/realtime"
+	"eduApp/token"
+	"eduApp/worker"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// errUnknownNotificationType is returned for preferences of a type that does not exist
+var errUnknownNotificationType = fmt.Errorf("type must be one of %s", strings.Join(notification.Types, ", "))
+
+// distributeNotification queues a notification, what triggered it stands even if the task cannot be queued
+func (server *Server) distributeNotification(ctx *gin.Context, payload *worker.PayloadSendNotification) {
+	err := server.taskDistributor.DistributeTaskSendNotification(ctx, payload, asynq.MaxRetry(5), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Error().Err(err).Str("notification_type", payload.Type).Int64("course_id", payload.CourseID).
+			Msg("failed to enqueue notification")
+	}
+}
+
+// distributeGradeNotifications tells students their submissions for an assignment were graded,
+// in their open tabs and as a notification. Nothing is sent while the grades are not published, publishing them notifies every graded student.
+func (server *Server) distributeGradeNotifications(ctx *gin.Context, assignment db.Assignment, userIDs []int64) {
+	if !assignment.GradesPublishedAt.Valid || len(userIDs) == 0 {
+		return
+	}
+	for _, userID := range userIDs {
+		server.publishEvent(ctx, userID, realtime.EventSubmissionGraded, realtime.SubmissionGraded{
+			CourseID:     assignment.CourseID,
+			AssignmentID: assignment.AssignmentID,
+		})
+	}
+	server.distributeNotification(ctx, &worker.PayloadSendNotification{
+		UserIDs:  userIDs,
+		CourseID: assignment.CourseID,
+		Type:     notification.TypeGrade,
+		Title:    fmt.Sprintf("%s was graded", assignment.Title),
+		Message:  fmt.Sprintf("Your submission for %s was graded, the grade and feedback are ready to view.", assignment.Title),
+		Template: mail.TemplateGrade,
+		Data:     map[string]string{"AssignmentTitle": assignment.Title},
+	})
+}
+
+// distributeSubmissionGraded tells a student their submission was graded
+func (server *Server) distributeSubmissionGraded(ctx *gin.Context, submission db.Submission) {
+	assignment, err := server.store.GetAssignment(ctx, submission.AssignmentID)
+	if err != nil {
+		log.Error().Err(err).Int64("submission_id", submission.SubmissionID).Msg("failed to get assignment for grade notification")
+		return
+	}
+	server.distributeGradeNotifications(ctx, assignment, []int64{submission.UserID})
+}
+
+// distributeMaterialNotification tells the students of a course a lesson was added
+func (server *Server) distributeMaterialNotification(ctx *gin.Context, material db.Material) {
+	course, err := server.store.GetCourses(ctx, material.CourseID)
+	if err != nil {
+		log.Error().Err(err).Int64("material_id", material.MaterialID).Msg("failed to get course for material notification")
+		return
+	}
+	server.distributeNotification(ctx, &worker.PayloadSendNotification{
+		ToCourse: true,
+		CourseID: course.CourseID,
+		Type:     notification.TypeMaterial,
+		Title:    fmt.Sprintf("New lesson in %s", course.Title),
+		Message:  fmt.Sprintf("%s was added to %s.", material.Title, course.Title),
+	})
+}
+
+// ListNotificationsRequest contains the input parameters for listing the notifications of the signed in user
+type ListNotificationsRequest struct {
+	UnreadOnly bool  `form:"unread_only"`
+	PageID     int32 `form:"page_id" binding:"required,min=1"`
+	PageSize   int32 `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// @Summary List notifications
+// @Description List the in-app notifications of the signed in user, newest first
+// @ID list-notifications
+// @Produce json
+// @Param unread_only query bool false "Only unread notifications"
+// @Param page_id query int true "Page ID"
+// @Param page_size query int true "Page size"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /notifications [get]
+func (server *Server) ListNotifications(ctx *gin.Context) {
+	var req ListNotificationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	notifications, err := server.store.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:     authPayload.UserID,
+		UnreadOnly: req.UnreadOnly,
+		Limit:      req.PageSize,
+		Offset:     (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// @Summary Count unread notifications
+// @Description Count the unread in-app notifications of the signed in user
+// @ID count-unread-notifications
+// @Produce json
+// @Success 200
+// @Failure 500
+// @Router /notifications/unread_count [get]
+func (server *Server) CountUnreadNotifications(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	count, err := server.store.CountUnreadNotifications(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkNotificationReadRequest contains the notification to mark read
+type MarkNotificationReadRequest struct {
+	NotificationID int64 `uri:"notification_id" binding:"required,min=1"`
+}
+
+// @Summary Mark a notification read
+// @Description Mark a notification of the signed in user read, a notification read before keeps its read time
+// @ID mark-notification-read
+// @Produce json
+// @Param notification_id path int true "Notification ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /notifications/{notification_id}/read [put]
+func (server *Server) MarkNotificationRead(ctx *gin.Context) {
+	var req MarkNotificationReadRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	read, err := server.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		NotificationID: req.NotificationID,
+		UserID:         authPayload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, read)
+}
+
+// @Summary Mark all notifications read
+// @Description Mark every unread notification of the signed in user read
+// @ID mark-all-notifications-read
+// @Produce json
+// @Success 200
+// @Failure 500
+// @Router /notifications/read_all [put]
+func (server *Server) MarkAllNotificationsRead(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	marked, err := server.store.MarkAllNotificationsRead(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// @Summary Get notification preferences
+// @Description Get how the signed in user receives every type of notification, types never set are shown in the app and emailed
+// @ID get-notification-preferences
+// @Produce json
+// @Success 200
+// @Failure 500
+// @Router /notifications/preferences [get]
+func (server *Server) GetNotificationPreferences(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	stored, err := server.store.ListNotificationPreferences(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	preferences := make([]notification.Preference, 0, len(stored))
+	for _, preference := range stored {
+		preferences = append(preferences, notification.Preference{
+			Type:  preference.Type,
+			InApp: preference.InApp,
+			Email: preference.Email,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, notification.Preferences(preferences))
+}
+
+// UpdateNotificationPreferenceRequest defines the request body structure for setting how a type of notification is received
+type UpdateNotificationPreferenceRequest struct {
+	Type  string `json:"type" binding:"required"`
+	InApp bool   `json:"in_app"`
+	Email bool   `json:"email"`
+}
+
+// @Summary Update a notification preference
+// @Description Set whether the signed in user sees a type of notification in the app and whether it is emailed to them
+// @ID update-notification-preference
+// @Accept json
+// @Produce json
+// @Param request body UpdateNotificationPreferenceRequest true "Update Notification Preference Request"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /notifications/preferences [put]
+func (server *Server) UpdateNotificationPreference(ctx *gin.Context) {
+	var req UpdateNotificationPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !notification.ValidType(req.Type) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errUnknownNotificationType))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	preference, err := server.store.UpsertNotificationPreference(ctx, db.UpsertNotificationPreferenceParams{
+		UserID: authPayload.UserID,
+		Type:   req.Type,
+		InApp:  req.InApp,
+		Email:  req.Email,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notification.Preference{
+		Type:  preference.Type,
+		InApp: preference.InApp,
+		Email: preference.Email,
+	})
+}
+
+// CreateAnnouncementRequest defines the request body structure for announcing something to the students of a course
+type CreateAnnouncementRequest struct {
+	CourseID int64  `json:"course_id" binding:"required,min=1"`
+	Title    string `json:"title" binding:"required,max=200"`
+	Message  string `json:"message" binding:"required,max=5000"`
+}
+
+// @Summary Create an announcement
+// @Description Notify every enrolled student of a course, as their announcement preferences allow
+// @ID create-announcement
+// @Accept json
+// @Produce json
+// @Param request body CreateAnnouncementRequest true "Create Announcement Request"
+// @Success 202
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /announcements [post]
+func (server *Server) CreateAnnouncement(ctx *gin.Context) {
+	var req CreateAnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	course, err := server.store.GetCourses(ctx, req.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the announcement has to reach the students, unlike the notifications queued as a side effect
+	err = server.taskDistributor.DistributeTaskSendNotification(ctx, &worker.PayloadSendNotification{
+		ToCourse: true,
+		CourseID: course.CourseID,
+		Type:     notification.TypeAnnouncement,
+		Title:    fmt.Sprintf("%s: %s", course.Title, strings.TrimSpace(req.Title)),
+		Message:  strings.TrimSpace(req.Message),
+	}, asynq.MaxRetry(5), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Announcement queued"})
+}
@@ -0,0 +1,607 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/payments"
+	"eduApp/token"
+	"eduApp/worker"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// maxWebhookSize bounds the body of a payment webhook request
+const maxWebhookSize = 1 << 20
+
+// paymentRedirectURL is where the checkout sends the student back to, the order id lets the front end show its status
+func (server *Server) paymentRedirectURL(base string, orderID int64) string {
+	if base == "" {
+		base = server.config.FrontEndOrigin
+	}
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%sorder_id=%d", base, separator, orderID)
+}
+
+// distributeOrderEmail tells the student of a completed order whether they got a seat or joined the waitlist
+func (server *Server) distributeOrderEmail(ctx *gin.Context, result db.CompleteOrderTxResult) {
+	if !result.Completed {
+		return
+	}
+	if result.Subscription.WaitlistedAt.Valid {
+		server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventWaitlisted)
+	} else if result.Subscription.Active {
+		server.distributeEnrollmentEmail(ctx, result.Subscription, worker.EnrollmentEventApproved)
+	}
+}
+
+// SetCoursePriceRequest defines the request body structure for pricing a course
+type SetCoursePriceRequest struct {
+	CourseID int64  `json:"course_id" binding:"required,min=1"`
+	Currency string `json:"currency" binding:"required"`
+	Amount   int64  `json:"amount" binding:"required,min=1"`
+}
+
+// @Summary Set the price of a course
+// @Description Make a course paid. The amount is in the smallest unit of the currency, for example cents. Students of a
+// @Description paid course enroll by checking out, admins and invite codes still enroll students without payment.
+// @ID set-course-price
+// @Accept json
+// @Produce json
+// @Param request body SetCoursePriceRequest true "Set Course Price Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /payment/price [put]
+func (server *Server) SetCoursePrice(ctx *gin.Context) {
+	var req SetCoursePriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	currency, err := payments.NormalizeCurrency(req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	price, err := server.store.UpsertCoursePrice(ctx, db.UpsertCoursePriceParams{
+		CourseID: req.CourseID,
+		Currency: currency,
+		Amount:   req.Amount,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, price)
+}
+
+// CoursePriceRequest contains the input parameters for the price of a course
+type CoursePriceRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Get the price of a course
+// @Description Get the price of a course, a course without a price is free
+// @ID get-course-price
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /payment/price [get]
+func (server *Server) GetCoursePrice(ctx *gin.Context) {
+	var req CoursePriceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	price, err := server.store.GetCoursePrice(ctx, req.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(errCourseNotForSale))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, price)
+}
+
+// @Summary Make a course free
+// @Description Remove the price of a course, students enroll following its enrollment settings again
+// @ID delete-course-price
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /payment/price [delete]
+func (server *Server) DeleteCoursePrice(ctx *gin.Context) {
+	var req CoursePriceRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteCoursePrice(ctx, req.CourseID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Course price removed successfully"})
+}
+
+// QuoteCourseRequest contains the input parameters for pricing a course with a coupon
+type QuoteCourseRequest struct {
+	CourseID   int64  `form:"course_id" binding:"required,min=1"`
+	CouponCode string `form:"coupon_code"`
+}
+
+// @Summary Quote a course
+// @Description Get what a course costs with an optional coupon before checking out
+// @ID quote-course
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Param coupon_code query string false "Coupon Code"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /payment/quote [get]
+func (server *Server) QuoteCourse(ctx *gin.Context) {
+	var req QuoteCourseRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	quote, err := server.quoteCourse(ctx, req.CourseID, req.CouponCode, time.Now())
+	if err != nil {
+		if isCouponRefused(err) || errors.Is(err, errCourseNotForSale) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+// CheckoutRequest defines the request body structure for buying a course
+type CheckoutRequest struct {
+	CourseID   int64  `json:"course_id" binding:"required,min=1"`
+	CouponCode string `json:"coupon_code"`
+}
+
+// checkoutResponse is a new order with the page where the student pays for it, or the subscription of an order
+// a coupon made free
+type checkoutResponse struct {
+	Order        db.Order         `json:"order"`
+	CheckoutURL  string           `json:"checkout_url,omitempty"`
+	Subscription *db.Subscription `json:"subscription,omitempty"`
+}
+
+// @Summary Check out a course
+// @Description Create an order for a paid course and open a checkout with the payment provider. The student is enrolled
+// @Description once the provider confirms the payment through its webhook, an order a coupon makes free enrolls right away.
+// @Description The enrollment mode and window of the course apply, a paid order skips approval. Students whose access
+// @Description has an end can check out again to renew it for another access period.
+// @ID checkout
+// @Accept json
+// @Produce json
+// @Param request body CheckoutRequest true "Checkout Request"
+// @Success 201
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Failure 502
+// @Failure 503
+// @Router /payment/checkout [post]
+func (server *Server) Checkout(ctx *gin.Context) {
+	var req CheckoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if server.paymentProvider == nil {
+		ctx.JSON(http.StatusServiceUnavailable, errorResponse(errPaymentsNotEnabled))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	now := time.Now()
+
+	subscription, err := server.store.GetCourseSubscription(ctx, db.GetCourseSubscriptionParams{
+		UserID:   authPayload.UserID,
+		CourseID: req.CourseID,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// students with time-boxed access can pay again to renew it
+	renewal := err == nil && subscription.Active && subscription.ExpiresAt.Valid
+	if err == nil && !renewal && (subscription.Active || subscription.WaitlistedAt.Valid) {
+		err := errors.New("already enrolled in this course")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// enrollment settings only apply to students who join, not to renewals
+	if !renewal {
+		settings, err := server.store.GetCourseEnrollmentSettings(ctx, req.CourseID)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			settings, err = db.CourseEnrollmentSetting{CourseID: req.CourseID, Mode: db.EnrollmentModeApproval}, nil
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if err := db.CheckEnrollmentOpen(settings, now); err != nil {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+
+	quote, err := server.quoteCourse(ctx, req.CourseID, req.CouponCode, now)
+	if err != nil {
+		if isCouponRefused(err) || errors.Is(err, errCourseNotForSale) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	course, err := server.store.GetCourses(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	user, err := server.store.GetUserByID(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateOrderParams{
+		UserID:         authPayload.UserID,
+		CourseID:       req.CourseID,
+		Currency:       quote.Currency,
+		ListAmount:     quote.ListAmount,
+		DiscountAmount: quote.DiscountAmount,
+		Amount:         quote.Amount,
+		Provider:       server.paymentProvider.Name(),
+	}
+	if quote.Coupon != nil {
+		arg.CouponID = pgtype.Int8{Int64: quote.Coupon.CouponID, Valid: true}
+	}
+
+	// the order holds a use of its coupon until it is paid or closed
+	order, err := server.store.CreateOrderTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrCouponExhausted) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errCouponUsedUp))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// nothing to pay, the coupon covers the whole price
+	if order.Amount == 0 {
+		result, err := server.store.CompleteOrderTx(ctx, db.CompleteOrderTxParams{
+			OrderID:     order.OrderID,
+			Currency:    order.Currency,
+			AfterCreate: server.distributeCreateSubscription(ctx),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		server.distributeOrderEmail(ctx, result)
+
+		ctx.JSON(http.StatusCreated, checkoutResponse{Order: result.Order, Subscription: &result.Subscription})
+		return
+	}
+
+	session, err := server.paymentProvider.CreateCheckoutSession(ctx, payments.CheckoutRequest{
+		OrderID:       order.OrderID,
+		Title:         course.Title,
+		Currency:      order.Currency,
+		Amount:        order.Amount,
+		CustomerEmail: user.Email,
+		SuccessURL:    server.paymentRedirectURL(server.config.PaymentSuccessURL, order.OrderID),
+		CancelURL:     server.paymentRedirectURL(server.config.PaymentCancelURL, order.OrderID),
+	})
+	if err != nil {
+		if _, closeErr := server.store.CloseOrder(ctx, db.CloseOrderParams{OrderID: order.OrderID, Status: db.OrderStatusFailed}); closeErr != nil {
+			log.Error().Err(closeErr).Int64("order_id", order.OrderID).Msg("failed to close order")
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	order, err = server.store.SetOrderCheckoutSession(ctx, db.SetOrderCheckoutSessionParams{
+		OrderID:           order.OrderID,
+		CheckoutSessionID: pgtype.Text{String: session.ID, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, checkoutResponse{Order: order, CheckoutURL: session.URL})
+}
+
+// @Summary Payment webhook
+// @Description Receives payment events from the payment provider. Only requests signed by the provider are accepted,
+// @Description a successful payment enrolls the student of its order and a refund takes the course away again.
+// @ID payment-webhook
+// @Accept json
+// @Produce json
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Failure 503
+// @Router /payment/webhook [post]
+func (server *Server) PaymentWebhook(ctx *gin.Context) {
+	if server.paymentProvider == nil {
+		ctx.JSON(http.StatusServiceUnavailable, errorResponse(errPaymentsNotEnabled))
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookSize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, err := server.paymentProvider.ParseWebhook(payload, ctx.Request.Header)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	switch event.Type {
+	case payments.EventPaymentSucceeded:
+		result, err := server.store.CompleteOrderTx(ctx, db.CompleteOrderTxParams{
+			OrderID:     event.OrderID,
+			SessionID:   event.SessionID,
+			PaymentID:   event.PaymentID,
+			Amount:      event.Amount,
+			Currency:    event.Currency,
+			AfterCreate: server.distributeCreateSubscription(ctx),
+		})
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// the checkout was not opened by this server
+			break
+		}
+		if errors.Is(err, db.ErrCouponExhausted) {
+			// the checkout closed before it was paid and its coupon went to others meanwhile,
+			// the discounted price no longer holds so the payment is given back
+			log.Warn().Int64("order_id", event.OrderID).Str("event_id", event.ID).Msg("refunding late payment of a used up coupon")
+			if err := server.paymentProvider.Refund(ctx, event.PaymentID); err != nil {
+				ctx.JSON(http.StatusBadGateway, errorResponse(err))
+				return
+			}
+			break
+		}
+		if err != nil {
+			log.Error().Err(err).Int64("order_id", event.OrderID).Str("event_id", event.ID).Msg("failed to complete order")
+			if errors.Is(err, db.ErrPaymentMismatch) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		server.distributeOrderEmail(ctx, result)
+
+	case payments.EventPaymentFailed, payments.EventCheckoutExpired:
+		status := db.OrderStatusFailed
+		if event.Type == payments.EventCheckoutExpired {
+			status = db.OrderStatusExpired
+		}
+		// only a pending order is closed, a late event must not undo a payment
+		_, err := server.store.CloseOrder(ctx, db.CloseOrderParams{OrderID: event.OrderID, Status: status})
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+	case payments.EventRefunded:
+		order, err := server.store.GetOrderByPaymentID(ctx, event.PaymentID)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		result, err := server.store.RefundOrderTx(ctx, order.OrderID)
+		if err != nil && !errors.Is(err, db.ErrOrderNotPaid) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for _, subscription := range result.Promoted {
+			server.distributeEnrollmentEmail(ctx, subscription, worker.EnrollmentEventPromoted)
+			server.distributeCourseLaunched(ctx, subscription)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// @Summary List my orders
+// @Description List the orders of the signed in student, newest first
+// @ID list-my-orders
+// @Produce json
+// @Success 200
+// @Failure 500
+// @Router /payment/orders [get]
+func (server *Server) ListMyOrders(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	orders, err := server.store.ListOrdersByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orders)
+}
+
+// ListCourseOrdersRequest contains the input parameters for listing the orders of a course
+type ListCourseOrdersRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List the orders of a course
+// @Description List every order placed for a course, newest first
+// @ID list-course-orders
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /payment/course/orders [get]
+func (server *Server) ListCourseOrders(ctx *gin.Context) {
+	var req ListCourseOrdersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	orders, err := server.store.ListOrdersByCourse(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orders)
+}
+
+// RefundOrderRequest defines the request body structure for refunding an order
+type RefundOrderRequest struct {
+	OrderID int64 `json:"order_id" binding:"required,min=1"`
+}
+
+// @Summary Refund an order
+// @Description Give back the payment of an order through the payment provider and take the course away from the student,
+// @Description the freed seat goes to the waitlist
+// @ID refund-order
+// @Accept json
+// @Produce json
+// @Param request body RefundOrderRequest true "Refund Order Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Failure 502
+// @Failure 503
+// @Router /payment/refund [put]
+func (server *Server) RefundOrder(ctx *gin.Context) {
+	var req RefundOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	order, err := server.store.GetOrder(ctx, req.OrderID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if order.Status != db.OrderStatusPaid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrOrderNotPaid))
+		return
+	}
+
+	// an order a coupon made free has no payment to give back
+	if order.PaymentID != "" {
+		if server.paymentProvider == nil {
+			ctx.JSON(http.StatusServiceUnavailable, errorResponse(errPaymentsNotEnabled))
+			return
+		}
+		if err := server.paymentProvider.Refund(ctx, order.PaymentID); err != nil {
+			ctx.JSON(http.StatusBadGateway, errorResponse(err))
+			return
+		}
+	}
+
+	result, err := server.store.RefundOrderTx(ctx, order.OrderID)
+	if err != nil {
+		if errors.Is(err, db.ErrOrderNotPaid) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	for _, subscription := range result.Promoted {
+		server.distributeEnrollmentEmail(ctx, subscription, worker.EnrollmentEventPromoted)
+		server.distributeCourseLaunched(ctx, subscription)
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,332 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/payments"
+	"eduApp/util"
+	"eduApp/worker"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// orderStore keeps orders in memory and completes them the way CompleteOrderTx does: once, and only for a
+// payment of the order's amount, currency and checkout session
+type orderStore struct {
+	db.Store
+
+	mu            sync.Mutex
+	orders        map[int64]db.Order
+	subscriptions map[int64]db.Subscription
+	// couponUsedUp refuses to take a coupon use again for a closed order that is paid late
+	couponUsedUp bool
+}
+
+func newOrderStore(orders ...db.Order) *orderStore {
+	store := &orderStore{orders: make(map[int64]db.Order), subscriptions: make(map[int64]db.Subscription)}
+	for _, order := range orders {
+		store.orders[order.OrderID] = order
+	}
+	return store
+}
+
+func (store *orderStore) CompleteOrderTx(ctx context.Context, arg db.CompleteOrderTxParams) (db.CompleteOrderTxResult, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var result db.CompleteOrderTxResult
+	order, ok := store.orders[arg.OrderID]
+	if !ok {
+		return result, db.ErrRecordNotFound
+	}
+	result.Order = order
+	if order.Status == db.OrderStatusPaid || order.Status == db.OrderStatusRefunded {
+		result.Subscription = store.subscriptions[order.OrderID]
+		return result, nil
+	}
+	if order.Amount != arg.Amount || order.Currency != arg.Currency {
+		return result, db.ErrPaymentMismatch
+	}
+	if order.CheckoutSessionID.Valid && order.CheckoutSessionID.String != arg.SessionID {
+		return result, db.ErrPaymentMismatch
+	}
+	if order.CouponID.Valid && order.Status != db.OrderStatusPending && store.couponUsedUp {
+		return result, db.ErrCouponExhausted
+	}
+
+	order.Status = db.OrderStatusPaid
+	order.PaymentID = arg.PaymentID
+	subscription := db.Subscription{SubscriptionID: order.OrderID, UserID: order.UserID, CourseID: order.CourseID, Active: true}
+	if arg.AfterCreate != nil {
+		if err := arg.AfterCreate(subscription); err != nil {
+			return db.CompleteOrderTxResult{}, err
+		}
+	}
+
+	store.orders[order.OrderID] = order
+	store.subscriptions[order.OrderID] = subscription
+	return db.CompleteOrderTxResult{Order: order, Subscription: subscription, Completed: true, Created: true}, nil
+}
+
+func (store *orderStore) CloseOrder(ctx context.Context, arg db.CloseOrderParams) (db.Order, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	order, ok := store.orders[arg.OrderID]
+	if !ok || order.Status != db.OrderStatusPending {
+		return db.Order{}, db.ErrRecordNotFound
+	}
+	order.Status = arg.Status
+	store.orders[order.OrderID] = order
+	return order, nil
+}
+
+func (store *orderStore) order(orderID int64) db.Order {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.orders[orderID]
+}
+
+// recordingDistributor records the tasks handlers queue
+type recordingDistributor struct {
+	worker.TaskDistributor
+
+	mu    sync.Mutex
+	tasks []string
+}
+
+func (distributor *recordingDistributor) record(task string) {
+	distributor.mu.Lock()
+	defer distributor.mu.Unlock()
+
+	distributor.tasks = append(distributor.tasks, task)
+}
+
+func (distributor *recordingDistributor) recorded() []string {
+	distributor.mu.Lock()
+	defer distributor.mu.Unlock()
+
+	return append([]string(nil), distributor.tasks...)
+}
+
+func (distributor *recordingDistributor) DistributeTaskCreateSubscription(ctx context.Context, payload *worker.PayloadCreateSubscription, opts ...asynq.Option) error {
+	distributor.record(worker.TaskCreateSubscription)
+	return nil
+}
+
+func (distributor *recordingDistributor) DistributeTaskSendEnrollmentEmail(ctx context.Context, payload *worker.PayloadSendEnrollmentEmail, opts ...asynq.Option) error {
+	distributor.record(worker.TaskSendEnrollmentEmail + ":" + payload.Event)
+	return nil
+}
+
+func (distributor *recordingDistributor) DistributeTaskSendXAPIStatement(ctx context.Context, payload *worker.PayloadSendXAPIStatement, opts ...asynq.Option) error {
+	distributor.record(worker.TaskSendXAPIStatement)
+	return nil
+}
+
+func postWebhook(t *testing.T, server *Server, payload []byte, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/payment/webhook", bytes.NewReader(payload))
+	for key, values := range header {
+		ctx.Request.Header[key] = values
+	}
+	server.PaymentWebhook(ctx)
+	return recorder
+}
+
+func TestPaymentWebhookFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	pending := func(orderID int64) db.Order {
+		return db.Order{
+			OrderID:           orderID,
+			UserID:            3,
+			CourseID:          5,
+			Currency:          "usd",
+			Amount:            2500,
+			Status:            db.OrderStatusPending,
+			Provider:          "fake",
+			CheckoutSessionID: pgtype.Text{String: fmt.Sprintf("fake_cs_%d", orderID), Valid: true},
+		}
+	}
+	paid := func(orderID int64) payments.Event {
+		return payments.Event{
+			ID:        "evt_paid",
+			Type:      payments.EventPaymentSucceeded,
+			OrderID:   orderID,
+			SessionID: fmt.Sprintf("fake_cs_%d", orderID),
+			PaymentID: "pay_1",
+			Amount:    2500,
+			Currency:  "usd",
+		}
+	}
+
+	enrolled := []string{
+		worker.TaskCreateSubscription,
+		worker.TaskSendXAPIStatement,
+		worker.TaskSendEnrollmentEmail + ":" + worker.EnrollmentEventApproved,
+	}
+
+	testCases := []struct {
+		name         string
+		events       []payments.Event
+		forge        bool
+		couponUsedUp bool
+		wantStatus   int
+		wantOrder    string
+		wantTasks    []string
+		wantRefunds  []string
+	}{
+		{
+			name:       "payment enrolls the student",
+			events:     []payments.Event{paid(1)},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusPaid,
+			wantTasks:  enrolled,
+		},
+		{
+			name:       "a redelivered payment enrolls once",
+			events:     []payments.Event{paid(1), paid(1)},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusPaid,
+			wantTasks:  enrolled,
+		},
+		{
+			name: "wrong amount is rejected",
+			events: func() []payments.Event {
+				event := paid(1)
+				event.Amount = 100
+				return []payments.Event{event}
+			}(),
+			wantStatus: http.StatusBadRequest,
+			wantOrder:  db.OrderStatusPending,
+		},
+		{
+			name: "another checkout session is rejected",
+			events: func() []payments.Event {
+				event := paid(1)
+				event.SessionID = "fake_cs_other"
+				return []payments.Event{event}
+			}(),
+			wantStatus: http.StatusBadRequest,
+			wantOrder:  db.OrderStatusPending,
+		},
+		{
+			name:       "unknown order is acknowledged",
+			events:     []payments.Event{paid(8)},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusPending,
+		},
+		{
+			name:       "forged signature is rejected",
+			events:     []payments.Event{paid(1)},
+			forge:      true,
+			wantStatus: http.StatusBadRequest,
+			wantOrder:  db.OrderStatusPending,
+		},
+		{
+			name:       "expired checkout closes the order",
+			events:     []payments.Event{{ID: "evt_expired", Type: payments.EventCheckoutExpired, OrderID: 1}},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusExpired,
+		},
+		{
+			name:       "late failure keeps the payment",
+			events:     []payments.Event{paid(1), {ID: "evt_failed", Type: payments.EventPaymentFailed, OrderID: 1}},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusPaid,
+			wantTasks:  enrolled,
+		},
+		{
+			name:       "late payment of an expired checkout enrolls the student",
+			events:     []payments.Event{{ID: "evt_expired", Type: payments.EventCheckoutExpired, OrderID: 1}, paid(1)},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusPaid,
+			wantTasks:  enrolled,
+		},
+		{
+			name:         "late payment after the coupon was used up is refunded",
+			events:       []payments.Event{{ID: "evt_expired", Type: payments.EventCheckoutExpired, OrderID: 1}, paid(1)},
+			couponUsedUp: true,
+			wantStatus:   http.StatusOK,
+			wantOrder:    db.OrderStatusExpired,
+			wantRefunds:  []string{"pay_1"},
+		},
+		{
+			name:       "ignored event",
+			events:     []payments.Event{{ID: "evt_other", Type: payments.EventIgnored}},
+			wantStatus: http.StatusOK,
+			wantOrder:  db.OrderStatusPending,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := payments.NewFakeProvider("webhook-secret")
+			order := pending(1)
+			order.CouponID = pgtype.Int8{Int64: 4, Valid: true}
+			store := newOrderStore(order)
+			store.couponUsedUp = tc.couponUsedUp
+			distributor := &recordingDistributor{}
+			server := &Server{
+				config:          util.Config{},
+				store:           store,
+				taskDistributor: distributor,
+				paymentProvider: provider,
+			}
+
+			var recorder *httptest.ResponseRecorder
+			for _, event := range tc.events {
+				payload, header, err := provider.SignedEvent(event)
+				if err != nil {
+					t.Fatalf("SignedEvent() error = %v", err)
+				}
+				if tc.forge {
+					payload, header, _ = payments.NewFakeProvider("attacker").SignedEvent(event)
+				}
+				recorder = postWebhook(t, server, payload, header)
+			}
+
+			if recorder.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if tc.wantStatus == http.StatusOK {
+				var body map[string]bool
+				if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil || !body["received"] {
+					t.Errorf("body = %s, want the event acknowledged", recorder.Body)
+				}
+			}
+			if status := store.order(1).Status; status != tc.wantOrder {
+				t.Errorf("order status = %s, want %s", status, tc.wantOrder)
+			}
+
+			if refunds := provider.Refunds(); len(refunds) != len(tc.wantRefunds) || (len(refunds) > 0 && refunds[0] != tc.wantRefunds[0]) {
+				t.Errorf("refunds = %v, want %v", refunds, tc.wantRefunds)
+			}
+
+			tasks := distributor.recorded()
+			if len(tasks) != len(tc.wantTasks) {
+				t.Fatalf("queued tasks = %v, want %v", tasks, tc.wantTasks)
+			}
+			for i := range tasks {
+				if tasks[i] != tc.wantTasks[i] {
+					t.Errorf("queued tasks = %v, want %v", tasks, tc.wantTasks)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,611 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/peerreview"
+	"eduApp/token"
+	"eduApp/typetext"
+	"eduApp/util"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// errPeerReviewFinalized is returned when peer review of an assignment is already closed
+var errPeerReviewFinalized = errors.New("peer review of this assignment is already finalized")
+
+// SetPeerReviewSettingsRequest defines the request body structure for turning on peer review for an assignment
+type SetPeerReviewSettingsRequest struct {
+	AssignmentID         int64  `json:"assignment_id" binding:"required,min=1"`
+	ReviewsPerSubmission int64  `json:"reviews_per_submission" binding:"required,min=1,max=10"`
+	ReviewDueAt          string `json:"review_due_at" binding:"required"`
+}
+
+// @Summary Set up peer review
+// @Description Turn on peer review for an assignment with a rubric and a due date. Once no more submissions are accepted every
+// @Description submitter is anonymously given reviews_per_submission submissions of others to review against the rubric until review_due_at
+// @ID set-peer-review-settings
+// @Accept json
+// @Produce json
+// @Param request body SetPeerReviewSettingsRequest true "Set Peer Review Settings Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/settings [put]
+func (server *Server) SetPeerReviewSettings(ctx *gin.Context) {
+	var req SetPeerReviewSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	reviewDueAt, err := util.ParseDueDate(req.ReviewDueAt, time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	assignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !assignment.DueDate.Valid {
+		err := errors.New("peer review needs an assignment with a due date")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !reviewDueAt.After(assignment.DueDate.Time) {
+		err := errors.New("reviews must be due after the assignment")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.GetRubricByAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err = errors.New("peer review needs an assignment with a rubric")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	current, err := server.store.GetPeerReviewSettings(ctx, req.AssignmentID)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil {
+		if current.FinalizedAt.Valid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errPeerReviewFinalized))
+			return
+		}
+		if current.AssignedAt.Valid && current.ReviewsPerSubmission != req.ReviewsPerSubmission {
+			err := errors.New("reviewers are already assigned, only the review due date can change")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	settings, err := server.store.UpsertPeerReviewSettings(ctx, db.UpsertPeerReviewSettingsParams{
+		AssignmentID:         req.AssignmentID,
+		ReviewsPerSubmission: req.ReviewsPerSubmission,
+		ReviewDueAt:          reviewDueAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// GetPeerReviewSettingsRequest contains the input parameters for getting the peer review settings of an assignment
+type GetPeerReviewSettingsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary Get peer review settings
+// @Description Get the number of reviews, the review due date and the stage peer review of an assignment is at
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/settings [get]
+func (server *Server) GetPeerReviewSettings(ctx *gin.Context) {
+	var req GetPeerReviewSettingsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	settings, err := server.store.GetPeerReviewSettings(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// DeletePeerReviewSettingsRequest defines the request structure for turning off peer review for an assignment
+type DeletePeerReviewSettingsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary Turn off peer review
+// @Description Turn off peer review for an assignment, which is only possible before reviewers are assigned
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/settings/delete [delete]
+func (server *Server) DeletePeerReviewSettings(ctx *gin.Context) {
+	var req DeletePeerReviewSettingsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	settings, err := server.store.GetPeerReviewSettings(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if settings.AssignedAt.Valid {
+		err := errors.New("reviewers are already assigned")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeletePeerReviewSettings(ctx, req.AssignmentID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Peer review turned off successfully"})
+}
+
+// ListAssignedPeerReviewsRequest contains the input parameters for listing the reviews a student has to write
+type ListAssignedPeerReviewsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// assignedPeerReview is a review to write, its submission is downloaded from FileURL under an opaque name
+type assignedPeerReview struct {
+	db.ListPeerReviewsByReviewerRow
+	FileURL string `json:"file_url"`
+}
+
+// @Summary List assigned peer reviews
+// @Description List the submissions the logged in student has to review, without the names of their authors.
+// @Description Each submission is downloaded from its file_url, which does not reveal the author's file name
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /peer-review/assigned [get]
+func (server *Server) ListAssignedPeerReviews(ctx *gin.Context) {
+	var req ListAssignedPeerReviewsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	reviews, err := server.store.ListPeerReviewsByReviewer(ctx, db.ListPeerReviewsByReviewerParams{
+		AssignmentID: req.AssignmentID,
+		ReviewerID:   authPayload.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	assigned := make([]assignedPeerReview, 0, len(reviews))
+	for _, review := range reviews {
+		assigned = append(assigned, assignedPeerReview{
+			ListPeerReviewsByReviewerRow: review,
+			FileURL:                      fmt.Sprintf("/peer-review/file?review_id=%d", review.ReviewID),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, assigned)
+}
+
+// GetPeerReviewFileRequest contains the input parameters for downloading a submission under review
+type GetPeerReviewFileRequest struct {
+	ReviewID int64 `form:"review_id" binding:"required,min=1"`
+}
+
+// @Summary Download a submission under review
+// @Description Download the submission of a review assigned to the logged in student. The file is named after the review,
+// @Description the stored file name holds the author's original file name and is never shown to reviewers
+// @Produce octet-stream
+// @Param review_id query int true "Review ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/file [get]
+func (server *Server) GetPeerReviewFile(ctx *gin.Context) {
+	var req GetPeerReviewFileRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	review, err := server.store.GetPeerReview(ctx, req.ReviewID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if review.ReviewerID != authPayload.UserID && authPayload.Role != "admin" {
+		err := errors.New("this review is not assigned to you")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	submission, err := server.store.GetSubmissionByID(ctx, review.SubmissionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	filePath, err := util.FilePathFromURL(submission.Resource)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if _, err := os.Stat(filePath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = errors.New("the submitted file is no longer available")
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	name := fmt.Sprintf("submission-%d%s", review.ReviewID, strings.ToLower(filepath.Ext(filePath)))
+	ctx.FileAttachment(filePath, name)
+}
+
+// SubmitPeerReviewRequest defines the request body structure for reviewing a peer's submission
+type SubmitPeerReviewRequest struct {
+	ReviewID int64                  `json:"review_id" binding:"required,min=1"`
+	Scores   []util.RubricSelection `json:"scores" binding:"required"`
+	Comment  string                 `json:"comment"`
+}
+
+// @Summary Submit a peer review
+// @Description Pick a level for every criterion of the assignment rubric for a submission assigned to the logged in student.
+// @Description A review can be changed until the review due date
+// @ID submit-peer-review
+// @Accept json
+// @Produce json
+// @Param request body SubmitPeerReviewRequest true "Submit Peer Review Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/submit [put]
+func (server *Server) SubmitPeerReview(ctx *gin.Context) {
+	var req SubmitPeerReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	review, err := server.store.GetPeerReview(ctx, req.ReviewID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if review.ReviewerID != authPayload.UserID {
+		err := errors.New("this review is not assigned to you")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	settings, err := server.store.GetPeerReviewSettings(ctx, review.AssignmentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if settings.FinalizedAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errPeerReviewFinalized))
+		return
+	}
+	if time.Now().After(settings.ReviewDueAt) {
+		err := errors.New("the review due date has passed")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rubric, err := server.store.GetRubricByAssignment(ctx, review.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err = errors.New("the assignment has no rubric")
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	scores, total, maxPoints, err := util.ScoreRubric(rubric.Criteria, req.Scores)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err = server.store.SubmitPeerReview(ctx, db.SubmitPeerReviewParams{
+		ReviewID:     req.ReviewID,
+		RubricScores: scores,
+		Points:       total,
+		Comment:      strings.TrimSpace(req.Comment),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"review_id":     review.ReviewID,
+		"submission_id": review.SubmissionID,
+		"rubric_scores": review.RubricScores,
+		"points":        review.Points,
+		"max_points":    maxPoints,
+		"comment":       review.Comment,
+		"submitted_at":  review.SubmittedAt,
+	})
+}
+
+// receivedPeerReview is a review of a student's submission without the reviewer
+type receivedPeerReview struct {
+	RubricScores typetext.RubricScores `json:"rubric_scores"`
+	Points       int64                 `json:"points"`
+	Comment      string                `json:"comment"`
+	SubmittedAt  pgtype.Timestamptz    `json:"submitted_at"`
+}
+
+// ListReceivedPeerReviewsRequest contains the input parameters for reading the peer reviews of a student's submission
+type ListReceivedPeerReviewsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary List received peer reviews
+// @Description List the anonymous peer reviews of the logged in student's submission with the aggregated peer score,
+// @Description available once peer review of the assignment is finalized
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/received [get]
+func (server *Server) ListReceivedPeerReviews(ctx *gin.Context) {
+	var req ListReceivedPeerReviewsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	settings, err := server.store.GetPeerReviewSettings(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !settings.FinalizedAt.Valid {
+		err := errors.New("peer reviews are shared once the review due date has passed")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	submission, err := server.store.GetSubmission(ctx, db.GetSubmissionParams{
+		AssignmentID: req.AssignmentID,
+		UserID:       authPayload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListPeerReviewsBySubmission(ctx, submission.SubmissionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	received := []receivedPeerReview{}
+	var points []int64
+	for _, review := range reviews {
+		if !review.SubmittedAt.Valid {
+			continue
+		}
+		received = append(received, receivedPeerReview{
+			RubricScores: review.RubricScores,
+			Points:       review.Points,
+			Comment:      review.Comment,
+			SubmittedAt:  review.SubmittedAt,
+		})
+		points = append(points, review.Points)
+	}
+	score, _ := peerreview.Aggregate(points)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"submission_id": submission.SubmissionID,
+		"peer_score":    score,
+		"reviews":       received,
+	})
+}
+
+// peerReviewResult is the peer review outcome of one submission as the instructor sees it
+type peerReviewResult struct {
+	SubmissionID int64              `json:"submission_id"`
+	UserID       int64              `json:"user_id"`
+	Grade        string             `json:"grade"`
+	GradedAt     pgtype.Timestamptz `json:"graded_at"`
+	PeerScore    peerreview.Score   `json:"peer_score"`
+	Reviews      []db.PeerReview    `json:"reviews"`
+}
+
+// GetPeerReviewResultsRequest contains the input parameters for the peer review results of an assignment
+type GetPeerReviewResultsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary Get peer review results
+// @Description List every submission of an assignment with its reviewers, their reviews and the peer score after outlier trimming.
+// @Description Peer scores become grades when the review due date passes, the instructor overrides one with PUT /submission/grade
+// @Description and submissions graded before the review due date keep the instructor's grade
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /peer-review/results [get]
+func (server *Server) GetPeerReviewResults(ctx *gin.Context) {
+	var req GetPeerReviewResultsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	settings, err := server.store.GetPeerReviewSettings(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	submissions, err := server.store.ListSubmittedSubmissionsByAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	reviews, err := server.store.ListPeerReviewsByAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	bySubmission := make(map[int64][]db.PeerReview)
+	for _, review := range reviews {
+		bySubmission[review.SubmissionID] = append(bySubmission[review.SubmissionID], review)
+	}
+
+	results := make([]peerReviewResult, 0, len(submissions))
+	for _, submission := range submissions {
+		result := peerReviewResult{
+			SubmissionID: submission.SubmissionID,
+			UserID:       submission.UserID,
+			Grade:        submission.Grade,
+			GradedAt:     submission.GradedAt,
+			Reviews:      []db.PeerReview{},
+		}
+
+		var points []int64
+		for _, review := range bySubmission[submission.SubmissionID] {
+			result.Reviews = append(result.Reviews, review)
+			if review.SubmittedAt.Valid {
+				points = append(points, review.Points)
+			}
+		}
+		result.PeerScore, _ = peerreview.Aggregate(points)
+
+		results = append(results, result)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"settings": settings,
+		"results":  results,
+	})
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// peerReviewStore holds the reviews of one assignment, submission 4 is reviewed by user 42
+type peerReviewStore struct {
+	db.Store
+
+	submission db.Submission
+	review     db.PeerReview
+}
+
+func (store *peerReviewStore) ListPeerReviewsByReviewer(ctx context.Context, arg db.ListPeerReviewsByReviewerParams) ([]db.ListPeerReviewsByReviewerRow, error) {
+	if arg.ReviewerID != store.review.ReviewerID {
+		return []db.ListPeerReviewsByReviewerRow{}, nil
+	}
+	return []db.ListPeerReviewsByReviewerRow{{ReviewID: store.review.ReviewID, SubmissionID: store.review.SubmissionID}}, nil
+}
+
+func (store *peerReviewStore) GetPeerReview(ctx context.Context, reviewID int64) (db.PeerReview, error) {
+	if reviewID != store.review.ReviewID {
+		return db.PeerReview{}, db.ErrRecordNotFound
+	}
+	return store.review, nil
+}
+
+func (store *peerReviewStore) GetSubmissionByID(ctx context.Context, submissionID int64) (db.Submission, error) {
+	return store.submission, nil
+}
+
+func peerReviewRouter(store *peerReviewStore, payload *token.Payload) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	server := &Server{store: store}
+	router := gin.New()
+	setPayload := func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, payload)
+	}
+	router.GET("/peer-review/assigned", setPayload, server.ListAssignedPeerReviews)
+	router.GET("/peer-review/file", setPayload, server.GetPeerReviewFile)
+	return router
+}
+
+func TestListAssignedPeerReviewsHidesAuthorFile(t *testing.T) {
+	store := &peerReviewStore{
+		submission: db.Submission{SubmissionID: 4, Resource: "http://localhost:8080/uploads/submissions/jane-doe-essay-0c3f.pdf"},
+		review:     db.PeerReview{ReviewID: 9, SubmissionID: 4, ReviewerID: 42},
+	}
+
+	recorder := httptest.NewRecorder()
+	peerReviewRouter(store, &token.Payload{UserID: 42, Role: "student"}).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/peer-review/assigned?assignment_id=1", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+	}
+	if strings.Contains(recorder.Body.String(), "jane") || strings.Contains(recorder.Body.String(), "uploads") {
+		t.Errorf("response reveals the stored file: %s", recorder.Body)
+	}
+
+	var reviews []gin.H
+	json.Unmarshal(recorder.Body.Bytes(), &reviews)
+	if len(reviews) != 1 || reviews[0]["file_url"] != "/peer-review/file?review_id=9" {
+		t.Errorf("reviews = %v, want review 9 with its file_url", reviews)
+	}
+}
+
+func TestGetPeerReviewFile(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	if err := os.MkdirAll(filepath.Join("uploads", "submissions"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("uploads", "submissions", "jane-doe-essay-0c3f.PDF"), []byte("%PDF-1.4 essay"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name       string
+		resource   string
+		payload    *token.Payload
+		reviewID   string
+		wantStatus int
+	}{
+		{name: "reviewer", resource: "http://localhost:8080/uploads/submissions/jane-doe-essay-0c3f.PDF",
+			payload: &token.Payload{UserID: 42, Role: "student"}, reviewID: "9", wantStatus: http.StatusOK},
+		{name: "admin", resource: "http://localhost:8080/static/submissions/jane-doe-essay-0c3f.PDF",
+			payload: &token.Payload{UserID: 1, Role: "admin"}, reviewID: "9", wantStatus: http.StatusOK},
+		{name: "another student", resource: "http://localhost:8080/uploads/submissions/jane-doe-essay-0c3f.PDF",
+			payload: &token.Payload{UserID: 43, Role: "student"}, reviewID: "9", wantStatus: http.StatusForbidden},
+		{name: "unknown review", resource: "http://localhost:8080/uploads/submissions/jane-doe-essay-0c3f.PDF",
+			payload: &token.Payload{UserID: 42, Role: "student"}, reviewID: "10", wantStatus: http.StatusNotFound},
+		{name: "file removed", resource: "http://localhost:8080/uploads/submissions/gone.pdf",
+			payload: &token.Payload{UserID: 42, Role: "student"}, reviewID: "9", wantStatus: http.StatusNotFound},
+		{name: "no review id", resource: "http://localhost:8080/uploads/submissions/jane-doe-essay-0c3f.PDF",
+			payload: &token.Payload{UserID: 42, Role: "student"}, reviewID: "", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &peerReviewStore{
+				submission: db.Submission{SubmissionID: 4, Resource: tc.resource},
+				review:     db.PeerReview{ReviewID: 9, SubmissionID: 4, ReviewerID: 42},
+			}
+
+			recorder := httptest.NewRecorder()
+			peerReviewRouter(store, tc.payload).
+				ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/peer-review/file?review_id="+tc.reviewID, nil))
+			if recorder.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			if got := recorder.Header().Get("Content-Disposition"); got != `attachment; filename="submission-9.pdf"` {
+				t.Errorf("Content-Disposition = %q, want the opaque review name", got)
+			}
+			if recorder.Body.String() != "%PDF-1.4 essay" {
+				t.Errorf("body = %q, want the submitted file", recorder.Body)
+			}
+		})
+	}
+}
@@ -3,79 +3,19 @@ package api
 import (
 	db "eduApp/db/sqlc"
 	"eduApp/token"
+	"eduApp/typetext"
 	"eduApp/util"
 	"errors"
-	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// uploadSingleFile handles uploading a single file
-func (server *Server) uploadSingleProfileImage(file multipart.File, header *multipart.FileHeader) (string, error) {
-
-	fileExt := strings.ToLower(filepath.Ext(header.Filename))
-	// Validate file extension (optional)
-	allowedExtensions := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".jfif": true,
-	}
-	if !allowedExtensions[fileExt] {
-		return "", fmt.Errorf("unsupported file extension: %s", fileExt)
-	}
-
-	// Generate unique filename
-	originalFileName := strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
-	now := time.Now()
-	filename := strings.ReplaceAll(strings.ToLower(originalFileName), " ", "-") + "-" + fmt.Sprintf("%v", now.Unix()) + fileExt
-
-	// Create upload directory if it doesn't exist
-	uploadDir := "uploads/profile"
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return "", err
-	}
-
-	// Create destination file path
-	filePath := filepath.Join(uploadDir, filename)
-	// Save uploaded file
-	out, err := os.Create(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer out.Close()
-	_, err = io.Copy(out, file)
-	if err != nil {
-		return "", err
-	}
-
-	// Split the URL into parts
-	parts := strings.Split(filePath, "/")
-	println(parts)
-
-	// Replace "uploads" with "static"
-	if len(parts) > 2 && parts[0] == "uploads" {
-		parts[0] = "static"
-	}
-	println(parts)
-
-	//newUrl for load images
-	newUrl := strings.Join(parts, "/")
-	println("LINK", newUrl)
-
-	serverAddress := server.config.FileSource
-	print("server address", serverAddress)
-	Image := fmt.Sprintf("%s/%s", serverAddress, newUrl)
-	println(Image)
-	return Image, nil
+// uploadSingleProfileImage stores the profile picture variants
+func (server *Server) uploadSingleProfileImage(file multipart.File, header *multipart.FileHeader) (typetext.ImageVariants, error) {
+	return server.saveImageVariants(file, header, "uploads/profile")
 }
 
 type CreateProfilePictureRequest struct {
@@ -112,21 +52,23 @@ func (server *Server) CreateProfilePicture(ctx *gin.Context) {
 
 	// Handle image upload (if included in the request)
 	var profileImageFile string
+	var pictureVariants typetext.ImageVariants
 	file, header, err := ctx.Request.FormFile("picture")
 	if err == nil {
-		profileImageFile, err = server.uploadSingleProfileImage(file, header)
-		println("image_files", profileImageFile)
+		pictureVariants, err = server.uploadSingleProfileImage(file, header)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "File upload failed: " + err.Error()})
 			return
 		}
+		profileImageFile = pictureVariants.Full.JPEG
 	}
 
 	req.Picture = profileImageFile
 
 	arg := db.CreateProfilePictureParams{
-		UserID:  int64(req.UserID),
-		Picture: profileImageFile,
+		UserID:          int64(req.UserID),
+		Picture:         profileImageFile,
+		PictureVariants: pictureVariants,
 	}
 
 	profile, err := server.store.CreateProfilePicture(ctx, arg)
@@ -170,35 +112,37 @@ func (server *Server) UpdateProfilePicture(ctx *gin.Context) {
 		return
 	}
 
+	getPPImage, err := server.store.GetProfilePicture(ctx, req.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	// Handle image upload (if included in the request)
 	var profileImageFile string
+	var pictureVariants *typetext.ImageVariants
 	file, header, err := ctx.Request.FormFile("image")
 	if err == nil {
-		profileImageFile, err = server.uploadSingleProfileImage(file, header)
-		println("image_files", profileImageFile)
+		variants, err := server.uploadSingleProfileImage(file, header)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, errorResponse(err))
 			return
 		}
-	}
+		profileImageFile = variants.Full.JPEG
+		pictureVariants = &variants
 
-	getPPImage, err := server.store.GetProfilePicture(ctx, req.UserID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		//remove exiting files
+		util.DeleteFileByURL(getPPImage.Picture)
+		deleteImageVariants(getPPImage.PictureVariants)
 	}
 
-	//assign url value to var
-	value := getPPImage.Picture
-
-	//remove exiting file
-	util.DeleteFileByURL(value)
-
 	arg := db.UpdateProfilePictureParams{
 		UserID: req.UserID,
 		Picture: pgtype.Text{
 			String: profileImageFile,
 			Valid:  profileImageFile != "",
 		},
+		PictureVariants: pictureVariants,
 	}
 
 	profileImage, err := server.store.UpdateProfilePicture(ctx, db.UpdateProfilePictureParams(arg))
@@ -250,8 +194,9 @@ func (server *Server) DeleteProfilePicture(ctx *gin.Context) {
 	//assign url value to var
 	value := getPPImage.Picture
 
-	//remove exiting file
+	//remove exiting files
 	util.DeleteFileByURL(value)
+	deleteImageVariants(getPPImage.PictureVariants)
 
 	errors := server.store.DeleteProfilePicture(ctx, req.UserID)
 
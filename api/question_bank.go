@@ -0,0 +1,271 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/quiz"
+	"eduApp/token"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateQuestionBankRequest defines the request body structure for creating a question bank
+type CreateQuestionBankRequest struct {
+	CourseID int64  `json:"course_id" binding:"required,min=1"`
+	Title    string `json:"title" binding:"required"`
+}
+
+// @Summary Create a question bank
+// @Description Create a question bank for a course
+// @ID create-question-bank
+// @Accept json
+// @Produce json
+// @Param request body CreateQuestionBankRequest true "Create Question Bank Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /questionbank [post]
+func (server *Server) CreateQuestionBank(ctx *gin.Context) {
+	var req CreateQuestionBankRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	bank, err := server.store.CreateQuestionBank(ctx, db.CreateQuestionBankParams{
+		CourseID: req.CourseID,
+		Title:    req.Title,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bank)
+}
+
+// ListQuestionBanksRequest contains the input parameters for listing question banks
+type ListQuestionBanksRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List question banks
+// @Description List the question banks of a course
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /questionbank/list [get]
+func (server *Server) ListQuestionBanks(ctx *gin.Context) {
+	var req ListQuestionBanksRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	banks, err := server.store.ListQuestionBanks(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, banks)
+}
+
+// DeleteQuestionBankRequest defines the request structure for deleting a question bank
+type DeleteQuestionBankRequest struct {
+	BankID int64 `form:"bank_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a question bank
+// @Description Delete a question bank together with its questions
+// @Produce json
+// @Param bank_id query int true "Bank ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /questionbank/delete [delete]
+func (server *Server) DeleteQuestionBank(ctx *gin.Context) {
+	var req DeleteQuestionBankRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteQuestionBank(ctx, req.BankID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Question bank deleted successfully"})
+}
+
+// CreateQuestionRequest defines the request body structure for adding a question to a bank
+type CreateQuestionRequest struct {
+	BankID       int64          `json:"bank_id" binding:"required,min=1"`
+	QuestionType string         `json:"question_type" binding:"required"`
+	Prompt       string         `json:"prompt" binding:"required"`
+	Options      quiz.Options   `json:"options"`
+	AnswerKey    quiz.AnswerKey `json:"answer_key"`
+	Points       int64          `json:"points" binding:"omitempty,min=1"`
+}
+
+// @Summary Create a question
+// @Description Add a question to a question bank, the answer key is checked against the question type
+// @ID create-question
+// @Accept json
+// @Produce json
+// @Param request body CreateQuestionRequest true "Create Question Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /question [post]
+func (server *Server) CreateQuestion(ctx *gin.Context) {
+	var req CreateQuestionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := quiz.Validate(req.QuestionType, req.Options, req.AnswerKey); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.Options == nil {
+		req.Options = quiz.Options{}
+	}
+	if req.Points == 0 {
+		req.Points = 1
+	}
+
+	question, err := server.store.CreateQuestion(ctx, db.CreateQuestionParams{
+		BankID:       req.BankID,
+		QuestionType: req.QuestionType,
+		Prompt:       req.Prompt,
+		Options:      req.Options,
+		AnswerKey:    req.AnswerKey,
+		Points:       req.Points,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, question)
+}
+
+// ListQuestionsRequest contains the input parameters for listing the questions of a bank
+type ListQuestionsRequest struct {
+	BankID int64 `form:"bank_id" binding:"required,min=1"`
+}
+
+// @Summary List questions
+// @Description List the questions of a bank including their answer keys
+// @Produce json
+// @Param bank_id query int true "Bank ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /question/list [get]
+func (server *Server) ListQuestions(ctx *gin.Context) {
+	var req ListQuestionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	questions, err := server.store.ListQuestionsByBank(ctx, req.BankID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, questions)
+}
+
+// DeleteQuestionRequest defines the request structure for deleting a question
+type DeleteQuestionRequest struct {
+	QuestionID int64 `form:"question_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a question
+// @Description Delete a question from its bank
+// @Produce json
+// @Param question_id query int true "Question ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /question/delete [delete]
+func (server *Server) DeleteQuestion(ctx *gin.Context) {
+	var req DeleteQuestionRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteQuestion(ctx, req.QuestionID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
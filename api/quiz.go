@@ -0,0 +1,636 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/quiz"
+	"eduApp/token"
+	"eduApp/xapi"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// attempt statuses stored in quiz_attempts.status
+const (
+	quizAttemptInProgress = "in_progress"
+	quizAttemptSubmitted  = "submitted"
+	quizAttemptExpired    = "expired"
+)
+
+// quizSubmitGracePeriod allows for network delay when an attempt is submitted right at its time limit
+const quizSubmitGracePeriod = 30 * time.Second
+
+// CreateQuizRequest defines the request body structure for creating a quiz
+type CreateQuizRequest struct {
+	CourseID         int64  `json:"course_id" binding:"required,min=1"`
+	MaterialID       int64  `json:"material_id" binding:"omitempty,min=1"`
+	Title            string `json:"title" binding:"required"`
+	TimeLimitSeconds int64  `json:"time_limit_seconds" binding:"min=0"`
+	MaxAttempts      int64  `json:"max_attempts" binding:"min=0"`
+}
+
+// @Summary Create a quiz
+// @Description Create a quiz for a course, optionally placed after a material. A zero time limit or attempt limit means unlimited
+// @ID create-quiz
+// @Accept json
+// @Produce json
+// @Param request body CreateQuizRequest true "Create Quiz Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /quiz [post]
+func (server *Server) CreateQuiz(ctx *gin.Context) {
+	var req CreateQuizRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	quizRecord, err := server.store.CreateQuiz(ctx, db.CreateQuizParams{
+		CourseID:         req.CourseID,
+		MaterialID:       pgtype.Int8{Int64: req.MaterialID, Valid: req.MaterialID != 0},
+		Title:            req.Title,
+		TimeLimitSeconds: req.TimeLimitSeconds,
+		MaxAttempts:      req.MaxAttempts,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// a new quiz lowers the progress of everyone who has not taken it yet
+	server.recomputeCourseProgress(ctx, quizRecord.CourseID)
+
+	ctx.JSON(http.StatusOK, quizRecord)
+}
+
+// CreateQuizPoolRequest defines the request body structure for adding a question pool to a quiz
+type CreateQuizPoolRequest struct {
+	QuizID    int64 `json:"quiz_id" binding:"required,min=1"`
+	BankID    int64 `json:"bank_id" binding:"required,min=1"`
+	DrawCount int64 `json:"draw_count" binding:"min=0"`
+}
+
+// @Summary Add a question pool to a quiz
+// @Description Every attempt draws draw_count random questions from the bank, or all of them when draw_count is zero
+// @ID create-quiz-pool
+// @Accept json
+// @Produce json
+// @Param request body CreateQuizPoolRequest true "Create Quiz Pool Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /quiz/pool [post]
+func (server *Server) CreateQuizPool(ctx *gin.Context) {
+	var req CreateQuizPoolRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	quizRecord, err := server.store.GetQuiz(ctx, req.QuizID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	bank, err := server.store.GetQuestionBank(ctx, req.BankID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if bank.CourseID != quizRecord.CourseID {
+		err := errors.New("question bank belongs to a different course")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	pool, err := server.store.CreateQuizPool(ctx, db.CreateQuizPoolParams{
+		QuizID:    req.QuizID,
+		BankID:    req.BankID,
+		DrawCount: req.DrawCount,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pool)
+}
+
+// GetQuizRequest contains the input parameters for getting a quiz
+type GetQuizRequest struct {
+	QuizID int64 `form:"quiz_id" binding:"required,min=1"`
+}
+
+// @Summary Get a quiz
+// @Description Get a quiz with its question pools
+// @Produce json
+// @Param quiz_id query int true "Quiz ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /quiz/get [get]
+func (server *Server) GetQuiz(ctx *gin.Context) {
+	var req GetQuizRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	quizRecord, err := server.store.GetQuiz(ctx, req.QuizID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	pools, err := server.store.ListQuizPools(ctx, req.QuizID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"quiz": quizRecord, "pools": pools})
+}
+
+// ListQuizzesRequest contains the input parameters for listing the quizzes of a course
+type ListQuizzesRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary List quizzes
+// @Description List the quizzes of a course
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /quiz/list [get]
+func (server *Server) ListQuizzes(ctx *gin.Context) {
+	var req ListQuizzesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	quizzes, err := server.store.ListQuizzesByCourse(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quizzes)
+}
+
+// @Summary Delete a quiz
+// @Description Delete a quiz together with its pools and attempts
+// @Produce json
+// @Param quiz_id query int true "Quiz ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /quiz/delete [delete]
+func (server *Server) DeleteQuiz(ctx *gin.Context) {
+	var req GetQuizRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	quizRecord, err := server.store.GetQuiz(ctx, req.QuizID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteQuiz(ctx, req.QuizID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.recomputeCourseProgress(ctx, quizRecord.CourseID)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Quiz deleted successfully"})
+}
+
+// quizQuestionResponse is a question as shown to a student, without its answer key
+type quizQuestionResponse struct {
+	QuestionID   int64        `json:"question_id"`
+	QuestionType string       `json:"question_type"`
+	Prompt       string       `json:"prompt"`
+	Options      quiz.Options `json:"options"`
+	Points       int64        `json:"points"`
+}
+
+// StartQuizAttemptRequest defines the request body structure for starting a quiz attempt
+type StartQuizAttemptRequest struct {
+	QuizID int64 `json:"quiz_id" binding:"required,min=1"`
+}
+
+// @Summary Start a quiz attempt
+// @Description Start an attempt with questions drawn from the quiz pools, an unfinished attempt is resumed instead
+// @ID start-quiz-attempt
+// @Accept json
+// @Produce json
+// @Param request body StartQuizAttemptRequest true "Start Quiz Attempt Request"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /quiz/attempt/start [post]
+func (server *Server) StartQuizAttempt(ctx *gin.Context) {
+	var req StartQuizAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	quizRecord, err := server.store.GetQuiz(ctx, req.QuizID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.requireCourseAccess(ctx, authPayload, quizRecord.CourseID) {
+		return
+	}
+
+	attempt, err := server.store.GetInProgressQuizAttempt(ctx, db.GetInProgressQuizAttemptParams{
+		QuizID: quizRecord.QuizID,
+		UserID: authPayload.UserID,
+	})
+	switch {
+	case err == nil && !attemptExpired(attempt, time.Now()):
+		server.respondWithAttempt(ctx, attempt)
+		return
+	case err == nil:
+		// close the timed out attempt so it counts towards the attempt limit
+		if _, err := server.closeQuizAttempt(ctx, quizRecord, attempt, nil); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	case !errors.Is(err, db.ErrRecordNotFound):
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	questionIDs, err := server.drawQuizQuestions(ctx, quizRecord.QuizID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if len(questionIDs) == 0 {
+		err := errors.New("quiz has no questions")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if quizRecord.TimeLimitSeconds > 0 {
+		expiresAt = pgtype.Timestamptz{
+			Time:  time.Now().Add(time.Duration(quizRecord.TimeLimitSeconds) * time.Second),
+			Valid: true,
+		}
+	}
+
+	result, err := server.store.StartQuizAttemptTx(ctx, db.StartQuizAttemptTxParams{
+		CreateQuizAttemptParams: db.CreateQuizAttemptParams{
+			QuizID:      quizRecord.QuizID,
+			UserID:      authPayload.UserID,
+			QuestionIds: questionIDs,
+			ExpiresAt:   expiresAt,
+		},
+		CourseID:    quizRecord.CourseID,
+		MaxAttempts: quizRecord.MaxAttempts,
+	})
+	switch {
+	case errors.Is(err, db.ErrNoQuizAttemptsLeft):
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	case errors.Is(err, db.ErrQuizAttemptInProgress):
+		// a concurrent start won, resume its attempt
+		result.QuizAttempt, err = server.store.GetInProgressQuizAttempt(ctx, db.GetInProgressQuizAttemptParams{
+			QuizID: quizRecord.QuizID,
+			UserID: authPayload.UserID,
+		})
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondWithAttempt(ctx, result.QuizAttempt)
+}
+
+// drawQuizQuestions picks the questions of a new attempt from every pool of the quiz
+func (server *Server) drawQuizQuestions(ctx *gin.Context, quizID int64) ([]int64, error) {
+	pools, err := server.store.ListQuizPools(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	seen := make(map[int64]bool)
+	var questionIDs []int64
+
+	for _, pool := range pools {
+		ids, err := server.store.ListQuestionIDsByBank(ctx, pool.BankID)
+		if err != nil {
+			return nil, err
+		}
+
+		// a bank used by several pools should not repeat questions
+		var available []int64
+		for _, id := range ids {
+			if !seen[id] {
+				available = append(available, id)
+			}
+		}
+
+		for _, id := range quiz.Draw(available, int(pool.DrawCount), rng) {
+			seen[id] = true
+			questionIDs = append(questionIDs, id)
+		}
+	}
+
+	rng.Shuffle(len(questionIDs), func(i, j int) {
+		questionIDs[i], questionIDs[j] = questionIDs[j], questionIDs[i]
+	})
+	return questionIDs, nil
+}
+
+// respondWithAttempt sends an attempt with its questions in the drawn order
+func (server *Server) respondWithAttempt(ctx *gin.Context, attempt db.QuizAttempt) {
+	questions, err := server.store.ListQuestionsByIDs(ctx, attempt.QuestionIds)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	byID := make(map[int64]db.Question, len(questions))
+	for _, question := range questions {
+		byID[question.QuestionID] = question
+	}
+
+	rsp := make([]quizQuestionResponse, 0, len(attempt.QuestionIds))
+	for _, id := range attempt.QuestionIds {
+		question, ok := byID[id]
+		if !ok {
+			continue
+		}
+		rsp = append(rsp, quizQuestionResponse{
+			QuestionID:   question.QuestionID,
+			QuestionType: question.QuestionType,
+			Prompt:       question.Prompt,
+			Options:      question.Options,
+			Points:       question.Points,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"attempt": attempt, "questions": rsp})
+}
+
+// SubmitQuizAttemptRequest defines the request body structure for submitting a quiz attempt
+type SubmitQuizAttemptRequest struct {
+	AttemptID int64          `json:"attempt_id" binding:"required,min=1"`
+	Responses quiz.Responses `json:"responses"`
+}
+
+// @Summary Submit a quiz attempt
+// @Description Grade an attempt and update the course marks. Answers sent after the time limit are not accepted
+// @ID submit-quiz-attempt
+// @Accept json
+// @Produce json
+// @Param request body SubmitQuizAttemptRequest true "Submit Quiz Attempt Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /quiz/attempt/submit [post]
+func (server *Server) SubmitQuizAttempt(ctx *gin.Context) {
+	var req SubmitQuizAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	attempt, err := server.store.GetQuizAttempt(ctx, req.AttemptID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if attempt.UserID != authPayload.UserID {
+		err := errors.New("not authorized to submit this attempt")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if attempt.Status != quizAttemptInProgress {
+		err := errors.New("attempt has already been submitted")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	quizRecord, err := server.store.GetQuiz(ctx, attempt.QuizID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	responses := req.Responses
+	if attemptExpired(attempt, time.Now()) {
+		responses = nil
+	}
+
+	result, err := server.closeQuizAttempt(ctx, quizRecord, attempt, responses)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("attempt has already been submitted")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// closeQuizAttempt grades the responses of an attempt and stores the result with the new course mark.
+// Attempts past their time limit are stored as expired.
+func (server *Server) closeQuizAttempt(ctx *gin.Context, quizRecord db.Quiz, attempt db.QuizAttempt, responses quiz.Responses) (db.SubmitQuizAttemptTxResult, error) {
+	questions, err := server.store.ListQuestionsByIDs(ctx, attempt.QuestionIds)
+	if err != nil {
+		return db.SubmitQuizAttemptTxResult{}, err
+	}
+
+	graded := make([]quiz.Question, len(questions))
+	for i, question := range questions {
+		graded[i] = quiz.Question{
+			ID:        question.QuestionID,
+			Type:      question.QuestionType,
+			AnswerKey: question.AnswerKey,
+			Points:    question.Points,
+		}
+	}
+
+	// keep only answers to the questions drawn for this attempt
+	kept := quiz.Responses{}
+	for _, id := range attempt.QuestionIds {
+		if response, ok := responses[id]; ok {
+			kept[id] = response
+		}
+	}
+
+	result := quiz.Grade(graded, kept)
+
+	status := quizAttemptSubmitted
+	if attemptExpired(attempt, time.Now()) {
+		status = quizAttemptExpired
+	}
+
+	txResult, err := server.store.SubmitQuizAttemptTx(ctx, db.SubmitQuizAttemptTxParams{
+		SubmitQuizAttemptParams: db.SubmitQuizAttemptParams{
+			AttemptID: attempt.AttemptID,
+			Responses: kept,
+			Score:     result.Score,
+			MaxScore:  result.MaxScore,
+			Status:    status,
+		},
+		CourseID:      quizRecord.CourseID,
+		AfterComplete: server.courseCompletionHook(ctx),
+	})
+	if err != nil {
+		return txResult, err
+	}
+
+	activities := server.xapiActivities()
+	server.distributeXAPIStatement(ctx, xapi.NewStatement(
+		activities.Agent(attempt.UserID, ""),
+		xapi.VerbScored,
+		activities.Quiz(quizRecord.QuizID, quizRecord.Title),
+	).WithScore(float64(result.Score), float64(result.MaxScore)).WithParent(activities.Course(quizRecord.CourseID, ""), xapiPlatform))
+	server.distributeLTIScore(ctx, txResult.Mark.CourseID, txResult.Mark.UserID)
+	server.publishProgress(ctx, txResult.CourseProgress)
+
+	return txResult, nil
+}
+
+// attemptExpired tells whether an attempt is past its time limit and grace period
+func attemptExpired(attempt db.QuizAttempt, now time.Time) bool {
+	return attempt.ExpiresAt.Valid && now.After(attempt.ExpiresAt.Time.Add(quizSubmitGracePeriod))
+}
+
+// ListQuizAttemptsRequest contains the input parameters for listing quiz attempts
+type ListQuizAttemptsRequest struct {
+	QuizID int64 `form:"quiz_id" binding:"required,min=1"`
+	UserID int64 `form:"user_id"`
+}
+
+// @Summary List quiz attempts
+// @Description List the attempts of the logged in user, admins can pass user_id to see another user's attempts
+// @Produce json
+// @Param quiz_id query int true "Quiz ID"
+// @Param user_id query int false "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /quiz/attempts [get]
+func (server *Server) ListQuizAttempts(ctx *gin.Context) {
+	var req ListQuizAttemptsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	userID := authPayload.UserID
+	if req.UserID != 0 && req.UserID != authPayload.UserID {
+		if authPayload.Role != "admin" {
+			err := errors.New("not authorized to access these attempts")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		userID = req.UserID
+	}
+
+	attempts, err := server.store.ListQuizAttemptsByUser(ctx, db.ListQuizAttemptsByUserParams{
+		QuizID: req.QuizID,
+		UserID: userID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attempts)
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// quizStore holds one quiz with a single question and the attempts of one student
+type quizStore struct {
+	db.Store
+
+	quiz         db.Quiz
+	subscription *db.Subscription
+	inProgress   *db.QuizAttempt
+	// startErr is returned by StartQuizAttemptTx, with raced set the attempt of a concurrent start appears first
+	startErr error
+	raced    *db.QuizAttempt
+	started  []db.StartQuizAttemptTxParams
+}
+
+func (store *quizStore) GetQuiz(ctx context.Context, quizID int64) (db.Quiz, error) {
+	if quizID != store.quiz.QuizID {
+		return db.Quiz{}, db.ErrRecordNotFound
+	}
+	return store.quiz, nil
+}
+
+func (store *quizStore) GetCourseSubscription(ctx context.Context, arg db.GetCourseSubscriptionParams) (db.Subscription, error) {
+	if store.subscription == nil {
+		return db.Subscription{}, db.ErrRecordNotFound
+	}
+	return *store.subscription, nil
+}
+
+func (store *quizStore) GetInProgressQuizAttempt(ctx context.Context, arg db.GetInProgressQuizAttemptParams) (db.QuizAttempt, error) {
+	if store.inProgress == nil {
+		return db.QuizAttempt{}, db.ErrRecordNotFound
+	}
+	return *store.inProgress, nil
+}
+
+func (store *quizStore) ListQuizPools(ctx context.Context, quizID int64) ([]db.QuizPool, error) {
+	return []db.QuizPool{{QuizID: quizID, BankID: 1, DrawCount: 1}}, nil
+}
+
+func (store *quizStore) ListQuestionIDsByBank(ctx context.Context, bankID int64) ([]int64, error) {
+	return []int64{7}, nil
+}
+
+func (store *quizStore) ListQuestionsByIDs(ctx context.Context, questionIDs []int64) ([]db.Question, error) {
+	return []db.Question{{QuestionID: 7, QuestionType: "true_false", Prompt: "Is it?", Points: 1}}, nil
+}
+
+func (store *quizStore) StartQuizAttemptTx(ctx context.Context, arg db.StartQuizAttemptTxParams) (db.StartQuizAttemptTxResult, error) {
+	store.started = append(store.started, arg)
+	if store.raced != nil {
+		store.inProgress = store.raced
+	}
+	if store.startErr != nil {
+		return db.StartQuizAttemptTxResult{}, store.startErr
+	}
+	return db.StartQuizAttemptTxResult{QuizAttempt: db.QuizAttempt{
+		AttemptID:   1,
+		QuizID:      arg.QuizID,
+		UserID:      arg.UserID,
+		QuestionIds: arg.QuestionIds,
+		Status:      "in_progress",
+	}}, nil
+}
+
+func startQuizAttempt(t *testing.T, store *quizStore, payload *token.Payload) (*httptest.ResponseRecorder, gin.H) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	server := &Server{store: store}
+	router := gin.New()
+	router.POST("/quiz/attempt/start", func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, payload)
+	}, server.StartQuizAttempt)
+
+	body, _ := json.Marshal(StartQuizAttemptRequest{QuizID: store.quiz.QuizID})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/quiz/attempt/start", bytes.NewReader(body)))
+
+	var response gin.H
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func TestStartQuizAttempt(t *testing.T) {
+	student := &token.Payload{UserID: 42, Role: "student"}
+	admin := &token.Payload{UserID: 1, Role: "admin"}
+	active := &db.Subscription{UserID: 42, CourseID: 3, Active: true}
+	expired := &db.Subscription{UserID: 42, CourseID: 3, Active: true,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}}
+	existing := &db.QuizAttempt{AttemptID: 9, QuizID: 5, UserID: 42, QuestionIds: []int64{7}, Status: "in_progress"}
+
+	testCases := []struct {
+		name         string
+		store        *quizStore
+		payload      *token.Payload
+		wantStatus   int
+		wantAttempt  float64
+		wantStarted  int
+		wantMaxLimit int64
+	}{
+		{name: "new attempt", store: &quizStore{subscription: active}, payload: student,
+			wantStatus: http.StatusOK, wantAttempt: 1, wantStarted: 1, wantMaxLimit: 2},
+		{name: "admin without a subscription", store: &quizStore{}, payload: admin,
+			wantStatus: http.StatusOK, wantAttempt: 1, wantStarted: 1, wantMaxLimit: 2},
+		{name: "attempt in progress is resumed", store: &quizStore{subscription: active, inProgress: existing}, payload: student,
+			wantStatus: http.StatusOK, wantAttempt: 9},
+		{name: "no subscription", store: &quizStore{}, payload: student, wantStatus: http.StatusUnauthorized},
+		{name: "inactive subscription", store: &quizStore{subscription: &db.Subscription{UserID: 42, CourseID: 3}}, payload: student,
+			wantStatus: http.StatusUnauthorized},
+		{name: "expired subscription", store: &quizStore{subscription: expired}, payload: student, wantStatus: http.StatusUnauthorized},
+		{name: "no attempts left", store: &quizStore{subscription: active, startErr: db.ErrNoQuizAttemptsLeft}, payload: student,
+			wantStatus: http.StatusForbidden, wantStarted: 1, wantMaxLimit: 2},
+		{name: "concurrent start is resumed", store: &quizStore{subscription: active, startErr: db.ErrQuizAttemptInProgress, raced: existing},
+			payload: student, wantStatus: http.StatusOK, wantAttempt: 9, wantStarted: 1, wantMaxLimit: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.store.quiz = db.Quiz{QuizID: 5, CourseID: 3, MaxAttempts: 2}
+			recorder, response := startQuizAttempt(t, tc.store, tc.payload)
+			if recorder.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if len(tc.store.started) != tc.wantStarted {
+				t.Fatalf("started %d attempts, want %d", len(tc.store.started), tc.wantStarted)
+			}
+			if tc.wantStarted > 0 {
+				started := tc.store.started[0]
+				if started.CourseID != 3 || started.MaxAttempts != tc.wantMaxLimit || started.UserID != tc.payload.UserID {
+					t.Errorf("StartQuizAttemptTx params = %+v, want the course, the limit and the user", started)
+				}
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			attempt, _ := response["attempt"].(map[string]any)
+			if attempt["attempt_id"] != tc.wantAttempt {
+				t.Errorf("attempt = %v, want attempt %v", attempt, tc.wantAttempt)
+			}
+		})
+	}
+}
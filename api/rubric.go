@@ -0,0 +1,406 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"eduApp/typetext"
+	"eduApp/util"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+// errGradesNotPublished is returned to students asking for feedback before the instructor publishes grades
+var errGradesNotPublished = errors.New("grades for this assignment have not been published yet")
+
+// hideUnpublishedGrade clears the grade and feedback of a submission until the assignment's grades are published
+func hideUnpublishedGrade(submission *db.Submission, assignment db.Assignment) {
+	if assignment.GradesPublishedAt.Valid {
+		return
+	}
+	submission.Grade = "null"
+	submission.RubricScores = typetext.RubricScores{}
+	submission.Feedback = ""
+	submission.GradedAt = pgtype.Timestamptz{}
+}
+
+// CreateRubricRequest defines the request body structure for attaching a rubric to an assignment
+type CreateRubricRequest struct {
+	AssignmentID int64                   `json:"assignment_id" binding:"required,min=1"`
+	Title        string                  `json:"title" binding:"required"`
+	Criteria     typetext.RubricCriteria `json:"criteria" binding:"required"`
+}
+
+// @Summary Create a rubric
+// @Description Attach a rubric to an assignment, an existing rubric of the assignment is replaced. Grades already given keep the criteria they were scored with
+// @ID create-rubric
+// @Accept json
+// @Produce json
+// @Param request body CreateRubricRequest true "Create Rubric Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /rubric [post]
+func (server *Server) CreateRubric(ctx *gin.Context) {
+	var req CreateRubricRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := util.ValidateRubric(req.Criteria); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rubric, err := server.store.UpsertRubric(ctx, db.UpsertRubricParams{
+		AssignmentID: req.AssignmentID,
+		Title:        strings.TrimSpace(req.Title),
+		Criteria:     req.Criteria,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rubric)
+}
+
+// GetRubricRequest contains the input parameters for getting the rubric of an assignment
+type GetRubricRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary Get a rubric
+// @Description Get the rubric of an assignment so students know how their work is graded
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /rubric [get]
+func (server *Server) GetRubric(ctx *gin.Context) {
+	var req GetRubricRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rubric, err := server.store.GetRubricByAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rubric)
+}
+
+// DeleteRubricRequest defines the request structure for removing the rubric of an assignment
+type DeleteRubricRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+}
+
+// @Summary Delete a rubric
+// @Description Remove the rubric of an assignment, grades already given are kept
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /rubric/delete [delete]
+func (server *Server) DeleteRubric(ctx *gin.Context) {
+	var req DeleteRubricRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteRubric(ctx, req.AssignmentID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Rubric deleted successfully"})
+}
+
+// GradeSubmissionWithRubricRequest defines the request body structure for grading a submission with the assignment rubric
+type GradeSubmissionWithRubricRequest struct {
+	SubmissionID int64                  `json:"submission_id" binding:"required,min=1"`
+	Scores       []util.RubricSelection `json:"scores" binding:"required"`
+	Feedback     string                 `json:"feedback"`
+}
+
+// @Summary Grade a submission with a rubric
+// @Description Pick a level for every criterion of the assignment rubric with an optional comment. The total of the levels, less the late penalty, becomes the grade
+// @ID grade-submission-rubric
+// @Accept json
+// @Produce json
+// @Param request body GradeSubmissionWithRubricRequest true "Grade Submission With Rubric Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /submission/grade/rubric [put]
+func (server *Server) GradeSubmissionWithRubric(ctx *gin.Context) {
+	var req GradeSubmissionWithRubricRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	submission, err := server.store.GetSubmissionByID(ctx, req.SubmissionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rubric, err := server.store.GetRubricByAssignment(ctx, submission.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err = errors.New("the assignment has no rubric")
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	scores, total, maxPoints, err := util.ScoreRubric(rubric.Criteria, req.Scores)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	grade := util.ApplyLatePenalty(float64(total), submission.PenaltyPercent)
+
+	submission, err = server.store.GradeSubmission(ctx, db.GradeSubmissionParams{
+		SubmissionID: req.SubmissionID,
+		Grade:        strconv.FormatFloat(grade, 'f', -1, 64),
+		RubricScores: scores,
+		Feedback:     strings.TrimSpace(req.Feedback),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.distributeSubmissionScored(ctx, submission, grade, float64(maxPoints))
+	server.distributeSubmissionGraded(ctx, submission)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"submission": submission,
+		"points":     total,
+		"max_points": maxPoints,
+	})
+}
+
+// PublishGradesRequest defines the request body structure for publishing the grades of an assignment
+type PublishGradesRequest struct {
+	AssignmentID int64 `json:"assignment_id" binding:"required,min=1"`
+	Published    bool  `json:"published"`
+}
+
+// @Summary Publish assignment grades
+// @Description Release the grades and feedback of an assignment to its students, or hide them again with published set to false
+// @ID publish-grades
+// @Accept json
+// @Produce json
+// @Param request body PublishGradesRequest true "Publish Grades Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /assignment/grades/publish [put]
+func (server *Server) PublishGrades(ctx *gin.Context) {
+	var req PublishGradesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	previous, err := server.store.GetAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	publishedAt := pgtype.Timestamptz{}
+	if req.Published {
+		publishedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+
+	assignment, err := server.store.SetAssignmentGradesPublished(ctx, db.SetAssignmentGradesPublishedParams{
+		AssignmentID:      req.AssignmentID,
+		GradesPublishedAt: publishedAt,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// publishing again after hiding the grades tells the students once more, the grades may have changed meanwhile
+	if !previous.GradesPublishedAt.Valid && assignment.GradesPublishedAt.Valid {
+		userIDs, err := server.store.ListGradedSubmissionUserIDs(ctx, assignment.AssignmentID)
+		if err != nil {
+			log.Error().Err(err).Int64("assignment_id", assignment.AssignmentID).Msg("failed to list graded students")
+		} else {
+			server.distributeGradeNotifications(ctx, assignment, userIDs)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, assignment)
+}
+
+// GetSubmissionFeedbackRequest contains the input parameters for reading the feedback of a submission
+type GetSubmissionFeedbackRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+	UserID       int64 `form:"user_id"`
+}
+
+// @Summary Get submission feedback
+// @Description Get the grade, rubric scores and feedback of a submission. Students only see them once the grades are published
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Param user_id query int false "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /submission/feedback [get]
+func (server *Server) GetSubmissionFeedback(ctx *gin.Context) {
+	var req GetSubmissionFeedbackRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.UserID == 0 {
+		req.UserID = authPayload.UserID
+	}
+	if req.UserID != authPayload.UserID && authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	assignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !assignment.GradesPublishedAt.Valid && authPayload.Role != "admin" {
+		ctx.JSON(http.StatusForbidden, errorResponse(errGradesNotPublished))
+		return
+	}
+
+	submission, err := server.store.GetSubmission(ctx, db.GetSubmissionParams{
+		AssignmentID: req.AssignmentID,
+		UserID:       req.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// a member of a group submission may have an individual adjustment on top of the group grade
+	adjustments, err := server.store.ListSubmissionAdjustments(ctx, submission.SubmissionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	var adjustment *db.SubmissionAdjustment
+	for i := range adjustments {
+		if adjustments[i].UserID == req.UserID {
+			adjustment = &adjustments[i]
+		}
+	}
+
+	var points, maxPoints int64
+	for _, score := range submission.RubricScores {
+		points += score.Points
+		maxPoints += score.MaxPoints
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"submission_id":   submission.SubmissionID,
+		"grade":           submission.Grade,
+		"graded_at":       submission.GradedAt,
+		"rubric_scores":   submission.RubricScores,
+		"points":          points,
+		"max_points":      maxPoints,
+		"penalty_percent": submission.PenaltyPercent,
+		"feedback":        submission.Feedback,
+		"adjustment":      adjustment,
+		"published_at":    assignment.GradesPublishedAt,
+	})
+}
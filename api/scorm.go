@@ -0,0 +1,371 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/scorm"
+	"eduApp/token"
+	"eduApp/util"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// scormDir is where extracted packages are stored, each in a folder of its own
+var scormDir = filepath.Join(util.UploadDir, "scorm")
+
+// extractSCORMPackage validates an uploaded package zip and extracts it to a new folder below scormDir.
+// The folder has to be removed by the caller when the package is not stored after all.
+func extractSCORMPackage(file multipart.File, header *multipart.FileHeader) (db.CreateSCORMPackageParams, error) {
+	if strings.ToLower(filepath.Ext(header.Filename)) != ".zip" {
+		return db.CreateSCORMPackageParams{}, fmt.Errorf("%w: expected a .zip file", scorm.ErrInvalidPackage)
+	}
+	if header.Size > scorm.MaxPackageSize {
+		return db.CreateSCORMPackageParams{}, fmt.Errorf("%w: larger than %d bytes", scorm.ErrInvalidPackage, scorm.MaxPackageSize)
+	}
+
+	// a zip is read from its end, so the upload is spooled to disk first
+	archive, err := os.CreateTemp("", "scorm-*.zip")
+	if err != nil {
+		return db.CreateSCORMPackageParams{}, err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if _, err := io.Copy(archive, io.LimitReader(file, scorm.MaxPackageSize)); err != nil {
+		return db.CreateSCORMPackageParams{}, err
+	}
+
+	dir := filepath.Join(scormDir, fmt.Sprintf("%d-%s", time.Now().Unix(), uuid.NewString()))
+	manifest, err := scorm.Extract(archive.Name(), dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return db.CreateSCORMPackageParams{}, err
+	}
+
+	return db.CreateSCORMPackageParams{
+		Version:    manifest.Version,
+		Title:      manifest.Title,
+		PackageDir: filepath.ToSlash(dir),
+		LaunchPath: manifest.LaunchPath,
+	}, nil
+}
+
+// removeSCORMPackage deletes the extracted files of a package
+func removeSCORMPackage(packageDir string) {
+	dir := filepath.Clean(filepath.FromSlash(packageDir))
+	// never follow a stored path out of scormDir
+	if !strings.HasPrefix(dir, scormDir+string(filepath.Separator)) {
+		return
+	}
+	os.RemoveAll(dir)
+}
+
+const (
+	// scormStaticPrefix is where the extracted packages are served below /static
+	scormStaticPrefix = "/static/scorm/"
+	// scormAdapterPath serves the run-time API the SCOs find in their parent window
+	scormAdapterPath = "/scorm/adapter"
+)
+
+var errSCORMNotConfigured = errors.New("SCORM packages cannot be played, no content origin is configured")
+
+// parseSCORMContentOrigin validates the origin the SCORM packages are served from and returns its host,
+// an empty origin leaves SCORM playback off
+func parseSCORMContentOrigin(origin string) (string, error) {
+	if origin == "" {
+		return "", nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.User != nil {
+		return "", fmt.Errorf("%q is not an origin like https://scorm.example.com", origin)
+	}
+	return u.Host, nil
+}
+
+// scormLaunchURL is the adapter page on the content origin that launches the SCO of a package
+func (server *Server) scormLaunchURL(pkg db.ScormPackage) string {
+	launch := util.StaticFileURL("", pkg.PackageDir) + "/" + pkg.LaunchPath
+	return strings.TrimRight(server.config.SCORMContentOrigin, "/") + scormAdapterPath + "?launch=" + url.QueryEscape(launch)
+}
+
+// SCORMPlayerRequest contains the input parameters for opening the player of a package
+type SCORMPlayerRequest struct {
+	MaterialID int64 `form:"material_id" binding:"required,min=1"`
+}
+
+// @Summary SCORM player
+// @Description Page a SCORM package plays in, to be embedded by the front end. The player asks the front end for the access
+// @Description token of the student with a {"type":"scorm:token"} message and expects {"type":"scorm:token","access_token":"..."}
+// @Description back. The package plays in a frame on the SCORM content origin and never sees the token.
+// @Produce html
+// @Param material_id query int true "Material ID"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Failure 503
+// @Router /scorm/player [get]
+// SCORMPlayer serves the page that plays a package and calls the runtime endpoints for it
+func (server *Server) SCORMPlayer(ctx *gin.Context) {
+	var req SCORMPlayerRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// the player only takes the token from the front end, so it has to know where the front end is
+	if server.contentHost == "" || server.config.FrontEndOrigin == "" {
+		ctx.JSON(http.StatusServiceUnavailable, errorResponse(errSCORMNotConfigured))
+		return
+	}
+
+	pkg, err := server.store.GetSCORMPackage(ctx, req.MaterialID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	frontEndOrigin := strings.TrimRight(server.config.FrontEndOrigin, "/")
+	contentOrigin := strings.TrimRight(server.config.SCORMContentOrigin, "/")
+	ctx.Header("Content-Security-Policy", "frame-src "+contentOrigin+"; frame-ancestors "+frontEndOrigin)
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	err = scorm.WritePlayer(ctx.Writer, pkg.Title, scorm.PlayerConfig{
+		MaterialID:     pkg.MaterialID,
+		RuntimeURL:     "/scorm/runtime",
+		FrontEndOrigin: frontEndOrigin,
+		ContentOrigin:  contentOrigin,
+	})
+	if err != nil {
+		ctx.Error(err)
+	}
+}
+
+// SCORMAdapterRequest contains the input parameters for launching a SCO in the adapter
+type SCORMAdapterRequest struct {
+	Launch string `form:"launch" binding:"required"`
+}
+
+// @Summary SCORM adapter
+// @Description Page on the SCORM content origin providing the run-time API to a SCO. It gets the data model from the
+// @Description player embedding it and sends the values the SCO sets back to it, it holds no session.
+// @Produce html
+// @Param launch query string true "Path of the SCO below /static/scorm/"
+// @Success 200
+// @Failure 400
+// @Router /scorm/adapter [get]
+// SCORMAdapter serves the page a SCO is launched in
+func (server *Server) SCORMAdapter(ctx *gin.Context) {
+	var req SCORMAdapterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// only the package files next to the adapter are launched
+	launch, err := url.Parse(req.Launch)
+	if err != nil || launch.Scheme != "" || launch.Host != "" || launch.User != nil ||
+		!strings.HasPrefix(path.Clean(launch.Path), scormStaticPrefix) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("launch must be a package file below "+scormStaticPrefix)))
+		return
+	}
+
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	err = scorm.WriteAdapter(ctx.Writer, scorm.AdapterConfig{LaunchURL: launch.String()})
+	if err != nil {
+		ctx.Error(err)
+	}
+}
+
+// GetSCORMRuntimeRequest contains the input parameters for loading the data model of a package
+type GetSCORMRuntimeRequest struct {
+	MaterialID int64 `form:"material_id" binding:"required,min=1"`
+}
+
+// scormRuntimeResponse is what the run-time API needs to launch a SCO
+type scormRuntimeResponse struct {
+	Version   string    `json:"version"`
+	LaunchURL string    `json:"launch_url"`
+	Values    scorm.CMI `json:"values"`
+}
+
+// @Summary Load SCORM runtime data
+// @Description Returns the version and launch URL of a package and the data model of the authenticated student,
+// @Description restored from their previous sessions
+// @Produce json
+// @Param material_id query int true "Material ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /scorm/runtime [get]
+// GetSCORMRuntime loads the data model a SCO starts with
+func (server *Server) GetSCORMRuntime(ctx *gin.Context) {
+	var req GetSCORMRuntimeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	material, err := server.store.GetMaterialByID(ctx, req.MaterialID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.requireCourseAccess(ctx, authPayload, material.CourseID) {
+		return
+	}
+
+	pkg, err := server.store.GetSCORMPackage(ctx, req.MaterialID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(db.ErrNotSCORM))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	attempt, err := server.store.GetSCORMAttempt(ctx, db.GetSCORMAttemptParams{
+		UserID:     authPayload.UserID,
+		MaterialID: req.MaterialID,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	learner := scorm.Learner{
+		ID:   strconv.FormatInt(authPayload.UserID, 10),
+		Name: authPayload.UserName,
+	}
+	ctx.JSON(http.StatusOK, scormRuntimeResponse{
+		Version:   pkg.Version,
+		LaunchURL: server.scormLaunchURL(pkg),
+		Values:    scorm.Initial(pkg.Version, attempt.Cmi, learner, attempt.TotalSeconds),
+	})
+}
+
+// CommitSCORMRuntimeRequest defines the request body structure for committing SCORM runtime data
+type CommitSCORMRuntimeRequest struct {
+	MaterialID int64             `json:"material_id" binding:"required,min=1"`
+	Values     map[string]string `json:"values"`
+	// Finish is set when the SCO terminated its session
+	Finish bool `json:"finish"`
+}
+
+// scormAttemptResponse is the state of a student's attempt after a commit
+type scormAttemptResponse struct {
+	MaterialID   int64    `json:"material_id"`
+	LessonStatus string   `json:"lesson_status"`
+	Score        *float64 `json:"score"`
+	TotalSeconds float64  `json:"total_seconds"`
+	Completed    bool     `json:"completed"`
+}
+
+// @Summary Commit SCORM runtime data
+// @Description Stores the data model elements a SCO set since its previous commit. A lesson status of passed or completed
+// @Description completes the lesson and the best score reported counts towards the course mark.
+// @Accept json
+// @Produce json
+// @Param request body CommitSCORMRuntimeRequest true "Commit SCORM Runtime Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /scorm/runtime [put]
+// CommitSCORMRuntime stores the runtime data of the authenticated student
+func (server *Server) CommitSCORMRuntime(ctx *gin.Context) {
+	var req CommitSCORMRuntimeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	material, err := server.store.GetMaterialByID(ctx, req.MaterialID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.requireCourseAccess(ctx, authPayload, material.CourseID) {
+		return
+	}
+
+	result, err := server.store.CommitSCORMRuntimeTx(ctx, db.CommitSCORMRuntimeTxParams{
+		UserID:        authPayload.UserID,
+		MaterialID:    req.MaterialID,
+		Values:        req.Values,
+		Finish:        req.Finish,
+		AfterComplete: server.courseCompletionHook(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrNotSCORM) || errors.Is(err, scorm.ErrInvalidElement) || errors.Is(err, scorm.ErrInvalidValue) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.Completed {
+		server.distributeLessonCompleted(ctx, db.LessonCompletion{
+			UserID:     authPayload.UserID,
+			CourseID:   material.CourseID,
+			MaterialID: req.MaterialID,
+		}, authPayload.UserName)
+	}
+	if result.MarkUpdated {
+		server.distributeLTIScore(ctx, material.CourseID, authPayload.UserID)
+	}
+	server.publishProgress(ctx, result.CourseProgress)
+
+	var score *float64
+	if result.Attempt.Score.Valid {
+		score = &result.Attempt.Score.Float64
+	}
+	ctx.JSON(http.StatusOK, scormAttemptResponse{
+		MaterialID:   req.MaterialID,
+		LessonStatus: result.Attempt.LessonStatus,
+		Score:        score,
+		TotalSeconds: result.Attempt.TotalSeconds,
+		Completed:    result.Attempt.CompletedAt.Valid,
+	})
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scormStore holds the package of material 5
+type scormStore struct {
+	db.Store
+}
+
+func (store *scormStore) GetSCORMPackage(ctx context.Context, materialID int64) (db.ScormPackage, error) {
+	if materialID != 5 {
+		return db.ScormPackage{}, db.ErrRecordNotFound
+	}
+	return db.ScormPackage{MaterialID: 5, Version: "1.2", Title: "Safety", PackageDir: "uploads/scorm/1-abc", LaunchPath: "index.html"}, nil
+}
+
+func TestSCORMContentMiddleware(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentHost string
+		host        string
+		path        string
+		status      int
+	}{
+		{name: "PackageOnContentHost", contentHost: "scorm.example.com", host: "scorm.example.com", path: "/static/scorm/1-abc/index.html", status: http.StatusOK},
+		{name: "AdapterOnContentHost", contentHost: "scorm.example.com", host: "SCORM.example.com", path: "/scorm/adapter", status: http.StatusOK},
+		{name: "PackageOnAPIHost", contentHost: "scorm.example.com", host: "api.example.com", path: "/static/scorm/1-abc/index.html", status: http.StatusNotFound},
+		{name: "DotDotPackageOnAPIHost", contentHost: "scorm.example.com", host: "api.example.com", path: "/static/images/../scorm/1-abc/index.html", status: http.StatusNotFound},
+		{name: "AdapterOnAPIHost", contentHost: "scorm.example.com", host: "api.example.com", path: "/scorm/adapter", status: http.StatusNotFound},
+		{name: "APIOnContentHost", contentHost: "scorm.example.com", host: "scorm.example.com", path: "/courses", status: http.StatusNotFound},
+		{name: "UploadOnContentHost", contentHost: "scorm.example.com", host: "scorm.example.com", path: "/static/images/a.png", status: http.StatusNotFound},
+		{name: "APIOnAPIHost", contentHost: "scorm.example.com", host: "api.example.com", path: "/courses", status: http.StatusOK},
+		{name: "UploadOnAPIHost", contentHost: "scorm.example.com", host: "api.example.com", path: "/static/images/a.png", status: http.StatusOK},
+		{name: "PackageUnconfigured", host: "api.example.com", path: "/static/scorm/1-abc/index.html", status: http.StatusNotFound},
+		{name: "APIUnconfigured", host: "api.example.com", path: "/courses", status: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(scormContentMiddleware(tc.contentHost))
+			ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+			router.GET("/static/*file", ok)
+			router.GET("/scorm/adapter", ok)
+			router.GET("/courses", ok)
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.URL.Path = tc.path
+			request.Host = tc.host
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != tc.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tc.status)
+			}
+		})
+	}
+}
+
+func TestParseSCORMContentOrigin(t *testing.T) {
+	testCases := []struct {
+		origin string
+		host   string
+		valid  bool
+	}{
+		{origin: "", host: "", valid: true},
+		{origin: "https://scorm.example.com", host: "scorm.example.com", valid: true},
+		{origin: "http://localhost:3391/", host: "localhost:3391", valid: true},
+		{origin: "scorm.example.com", valid: false},
+		{origin: "ftp://scorm.example.com", valid: false},
+		{origin: "https://scorm.example.com/packages", valid: false},
+		{origin: "https://user@scorm.example.com", valid: false},
+	}
+
+	for _, tc := range testCases {
+		host, err := parseSCORMContentOrigin(tc.origin)
+		if (err == nil) != tc.valid {
+			t.Errorf("parseSCORMContentOrigin(%q) error = %v, want valid %v", tc.origin, err, tc.valid)
+			continue
+		}
+		if host != tc.host {
+			t.Errorf("parseSCORMContentOrigin(%q) = %q, want %q", tc.origin, host, tc.host)
+		}
+	}
+}
+
+func TestSCORMPlayer(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config util.Config
+		status int
+	}{
+		{
+			name:   "Configured",
+			config: util.Config{FrontEndOrigin: "https://app.example.com", SCORMContentOrigin: "https://scorm.example.com"},
+			status: http.StatusOK,
+		},
+		{name: "NoContentOrigin", config: util.Config{FrontEndOrigin: "https://app.example.com"}, status: http.StatusServiceUnavailable},
+		{name: "NoFrontEndOrigin", config: util.Config{SCORMContentOrigin: "https://scorm.example.com"}, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			contentHost, err := parseSCORMContentOrigin(tc.config.SCORMContentOrigin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := &Server{config: tc.config, store: &scormStore{}, contentHost: contentHost}
+			router := gin.New()
+			router.GET("/scorm/player", server.SCORMPlayer)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/scorm/player?material_id=5", nil))
+			if recorder.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.status, recorder.Body)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			body := recorder.Body.String()
+			if strings.Contains(body, "access_token=") || strings.Contains(body, "location.hash") {
+				t.Error("player reads tokens from its URL")
+			}
+			if !strings.Contains(body, `sandbox="allow-scripts allow-same-origin allow-forms allow-popups"`) {
+				t.Error("package frame is not sandboxed")
+			}
+			want := "frame-src https://scorm.example.com; frame-ancestors https://app.example.com"
+			if csp := recorder.Header().Get("Content-Security-Policy"); csp != want {
+				t.Errorf("Content-Security-Policy = %q, want %q", csp, want)
+			}
+		})
+	}
+}
+
+func TestSCORMLaunchURL(t *testing.T) {
+	server := &Server{config: util.Config{SCORMContentOrigin: "https://scorm.example.com/"}}
+	pkg, _ := (&scormStore{}).GetSCORMPackage(context.Background(), 5)
+
+	launchURL, err := url.Parse(server.scormLaunchURL(pkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if launchURL.Scheme != "https" || launchURL.Host != "scorm.example.com" || launchURL.Path != scormAdapterPath {
+		t.Errorf("launch URL = %s, want the adapter on the content origin", launchURL)
+	}
+	if launch := launchURL.Query().Get("launch"); launch != "/static/scorm/1-abc/index.html" {
+		t.Errorf("launch = %q, want /static/scorm/1-abc/index.html", launch)
+	}
+}
+
+func TestSCORMAdapter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		launch string
+		status int
+	}{
+		{name: "PackageFile", launch: "/static/scorm/1-abc/index.html", status: http.StatusOK},
+		{name: "PackageFileWithQuery", launch: "/static/scorm/1-abc/index.html?lesson=2", status: http.StatusOK},
+		{name: "Missing", launch: "", status: http.StatusBadRequest},
+		{name: "OtherOrigin", launch: "https://evil.example.com/static/scorm/1-abc/index.html", status: http.StatusBadRequest},
+		{name: "SchemeRelative", launch: "//evil.example.com/static/scorm/1-abc/index.html", status: http.StatusBadRequest},
+		{name: "Script", launch: "javascript:alert(1)", status: http.StatusBadRequest},
+		{name: "OutsidePackages", launch: "/static/scorm/../images/a.html", status: http.StatusBadRequest},
+		{name: "APIRoute", launch: "/scorm/runtime", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			server := &Server{}
+			router := gin.New()
+			router.GET("/scorm/adapter", server.SCORMAdapter)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/scorm/adapter?launch="+url.QueryEscape(tc.launch), nil))
+			if recorder.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.status, recorder.Body)
+			}
+			if tc.status == http.StatusOK && strings.Contains(recorder.Body.String(), "Authorization") {
+				t.Error("adapter handles the session")
+			}
+		})
+	}
+}
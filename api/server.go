@@ -1,10 +1,15 @@
 package api
 
 import (
+	"eduApp/credential"
 	db "eduApp/db/sqlc"
+	"eduApp/lti"
+	"eduApp/payments"
+	"eduApp/realtime"
 	"eduApp/token"
 	"eduApp/util"
 	"eduApp/worker"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -13,6 +18,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/go-redis/redis/v8"
 )
 
 // server serves hhtp requests
@@ -22,6 +28,12 @@ type Server struct {
 	tokenMaker      token.Maker
 	router          *gin.Engine
 	taskDistributor worker.TaskDistributor
+	paymentProvider payments.Provider
+	badgeIssuer     *credential.Issuer
+	ltiTool         *lti.Tool
+	events          realtime.Broker
+	// contentHost serves the SCORM packages and nothing else, empty when no content origin is configured
+	contentHost string
 }
 
 // NewServer creates a http server and setup routing
@@ -31,11 +43,39 @@ func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDi
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	paymentProvider, err := payments.NewProvider(config.PaymentProvider, config.PaymentSecretKey, config.PaymentWebhookSecret)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create payment provider: %w", err)
+	}
+
+	// verifiable credentials are optional, without a signing key the badge endpoints answer 503
+	badgeIssuer, err := credential.NewIssuer(config.BadgeBaseURL, config.BadgeIssuerName, config.BadgeSigningKey)
+	if err != nil && !errors.Is(err, credential.ErrNotConfigured) {
+		return nil, fmt.Errorf("cannot create badge issuer: %w", err)
+	}
+
+	// LTI is optional too, without a tool key the LTI endpoints answer 503
+	ltiTool, err := lti.NewTool(config.LTIToolURL, config.LTIPrivateKey)
+	if err != nil && !errors.Is(err, lti.ErrNotConfigured) {
+		return nil, fmt.Errorf("cannot create LTI tool: %w", err)
+	}
+
+	// SCORM packages are played from an origin of their own, without one the player answers 503
+	scormContentHost, err := parseSCORMContentOrigin(config.SCORMContentOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("cannot use SCORM content origin: %w", err)
+	}
+
 	server := &Server{
 		config:          config,
 		store:           store,
 		tokenMaker:      tokenMaker,
 		taskDistributor: taskDistributor,
+		paymentProvider: paymentProvider,
+		badgeIssuer:     badgeIssuer,
+		ltiTool:         ltiTool,
+		events:          realtime.NewRedisBroker(&redis.Options{Addr: config.RedisAddress}),
+		contentHost:     scormContentHost,
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -56,6 +96,7 @@ func (server *Server) setupRouter() {
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	router.Use(scormContentMiddleware(server.contentHost))
 
 	grp := router.Group("/static")
 	{
@@ -102,6 +143,9 @@ func (server *Server) setupRouter() {
 	router.GET("/courses", server.ListCourses)
 	authroute.PATCH("/course/edit", server.UpdateCourse)
 	authroute.DELETE("/course/delete", server.DeleteCourse)
+	authroute.GET("/course/:id/gradebook", server.GetGradebook)
+	authroute.GET("/course/:id/gradebook/export", server.ExportGradebook)
+	authroute.POST("/course/:id/gradebook/import", server.ImportGradebookScores)
 	router.GET("/list/catagories", server.ListAllCourseCatagories)
 
 	//Assignment
@@ -109,6 +153,46 @@ func (server *Server) setupRouter() {
 	authroute.GET("/assignment/get", server.getAssignment)
 	authroute.PATCH("/assignments/edit", server.UpdateAssignment)
 	authroute.DELETE("/assignment/delete", server.deleteAssignment)
+	authroute.GET("/assignments/list", server.ListAssignments)
+	authroute.POST("/assignment/extension", server.CreateAssignmentExtension)
+	authroute.GET("/assignment/extensions", server.ListAssignmentExtensions)
+	authroute.DELETE("/assignment/extension/delete", server.DeleteAssignmentExtension)
+	authroute.PUT("/assignment/grades/publish", server.PublishGrades)
+
+	// Gradebook
+	authroute.POST("/gradebook/category", server.CreateGradeCategory)
+	authroute.PUT("/gradebook/category/edit", server.UpdateGradeCategory)
+	authroute.DELETE("/gradebook/category/delete", server.DeleteGradeCategory)
+	authroute.POST("/gradebook/item", server.CreateGradeItem)
+	authroute.PUT("/gradebook/item/edit", server.UpdateGradeItem)
+	authroute.DELETE("/gradebook/item/delete", server.DeleteGradeItem)
+	authroute.PUT("/gradebook/score", server.SetGradeItemScore)
+	authroute.PUT("/gradebook/scale", server.SetGradeScale)
+
+	// Rubrics
+	authroute.POST("/rubric", server.CreateRubric)
+	authroute.GET("/rubric", server.GetRubric)
+	authroute.DELETE("/rubric/delete", server.DeleteRubric)
+
+	// Groups
+	authroute.POST("/group", server.CreateCourseGroup)
+	authroute.GET("/groups", server.ListCourseGroups)
+	authroute.GET("/group/mine", server.GetMyCourseGroup)
+	authroute.PATCH("/group/edit", server.UpdateCourseGroup)
+	authroute.DELETE("/group/delete", server.DeleteCourseGroup)
+	authroute.POST("/group/member", server.AddCourseGroupMember)
+	authroute.DELETE("/group/member/delete", server.RemoveCourseGroupMember)
+	authroute.POST("/groups/generate", server.GenerateCourseGroups)
+
+	// Peer review
+	authroute.PUT("/peer-review/settings", server.SetPeerReviewSettings)
+	authroute.GET("/peer-review/settings", server.GetPeerReviewSettings)
+	authroute.DELETE("/peer-review/settings/delete", server.DeletePeerReviewSettings)
+	authroute.GET("/peer-review/assigned", server.ListAssignedPeerReviews)
+	authroute.GET("/peer-review/file", server.GetPeerReviewFile)
+	authroute.PUT("/peer-review/submit", server.SubmitPeerReview)
+	authroute.GET("/peer-review/received", server.ListReceivedPeerReviews)
+	authroute.GET("/peer-review/results", server.GetPeerReviewResults)
 
 	//category
 	authroute.POST("/category", server.CreateCategory)
@@ -122,7 +206,15 @@ func (server *Server) setupRouter() {
 	authroute.GET("/submission/byassignment", server.GetSubmissionsByAssignment)
 	authroute.GET("/submission/byuser", server.GetSubmissionsByUser)
 	authroute.GET("/submissions", server.listSubmissions)
-	authroute.PUT("/submission/edit", server.UpdateSubmission)
+	authroute.PUT("/submission/edit", server.CreateSubmission)
+	authroute.GET("/submission/attempts", server.ListSubmissionAttempts)
+	authroute.PUT("/submission/graded-attempt", server.SelectGradedAttempt)
+	authroute.PUT("/submission/grade", server.GradeSubmission)
+	authroute.PUT("/submission/grade/rubric", server.GradeSubmissionWithRubric)
+	authroute.GET("/submission/feedback", server.GetSubmissionFeedback)
+	authroute.GET("/submission/similarity", server.ListSimilarityReports)
+	authroute.PUT("/submission/adjustment", server.SetSubmissionAdjustment)
+	authroute.DELETE("/submission/adjustment/delete", server.DeleteSubmissionAdjustment)
 	authroute.DELETE("/submission/delete", server.DeleteSubmission)
 
 	//Materials
@@ -149,22 +241,133 @@ func (server *Server) setupRouter() {
 	authroute.GET("/marks/bycourse", server.ListMarks)
 	authroute.PUT("/mark/edit", server.UpdateMark)
 
+	// Quizzes
+	authroute.POST("/questionbank", server.CreateQuestionBank)
+	authroute.GET("/questionbank/list", server.ListQuestionBanks)
+	authroute.DELETE("/questionbank/delete", server.DeleteQuestionBank)
+	authroute.POST("/question", server.CreateQuestion)
+	authroute.GET("/question/list", server.ListQuestions)
+	authroute.DELETE("/question/delete", server.DeleteQuestion)
+	authroute.POST("/quiz", server.CreateQuiz)
+	authroute.POST("/quiz/pool", server.CreateQuizPool)
+	authroute.GET("/quiz/get", server.GetQuiz)
+	authroute.GET("/quiz/list", server.ListQuizzes)
+	authroute.DELETE("/quiz/delete", server.DeleteQuiz)
+	authroute.POST("/quiz/attempt/start", server.StartQuizAttempt)
+	authroute.POST("/quiz/attempt/submit", server.SubmitQuizAttempt)
+	authroute.GET("/quiz/attempts", server.ListQuizAttempts)
+
 	// Progress
 	router.POST("/createprogress", server.createCourseProgress)
 	authroute.GET("/progress/get", server.getCourseProgress)
 	authroute.GET("/progress/list", server.ListCourseProgressByUser)
 	authroute.DELETE("/courseProgress/delete ", server.DeleteCourseProgress)
+	authroute.PUT("/progress/weights", server.SetCourseProgressWeights)
+	authroute.GET("/progress/weights", server.GetCourseProgressWeights)
 
 	//router.PUT("/progress/edit", server.UpdateCourseProgress)
 
+	// xAPI learning record store
+	router.GET("/xapi/about", server.XAPIAbout)
+	xapiroute := router.Group("/xapi").Use(xapiMiddleware(server.config))
+	xapiroute.PUT("/statements", server.PutXAPIStatement)
+	xapiroute.POST("/statements", server.PostXAPIStatements)
+	xapiroute.GET("/statements", server.GetXAPIStatements)
+
+	// Video watch time
+	authroute.POST("/video/heartbeat", server.VideoHeartbeat)
+	authroute.GET("/video/progress", server.GetVideoProgress)
+	authroute.GET("/video/dropoff", server.ListVideoDropOff)
+
+	// Notifications
+	authroute.GET("/notifications", server.ListNotifications)
+	authroute.GET("/notifications/unread_count", server.CountUnreadNotifications)
+	authroute.PUT("/notifications/:notification_id/read", server.MarkNotificationRead)
+	authroute.PUT("/notifications/read_all", server.MarkAllNotificationsRead)
+	authroute.GET("/notifications/preferences", server.GetNotificationPreferences)
+	authroute.PUT("/notifications/preferences", server.UpdateNotificationPreference)
+	authroute.POST("/announcements", server.CreateAnnouncement)
+
+	// Email templates
+	authroute.GET("/emails/templates", server.ListEmailTemplates)
+	authroute.GET("/emails/preview", server.PreviewEmail)
+	authroute.PUT("/users/locale", server.UpdateUserLocale)
+
+	// Real-time events
+	router.GET("/events", eventStreamAuthMiddleware(server.tokenMaker, server.events), server.StreamEvents)
+	authroute.POST("/events/ticket", server.CreateStreamTicket)
+
+	// SCORM packages
+	router.GET("/scorm/player", server.SCORMPlayer)
+	router.GET("/scorm/adapter", server.SCORMAdapter)
+	authroute.GET("/scorm/runtime", server.GetSCORMRuntime)
+	authroute.PUT("/scorm/runtime", server.CommitSCORMRuntime)
+
 	//Subscription
 	authroute.POST("/subscription", server.CreateSubscription)
 	authroute.GET("/subscription/get", server.GetSubscription)
 	authroute.GET("/subscription/user", server.ListSubscriptionsByUser)
 	authroute.GET("/subscription/course", server.ListSubscriptionsByCourse)
 	authroute.PUT("/subscription/edit", server.UpdateSubscriptions)
+	authroute.PUT("/subscription/extend", server.ExtendSubscriptions)
 	authroute.GET("/count/course/subscription", server.GetUserCountForCertianCourse)
 
+	// Enrollment
+	authroute.PUT("/enrollment/settings", server.SetEnrollmentSettings)
+	authroute.GET("/enrollment/settings", server.GetEnrollmentSettings)
+	authroute.GET("/enrollment/pending", server.ListPendingEnrollments)
+	authroute.GET("/enrollment/waitlist", server.ListEnrollmentWaitlist)
+	authroute.PUT("/enrollment/decision", server.DecideEnrollment)
+	authroute.DELETE("/enrollment/unenroll", server.Unenroll)
+	authroute.POST("/enrollment/import", server.CreateEnrollmentImport)
+	authroute.GET("/enrollment/import", server.GetEnrollmentImport)
+	authroute.GET("/enrollment/imports", server.ListEnrollmentImports)
+	authroute.POST("/enrollment/invite", server.CreateCourseInvite)
+	authroute.GET("/enrollment/invites", server.ListCourseInvites)
+	authroute.PUT("/enrollment/invite/revoke", server.RevokeCourseInvite)
+	authroute.POST("/enrollment/invite/redeem", server.RedeemCourseInvite)
+
+	// Payments
+	router.GET("/payment/price", server.GetCoursePrice)
+	router.POST("/payment/webhook", server.PaymentWebhook)
+	authroute.PUT("/payment/price", server.SetCoursePrice)
+	authroute.DELETE("/payment/price", server.DeleteCoursePrice)
+	authroute.GET("/payment/quote", server.QuoteCourse)
+	authroute.POST("/payment/checkout", server.Checkout)
+	authroute.GET("/payment/orders", server.ListMyOrders)
+	authroute.GET("/payment/course/orders", server.ListCourseOrders)
+	authroute.PUT("/payment/refund", server.RefundOrder)
+	authroute.POST("/payment/coupon", server.CreateCoupon)
+	authroute.GET("/payment/coupons", server.ListCoupons)
+	authroute.DELETE("/payment/coupon", server.DeleteCoupon)
+
+	// Certificates
+	router.GET("/certificates/verify/:code", server.VerifyCertificate)
+	authroute.GET("/certificates", server.ListMyCertificates)
+
+	// Badges
+	router.GET("/badges/issuer", server.GetBadgeIssuer)
+	router.GET("/badges/class/:course_id", server.GetBadgeClass)
+	router.GET("/badges/revocations", server.GetBadgeRevocations)
+	router.GET("/badges/credential", server.GetBadgeCredential)
+	router.POST("/badges/verify", server.VerifyBadgeCredential)
+	authroute.GET("/badges", server.ListMyBadgeCredentials)
+	authroute.PUT("/badges/revoke", server.RevokeBadgeCredential)
+
+	// LTI 1.3 tool
+	router.GET("/lti/config", server.GetLTIConfig)
+	router.GET("/lti/jwks", server.GetLTIJWKS)
+	router.GET("/lti/login", server.LTILogin)
+	router.POST("/lti/login", server.LTILogin)
+	router.POST("/lti/launch", server.LTILaunch)
+	authroute.POST("/lti/deeplink", server.LTIDeepLink)
+	authroute.POST("/lti/platforms", server.CreateLTIPlatform)
+	authroute.GET("/lti/platforms", server.ListLTIPlatforms)
+	authroute.PUT("/lti/platforms/:platform_id", server.UpdateLTIPlatform)
+	authroute.DELETE("/lti/platforms/:platform_id", server.DeleteLTIPlatform)
+	authroute.PUT("/lti/platforms/:platform_id/courses/:course_id", server.AddLTIPlatformCourse)
+	authroute.DELETE("/lti/platforms/:platform_id/courses/:course_id", server.DeleteLTIPlatformCourse)
+
 	//Request
 	router.POST("/request/create", server.CreateRequest)
 	authroute.PUT("/request/edit", server.UpdateRequest)
@@ -180,6 +383,10 @@ func (server *Server) setupRouter() {
 	router.GET("/count/subscription", server.GetTotalSubscribedUserCount)
 	router.GET("/all/counts", server.counts)
 
+	//Files
+	authroute.GET("/admin/files/orphans", server.ListOrphanFiles)
+	authroute.POST("/admin/files/gc", server.CollectOrphanFiles)
+
 	server.router = router
 }
 
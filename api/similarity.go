@@ -0,0 +1,59 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSimilarityReportsRequest contains the input parameters for listing the similarity reports of an assignment
+type ListSimilarityReportsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
+	UserID       int64 `form:"user_id" binding:"omitempty,min=1"`
+}
+
+// @Summary List similarity reports
+// @Description List the submissions of an assignment that share passages, most similar first. Each report scores how much
+// @Description of the attempt was found in the matched attempt and quotes the matched passages. Pass user_id to see one student's reports
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Param user_id query int false "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /submission/similarity [get]
+func (server *Server) ListSimilarityReports(ctx *gin.Context) {
+	var req ListSimilarityReportsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	var reports []db.SimilarityReport
+	var err error
+	if req.UserID != 0 {
+		reports, err = server.store.ListSimilarityReportsByUser(ctx, db.ListSimilarityReportsByUserParams{
+			AssignmentID: req.AssignmentID,
+			UserID:       req.UserID,
+		})
+	} else {
+		reports, err = server.store.ListSimilarityReports(ctx, req.AssignmentID)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reports)
+}
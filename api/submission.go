@@ -1,10 +1,15 @@
 package api
 
 import (
+	"crypto/sha256"
 	"database/sql"
 	db "eduApp/db/sqlc"
 	"eduApp/token"
+	"eduApp/typetext"
 	"eduApp/util"
+	"eduApp/worker"
+	"eduApp/xapi"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -12,14 +17,19 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
 )
 
-// uploading a single file
-func (server *Server) uploadResource(file multipart.File, header *multipart.FileHeader) (string, error) {
+// uploading a single file, returns its URL with the sha256 checksum and size of the content
+func (server *Server) uploadResource(file multipart.File, header *multipart.FileHeader) (string, string, int64, error) {
 
 	// Convert file extension to lowercase
 	fileExt := strings.ToLower(filepath.Ext(header.Filename))
@@ -32,41 +42,46 @@ func (server *Server) uploadResource(file multipart.File, header *multipart.File
 		".txt":  true,
 		".docx": true,
 		".pdf":  true,
-		"jfif":  true,
+		".jfif": true,
 	}
 	if !allowedExtensions[fileExt] {
-		return "", fmt.Errorf("unsupported file extension: %s", fileExt)
+		return "", "", 0, fmt.Errorf("unsupported file extension: %s", fileExt)
 	}
 
-	// Generate unique filename
+	// Generate unique filename, uploads within the same second must not share a name
 	originalFileName := strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
-	now := time.Now()
-	filename := strings.ReplaceAll(strings.ToLower(originalFileName), " ", "-") + "-" + fmt.Sprintf("%v", now.Unix()) + fileExt
+	filename := strings.ReplaceAll(strings.ToLower(originalFileName), " ", "-") + "-" + uuid.NewString() + fileExt
 
 	// Create upload directory if it doesn't exist
 	uploadDir := "uploads/submissions"
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return "", err
+		return "", "", 0, err
 	}
 
 	// Create destination file path
 	filePath := filepath.Join(uploadDir, filename)
 
-	// Save uploaded file
-	out, err := os.Create(filePath)
+	// Save uploaded file, never overwriting an existing one
+	out, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
 	if err != nil {
-		return "", err
+		return "", "", 0, err
 	}
 	defer out.Close()
-	_, err = io.Copy(out, file)
+
+	// hash the content while it is written
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), file)
 	if err != nil {
-		return "", err
+		// the file was created by this upload, so removing it cannot touch another attempt
+		out.Close()
+		os.Remove(filePath)
+		return "", "", 0, err
 	}
 
 	serverAddress := server.config.FileSource
 	File := fmt.Sprintf("%s/%s", serverAddress, filePath)
 
-	return File, nil
+	return File, hex.EncodeToString(hash.Sum(nil)), size, nil
 }
 
 // CreateSubmissionRequest defines the request body structure for creating a submission
@@ -80,16 +95,18 @@ type CreateSubmissionRequest struct {
 }
 
 // @Summary Create a new Submission
-// @Description Creates a new submission by depend on assignment
+// @Description Uploads a new attempt for an assignment. Every upload is kept, up to the assignment's max attempts.
+// @Description For a group assignment the attempt is added to the submission of the student's group
 // @Accept json
 // @Produce json
 // @Param request body CreateSubmissionRequest true "assignmnet_id and user_id"
 // @Success 200
 // @Failure 400
+// @Failure 403
 // @Failure 404
 // @Failure 500
 // @Router /submission/create [post]
-// CreateSubmission creates a new submission
+// CreateSubmission creates a new submission attempt
 func (server *Server) CreateSubmission(ctx *gin.Context) {
 	var req CreateSubmissionRequest
 
@@ -98,35 +115,208 @@ func (server *Server) CreateSubmission(ctx *gin.Context) {
 		return
 	}
 
-	// Handle image upload (if included in the request)
-	var resourceFile string
-	file, header, err := ctx.Request.FormFile("resource")
-	if err == nil {
-		resourceFile, err = server.uploadResource(file, header)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.UserID == 0 {
+		req.UserID = authPayload.UserID
+	}
+	if req.UserID != authPayload.UserID && authPayload.Role != "admin" {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	assignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// only students whose access to the course has not ended can submit
+	if !server.requireCourseAccess(ctx, authPayload, assignment.CourseID) {
+		return
+	}
+
+	// members of a group share the group's submission
+	var groupID pgtype.Int8
+	if assignment.GroupSubmission {
+		group, err := server.store.GetCourseGroupByMember(ctx, db.GetCourseGroupByMemberParams{
+			CourseID: assignment.CourseID,
+			UserID:   req.UserID,
+		})
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			if errors.Is(err, db.ErrRecordNotFound) {
+				err = errors.New("this is a group assignment and you are not in a group of the course")
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
+		groupID = pgtype.Int8{Int64: group.GroupID, Valid: true}
+	}
+
+	submittedAt := time.Now()
+	late, err := server.checkLateSubmission(ctx, assignment, req.UserID, submittedAt)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// every attempt needs an uploaded file
+	file, header, err := ctx.Request.FormFile("resource")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "resource file is required"})
+		return
+	}
+
+	resourceFile, checksum, size, err := server.uploadResource(file, header)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
 
-	// Update request struct with filename (if uploaded)
+	// Update request struct with filename
 	req.Resource = resourceFile
 
-	arg := db.CreateSubmissionParams{
-		AssignmentID:     req.AssignmentID,
-		UserID:           req.UserID,
-		Submitted:        true,
-		Grade:            "null",
-		DateOfSubmission: time.Now(),
-		Resource:         resourceFile,
+	result, err := server.store.CreateSubmissionAttemptTx(ctx, db.CreateSubmissionAttemptTxParams{
+		AssignmentID:   req.AssignmentID,
+		UserID:         req.UserID,
+		GroupID:        groupID,
+		MaxAttempts:    assignment.MaxAttempts,
+		Resource:       resourceFile,
+		Checksum:       checksum,
+		Size:           size,
+		IsLate:         late.IsLate,
+		PenaltyPercent: late.PenaltyPercent,
+		SubmittedAt:    submittedAt,
+		CourseID:       assignment.CourseID,
+		AfterComplete:  server.courseCompletionHook(ctx),
+	})
+	if err != nil {
+		util.DeleteFileByURL(resourceFile)
+		if errors.Is(err, db.ErrMaxAttemptsReached) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// the upload is saved either way, a failed enqueue only leaves the attempt unchecked
+	err = server.taskDistributor.DistributeTaskCheckSubmissionSimilarity(ctx, &worker.PayloadCheckSubmissionSimilarity{
+		AttemptID: result.SubmissionAttempt.AttemptID,
+	}, asynq.MaxRetry(3), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Error().Err(err).Int64("attempt_id", result.SubmissionAttempt.AttemptID).Msg("failed to enqueue similarity check")
+	}
+	server.publishProgress(ctx, result.CourseProgress...)
+
+	activities := server.xapiActivities()
+	server.distributeXAPIStatement(ctx, xapi.NewStatement(
+		activities.Agent(req.UserID, ""),
+		xapi.VerbCompleted,
+		activities.Assignment(assignment.AssignmentID, assignment.Title),
+	).WithCompletion(true, nil).WithParent(activities.Course(assignment.CourseID, ""), xapiPlatform))
+
+	// a pinned graded attempt keeps its grade, which stays hidden until published
+	if authPayload.Role != "admin" {
+		hideUnpublishedGrade(&result.Submission, assignment)
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// checkLateSubmission applies the assignment late policy using the student's extended due date when they have one
+func (server *Server) checkLateSubmission(ctx *gin.Context, assignment db.Assignment, userID int64, submittedAt time.Time) (util.LateSubmission, error) {
+	var dueDate, cutoff time.Time
+	if assignment.DueDate.Valid {
+		dueDate = assignment.DueDate.Time
+	}
+	if assignment.LateCutoff.Valid {
+		cutoff = assignment.LateCutoff.Time
+	}
+
+	extension, err := server.store.GetAssignmentExtension(ctx, db.GetAssignmentExtensionParams{
+		AssignmentID: assignment.AssignmentID,
+		UserID:       userID,
+	})
+	if err == nil {
+		dueDate = extension.DueDate
+	} else if !errors.Is(err, db.ErrRecordNotFound) {
+		return util.LateSubmission{}, err
+	}
+
+	policy := util.LatePolicy{
+		Policy:         assignment.LatePolicy,
+		PenaltyPercent: assignment.LatePenaltyPercent,
+		Cutoff:         cutoff,
+	}
+	return policy.CheckSubmissionTime(dueDate, submittedAt)
+}
+
+// GradeSubmissionRequest defines the request body structure for grading a submission
+type GradeSubmissionRequest struct {
+	SubmissionID int64   `json:"submission_id" binding:"required,min=1"`
+	Score        float64 `json:"score" binding:"min=0"`
+	Feedback     string  `json:"feedback"`
+}
+
+// @Summary Grade a submission
+// @Description Store the grade and feedback of a submission, the late penalty recorded on the submission is deducted from the score
+// @ID grade-submission
+// @Accept json
+// @Produce json
+// @Param request body GradeSubmissionRequest true "Grade Submission Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /submission/grade [put]
+func (server *Server) GradeSubmission(ctx *gin.Context) {
+	var req GradeSubmissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	submission, err := server.store.GetSubmissionByID(ctx, req.SubmissionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	submission, err := server.store.CreateSubmission(ctx, db.CreateSubmissionParams(arg))
+	grade := util.ApplyLatePenalty(req.Score, submission.PenaltyPercent)
+
+	submission, err = server.store.GradeSubmission(ctx, db.GradeSubmissionParams{
+		SubmissionID: req.SubmissionID,
+		Grade:        strconv.FormatFloat(grade, 'f', -1, 64),
+		RubricScores: typetext.RubricScores{},
+		Feedback:     strings.TrimSpace(req.Feedback),
+	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	server.distributeSubmissionScored(ctx, submission, grade, 0)
+	server.distributeSubmissionGraded(ctx, submission)
+
 	ctx.JSON(http.StatusOK, submission)
 }
 
@@ -163,6 +353,16 @@ func (server *Server) GetSubmissionsByAssignment(ctx *gin.Context) {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		assignment, err := server.store.GetAssignment(ctx, submission.AssignmentID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		hideUnpublishedGrade(&submission, assignment)
+	}
+
 	ctx.JSON(http.StatusOK, submission)
 }
 
@@ -255,111 +455,220 @@ func (server *Server) listSubmissions(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, submissions)
 }
 
-// UpdateSubmissionRequest defines the request body structure for updating a submission
-type UpdateSubmissionRequest struct {
-	SubmissionID int64 `json:"submission_id"`
-	AssignmentID int64 `json:"assignment_id"`
-	UserID       int64 `json:"user_id"`
+// DeleteSubmissionRequest defines the request body structure for deleting a submission
+type DeleteSubmissionRequest struct {
+	AssignmentID int64 `form:"assignment_id"`
+	UserID       int64 `form:"user_id"`
 }
 
-// @Summary Update a submission
-// @ID update-submission
+// @Summary Delete a submission
+// @Description Delete a student's submission of an assignment with every attempt and its files
+// @ID delete-submission
 // @Accept  json
 // @Produce  json
-// @Param material_id path int true "Material ID"
-// @Param request body UpdateSubmissionRequest true "Update Submission Request"
+// @Param assignment_id query int true "Assignment ID"
+// @Param user_id query int true "User ID"
 // @Success 200
 // @Failure 400
+// @Failure 403
 // @Failure 404
 // @Failure 500
-// @Router /submission/edit [put]
-// UpdateSubmission updates a submission
-func (server *Server) UpdateSubmission(ctx *gin.Context) {
-	var req UpdateSubmissionRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// @Router /submission/delete [delete]
+// DeleteSubmission deletes a submission
+func (server *Server) DeleteSubmission(ctx *gin.Context) {
+	var req DeleteSubmissionRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+
+	// attempts are kept as history, only admins can remove a submission
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if req.UserID != authPayload.UserID {
-		err := errors.New("account doesn't belong to the authenticated user")
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	arg := db.UpdateSubmissionParams{
-		UserID:       req.UserID,
+	arg := db.GetSubmissionParams{
 		AssignmentID: req.AssignmentID,
-		SubmissionID: req.SubmissionID,
+		UserID:       req.UserID,
 	}
 
-	submission, err := server.store.UpdateSubmission(ctx, arg)
+	getSubmission, err := server.store.GetSubmission(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	attempts, err := server.store.ListSubmissionAttempts(ctx, getSubmission.SubmissionID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, submission)
+	// delete exactly the submission that was looked up, its attempts go with it
+	deleted, err := server.store.DeleteSubmission(ctx, getSubmission.SubmissionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Submission data Deletion Failed, Please try agian.!"})
+		return
+	}
+	if deleted == 0 {
+		// a concurrent request removed it first and took care of its files
+		err := errors.New("submission was already deleted")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	// the student no longer gets credit for the assignment
+	if assignment, err := server.store.GetAssignment(ctx, req.AssignmentID); err == nil {
+		server.recomputeCourseProgress(ctx, assignment.CourseID, req.UserID)
+	}
+
+	//remove the files of every attempt
+	util.DeleteFileByURL(getSubmission.Resource)
+	for _, attempt := range attempts {
+		if attempt.Resource != getSubmission.Resource {
+			util.DeleteFileByURL(attempt.Resource)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"messege": "Submission successfully deleted.!"})
 }
 
-// DeleteSubmissionRequest defines the request body structure for deleting a submission
-type DeleteSubmissionRequest struct {
-	AssignmentID int64 `form:"assignment_id"`
+// ListSubmissionAttemptsRequest contains the input parameters for listing a student's submission history
+type ListSubmissionAttemptsRequest struct {
+	AssignmentID int64 `form:"assignment_id" binding:"required,min=1"`
 	UserID       int64 `form:"user_id"`
 }
 
-// @Summary Delete a submission
-// @Description Delete a submission
-// @ID delete-submission
-// @Accept  json
-// @Produce  json
-// @Param submission_id path int true "Submission ID"
+// @Summary List submission attempts
+// @Description Lists every attempt a student uploaded for an assignment, admins can pass user_id to see another student's history
+// @Produce json
+// @Param assignment_id query int true "Assignment ID"
+// @Param user_id query int false "User ID"
 // @Success 200
 // @Failure 400
+// @Failure 403
 // @Failure 404
 // @Failure 500
-// @Router /submission/delete [delete]
-// DeleteSubmission deletes a submission
-func (server *Server) DeleteSubmission(ctx *gin.Context) {
-	var req DeleteSubmissionRequest
-
+// @Router /submission/attempts [get]
+func (server *Server) ListSubmissionAttempts(ctx *gin.Context) {
+	var req ListSubmissionAttemptsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if authPayload.UserID == req.UserID {
+	if req.UserID == 0 {
+		req.UserID = authPayload.UserID
+	}
+	if req.UserID != authPayload.UserID && authPayload.Role != "admin" {
 		err := errors.New("you are not an authorized user")
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	arg := db.GetSubmissionParams{
+	submission, err := server.store.GetSubmission(ctx, db.GetSubmissionParams{
 		AssignmentID: req.AssignmentID,
 		UserID:       req.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	getSubmission, err := server.store.GetSubmission(ctx, arg)
+	attempts, err := server.store.ListSubmissionAttempts(ctx, submission.SubmissionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
+	if authPayload.Role != "admin" {
+		assignment, err := server.store.GetAssignment(ctx, req.AssignmentID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		hideUnpublishedGrade(&submission, assignment)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"submission": submission, "attempts": attempts})
+}
+
+// SelectGradedAttemptRequest defines the request body structure for choosing the graded attempt
+type SelectGradedAttemptRequest struct {
+	SubmissionID  int64 `json:"submission_id" binding:"required,min=1"`
+	AttemptNumber int64 `json:"attempt_number" binding:"required,min=1"`
+}
+
+// @Summary Choose the graded attempt
+// @Description Pick which attempt of a submission is graded, later attempts no longer replace it.
+// @Description The current grade is cleared unless it was given to the chosen attempt
+// @Accept json
+// @Produce json
+// @Param request body SelectGradedAttemptRequest true "Select Graded Attempt Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /submission/graded-attempt [put]
+func (server *Server) SelectGradedAttempt(ctx *gin.Context) {
+	var req SelectGradedAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("you are not an authorized user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	attempt, err := server.store.GetSubmissionAttempt(ctx, db.GetSubmissionAttemptParams{
+		SubmissionID:  req.SubmissionID,
+		AttemptNumber: req.AttemptNumber,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	submission, err := server.store.GetSubmissionByID(ctx, attempt.SubmissionID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	//assign value to file
-	value := getSubmission.Resource
-	//remove the exiting file
-	util.DeleteFileByURL(value)
 
-	//delete submission details
-	errors := server.store.DeleteSubmission(ctx, db.DeleteSubmissionParams{
-		AssignmentID: req.AssignmentID,
-		UserID:       req.UserID,
+	submission, err = server.store.UpdateSubmissionFromAttempt(ctx, db.UpdateSubmissionFromAttemptParams{
+		SubmissionID:     attempt.SubmissionID,
+		Resource:         attempt.Resource,
+		DateOfSubmission: attempt.SubmittedAt,
+		IsLate:           attempt.IsLate,
+		PenaltyPercent:   attempt.PenaltyPercent,
+		GradedAttemptID:  pgtype.Int8{Int64: attempt.AttemptID, Valid: true},
+		ClearGrade:       db.AttemptClearsGrade(submission, attempt),
 	})
-	if errors != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Submission data Deletion Failed, Please try agian.!"})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"messege": "Submission successfully deleted.!"})
+	ctx.JSON(http.StatusOK, submission)
 }
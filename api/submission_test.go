@@ -0,0 +1,228 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// gradedAttemptStore holds one graded submission with two attempts
+type gradedAttemptStore struct {
+	db.Store
+
+	submission db.Submission
+	attempts   []db.SubmissionAttempt
+	updated    []db.UpdateSubmissionFromAttemptParams
+}
+
+func (store *gradedAttemptStore) GetSubmissionAttempt(ctx context.Context, arg db.GetSubmissionAttemptParams) (db.SubmissionAttempt, error) {
+	for _, attempt := range store.attempts {
+		if attempt.SubmissionID == arg.SubmissionID && attempt.AttemptNumber == arg.AttemptNumber {
+			return attempt, nil
+		}
+	}
+	return db.SubmissionAttempt{}, db.ErrRecordNotFound
+}
+
+func (store *gradedAttemptStore) GetSubmissionByID(ctx context.Context, submissionID int64) (db.Submission, error) {
+	return store.submission, nil
+}
+
+func (store *gradedAttemptStore) UpdateSubmissionFromAttempt(ctx context.Context, arg db.UpdateSubmissionFromAttemptParams) (db.Submission, error) {
+	store.updated = append(store.updated, arg)
+	submission := store.submission
+	submission.Resource = arg.Resource
+	submission.GradedAttemptID = arg.GradedAttemptID
+	if arg.ClearGrade {
+		submission.Grade = "null"
+		submission.Feedback = ""
+		submission.GradedAt = pgtype.Timestamptz{}
+	}
+	return submission, nil
+}
+
+func TestSelectGradedAttempt(t *testing.T) {
+	testCases := []struct {
+		name          string
+		payload       *token.Payload
+		attemptNumber int64
+		wantStatus    int
+		wantCleared   bool
+		wantGrade     string
+	}{
+		{name: "the graded attempt keeps its grade", payload: &token.Payload{UserID: 1, Role: "admin"},
+			attemptNumber: 2, wantStatus: http.StatusOK, wantGrade: "8"},
+		{name: "an earlier attempt clears the grade", payload: &token.Payload{UserID: 1, Role: "admin"},
+			attemptNumber: 1, wantStatus: http.StatusOK, wantCleared: true, wantGrade: "null"},
+		{name: "unknown attempt", payload: &token.Payload{UserID: 1, Role: "admin"},
+			attemptNumber: 3, wantStatus: http.StatusNotFound},
+		{name: "student", payload: &token.Payload{UserID: 42, Role: "student"},
+			attemptNumber: 1, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &gradedAttemptStore{
+				submission: db.Submission{SubmissionID: 4, UserID: 42, Grade: "8", Feedback: "Good",
+					Resource: "uploads/essay-2.pdf", GradedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+				attempts: []db.SubmissionAttempt{
+					{AttemptID: 10, SubmissionID: 4, AttemptNumber: 1, Resource: "uploads/essay-1.pdf"},
+					{AttemptID: 11, SubmissionID: 4, AttemptNumber: 2, Resource: "uploads/essay-2.pdf"},
+				},
+			}
+
+			gin.SetMode(gin.TestMode)
+			server := &Server{store: store}
+			router := gin.New()
+			router.PUT("/submission/graded-attempt", func(ctx *gin.Context) {
+				ctx.Set(authorizationPayloadKey, tc.payload)
+			}, server.SelectGradedAttempt)
+
+			body, _ := json.Marshal(SelectGradedAttemptRequest{SubmissionID: 4, AttemptNumber: tc.attemptNumber})
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/submission/graded-attempt", bytes.NewReader(body)))
+			if recorder.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				if len(store.updated) != 0 {
+					t.Errorf("submission updated on a rejected request")
+				}
+				return
+			}
+
+			if len(store.updated) != 1 {
+				t.Fatalf("updated %d times, want once", len(store.updated))
+			}
+			updated := store.updated[0]
+			if updated.ClearGrade != tc.wantCleared {
+				t.Errorf("ClearGrade = %v, want %v", updated.ClearGrade, tc.wantCleared)
+			}
+			if !updated.GradedAttemptID.Valid || updated.GradedAttemptID.Int64 != store.attempts[tc.attemptNumber-1].AttemptID {
+				t.Errorf("GradedAttemptID = %v, want the chosen attempt", updated.GradedAttemptID)
+			}
+
+			var response db.Submission
+			json.Unmarshal(recorder.Body.Bytes(), &response)
+			if response.Grade != tc.wantGrade {
+				t.Errorf("grade = %q, want %q", response.Grade, tc.wantGrade)
+			}
+		})
+	}
+}
+
+// deleteSubmissionStore holds one submission with two attempts, deleted reports how many rows the delete removes
+type deleteSubmissionStore struct {
+	db.Store
+
+	submission *db.Submission
+	attempts   []db.SubmissionAttempt
+	deleted    int64
+	deletedIDs []int64
+}
+
+func (store *deleteSubmissionStore) GetSubmission(ctx context.Context, arg db.GetSubmissionParams) (db.Submission, error) {
+	if store.submission == nil {
+		return db.Submission{}, db.ErrRecordNotFound
+	}
+	return *store.submission, nil
+}
+
+func (store *deleteSubmissionStore) ListSubmissionAttempts(ctx context.Context, submissionID int64) ([]db.SubmissionAttempt, error) {
+	return store.attempts, nil
+}
+
+func (store *deleteSubmissionStore) DeleteSubmission(ctx context.Context, submissionID int64) (int64, error) {
+	store.deletedIDs = append(store.deletedIDs, submissionID)
+	return store.deleted, nil
+}
+
+func (store *deleteSubmissionStore) GetAssignment(ctx context.Context, assignmentID int64) (db.Assignment, error) {
+	return db.Assignment{}, db.ErrRecordNotFound
+}
+
+func TestDeleteSubmission(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	files := []string{"essay-1.pdf", "essay-2.pdf"}
+	submission := &db.Submission{SubmissionID: 4, AssignmentID: 2, UserID: 42,
+		Resource: "http://localhost:8080/uploads/submissions/essay-2.pdf"}
+	attempts := []db.SubmissionAttempt{
+		{AttemptID: 10, SubmissionID: 4, Resource: "http://localhost:8080/uploads/submissions/essay-1.pdf"},
+		{AttemptID: 11, SubmissionID: 4, Resource: "http://localhost:8080/uploads/submissions/essay-2.pdf"},
+	}
+
+	testCases := []struct {
+		name           string
+		store          *deleteSubmissionStore
+		payload        *token.Payload
+		wantStatus     int
+		wantDeletedIDs []int64
+		wantFilesKept  bool
+	}{
+		{name: "deleted", store: &deleteSubmissionStore{submission: submission, attempts: attempts, deleted: 1},
+			payload: &token.Payload{UserID: 1, Role: "admin"}, wantStatus: http.StatusOK, wantDeletedIDs: []int64{4}},
+		{name: "deleted concurrently", store: &deleteSubmissionStore{submission: submission, attempts: attempts},
+			payload: &token.Payload{UserID: 1, Role: "admin"}, wantStatus: http.StatusNotFound, wantDeletedIDs: []int64{4}, wantFilesKept: true},
+		{name: "no submission", store: &deleteSubmissionStore{},
+			payload: &token.Payload{UserID: 1, Role: "admin"}, wantStatus: http.StatusNotFound, wantFilesKept: true},
+		{name: "student", store: &deleteSubmissionStore{submission: submission, attempts: attempts, deleted: 1},
+			payload: &token.Payload{UserID: 42, Role: "student"}, wantStatus: http.StatusForbidden, wantFilesKept: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uploadDir := filepath.Join("uploads", "submissions")
+			if err := os.MkdirAll(uploadDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range files {
+				if err := os.WriteFile(filepath.Join(uploadDir, file), []byte("essay"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			gin.SetMode(gin.TestMode)
+			server := &Server{store: tc.store}
+			router := gin.New()
+			router.DELETE("/submission/delete", func(ctx *gin.Context) {
+				ctx.Set(authorizationPayloadKey, tc.payload)
+			}, server.DeleteSubmission)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/submission/delete?assignment_id=2&user_id=42", nil))
+			if recorder.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tc.wantStatus, recorder.Body)
+			}
+			if !reflect.DeepEqual(tc.store.deletedIDs, tc.wantDeletedIDs) {
+				t.Errorf("deleted submissions %v, want %v", tc.store.deletedIDs, tc.wantDeletedIDs)
+			}
+			for _, file := range files {
+				_, err := os.Stat(filepath.Join(uploadDir, file))
+				if kept := err == nil; kept != tc.wantFilesKept {
+					t.Errorf("%s kept = %v, want %v", file, kept, tc.wantFilesKept)
+				}
+			}
+		})
+	}
+}
@@ -188,21 +188,53 @@ func (server *Server) UpdateSubscriptions(ctx *gin.Context) {
 		return
 	}
 
+	// deactivating a student by hand frees a seat for the waitlist
+	if !material.Active {
+		server.promoteWaitlist(ctx, material.CourseID)
+	}
+
 	ctx.JSON(http.StatusOK, material)
 }
 
+// distributeCreateSubscription returns the AfterCreate hook that sets up the progress of a new subscription
+// and records the launch of the course when the student got a seat
+func (server *Server) distributeCreateSubscription(ctx *gin.Context) func(subscription db.Subscription) error {
+	return func(subscription db.Subscription) error {
+		// Use Redis for task distribution
+		taskPayload := &worker.PayloadCreateSubscription{
+			UserID:   subscription.UserID,
+			CourseID: subscription.CourseID,
+		}
+
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.ProcessIn(10 * time.Second),
+			asynq.Queue(worker.QueueCritical),
+		}
+		err := server.taskDistributor.DistributeTaskCreateSubscription(ctx, taskPayload, opts...)
+		if err != nil || !subscription.Active {
+			return err
+		}
+		return worker.DistributeCourseLaunched(ctx, server.taskDistributor, server.xapiActivities(), subscription)
+	}
+}
+
 type createSubscriptionRequest struct {
 	UserID   int64 `json:"user_id"`
 	CourseID int64 `json:"course_id"`
 }
 
 // @Summary Create a new Subscription
-// @Description Creates a new Subscription by depend on course
+// @Description Asks to enroll in a course following its enrollment settings. Open courses enroll right away or waitlist the
+// @Description student when full, other courses keep the request pending for approval. Admins can enroll any student
+// @Description regardless of the enrollment mode and window.
 // @Accept json
 // @Produce json
 // @Param request body CreateSubscriptionRequest true "user_id and course_id"
 // @Success 200
 // @Failure 400
+// @Failure 402
+// @Failure 403
 // @Failure 404
 // @Failure 500
 // @Router /subscription [post]
@@ -216,46 +248,46 @@ func (server Server) CreateSubscription(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if req.UserID != authPayload.UserID {
+	invited := authPayload.Role == "admin"
+	if req.UserID != authPayload.UserID && !invited {
 		err := errors.New("authenticated users only able to make changes, access denied ")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
 	arg := db.CreateSubscriptionTxParams{
-		CreateSubscriptionParams: db.CreateSubscriptionParams{
-			UserID:   req.UserID,
-			CourseID: req.CourseID,
-			Active:   false,
-			Pending:  true,
-		},
-		AfterCreate: func(subscription db.Subscription) error {
-			// Use Redis for task distribution
-			taskPayload := &worker.PayloadCreateSubscription{
-				UserID:   subscription.UserID,
-				CourseID: subscription.CourseID,
-			}
-
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.ProcessIn(10 * time.Second),
-				asynq.Queue(worker.QueueCritical),
-			}
-			return server.taskDistributor.DistributeTaskCreateSubscription(ctx, taskPayload, opts...)
-		},
+		UserID:      req.UserID,
+		CourseID:    req.CourseID,
+		Now:         time.Now(),
+		Invited:     invited,
+		AfterCreate: server.distributeCreateSubscription(ctx),
 	}
 
 	txResult, err := server.store.CreateSubscriptionTx(ctx, arg)
 
 	if err != nil {
-		if db.ErrorCode(err) == db.UniqueViolations {
+		if errors.Is(err, db.ErrPaymentRequired) {
+			ctx.JSON(http.StatusPaymentRequired, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolations || isEnrollmentRefused(err) {
 			ctx.JSON(http.StatusForbidden, errorResponse(err))
 			return
 		}
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	if txResult.Subscription.WaitlistedAt.Valid {
+		server.distributeEnrollmentEmail(ctx, txResult.Subscription, worker.EnrollmentEventWaitlisted)
+	} else if invited && txResult.Subscription.Active {
+		server.distributeEnrollmentEmail(ctx, txResult.Subscription, worker.EnrollmentEventApproved)
+	}
+
 	ctx.JSON(http.StatusCreated, txResult)
 }
 
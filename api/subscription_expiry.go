@@ -0,0 +1,134 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errNoActiveSubscription = errors.New("user doesn't have an active subscription")
+
+// courseAccessError returns why a subscription does not give access to its course at now, if it does not.
+// The expiry task deactivates expired subscriptions, the expiry is checked here as well so access ends on time.
+func courseAccessError(subscription db.Subscription, now time.Time) error {
+	if !subscription.Active {
+		return errNoActiveSubscription
+	}
+	if subscription.StartsAt.Valid && now.Before(subscription.StartsAt.Time) {
+		return fmt.Errorf("access to this course starts at %s", subscription.StartsAt.Time.UTC().Format(time.RFC1123))
+	}
+	if subscription.ExpiresAt.Valid && !now.Before(subscription.ExpiresAt.Time) {
+		return fmt.Errorf("access to this course expired at %s", subscription.ExpiresAt.Time.UTC().Format(time.RFC1123))
+	}
+	return nil
+}
+
+// requireCourseAccess checks that the authenticated user may use the content of a course and writes the error response
+// when they may not. Admins always have access.
+func (server *Server) requireCourseAccess(ctx *gin.Context, authPayload *token.Payload, courseID int64) bool {
+	if authPayload.Role == "admin" {
+		return true
+	}
+
+	subscription, err := server.store.GetCourseSubscription(ctx, db.GetCourseSubscriptionParams{
+		UserID:   authPayload.UserID,
+		CourseID: courseID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errNoActiveSubscription))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if err := courseAccessError(subscription, time.Now()); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+// ExtendSubscriptionsRequest defines the request body structure for extending the access of students in bulk
+type ExtendSubscriptionsRequest struct {
+	CourseID int64 `json:"course_id" binding:"required,min=1"`
+	// UserIDs limits the extension to some students, empty extends every student of the course
+	UserIDs []int64 `json:"user_ids"`
+	// Days extends access from the current expiry, or from now when it has passed
+	Days int32 `json:"days" binding:"min=0,max=36500"`
+	// ExpiresAt sets the expiry instead, it also gives an end to students who keep access forever
+	ExpiresAt string `json:"expires_at"`
+}
+
+// @Summary Extend access in bulk
+// @Description Extend the access of students to a course by days, or move their expiry to expires_at. Students whose access
+// @Description expired get it back and keep their seat even when the course filled up since. Without user_ids every student
+// @Description of the course is extended, days only extends students whose access has an end.
+// @ID extend-subscriptions
+// @Accept json
+// @Produce json
+// @Param request body ExtendSubscriptionsRequest true "Extend Subscriptions Request"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /subscription/extend [put]
+func (server *Server) ExtendSubscriptions(ctx *gin.Context) {
+	var req ExtendSubscriptionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	expiresAt, err := parseEnrollmentTime(req.ExpiresAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if expiresAt.Valid == (req.Days > 0) {
+		err := errors.New("give either days or expires_at")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if expiresAt.Valid && !expiresAt.Time.After(time.Now()) {
+		err := errors.New("expires_at must be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userIDs := req.UserIDs
+	if userIDs == nil {
+		userIDs = []int64{}
+	}
+
+	subscriptions, err := server.store.ExtendSubscriptions(ctx, db.ExtendSubscriptionsParams{
+		ExpiresAt: expiresAt,
+		Days:      req.Days,
+		CourseID:  req.CourseID,
+		UserIds:   userIDs,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.CheckViolation {
+			err := errors.New("expires_at must be after the start of access")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
@@ -2,12 +2,15 @@ package api
 
 import (
 	db "eduApp/db/sqlc"
+	"eduApp/mail"
 	"eduApp/token"
 	"eduApp/util"
 	"eduApp/val"
 	"eduApp/worker"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -31,6 +34,7 @@ type userResponse struct {
 	LastName  string    `json:"last_name"`
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
+	Locale    string    `json:"locale"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -41,6 +45,7 @@ func newUserResponse(user db.User) userResponse {
 		LastName:  user.LastName,
 		Email:     user.Email,
 		Role:      user.Role,
+		Locale:    user.Locale,
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -711,3 +716,49 @@ func (server *Server) deleteUsers(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
+
+// UpdateUserLocaleRequest defines the request body structure for choosing the language emails are sent in
+type UpdateUserLocaleRequest struct {
+	Locale string `json:"locale" binding:"required"`
+}
+
+// @Summary Update the locale of the signed in user
+// @Description Set the language transactional emails are sent to the signed in user in, such as en or fr
+// @ID update-user-locale
+// @Accept json
+// @Produce json
+// @Param request body UpdateUserLocaleRequest true "Update User Locale Request"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /users/locale [put]
+func (server *Server) UpdateUserLocale(ctx *gin.Context) {
+	var req UpdateUserLocaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	locale := strings.ToLower(strings.TrimSpace(req.Locale))
+	if !mail.SupportedLocale(locale) {
+		err := fmt.Errorf("locale must be one of %s", strings.Join(mail.Locales(), ", "))
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.UpdateUserLocale(ctx, db.UpdateUserLocaleParams{
+		Locale: locale,
+		UserID: authPayload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
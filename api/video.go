@@ -0,0 +1,217 @@
+package api
+
+import (
+	db "eduApp/db/sqlc"
+	"eduApp/token"
+	"eduApp/video"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errHeartbeatTooLong is returned for a heartbeat covering more of the video than a player plays between two heartbeats
+var errHeartbeatTooLong = errors.New("segment is longer than one heartbeat")
+
+// VideoHeartbeatRequest defines the request body structure for reporting video playback
+type VideoHeartbeatRequest struct {
+	MaterialID int64 `json:"material_id" binding:"required,min=1"`
+	// Start and End are the part of the video played since the previous heartbeat, in seconds
+	Start    float64 `json:"start" binding:"min=0"`
+	End      float64 `json:"end" binding:"min=0,gtefield=Start"`
+	Position float64 `json:"position" binding:"min=0"`
+}
+
+// videoProgressResponse is how far a student got in a video
+type videoProgressResponse struct {
+	MaterialID      int64          `json:"material_id"`
+	PositionSeconds float64        `json:"position_seconds"`
+	DurationSeconds float64        `json:"duration_seconds"`
+	WatchedSeconds  float64        `json:"watched_seconds"`
+	WatchedPercent  int64          `json:"watched_percent"`
+	Segments        video.Segments `json:"segments"`
+	Completed       bool           `json:"completed"`
+}
+
+func newVideoProgressResponse(watch db.VideoWatch) videoProgressResponse {
+	return videoProgressResponse{
+		MaterialID:      watch.MaterialID,
+		PositionSeconds: watch.PositionSeconds,
+		DurationSeconds: watch.DurationSeconds,
+		WatchedSeconds:  watch.WatchedSeconds,
+		WatchedPercent:  video.WatchedPercent(watch.WatchedSeconds, watch.DurationSeconds),
+		Segments:        watch.Segments,
+		Completed:       watch.CompletedAt.Valid,
+	}
+}
+
+// @Summary Report video playback
+// @Description Players send a heartbeat every few seconds with the segment played since the previous one and the current position.
+// @Description Segments are merged so replaying or scrubbing does not count twice, and the lesson is completed once
+// @Description VIDEO_COMPLETE_PERCENT of the video was watched. Playback is checked against the real time between heartbeats
+// @Description and the length of the video set on the material, heartbeats sent less than two seconds apart are refused and
+// @Description heartbeats of a video whose length is not set yet are refused with 409
+// @Accept json
+// @Produce json
+// @Param request body VideoHeartbeatRequest true "Video Heartbeat Request"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 409
+// @Failure 429
+// @Failure 500
+// @Router /video/heartbeat [post]
+// VideoHeartbeat records watch time of the authenticated student
+func (server *Server) VideoHeartbeat(ctx *gin.Context) {
+	var req VideoHeartbeatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.End-req.Start > video.MaxHeartbeatSeconds {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errHeartbeatTooLong))
+		return
+	}
+
+	completePercent := server.config.VideoCompletePercent
+	if completePercent <= 0 {
+		completePercent = video.DefaultCompletePercent
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	material, err := server.store.GetMaterialByID(ctx, req.MaterialID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// only students whose access to the course has not ended earn watch time
+	if !server.requireCourseAccess(ctx, authPayload, material.CourseID) {
+		return
+	}
+
+	result, err := server.store.RecordVideoHeartbeatTx(ctx, db.RecordVideoHeartbeatTxParams{
+		UserID:          authPayload.UserID,
+		MaterialID:      req.MaterialID,
+		Segment:         video.Segment{Start: req.Start, End: req.End},
+		Position:        req.Position,
+		CompletePercent: completePercent,
+		AfterComplete:   server.courseCompletionHook(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrNotVideo) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrVideoDurationUnset) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrHeartbeatTooSoon) {
+			ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.Completed {
+		server.distributeLessonCompleted(ctx, db.LessonCompletion{
+			UserID:     authPayload.UserID,
+			CourseID:   result.CourseProgress.CourseID,
+			MaterialID: req.MaterialID,
+		}, authPayload.UserName)
+	}
+	server.publishProgress(ctx, result.CourseProgress)
+
+	ctx.JSON(http.StatusOK, newVideoProgressResponse(result.VideoWatch))
+}
+
+// GetVideoProgressRequest contains the input parameters for getting the resume position of a video
+type GetVideoProgressRequest struct {
+	MaterialID int64 `form:"material_id" binding:"required,min=1"`
+}
+
+// @Summary Get video progress
+// @Description Returns where the authenticated student left off in a video, a video never played starts at zero
+// @Produce json
+// @Param material_id query int true "Material ID"
+// @Success 200
+// @Failure 400
+// @Failure 500
+// @Router /video/progress [get]
+// GetVideoProgress returns the resume position and watched share of a video
+func (server *Server) GetVideoProgress(ctx *gin.Context) {
+	var req GetVideoProgressRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	watch, err := server.store.GetVideoWatch(ctx, db.GetVideoWatchParams{
+		UserID:     authPayload.UserID,
+		MaterialID: req.MaterialID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusOK, newVideoProgressResponse(db.VideoWatch{
+				MaterialID: req.MaterialID,
+				Segments:   video.Segments{},
+			}))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newVideoProgressResponse(watch))
+}
+
+// ListVideoDropOffRequest contains the input parameters for the video drop-off report of a course
+type ListVideoDropOffRequest struct {
+	CourseID int64 `form:"course_id" binding:"required,min=1"`
+}
+
+// @Summary Video drop-off report
+// @Description Lists every video of a course with its viewers, completions and the average point where viewers stopped watching
+// @Produce json
+// @Param course_id query int true "Course ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 500
+// @Router /video/dropoff [get]
+// ListVideoDropOff reports how far students get in the videos of a course
+func (server *Server) ListVideoDropOff(ctx *gin.Context) {
+	var req ListVideoDropOffRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != "admin" {
+		err := errors.New("not an admin of the system")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	report, err := server.store.ListVideoDropOff(ctx, req.CourseID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,399 @@
+package api
+
+import (
+	"bytes"
+	db "eduApp/db/sqlc"
+	"eduApp/worker"
+	"eduApp/xapi"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxStatementBytes is the largest statement request the built-in LRS reads
+	maxStatementBytes = 1 << 20
+	// defaultStatementLimit is how many statements a query returns when it sets no limit
+	defaultStatementLimit = 100
+	// maxStatementLimit is the most statements a query returns at once
+	maxStatementLimit = 500
+	// xapiPlatform is the platform statements of the app are recorded on
+	xapiPlatform = "eduApp"
+)
+
+// xapiActivities returns the activity IRIs statements are written with
+func (server *Server) xapiActivities() xapi.Activities {
+	return worker.XAPIActivities(server.config)
+}
+
+// distributeXAPIStatement queues a statement for the LRS, the event it records stands even if it cannot be queued
+func (server *Server) distributeXAPIStatement(ctx *gin.Context, statement xapi.Statement) {
+	if err := worker.DistributeXAPIStatement(ctx, server.taskDistributor, statement); err != nil {
+		log.Error().Err(err).Str("verb", statement.Verb.ID).Str("object", statement.Object.ID).Msg("failed to enqueue xAPI statement")
+	}
+}
+
+// distributeCourseLaunched queues the launched statement of a student who got a seat in a course
+func (server *Server) distributeCourseLaunched(ctx *gin.Context, subscription db.Subscription) {
+	err := worker.DistributeCourseLaunched(ctx, server.taskDistributor, server.xapiActivities(), subscription)
+	if err != nil {
+		log.Error().Err(err).Int64("subscription_id", subscription.SubscriptionID).Msg("failed to enqueue xAPI statement")
+	}
+}
+
+// distributeLessonCompleted queues the completed statement of a lesson
+func (server *Server) distributeLessonCompleted(ctx *gin.Context, lesson db.LessonCompletion, name string) {
+	activities := server.xapiActivities()
+	statement := xapi.NewStatement(
+		activities.Agent(lesson.UserID, name),
+		xapi.VerbCompleted,
+		activities.Lesson(lesson.MaterialID, ""),
+	).WithCompletion(true, nil).WithParent(activities.Course(lesson.CourseID, ""), xapiPlatform)
+	server.distributeXAPIStatement(ctx, statement)
+}
+
+// distributeSubmissionScored queues the scored statement of a graded submission, maxPoints is zero when the grade has no maximum
+func (server *Server) distributeSubmissionScored(ctx *gin.Context, submission db.Submission, grade, maxPoints float64) {
+	assignment, err := server.store.GetAssignment(ctx, submission.AssignmentID)
+	if err != nil {
+		log.Error().Err(err).Int64("submission_id", submission.SubmissionID).Msg("failed to get assignment for xAPI statement")
+		return
+	}
+
+	activities := server.xapiActivities()
+	statement := xapi.NewStatement(
+		activities.Agent(submission.UserID, ""),
+		xapi.VerbScored,
+		activities.Assignment(assignment.AssignmentID, assignment.Title),
+	).WithScore(grade, maxPoints).WithParent(activities.Course(assignment.CourseID, ""), xapiPlatform)
+	server.distributeXAPIStatement(ctx, statement)
+}
+
+// xapiAbout is the answer of the about resource
+type xapiAbout struct {
+	Version []string `json:"version"`
+}
+
+// @Summary LRS about
+// @Description Returns the xAPI versions the built-in learning record store supports
+// @Produce json
+// @Success 200
+// @Router /xapi/about [get]
+// XAPIAbout describes the built-in learning record store
+func (server *Server) XAPIAbout(ctx *gin.Context) {
+	ctx.Header(xapi.VersionHeader, xapi.Version)
+	ctx.JSON(http.StatusOK, xapiAbout{Version: []string{xapi.Version}})
+}
+
+// readStatementBody reads the body of a statement request
+func readStatementBody(ctx *gin.Context) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxStatementBytes))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(body), nil
+}
+
+// prepareStatements checks the statements of a request and gets them ready to be stored
+func (server *Server) prepareStatements(documents []json.RawMessage) ([]db.XAPIStatementRecord, []string, error) {
+	stored := time.Now()
+	authority := server.xapiActivities().Authority(worker.XAPIAuthorityName)
+
+	records := make([]db.XAPIStatementRecord, 0, len(documents))
+	ids := make([]string, 0, len(documents))
+	seen := make(map[string]bool, len(documents))
+	for _, document := range documents {
+		statement, prepared, err := xapi.Prepare(document, authority, stored)
+		if err != nil {
+			return nil, nil, err
+		}
+		if seen[statement.ID] {
+			return nil, nil, fmt.Errorf("%w: statement id %s is repeated", xapi.ErrInvalidStatement, statement.ID)
+		}
+		seen[statement.ID] = true
+
+		record, err := db.NewXAPIStatementRecord(statement, prepared, stored)
+		if err != nil {
+			return nil, nil, err
+		}
+		records = append(records, record)
+		ids = append(ids, statement.ID)
+	}
+	return records, ids, nil
+}
+
+// storeStatements answers a statement request with the outcome of storing its statements
+func (server *Server) storeStatements(ctx *gin.Context, documents []json.RawMessage) ([]string, bool) {
+	records, ids, err := server.prepareStatements(documents)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, false
+	}
+
+	_, err = server.store.StoreXAPIStatementsTx(ctx, db.StoreXAPIStatementsTxParams{Statements: records})
+	if err != nil {
+		if errors.Is(err, db.ErrStatementConflict) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false
+	}
+	return ids, true
+}
+
+// @Summary Store a statement with a given ID
+// @Description Stores one statement in the built-in LRS under the statementId query parameter. Storing the same statement
+// @Description again succeeds, a different statement with the same ID is a conflict.
+// @Accept json
+// @Param statementId query string true "Statement ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 409
+// @Failure 500
+// @Router /xapi/statements [put]
+// PutXAPIStatement stores a statement under the ID chosen by the client
+func (server *Server) PutXAPIStatement(ctx *gin.Context) {
+	statementID := ctx.Query("statementId")
+	if _, err := uuid.Parse(statementID); err != nil {
+		err := errors.New("statementId must be a UUID")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	body, err := readStatementBody(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		err := fmt.Errorf("%w: statement is not a JSON object", xapi.ErrInvalidStatement)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if id, ok := raw["id"]; ok {
+		var bodyID string
+		if err := json.Unmarshal(id, &bodyID); err != nil || bodyID != statementID {
+			err := errors.New("statement id does not match statementId")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+	raw["id"], _ = json.Marshal(statementID)
+
+	document, err := json.Marshal(raw)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.storeStatements(ctx, []json.RawMessage{document}); !ok {
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Store statements
+// @Description Stores one statement or an array of statements in the built-in LRS, all of them or none,
+// @Description and returns their IDs in order. Statements without an ID get one.
+// @Accept json
+// @Produce json
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 409
+// @Failure 500
+// @Router /xapi/statements [post]
+// PostXAPIStatements stores a batch of statements
+func (server *Server) PostXAPIStatements(ctx *gin.Context) {
+	body, err := readStatementBody(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var documents []json.RawMessage
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &documents); err != nil {
+			err := fmt.Errorf("%w: body is not an array of statements", xapi.ErrInvalidStatement)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	} else {
+		documents = []json.RawMessage{body}
+	}
+	if len(documents) == 0 {
+		err := fmt.Errorf("%w: no statements were sent", xapi.ErrInvalidStatement)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ids, ok := server.storeStatements(ctx, documents)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ids)
+}
+
+// GetXAPIStatementsRequest contains the filters of a statement query
+type GetXAPIStatementsRequest struct {
+	StatementID       string `form:"statementId"`
+	VoidedStatementID string `form:"voidedStatementId"`
+	Agent             string `form:"agent"`
+	Verb              string `form:"verb"`
+	Activity          string `form:"activity"`
+	Since             string `form:"since"`
+	Until             string `form:"until"`
+	Limit             int32  `form:"limit" binding:"min=0"`
+	Ascending         bool   `form:"ascending"`
+	// Offset pages through the results, it is set in the more URL of the previous page
+	Offset int32 `form:"offset" binding:"min=0"`
+}
+
+// parseStatementTime parses an optional since or until filter
+func parseStatementTime(value string) (pgtype.Timestamptz, error) {
+	if value == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return pgtype.Timestamptz{}, err
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// @Summary Get statements
+// @Description Returns one statement by statementId or voidedStatementId, or the statements matching the agent, verb, activity,
+// @Description since and until filters newest first. Voided statements are left out of queries.
+// @Produce json
+// @Param statementId query string false "Statement ID"
+// @Param voidedStatementId query string false "Voided Statement ID"
+// @Param agent query string false "Agent as JSON"
+// @Param verb query string false "Verb IRI"
+// @Param activity query string false "Activity IRI"
+// @Param since query string false "Stored after"
+// @Param until query string false "Stored at or before"
+// @Param limit query int false "Limit"
+// @Param ascending query bool false "Oldest first"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /xapi/statements [get]
+// GetXAPIStatements answers statement queries of the built-in LRS
+func (server *Server) GetXAPIStatements(ctx *gin.Context) {
+	var req GetXAPIStatementsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ctx.Header("X-Experience-API-Consistent-Through", time.Now().UTC().Format(time.RFC3339Nano))
+
+	if req.StatementID != "" || req.VoidedStatementID != "" {
+		server.getXAPIStatement(ctx, req)
+		return
+	}
+
+	arg := db.ListXAPIStatementsParams{
+		VerbID:     req.Verb,
+		ActivityID: req.Activity,
+		Ascending:  req.Ascending,
+		RowLimit:   req.Limit,
+		RowOffset:  req.Offset,
+	}
+	if arg.RowLimit == 0 {
+		arg.RowLimit = defaultStatementLimit
+	}
+	if arg.RowLimit > maxStatementLimit {
+		arg.RowLimit = maxStatementLimit
+	}
+
+	if req.Agent != "" {
+		var agent xapi.Agent
+		if err := json.Unmarshal([]byte(req.Agent), &agent); err != nil || xapi.AgentIdentifier(agent) == "" {
+			err := errors.New("agent must be a JSON agent with an identifier")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		arg.Actor = xapi.AgentIdentifier(agent)
+	}
+
+	var err error
+	if arg.Since, err = parseStatementTime(req.Since); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if arg.Until, err = parseStatementTime(req.Until); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.ListXAPIStatements(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result := xapi.StatementResult{Statements: make([]json.RawMessage, 0, len(rows))}
+	for _, row := range rows {
+		result.Statements = append(result.Statements, row.Statement)
+	}
+	if len(rows) == int(arg.RowLimit) {
+		query := ctx.Request.URL.Query()
+		query.Set("offset", strconv.Itoa(int(arg.RowOffset+arg.RowLimit)))
+		result.More = ctx.Request.URL.Path + "?" + query.Encode()
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// getXAPIStatement answers a query for a single statement, a voided statement is only returned as voidedStatementId
+func (server *Server) getXAPIStatement(ctx *gin.Context, req GetXAPIStatementsRequest) {
+	if req.StatementID != "" && req.VoidedStatementID != "" {
+		err := errors.New("statementId and voidedStatementId cannot be used together")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	statementID, voided := req.StatementID, false
+	if req.VoidedStatementID != "" {
+		statementID, voided = req.VoidedStatementID, true
+	}
+
+	id, err := uuid.Parse(statementID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	row, err := server.store.GetXAPIStatement(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err == nil && row.Voided != voided {
+		err = db.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/json", row.Statement)
+}
@@ -0,0 +1,55 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// codeAlphabet leaves out letters and digits that are easily mixed up when a code is typed in
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// codeGroups and codeGroupSize make codes like ABCD-EFGH-JKLM-NPQR, about 80 bits of randomness
+const (
+	codeGroups    = 4
+	codeGroupSize = 4
+)
+
+// NewCode returns a random certificate code
+func NewCode() (string, error) {
+	max := big.NewInt(int64(len(codeAlphabet)))
+
+	var sb strings.Builder
+	for i := 0; i < codeGroups*codeGroupSize; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(codeAlphabet[n.Int64()])
+	}
+	return formatCode(sb.String()), nil
+}
+
+// NormalizeCode turns a code as typed or pasted by a visitor into the stored form
+func NormalizeCode(code string) string {
+	code = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+
+	if len(code) != codeGroups*codeGroupSize {
+		return code
+	}
+	return formatCode(code)
+}
+
+// formatCode splits a code into dash separated groups
+func formatCode(code string) string {
+	groups := make([]string, 0, codeGroups)
+	for i := 0; i < len(code); i += codeGroupSize {
+		groups = append(groups, code[i:i+codeGroupSize])
+	}
+	return strings.Join(groups, "-")
+}
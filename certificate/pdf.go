@@ -0,0 +1,192 @@
+package certificate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Data is what a certificate says about the student who earned it
+type Data struct {
+	StudentName string
+	CourseTitle string
+	Instructor  string
+	IssuedAt    time.Time
+	Code        string
+	VerifyURL   string
+}
+
+// IssuedOn is the issue date as printed on the certificate
+func (data Data) IssuedOn() string {
+	return data.IssuedAt.Format("January 2, 2006")
+}
+
+// Template lays out a certificate. Title, Lines and Footer are text/template strings over Data,
+// for example "awarded to {{.StudentName}}". Colors are hex codes like #1f3a93.
+type Template struct {
+	// Orientation is "L" for landscape or "P" for portrait
+	Orientation string `json:"orientation"`
+	// PageSize is one of A4, A5, Letter or Legal
+	PageSize string `json:"page_size"`
+	// Font is one of the standard PDF fonts: Helvetica, Times or Courier
+	Font        string   `json:"font"`
+	Title       string   `json:"title"`
+	Lines       []string `json:"lines"`
+	Footer      string   `json:"footer"`
+	BorderColor string   `json:"border_color"`
+	TitleColor  string   `json:"title_color"`
+	TextColor   string   `json:"text_color"`
+}
+
+// DefaultTemplate is used when no template file is configured
+var DefaultTemplate = Template{
+	Orientation: "L",
+	PageSize:    "A4",
+	Font:        "Times",
+	Title:       "Certificate of Completion",
+	Lines: []string{
+		"This certifies that",
+		"{{.StudentName}}",
+		"has successfully completed the course",
+		"{{.CourseTitle}}",
+		"{{if .Instructor}}taught by {{.Instructor}}, {{end}}on {{.IssuedOn}}",
+	},
+	Footer:      "Certificate {{.Code}} - verify at {{.VerifyURL}}",
+	BorderColor: "#1f3a93",
+	TitleColor:  "#1f3a93",
+	TextColor:   "#222222",
+}
+
+// LoadTemplate reads a template from a JSON file, fields left out keep their default
+func LoadTemplate(path string) (Template, error) {
+	tmpl := DefaultTemplate
+	if path == "" {
+		return tmpl, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to read certificate template: %w", err)
+	}
+	if err := json.Unmarshal(content, &tmpl); err != nil {
+		return Template{}, fmt.Errorf("failed to decode certificate template: %w", err)
+	}
+
+	// render once with sample data so a broken template is found when it is loaded
+	if err := tmpl.Render(io.Discard, Data{StudentName: "Student", CourseTitle: "Course", IssuedAt: time.Now()}); err != nil {
+		return Template{}, err
+	}
+	return tmpl, nil
+}
+
+// Render writes the certificate for data as a PDF document
+func (tmpl Template) Render(w io.Writer, data Data) error {
+	title, err := execute(tmpl.Title, data)
+	if err != nil {
+		return err
+	}
+	lines := make([]string, 0, len(tmpl.Lines))
+	for _, line := range tmpl.Lines {
+		text, err := execute(line, data)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, text)
+	}
+	footer, err := execute(tmpl.Footer, data)
+	if err != nil {
+		return err
+	}
+
+	colors := make(map[string][3]int, 3)
+	for name, hex := range map[string]string{"border": tmpl.BorderColor, "title": tmpl.TitleColor, "text": tmpl.TextColor} {
+		rgb, err := parseColor(hex)
+		if err != nil {
+			return fmt.Errorf("invalid %s color: %w", name, err)
+		}
+		colors[name] = rgb
+	}
+
+	pdf := gofpdf.New(tmpl.Orientation, "mm", tmpl.PageSize, "")
+	pdf.SetTitle(title, true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	// the standard fonts only cover cp1252, names with accents are translated to it
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	width, height := pdf.GetPageSize()
+
+	border := colors["border"]
+	pdf.SetDrawColor(border[0], border[1], border[2])
+	pdf.SetLineWidth(2)
+	pdf.Rect(10, 10, width-20, height-20, "D")
+	pdf.SetLineWidth(0.5)
+	pdf.Rect(14, 14, width-28, height-28, "D")
+
+	titleColor := colors["title"]
+	pdf.SetTextColor(titleColor[0], titleColor[1], titleColor[2])
+	pdf.SetFont(tmpl.Font, "B", 32)
+	pdf.SetXY(20, height*0.2)
+	pdf.CellFormat(width-40, 16, tr(title), "", 1, "C", false, 0, "")
+
+	textColor := colors["text"]
+	pdf.SetTextColor(textColor[0], textColor[1], textColor[2])
+	pdf.Ln(10)
+	for i, line := range lines {
+		// lines that only hold the student name or the course title stand out
+		size, style := 16.0, ""
+		if strings.TrimSpace(line) == strings.TrimSpace(data.StudentName) ||
+			strings.TrimSpace(line) == strings.TrimSpace(data.CourseTitle) {
+			size, style = 24, "B"
+		}
+		pdf.SetFont(tmpl.Font, style, size)
+		pdf.SetX(20)
+		pdf.MultiCell(width-40, size*0.55, tr(line), "", "C", false)
+		if i < len(lines)-1 {
+			pdf.Ln(3)
+		}
+	}
+
+	pdf.SetFont(tmpl.Font, "", 9)
+	pdf.SetXY(20, height-26)
+	pdf.CellFormat(width-40, 6, tr(footer), "", 0, "C", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return pdf.Output(w)
+}
+
+// execute fills a template string with the certificate data
+func execute(text string, data Data) (string, error) {
+	t, err := template.New("certificate").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate template %q: %w", text, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid certificate template %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+// parseColor reads a #rrggbb color
+func parseColor(hex string) ([3]int, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(value) != 6 {
+		return [3]int{}, fmt.Errorf("expected #rrggbb, got %q", hex)
+	}
+	n, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return [3]int{}, fmt.Errorf("expected #rrggbb, got %q", hex)
+	}
+	return [3]int{int(n >> 16 & 0xff), int(n >> 8 & 0xff), int(n & 0xff)}, nil
+}
@@ -0,0 +1,69 @@
+package credential
+
+import (
+	"errors"
+	"math/big"
+)
+
+// base58Alphabet is the bitcoin alphabet used by multibase base58btc
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// multibaseBase58 is the multibase prefix of a base58btc string
+const multibaseBase58 = 'z'
+
+// encodeMultibase encodes data as a multibase base58btc string
+func encodeMultibase(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var encoded []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	// every leading zero byte is kept as a leading '1'
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	encoded = append(encoded, multibaseBase58)
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// decodeMultibase decodes a multibase base58btc string
+func decodeMultibase(value string) ([]byte, error) {
+	if len(value) == 0 || value[0] != multibaseBase58 {
+		return nil, errors.New("not a base58btc multibase value")
+	}
+	value = value[1:]
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	zeros := 0
+	for i := 0; i < len(value); i++ {
+		digit := -1
+		for j := 0; j < len(base58Alphabet); j++ {
+			if base58Alphabet[j] == value[i] {
+				digit = j
+				break
+			}
+		}
+		if digit < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		if digit == 0 && n.Sign() == 0 {
+			zeros++
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
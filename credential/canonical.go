@@ -0,0 +1,194 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// canonicalize serializes a JSON value following the JSON Canonicalization Scheme (RFC 8785):
+// object keys sorted by their UTF-16 code units, no insignificant whitespace, strings escaped only where JSON
+// requires it and numbers written the way ECMAScript writes a double.
+func canonicalize(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toJSONValue turns a Go value into the generic form decoded from JSON, so it can be canonicalized
+func toJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// decodeJSON decodes JSON keeping numbers as they are written
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func writeCanonical(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		number, err := formatNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(number)
+	case string:
+		return writeString(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sortKeys(keys)
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("cannot canonicalize %T", value)
+	}
+	return nil
+}
+
+// sortKeys sorts object keys by their UTF-16 code units, which differs from the byte order of UTF-8
+// for characters outside the basic multilingual plane
+func sortKeys(keys []string) {
+	units := make(map[string][]uint16, len(keys))
+	for _, key := range keys {
+		units[key] = utf16.Encode([]rune(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := units[keys[i]], units[keys[j]]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
+
+// writeString writes a string the way ECMAScript JSON.stringify does: only the quote, the backslash and
+// control characters are escaped, everything else including U+2028, U+2029 and HTML characters is written as is
+func writeString(buf *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return errors.New("cannot canonicalize a string that is not valid UTF-8")
+	}
+
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xf])
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return nil
+}
+
+// formatNumber writes a number as the double it is read as, the way ECMAScript Number.prototype.toString does:
+// the shortest digits that read back as the same double, in exponent form below 1e-6 and from 1e21
+func formatNumber(number json.Number) (string, error) {
+	value, err := strconv.ParseFloat(string(number), 64)
+	if err != nil {
+		return "", fmt.Errorf("cannot canonicalize number %s: %w", number, err)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("cannot canonicalize number %s", number)
+	}
+	if value == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	if value >= 1e-6 && value < 1e21 {
+		return sign + strconv.FormatFloat(value, 'f', -1, 64), nil
+	}
+
+	// Go pads the exponent to two digits, ECMAScript does not
+	formatted := strconv.FormatFloat(value, 'e', -1, 64)
+	exponent := strings.IndexByte(formatted, 'e')
+	if formatted[exponent+2] == '0' {
+		formatted = formatted[:exponent+2] + formatted[exponent+3:]
+	}
+	return sign + formatted, nil
+}
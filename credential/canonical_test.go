@@ -0,0 +1,124 @@
+package credential
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// the examples of RFC 8785
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "Primitives",
+			input: `{
+				"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+				"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			want: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			name: "SortingByUTF16",
+			input: `{
+				"\u20ac": "Euro Sign",
+				"\r": "Carriage Return",
+				"\ufb33": "Hebrew Letter Dalet With Dagesh",
+				"1": "One",
+				"\ud83d\ude00": "Emoji: Grinning Face",
+				"\u0080": "Control",
+				"\u00f6": "Latin Small Letter O With Diaeresis"
+			}`,
+			want: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\"," +
+				"\"€\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			name:  "Nested",
+			input: `{"1": {"f": {"f": "hi", "F": 5}, "\n": 56.0}, "10": {}, "": "empty", "a": {}, "111": [{"e": "yes", "E": "no"}], "A": {}}`,
+			want:  `{"":"empty","1":{"\n":56,"f":{"F":5,"f":"hi"}},"10":{},"111":[{"E":"no","e":"yes"}],"A":{},"a":{}}`,
+		},
+		{
+			name:  "NoHTMLOrLineSeparatorEscaping",
+			input: `{"html": "<a href=\"x\">&amp;</a>", "separators": "\u2028\u2029", "delete": "\u007f", "controls": "\u0000\u001f\b\f\t"}`,
+			want:  "{\"controls\":\"\\u0000\\u001f\\b\\f\\t\",\"delete\":\"\u007f\",\"html\":\"<a href=\\\"x\\\">&amp;</a>\",\"separators\":\"\u2028\u2029\"}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := decodeJSON([]byte(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := canonicalize(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("canonicalize =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// the number examples of RFC 8785 appendix B, given as the bits of the double
+func TestFormatNumber(t *testing.T) {
+	testCases := []struct {
+		bits uint64
+		want string
+	}{
+		{bits: 0x0000000000000000, want: "0"},
+		{bits: 0x8000000000000000, want: "0"},
+		{bits: 0x0000000000000001, want: "5e-324"},
+		{bits: 0x8000000000000001, want: "-5e-324"},
+		{bits: 0x7fefffffffffffff, want: "1.7976931348623157e+308"},
+		{bits: 0xffefffffffffffff, want: "-1.7976931348623157e+308"},
+		{bits: 0x4340000000000000, want: "9007199254740992"},
+		{bits: 0xc340000000000000, want: "-9007199254740992"},
+		{bits: 0x4430000000000000, want: "295147905179352830000"},
+		{bits: 0x44b52d02c7e14af5, want: "9.999999999999997e+22"},
+		{bits: 0x44b52d02c7e14af6, want: "1e+23"},
+		{bits: 0x44b52d02c7e14af7, want: "1.0000000000000001e+23"},
+		{bits: 0x444b1ae4d6e2ef4e, want: "999999999999999700000"},
+		{bits: 0x444b1ae4d6e2ef4f, want: "999999999999999900000"},
+		{bits: 0x444b1ae4d6e2ef50, want: "1e+21"},
+		{bits: 0x3eb0c6f7a0b5ed8c, want: "9.999999999999997e-7"},
+		{bits: 0x3eb0c6f7a0b5ed8d, want: "0.000001"},
+		{bits: 0x41b3de4355555553, want: "333333333.3333332"},
+		{bits: 0x41b3de4355555554, want: "333333333.33333325"},
+		{bits: 0x41b3de4355555555, want: "333333333.3333333"},
+		{bits: 0x41b3de4355555556, want: "333333333.3333334"},
+		{bits: 0x41b3de4355555557, want: "333333333.33333343"},
+		{bits: 0xbecbf647612f3696, want: "-0.0000033333333333333333"},
+		{bits: 0x43143ff3c1cb0959, want: "1424953923781206.2"},
+	}
+
+	for _, tc := range testCases {
+		value := math.Float64frombits(tc.bits)
+		got, err := formatNumber(json.Number(strconv.FormatFloat(value, 'g', -1, 64)))
+		if err != nil {
+			t.Errorf("%016x: %v", tc.bits, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%016x = %s, want %s", tc.bits, got, tc.want)
+		}
+	}
+
+	for _, number := range []json.Number{"1e400", "-1e400", "NaN"} {
+		if _, err := formatNumber(number); err == nil {
+			t.Errorf("formatNumber(%s) succeeded, want an error", number)
+		}
+	}
+}
+
+func TestCanonicalizeRejectsInvalidUTF8(t *testing.T) {
+	if _, err := canonicalize(map[string]any{"name": "caf\xe9"}); err == nil {
+		t.Error("canonicalize succeeded on invalid UTF-8")
+	}
+}
@@ -0,0 +1,107 @@
+package credential
+
+import "time"
+
+// JSON-LD contexts of an Open Badges 3.0 credential
+var contexts = []string{
+	"https://www.w3.org/ns/credentials/v2",
+	"https://purl.imsglobal.org/spec/ob/v3p0/context-3.0.3.json",
+}
+
+// Profile describes the organization issuing credentials
+type Profile struct {
+	Context            []string             `json:"@context,omitempty"`
+	ID                 string               `json:"id"`
+	Type               []string             `json:"type"`
+	Name               string               `json:"name"`
+	URL                string               `json:"url,omitempty"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod,omitempty"`
+}
+
+// VerificationMethod publishes the public key that signs the credentials of an issuer
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+// Achievement is the badge class earned by completing a course
+type Achievement struct {
+	Context     []string `json:"@context,omitempty"`
+	ID          string   `json:"id"`
+	Type        []string `json:"type"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Criteria    Criteria `json:"criteria"`
+	Creator     *Profile `json:"creator,omitempty"`
+}
+
+// Criteria explains what a student did to earn an achievement
+type Criteria struct {
+	Narrative string `json:"narrative"`
+}
+
+// Subject is the student a credential is issued to
+type Subject struct {
+	Type        []string         `json:"type"`
+	Identifier  []IdentityObject `json:"identifier"`
+	Achievement Achievement      `json:"achievement"`
+}
+
+// IdentityObject names the student by a salted hash of their email, so a credential does not reveal it
+type IdentityObject struct {
+	Type         string `json:"type"`
+	IdentityHash string `json:"identityHash"`
+	IdentityType string `json:"identityType"`
+	Hashed       bool   `json:"hashed"`
+	Salt         string `json:"salt"`
+}
+
+// Status points to the list a credential is looked up in to learn whether it was revoked
+type Status struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Proof is the signature of a credential
+type Proof struct {
+	Type               string `json:"type"`
+	Cryptosuite        string `json:"cryptosuite"`
+	Created            string `json:"created"`
+	VerificationMethod string `json:"verificationMethod"`
+	ProofPurpose       string `json:"proofPurpose"`
+	ProofValue         string `json:"proofValue,omitempty"`
+}
+
+// Credential is an Open Badges 3.0 OpenBadgeCredential, a W3C verifiable credential
+type Credential struct {
+	Context           []string `json:"@context"`
+	ID                string   `json:"id"`
+	Type              []string `json:"type"`
+	Issuer            Profile  `json:"issuer"`
+	ValidFrom         string   `json:"validFrom"`
+	Name              string   `json:"name"`
+	CredentialSubject Subject  `json:"credentialSubject"`
+	CredentialStatus  Status   `json:"credentialStatus"`
+	Proof             *Proof   `json:"proof,omitempty"`
+}
+
+// RevokedCredential is an entry of the revocation list
+type RevokedCredential struct {
+	ID               string `json:"id"`
+	RevocationReason string `json:"revocationReason,omitempty"`
+}
+
+// RevocationList is a 1EdTech revocation list of the credentials an issuer revoked
+type RevocationList struct {
+	ID                 string              `json:"id"`
+	Issuer             string              `json:"issuer"`
+	LastModified       string              `json:"lastModified"`
+	RevokedCredentials []RevokedCredential `json:"revokedCredentials"`
+}
+
+// formatTime is how times are written in a credential
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,307 @@
+package credential
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// the data integrity proof written on every credential
+const (
+	proofType    = "DataIntegrityProof"
+	cryptosuite  = "eddsa-jcs-2022"
+	proofPurpose = "assertionMethod"
+)
+
+// ed25519MulticodecPrefix marks an Ed25519 public key in publicKeyMultibase
+var ed25519MulticodecPrefix = []byte{0xed, 0x01}
+
+// Different types of error returned when a credential is verified
+var (
+	ErrNotConfigured   = errors.New("verifiable credentials are not configured")
+	ErrMalformed       = errors.New("credential is malformed")
+	ErrUnknownIssuer   = errors.New("credential was not issued by this issuer")
+	ErrInvalidProof    = errors.New("credential signature is invalid")
+	ErrUnsupportedType = errors.New("credential proof type is not supported")
+)
+
+// Issuer signs Open Badges credentials for completed courses and verifies the ones it signed
+type Issuer struct {
+	baseURL string
+	name    string
+	key     ed25519.PrivateKey
+}
+
+// NewIssuer creates an issuer whose documents are served below baseURL.
+// key is the base64 encoded Ed25519 private key, either the 32 byte seed or the 64 byte key.
+// ErrNotConfigured is returned when no key is set, credentials are then not issued at all.
+func NewIssuer(baseURL string, name string, key string) (*Issuer, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, ErrNotConfigured
+	}
+	baseURL = strings.TrimSuffix(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return nil, errors.New("a base URL is required to issue credentials")
+	}
+	if name == "" {
+		name = baseURL
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential signing key: %w", err)
+	}
+
+	var privateKey ed25519.PrivateKey
+	switch len(raw) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(raw)
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.PrivateKey(raw)
+	default:
+		return nil, fmt.Errorf("invalid credential signing key: expected %d or %d bytes, got %d",
+			ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+
+	return &Issuer{
+		baseURL: baseURL,
+		name:    name,
+		key:     privateKey,
+	}, nil
+}
+
+// ID is the URL of the issuer profile
+func (issuer *Issuer) ID() string {
+	return issuer.baseURL + "/badges/issuer"
+}
+
+// KeyID is the URL of the verification method that signs credentials
+func (issuer *Issuer) KeyID() string {
+	return issuer.ID() + "#key-1"
+}
+
+// AchievementID is the URL of the badge class of a course
+func (issuer *Issuer) AchievementID(courseID int64) string {
+	return issuer.baseURL + "/badges/class/" + strconv.FormatInt(courseID, 10)
+}
+
+// RevocationListID is the URL of the revocation list
+func (issuer *Issuer) RevocationListID() string {
+	return issuer.baseURL + "/badges/revocations"
+}
+
+// Profile is the issuer metadata, including the public key that verifies its credentials
+func (issuer *Issuer) Profile() Profile {
+	publicKey := issuer.key.Public().(ed25519.PublicKey)
+	return Profile{
+		Context: contexts,
+		ID:      issuer.ID(),
+		Type:    []string{"Profile"},
+		Name:    issuer.name,
+		URL:     issuer.baseURL,
+		VerificationMethod: []VerificationMethod{{
+			ID:                 issuer.KeyID(),
+			Type:               "Multikey",
+			Controller:         issuer.ID(),
+			PublicKeyMultibase: encodeMultibase(append(append([]byte{}, ed25519MulticodecPrefix...), publicKey...)),
+		}},
+	}
+}
+
+// reference is how the issuer is named inside its credentials and achievements
+func (issuer *Issuer) reference() Profile {
+	return Profile{
+		ID:   issuer.ID(),
+		Type: []string{"Profile"},
+		Name: issuer.name,
+	}
+}
+
+// Achievement is the badge class earned by completing a course
+func (issuer *Issuer) Achievement(courseID int64, title string, description string) Achievement {
+	creator := issuer.reference()
+	return Achievement{
+		ID:          issuer.AchievementID(courseID),
+		Type:        []string{"Achievement"},
+		Name:        title,
+		Description: description,
+		Criteria: Criteria{
+			Narrative: fmt.Sprintf("Completed every lesson of the course %s.", title),
+		},
+		Creator: &creator,
+	}
+}
+
+// Issue signs a credential stating that the student with the given email earned the achievement
+func (issuer *Issuer) Issue(email string, achievement Achievement, issuedAt time.Time) (Credential, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return Credential{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	saltHex := hex.EncodeToString(salt)
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email)) + saltHex))
+
+	achievement.Context = nil
+	credential := Credential{
+		Context:   contexts,
+		ID:        "urn:uuid:" + uuid.NewString(),
+		Type:      []string{"VerifiableCredential", "OpenBadgeCredential"},
+		Issuer:    issuer.reference(),
+		ValidFrom: formatTime(issuedAt),
+		Name:      achievement.Name,
+		CredentialSubject: Subject{
+			Type: []string{"AchievementSubject"},
+			Identifier: []IdentityObject{{
+				Type:         "IdentityObject",
+				IdentityHash: "sha256$" + hex.EncodeToString(hash[:]),
+				IdentityType: "emailAddress",
+				Hashed:       true,
+				Salt:         saltHex,
+			}},
+			Achievement: achievement,
+		},
+		CredentialStatus: Status{
+			ID:   issuer.RevocationListID(),
+			Type: "1EdTechRevocationList",
+		},
+	}
+
+	proof := Proof{
+		Type:               proofType,
+		Cryptosuite:        cryptosuite,
+		Created:            formatTime(issuedAt),
+		VerificationMethod: issuer.KeyID(),
+		ProofPurpose:       proofPurpose,
+	}
+	document, err := toJSONValue(credential)
+	if err != nil {
+		return Credential{}, err
+	}
+	options, err := toJSONValue(proof)
+	if err != nil {
+		return Credential{}, err
+	}
+	signed, err := hashData(document.(map[string]any), options.(map[string]any))
+	if err != nil {
+		return Credential{}, err
+	}
+
+	proof.ProofValue = encodeMultibase(ed25519.Sign(issuer.key, signed))
+	credential.Proof = &proof
+	return credential, nil
+}
+
+// Verify checks that a credential was signed by this issuer and was not altered, and returns its id.
+// Whether the credential was revoked is up to the caller, who keeps the revocation list.
+func (issuer *Issuer) Verify(data []byte) (string, error) {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	document, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("%w: not a JSON object", ErrMalformed)
+	}
+	id, _ := document["id"].(string)
+	if id == "" {
+		return "", fmt.Errorf("%w: missing id", ErrMalformed)
+	}
+
+	proof, ok := document["proof"].(map[string]any)
+	if !ok {
+		return id, fmt.Errorf("%w: missing proof", ErrMalformed)
+	}
+	if proof["type"] != proofType || proof["cryptosuite"] != cryptosuite {
+		return id, ErrUnsupportedType
+	}
+	if proof["verificationMethod"] != issuer.KeyID() || issuerID(document["issuer"]) != issuer.ID() {
+		return id, ErrUnknownIssuer
+	}
+	if proof["proofPurpose"] != proofPurpose {
+		return id, fmt.Errorf("%w: unexpected proof purpose", ErrInvalidProof)
+	}
+	proofValue, _ := proof["proofValue"].(string)
+	signature, err := decodeMultibase(proofValue)
+	if err != nil {
+		return id, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	unsecured := make(map[string]any, len(document))
+	for key, value := range document {
+		if key != "proof" {
+			unsecured[key] = value
+		}
+	}
+	options := make(map[string]any, len(proof))
+	for key, value := range proof {
+		if key != "proofValue" {
+			options[key] = value
+		}
+	}
+	signed, err := hashData(unsecured, options)
+	if err != nil {
+		return id, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	if !ed25519.Verify(issuer.key.Public().(ed25519.PublicKey), signed, signature) {
+		return id, ErrInvalidProof
+	}
+	return id, nil
+}
+
+// RevocationList lists the revoked credentials in the form linked from their credentialStatus
+func (issuer *Issuer) RevocationList(revoked []RevokedCredential, lastModified time.Time) RevocationList {
+	if revoked == nil {
+		revoked = []RevokedCredential{}
+	}
+	return RevocationList{
+		ID:                 issuer.RevocationListID(),
+		Issuer:             issuer.ID(),
+		LastModified:       formatTime(lastModified),
+		RevokedCredentials: revoked,
+	}
+}
+
+// hashData is the data signed by eddsa-jcs-2022: the hash of the canonical proof options,
+// which carry the document context, followed by the hash of the canonical document without its proof
+func hashData(document map[string]any, options map[string]any) ([]byte, error) {
+	config := make(map[string]any, len(options)+1)
+	for key, value := range options {
+		config[key] = value
+	}
+	config["@context"] = document["@context"]
+
+	canonicalConfig, err := canonicalize(config)
+	if err != nil {
+		return nil, err
+	}
+	canonicalDocument, err := canonicalize(document)
+	if err != nil {
+		return nil, err
+	}
+
+	configHash := sha256.Sum256(canonicalConfig)
+	documentHash := sha256.Sum256(canonicalDocument)
+	return append(configHash[:], documentHash[:]...), nil
+}
+
+// issuerID reads the issuer of a credential, which is either a URL or a profile with an id
+func issuerID(issuer any) string {
+	switch v := issuer.(type) {
+	case string:
+		return v
+	case map[string]any:
+		id, _ := v["id"].(string)
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testIssuer(t *testing.T, seed byte) *Issuer {
+	t.Helper()
+	issuer, err := NewIssuer("https://school.example/", "Example School", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{seed}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issuer
+}
+
+// issueJSON issues a credential for a course whose title needs escaping and returns it as a JSON object
+func issueJSON(t *testing.T, issuer *Issuer) map[string]any {
+	t.Helper()
+	achievement := issuer.Achievement(7, "Tom & Jerry <Intro>\u2028Part 2 😀", "Ünïcode \"quoted\" description")
+	credential, err := issuer.Issue("Student@Example.com", achievement, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+func marshal(t *testing.T, document map[string]any) []byte {
+	t.Helper()
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestIssueVerify(t *testing.T) {
+	issuer := testIssuer(t, 1)
+
+	testCases := []struct {
+		name   string
+		modify func(document map[string]any)
+		issuer *Issuer
+		err    error
+	}{
+		{
+			name:   "Valid",
+			modify: func(document map[string]any) {},
+		},
+		{
+			name: "AlteredName",
+			modify: func(document map[string]any) {
+				document["name"] = "Another course"
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "AlteredSubject",
+			modify: func(document map[string]any) {
+				subject := document["credentialSubject"].(map[string]any)
+				subject["identifier"].([]any)[0].(map[string]any)["salt"] = "00"
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "AddedField",
+			modify: func(document map[string]any) {
+				document["validUntil"] = "2030-01-01T00:00:00Z"
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "AlteredProofDate",
+			modify: func(document map[string]any) {
+				document["proof"].(map[string]any)["created"] = "2020-01-01T00:00:00Z"
+			},
+			err: ErrInvalidProof,
+		},
+		{
+			name: "OtherProofType",
+			modify: func(document map[string]any) {
+				document["proof"].(map[string]any)["cryptosuite"] = "eddsa-rdfc-2022"
+			},
+			err: ErrUnsupportedType,
+		},
+		{
+			name: "MissingProof",
+			modify: func(document map[string]any) {
+				delete(document, "proof")
+			},
+			err: ErrMalformed,
+		},
+		{
+			name:   "OtherKey",
+			modify: func(document map[string]any) {},
+			issuer: testIssuer(t, 2),
+			err:    ErrInvalidProof,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			document := issueJSON(t, issuer)
+			id := document["id"].(string)
+			tc.modify(document)
+
+			verifier := issuer
+			if tc.issuer != nil {
+				verifier = tc.issuer
+			}
+			got, err := verifier.Verify(marshal(t, document))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Verify error = %v, want %v", err, tc.err)
+			}
+			if got != id {
+				t.Errorf("Verify id = %q, want %q", got, id)
+			}
+		})
+	}
+}
+
+// a verifier may reformat the credential, only its canonical form is signed
+func TestVerifyIgnoresFormatting(t *testing.T) {
+	issuer := testIssuer(t, 1)
+	document := issueJSON(t, issuer)
+
+	// encoding/json escapes HTML characters and line separators, the signature must not depend on it
+	escaped, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(escaped, []byte(`\u0026`)) || !bytes.Contains(escaped, []byte(`\u2028`)) {
+		t.Fatalf("expected escaped characters in %s", escaped)
+	}
+	unescaped := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(unescaped)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(document); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{escaped, unescaped.Bytes()} {
+		if _, err := issuer.Verify(data); err != nil {
+			t.Errorf("Verify(%s) = %v", data, err)
+		}
+	}
+}
+
+func TestIssueHashesEmail(t *testing.T) {
+	issuer := testIssuer(t, 1)
+	document := issueJSON(t, issuer)
+	if data := marshal(t, document); bytes.Contains(bytes.ToLower(data), []byte("student@example.com")) {
+		t.Errorf("credential reveals the email: %s", data)
+	}
+
+	identifier := document["credentialSubject"].(map[string]any)["identifier"].([]any)[0].(map[string]any)
+	if hash, _ := identifier["identityHash"].(string); !strings.HasPrefix(hash, "sha256$") {
+		t.Errorf("identityHash = %q, want a sha256 hash", hash)
+	}
+}
+
+func TestNewIssuer(t *testing.T) {
+	if _, err := NewIssuer("https://school.example", "", ""); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("NewIssuer without key = %v, want ErrNotConfigured", err)
+	}
+	if _, err := NewIssuer("https://school.example", "", base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("NewIssuer accepted a short key")
+	}
+
+	issuer := testIssuer(t, 1)
+	if issuer.KeyID() != "https://school.example/badges/issuer#key-1" {
+		t.Errorf("KeyID = %q", issuer.KeyID())
+	}
+	method := issuer.Profile().VerificationMethod[0]
+	key, err := decodeMultibase(method.PublicKeyMultibase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(key, ed25519MulticodecPrefix) || len(key) != len(ed25519MulticodecPrefix)+32 {
+		t.Errorf("publicKeyMultibase decodes to %x, want a multicodec Ed25519 key", key)
+	}
+}
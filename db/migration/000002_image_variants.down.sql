@@ -0,0 +1,3 @@
+ALTER TABLE "profile_pictures" DROP COLUMN IF EXISTS "picture_variants";
+
+ALTER TABLE "courses" DROP COLUMN IF EXISTS "image_variants";
@@ -0,0 +1,3 @@
+ALTER TABLE "courses" ADD COLUMN "image_variants" jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE "profile_pictures" ADD COLUMN "picture_variants" jsonb NOT NULL DEFAULT '{}'::jsonb;
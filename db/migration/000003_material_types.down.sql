@@ -0,0 +1,9 @@
+ALTER TABLE "material" DROP CONSTRAINT IF EXISTS "material_type_check";
+
+ALTER TABLE "material" DROP COLUMN IF EXISTS "external_url";
+
+ALTER TABLE "material" DROP COLUMN IF EXISTS "content_html";
+
+ALTER TABLE "material" DROP COLUMN IF EXISTS "content";
+
+ALTER TABLE "material" DROP COLUMN IF EXISTS "material_type";
@@ -0,0 +1,10 @@
+ALTER TABLE "material" ADD COLUMN "material_type" varchar NOT NULL DEFAULT 'file';
+
+ALTER TABLE "material" ADD COLUMN "content" text NOT NULL DEFAULT '';
+
+ALTER TABLE "material" ADD COLUMN "content_html" text NOT NULL DEFAULT '';
+
+ALTER TABLE "material" ADD COLUMN "external_url" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "material" ADD CONSTRAINT "material_type_check"
+  CHECK ("material_type" IN ('file', 'markdown', 'link', 'video'));
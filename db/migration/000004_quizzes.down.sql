@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS quiz_attempts;
+DROP TABLE IF EXISTS quiz_pools;
+DROP TABLE IF EXISTS quizzes;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS question_banks;
@@ -0,0 +1,79 @@
+CREATE TABLE "question_banks" (
+  "bank_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "title" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "questions" (
+  "question_id" bigserial PRIMARY KEY,
+  "bank_id" bigint NOT NULL,
+  "question_type" varchar NOT NULL,
+  "prompt" text NOT NULL,
+  "options" jsonb NOT NULL DEFAULT '[]'::jsonb,
+  "answer_key" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "points" bigint NOT NULL DEFAULT 1,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "question_type_check" CHECK ("question_type" IN ('single_choice', 'multiple_choice', 'true_false', 'short_answer', 'numeric'))
+);
+
+CREATE TABLE "quizzes" (
+  "quiz_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "material_id" bigint,
+  "title" varchar NOT NULL,
+  "time_limit_seconds" bigint NOT NULL DEFAULT 0,
+  "max_attempts" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "quiz_pools" (
+  "pool_id" bigserial PRIMARY KEY,
+  "quiz_id" bigint NOT NULL,
+  "bank_id" bigint NOT NULL,
+  "draw_count" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "quiz_attempts" (
+  "attempt_id" bigserial PRIMARY KEY,
+  "quiz_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "question_ids" bigint[] NOT NULL,
+  "responses" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "score" bigint NOT NULL DEFAULT 0,
+  "max_score" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'in_progress',
+  "started_at" timestamptz NOT NULL DEFAULT (now()),
+  "expires_at" timestamptz,
+  "submitted_at" timestamptz
+);
+
+CREATE INDEX ON "question_banks" ("course_id");
+
+CREATE INDEX ON "questions" ("bank_id");
+
+CREATE INDEX ON "quizzes" ("course_id");
+
+CREATE INDEX ON "quiz_pools" ("quiz_id");
+
+CREATE INDEX ON "quiz_attempts" ("quiz_id", "user_id");
+
+ALTER TABLE "question_banks" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "questions" ADD FOREIGN KEY ("bank_id") REFERENCES "question_banks" ("bank_id") ON DELETE CASCADE;
+
+ALTER TABLE "quizzes" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "quizzes" ADD FOREIGN KEY ("material_id") REFERENCES "material" ("material_id") ON DELETE SET NULL;
+
+ALTER TABLE "quiz_pools" ADD FOREIGN KEY ("quiz_id") REFERENCES "quizzes" ("quiz_id") ON DELETE CASCADE;
+
+ALTER TABLE "quiz_pools" ADD FOREIGN KEY ("bank_id") REFERENCES "question_banks" ("bank_id") ON DELETE CASCADE;
+
+ALTER TABLE "quiz_attempts" ADD FOREIGN KEY ("quiz_id") REFERENCES "quizzes" ("quiz_id") ON DELETE CASCADE;
+
+ALTER TABLE "quiz_attempts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
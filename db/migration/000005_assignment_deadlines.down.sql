@@ -0,0 +1,19 @@
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "penalty_percent";
+
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "is_late";
+
+DROP TABLE IF EXISTS assignment_extensions;
+
+ALTER TABLE "assignment" DROP CONSTRAINT IF EXISTS "late_penalty_percent_check";
+
+ALTER TABLE "assignment" DROP CONSTRAINT IF EXISTS "late_policy_check";
+
+ALTER TABLE "assignment" DROP COLUMN IF EXISTS "late_cutoff";
+
+ALTER TABLE "assignment" DROP COLUMN IF EXISTS "late_penalty_percent";
+
+ALTER TABLE "assignment" DROP COLUMN IF EXISTS "late_policy";
+
+ALTER TABLE "assignment" ALTER COLUMN "due_date" TYPE varchar USING COALESCE(to_char("due_date" AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), '');
+
+ALTER TABLE "assignment" ALTER COLUMN "due_date" SET NOT NULL;
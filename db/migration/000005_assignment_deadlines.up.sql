@@ -0,0 +1,109 @@
+-- parse_due_date converts the free text due dates stored so far. It accepts only the layouts of
+-- util.ParseDueDate with the same checks, so 2024-02-30 or 25:00 are refused instead of rolled over:
+--   2006-01-02T15:04:05Z07:00 (fractional seconds allowed), 2006-01-02T15:04, 2006-01-02 15:04:05, 2006-01-02 15:04,
+--   and the dates 2006-01-02, 2006/01/02 and 01/02/2006, which are due at the last second of the day.
+-- Values without an offset are read in zone, never in the session TimeZone. It returns NULL for a value it cannot read.
+CREATE FUNCTION parse_due_date(value varchar, zone text) RETURNS timestamptz AS $$
+DECLARE
+  trimmed varchar := btrim(value);
+  m text[];
+  local_time timestamp;
+  utc_offset interval;
+BEGIN
+  m := regexp_match(trimmed, '^(\d{4})-(\d{2})-(\d{2})T(\d{1,2}):(\d{2}):(\d{2}(?:\.\d+)?)(?:Z|([+-])(\d{2}):(\d{2}))$');
+  IF m IS NOT NULL THEN
+    IF m[4]::int > 23 OR m[5]::int > 59 OR m[6]::numeric >= 60 OR coalesce(m[8]::int, 0) > 23 OR coalesce(m[9]::int, 0) > 59 THEN
+      RETURN NULL;
+    END IF;
+    local_time := make_timestamp(m[1]::int, m[2]::int, m[3]::int, m[4]::int, m[5]::int, m[6]::float8);
+    utc_offset := make_interval(hours => coalesce(m[8]::int, 0), mins => coalesce(m[9]::int, 0));
+    IF m[7] = '-' THEN
+      utc_offset := -utc_offset;
+    END IF;
+    RETURN (local_time - utc_offset) AT TIME ZONE 'UTC';
+  END IF;
+
+  m := regexp_match(trimmed, '^(\d{4})-(\d{2})-(\d{2})(?:T(\d{1,2}):(\d{2})| (\d{1,2}):(\d{2})(?::(\d{2}(?:\.\d+)?))?)$');
+  IF m IS NOT NULL THEN
+    m[4] := coalesce(m[4], m[6]);
+    m[5] := coalesce(m[5], m[7]);
+    IF m[4]::int > 23 OR m[5]::int > 59 OR coalesce(m[8]::numeric, 0) >= 60 THEN
+      RETURN NULL;
+    END IF;
+    local_time := make_timestamp(m[1]::int, m[2]::int, m[3]::int, m[4]::int, m[5]::int, coalesce(m[8]::float8, 0));
+    RETURN local_time AT TIME ZONE zone;
+  END IF;
+
+  -- dates without a time are due at the end of the day
+  m := coalesce(regexp_match(trimmed, '^(\d{4})-(\d{2})-(\d{2})$'), regexp_match(trimmed, '^(\d{4})/(\d{2})/(\d{2})$'));
+  IF m IS NULL THEN
+    m := regexp_match(trimmed, '^(\d{2})/(\d{2})/(\d{4})$');
+    m := ARRAY[m[3], m[1], m[2]];
+  END IF;
+  IF m[1] IS NOT NULL THEN
+    local_time := make_timestamp(m[1]::int, m[2]::int, m[3]::int, 23, 59, 59);
+    RETURN local_time AT TIME ZONE zone;
+  END IF;
+
+  RETURN NULL;
+EXCEPTION WHEN datetime_field_overflow THEN
+  -- make_timestamp refuses days a month does not have
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- zone-less due dates are read in the time zone the API runs in, UTC unless the migration is run with
+-- PGOPTIONS='-c eduapp.due_date_timezone=Europe/Paris' (or the options parameter of the database URL).
+-- Values no layout matches stop the migration and are listed, so none of them silently loses its deadline.
+DO $$
+DECLARE
+  zone text := coalesce(nullif(current_setting('eduapp.due_date_timezone', true), ''), 'UTC');
+  unreadable text;
+BEGIN
+  SELECT string_agg(format('%s: %L', "assignment_id", "due_date"), ', ' ORDER BY "assignment_id") INTO unreadable
+  FROM "assignment"
+  WHERE btrim("due_date") <> '' AND parse_due_date("due_date", zone) IS NULL;
+
+  IF unreadable IS NOT NULL THEN
+    RAISE EXCEPTION 'assignment due dates that cannot be read: %', unreadable
+      USING HINT = 'correct these values, or set them to an empty string to leave the assignment without a deadline, and run the migration again';
+  END IF;
+
+  ALTER TABLE "assignment" ALTER COLUMN "due_date" DROP NOT NULL;
+
+  EXECUTE format('ALTER TABLE "assignment" ALTER COLUMN "due_date" TYPE timestamptz USING parse_due_date("due_date", %L)', zone);
+END;
+$$;
+
+DROP FUNCTION parse_due_date(varchar, text);
+
+ALTER TABLE "assignment" ADD COLUMN "late_policy" varchar NOT NULL DEFAULT 'accept';
+
+ALTER TABLE "assignment" ADD COLUMN "late_penalty_percent" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "assignment" ADD COLUMN "late_cutoff" timestamptz;
+
+ALTER TABLE "assignment" ADD CONSTRAINT "late_policy_check"
+  CHECK ("late_policy" IN ('accept', 'penalty', 'reject'));
+
+ALTER TABLE "assignment" ADD CONSTRAINT "late_penalty_percent_check"
+  CHECK ("late_penalty_percent" BETWEEN 0 AND 100);
+
+CREATE INDEX ON "assignment" ("course_id", "due_date");
+
+CREATE TABLE "assignment_extensions" (
+  "extension_id" bigserial PRIMARY KEY,
+  "assignment_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "due_date" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("assignment_id", "user_id")
+);
+
+ALTER TABLE "assignment_extensions" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "assignment_extensions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "submission" ADD COLUMN "is_late" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "submission" ADD COLUMN "penalty_percent" bigint NOT NULL DEFAULT 0;
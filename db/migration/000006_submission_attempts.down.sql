@@ -0,0 +1,5 @@
+ALTER TABLE "assignment" DROP COLUMN IF EXISTS "max_attempts";
+
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "graded_attempt_id";
+
+DROP TABLE IF EXISTS submission_attempts;
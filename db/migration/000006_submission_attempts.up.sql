@@ -0,0 +1,25 @@
+CREATE TABLE "submission_attempts" (
+  "attempt_id" bigserial PRIMARY KEY,
+  "submission_id" bigint NOT NULL,
+  "attempt_number" bigint NOT NULL,
+  "resource" varchar NOT NULL,
+  "checksum" varchar NOT NULL,
+  "size" bigint NOT NULL DEFAULT 0,
+  "is_late" boolean NOT NULL DEFAULT false,
+  "penalty_percent" bigint NOT NULL DEFAULT 0,
+  "submitted_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("submission_id", "attempt_number")
+);
+
+ALTER TABLE "submission_attempts" ADD FOREIGN KEY ("submission_id") REFERENCES "submission" ("submission_id") ON DELETE CASCADE;
+
+ALTER TABLE "submission" ADD COLUMN "graded_attempt_id" bigint;
+
+ALTER TABLE "submission" ADD FOREIGN KEY ("graded_attempt_id") REFERENCES "submission_attempts" ("attempt_id") ON DELETE SET NULL;
+
+ALTER TABLE "assignment" ADD COLUMN "max_attempts" bigint NOT NULL DEFAULT 1;
+
+-- existing submissions become their first attempt, their checksum is unknown
+INSERT INTO "submission_attempts" ("submission_id", "attempt_number", "resource", "checksum", "is_late", "penalty_percent", "submitted_at")
+SELECT "submission_id", 1, "resource", '', "is_late", "penalty_percent", "date_of_submission"
+FROM "submission";
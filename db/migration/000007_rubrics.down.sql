@@ -0,0 +1,7 @@
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "graded_at";
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "feedback";
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "rubric_scores";
+
+ALTER TABLE "assignment" DROP COLUMN IF EXISTS "grades_published_at";
+
+DROP TABLE IF EXISTS rubrics;
@@ -0,0 +1,25 @@
+CREATE TABLE "rubrics" (
+  "rubric_id" bigserial PRIMARY KEY,
+  "assignment_id" bigint UNIQUE NOT NULL,
+  "title" varchar NOT NULL,
+  "criteria" jsonb NOT NULL DEFAULT '[]',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "rubrics" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "assignment" ADD COLUMN "grades_published_at" timestamptz;
+
+ALTER TABLE "submission" ADD COLUMN "rubric_scores" jsonb NOT NULL DEFAULT '[]';
+ALTER TABLE "submission" ADD COLUMN "feedback" text NOT NULL DEFAULT '';
+ALTER TABLE "submission" ADD COLUMN "graded_at" timestamptz;
+
+-- grades given before publishing existed were already visible to students
+UPDATE "submission" SET "graded_at" = "updated_at" WHERE "grade" <> 'null';
+
+UPDATE "assignment" SET "grades_published_at" = now()
+WHERE EXISTS (
+  SELECT 1 FROM "submission"
+  WHERE "submission"."assignment_id" = "assignment"."assignment_id" AND "submission"."graded_at" IS NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS grade_scales;
+DROP TABLE IF EXISTS grade_item_scores;
+DROP TABLE IF EXISTS grade_items;
+DROP TABLE IF EXISTS grade_categories;
@@ -0,0 +1,61 @@
+CREATE TABLE "grade_categories" (
+  "category_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "title" varchar NOT NULL,
+  "weight" double precision NOT NULL DEFAULT 0,
+  "drop_lowest" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "grade_items" (
+  "item_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "category_id" bigint NOT NULL,
+  "title" varchar NOT NULL,
+  "item_type" varchar NOT NULL DEFAULT 'manual',
+  "assignment_id" bigint,
+  "quiz_id" bigint,
+  "max_points" double precision NOT NULL DEFAULT 100,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "grade_item_scores" (
+  "score_id" bigserial PRIMARY KEY,
+  "item_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "points" double precision NOT NULL,
+  "comment" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("item_id", "user_id")
+);
+
+CREATE TABLE "grade_scales" (
+  "course_id" bigint PRIMARY KEY,
+  "letters" jsonb NOT NULL DEFAULT '[]',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "grade_categories" ("course_id");
+
+CREATE INDEX ON "grade_items" ("course_id");
+
+CREATE INDEX ON "grade_items" ("category_id");
+
+ALTER TABLE "grade_categories" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_items" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_items" ADD FOREIGN KEY ("category_id") REFERENCES "grade_categories" ("category_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_items" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_items" ADD FOREIGN KEY ("quiz_id") REFERENCES "quizzes" ("quiz_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_item_scores" ADD FOREIGN KEY ("item_id") REFERENCES "grade_items" ("item_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_item_scores" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "grade_scales" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS similarity_reports;
+DROP TABLE IF EXISTS submission_texts;
@@ -0,0 +1,34 @@
+CREATE TABLE "submission_texts" (
+  "attempt_id" bigint PRIMARY KEY,
+  "assignment_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "content" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "similarity_reports" (
+  "report_id" bigserial PRIMARY KEY,
+  "assignment_id" bigint NOT NULL,
+  "attempt_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "matched_attempt_id" bigint NOT NULL,
+  "matched_user_id" bigint NOT NULL,
+  "score" double precision NOT NULL,
+  "matches" jsonb NOT NULL DEFAULT '[]',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("attempt_id", "matched_attempt_id")
+);
+
+CREATE INDEX ON "submission_texts" ("assignment_id", "user_id");
+
+CREATE INDEX ON "similarity_reports" ("assignment_id");
+
+ALTER TABLE "submission_texts" ADD FOREIGN KEY ("attempt_id") REFERENCES "submission_attempts" ("attempt_id") ON DELETE CASCADE;
+
+ALTER TABLE "submission_texts" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "similarity_reports" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "similarity_reports" ADD FOREIGN KEY ("attempt_id") REFERENCES "submission_attempts" ("attempt_id") ON DELETE CASCADE;
+
+ALTER TABLE "similarity_reports" ADD FOREIGN KEY ("matched_attempt_id") REFERENCES "submission_attempts" ("attempt_id") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS peer_reviews;
+DROP TABLE IF EXISTS peer_review_settings;
@@ -0,0 +1,34 @@
+CREATE TABLE "peer_review_settings" (
+  "assignment_id" bigint PRIMARY KEY,
+  "reviews_per_submission" bigint NOT NULL DEFAULT 3,
+  "review_due_at" timestamptz NOT NULL,
+  "assigned_at" timestamptz,
+  "reminded_at" timestamptz,
+  "finalized_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("reviews_per_submission" BETWEEN 1 AND 10)
+);
+
+CREATE TABLE "peer_reviews" (
+  "review_id" bigserial PRIMARY KEY,
+  "assignment_id" bigint NOT NULL,
+  "submission_id" bigint NOT NULL,
+  "reviewer_id" bigint NOT NULL,
+  "rubric_scores" jsonb NOT NULL DEFAULT '[]',
+  "points" bigint NOT NULL DEFAULT 0,
+  "comment" text NOT NULL DEFAULT '',
+  "submitted_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("submission_id", "reviewer_id")
+);
+
+CREATE INDEX ON "peer_reviews" ("assignment_id", "reviewer_id");
+
+ALTER TABLE "peer_review_settings" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "peer_reviews" ADD FOREIGN KEY ("assignment_id") REFERENCES "assignment" ("assignment_id") ON DELETE CASCADE;
+
+ALTER TABLE "peer_reviews" ADD FOREIGN KEY ("submission_id") REFERENCES "submission" ("submission_id") ON DELETE CASCADE;
+
+ALTER TABLE "peer_reviews" ADD FOREIGN KEY ("reviewer_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+ALTER TABLE "submission" DROP CONSTRAINT IF EXISTS "submission_assignment_group_key";
+
+ALTER TABLE "submission" DROP COLUMN IF EXISTS "group_id";
+
+ALTER TABLE "assignment" DROP COLUMN IF EXISTS "group_submission";
+
+DROP TABLE IF EXISTS submission_adjustments;
+DROP TABLE IF EXISTS course_group_members;
+DROP TABLE IF EXISTS course_groups;
@@ -0,0 +1,48 @@
+CREATE TABLE "course_groups" (
+  "group_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("course_id", "name"),
+  UNIQUE ("group_id", "course_id")
+);
+
+-- course_id is repeated on members so a student belongs to at most one group per course
+CREATE TABLE "course_group_members" (
+  "group_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("group_id", "user_id"),
+  UNIQUE ("course_id", "user_id")
+);
+
+CREATE TABLE "submission_adjustments" (
+  "adjustment_id" bigserial PRIMARY KEY,
+  "submission_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "points" double precision NOT NULL,
+  "reason" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("submission_id", "user_id")
+);
+
+ALTER TABLE "course_groups" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "course_group_members" ADD FOREIGN KEY ("group_id", "course_id") REFERENCES "course_groups" ("group_id", "course_id") ON DELETE CASCADE;
+
+ALTER TABLE "course_group_members" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "submission_adjustments" ADD FOREIGN KEY ("submission_id") REFERENCES "submission" ("submission_id") ON DELETE CASCADE;
+
+ALTER TABLE "submission_adjustments" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "assignment" ADD COLUMN "group_submission" boolean NOT NULL DEFAULT false;
+
+-- a group assignment keeps one submission per group, user_id is the member who uploaded first
+ALTER TABLE "submission" ADD COLUMN "group_id" bigint;
+
+ALTER TABLE "submission" ADD FOREIGN KEY ("group_id") REFERENCES "course_groups" ("group_id") ON DELETE SET NULL;
+
+ALTER TABLE "submission" ADD CONSTRAINT "submission_assignment_group_key" UNIQUE ("assignment_id", "group_id");
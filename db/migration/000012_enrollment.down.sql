@@ -0,0 +1,7 @@
+ALTER TABLE "subscriptions" DROP CONSTRAINT IF EXISTS "subscriptions_user_course_key";
+
+ALTER TABLE "subscriptions" DROP COLUMN IF EXISTS "denied_at";
+
+ALTER TABLE "subscriptions" DROP COLUMN IF EXISTS "waitlisted_at";
+
+DROP TABLE IF EXISTS course_enrollment_settings;
@@ -0,0 +1,27 @@
+CREATE TABLE "course_enrollment_settings" (
+  "course_id" bigint PRIMARY KEY,
+  "mode" varchar NOT NULL DEFAULT 'approval',
+  "capacity" bigint NOT NULL DEFAULT 0,
+  "enrollment_starts_at" timestamptz,
+  "enrollment_ends_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("mode" IN ('open', 'approval', 'invite', 'closed')),
+  CHECK ("capacity" >= 0),
+  CHECK ("enrollment_starts_at" < "enrollment_ends_at")
+);
+
+ALTER TABLE "course_enrollment_settings" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "subscriptions" ADD COLUMN "waitlisted_at" timestamptz;
+
+ALTER TABLE "subscriptions" ADD COLUMN "denied_at" timestamptz;
+
+-- the subscription worker used to insert a second, inactive row for every request
+DELETE FROM "subscriptions" s
+USING "subscriptions" d
+WHERE s.user_id = d.user_id AND s.course_id = d.course_id AND s.subscription_id > d.subscription_id;
+
+ALTER TABLE "subscriptions" ADD CONSTRAINT "subscriptions_user_course_key" UNIQUE ("user_id", "course_id");
+
+CREATE INDEX ON "subscriptions" ("course_id", "waitlisted_at") WHERE "waitlisted_at" IS NOT NULL;
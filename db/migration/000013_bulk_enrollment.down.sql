@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS course_invites;
+DROP TABLE IF EXISTS enrollment_imports;
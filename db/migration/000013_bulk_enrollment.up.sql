@@ -0,0 +1,41 @@
+CREATE TABLE "enrollment_imports" (
+  "import_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "created_by" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'queued',
+  "total_rows" bigint NOT NULL,
+  "processed_rows" bigint NOT NULL DEFAULT 0,
+  "rows" jsonb NOT NULL DEFAULT '[]',
+  "results" jsonb NOT NULL DEFAULT '[]',
+  "error" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "started_at" timestamptz,
+  "finished_at" timestamptz,
+  CHECK ("status" IN ('queued', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX ON "enrollment_imports" ("course_id");
+
+CREATE TABLE "course_invites" (
+  "invite_id" bigserial PRIMARY KEY,
+  "course_id" bigint NOT NULL,
+  "code" varchar NOT NULL UNIQUE,
+  "max_uses" bigint NOT NULL DEFAULT 0,
+  "uses" bigint NOT NULL DEFAULT 0,
+  "expires_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("max_uses" >= 0),
+  CHECK ("uses" >= 0)
+);
+
+CREATE INDEX ON "course_invites" ("course_id");
+
+ALTER TABLE "enrollment_imports" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "enrollment_imports" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "course_invites" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "course_invites" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("user_id") ON DELETE CASCADE;
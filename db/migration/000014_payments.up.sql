@@ -0,0 +1,67 @@
+CREATE TABLE "course_prices" (
+  "course_id" bigint PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("amount" > 0)
+);
+
+CREATE TABLE "coupons" (
+  "coupon_id" bigserial PRIMARY KEY,
+  "code" varchar NOT NULL UNIQUE,
+  "course_id" bigint,
+  "kind" varchar NOT NULL,
+  "value" bigint NOT NULL,
+  "currency" varchar NOT NULL DEFAULT '',
+  "max_uses" bigint NOT NULL DEFAULT 0,
+  "uses" bigint NOT NULL DEFAULT 0,
+  "expires_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("kind" IN ('percent', 'fixed')),
+  CHECK ("value" > 0),
+  CHECK ("kind" <> 'percent' OR "value" <= 100),
+  CHECK ("kind" <> 'fixed' OR "currency" <> ''),
+  CHECK ("max_uses" >= 0),
+  CHECK ("uses" >= 0)
+);
+
+CREATE TABLE "orders" (
+  "order_id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "coupon_id" bigint,
+  "currency" varchar NOT NULL,
+  "list_amount" bigint NOT NULL,
+  "discount_amount" bigint NOT NULL DEFAULT 0,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "provider" varchar NOT NULL,
+  "checkout_session_id" varchar UNIQUE,
+  "payment_id" varchar NOT NULL DEFAULT '',
+  "paid_at" timestamptz,
+  "refunded_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("status" IN ('pending', 'paid', 'failed', 'expired', 'refunded')),
+  CHECK ("amount" >= 0 AND "discount_amount" >= 0 AND "amount" + "discount_amount" = "list_amount")
+);
+
+CREATE INDEX ON "orders" ("user_id");
+
+CREATE INDEX ON "orders" ("course_id");
+
+CREATE INDEX ON "orders" ("payment_id") WHERE "payment_id" <> '';
+
+ALTER TABLE "course_prices" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "coupons" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "coupons" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("coupon_id") REFERENCES "coupons" ("coupon_id") ON DELETE SET NULL;
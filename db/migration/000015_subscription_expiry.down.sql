@@ -0,0 +1,11 @@
+ALTER TABLE "course_enrollment_settings" DROP COLUMN IF EXISTS "access_starts_at";
+
+ALTER TABLE "course_enrollment_settings" DROP COLUMN IF EXISTS "access_days";
+
+ALTER TABLE "subscriptions" DROP COLUMN IF EXISTS "expired_at";
+
+ALTER TABLE "subscriptions" DROP COLUMN IF EXISTS "expiry_reminded_at";
+
+ALTER TABLE "subscriptions" DROP COLUMN IF EXISTS "expires_at";
+
+ALTER TABLE "subscriptions" DROP COLUMN IF EXISTS "starts_at";
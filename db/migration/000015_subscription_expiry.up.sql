@@ -0,0 +1,20 @@
+ALTER TABLE "subscriptions" ADD COLUMN "starts_at" timestamptz;
+
+ALTER TABLE "subscriptions" ADD COLUMN "expires_at" timestamptz;
+
+ALTER TABLE "subscriptions" ADD COLUMN "expiry_reminded_at" timestamptz;
+
+ALTER TABLE "subscriptions" ADD COLUMN "expired_at" timestamptz;
+
+ALTER TABLE "subscriptions" ADD CHECK ("starts_at" < "expires_at");
+
+-- existing students keep access from the day they subscribed, with no end
+UPDATE "subscriptions" SET "starts_at" = "created_at" WHERE "active" = true;
+
+CREATE INDEX ON "subscriptions" ("expires_at") WHERE "active" = true;
+
+ALTER TABLE "course_enrollment_settings" ADD COLUMN "access_days" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "course_enrollment_settings" ADD COLUMN "access_starts_at" timestamptz;
+
+ALTER TABLE "course_enrollment_settings" ADD CHECK ("access_days" >= 0);
@@ -0,0 +1,18 @@
+CREATE TABLE "certificates" (
+  "certificate_id" bigserial PRIMARY KEY,
+  "code" varchar NOT NULL UNIQUE,
+  "user_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "student_name" varchar NOT NULL,
+  "course_title" varchar NOT NULL,
+  "instructor_name" varchar NOT NULL DEFAULT '',
+  "file" varchar NOT NULL,
+  "issued_at" timestamptz NOT NULL DEFAULT (now()),
+  "emailed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("user_id", "course_id")
+);
+
+ALTER TABLE "certificates" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "certificates" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
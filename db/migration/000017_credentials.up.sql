@@ -0,0 +1,18 @@
+CREATE TABLE "credentials" (
+  "credential_id" bigserial PRIMARY KEY,
+  "credential_uri" varchar NOT NULL UNIQUE,
+  "user_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "document" jsonb NOT NULL,
+  "issued_at" timestamptz NOT NULL DEFAULT (now()),
+  "revoked_at" timestamptz,
+  "revocation_reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("user_id", "course_id")
+);
+
+CREATE INDEX ON "credentials" ("revoked_at") WHERE "revoked_at" IS NOT NULL;
+
+ALTER TABLE "credentials" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "credentials" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+ALTER TABLE "lesson_completion" DROP CONSTRAINT IF EXISTS "lesson_completion_material_id_fkey";
+
+ALTER TABLE "lesson_completion" DROP CONSTRAINT IF EXISTS "lesson_completion_user_id_material_id_key";
+
+ALTER TABLE "course_progress" DROP COLUMN IF EXISTS "completed_at";
+
+ALTER TABLE "course_progress" DROP CONSTRAINT IF EXISTS "course_progress_user_id_course_id_key";
+
+DROP TABLE IF EXISTS course_progress_weights;
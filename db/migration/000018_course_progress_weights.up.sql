@@ -0,0 +1,36 @@
+CREATE TABLE "course_progress_weights" (
+  "course_id" bigint PRIMARY KEY,
+  "material_weight" bigint NOT NULL DEFAULT 1,
+  "assignment_weight" bigint NOT NULL DEFAULT 1,
+  "quiz_weight" bigint NOT NULL DEFAULT 1,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("material_weight" >= 0 AND "assignment_weight" >= 0 AND "quiz_weight" >= 0),
+  CHECK ("material_weight" + "assignment_weight" + "quiz_weight" > 0)
+);
+
+ALTER TABLE "course_progress_weights" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+-- a student has one progress row per course, keep the furthest one
+DELETE FROM "course_progress" a USING "course_progress" b
+WHERE a."user_id" = b."user_id"
+  AND a."course_id" = b."course_id"
+  AND (a."progress" < b."progress" OR (a."progress" = b."progress" AND a."courseprogress_id" < b."courseprogress_id"));
+
+ALTER TABLE "course_progress" ADD UNIQUE ("user_id", "course_id");
+
+ALTER TABLE "course_progress" ADD COLUMN "completed_at" timestamptz;
+
+UPDATE "course_progress" SET "completed_at" = "updated_at" WHERE "progress" >= 100;
+
+-- completions of lessons that were deleted no longer count
+DELETE FROM "lesson_completion" WHERE "material_id" NOT IN (SELECT "material_id" FROM "material");
+
+DELETE FROM "lesson_completion" a USING "lesson_completion" b
+WHERE a."user_id" = b."user_id"
+  AND a."material_id" = b."material_id"
+  AND a."completion_id" > b."completion_id";
+
+ALTER TABLE "lesson_completion" ADD UNIQUE ("user_id", "material_id");
+
+ALTER TABLE "lesson_completion" ADD FOREIGN KEY ("material_id") REFERENCES "material" ("material_id") ON DELETE CASCADE;
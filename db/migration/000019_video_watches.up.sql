@@ -0,0 +1,20 @@
+CREATE TABLE "video_watches" (
+  "watch_id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "material_id" bigint NOT NULL,
+  "segments" jsonb NOT NULL DEFAULT '[]',
+  "watched_seconds" double precision NOT NULL DEFAULT 0,
+  "duration_seconds" double precision NOT NULL DEFAULT 0,
+  "position_seconds" double precision NOT NULL DEFAULT 0,
+  "furthest_seconds" double precision NOT NULL DEFAULT 0,
+  "completed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("user_id", "material_id")
+);
+
+CREATE INDEX ON "video_watches" ("material_id");
+
+ALTER TABLE "video_watches" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "video_watches" ADD FOREIGN KEY ("material_id") REFERENCES "material" ("material_id") ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+CREATE TABLE "xapi_statements" (
+  "statement_id" uuid PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "verb_id" varchar NOT NULL,
+  "activity_id" varchar NOT NULL DEFAULT '',
+  "statement" jsonb NOT NULL,
+  "voided" boolean NOT NULL DEFAULT false,
+  "stored" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "xapi_statements" ("stored");
+
+CREATE INDEX ON "xapi_statements" ("actor");
+
+CREATE INDEX ON "xapi_statements" ("activity_id");
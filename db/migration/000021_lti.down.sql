@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS lti_deep_links;
+DROP TABLE IF EXISTS lti_resource_links;
+DROP TABLE IF EXISTS lti_users;
+DROP TABLE IF EXISTS lti_launch_states;
+DROP TABLE IF EXISTS lti_platforms;
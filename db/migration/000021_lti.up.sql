@@ -0,0 +1,72 @@
+CREATE TABLE "lti_platforms" (
+  "platform_id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "issuer" varchar NOT NULL,
+  "client_id" varchar NOT NULL,
+  "deployment_id" varchar NOT NULL DEFAULT '',
+  "auth_login_url" varchar NOT NULL,
+  "auth_token_url" varchar NOT NULL,
+  "jwks_url" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("issuer", "client_id", "deployment_id")
+);
+
+CREATE TABLE "lti_launch_states" (
+  "state" varchar PRIMARY KEY,
+  "nonce" varchar NOT NULL,
+  "platform_id" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL
+);
+
+CREATE TABLE "lti_users" (
+  "platform_id" bigint NOT NULL,
+  "subject" varchar NOT NULL,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("platform_id", "subject")
+);
+
+CREATE TABLE "lti_resource_links" (
+  "link_id" bigserial PRIMARY KEY,
+  "platform_id" bigint NOT NULL,
+  "resource_link_id" varchar NOT NULL,
+  "course_id" bigint NOT NULL,
+  "material_id" bigint,
+  "lineitem_url" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("platform_id", "resource_link_id")
+);
+
+CREATE TABLE "lti_deep_links" (
+  "deep_link_id" varchar PRIMARY KEY,
+  "platform_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "deployment_id" varchar NOT NULL,
+  "return_url" varchar NOT NULL,
+  "data" varchar NOT NULL DEFAULT '',
+  "expires_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "lti_launch_states" ("expires_at");
+
+CREATE INDEX ON "lti_users" ("user_id");
+
+CREATE INDEX ON "lti_resource_links" ("course_id");
+
+ALTER TABLE "lti_launch_states" ADD FOREIGN KEY ("platform_id") REFERENCES "lti_platforms" ("platform_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_users" ADD FOREIGN KEY ("platform_id") REFERENCES "lti_platforms" ("platform_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_users" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_resource_links" ADD FOREIGN KEY ("platform_id") REFERENCES "lti_platforms" ("platform_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_resource_links" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_resource_links" ADD FOREIGN KEY ("material_id") REFERENCES "material" ("material_id") ON DELETE SET NULL;
+
+ALTER TABLE "lti_deep_links" ADD FOREIGN KEY ("platform_id") REFERENCES "lti_platforms" ("platform_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_deep_links" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
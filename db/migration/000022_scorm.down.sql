@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS scorm_attempts;
+DROP TABLE IF EXISTS scorm_packages;
+
+DELETE FROM material WHERE material_type = 'scorm';
+
+ALTER TABLE "material" DROP CONSTRAINT IF EXISTS "material_type_check";
+
+ALTER TABLE "material" ADD CONSTRAINT "material_type_check"
+  CHECK ("material_type" IN ('file', 'markdown', 'link', 'video'));
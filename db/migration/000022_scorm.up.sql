@@ -0,0 +1,37 @@
+ALTER TABLE "material" DROP CONSTRAINT IF EXISTS "material_type_check";
+
+ALTER TABLE "material" ADD CONSTRAINT "material_type_check"
+  CHECK ("material_type" IN ('file', 'markdown', 'link', 'video', 'scorm'));
+
+CREATE TABLE "scorm_packages" (
+  "material_id" bigint PRIMARY KEY,
+  "version" varchar NOT NULL,
+  "title" varchar NOT NULL DEFAULT '',
+  "package_dir" varchar NOT NULL,
+  "launch_path" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("version" IN ('1.2', '2004'))
+);
+
+CREATE TABLE "scorm_attempts" (
+  "attempt_id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "material_id" bigint NOT NULL,
+  "cmi" jsonb NOT NULL DEFAULT '{}',
+  "lesson_status" varchar NOT NULL DEFAULT 'not attempted',
+  "score" double precision,
+  "total_seconds" double precision NOT NULL DEFAULT 0,
+  "completed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("user_id", "material_id")
+);
+
+CREATE INDEX ON "scorm_attempts" ("material_id");
+
+ALTER TABLE "scorm_packages" ADD FOREIGN KEY ("material_id") REFERENCES "material" ("material_id") ON DELETE CASCADE;
+
+ALTER TABLE "scorm_attempts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "scorm_attempts" ADD FOREIGN KEY ("material_id") REFERENCES "material" ("material_id") ON DELETE CASCADE;
@@ -0,0 +1,35 @@
+CREATE TABLE "notifications" (
+  "notification_id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "type" varchar NOT NULL,
+  "course_id" bigint,
+  "title" varchar NOT NULL,
+  "message" varchar NOT NULL DEFAULT '',
+  "dedup_key" varchar NOT NULL,
+  "in_app" boolean NOT NULL DEFAULT true,
+  "read_at" timestamptz,
+  "emailed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("user_id", "dedup_key"),
+  CHECK ("type" IN ('grade', 'enrollment', 'material', 'announcement', 'due_date'))
+);
+
+CREATE INDEX ON "notifications" ("user_id", "created_at") WHERE "in_app" = true;
+
+CREATE INDEX ON "notifications" ("user_id") WHERE "in_app" = true AND "read_at" IS NULL;
+
+CREATE TABLE "notification_preferences" (
+  "user_id" bigint NOT NULL,
+  "type" varchar NOT NULL,
+  "in_app" boolean NOT NULL DEFAULT true,
+  "email" boolean NOT NULL DEFAULT true,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_id", "type"),
+  CHECK ("type" IN ('grade', 'enrollment', 'material', 'announcement', 'due_date'))
+);
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("user_id") ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+-- the language transactional emails are sent in, a locale without templates falls back to en
+ALTER TABLE users ADD COLUMN locale varchar NOT NULL DEFAULT 'en';
@@ -0,0 +1,2 @@
+ALTER TABLE video_watches DROP COLUMN IF EXISTS last_heartbeat_at;
+ALTER TABLE video_watches DROP COLUMN IF EXISTS started_at;
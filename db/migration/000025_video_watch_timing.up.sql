@@ -0,0 +1,3 @@
+-- when a student started watching and sent the latest heartbeat, watch time is checked against the real time that passed
+ALTER TABLE video_watches ADD COLUMN started_at timestamptz;
+ALTER TABLE video_watches ADD COLUMN last_heartbeat_at timestamptz;
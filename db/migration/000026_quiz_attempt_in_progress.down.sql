@@ -0,0 +1 @@
+DROP INDEX IF EXISTS quiz_attempts_in_progress_key;
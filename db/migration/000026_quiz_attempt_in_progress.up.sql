@@ -0,0 +1,7 @@
+-- a student has at most one attempt in progress per quiz, older duplicates left by concurrent starts are expired
+UPDATE quiz_attempts SET status = 'expired', submitted_at = now()
+WHERE status = 'in_progress' AND attempt_id NOT IN (
+  SELECT max(attempt_id) FROM quiz_attempts WHERE status = 'in_progress' GROUP BY quiz_id, user_id
+);
+
+CREATE UNIQUE INDEX quiz_attempts_in_progress_key ON quiz_attempts (quiz_id, user_id) WHERE status = 'in_progress';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS submission_assignment_user_key;
@@ -0,0 +1,67 @@
+-- a student has one individual submission per assignment, duplicates left by concurrent first uploads
+-- are merged into the oldest one: their attempts move over and are renumbered by submission time
+CREATE TEMPORARY TABLE submission_duplicates AS
+SELECT s.submission_id, k.submission_id AS kept_id
+FROM submission s
+JOIN (
+  SELECT assignment_id, user_id, min(submission_id) AS submission_id
+  FROM submission WHERE group_id IS NULL
+  GROUP BY assignment_id, user_id HAVING count(*) > 1
+) k ON k.assignment_id = s.assignment_id AND k.user_id = s.user_id
+WHERE s.group_id IS NULL AND s.submission_id <> k.submission_id;
+
+UPDATE submission_attempts a SET submission_id = d.kept_id, attempt_number = -a.attempt_id
+FROM submission_duplicates d WHERE a.submission_id = d.submission_id;
+
+-- the kept submission's own attempts step aside too before everything is numbered again
+UPDATE submission_attempts SET attempt_number = -attempt_id
+WHERE submission_id IN (SELECT kept_id FROM submission_duplicates) AND attempt_number > 0;
+
+UPDATE submission_attempts a SET attempt_number = n.attempt_number
+FROM (
+  SELECT attempt_id, row_number() OVER (PARTITION BY submission_id ORDER BY submitted_at, attempt_id) AS attempt_number
+  FROM submission_attempts
+  WHERE submission_id IN (SELECT kept_id FROM submission_duplicates)
+) n
+WHERE a.attempt_id = n.attempt_id;
+
+-- unless an attempt is pinned, the kept submission follows its latest attempt
+UPDATE submission s SET
+  resource = a.resource,
+  date_of_submission = a.submitted_at,
+  is_late = a.is_late,
+  penalty_percent = a.penalty_percent,
+  updated_at = now()
+FROM (
+  SELECT DISTINCT ON (submission_id) * FROM submission_attempts
+  WHERE submission_id IN (SELECT kept_id FROM submission_duplicates)
+  ORDER BY submission_id, attempt_number DESC
+) a
+WHERE s.submission_id = a.submission_id AND s.graded_attempt_id IS NULL;
+
+-- peer reviews and grade adjustments move over unless the kept submission already has one for that person
+UPDATE peer_reviews r SET submission_id = d.kept_id
+FROM submission_duplicates d
+WHERE r.submission_id = d.submission_id
+  AND r.review_id = (
+    SELECT min(o.review_id) FROM peer_reviews o
+    JOIN submission_duplicates od ON od.submission_id = o.submission_id
+    WHERE od.kept_id = d.kept_id AND o.reviewer_id = r.reviewer_id
+  )
+  AND NOT EXISTS (SELECT 1 FROM peer_reviews k WHERE k.submission_id = d.kept_id AND k.reviewer_id = r.reviewer_id);
+
+UPDATE submission_adjustments j SET submission_id = d.kept_id
+FROM submission_duplicates d
+WHERE j.submission_id = d.submission_id
+  AND j.adjustment_id = (
+    SELECT min(o.adjustment_id) FROM submission_adjustments o
+    JOIN submission_duplicates od ON od.submission_id = o.submission_id
+    WHERE od.kept_id = d.kept_id AND o.user_id = j.user_id
+  )
+  AND NOT EXISTS (SELECT 1 FROM submission_adjustments k WHERE k.submission_id = d.kept_id AND k.user_id = j.user_id);
+
+DELETE FROM submission WHERE submission_id IN (SELECT submission_id FROM submission_duplicates);
+
+DROP TABLE submission_duplicates;
+
+CREATE UNIQUE INDEX submission_assignment_user_key ON submission (assignment_id, user_id) WHERE group_id IS NULL;
@@ -0,0 +1,11 @@
+UPDATE "coupons" c SET "uses" = GREATEST(c."uses" - p."pending", 0)
+FROM (
+  SELECT "coupon_id", count(*) AS "pending" FROM "orders"
+  WHERE "status" = 'pending' AND "coupon_id" IS NOT NULL
+  GROUP BY "coupon_id"
+) p
+WHERE c."coupon_id" = p."coupon_id";
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "renewed_until";
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "renewed_from";
@@ -0,0 +1,15 @@
+-- a renewal order remembers the access it added, so refunding it takes back only that extension
+ALTER TABLE "orders" ADD COLUMN "renewed_from" timestamptz;
+
+ALTER TABLE "orders" ADD COLUMN "renewed_until" timestamptz;
+
+ALTER TABLE "orders" ADD CHECK ("renewed_from" < "renewed_until");
+
+-- a pending order holds a use of its coupon, existing pending orders take theirs now
+UPDATE "coupons" c SET "uses" = c."uses" + p."pending"
+FROM (
+  SELECT "coupon_id", count(*) AS "pending" FROM "orders"
+  WHERE "status" = 'pending' AND "coupon_id" IS NOT NULL
+  GROUP BY "coupon_id"
+) p
+WHERE c."coupon_id" = p."coupon_id";
@@ -0,0 +1 @@
+ALTER TABLE "material" DROP COLUMN IF EXISTS "duration_seconds";
@@ -0,0 +1,5 @@
+-- the length of a video lesson is set by the instructor, watch time is measured against it instead of what players report.
+-- Existing videos start without one and do not earn watch time until it is set.
+ALTER TABLE "material" ADD COLUMN "duration_seconds" double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE "material" ADD CHECK ("duration_seconds" >= 0);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "lti_platform_courses";
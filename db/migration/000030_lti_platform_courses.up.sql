@@ -0,0 +1,15 @@
+-- the courses a platform may link to. A teacher of the course grants it by answering a deep linking request from the
+-- platform, or an admin grants it. Launches naming any other course are refused, so resource links created before
+-- are only kept for the courses they are granted again.
+CREATE TABLE "lti_platform_courses" (
+  "platform_id" bigint NOT NULL,
+  "course_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("platform_id", "course_id")
+);
+
+CREATE INDEX ON "lti_platform_courses" ("course_id");
+
+ALTER TABLE "lti_platform_courses" ADD FOREIGN KEY ("platform_id") REFERENCES "lti_platforms" ("platform_id") ON DELETE CASCADE;
+
+ALTER TABLE "lti_platform_courses" ADD FOREIGN KEY ("course_id") REFERENCES "courses" ("course_id") ON DELETE CASCADE;
@@ -3,21 +3,62 @@ INSERT INTO assignment (
     title,
     course_id,
     due_date,
-    assignment_file
+    assignment_file,
+    late_policy,
+    late_penalty_percent,
+    late_cutoff,
+    max_attempts,
+    group_submission
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )  RETURNING *;
 
 -- name: GetAssignment :one
 SELECT * FROM assignment
 WHERE assignment_id = $1;
 
+-- name: ListAssignmentsByCourse :many
+SELECT * FROM assignment
+WHERE course_id = $1
+ORDER BY due_date NULLS LAST, assignment_id;
+
 -- name: UpdateAssignment :one
 UPDATE assignment
-SET  title = $2,due_date = $3, assignment_file = $4, course_id = $5
+SET  title = $2,due_date = $3, assignment_file = $4, course_id = $5,
+    late_policy = $6, late_penalty_percent = $7, late_cutoff = $8, max_attempts = $9, group_submission = $10
+WHERE assignment_id = $1
+RETURNING *;
+
+-- name: SetAssignmentGradesPublished :one
+UPDATE assignment
+SET grades_published_at = $2
 WHERE assignment_id = $1
 RETURNING *;
 
 -- name: DeleteAssignment :exec
 DELETE FROM assignment
-WHERE assignment_id = $1;
\ No newline at end of file
+WHERE assignment_id = $1;
+
+-- name: CreateAssignmentExtension :one
+INSERT INTO assignment_extensions (
+    assignment_id,
+    user_id,
+    due_date
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (assignment_id, user_id) DO UPDATE SET due_date = EXCLUDED.due_date
+RETURNING *;
+
+-- name: GetAssignmentExtension :one
+SELECT * FROM assignment_extensions
+WHERE assignment_id = $1 AND user_id = $2;
+
+-- name: ListAssignmentExtensions :many
+SELECT * FROM assignment_extensions
+WHERE assignment_id = $1
+ORDER BY user_id;
+
+-- name: DeleteAssignmentExtension :exec
+DELETE FROM assignment_extensions
+WHERE assignment_id = $1 AND user_id = $2;
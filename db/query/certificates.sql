@@ -0,0 +1,38 @@
+-- name: CreateCertificate :one
+INSERT INTO certificates (
+    code,
+    user_id,
+    course_id,
+    student_name,
+    course_title,
+    instructor_name,
+    file,
+    issued_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetCertificateByCode :one
+SELECT * FROM certificates
+WHERE code = $1
+LIMIT 1;
+
+-- name: GetCourseCertificate :one
+SELECT * FROM certificates
+WHERE
+    user_id = $1
+    AND course_id = $2
+LIMIT 1;
+
+-- name: ListCertificatesByUser :many
+SELECT * FROM certificates
+WHERE user_id = $1
+ORDER BY issued_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: SetCertificateEmailed :one
+UPDATE certificates
+SET emailed_at = now()
+WHERE certificate_id = $1
+RETURNING *;
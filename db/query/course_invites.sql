@@ -0,0 +1,31 @@
+-- name: CreateCourseInvite :one
+INSERT INTO course_invites (
+    course_id,
+    code,
+    max_uses,
+    expires_at,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListCourseInvites :many
+SELECT * FROM course_invites
+WHERE course_id = $1
+ORDER BY invite_id DESC;
+
+-- name: GetCourseInviteByCodeForUpdate :one
+SELECT * FROM course_invites
+WHERE code = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: UseCourseInvite :exec
+UPDATE course_invites
+SET uses = uses + 1
+WHERE invite_id = $1;
+
+-- name: RevokeCourseInvite :one
+UPDATE course_invites
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE invite_id = $1
+RETURNING *;
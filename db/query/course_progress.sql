@@ -5,7 +5,9 @@ INSERT INTO course_progress (
     progress
 ) VALUES (
     $1, $2, $3
-) RETURNING *;
+)
+ON CONFLICT (user_id, course_id) DO UPDATE SET progress = course_progress.progress
+RETURNING *;
 
 -- name: GetCourseProgress :one
 SELECT * FROM course_progress
@@ -53,3 +55,65 @@ WHERE
     progress = $1
     AND user_id = $2;
 
+-- name: UpsertCourseProgress :one
+INSERT INTO course_progress (
+    course_id,
+    user_id,
+    progress,
+    completed_at
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (user_id, course_id) DO UPDATE SET
+    progress = EXCLUDED.progress,
+    completed_at = COALESCE(course_progress.completed_at, EXCLUDED.completed_at),
+    updated_at = now()
+RETURNING *;
+
+-- name: GetCourseProgressCounts :one
+SELECT
+    (SELECT COUNT(*) FROM material m
+        WHERE m.course_id = sqlc.arg(course_id))::bigint AS total_materials,
+    (SELECT COUNT(*) FROM lesson_completion lc
+        JOIN material m ON m.material_id = lc.material_id
+        WHERE m.course_id = sqlc.arg(course_id) AND lc.user_id = sqlc.arg(user_id) AND lc.completed = true)::bigint AS completed_materials,
+    (SELECT COUNT(*) FROM assignment a
+        WHERE a.course_id = sqlc.arg(course_id))::bigint AS total_assignments,
+    (SELECT COUNT(DISTINCT s.assignment_id) FROM submission s
+        JOIN assignment a ON a.assignment_id = s.assignment_id
+        WHERE a.course_id = sqlc.arg(course_id) AND s.submitted = true
+            AND (s.user_id = sqlc.arg(user_id) OR s.group_id IN (SELECT group_id FROM course_group_members WHERE user_id = sqlc.arg(user_id))))::bigint AS submitted_assignments,
+    (SELECT COUNT(*) FROM quizzes qz
+        WHERE qz.course_id = sqlc.arg(course_id))::bigint AS total_quizzes,
+    (SELECT COUNT(DISTINCT qa.quiz_id) FROM quiz_attempts qa
+        JOIN quizzes qz ON qz.quiz_id = qa.quiz_id
+        WHERE qz.course_id = sqlc.arg(course_id) AND qa.user_id = sqlc.arg(user_id) AND qa.submitted_at IS NOT NULL)::bigint AS submitted_quizzes;
+
+-- name: ListCourseLearners :many
+SELECT user_id FROM subscriptions
+WHERE course_id = $1 AND active = true
+UNION
+SELECT user_id FROM course_progress
+WHERE course_id = $1
+ORDER BY user_id;
+
+-- name: GetCourseProgressWeights :one
+SELECT * FROM course_progress_weights
+WHERE course_id = $1
+LIMIT 1;
+
+-- name: UpsertCourseProgressWeights :one
+INSERT INTO course_progress_weights (
+    course_id,
+    material_weight,
+    assignment_weight,
+    quiz_weight
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (course_id) DO UPDATE SET
+    material_weight = EXCLUDED.material_weight,
+    assignment_weight = EXCLUDED.assignment_weight,
+    quiz_weight = EXCLUDED.quiz_weight,
+    updated_at = now()
+RETURNING *;
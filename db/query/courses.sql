@@ -6,10 +6,10 @@ INSERT INTO courses (
     image,
     catagory,
     what_will,
-    sequential_access
-    
+    sequential_access,
+    image_variants
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetCourses :one
@@ -29,7 +29,8 @@ SET
        description = COALESCE(sqlc.narg(description), description),
        catagory = COALESCE(sqlc.narg(catagory), catagory),
         sequential_access = COALESCE(sqlc.narg(sequential_access), sequential_access),
-         what_will = COALESCE(sqlc.narg(what_will), what_will)
+         what_will = COALESCE(sqlc.narg(what_will), what_will),
+       image_variants = COALESCE(sqlc.narg(image_variants), image_variants)
 
 WHERE  course_id = sqlc.arg(course_id)
 RETURNING *;
@@ -0,0 +1,44 @@
+-- name: CreateCredential :one
+INSERT INTO credentials (
+    credential_uri,
+    user_id,
+    course_id,
+    document,
+    issued_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCredentialByURI :one
+SELECT * FROM credentials
+WHERE credential_uri = $1
+LIMIT 1;
+
+-- name: GetCourseCredential :one
+SELECT * FROM credentials
+WHERE
+    user_id = $1
+    AND course_id = $2
+LIMIT 1;
+
+-- name: ListCredentialsByUser :many
+SELECT * FROM credentials
+WHERE user_id = $1
+ORDER BY issued_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: RevokeCredential :one
+UPDATE credentials
+SET
+    revoked_at = now(),
+    revocation_reason = $2
+WHERE
+    credential_uri = $1
+    AND revoked_at IS NULL
+RETURNING *;
+
+-- name: ListRevokedCredentials :many
+SELECT credential_uri, revocation_reason, revoked_at FROM credentials
+WHERE revoked_at IS NOT NULL
+ORDER BY revoked_at;
@@ -0,0 +1,142 @@
+-- name: UpsertCourseEnrollmentSettings :one
+INSERT INTO course_enrollment_settings (
+    course_id,
+    mode,
+    capacity,
+    enrollment_starts_at,
+    enrollment_ends_at,
+    access_days,
+    access_starts_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (course_id) DO UPDATE
+SET
+    mode = EXCLUDED.mode,
+    capacity = EXCLUDED.capacity,
+    enrollment_starts_at = EXCLUDED.enrollment_starts_at,
+    enrollment_ends_at = EXCLUDED.enrollment_ends_at,
+    access_days = EXCLUDED.access_days,
+    access_starts_at = EXCLUDED.access_starts_at,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetCourseEnrollmentSettings :one
+SELECT * FROM course_enrollment_settings
+WHERE course_id = $1 LIMIT 1;
+
+-- name: GetCourseEnrollmentSettingsForUpdate :one
+SELECT * FROM course_enrollment_settings
+WHERE course_id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: GetCourseSubscription :one
+SELECT * FROM subscriptions
+WHERE user_id = $1 AND course_id = $2 LIMIT 1;
+
+-- name: GetCourseSubscriptionForUpdate :one
+SELECT * FROM subscriptions
+WHERE user_id = $1 AND course_id = $2 LIMIT 1
+FOR UPDATE;
+
+-- name: CountActiveSubscriptions :one
+SELECT COUNT(*) FROM subscriptions
+WHERE course_id = $1 AND active = true;
+
+-- name: CountWaitlistedSubscriptions :one
+SELECT COUNT(*) FROM subscriptions
+WHERE course_id = $1 AND waitlisted_at IS NOT NULL;
+
+-- name: GetNextWaitlistedSubscription :one
+SELECT * FROM subscriptions
+WHERE course_id = $1 AND waitlisted_at IS NOT NULL
+ORDER BY waitlisted_at, subscription_id
+LIMIT 1
+FOR UPDATE;
+
+-- name: ActivateSubscription :one
+UPDATE subscriptions
+SET
+    active = true,
+    pending = false,
+    waitlisted_at = NULL,
+    denied_at = NULL,
+    starts_at = GREATEST(now(), (
+        SELECT e.access_starts_at FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id
+    )),
+    expires_at = (
+        SELECT GREATEST(now(), e.access_starts_at) + make_interval(days => e.access_days::int)
+        FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id AND e.access_days > 0
+    ),
+    expiry_reminded_at = NULL,
+    expired_at = NULL,
+    updated_at = now()
+WHERE subscription_id = $1
+RETURNING *;
+
+-- name: WaitlistSubscription :one
+UPDATE subscriptions
+SET
+    active = false,
+    pending = false,
+    waitlisted_at = COALESCE(waitlisted_at, now()),
+    denied_at = NULL,
+    updated_at = now()
+WHERE subscription_id = $1
+RETURNING *;
+
+-- name: DenySubscription :one
+UPDATE subscriptions
+SET
+    active = false,
+    pending = false,
+    waitlisted_at = NULL,
+    denied_at = now(),
+    updated_at = now()
+WHERE subscription_id = $1
+RETURNING *;
+
+-- name: DeleteCourseSubscription :one
+DELETE FROM subscriptions
+WHERE user_id = $1 AND course_id = $2
+RETURNING *;
+
+-- name: ListPendingSubscriptions :many
+SELECT
+    s.subscription_id,
+    s.user_id,
+    u.user_name,
+    u.first_name,
+    u.last_name,
+    u.email,
+    s.created_at
+FROM subscriptions s
+JOIN users u ON u.user_id = s.user_id
+WHERE s.course_id = $1 AND s.pending = true
+ORDER BY s.created_at, s.subscription_id;
+
+-- name: ListWaitlistedSubscriptions :many
+SELECT
+    s.subscription_id,
+    s.user_id,
+    u.user_name,
+    u.first_name,
+    u.last_name,
+    u.email,
+    s.waitlisted_at
+FROM subscriptions s
+JOIN users u ON u.user_id = s.user_id
+WHERE s.course_id = $1 AND s.waitlisted_at IS NOT NULL
+ORDER BY s.waitlisted_at, s.subscription_id;
+
+-- name: DeactivateSubscription :one
+UPDATE subscriptions
+SET
+    active = false,
+    pending = false,
+    waitlisted_at = NULL,
+    updated_at = now()
+WHERE subscription_id = $1
+RETURNING *;
@@ -0,0 +1,53 @@
+-- name: CreateEnrollmentImport :one
+INSERT INTO enrollment_imports (
+    course_id,
+    created_by,
+    total_rows,
+    rows,
+    results
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetEnrollmentImport :one
+SELECT * FROM enrollment_imports
+WHERE import_id = $1 LIMIT 1;
+
+-- name: ListEnrollmentImports :many
+SELECT
+    import_id,
+    course_id,
+    created_by,
+    status,
+    total_rows,
+    processed_rows,
+    error,
+    created_at,
+    started_at,
+    finished_at
+FROM enrollment_imports
+WHERE course_id = $1
+ORDER BY import_id DESC;
+
+-- name: StartEnrollmentImport :one
+UPDATE enrollment_imports
+SET
+    status = 'running',
+    started_at = COALESCE(started_at, now())
+WHERE import_id = $1 AND status IN ('queued', 'running')
+RETURNING *;
+
+-- name: AddEnrollmentImportResult :exec
+UPDATE enrollment_imports
+SET
+    results = results || jsonb_build_array(sqlc.arg(result)::jsonb),
+    processed_rows = processed_rows + 1
+WHERE import_id = sqlc.arg(import_id);
+
+-- name: FinishEnrollmentImport :exec
+UPDATE enrollment_imports
+SET
+    status = $2,
+    error = $3,
+    finished_at = now()
+WHERE import_id = $1;
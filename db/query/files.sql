@@ -0,0 +1,28 @@
+-- name: ListReferencedFiles :many
+SELECT material_file AS file_url FROM material WHERE material_file <> ''
+UNION
+SELECT assignment_file FROM assignment WHERE assignment_file <> ''
+UNION
+SELECT resource FROM submission WHERE resource <> ''
+UNION
+SELECT resource FROM submission_attempts WHERE resource <> ''
+UNION
+SELECT image FROM courses WHERE image <> ''
+UNION
+SELECT picture FROM profile_pictures WHERE picture <> ''
+UNION
+SELECT file FROM certificates WHERE file <> '';
+
+-- name: ListReferencedImageVariants :many
+SELECT variant_file.value AS file_url FROM courses,
+    jsonb_each(image_variants) AS variant(size, files),
+    jsonb_each_text(variant.files) AS variant_file(format, value)
+WHERE variant_file.format IN ('jpeg', 'webp') AND variant_file.value <> ''
+UNION
+SELECT variant_file.value FROM profile_pictures,
+    jsonb_each(picture_variants) AS variant(size, files),
+    jsonb_each_text(variant.files) AS variant_file(format, value)
+WHERE variant_file.format IN ('jpeg', 'webp') AND variant_file.value <> '';
+
+-- name: ListReferencedDirs :many
+SELECT package_dir AS dir FROM scorm_packages WHERE package_dir <> '';
@@ -0,0 +1,117 @@
+-- name: CreateGradeCategory :one
+INSERT INTO grade_categories (
+    course_id,
+    title,
+    weight,
+    drop_lowest
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetGradeCategory :one
+SELECT * FROM grade_categories
+WHERE category_id = $1;
+
+-- name: ListGradeCategories :many
+SELECT * FROM grade_categories
+WHERE course_id = $1
+ORDER BY category_id;
+
+-- name: UpdateGradeCategory :one
+UPDATE grade_categories
+SET title = $2, weight = $3, drop_lowest = $4, updated_at = now()
+WHERE category_id = $1
+RETURNING *;
+
+-- name: DeleteGradeCategory :exec
+DELETE FROM grade_categories
+WHERE category_id = $1;
+
+-- name: CreateGradeItem :one
+INSERT INTO grade_items (
+    course_id,
+    category_id,
+    title,
+    item_type,
+    assignment_id,
+    quiz_id,
+    max_points
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetGradeItem :one
+SELECT * FROM grade_items
+WHERE item_id = $1;
+
+-- name: ListGradeItems :many
+SELECT * FROM grade_items
+WHERE course_id = $1
+ORDER BY category_id, item_id;
+
+-- name: UpdateGradeItem :one
+UPDATE grade_items
+SET category_id = $2, title = $3, max_points = $4, updated_at = now()
+WHERE item_id = $1
+RETURNING *;
+
+-- name: DeleteGradeItem :exec
+DELETE FROM grade_items
+WHERE item_id = $1;
+
+-- name: UpsertGradeItemScore :one
+INSERT INTO grade_item_scores (
+    item_id,
+    user_id,
+    points,
+    comment
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (item_id, user_id) DO UPDATE
+SET points = EXCLUDED.points, comment = EXCLUDED.comment, updated_at = now()
+RETURNING *;
+
+-- name: ListGradebookScores :many
+SELECT s.item_id, s.user_id, s.points, true AS published
+FROM grade_item_scores s
+JOIN grade_items gi ON gi.item_id = s.item_id
+WHERE gi.course_id = $1
+UNION ALL
+SELECT gi.item_id, COALESCE(m.user_id, sub.user_id) AS user_id,
+    GREATEST(sub.grade::double precision + COALESCE(adj.points, 0), 0)::double precision AS points,
+    a.grades_published_at IS NOT NULL AS published
+FROM grade_items gi
+JOIN assignment a ON a.assignment_id = gi.assignment_id
+JOIN submission sub ON sub.assignment_id = gi.assignment_id
+LEFT JOIN course_group_members m ON m.group_id = sub.group_id
+LEFT JOIN submission_adjustments adj ON adj.submission_id = sub.submission_id AND adj.user_id = COALESCE(m.user_id, sub.user_id)
+WHERE gi.course_id = $1 AND sub.graded_at IS NOT NULL AND sub.grade ~ '^[0-9]+(\.[0-9]+)?$'
+UNION ALL
+SELECT gi.item_id, qa.user_id, MAX(qa.score * gi.max_points / NULLIF(qa.max_score, 0))::double precision AS points, true AS published
+FROM grade_items gi
+JOIN quiz_attempts qa ON qa.quiz_id = gi.quiz_id
+WHERE gi.course_id = $1 AND qa.status <> 'in_progress' AND qa.max_score > 0
+GROUP BY gi.item_id, qa.user_id;
+
+-- name: ListGradebookStudents :many
+SELECT DISTINCT u.user_id, u.user_name, u.first_name, u.last_name, u.email
+FROM subscriptions s
+JOIN users u ON u.user_id = s.user_id
+WHERE s.course_id = $1 AND s.active = true
+ORDER BY u.user_id;
+
+-- name: GetGradeScale :one
+SELECT * FROM grade_scales
+WHERE course_id = $1;
+
+-- name: UpsertGradeScale :one
+INSERT INTO grade_scales (
+    course_id,
+    letters
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (course_id) DO UPDATE
+SET letters = EXCLUDED.letters, updated_at = now()
+RETURNING *;
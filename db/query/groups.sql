@@ -0,0 +1,83 @@
+-- name: CreateCourseGroup :one
+INSERT INTO course_groups (
+    course_id,
+    name
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetCourseGroup :one
+SELECT * FROM course_groups
+WHERE group_id = $1;
+
+-- name: ListCourseGroups :many
+SELECT * FROM course_groups
+WHERE course_id = $1
+ORDER BY group_id;
+
+-- name: UpdateCourseGroup :one
+UPDATE course_groups
+SET name = $2
+WHERE group_id = $1
+RETURNING *;
+
+-- name: DeleteCourseGroup :exec
+DELETE FROM course_groups
+WHERE group_id = $1;
+
+-- name: AddCourseGroupMember :one
+INSERT INTO course_group_members (
+    group_id,
+    course_id,
+    user_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: RemoveCourseGroupMember :exec
+DELETE FROM course_group_members
+WHERE group_id = $1 AND user_id = $2;
+
+-- name: GetCourseGroupByMember :one
+SELECT course_groups.* FROM course_groups
+JOIN course_group_members ON course_group_members.group_id = course_groups.group_id
+WHERE course_group_members.course_id = $1 AND course_group_members.user_id = $2;
+
+-- name: ListCourseGroupMembers :many
+SELECT m.group_id, u.user_id, u.user_name, u.first_name, u.last_name
+FROM course_group_members m
+JOIN users u ON u.user_id = m.user_id
+WHERE m.course_id = $1
+ORDER BY m.group_id, u.user_id;
+
+-- name: ListUngroupedStudents :many
+SELECT DISTINCT s.user_id
+FROM subscriptions s
+WHERE s.course_id = $1 AND s.active = true
+    AND NOT EXISTS (
+        SELECT 1 FROM course_group_members m
+        WHERE m.course_id = s.course_id AND m.user_id = s.user_id
+    )
+ORDER BY s.user_id;
+
+-- name: UpsertSubmissionAdjustment :one
+INSERT INTO submission_adjustments (
+    submission_id,
+    user_id,
+    points,
+    reason
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (submission_id, user_id) DO UPDATE
+SET points = EXCLUDED.points, reason = EXCLUDED.reason, updated_at = now()
+RETURNING *;
+
+-- name: ListSubmissionAdjustments :many
+SELECT * FROM submission_adjustments
+WHERE submission_id = $1
+ORDER BY user_id;
+
+-- name: DeleteSubmissionAdjustment :exec
+DELETE FROM submission_adjustments
+WHERE submission_id = $1 AND user_id = $2;
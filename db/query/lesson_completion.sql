@@ -6,7 +6,9 @@ INSERT INTO lesson_completion (
     completed
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING *;
+)
+ON CONFLICT (user_id, material_id) DO UPDATE SET completed = EXCLUDED.completed
+RETURNING *;
 
 -- name: UpdateLessonCompletion :one
 UPDATE lesson_completion
@@ -0,0 +1,150 @@
+-- name: CreateLTIPlatform :one
+INSERT INTO lti_platforms (
+    name,
+    issuer,
+    client_id,
+    deployment_id,
+    auth_login_url,
+    auth_token_url,
+    jwks_url
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetLTIPlatform :one
+SELECT * FROM lti_platforms
+WHERE platform_id = $1
+LIMIT 1;
+
+-- name: ListLTIPlatforms :many
+SELECT * FROM lti_platforms
+ORDER BY platform_id;
+
+-- name: ListLTIPlatformsByIssuer :many
+SELECT * FROM lti_platforms
+WHERE issuer = $1
+ORDER BY platform_id;
+
+-- name: UpdateLTIPlatform :one
+UPDATE lti_platforms
+SET
+    name = $2,
+    issuer = $3,
+    client_id = $4,
+    deployment_id = $5,
+    auth_login_url = $6,
+    auth_token_url = $7,
+    jwks_url = $8,
+    updated_at = now()
+WHERE platform_id = $1
+RETURNING *;
+
+-- name: DeleteLTIPlatform :exec
+DELETE FROM lti_platforms
+WHERE platform_id = $1;
+
+-- name: CreateLTILaunchState :exec
+INSERT INTO lti_launch_states (
+    state,
+    nonce,
+    platform_id,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ConsumeLTILaunchState :one
+DELETE FROM lti_launch_states
+WHERE state = $1 AND expires_at > now()
+RETURNING *;
+
+-- name: DeleteExpiredLTILaunchStates :exec
+DELETE FROM lti_launch_states
+WHERE expires_at <= now();
+
+-- name: GetLTIUser :one
+SELECT * FROM lti_users
+WHERE platform_id = $1 AND subject = $2
+LIMIT 1;
+
+-- name: CreateLTIUser :one
+INSERT INTO lti_users (
+    platform_id,
+    subject,
+    user_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: UpsertLTIResourceLink :one
+INSERT INTO lti_resource_links (
+    platform_id,
+    resource_link_id,
+    course_id,
+    material_id,
+    lineitem_url
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (platform_id, resource_link_id) DO UPDATE
+SET
+    course_id = EXCLUDED.course_id,
+    material_id = EXCLUDED.material_id,
+    lineitem_url = CASE WHEN EXCLUDED.lineitem_url = '' THEN lti_resource_links.lineitem_url ELSE EXCLUDED.lineitem_url END,
+    updated_at = now()
+RETURNING *;
+
+-- name: CreateLTIDeepLink :one
+INSERT INTO lti_deep_links (
+    deep_link_id,
+    platform_id,
+    user_id,
+    deployment_id,
+    return_url,
+    data,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ConsumeLTIDeepLink :one
+DELETE FROM lti_deep_links
+WHERE deep_link_id = $1 AND user_id = $2 AND expires_at > now()
+RETURNING *;
+
+-- name: ListLTIScoreTargets :many
+SELECT DISTINCT
+    l.lineitem_url,
+    u.subject,
+    p.platform_id,
+    p.issuer,
+    p.client_id,
+    p.deployment_id,
+    p.auth_login_url,
+    p.auth_token_url,
+    p.jwks_url
+FROM lti_resource_links l
+JOIN lti_users u ON u.platform_id = l.platform_id
+JOIN lti_platforms p ON p.platform_id = l.platform_id
+WHERE l.course_id = $1 AND u.user_id = $2 AND l.lineitem_url <> '';
+
+-- name: CreateLTIPlatformCourse :exec
+INSERT INTO lti_platform_courses (
+    platform_id,
+    course_id
+) VALUES (
+    $1, $2
+) ON CONFLICT (platform_id, course_id) DO NOTHING;
+
+-- name: HasLTIPlatformCourse :one
+SELECT EXISTS (
+    SELECT 1 FROM lti_platform_courses
+    WHERE platform_id = $1 AND course_id = $2
+);
+
+-- name: DeleteLTIPlatformCourse :exec
+WITH links AS (
+    DELETE FROM lti_resource_links
+    WHERE platform_id = $1 AND course_id = $2
+)
+DELETE FROM lti_platform_courses
+WHERE platform_id = $1 AND course_id = $2;
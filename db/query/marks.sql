@@ -15,7 +15,7 @@ WHERE mark_id = $1;
 
 -- name: UpdateMark :one
 UPDATE marks
-SET marks = $2 , user_id = $3, course_id = $4
+SET marks = $2 , user_id = $3, course_id = $4, updated_at = now()
 WHERE mark_id = $1
 RETURNING *;
 
@@ -29,3 +29,24 @@ OFFSET $3;
 -- name: DeleteMark :exec
 DELETE FROM marks
 WHERE mark_id = $1;
+
+-- name: GetMarkByCourseAndUser :one
+SELECT * FROM marks
+WHERE course_id = $1 AND user_id = $2
+ORDER BY mark_id
+LIMIT 1;
+
+-- name: GetCourseAssessmentMark :one
+SELECT COALESCE(ROUND(AVG(best.percentage)), 0)::bigint AS marks
+FROM (
+    SELECT MAX(a.score * 100.0 / NULLIF(a.max_score, 0))::double precision AS percentage
+    FROM quiz_attempts a
+    JOIN quizzes q ON q.quiz_id = a.quiz_id
+    WHERE q.course_id = $1 AND a.user_id = $2 AND a.status <> 'in_progress'
+    GROUP BY a.quiz_id
+    UNION ALL
+    SELECT s.score AS percentage
+    FROM scorm_attempts s
+    JOIN material m ON m.material_id = s.material_id
+    WHERE m.course_id = $1 AND s.user_id = $2 AND s.score IS NOT NULL
+) AS best;
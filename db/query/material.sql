@@ -4,9 +4,14 @@ INSERT INTO material (
     course_id,
     title,
     material_file,
-    order_number
+    order_number,
+    material_type,
+    content,
+    content_html,
+    external_url,
+    duration_seconds
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 
@@ -14,7 +19,11 @@ INSERT INTO material (
 UPDATE material
 SET
     title = COALESCE(sqlc.narg(title),title),
-    material_file = COALESCE(sqlc.narg(material_file),material_file)
+    material_file = COALESCE(sqlc.narg(material_file),material_file),
+    content = COALESCE(sqlc.narg(content),content),
+    content_html = COALESCE(sqlc.narg(content_html),content_html),
+    external_url = COALESCE(sqlc.narg(external_url),external_url),
+    duration_seconds = COALESCE(sqlc.narg(duration_seconds),duration_seconds)
 WHERE
     material_id = sqlc.arg(material_id)
 RETURNING *;
@@ -24,6 +33,11 @@ RETURNING *;
 DELETE FROM material
 WHERE material_id = $1;
 
+-- name: GetMaterialByID :one
+SELECT * FROM material
+WHERE material_id = $1
+LIMIT 1;
+
 -- name: GetTotalMaterialsInCourse :one
 SELECT COUNT(*)
 FROM material
@@ -47,7 +61,9 @@ SELECT
     course_id,
     title,
     material_file,
-    order_number
+    order_number,
+    material_type,
+    external_url
 FROM material
 WHERE course_id = $1
 ORDER BY material_id
@@ -0,0 +1,93 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (
+    user_id,
+    type,
+    course_id,
+    title,
+    message,
+    dedup_key,
+    in_app
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) ON CONFLICT (user_id, dedup_key) DO UPDATE SET dedup_key = EXCLUDED.dedup_key
+RETURNING *;
+
+-- name: SetNotificationEmailed :exec
+UPDATE notifications
+SET emailed_at = now()
+WHERE notification_id = $1;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id) AND in_app = true
+    AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL)
+ORDER BY created_at DESC, notification_id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND in_app = true AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE notification_id = $1 AND user_id = $2 AND in_app = true
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = $1 AND in_app = true AND read_at IS NULL;
+
+-- name: GetNotificationPreference :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1 AND type = $2
+LIMIT 1;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE user_id = $1
+ORDER BY type;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO notification_preferences (
+    user_id,
+    type,
+    in_app,
+    email
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (user_id, type) DO UPDATE SET
+    in_app = EXCLUDED.in_app,
+    email = EXCLUDED.email,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListCourseStudentIDs :many
+SELECT user_id FROM subscriptions
+WHERE course_id = $1 AND active = true
+ORDER BY user_id;
+
+-- name: ListGradedSubmissionUserIDs :many
+SELECT DISTINCT user_id FROM submission
+WHERE assignment_id = $1 AND graded_at IS NOT NULL
+ORDER BY user_id;
+
+-- name: ListDueAssignmentReminders :many
+SELECT
+    a.assignment_id,
+    a.course_id,
+    a.title,
+    s.user_id,
+    COALESCE(e.due_date, a.due_date)::timestamptz AS due_date
+FROM assignment a
+JOIN subscriptions s ON s.course_id = a.course_id AND s.active = true
+LEFT JOIN assignment_extensions e ON e.assignment_id = a.assignment_id AND e.user_id = s.user_id
+WHERE COALESCE(e.due_date, a.due_date) > now()
+    AND COALESCE(e.due_date, a.due_date) <= sqlc.arg(due_before)
+    AND NOT EXISTS (
+        SELECT 1 FROM submission sub
+        WHERE sub.assignment_id = a.assignment_id AND sub.user_id = s.user_id
+    )
+ORDER BY a.assignment_id, s.user_id;
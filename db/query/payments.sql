@@ -0,0 +1,143 @@
+-- name: UpsertCoursePrice :one
+INSERT INTO course_prices (
+    course_id,
+    currency,
+    amount
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (course_id) DO UPDATE
+SET
+    currency = EXCLUDED.currency,
+    amount = EXCLUDED.amount,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetCoursePrice :one
+SELECT * FROM course_prices
+WHERE course_id = $1 LIMIT 1;
+
+-- name: DeleteCoursePrice :exec
+DELETE FROM course_prices
+WHERE course_id = $1;
+
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+    code,
+    course_id,
+    kind,
+    value,
+    currency,
+    max_uses,
+    expires_at,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetCouponByCode :one
+SELECT * FROM coupons
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCoupons :many
+SELECT * FROM coupons
+ORDER BY coupon_id DESC;
+
+-- name: DeleteCoupon :exec
+DELETE FROM coupons
+WHERE coupon_id = $1;
+
+-- name: UseCoupon :one
+UPDATE coupons
+SET uses = uses + 1
+WHERE coupon_id = $1 AND (max_uses = 0 OR uses < max_uses)
+RETURNING *;
+
+-- name: CreateOrder :one
+INSERT INTO orders (
+    user_id,
+    course_id,
+    coupon_id,
+    currency,
+    list_amount,
+    discount_amount,
+    amount,
+    provider
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: SetOrderCheckoutSession :one
+UPDATE orders
+SET
+    checkout_session_id = $2,
+    updated_at = now()
+WHERE order_id = $1
+RETURNING *;
+
+-- name: GetOrder :one
+SELECT * FROM orders
+WHERE order_id = $1 LIMIT 1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE order_id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: GetOrderByPaymentID :one
+SELECT * FROM orders
+WHERE payment_id = $1 AND payment_id <> '' LIMIT 1;
+
+-- name: MarkOrderPaid :one
+UPDATE orders
+SET
+    status = 'paid',
+    payment_id = $2,
+    paid_at = now(),
+    updated_at = now()
+WHERE order_id = $1
+RETURNING *;
+
+-- name: SetOrderRenewal :one
+UPDATE orders
+SET
+    renewed_from = $2,
+    renewed_until = $3,
+    updated_at = now()
+WHERE order_id = $1
+RETURNING *;
+
+-- name: MarkOrderRefunded :one
+UPDATE orders
+SET
+    status = 'refunded',
+    refunded_at = now(),
+    updated_at = now()
+WHERE order_id = $1
+RETURNING *;
+
+-- name: CloseOrder :one
+WITH closed AS (
+    UPDATE orders
+    SET
+        status = $2,
+        updated_at = now()
+    WHERE order_id = $1 AND status = 'pending'
+    RETURNING *
+), released AS (
+    UPDATE coupons
+    SET uses = uses - 1
+    FROM closed
+    WHERE coupons.coupon_id = closed.coupon_id AND coupons.uses > 0
+)
+SELECT * FROM closed;
+
+-- name: ListOrdersByUser :many
+SELECT * FROM orders
+WHERE user_id = $1
+ORDER BY order_id DESC;
+
+-- name: ListOrdersByCourse :many
+SELECT * FROM orders
+WHERE course_id = $1
+ORDER BY order_id DESC;
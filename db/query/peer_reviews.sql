@@ -0,0 +1,122 @@
+-- name: UpsertPeerReviewSettings :one
+INSERT INTO peer_review_settings (
+    assignment_id,
+    reviews_per_submission,
+    review_due_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (assignment_id) DO UPDATE
+SET
+    reviews_per_submission = EXCLUDED.reviews_per_submission,
+    review_due_at = EXCLUDED.review_due_at,
+    reminded_at = NULL,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetPeerReviewSettings :one
+SELECT * FROM peer_review_settings
+WHERE assignment_id = $1;
+
+-- name: GetPeerReviewSettingsForUpdate :one
+SELECT * FROM peer_review_settings
+WHERE assignment_id = $1
+FOR UPDATE;
+
+-- name: DeletePeerReviewSettings :exec
+DELETE FROM peer_review_settings
+WHERE assignment_id = $1;
+
+-- name: ListPeerReviewSettingsToAssign :many
+SELECT peer_review_settings.* FROM peer_review_settings
+JOIN assignment ON assignment.assignment_id = peer_review_settings.assignment_id
+WHERE peer_review_settings.assigned_at IS NULL
+    AND assignment.due_date IS NOT NULL
+    AND GREATEST(
+        assignment.due_date,
+        assignment.late_cutoff,
+        (SELECT max(due_date) FROM assignment_extensions WHERE assignment_extensions.assignment_id = assignment.assignment_id)
+    ) <= now()
+ORDER BY peer_review_settings.assignment_id;
+
+-- name: ListPeerReviewSettingsToRemind :many
+SELECT * FROM peer_review_settings
+WHERE assigned_at IS NOT NULL
+    AND reminded_at IS NULL
+    AND finalized_at IS NULL
+    AND review_due_at <= $1
+ORDER BY assignment_id;
+
+-- name: ListPeerReviewSettingsToFinalize :many
+SELECT * FROM peer_review_settings
+WHERE assigned_at IS NOT NULL
+    AND finalized_at IS NULL
+    AND review_due_at <= now()
+ORDER BY assignment_id;
+
+-- name: SetPeerReviewsAssigned :exec
+UPDATE peer_review_settings
+SET assigned_at = now(), updated_at = now()
+WHERE assignment_id = $1;
+
+-- name: SetPeerReviewsReminded :exec
+UPDATE peer_review_settings
+SET reminded_at = now(), updated_at = now()
+WHERE assignment_id = $1;
+
+-- name: SetPeerReviewsFinalized :exec
+UPDATE peer_review_settings
+SET finalized_at = now(), updated_at = now()
+WHERE assignment_id = $1;
+
+-- name: CreatePeerReview :one
+INSERT INTO peer_reviews (
+    assignment_id,
+    submission_id,
+    reviewer_id
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (submission_id, reviewer_id) DO UPDATE
+SET assignment_id = EXCLUDED.assignment_id
+RETURNING *;
+
+-- name: GetPeerReview :one
+SELECT * FROM peer_reviews
+WHERE review_id = $1;
+
+-- name: SubmitPeerReview :one
+UPDATE peer_reviews
+SET rubric_scores = $2, points = $3, comment = $4, submitted_at = now()
+WHERE review_id = $1
+RETURNING *;
+
+-- name: ListPeerReviewsByAssignment :many
+SELECT * FROM peer_reviews
+WHERE assignment_id = $1
+ORDER BY submission_id, review_id;
+
+-- name: ListPeerReviewsBySubmission :many
+SELECT * FROM peer_reviews
+WHERE submission_id = $1
+ORDER BY review_id;
+
+-- name: ListPeerReviewsByReviewer :many
+SELECT
+    peer_reviews.review_id,
+    peer_reviews.submission_id,
+    peer_reviews.rubric_scores,
+    peer_reviews.points,
+    peer_reviews.comment,
+    peer_reviews.submitted_at
+FROM peer_reviews
+WHERE peer_reviews.assignment_id = $1 AND peer_reviews.reviewer_id = $2
+ORDER BY peer_reviews.review_id;
+
+-- name: ListPendingPeerReviewers :many
+SELECT users.user_id, users.first_name, users.email, count(*) AS pending
+FROM peer_reviews
+JOIN users ON users.user_id = peer_reviews.reviewer_id
+WHERE peer_reviews.assignment_id = $1 AND peer_reviews.submitted_at IS NULL
+GROUP BY users.user_id, users.first_name, users.email
+ORDER BY users.user_id;
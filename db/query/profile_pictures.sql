@@ -1,15 +1,17 @@
 -- name: CreateProfilePicture :one
 INSERT INTO profile_pictures(
     user_id,
-    picture
+    picture,
+    picture_variants
 ) VALUES (
-    $1, $2
+    $1, $2, $3
 ) RETURNING *;
 
 -- name: UpdateProfilePicture :one 
 UPDATE profile_pictures
 SET
-    picture = COALESCE(sqlc.narg(picture),picture)
+    picture = COALESCE(sqlc.narg(picture),picture),
+    picture_variants = COALESCE(sqlc.narg(picture_variants),picture_variants)
 WHERE
     user_id = sqlc.arg(user_id)
 RETURNING *;
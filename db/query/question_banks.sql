@@ -0,0 +1,50 @@
+-- name: CreateQuestionBank :one
+INSERT INTO question_banks (
+    course_id,
+    title
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetQuestionBank :one
+SELECT * FROM question_banks
+WHERE bank_id = $1 LIMIT 1;
+
+-- name: ListQuestionBanks :many
+SELECT * FROM question_banks
+WHERE course_id = $1
+ORDER BY bank_id;
+
+-- name: DeleteQuestionBank :exec
+DELETE FROM question_banks
+WHERE bank_id = $1;
+
+-- name: CreateQuestion :one
+INSERT INTO questions (
+    bank_id,
+    question_type,
+    prompt,
+    options,
+    answer_key,
+    points
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListQuestionsByBank :many
+SELECT * FROM questions
+WHERE bank_id = $1
+ORDER BY question_id;
+
+-- name: ListQuestionIDsByBank :many
+SELECT question_id FROM questions
+WHERE bank_id = $1
+ORDER BY question_id;
+
+-- name: ListQuestionsByIDs :many
+SELECT * FROM questions
+WHERE question_id = ANY(sqlc.arg(question_ids)::bigint[]);
+
+-- name: DeleteQuestion :exec
+DELETE FROM questions
+WHERE question_id = $1;
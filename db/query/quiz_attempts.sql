@@ -0,0 +1,39 @@
+-- name: CreateQuizAttempt :one
+INSERT INTO quiz_attempts (
+    quiz_id,
+    user_id,
+    question_ids,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetQuizAttempt :one
+SELECT * FROM quiz_attempts
+WHERE attempt_id = $1 LIMIT 1;
+
+-- name: GetInProgressQuizAttempt :one
+SELECT * FROM quiz_attempts
+WHERE quiz_id = $1 AND user_id = $2 AND status = 'in_progress'
+ORDER BY attempt_id DESC
+LIMIT 1;
+
+-- name: CountQuizAttempts :one
+SELECT COUNT(*) FROM quiz_attempts
+WHERE quiz_id = $1 AND user_id = $2;
+
+-- name: ListQuizAttemptsByUser :many
+SELECT * FROM quiz_attempts
+WHERE quiz_id = $1 AND user_id = $2
+ORDER BY attempt_id;
+
+-- name: SubmitQuizAttempt :one
+UPDATE quiz_attempts
+SET
+    responses = $2,
+    score = $3,
+    max_score = $4,
+    status = $5,
+    submitted_at = now()
+WHERE attempt_id = $1 AND status = 'in_progress'
+RETURNING *;
@@ -0,0 +1,37 @@
+-- name: CreateQuiz :one
+INSERT INTO quizzes (
+    course_id,
+    material_id,
+    title,
+    time_limit_seconds,
+    max_attempts
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetQuiz :one
+SELECT * FROM quizzes
+WHERE quiz_id = $1 LIMIT 1;
+
+-- name: ListQuizzesByCourse :many
+SELECT * FROM quizzes
+WHERE course_id = $1
+ORDER BY quiz_id;
+
+-- name: DeleteQuiz :exec
+DELETE FROM quizzes
+WHERE quiz_id = $1;
+
+-- name: CreateQuizPool :one
+INSERT INTO quiz_pools (
+    quiz_id,
+    bank_id,
+    draw_count
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: ListQuizPools :many
+SELECT * FROM quiz_pools
+WHERE quiz_id = $1
+ORDER BY pool_id;
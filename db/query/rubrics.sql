@@ -0,0 +1,19 @@
+-- name: UpsertRubric :one
+INSERT INTO rubrics (
+    assignment_id,
+    title,
+    criteria
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (assignment_id) DO UPDATE
+SET title = EXCLUDED.title, criteria = EXCLUDED.criteria, updated_at = now()
+RETURNING *;
+
+-- name: GetRubricByAssignment :one
+SELECT * FROM rubrics
+WHERE assignment_id = $1;
+
+-- name: DeleteRubric :exec
+DELETE FROM rubrics
+WHERE assignment_id = $1;
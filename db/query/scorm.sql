@@ -0,0 +1,57 @@
+-- name: CreateSCORMPackage :one
+INSERT INTO scorm_packages (
+    material_id,
+    version,
+    title,
+    package_dir,
+    launch_path
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetSCORMPackage :one
+SELECT * FROM scorm_packages
+WHERE material_id = $1
+LIMIT 1;
+
+-- name: UpdateSCORMPackage :one
+UPDATE scorm_packages
+SET
+    version = $2,
+    title = $3,
+    package_dir = $4,
+    launch_path = $5,
+    updated_at = now()
+WHERE material_id = $1
+RETURNING *;
+
+-- name: CreateSCORMAttempt :exec
+INSERT INTO scorm_attempts (
+    user_id,
+    material_id
+) VALUES (
+    $1, $2
+) ON CONFLICT (user_id, material_id) DO NOTHING;
+
+-- name: GetSCORMAttempt :one
+SELECT * FROM scorm_attempts
+WHERE user_id = $1 AND material_id = $2
+LIMIT 1;
+
+-- name: GetSCORMAttemptForUpdate :one
+SELECT * FROM scorm_attempts
+WHERE user_id = $1 AND material_id = $2
+LIMIT 1
+FOR UPDATE;
+
+-- name: UpdateSCORMAttempt :one
+UPDATE scorm_attempts
+SET
+    cmi = $2,
+    lesson_status = $3,
+    score = $4,
+    total_seconds = $5,
+    completed_at = COALESCE(completed_at, $6),
+    updated_at = now()
+WHERE attempt_id = $1
+RETURNING *;
@@ -0,0 +1,42 @@
+-- name: UpsertSubmissionText :one
+INSERT INTO submission_texts (
+    attempt_id,
+    assignment_id,
+    user_id,
+    content
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (attempt_id) DO UPDATE SET content = EXCLUDED.content
+RETURNING *;
+
+-- name: ListLatestSubmissionTexts :many
+SELECT DISTINCT ON (user_id) * FROM submission_texts
+WHERE assignment_id = $1 AND user_id <> $2
+ORDER BY user_id, attempt_id DESC;
+
+-- name: UpsertSimilarityReport :one
+INSERT INTO similarity_reports (
+    assignment_id,
+    attempt_id,
+    user_id,
+    matched_attempt_id,
+    matched_user_id,
+    score,
+    matches
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (attempt_id, matched_attempt_id) DO UPDATE
+SET score = EXCLUDED.score, matches = EXCLUDED.matches, created_at = now()
+RETURNING *;
+
+-- name: ListSimilarityReports :many
+SELECT * FROM similarity_reports
+WHERE assignment_id = $1
+ORDER BY score DESC, report_id;
+
+-- name: ListSimilarityReportsByUser :many
+SELECT * FROM similarity_reports
+WHERE assignment_id = $1 AND (user_id = $2 OR matched_user_id = $2)
+ORDER BY score DESC, report_id;
@@ -5,9 +5,12 @@ INSERT INTO submission (
     grade,
     resource,
     date_of_submission,
-    submitted
+    submitted,
+    is_late,
+    penalty_percent,
+    group_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetsubmissionsByAssignment :one
@@ -26,19 +29,71 @@ ORDER BY submission_id
 LIMIT $1
 OFFSET $2;
 
--- name: UpdateSubmission :one
+-- name: DeleteSubmission :execrows
+DELETE FROM submission
+WHERE submission_id = $1;
+
+
+-- name: GetSubmission :one
+SELECT * FROM submission
+WHERE assignment_id = $1
+    AND (user_id = $2 OR group_id IN (SELECT group_id FROM course_group_members WHERE user_id = $2))
+ORDER BY group_id IS NULL, submission_id
+LIMIT 1;
+
+-- name: GetSubmissionByID :one
+SELECT * FROM submission
+WHERE submission_id = $1;
+
+-- name: GradeSubmission :one
 UPDATE submission
-SET assignment_id = $2 AND user_id = $3
+SET grade = $2, rubric_scores = $3, feedback = $4, graded_at = now(), updated_at = now()
 WHERE submission_id = $1
 RETURNING *;
 
--- name: DeleteSubmission :exec
-DELETE FROM submission
-WHERE 
-    assignment_id = $1
-    AND user_id = $2;
+-- name: CreateSubmissionIfMissing :exec
+INSERT INTO submission (
+    assignment_id,
+    user_id,
+    grade,
+    resource,
+    date_of_submission,
+    submitted,
+    is_late,
+    penalty_percent,
+    group_id
+) VALUES (
+    $1, $2, 'null', $3, $4, true, $5, $6, $7
+) ON CONFLICT DO NOTHING;
 
+-- name: GetSubmissionForUpdate :one
+SELECT * FROM submission
+WHERE assignment_id = $1 AND user_id = $2 AND group_id IS NULL
+FOR UPDATE;
 
--- name: GetSubmission :one
+-- name: GetGroupSubmissionForUpdate :one
+SELECT * FROM submission
+WHERE assignment_id = $1 AND group_id = $2
+FOR UPDATE;
+
+-- name: UpdateSubmissionFromAttempt :one
+UPDATE submission
+SET
+    submitted = true,
+    resource = $2,
+    date_of_submission = $3,
+    is_late = $4,
+    penalty_percent = $5,
+    graded_attempt_id = $6,
+    grade = CASE WHEN sqlc.arg(clear_grade)::boolean THEN 'null' ELSE grade END,
+    rubric_scores = CASE WHEN sqlc.arg(clear_grade)::boolean THEN '[]' ELSE rubric_scores END,
+    feedback = CASE WHEN sqlc.arg(clear_grade)::boolean THEN '' ELSE feedback END,
+    graded_at = CASE WHEN sqlc.arg(clear_grade)::boolean THEN NULL ELSE graded_at END,
+    updated_at = now()
+WHERE submission_id = $1
+RETURNING *;
+
+-- name: ListSubmittedSubmissionsByAssignment :many
 SELECT * FROM submission
-WHERE assignment_id = $1 AND user_id = $2;
\ No newline at end of file
+WHERE assignment_id = $1 AND submitted = true
+ORDER BY submission_id;
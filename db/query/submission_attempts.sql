@@ -0,0 +1,30 @@
+-- name: CreateSubmissionAttempt :one
+INSERT INTO submission_attempts (
+    submission_id,
+    attempt_number,
+    resource,
+    checksum,
+    size,
+    is_late,
+    penalty_percent,
+    submitted_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetSubmissionAttempt :one
+SELECT * FROM submission_attempts
+WHERE submission_id = $1 AND attempt_number = $2;
+
+-- name: GetLatestSubmissionAttemptNumber :one
+SELECT COALESCE(MAX(attempt_number), 0)::bigint AS attempt_number FROM submission_attempts
+WHERE submission_id = $1;
+
+-- name: ListSubmissionAttempts :many
+SELECT * FROM submission_attempts
+WHERE submission_id = $1
+ORDER BY attempt_number;
+
+-- name: GetSubmissionAttemptByID :one
+SELECT * FROM submission_attempts
+WHERE attempt_id = $1;
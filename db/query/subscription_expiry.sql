@@ -0,0 +1,74 @@
+-- name: ExpireSubscriptions :many
+UPDATE subscriptions
+SET
+    active = false,
+    expired_at = now(),
+    updated_at = now()
+WHERE active = true AND expires_at <= now()
+RETURNING *;
+
+-- name: ListSubscriptionsToRemind :many
+SELECT
+    s.subscription_id,
+    s.user_id,
+    s.course_id,
+    s.expires_at,
+    u.email,
+    u.first_name,
+    u.locale,
+    c.title
+FROM subscriptions s
+JOIN users u ON u.user_id = s.user_id
+JOIN courses c ON c.course_id = s.course_id
+WHERE s.active = true
+    AND s.expiry_reminded_at IS NULL
+    AND s.expires_at > now()
+    AND s.expires_at <= sqlc.arg(remind_before)
+ORDER BY s.expires_at;
+
+-- name: SetSubscriptionExpiryReminded :exec
+UPDATE subscriptions
+SET expiry_reminded_at = now()
+WHERE subscription_id = $1;
+
+-- name: ExtendSubscriptions :many
+UPDATE subscriptions
+SET
+    active = true,
+    expires_at = CASE
+        WHEN sqlc.narg(expires_at)::timestamptz IS NOT NULL THEN sqlc.narg(expires_at)::timestamptz
+        ELSE GREATEST(expires_at, now()) + make_interval(days => sqlc.arg(days)::int)
+    END,
+    expiry_reminded_at = NULL,
+    expired_at = NULL,
+    updated_at = now()
+WHERE course_id = sqlc.arg(course_id)
+    AND (cardinality(sqlc.arg(user_ids)::bigint[]) = 0 OR user_id = ANY(sqlc.arg(user_ids)::bigint[]))
+    AND (active = true OR expired_at IS NOT NULL)
+    AND (sqlc.narg(expires_at)::timestamptz IS NOT NULL OR expires_at IS NOT NULL)
+RETURNING *;
+
+-- name: RenewSubscription :one
+UPDATE subscriptions
+SET
+    expires_at = (
+        SELECT GREATEST(subscriptions.expires_at, now()) + make_interval(days => e.access_days::int)
+        FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id AND e.access_days > 0
+    ),
+    expiry_reminded_at = NULL,
+    updated_at = now()
+WHERE subscription_id = $1
+RETURNING *;
+
+-- name: RevertSubscriptionRenewal :one
+UPDATE subscriptions
+SET
+    expires_at = CASE
+        WHEN sqlc.narg(renewed_until)::timestamptz IS NULL OR expires_at IS NULL THEN sqlc.arg(renewed_from)::timestamptz
+        ELSE expires_at - (sqlc.narg(renewed_until)::timestamptz - sqlc.arg(renewed_from)::timestamptz)
+    END,
+    expiry_reminded_at = NULL,
+    updated_at = now()
+WHERE subscription_id = sqlc.arg(subscription_id)
+RETURNING *;
@@ -14,7 +14,19 @@ INSERT INTO subscriptions (
 UPDATE subscriptions
 SET 
     pending = COALESCE(sqlc.narg(pending), pending),
-    active = COALESCE(sqlc.narg(active), active)
+    active = COALESCE(sqlc.narg(active), active),
+    waitlisted_at = CASE WHEN COALESCE(sqlc.narg(active), active) THEN NULL ELSE waitlisted_at END,
+    starts_at = CASE WHEN NOT active AND COALESCE(sqlc.narg(active), active) THEN GREATEST(now(), (
+        SELECT e.access_starts_at FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id
+    )) ELSE starts_at END,
+    expires_at = CASE WHEN NOT active AND COALESCE(sqlc.narg(active), active) THEN (
+        SELECT GREATEST(now(), e.access_starts_at) + make_interval(days => e.access_days::int)
+        FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id AND e.access_days > 0
+    ) ELSE expires_at END,
+    expiry_reminded_at = CASE WHEN NOT active AND COALESCE(sqlc.narg(active), active) THEN NULL ELSE expiry_reminded_at END,
+    expired_at = CASE WHEN COALESCE(sqlc.narg(active), active) THEN NULL ELSE expired_at END
 
 WHERE
     user_id = sqlc.arg(user_id) AND course_id = sqlc.arg(course_id)
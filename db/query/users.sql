@@ -81,3 +81,15 @@ WHERE EXISTS (
     FROM users
     WHERE u.email = $1
 );
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE lower(email) = lower(sqlc.arg(email))
+ORDER BY user_id
+LIMIT 1;
+
+-- name: UpdateUserLocale :one
+UPDATE users
+SET locale = sqlc.arg(locale)
+WHERE user_id = sqlc.arg(user_id)
+RETURNING *;
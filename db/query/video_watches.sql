@@ -0,0 +1,48 @@
+-- name: CreateVideoWatch :exec
+INSERT INTO video_watches (
+    user_id,
+    material_id
+) VALUES (
+    $1, $2
+) ON CONFLICT (user_id, material_id) DO NOTHING;
+
+-- name: GetVideoWatch :one
+SELECT * FROM video_watches
+WHERE user_id = $1 AND material_id = $2
+LIMIT 1;
+
+-- name: GetVideoWatchForUpdate :one
+SELECT * FROM video_watches
+WHERE user_id = $1 AND material_id = $2
+LIMIT 1
+FOR UPDATE;
+
+-- name: UpdateVideoWatch :one
+UPDATE video_watches
+SET
+    segments = $2,
+    watched_seconds = $3,
+    duration_seconds = $4,
+    position_seconds = $5,
+    furthest_seconds = $6,
+    completed_at = COALESCE(completed_at, $7),
+    last_heartbeat_at = $8,
+    started_at = COALESCE(started_at, $8),
+    updated_at = now()
+WHERE watch_id = $1
+RETURNING *;
+
+-- name: ListVideoDropOff :many
+SELECT
+    m.material_id,
+    m.title,
+    COUNT(w.watch_id) AS viewers,
+    COUNT(w.completed_at) AS completions,
+    COALESCE(AVG(w.furthest_seconds), 0)::double precision AS average_drop_off_seconds,
+    COALESCE(AVG(w.furthest_seconds * 100 / NULLIF(w.duration_seconds, 0)), 0)::double precision AS average_drop_off_percent,
+    COALESCE(AVG(w.watched_seconds * 100 / NULLIF(w.duration_seconds, 0)), 0)::double precision AS average_watched_percent
+FROM material m
+LEFT JOIN video_watches w ON w.material_id = m.material_id
+WHERE m.course_id = $1 AND m.material_type = 'video'
+GROUP BY m.material_id, m.title, m.order_number
+ORDER BY m.order_number, m.material_id;
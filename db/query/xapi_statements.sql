@@ -0,0 +1,36 @@
+-- name: CreateXAPIStatement :exec
+INSERT INTO xapi_statements (
+    statement_id,
+    actor,
+    verb_id,
+    activity_id,
+    statement,
+    stored
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) ON CONFLICT (statement_id) DO NOTHING;
+
+-- name: GetXAPIStatement :one
+SELECT * FROM xapi_statements
+WHERE statement_id = $1
+LIMIT 1;
+
+-- name: VoidXAPIStatement :exec
+UPDATE xapi_statements
+SET voided = true
+WHERE statement_id = $1 AND verb_id <> 'http://adlnet.gov/expapi/verbs/voided';
+
+-- name: ListXAPIStatements :many
+SELECT * FROM xapi_statements
+WHERE voided = false
+  AND (sqlc.arg(actor)::varchar = '' OR actor = sqlc.arg(actor))
+  AND (sqlc.arg(verb_id)::varchar = '' OR verb_id = sqlc.arg(verb_id))
+  AND (sqlc.arg(activity_id)::varchar = '' OR activity_id = sqlc.arg(activity_id))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR stored > sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamptz IS NULL OR stored <= sqlc.narg(until))
+ORDER BY
+    CASE WHEN sqlc.arg(ascending)::boolean THEN stored END ASC,
+    CASE WHEN NOT sqlc.arg(ascending)::boolean THEN stored END DESC,
+    statement_id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
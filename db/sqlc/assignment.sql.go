@@ -7,6 +7,9 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createAssignment = `-- name: CreateAssignment :one
@@ -14,17 +17,27 @@ INSERT INTO assignment (
     title,
     course_id,
     due_date,
-    assignment_file
+    assignment_file,
+    late_policy,
+    late_penalty_percent,
+    late_cutoff,
+    max_attempts,
+    group_submission
 ) VALUES (
-    $1, $2, $3, $4
-)  RETURNING assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)  RETURNING assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at, late_policy, late_penalty_percent, late_cutoff, max_attempts, grades_published_at, group_submission
 `
 
 type CreateAssignmentParams struct {
-	Title          string `json:"title"`
-	CourseID       int64  `json:"course_id"`
-	DueDate        string `json:"due_date"`
-	AssignmentFile string `json:"assignment_file"`
+	Title              string             `json:"title"`
+	CourseID           int64              `json:"course_id"`
+	DueDate            pgtype.Timestamptz `json:"due_date"`
+	AssignmentFile     string             `json:"assignment_file"`
+	LatePolicy         string             `json:"late_policy"`
+	LatePenaltyPercent int64              `json:"late_penalty_percent"`
+	LateCutoff         pgtype.Timestamptz `json:"late_cutoff"`
+	MaxAttempts        int64              `json:"max_attempts"`
+	GroupSubmission    bool               `json:"group_submission"`
 }
 
 func (q *Queries) CreateAssignment(ctx context.Context, arg CreateAssignmentParams) (Assignment, error) {
@@ -33,6 +46,11 @@ func (q *Queries) CreateAssignment(ctx context.Context, arg CreateAssignmentPara
 		arg.CourseID,
 		arg.DueDate,
 		arg.AssignmentFile,
+		arg.LatePolicy,
+		arg.LatePenaltyPercent,
+		arg.LateCutoff,
+		arg.MaxAttempts,
+		arg.GroupSubmission,
 	)
 	var i Assignment
 	err := row.Scan(
@@ -43,6 +61,43 @@ func (q *Queries) CreateAssignment(ctx context.Context, arg CreateAssignmentPara
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LatePolicy,
+		&i.LatePenaltyPercent,
+		&i.LateCutoff,
+		&i.MaxAttempts,
+		&i.GradesPublishedAt,
+		&i.GroupSubmission,
+	)
+	return i, err
+}
+
+const createAssignmentExtension = `-- name: CreateAssignmentExtension :one
+INSERT INTO assignment_extensions (
+    assignment_id,
+    user_id,
+    due_date
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (assignment_id, user_id) DO UPDATE SET due_date = EXCLUDED.due_date
+RETURNING extension_id, assignment_id, user_id, due_date, created_at
+`
+
+type CreateAssignmentExtensionParams struct {
+	AssignmentID int64     `json:"assignment_id"`
+	UserID       int64     `json:"user_id"`
+	DueDate      time.Time `json:"due_date"`
+}
+
+func (q *Queries) CreateAssignmentExtension(ctx context.Context, arg CreateAssignmentExtensionParams) (AssignmentExtension, error) {
+	row := q.db.QueryRow(ctx, createAssignmentExtension, arg.AssignmentID, arg.UserID, arg.DueDate)
+	var i AssignmentExtension
+	err := row.Scan(
+		&i.ExtensionID,
+		&i.AssignmentID,
+		&i.UserID,
+		&i.DueDate,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -57,8 +112,23 @@ func (q *Queries) DeleteAssignment(ctx context.Context, assignmentID int64) erro
 	return err
 }
 
+const deleteAssignmentExtension = `-- name: DeleteAssignmentExtension :exec
+DELETE FROM assignment_extensions
+WHERE assignment_id = $1 AND user_id = $2
+`
+
+type DeleteAssignmentExtensionParams struct {
+	AssignmentID int64 `json:"assignment_id"`
+	UserID       int64 `json:"user_id"`
+}
+
+func (q *Queries) DeleteAssignmentExtension(ctx context.Context, arg DeleteAssignmentExtensionParams) error {
+	_, err := q.db.Exec(ctx, deleteAssignmentExtension, arg.AssignmentID, arg.UserID)
+	return err
+}
+
 const getAssignment = `-- name: GetAssignment :one
-SELECT assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at FROM assignment
+SELECT assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at, late_policy, late_penalty_percent, late_cutoff, max_attempts, grades_published_at, group_submission FROM assignment
 WHERE assignment_id = $1
 `
 
@@ -73,23 +143,163 @@ func (q *Queries) GetAssignment(ctx context.Context, assignmentID int64) (Assign
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LatePolicy,
+		&i.LatePenaltyPercent,
+		&i.LateCutoff,
+		&i.MaxAttempts,
+		&i.GradesPublishedAt,
+		&i.GroupSubmission,
+	)
+	return i, err
+}
+
+const getAssignmentExtension = `-- name: GetAssignmentExtension :one
+SELECT extension_id, assignment_id, user_id, due_date, created_at FROM assignment_extensions
+WHERE assignment_id = $1 AND user_id = $2
+`
+
+type GetAssignmentExtensionParams struct {
+	AssignmentID int64 `json:"assignment_id"`
+	UserID       int64 `json:"user_id"`
+}
+
+func (q *Queries) GetAssignmentExtension(ctx context.Context, arg GetAssignmentExtensionParams) (AssignmentExtension, error) {
+	row := q.db.QueryRow(ctx, getAssignmentExtension, arg.AssignmentID, arg.UserID)
+	var i AssignmentExtension
+	err := row.Scan(
+		&i.ExtensionID,
+		&i.AssignmentID,
+		&i.UserID,
+		&i.DueDate,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAssignmentExtensions = `-- name: ListAssignmentExtensions :many
+SELECT extension_id, assignment_id, user_id, due_date, created_at FROM assignment_extensions
+WHERE assignment_id = $1
+ORDER BY user_id
+`
+
+func (q *Queries) ListAssignmentExtensions(ctx context.Context, assignmentID int64) ([]AssignmentExtension, error) {
+	rows, err := q.db.Query(ctx, listAssignmentExtensions, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AssignmentExtension{}
+	for rows.Next() {
+		var i AssignmentExtension
+		if err := rows.Scan(
+			&i.ExtensionID,
+			&i.AssignmentID,
+			&i.UserID,
+			&i.DueDate,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAssignmentsByCourse = `-- name: ListAssignmentsByCourse :many
+SELECT assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at, late_policy, late_penalty_percent, late_cutoff, max_attempts, grades_published_at, group_submission FROM assignment
+WHERE course_id = $1
+ORDER BY due_date NULLS LAST, assignment_id
+`
+
+func (q *Queries) ListAssignmentsByCourse(ctx context.Context, courseID int64) ([]Assignment, error) {
+	rows, err := q.db.Query(ctx, listAssignmentsByCourse, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Assignment{}
+	for rows.Next() {
+		var i Assignment
+		if err := rows.Scan(
+			&i.AssignmentID,
+			&i.CourseID,
+			&i.Title,
+			&i.AssignmentFile,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LatePolicy,
+			&i.LatePenaltyPercent,
+			&i.LateCutoff,
+			&i.MaxAttempts,
+			&i.GradesPublishedAt,
+			&i.GroupSubmission,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setAssignmentGradesPublished = `-- name: SetAssignmentGradesPublished :one
+UPDATE assignment
+SET grades_published_at = $2
+WHERE assignment_id = $1
+RETURNING assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at, late_policy, late_penalty_percent, late_cutoff, max_attempts, grades_published_at, group_submission
+`
+
+type SetAssignmentGradesPublishedParams struct {
+	AssignmentID      int64              `json:"assignment_id"`
+	GradesPublishedAt pgtype.Timestamptz `json:"grades_published_at"`
+}
+
+func (q *Queries) SetAssignmentGradesPublished(ctx context.Context, arg SetAssignmentGradesPublishedParams) (Assignment, error) {
+	row := q.db.QueryRow(ctx, setAssignmentGradesPublished, arg.AssignmentID, arg.GradesPublishedAt)
+	var i Assignment
+	err := row.Scan(
+		&i.AssignmentID,
+		&i.CourseID,
+		&i.Title,
+		&i.AssignmentFile,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LatePolicy,
+		&i.LatePenaltyPercent,
+		&i.LateCutoff,
+		&i.MaxAttempts,
+		&i.GradesPublishedAt,
+		&i.GroupSubmission,
 	)
 	return i, err
 }
 
 const updateAssignment = `-- name: UpdateAssignment :one
 UPDATE assignment
-SET  title = $2,due_date = $3, assignment_file = $4, course_id = $5
+SET  title = $2,due_date = $3, assignment_file = $4, course_id = $5,
+    late_policy = $6, late_penalty_percent = $7, late_cutoff = $8, max_attempts = $9, group_submission = $10
 WHERE assignment_id = $1
-RETURNING assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at
+RETURNING assignment_id, course_id, title, assignment_file, due_date, created_at, updated_at, late_policy, late_penalty_percent, late_cutoff, max_attempts, grades_published_at, group_submission
 `
 
 type UpdateAssignmentParams struct {
-	AssignmentID   int64  `json:"assignment_id"`
-	Title          string `json:"title"`
-	DueDate        string `json:"due_date"`
-	AssignmentFile string `json:"assignment_file"`
-	CourseID       int64  `json:"course_id"`
+	AssignmentID       int64              `json:"assignment_id"`
+	Title              string             `json:"title"`
+	DueDate            pgtype.Timestamptz `json:"due_date"`
+	AssignmentFile     string             `json:"assignment_file"`
+	CourseID           int64              `json:"course_id"`
+	LatePolicy         string             `json:"late_policy"`
+	LatePenaltyPercent int64              `json:"late_penalty_percent"`
+	LateCutoff         pgtype.Timestamptz `json:"late_cutoff"`
+	MaxAttempts        int64              `json:"max_attempts"`
+	GroupSubmission    bool               `json:"group_submission"`
 }
 
 func (q *Queries) UpdateAssignment(ctx context.Context, arg UpdateAssignmentParams) (Assignment, error) {
@@ -99,6 +309,11 @@ func (q *Queries) UpdateAssignment(ctx context.Context, arg UpdateAssignmentPara
 		arg.DueDate,
 		arg.AssignmentFile,
 		arg.CourseID,
+		arg.LatePolicy,
+		arg.LatePenaltyPercent,
+		arg.LateCutoff,
+		arg.MaxAttempts,
+		arg.GroupSubmission,
 	)
 	var i Assignment
 	err := row.Scan(
@@ -109,6 +324,12 @@ func (q *Queries) UpdateAssignment(ctx context.Context, arg UpdateAssignmentPara
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LatePolicy,
+		&i.LatePenaltyPercent,
+		&i.LateCutoff,
+		&i.MaxAttempts,
+		&i.GradesPublishedAt,
+		&i.GroupSubmission,
 	)
 	return i, err
 }
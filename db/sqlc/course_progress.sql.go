@@ -18,7 +18,9 @@ INSERT INTO course_progress (
     progress
 ) VALUES (
     $1, $2, $3
-) RETURNING courseprogress_id, course_id, user_id, progress, created_at, updated_at
+)
+ON CONFLICT (user_id, course_id) DO UPDATE SET progress = course_progress.progress
+RETURNING courseprogress_id, course_id, user_id, progress, created_at, updated_at, completed_at
 `
 
 type CreateCourseProgressParams struct {
@@ -37,6 +39,7 @@ func (q *Queries) CreateCourseProgress(ctx context.Context, arg CreateCourseProg
 		&i.Progress,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CompletedAt,
 	)
 	return i, err
 }
@@ -65,7 +68,7 @@ func (q *Queries) GetCourseCompletedUserCount(ctx context.Context, progress int6
 }
 
 const getCourseProgress = `-- name: GetCourseProgress :one
-SELECT courseprogress_id, course_id, user_id, progress, created_at, updated_at FROM course_progress
+SELECT courseprogress_id, course_id, user_id, progress, created_at, updated_at, completed_at FROM course_progress
 WHERE 
     user_id = $1
     AND course_id = $2
@@ -87,6 +90,75 @@ func (q *Queries) GetCourseProgress(ctx context.Context, arg GetCourseProgressPa
 		&i.Progress,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getCourseProgressCounts = `-- name: GetCourseProgressCounts :one
+SELECT
+    (SELECT COUNT(*) FROM material m
+        WHERE m.course_id = $1)::bigint AS total_materials,
+    (SELECT COUNT(*) FROM lesson_completion lc
+        JOIN material m ON m.material_id = lc.material_id
+        WHERE m.course_id = $1 AND lc.user_id = $2 AND lc.completed = true)::bigint AS completed_materials,
+    (SELECT COUNT(*) FROM assignment a
+        WHERE a.course_id = $1)::bigint AS total_assignments,
+    (SELECT COUNT(DISTINCT s.assignment_id) FROM submission s
+        JOIN assignment a ON a.assignment_id = s.assignment_id
+        WHERE a.course_id = $1 AND s.submitted = true
+            AND (s.user_id = $2 OR s.group_id IN (SELECT group_id FROM course_group_members WHERE user_id = $2)))::bigint AS submitted_assignments,
+    (SELECT COUNT(*) FROM quizzes qz
+        WHERE qz.course_id = $1)::bigint AS total_quizzes,
+    (SELECT COUNT(DISTINCT qa.quiz_id) FROM quiz_attempts qa
+        JOIN quizzes qz ON qz.quiz_id = qa.quiz_id
+        WHERE qz.course_id = $1 AND qa.user_id = $2 AND qa.submitted_at IS NOT NULL)::bigint AS submitted_quizzes
+`
+
+type GetCourseProgressCountsRow struct {
+	TotalMaterials       int64 `json:"total_materials"`
+	CompletedMaterials   int64 `json:"completed_materials"`
+	TotalAssignments     int64 `json:"total_assignments"`
+	SubmittedAssignments int64 `json:"submitted_assignments"`
+	TotalQuizzes         int64 `json:"total_quizzes"`
+	SubmittedQuizzes     int64 `json:"submitted_quizzes"`
+}
+
+type GetCourseProgressCountsParams struct {
+	CourseID int64 `json:"course_id"`
+	UserID   int64 `json:"user_id"`
+}
+
+func (q *Queries) GetCourseProgressCounts(ctx context.Context, arg GetCourseProgressCountsParams) (GetCourseProgressCountsRow, error) {
+	row := q.db.QueryRow(ctx, getCourseProgressCounts, arg.CourseID, arg.UserID)
+	var i GetCourseProgressCountsRow
+	err := row.Scan(
+		&i.TotalMaterials,
+		&i.CompletedMaterials,
+		&i.TotalAssignments,
+		&i.SubmittedAssignments,
+		&i.TotalQuizzes,
+		&i.SubmittedQuizzes,
+	)
+	return i, err
+}
+
+const getCourseProgressWeights = `-- name: GetCourseProgressWeights :one
+SELECT course_id, material_weight, assignment_weight, quiz_weight, created_at, updated_at FROM course_progress_weights
+WHERE course_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCourseProgressWeights(ctx context.Context, courseID int64) (CourseProgressWeight, error) {
+	row := q.db.QueryRow(ctx, getCourseProgressWeights, courseID)
+	var i CourseProgressWeight
+	err := row.Scan(
+		&i.CourseID,
+		&i.MaterialWeight,
+		&i.AssignmentWeight,
+		&i.QuizWeight,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -125,8 +197,37 @@ func (q *Queries) GetTotalUserCompletedCourseCount(ctx context.Context, arg GetT
 	return count, err
 }
 
+const listCourseLearners = `-- name: ListCourseLearners :many
+SELECT user_id FROM subscriptions
+WHERE course_id = $1 AND active = true
+UNION
+SELECT user_id FROM course_progress
+WHERE course_id = $1
+ORDER BY user_id
+`
+
+func (q *Queries) ListCourseLearners(ctx context.Context, courseID int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listCourseLearners, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCourseProgressByUser = `-- name: ListCourseProgressByUser :many
-SELECT courseprogress_id, course_id, user_id, progress, created_at, updated_at FROM course_progress
+SELECT courseprogress_id, course_id, user_id, progress, created_at, updated_at, completed_at FROM course_progress
 WHERE user_id =$1
 ORDER BY courseprogress_id
 LIMIT $2
@@ -155,6 +256,7 @@ func (q *Queries) ListCourseProgressByUser(ctx context.Context, arg ListCoursePr
 			&i.Progress,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CompletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -173,7 +275,7 @@ SET
     progress = COALESCE($2,progress)
 WHERE
     user_id = $3
-RETURNING courseprogress_id, course_id, user_id, progress, created_at, updated_at
+RETURNING courseprogress_id, course_id, user_id, progress, created_at, updated_at, completed_at
 `
 
 type UpdateCourseProgressParams struct {
@@ -192,6 +294,93 @@ func (q *Queries) UpdateCourseProgress(ctx context.Context, arg UpdateCourseProg
 		&i.Progress,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const upsertCourseProgress = `-- name: UpsertCourseProgress :one
+INSERT INTO course_progress (
+    course_id,
+    user_id,
+    progress,
+    completed_at
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (user_id, course_id) DO UPDATE SET
+    progress = EXCLUDED.progress,
+    completed_at = COALESCE(course_progress.completed_at, EXCLUDED.completed_at),
+    updated_at = now()
+RETURNING courseprogress_id, course_id, user_id, progress, created_at, updated_at, completed_at
+`
+
+type UpsertCourseProgressParams struct {
+	CourseID    int64              `json:"course_id"`
+	UserID      int64              `json:"user_id"`
+	Progress    int64              `json:"progress"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+func (q *Queries) UpsertCourseProgress(ctx context.Context, arg UpsertCourseProgressParams) (CourseProgress, error) {
+	row := q.db.QueryRow(ctx, upsertCourseProgress,
+		arg.CourseID,
+		arg.UserID,
+		arg.Progress,
+		arg.CompletedAt,
+	)
+	var i CourseProgress
+	err := row.Scan(
+		&i.CourseprogressID,
+		&i.CourseID,
+		&i.UserID,
+		&i.Progress,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const upsertCourseProgressWeights = `-- name: UpsertCourseProgressWeights :one
+INSERT INTO course_progress_weights (
+    course_id,
+    material_weight,
+    assignment_weight,
+    quiz_weight
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (course_id) DO UPDATE SET
+    material_weight = EXCLUDED.material_weight,
+    assignment_weight = EXCLUDED.assignment_weight,
+    quiz_weight = EXCLUDED.quiz_weight,
+    updated_at = now()
+RETURNING course_id, material_weight, assignment_weight, quiz_weight, created_at, updated_at
+`
+
+type UpsertCourseProgressWeightsParams struct {
+	CourseID         int64 `json:"course_id"`
+	MaterialWeight   int64 `json:"material_weight"`
+	AssignmentWeight int64 `json:"assignment_weight"`
+	QuizWeight       int64 `json:"quiz_weight"`
+}
+
+func (q *Queries) UpsertCourseProgressWeights(ctx context.Context, arg UpsertCourseProgressWeightsParams) (CourseProgressWeight, error) {
+	row := q.db.QueryRow(ctx, upsertCourseProgressWeights,
+		arg.CourseID,
+		arg.MaterialWeight,
+		arg.AssignmentWeight,
+		arg.QuizWeight,
+	)
+	var i CourseProgressWeight
+	err := row.Scan(
+		&i.CourseID,
+		&i.MaterialWeight,
+		&i.AssignmentWeight,
+		&i.QuizWeight,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -21,21 +21,22 @@ INSERT INTO courses (
     image,
     catagory,
     what_will,
-    sequential_access
-    
+    sequential_access,
+    image_variants
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at, image_variants
 `
 
 type CreateCoursesParams struct {
-	UserID           int64  `json:"user_id"`
-	Title            string `json:"title"`
-	Description      string `json:"description"`
-	Image            string `json:"image"`
-	Catagory         string `json:"catagory"`
-	WhatWill         typetext.WhatWill `json:"what_will"`
-	SequentialAccess bool   `json:"sequential_access"`
+	UserID           int64                  `json:"user_id"`
+	Title            string                 `json:"title"`
+	Description      string                 `json:"description"`
+	Image            string                 `json:"image"`
+	Catagory         string                 `json:"catagory"`
+	WhatWill         typetext.WhatWill      `json:"what_will"`
+	SequentialAccess bool                   `json:"sequential_access"`
+	ImageVariants    typetext.ImageVariants `json:"image_variants"`
 }
 
 func (q *Queries) CreateCourses(ctx context.Context, arg CreateCoursesParams) (Course, error) {
@@ -47,6 +48,7 @@ func (q *Queries) CreateCourses(ctx context.Context, arg CreateCoursesParams) (C
 		arg.Catagory,
 		arg.WhatWill,
 		arg.SequentialAccess,
+		arg.ImageVariants,
 	)
 	var i Course
 	err := row.Scan(
@@ -60,6 +62,7 @@ func (q *Queries) CreateCourses(ctx context.Context, arg CreateCoursesParams) (C
 		&i.SequentialAccess,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ImageVariants,
 	)
 	return i, err
 }
@@ -75,7 +78,7 @@ func (q *Queries) DeleteCourses(ctx context.Context, courseID int64) error {
 }
 
 const getCourseByUserID = `-- name: GetCourseByUserID :one
-SELECT course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at FROM courses
+SELECT course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at, image_variants FROM courses
 WHERE user_id = $1
 `
 
@@ -93,12 +96,13 @@ func (q *Queries) GetCourseByUserID(ctx context.Context, userID int64) (Course,
 		&i.SequentialAccess,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ImageVariants,
 	)
 	return i, err
 }
 
 const getCourses = `-- name: GetCourses :one
-SELECT course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at FROM courses
+SELECT course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at, image_variants FROM courses
 WHERE course_id = $1
 `
 
@@ -116,13 +120,14 @@ func (q *Queries) GetCourses(ctx context.Context, courseID int64) (Course, error
 		&i.SequentialAccess,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ImageVariants,
 	)
 	return i, err
 }
 
 const getEntireCourse = `-- name: GetEntireCourse :one
 SELECT 
-    c.course_id, c.user_id, c.title, c.description, c.image, c.catagory, c.what_will, c.sequential_access, c.created_at, c.updated_at,
+    c.course_id, c.user_id, c.title, c.description, c.image, c.catagory, c.what_will, c.sequential_access, c.created_at, c.updated_at, c.image_variants,
     COALESCE(json_agg(DISTINCT m) FILTER (WHERE m.material_id IS NOT NULL), '[]') AS material,
     COALESCE(json_agg(DISTINCT a) FILTER (WHERE a.assignment_id IS NOT NULL), '[]') AS assignment
 FROM 
@@ -139,18 +144,19 @@ LIMIT 1
 `
 
 type GetEntireCourseRow struct {
-	CourseID         int64       `json:"course_id"`
-	UserID           int64       `json:"user_id"`
-	Title            string      `json:"title"`
-	Description      string      `json:"description"`
-	Image            string      `json:"image"`
-	Catagory         string      `json:"catagory"`
-	WhatWill         []byte      `json:"what_will"`
-	SequentialAccess bool        `json:"sequential_access"`
-	CreatedAt        time.Time   `json:"created_at"`
-	UpdatedAt        time.Time   `json:"updated_at"`
-	Material         interface{} `json:"material"`
-	Assignment       interface{} `json:"assignment"`
+	CourseID         int64                  `json:"course_id"`
+	UserID           int64                  `json:"user_id"`
+	Title            string                 `json:"title"`
+	Description      string                 `json:"description"`
+	Image            string                 `json:"image"`
+	Catagory         string                 `json:"catagory"`
+	WhatWill         []byte                 `json:"what_will"`
+	SequentialAccess bool                   `json:"sequential_access"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	ImageVariants    typetext.ImageVariants `json:"image_variants"`
+	Material         interface{}            `json:"material"`
+	Assignment       interface{}            `json:"assignment"`
 }
 
 func (q *Queries) GetEntireCourse(ctx context.Context, courseID int64) (GetEntireCourseRow, error) {
@@ -167,6 +173,7 @@ func (q *Queries) GetEntireCourse(ctx context.Context, courseID int64) (GetEntir
 		&i.SequentialAccess,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ImageVariants,
 		&i.Material,
 		&i.Assignment,
 	)
@@ -203,15 +210,15 @@ LIMIT 100
 `
 
 type ListAllCourseByCatagoryRow struct {
-	CourseID    int64     `json:"course_id"`
-	UserID      int64     `json:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Image       string    `json:"image"`
-	Catagory    string    `json:"catagory"`
-	CreatedAt   time.Time `json:"created_at"`
-	WhatWill    typetext.WhatWill   `json:"what_will"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	CourseID    int64             `json:"course_id"`
+	UserID      int64             `json:"user_id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	Catagory    string            `json:"catagory"`
+	CreatedAt   time.Time         `json:"created_at"`
+	WhatWill    typetext.WhatWill `json:"what_will"`
+	UpdatedAt   time.Time         `json:"updated_at"`
 }
 
 func (q *Queries) ListAllCourseByCatagory(ctx context.Context, catagory string) ([]ListAllCourseByCatagoryRow, error) {
@@ -272,7 +279,7 @@ func (q *Queries) ListAllCourseCatagories(ctx context.Context) ([]string, error)
 }
 
 const listCourses = `-- name: ListCourses :many
-SELECT course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at FROM courses
+SELECT course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at, image_variants FROM courses
 ORDER BY course_id
 LIMIT $1
 OFFSET $2
@@ -303,6 +310,7 @@ func (q *Queries) ListCourses(ctx context.Context, arg ListCoursesParams) ([]Cou
 			&i.SequentialAccess,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ImageVariants,
 		); err != nil {
 			return nil, err
 		}
@@ -322,20 +330,22 @@ SET
        description = COALESCE($3, description),
        catagory = COALESCE($4, catagory),
         sequential_access = COALESCE($5, sequential_access),
-         what_will = COALESCE($6, what_will)
+         what_will = COALESCE($6, what_will),
+       image_variants = COALESCE($7, image_variants)
 
-WHERE  course_id = $7
-RETURNING course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at
+WHERE  course_id = $8
+RETURNING course_id, user_id, title, description, image, catagory, what_will, sequential_access, created_at, updated_at, image_variants
 `
 
 type UpdateCoursesParams struct {
-	Title            pgtype.Text `json:"title"`
-	Image            pgtype.Text `json:"image"`
-	Description      pgtype.Text `json:"description"`
-	Catagory         pgtype.Text `json:"catagory"`
-	SequentialAccess pgtype.Bool `json:"sequential_access"`
-	WhatWill         typetext.WhatWill `json:"what_will"`
-	CourseID         int64       `json:"course_id"`
+	Title            pgtype.Text             `json:"title"`
+	Image            pgtype.Text             `json:"image"`
+	Description      pgtype.Text             `json:"description"`
+	Catagory         pgtype.Text             `json:"catagory"`
+	SequentialAccess pgtype.Bool             `json:"sequential_access"`
+	WhatWill         typetext.WhatWill       `json:"what_will"`
+	ImageVariants    *typetext.ImageVariants `json:"image_variants"`
+	CourseID         int64                   `json:"course_id"`
 }
 
 func (q *Queries) UpdateCourses(ctx context.Context, arg UpdateCoursesParams) (Course, error) {
@@ -346,6 +356,7 @@ func (q *Queries) UpdateCourses(ctx context.Context, arg UpdateCoursesParams) (C
 		arg.Catagory,
 		arg.SequentialAccess,
 		arg.WhatWill,
+		arg.ImageVariants,
 		arg.CourseID,
 	)
 	var i Course
@@ -360,6 +371,7 @@ func (q *Queries) UpdateCourses(ctx context.Context, arg UpdateCoursesParams) (C
 		&i.SequentialAccess,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ImageVariants,
 	)
 	return i, err
 }
@@ -3,13 +3,14 @@ package db
 import (
 	"errors"
 
-	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
 const (
 	ForeignKeyViolation = "23503"
 	UniqueViolations    = "23505"
+	CheckViolation      = "23514"
 )
 
 var ErrRecordNotFound = pgx.ErrNoRows
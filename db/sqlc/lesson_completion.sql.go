@@ -19,7 +19,9 @@ INSERT INTO lesson_completion (
     completed
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING completion_id, user_id, course_id, material_id, completed, completed_at
+)
+ON CONFLICT (user_id, material_id) DO UPDATE SET completed = EXCLUDED.completed
+RETURNING completion_id, user_id, course_id, material_id, completed, completed_at
 `
 
 type CreateLessonCompletionParams struct {
@@ -49,6 +49,34 @@ func (q *Queries) DeleteMark(ctx context.Context, markID int64) error {
 	return err
 }
 
+const getCourseAssessmentMark = `-- name: GetCourseAssessmentMark :one
+SELECT COALESCE(ROUND(AVG(best.percentage)), 0)::bigint AS marks
+FROM (
+    SELECT MAX(a.score * 100.0 / NULLIF(a.max_score, 0))::double precision AS percentage
+    FROM quiz_attempts a
+    JOIN quizzes q ON q.quiz_id = a.quiz_id
+    WHERE q.course_id = $1 AND a.user_id = $2 AND a.status <> 'in_progress'
+    GROUP BY a.quiz_id
+    UNION ALL
+    SELECT s.score AS percentage
+    FROM scorm_attempts s
+    JOIN material m ON m.material_id = s.material_id
+    WHERE m.course_id = $1 AND s.user_id = $2 AND s.score IS NOT NULL
+) AS best
+`
+
+type GetCourseAssessmentMarkParams struct {
+	CourseID int64 `json:"course_id"`
+	UserID   int64 `json:"user_id"`
+}
+
+func (q *Queries) GetCourseAssessmentMark(ctx context.Context, arg GetCourseAssessmentMarkParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getCourseAssessmentMark, arg.CourseID, arg.UserID)
+	var marks int64
+	err := row.Scan(&marks)
+	return marks, err
+}
+
 const getMark = `-- name: GetMark :one
 SELECT mark_id, course_id, user_id, marks, created_at, updated_at FROM marks
 WHERE mark_id = $1
@@ -68,6 +96,32 @@ func (q *Queries) GetMark(ctx context.Context, markID int64) (Mark, error) {
 	return i, err
 }
 
+const getMarkByCourseAndUser = `-- name: GetMarkByCourseAndUser :one
+SELECT mark_id, course_id, user_id, marks, created_at, updated_at FROM marks
+WHERE course_id = $1 AND user_id = $2
+ORDER BY mark_id
+LIMIT 1
+`
+
+type GetMarkByCourseAndUserParams struct {
+	CourseID int64 `json:"course_id"`
+	UserID   int64 `json:"user_id"`
+}
+
+func (q *Queries) GetMarkByCourseAndUser(ctx context.Context, arg GetMarkByCourseAndUserParams) (Mark, error) {
+	row := q.db.QueryRow(ctx, getMarkByCourseAndUser, arg.CourseID, arg.UserID)
+	var i Mark
+	err := row.Scan(
+		&i.MarkID,
+		&i.CourseID,
+		&i.UserID,
+		&i.Marks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listMarks = `-- name: ListMarks :many
 SELECT mark_id, course_id, user_id, marks, created_at, updated_at FROM marks
 WHERE course_id = $1
@@ -111,7 +165,7 @@ func (q *Queries) ListMarks(ctx context.Context, arg ListMarksParams) ([]Mark, e
 
 const updateMark = `-- name: UpdateMark :one
 UPDATE marks
-SET marks = $2 , user_id = $3, course_id = $4
+SET marks = $2 , user_id = $3, course_id = $4, updated_at = now()
 WHERE mark_id = $1
 RETURNING mark_id, course_id, user_id, marks, created_at, updated_at
 `
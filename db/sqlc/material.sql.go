@@ -16,17 +16,27 @@ INSERT INTO material (
     course_id,
     title,
     material_file,
-    order_number
+    order_number,
+    material_type,
+    content,
+    content_html,
+    external_url,
+    duration_seconds
 ) VALUES (
-    $1, $2, $3, $4
-) RETURNING material_id, course_id, title, material_file, order_number, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING material_id, course_id, title, material_file, order_number, created_at, updated_at, material_type, content, content_html, external_url, duration_seconds
 `
 
 type CreateMaterialParams struct {
-	CourseID     int64  `json:"course_id"`
-	Title        string `json:"title"`
-	MaterialFile string `json:"material_file"`
-	OrderNumber  int64  `json:"order_number"`
+	CourseID        int64   `json:"course_id"`
+	Title           string  `json:"title"`
+	MaterialFile    string  `json:"material_file"`
+	OrderNumber     int64   `json:"order_number"`
+	MaterialType    string  `json:"material_type"`
+	Content         string  `json:"content"`
+	ContentHtml     string  `json:"content_html"`
+	ExternalUrl     string  `json:"external_url"`
+	DurationSeconds float64 `json:"duration_seconds"`
 }
 
 func (q *Queries) CreateMaterial(ctx context.Context, arg CreateMaterialParams) (Material, error) {
@@ -35,6 +45,11 @@ func (q *Queries) CreateMaterial(ctx context.Context, arg CreateMaterialParams)
 		arg.Title,
 		arg.MaterialFile,
 		arg.OrderNumber,
+		arg.MaterialType,
+		arg.Content,
+		arg.ContentHtml,
+		arg.ExternalUrl,
+		arg.DurationSeconds,
 	)
 	var i Material
 	err := row.Scan(
@@ -45,6 +60,11 @@ func (q *Queries) CreateMaterial(ctx context.Context, arg CreateMaterialParams)
 		&i.OrderNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaterialType,
+		&i.Content,
+		&i.ContentHtml,
+		&i.ExternalUrl,
+		&i.DurationSeconds,
 	)
 	return i, err
 }
@@ -61,7 +81,7 @@ func (q *Queries) DeleteMaterial(ctx context.Context, materialID int64) error {
 
 const getMaterial = `-- name: GetMaterial :one
 SELECT 
-    m.material_id, m.course_id, m.title, m.material_file, m.order_number, m.created_at, m.updated_at
+    m.material_id, m.course_id, m.title, m.material_file, m.order_number, m.created_at, m.updated_at, m.material_type, m.content, m.content_html, m.external_url, m.duration_seconds
 FROM 
     material m
 LEFT JOIN 
@@ -87,12 +107,43 @@ func (q *Queries) GetMaterial(ctx context.Context, arg GetMaterialParams) (Mater
 		&i.OrderNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaterialType,
+		&i.Content,
+		&i.ContentHtml,
+		&i.ExternalUrl,
+		&i.DurationSeconds,
+	)
+	return i, err
+}
+
+const getMaterialByID = `-- name: GetMaterialByID :one
+SELECT material_id, course_id, title, material_file, order_number, created_at, updated_at, material_type, content, content_html, external_url, duration_seconds FROM material
+WHERE material_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetMaterialByID(ctx context.Context, materialID int64) (Material, error) {
+	row := q.db.QueryRow(ctx, getMaterialByID, materialID)
+	var i Material
+	err := row.Scan(
+		&i.MaterialID,
+		&i.CourseID,
+		&i.Title,
+		&i.MaterialFile,
+		&i.OrderNumber,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaterialType,
+		&i.Content,
+		&i.ContentHtml,
+		&i.ExternalUrl,
+		&i.DurationSeconds,
 	)
 	return i, err
 }
 
 const getMaterialByOrderNumber = `-- name: GetMaterialByOrderNumber :one
-SELECT material_id, course_id, title, material_file, order_number, created_at, updated_at FROM material
+SELECT material_id, course_id, title, material_file, order_number, created_at, updated_at, material_type, content, content_html, external_url, duration_seconds FROM material
 WHERE 
     order_number = $1
     AND course_id = $2
@@ -115,6 +166,11 @@ func (q *Queries) GetMaterialByOrderNumber(ctx context.Context, arg GetMaterialB
 		&i.OrderNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaterialType,
+		&i.Content,
+		&i.ContentHtml,
+		&i.ExternalUrl,
+		&i.DurationSeconds,
 	)
 	return i, err
 }
@@ -138,7 +194,9 @@ SELECT
     course_id,
     title,
     material_file,
-    order_number
+    order_number,
+    material_type,
+    external_url
 FROM material
 WHERE course_id = $1
 ORDER BY material_id
@@ -150,6 +208,8 @@ type ListMaterialRow struct {
 	Title        string `json:"title"`
 	MaterialFile string `json:"material_file"`
 	OrderNumber  int64  `json:"order_number"`
+	MaterialType string `json:"material_type"`
+	ExternalUrl  string `json:"external_url"`
 }
 
 func (q *Queries) ListMaterial(ctx context.Context, courseID int64) ([]ListMaterialRow, error) {
@@ -166,6 +226,8 @@ func (q *Queries) ListMaterial(ctx context.Context, courseID int64) ([]ListMater
 			&i.Title,
 			&i.MaterialFile,
 			&i.OrderNumber,
+			&i.MaterialType,
+			&i.ExternalUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -178,7 +240,7 @@ func (q *Queries) ListMaterial(ctx context.Context, courseID int64) ([]ListMater
 }
 
 const listMaterialByCourse = `-- name: ListMaterialByCourse :many
-SELECT material_id, course_id, title, material_file, order_number, created_at, updated_at FROM material
+SELECT material_id, course_id, title, material_file, order_number, created_at, updated_at, material_type, content, content_html, external_url, duration_seconds FROM material
 WHERE 
     course_id = $1
 ORDER BY material_id
@@ -202,6 +264,11 @@ func (q *Queries) ListMaterialByCourse(ctx context.Context, courseID int64) ([]M
 			&i.OrderNumber,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MaterialType,
+			&i.Content,
+			&i.ContentHtml,
+			&i.ExternalUrl,
+			&i.DurationSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -217,20 +284,36 @@ const updateMaterial = `-- name: UpdateMaterial :one
 UPDATE material
 SET
     title = COALESCE($1,title),
-    material_file = COALESCE($2,material_file)
+    material_file = COALESCE($2,material_file),
+    content = COALESCE($3,content),
+    content_html = COALESCE($4,content_html),
+    external_url = COALESCE($5,external_url),
+    duration_seconds = COALESCE($6,duration_seconds)
 WHERE
-    material_id = $3
-RETURNING material_id, course_id, title, material_file, order_number, created_at, updated_at
+    material_id = $7
+RETURNING material_id, course_id, title, material_file, order_number, created_at, updated_at, material_type, content, content_html, external_url, duration_seconds
 `
 
 type UpdateMaterialParams struct {
-	Title        pgtype.Text `json:"title"`
-	MaterialFile pgtype.Text `json:"material_file"`
-	MaterialID   int64       `json:"material_id"`
+	Title           pgtype.Text   `json:"title"`
+	MaterialFile    pgtype.Text   `json:"material_file"`
+	Content         pgtype.Text   `json:"content"`
+	ContentHtml     pgtype.Text   `json:"content_html"`
+	ExternalUrl     pgtype.Text   `json:"external_url"`
+	DurationSeconds pgtype.Float8 `json:"duration_seconds"`
+	MaterialID      int64         `json:"material_id"`
 }
 
 func (q *Queries) UpdateMaterial(ctx context.Context, arg UpdateMaterialParams) (Material, error) {
-	row := q.db.QueryRow(ctx, updateMaterial, arg.Title, arg.MaterialFile, arg.MaterialID)
+	row := q.db.QueryRow(ctx, updateMaterial,
+		arg.Title,
+		arg.MaterialFile,
+		arg.Content,
+		arg.ContentHtml,
+		arg.ExternalUrl,
+		arg.DurationSeconds,
+		arg.MaterialID,
+	)
 	var i Material
 	err := row.Scan(
 		&i.MaterialID,
@@ -240,6 +323,11 @@ func (q *Queries) UpdateMaterial(ctx context.Context, arg UpdateMaterialParams)
 		&i.OrderNumber,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaterialType,
+		&i.Content,
+		&i.ContentHtml,
+		&i.ExternalUrl,
+		&i.DurationSeconds,
 	)
 	return i, err
 }
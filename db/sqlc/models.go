@@ -5,19 +5,40 @@
 package db
 
 import (
+	"eduApp/enrollment"
+	"eduApp/gradebook"
+	"eduApp/quiz"
+	"eduApp/scorm"
+	"eduApp/similarity"
+	"eduApp/typetext"
+	"eduApp/video"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Assignment struct {
-	AssignmentID   int64     `json:"assignment_id"`
-	CourseID       int64     `json:"course_id"`
-	Title          string    `json:"title"`
-	AssignmentFile string    `json:"assignment_file"`
-	DueDate        string    `json:"due_date"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	AssignmentID       int64              `json:"assignment_id"`
+	CourseID           int64              `json:"course_id"`
+	Title              string             `json:"title"`
+	AssignmentFile     string             `json:"assignment_file"`
+	DueDate            pgtype.Timestamptz `json:"due_date"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	LatePolicy         string             `json:"late_policy"`
+	LatePenaltyPercent int64              `json:"late_penalty_percent"`
+	LateCutoff         pgtype.Timestamptz `json:"late_cutoff"`
+	MaxAttempts        int64              `json:"max_attempts"`
+	GradesPublishedAt  pgtype.Timestamptz `json:"grades_published_at"`
+	GroupSubmission    bool               `json:"group_submission"`
+}
+
+type AssignmentExtension struct {
+	ExtensionID  int64     `json:"extension_id"`
+	AssignmentID int64     `json:"assignment_id"`
+	UserID       int64     `json:"user_id"`
+	DueDate      time.Time `json:"due_date"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Category struct {
@@ -27,28 +48,179 @@ type Category struct {
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
+type Certificate struct {
+	CertificateID  int64              `json:"certificate_id"`
+	Code           string             `json:"code"`
+	UserID         int64              `json:"user_id"`
+	CourseID       int64              `json:"course_id"`
+	StudentName    string             `json:"student_name"`
+	CourseTitle    string             `json:"course_title"`
+	InstructorName string             `json:"instructor_name"`
+	File           string             `json:"file"`
+	IssuedAt       time.Time          `json:"issued_at"`
+	EmailedAt      pgtype.Timestamptz `json:"emailed_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type Coupon struct {
+	CouponID  int64              `json:"coupon_id"`
+	Code      string             `json:"code"`
+	CourseID  pgtype.Int8        `json:"course_id"`
+	Kind      string             `json:"kind"`
+	Value     int64              `json:"value"`
+	Currency  string             `json:"currency"`
+	MaxUses   int64              `json:"max_uses"`
+	Uses      int64              `json:"uses"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedBy int64              `json:"created_by"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
 type Course struct {
-	CourseID         int64     `json:"course_id"`
-	UserID           int64     `json:"user_id"`
-	Title            string    `json:"title"`
-	Description      string    `json:"description"`
-	Image            string    `json:"image"`
-	Catagory         string    `json:"catagory"`
-	WhatWill         []byte    `json:"what_will"`
-	SequentialAccess bool      `json:"sequential_access"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	CourseID         int64                  `json:"course_id"`
+	UserID           int64                  `json:"user_id"`
+	Title            string                 `json:"title"`
+	Description      string                 `json:"description"`
+	Image            string                 `json:"image"`
+	Catagory         string                 `json:"catagory"`
+	WhatWill         []byte                 `json:"what_will"`
+	SequentialAccess bool                   `json:"sequential_access"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	ImageVariants    typetext.ImageVariants `json:"image_variants"`
+}
+
+type CourseEnrollmentSetting struct {
+	CourseID           int64              `json:"course_id"`
+	Mode               string             `json:"mode"`
+	Capacity           int64              `json:"capacity"`
+	EnrollmentStartsAt pgtype.Timestamptz `json:"enrollment_starts_at"`
+	EnrollmentEndsAt   pgtype.Timestamptz `json:"enrollment_ends_at"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	AccessDays         int64              `json:"access_days"`
+	AccessStartsAt     pgtype.Timestamptz `json:"access_starts_at"`
+}
+
+type CourseGroup struct {
+	GroupID   int64     `json:"group_id"`
+	CourseID  int64     `json:"course_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CourseGroupMember struct {
+	GroupID   int64     `json:"group_id"`
+	CourseID  int64     `json:"course_id"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CourseInvite struct {
+	InviteID  int64              `json:"invite_id"`
+	CourseID  int64              `json:"course_id"`
+	Code      string             `json:"code"`
+	MaxUses   int64              `json:"max_uses"`
+	Uses      int64              `json:"uses"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedBy int64              `json:"created_by"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type CoursePrice struct {
+	CourseID  int64     `json:"course_id"`
+	Currency  string    `json:"currency"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type CourseProgress struct {
-	CourseprogressID int64     `json:"courseprogress_id"`
+	CourseprogressID int64              `json:"courseprogress_id"`
+	CourseID         int64              `json:"course_id"`
+	UserID           int64              `json:"user_id"`
+	Progress         int64              `json:"progress"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	CompletedAt      pgtype.Timestamptz `json:"completed_at"`
+}
+
+type CourseProgressWeight struct {
 	CourseID         int64     `json:"course_id"`
-	UserID           int64     `json:"user_id"`
-	Progress         int64     `json:"progress"`
+	MaterialWeight   int64     `json:"material_weight"`
+	AssignmentWeight int64     `json:"assignment_weight"`
+	QuizWeight       int64     `json:"quiz_weight"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
+type Credential struct {
+	CredentialID     int64              `json:"credential_id"`
+	CredentialUri    string             `json:"credential_uri"`
+	UserID           int64              `json:"user_id"`
+	CourseID         int64              `json:"course_id"`
+	Document         []byte             `json:"document"`
+	IssuedAt         time.Time          `json:"issued_at"`
+	RevokedAt        pgtype.Timestamptz `json:"revoked_at"`
+	RevocationReason string             `json:"revocation_reason"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+type EnrollmentImport struct {
+	ImportID      int64                    `json:"import_id"`
+	CourseID      int64                    `json:"course_id"`
+	CreatedBy     int64                    `json:"created_by"`
+	Status        string                   `json:"status"`
+	TotalRows     int64                    `json:"total_rows"`
+	ProcessedRows int64                    `json:"processed_rows"`
+	Rows          enrollment.ImportRows    `json:"rows"`
+	Results       enrollment.ImportResults `json:"results"`
+	Error         string                   `json:"error"`
+	CreatedAt     time.Time                `json:"created_at"`
+	StartedAt     pgtype.Timestamptz       `json:"started_at"`
+	FinishedAt    pgtype.Timestamptz       `json:"finished_at"`
+}
+
+type GradeCategory struct {
+	CategoryID int64     `json:"category_id"`
+	CourseID   int64     `json:"course_id"`
+	Title      string    `json:"title"`
+	Weight     float64   `json:"weight"`
+	DropLowest int64     `json:"drop_lowest"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type GradeItem struct {
+	ItemID       int64       `json:"item_id"`
+	CourseID     int64       `json:"course_id"`
+	CategoryID   int64       `json:"category_id"`
+	Title        string      `json:"title"`
+	ItemType     string      `json:"item_type"`
+	AssignmentID pgtype.Int8 `json:"assignment_id"`
+	QuizID       pgtype.Int8 `json:"quiz_id"`
+	MaxPoints    float64     `json:"max_points"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type GradeItemScore struct {
+	ScoreID   int64     `json:"score_id"`
+	ItemID    int64     `json:"item_id"`
+	UserID    int64     `json:"user_id"`
+	Points    float64   `json:"points"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GradeScale struct {
+	CourseID  int64           `json:"course_id"`
+	Letters   gradebook.Scale `json:"letters"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
 type LessonCompletion struct {
 	CompletionID int64     `json:"completion_id"`
 	UserID       int64     `json:"user_id"`
@@ -58,6 +230,60 @@ type LessonCompletion struct {
 	CompletedAt  time.Time `json:"completed_at"`
 }
 
+type LtiDeepLink struct {
+	DeepLinkID   string    `json:"deep_link_id"`
+	PlatformID   int64     `json:"platform_id"`
+	UserID       int64     `json:"user_id"`
+	DeploymentID string    `json:"deployment_id"`
+	ReturnURL    string    `json:"return_url"`
+	Data         string    `json:"data"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type LtiLaunchState struct {
+	State      string    `json:"state"`
+	Nonce      string    `json:"nonce"`
+	PlatformID int64     `json:"platform_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type LtiPlatform struct {
+	PlatformID   int64     `json:"platform_id"`
+	Name         string    `json:"name"`
+	Issuer       string    `json:"issuer"`
+	ClientID     string    `json:"client_id"`
+	DeploymentID string    `json:"deployment_id"`
+	AuthLoginURL string    `json:"auth_login_url"`
+	AuthTokenURL string    `json:"auth_token_url"`
+	JwksURL      string    `json:"jwks_url"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type LtiPlatformCourse struct {
+	PlatformID int64     `json:"platform_id"`
+	CourseID   int64     `json:"course_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type LtiResourceLink struct {
+	LinkID         int64       `json:"link_id"`
+	PlatformID     int64       `json:"platform_id"`
+	ResourceLinkID string      `json:"resource_link_id"`
+	CourseID       int64       `json:"course_id"`
+	MaterialID     pgtype.Int8 `json:"material_id"`
+	LineitemURL    string      `json:"lineitem_url"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+type LtiUser struct {
+	PlatformID int64     `json:"platform_id"`
+	Subject    string    `json:"subject"`
+	UserID     int64     `json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Mark struct {
 	MarkID    int64     `json:"mark_id"`
 	CourseID  int64     `json:"course_id"`
@@ -68,21 +294,146 @@ type Mark struct {
 }
 
 type Material struct {
-	MaterialID   int64     `json:"material_id"`
-	CourseID     int64     `json:"course_id"`
-	Title        string    `json:"title"`
-	MaterialFile string    `json:"material_file"`
-	OrderNumber  int64     `json:"order_number"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	MaterialID      int64     `json:"material_id"`
+	CourseID        int64     `json:"course_id"`
+	Title           string    `json:"title"`
+	MaterialFile    string    `json:"material_file"`
+	OrderNumber     int64     `json:"order_number"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	MaterialType    string    `json:"material_type"`
+	Content         string    `json:"content"`
+	ContentHtml     string    `json:"content_html"`
+	ExternalUrl     string    `json:"external_url"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+type Notification struct {
+	NotificationID int64              `json:"notification_id"`
+	UserID         int64              `json:"user_id"`
+	Type           string             `json:"type"`
+	CourseID       pgtype.Int8        `json:"course_id"`
+	Title          string             `json:"title"`
+	Message        string             `json:"message"`
+	DedupKey       string             `json:"dedup_key"`
+	InApp          bool               `json:"in_app"`
+	ReadAt         pgtype.Timestamptz `json:"read_at"`
+	EmailedAt      pgtype.Timestamptz `json:"emailed_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserID    int64     `json:"user_id"`
+	Type      string    `json:"type"`
+	InApp     bool      `json:"in_app"`
+	Email     bool      `json:"email"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Order struct {
+	OrderID           int64              `json:"order_id"`
+	UserID            int64              `json:"user_id"`
+	CourseID          int64              `json:"course_id"`
+	CouponID          pgtype.Int8        `json:"coupon_id"`
+	Currency          string             `json:"currency"`
+	ListAmount        int64              `json:"list_amount"`
+	DiscountAmount    int64              `json:"discount_amount"`
+	Amount            int64              `json:"amount"`
+	Status            string             `json:"status"`
+	Provider          string             `json:"provider"`
+	CheckoutSessionID pgtype.Text        `json:"checkout_session_id"`
+	PaymentID         string             `json:"payment_id"`
+	PaidAt            pgtype.Timestamptz `json:"paid_at"`
+	RefundedAt        pgtype.Timestamptz `json:"refunded_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	RenewedFrom       pgtype.Timestamptz `json:"renewed_from"`
+	RenewedUntil      pgtype.Timestamptz `json:"renewed_until"`
+}
+
+type PeerReview struct {
+	ReviewID     int64                 `json:"review_id"`
+	AssignmentID int64                 `json:"assignment_id"`
+	SubmissionID int64                 `json:"submission_id"`
+	ReviewerID   int64                 `json:"reviewer_id"`
+	RubricScores typetext.RubricScores `json:"rubric_scores"`
+	Points       int64                 `json:"points"`
+	Comment      string                `json:"comment"`
+	SubmittedAt  pgtype.Timestamptz    `json:"submitted_at"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+type PeerReviewSetting struct {
+	AssignmentID         int64              `json:"assignment_id"`
+	ReviewsPerSubmission int64              `json:"reviews_per_submission"`
+	ReviewDueAt          time.Time          `json:"review_due_at"`
+	AssignedAt           pgtype.Timestamptz `json:"assigned_at"`
+	RemindedAt           pgtype.Timestamptz `json:"reminded_at"`
+	FinalizedAt          pgtype.Timestamptz `json:"finalized_at"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
 }
 
 type ProfilePicture struct {
-	ProfilePictureID int64     `json:"profile_picture_id"`
-	UserID           int64     `json:"user_id"`
-	Picture          string    `json:"picture"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ProfilePictureID int64                  `json:"profile_picture_id"`
+	UserID           int64                  `json:"user_id"`
+	Picture          string                 `json:"picture"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	PictureVariants  typetext.ImageVariants `json:"picture_variants"`
+}
+
+type Question struct {
+	QuestionID   int64          `json:"question_id"`
+	BankID       int64          `json:"bank_id"`
+	QuestionType string         `json:"question_type"`
+	Prompt       string         `json:"prompt"`
+	Options      quiz.Options   `json:"options"`
+	AnswerKey    quiz.AnswerKey `json:"answer_key"`
+	Points       int64          `json:"points"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type QuestionBank struct {
+	BankID    int64     `json:"bank_id"`
+	CourseID  int64     `json:"course_id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Quiz struct {
+	QuizID           int64       `json:"quiz_id"`
+	CourseID         int64       `json:"course_id"`
+	MaterialID       pgtype.Int8 `json:"material_id"`
+	Title            string      `json:"title"`
+	TimeLimitSeconds int64       `json:"time_limit_seconds"`
+	MaxAttempts      int64       `json:"max_attempts"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+type QuizAttempt struct {
+	AttemptID   int64              `json:"attempt_id"`
+	QuizID      int64              `json:"quiz_id"`
+	UserID      int64              `json:"user_id"`
+	QuestionIds []int64            `json:"question_ids"`
+	Responses   quiz.Responses     `json:"responses"`
+	Score       int64              `json:"score"`
+	MaxScore    int64              `json:"max_score"`
+	Status      string             `json:"status"`
+	StartedAt   time.Time          `json:"started_at"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	SubmittedAt pgtype.Timestamptz `json:"submitted_at"`
+}
+
+type QuizPool struct {
+	PoolID    int64     `json:"pool_id"`
+	QuizID    int64     `json:"quiz_id"`
+	BankID    int64     `json:"bank_id"`
+	DrawCount int64     `json:"draw_count"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Request struct {
@@ -94,6 +445,38 @@ type Request struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type Rubric struct {
+	RubricID     int64                   `json:"rubric_id"`
+	AssignmentID int64                   `json:"assignment_id"`
+	Title        string                  `json:"title"`
+	Criteria     typetext.RubricCriteria `json:"criteria"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+type ScormAttempt struct {
+	AttemptID    int64              `json:"attempt_id"`
+	UserID       int64              `json:"user_id"`
+	MaterialID   int64              `json:"material_id"`
+	Cmi          scorm.CMI          `json:"cmi"`
+	LessonStatus string             `json:"lesson_status"`
+	Score        pgtype.Float8      `json:"score"`
+	TotalSeconds float64            `json:"total_seconds"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type ScormPackage struct {
+	MaterialID int64     `json:"material_id"`
+	Version    string    `json:"version"`
+	Title      string    `json:"title"`
+	PackageDir string    `json:"package_dir"`
+	LaunchPath string    `json:"launch_path"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 type Session struct {
 	SessionID    pgtype.UUID `json:"session_id"`
 	UserID       int64       `json:"user_id"`
@@ -105,25 +488,80 @@ type Session struct {
 	CreatedAt    time.Time   `json:"created_at"`
 }
 
+type SimilarityReport struct {
+	ReportID         int64              `json:"report_id"`
+	AssignmentID     int64              `json:"assignment_id"`
+	AttemptID        int64              `json:"attempt_id"`
+	UserID           int64              `json:"user_id"`
+	MatchedAttemptID int64              `json:"matched_attempt_id"`
+	MatchedUserID    int64              `json:"matched_user_id"`
+	Score            float64            `json:"score"`
+	Matches          similarity.Matches `json:"matches"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
 type Submission struct {
-	SubmissionID     int64     `json:"submission_id"`
-	AssignmentID     int64     `json:"assignment_id"`
-	UserID           int64     `json:"user_id"`
-	Submitted        bool      `json:"submitted"`
-	Grade            string    `json:"grade"`
-	Resource         string    `json:"resource"`
-	DateOfSubmission time.Time `json:"date_of_submission"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	SubmissionID     int64                 `json:"submission_id"`
+	AssignmentID     int64                 `json:"assignment_id"`
+	UserID           int64                 `json:"user_id"`
+	Submitted        bool                  `json:"submitted"`
+	Grade            string                `json:"grade"`
+	Resource         string                `json:"resource"`
+	DateOfSubmission time.Time             `json:"date_of_submission"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+	IsLate           bool                  `json:"is_late"`
+	PenaltyPercent   int64                 `json:"penalty_percent"`
+	GradedAttemptID  pgtype.Int8           `json:"graded_attempt_id"`
+	RubricScores     typetext.RubricScores `json:"rubric_scores"`
+	Feedback         string                `json:"feedback"`
+	GradedAt         pgtype.Timestamptz    `json:"graded_at"`
+	GroupID          pgtype.Int8           `json:"group_id"`
+}
+
+type SubmissionAdjustment struct {
+	AdjustmentID int64     `json:"adjustment_id"`
+	SubmissionID int64     `json:"submission_id"`
+	UserID       int64     `json:"user_id"`
+	Points       float64   `json:"points"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type SubmissionAttempt struct {
+	AttemptID      int64     `json:"attempt_id"`
+	SubmissionID   int64     `json:"submission_id"`
+	AttemptNumber  int64     `json:"attempt_number"`
+	Resource       string    `json:"resource"`
+	Checksum       string    `json:"checksum"`
+	Size           int64     `json:"size"`
+	IsLate         bool      `json:"is_late"`
+	PenaltyPercent int64     `json:"penalty_percent"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+}
+
+type SubmissionText struct {
+	AttemptID    int64     `json:"attempt_id"`
+	AssignmentID int64     `json:"assignment_id"`
+	UserID       int64     `json:"user_id"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Subscription struct {
-	SubscriptionID int64     `json:"subscription_id"`
-	UserID         int64     `json:"user_id"`
-	CourseID       int64     `json:"course_id"`
-	Active         bool      `json:"active"`
-	Pending        bool      `json:"pending"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	SubscriptionID   int64              `json:"subscription_id"`
+	UserID           int64              `json:"user_id"`
+	CourseID         int64              `json:"course_id"`
+	Active           bool               `json:"active"`
+	Pending          bool               `json:"pending"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	WaitlistedAt     pgtype.Timestamptz `json:"waitlisted_at"`
+	DeniedAt         pgtype.Timestamptz `json:"denied_at"`
+	StartsAt         pgtype.Timestamptz `json:"starts_at"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	ExpiryRemindedAt pgtype.Timestamptz `json:"expiry_reminded_at"`
+	ExpiredAt        pgtype.Timestamptz `json:"expired_at"`
 }
 
 type User struct {
@@ -138,6 +576,7 @@ type User struct {
 	Role              string    `json:"role"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	Locale            string    `json:"locale"`
 }
 
 type UserStatus struct {
@@ -157,3 +596,29 @@ type VerifyEmail struct {
 	CreatedAt  time.Time `json:"created_at"`
 	ExpiredAt  time.Time `json:"expired_at"`
 }
+
+type VideoWatch struct {
+	WatchID         int64              `json:"watch_id"`
+	UserID          int64              `json:"user_id"`
+	MaterialID      int64              `json:"material_id"`
+	Segments        video.Segments     `json:"segments"`
+	WatchedSeconds  float64            `json:"watched_seconds"`
+	DurationSeconds float64            `json:"duration_seconds"`
+	PositionSeconds float64            `json:"position_seconds"`
+	FurthestSeconds float64            `json:"furthest_seconds"`
+	CompletedAt     pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	StartedAt       pgtype.Timestamptz `json:"started_at"`
+	LastHeartbeatAt pgtype.Timestamptz `json:"last_heartbeat_at"`
+}
+
+type XapiStatement struct {
+	StatementID pgtype.UUID `json:"statement_id"`
+	Actor       string      `json:"actor"`
+	VerbID      string      `json:"verb_id"`
+	ActivityID  string      `json:"activity_id"`
+	Statement   []byte      `json:"statement"`
+	Voided      bool        `json:"voided"`
+	Stored      time.Time   `json:"stored"`
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"eduApp/typetext"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -14,19 +15,21 @@ import (
 const createProfilePicture = `-- name: CreateProfilePicture :one
 INSERT INTO profile_pictures(
     user_id,
-    picture
+    picture,
+    picture_variants
 ) VALUES (
-    $1, $2
-) RETURNING profile_picture_id, user_id, picture, created_at, updated_at
+    $1, $2, $3
+) RETURNING profile_picture_id, user_id, picture, created_at, updated_at, picture_variants
 `
 
 type CreateProfilePictureParams struct {
-	UserID  int64  `json:"user_id"`
-	Picture string `json:"picture"`
+	UserID          int64                  `json:"user_id"`
+	Picture         string                 `json:"picture"`
+	PictureVariants typetext.ImageVariants `json:"picture_variants"`
 }
 
 func (q *Queries) CreateProfilePicture(ctx context.Context, arg CreateProfilePictureParams) (ProfilePicture, error) {
-	row := q.db.QueryRow(ctx, createProfilePicture, arg.UserID, arg.Picture)
+	row := q.db.QueryRow(ctx, createProfilePicture, arg.UserID, arg.Picture, arg.PictureVariants)
 	var i ProfilePicture
 	err := row.Scan(
 		&i.ProfilePictureID,
@@ -34,6 +37,7 @@ func (q *Queries) CreateProfilePicture(ctx context.Context, arg CreateProfilePic
 		&i.Picture,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PictureVariants,
 	)
 	return i, err
 }
@@ -49,7 +53,7 @@ func (q *Queries) DeleteProfilePicture(ctx context.Context, userID int64) error
 }
 
 const getProfilePicture = `-- name: GetProfilePicture :one
-SELECT profile_picture_id, user_id, picture, created_at, updated_at FROM profile_pictures
+SELECT profile_picture_id, user_id, picture, created_at, updated_at, picture_variants FROM profile_pictures
 WHERE user_id = $1
 `
 
@@ -62,6 +66,7 @@ func (q *Queries) GetProfilePicture(ctx context.Context, userID int64) (ProfileP
 		&i.Picture,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PictureVariants,
 	)
 	return i, err
 }
@@ -69,19 +74,21 @@ func (q *Queries) GetProfilePicture(ctx context.Context, userID int64) (ProfileP
 const updateProfilePicture = `-- name: UpdateProfilePicture :one
 UPDATE profile_pictures
 SET
-    picture = COALESCE($1,picture)
+    picture = COALESCE($1,picture),
+    picture_variants = COALESCE($2,picture_variants)
 WHERE
-    user_id = $2
-RETURNING profile_picture_id, user_id, picture, created_at, updated_at
+    user_id = $3
+RETURNING profile_picture_id, user_id, picture, created_at, updated_at, picture_variants
 `
 
 type UpdateProfilePictureParams struct {
-	Picture pgtype.Text `json:"picture"`
-	UserID  int64       `json:"user_id"`
+	Picture         pgtype.Text             `json:"picture"`
+	PictureVariants *typetext.ImageVariants `json:"picture_variants"`
+	UserID          int64                   `json:"user_id"`
 }
 
 func (q *Queries) UpdateProfilePicture(ctx context.Context, arg UpdateProfilePictureParams) (ProfilePicture, error) {
-	row := q.db.QueryRow(ctx, updateProfilePicture, arg.Picture, arg.UserID)
+	row := q.db.QueryRow(ctx, updateProfilePicture, arg.Picture, arg.PictureVariants, arg.UserID)
 	var i ProfilePicture
 	err := row.Scan(
 		&i.ProfilePictureID,
@@ -89,6 +96,7 @@ func (q *Queries) UpdateProfilePicture(ctx context.Context, arg UpdateProfilePic
 		&i.Picture,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PictureVariants,
 	)
 	return i, err
 }
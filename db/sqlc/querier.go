@@ -6,56 +6,163 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	ActivateSubscription(ctx context.Context, subscriptionID int64) (Subscription, error)
+	AddCourseGroupMember(ctx context.Context, arg AddCourseGroupMemberParams) (CourseGroupMember, error)
+	AddEnrollmentImportResult(ctx context.Context, arg AddEnrollmentImportResultParams) error
 	CheckEmail(ctx context.Context, email string) (string, error)
+	CloseOrder(ctx context.Context, arg CloseOrderParams) (Order, error)
+	ConsumeLTIDeepLink(ctx context.Context, arg ConsumeLTIDeepLinkParams) (LtiDeepLink, error)
+	ConsumeLTILaunchState(ctx context.Context, state string) (LtiLaunchState, error)
+	CountActiveSubscriptions(ctx context.Context, courseID int64) (int64, error)
+	CountQuizAttempts(ctx context.Context, arg CountQuizAttemptsParams) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID int64) (int64, error)
+	CountWaitlistedSubscriptions(ctx context.Context, courseID int64) (int64, error)
 	CreateAssignment(ctx context.Context, arg CreateAssignmentParams) (Assignment, error)
+	CreateAssignmentExtension(ctx context.Context, arg CreateAssignmentExtensionParams) (AssignmentExtension, error)
 	CreateCategory(ctx context.Context, category string) (Category, error)
+	CreateCertificate(ctx context.Context, arg CreateCertificateParams) (Certificate, error)
+	CreateCoupon(ctx context.Context, arg CreateCouponParams) (Coupon, error)
+	CreateCourseGroup(ctx context.Context, arg CreateCourseGroupParams) (CourseGroup, error)
+	CreateCourseInvite(ctx context.Context, arg CreateCourseInviteParams) (CourseInvite, error)
 	CreateCourseProgress(ctx context.Context, arg CreateCourseProgressParams) (CourseProgress, error)
 	CreateCourses(ctx context.Context, arg CreateCoursesParams) (Course, error)
+	CreateCredential(ctx context.Context, arg CreateCredentialParams) (Credential, error)
+	CreateEnrollmentImport(ctx context.Context, arg CreateEnrollmentImportParams) (EnrollmentImport, error)
+	CreateGradeCategory(ctx context.Context, arg CreateGradeCategoryParams) (GradeCategory, error)
+	CreateGradeItem(ctx context.Context, arg CreateGradeItemParams) (GradeItem, error)
+	CreateLTIDeepLink(ctx context.Context, arg CreateLTIDeepLinkParams) (LtiDeepLink, error)
+	CreateLTILaunchState(ctx context.Context, arg CreateLTILaunchStateParams) error
+	CreateLTIPlatform(ctx context.Context, arg CreateLTIPlatformParams) (LtiPlatform, error)
+	CreateLTIPlatformCourse(ctx context.Context, arg CreateLTIPlatformCourseParams) error
+	CreateLTIUser(ctx context.Context, arg CreateLTIUserParams) (LtiUser, error)
 	CreateLessonCompletion(ctx context.Context, arg CreateLessonCompletionParams) (LessonCompletion, error)
 	CreateMark(ctx context.Context, arg CreateMarkParams) (Mark, error)
 	CreateMaterial(ctx context.Context, arg CreateMaterialParams) (Material, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreatePeerReview(ctx context.Context, arg CreatePeerReviewParams) (PeerReview, error)
 	CreateProfilePicture(ctx context.Context, arg CreateProfilePictureParams) (ProfilePicture, error)
+	CreateQuestion(ctx context.Context, arg CreateQuestionParams) (Question, error)
+	CreateQuestionBank(ctx context.Context, arg CreateQuestionBankParams) (QuestionBank, error)
+	CreateQuiz(ctx context.Context, arg CreateQuizParams) (Quiz, error)
+	CreateQuizAttempt(ctx context.Context, arg CreateQuizAttemptParams) (QuizAttempt, error)
+	CreateQuizPool(ctx context.Context, arg CreateQuizPoolParams) (QuizPool, error)
 	CreateRequest(ctx context.Context, arg CreateRequestParams) (Request, error)
+	CreateSCORMAttempt(ctx context.Context, arg CreateSCORMAttemptParams) error
+	CreateSCORMPackage(ctx context.Context, arg CreateSCORMPackageParams) (ScormPackage, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (Submission, error)
+	CreateSubmissionAttempt(ctx context.Context, arg CreateSubmissionAttemptParams) (SubmissionAttempt, error)
+	CreateSubmissionIfMissing(ctx context.Context, arg CreateSubmissionIfMissingParams) error
 	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserStatus(ctx context.Context, arg CreateUserStatusParams) (UserStatus, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	CreateVideoWatch(ctx context.Context, arg CreateVideoWatchParams) error
+	CreateXAPIStatement(ctx context.Context, arg CreateXAPIStatementParams) error
+	DeactivateSubscription(ctx context.Context, subscriptionID int64) (Subscription, error)
 	DeleteAssignment(ctx context.Context, assignmentID int64) error
+	DeleteAssignmentExtension(ctx context.Context, arg DeleteAssignmentExtensionParams) error
 	DeleteCategory(ctx context.Context, categoryID int64) error
+	DeleteCoupon(ctx context.Context, couponID int64) error
+	DeleteCourseGroup(ctx context.Context, groupID int64) error
+	DeleteCoursePrice(ctx context.Context, courseID int64) error
 	DeleteCourseProgress(ctx context.Context, courseprogressID int64) error
+	DeleteCourseSubscription(ctx context.Context, arg DeleteCourseSubscriptionParams) (Subscription, error)
 	DeleteCourses(ctx context.Context, courseID int64) error
+	DeleteExpiredLTILaunchStates(ctx context.Context) error
+	DeleteGradeCategory(ctx context.Context, categoryID int64) error
+	DeleteGradeItem(ctx context.Context, itemID int64) error
+	DeleteLTIPlatform(ctx context.Context, platformID int64) error
+	DeleteLTIPlatformCourse(ctx context.Context, arg DeleteLTIPlatformCourseParams) error
 	DeleteLessonCompletion(ctx context.Context, completionID int64) error
 	DeleteMark(ctx context.Context, markID int64) error
 	DeleteMaterial(ctx context.Context, materialID int64) error
+	DeletePeerReviewSettings(ctx context.Context, assignmentID int64) error
 	DeleteProfilePicture(ctx context.Context, userID int64) error
-	DeleteSubmission(ctx context.Context, arg DeleteSubmissionParams) error
+	DeleteQuestion(ctx context.Context, questionID int64) error
+	DeleteQuestionBank(ctx context.Context, bankID int64) error
+	DeleteQuiz(ctx context.Context, quizID int64) error
+	DeleteRubric(ctx context.Context, assignmentID int64) error
+	DeleteSubmission(ctx context.Context, submissionID int64) (int64, error)
+	DeleteSubmissionAdjustment(ctx context.Context, arg DeleteSubmissionAdjustmentParams) error
 	DeleteUserStatus(ctx context.Context, statusID int64) error
 	DeleteUsers(ctx context.Context, userID int64) error
+	DenySubscription(ctx context.Context, subscriptionID int64) (Subscription, error)
+	ExpireSubscriptions(ctx context.Context) ([]Subscription, error)
+	ExtendSubscriptions(ctx context.Context, arg ExtendSubscriptionsParams) ([]Subscription, error)
+	FinishEnrollmentImport(ctx context.Context, arg FinishEnrollmentImportParams) error
 	GetAssignment(ctx context.Context, assignmentID int64) (Assignment, error)
+	GetAssignmentExtension(ctx context.Context, arg GetAssignmentExtensionParams) (AssignmentExtension, error)
 	GetCategory(ctx context.Context, categoryID int64) (Category, error)
+	GetCertificateByCode(ctx context.Context, code string) (Certificate, error)
 	GetCompletedLessonsCount(ctx context.Context, arg GetCompletedLessonsCountParams) (int64, error)
+	GetCouponByCode(ctx context.Context, code string) (Coupon, error)
+	GetCourseAssessmentMark(ctx context.Context, arg GetCourseAssessmentMarkParams) (int64, error)
 	GetCourseByUserID(ctx context.Context, userID int64) (Course, error)
+	GetCourseCertificate(ctx context.Context, arg GetCourseCertificateParams) (Certificate, error)
 	GetCourseCompletedUserCount(ctx context.Context, progress int64) (int64, error)
+	GetCourseCredential(ctx context.Context, arg GetCourseCredentialParams) (Credential, error)
+	GetCourseEnrollmentSettings(ctx context.Context, courseID int64) (CourseEnrollmentSetting, error)
+	GetCourseEnrollmentSettingsForUpdate(ctx context.Context, courseID int64) (CourseEnrollmentSetting, error)
+	GetCourseGroup(ctx context.Context, groupID int64) (CourseGroup, error)
+	GetCourseGroupByMember(ctx context.Context, arg GetCourseGroupByMemberParams) (CourseGroup, error)
+	GetCourseInviteByCodeForUpdate(ctx context.Context, code string) (CourseInvite, error)
+	GetCoursePrice(ctx context.Context, courseID int64) (CoursePrice, error)
 	GetCourseProgress(ctx context.Context, arg GetCourseProgressParams) (CourseProgress, error)
+	GetCourseProgressCounts(ctx context.Context, arg GetCourseProgressCountsParams) (GetCourseProgressCountsRow, error)
+	GetCourseProgressWeights(ctx context.Context, courseID int64) (CourseProgressWeight, error)
+	GetCourseSubscription(ctx context.Context, arg GetCourseSubscriptionParams) (Subscription, error)
+	GetCourseSubscriptionForUpdate(ctx context.Context, arg GetCourseSubscriptionForUpdateParams) (Subscription, error)
 	GetCourses(ctx context.Context, courseID int64) (Course, error)
+	GetCredentialByURI(ctx context.Context, credentialUri string) (Credential, error)
+	GetEnrollmentImport(ctx context.Context, importID int64) (EnrollmentImport, error)
 	GetEntireCourse(ctx context.Context, courseID int64) (GetEntireCourseRow, error)
+	GetGradeCategory(ctx context.Context, categoryID int64) (GradeCategory, error)
+	GetGradeItem(ctx context.Context, itemID int64) (GradeItem, error)
+	GetGradeScale(ctx context.Context, courseID int64) (GradeScale, error)
+	GetGroupSubmissionForUpdate(ctx context.Context, arg GetGroupSubmissionForUpdateParams) (Submission, error)
 	GetInProgressCourseCount(ctx context.Context) (int64, error)
+	GetInProgressQuizAttempt(ctx context.Context, arg GetInProgressQuizAttemptParams) (QuizAttempt, error)
+	GetLTIPlatform(ctx context.Context, platformID int64) (LtiPlatform, error)
+	GetLTIUser(ctx context.Context, arg GetLTIUserParams) (LtiUser, error)
+	GetLatestSubmissionAttemptNumber(ctx context.Context, submissionID int64) (int64, error)
 	GetLessonCompletion(ctx context.Context, arg GetLessonCompletionParams) (LessonCompletion, error)
 	GetMark(ctx context.Context, markID int64) (Mark, error)
+	GetMarkByCourseAndUser(ctx context.Context, arg GetMarkByCourseAndUserParams) (Mark, error)
 	GetMaterial(ctx context.Context, arg GetMaterialParams) (Material, error)
+	GetMaterialByID(ctx context.Context, materialID int64) (Material, error)
 	GetMaterialByOrderNumber(ctx context.Context, arg GetMaterialByOrderNumberParams) (Material, error)
+	GetNextWaitlistedSubscription(ctx context.Context, courseID int64) (Subscription, error)
+	GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (NotificationPreference, error)
+	GetOrder(ctx context.Context, orderID int64) (Order, error)
+	GetOrderByPaymentID(ctx context.Context, paymentID string) (Order, error)
+	GetOrderForUpdate(ctx context.Context, orderID int64) (Order, error)
+	GetPeerReview(ctx context.Context, reviewID int64) (PeerReview, error)
+	GetPeerReviewSettings(ctx context.Context, assignmentID int64) (PeerReviewSetting, error)
+	GetPeerReviewSettingsForUpdate(ctx context.Context, assignmentID int64) (PeerReviewSetting, error)
 	GetProfilePicture(ctx context.Context, userID int64) (ProfilePicture, error)
+	GetQuestionBank(ctx context.Context, bankID int64) (QuestionBank, error)
+	GetQuiz(ctx context.Context, quizID int64) (Quiz, error)
+	GetQuizAttempt(ctx context.Context, attemptID int64) (QuizAttempt, error)
 	GetRequest(ctx context.Context, requestID int64) (Request, error)
+	GetRubricByAssignment(ctx context.Context, assignmentID int64) (Rubric, error)
+	GetSCORMAttempt(ctx context.Context, arg GetSCORMAttemptParams) (ScormAttempt, error)
+	GetSCORMAttemptForUpdate(ctx context.Context, arg GetSCORMAttemptForUpdateParams) (ScormAttempt, error)
+	GetSCORMPackage(ctx context.Context, materialID int64) (ScormPackage, error)
 	GetSession(ctx context.Context, sessionID pgtype.UUID) (Session, error)
 	GetStudentCountInCourse(ctx context.Context) ([]int64, error)
 	GetSubmission(ctx context.Context, arg GetSubmissionParams) (Submission, error)
+	GetSubmissionAttempt(ctx context.Context, arg GetSubmissionAttemptParams) (SubmissionAttempt, error)
+	GetSubmissionAttemptByID(ctx context.Context, attemptID int64) (SubmissionAttempt, error)
+	GetSubmissionByID(ctx context.Context, submissionID int64) (Submission, error)
+	GetSubmissionForUpdate(ctx context.Context, arg GetSubmissionForUpdateParams) (Submission, error)
 	GetSubscription(ctx context.Context, userID int64) (Subscription, error)
 	GetSubscriptionByUser(ctx context.Context, arg GetSubscriptionByUserParams) (GetSubscriptionByUserRow, error)
 	GetTotalCourseCount(ctx context.Context) (int64, error)
@@ -65,41 +172,147 @@ type Querier interface {
 	GetTotalUserCompletedCourseCount(ctx context.Context, arg GetTotalUserCompletedCourseCountParams) (int64, error)
 	GetTotalUserCount(ctx context.Context, role string) (int64, error)
 	GetUser(ctx context.Context, userName string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, userID int64) (User, error)
 	GetUserCountForCertianCourse(ctx context.Context, arg GetUserCountForCertianCourseParams) (int64, error)
 	GetUserStatus(ctx context.Context, userID int64) (UserStatus, error)
+	GetVideoWatch(ctx context.Context, arg GetVideoWatchParams) (VideoWatch, error)
+	GetVideoWatchForUpdate(ctx context.Context, arg GetVideoWatchForUpdateParams) (VideoWatch, error)
+	GetXAPIStatement(ctx context.Context, statementID pgtype.UUID) (XapiStatement, error)
 	GetsubmissionsByAssignment(ctx context.Context, assignmentID int64) (Submission, error)
 	GetsubmissionsByUser(ctx context.Context, userID int64) (Submission, error)
+	GradeSubmission(ctx context.Context, arg GradeSubmissionParams) (Submission, error)
+	HasLTIPlatformCourse(ctx context.Context, arg HasLTIPlatformCourseParams) (bool, error)
 	ListAllCategories(ctx context.Context, arg ListAllCategoriesParams) ([]Category, error)
 	ListAllCourseByCatagory(ctx context.Context, catagory string) ([]ListAllCourseByCatagoryRow, error)
 	ListAllCourseCatagories(ctx context.Context) ([]string, error)
+	ListAssignmentExtensions(ctx context.Context, assignmentID int64) ([]AssignmentExtension, error)
+	ListAssignmentsByCourse(ctx context.Context, courseID int64) ([]Assignment, error)
+	ListCertificatesByUser(ctx context.Context, arg ListCertificatesByUserParams) ([]Certificate, error)
+	ListCoupons(ctx context.Context) ([]Coupon, error)
+	ListCourseGroupMembers(ctx context.Context, courseID int64) ([]ListCourseGroupMembersRow, error)
+	ListCourseGroups(ctx context.Context, courseID int64) ([]CourseGroup, error)
+	ListCourseInvites(ctx context.Context, courseID int64) ([]CourseInvite, error)
+	ListCourseLearners(ctx context.Context, courseID int64) ([]int64, error)
 	ListCourseProgressByUser(ctx context.Context, arg ListCourseProgressByUserParams) ([]CourseProgress, error)
+	ListCourseStudentIDs(ctx context.Context, courseID int64) ([]int64, error)
 	ListCourses(ctx context.Context, arg ListCoursesParams) ([]Course, error)
+	ListCredentialsByUser(ctx context.Context, arg ListCredentialsByUserParams) ([]Credential, error)
+	ListDueAssignmentReminders(ctx context.Context, dueBefore time.Time) ([]ListDueAssignmentRemindersRow, error)
+	ListEnrollmentImports(ctx context.Context, courseID int64) ([]ListEnrollmentImportsRow, error)
+	ListGradeCategories(ctx context.Context, courseID int64) ([]GradeCategory, error)
+	ListGradeItems(ctx context.Context, courseID int64) ([]GradeItem, error)
+	ListGradebookScores(ctx context.Context, courseID int64) ([]ListGradebookScoresRow, error)
+	ListGradebookStudents(ctx context.Context, courseID int64) ([]ListGradebookStudentsRow, error)
+	ListGradedSubmissionUserIDs(ctx context.Context, assignmentID int64) ([]int64, error)
+	ListLTIPlatforms(ctx context.Context) ([]LtiPlatform, error)
+	ListLTIPlatformsByIssuer(ctx context.Context, issuer string) ([]LtiPlatform, error)
+	ListLTIScoreTargets(ctx context.Context, arg ListLTIScoreTargetsParams) ([]ListLTIScoreTargetsRow, error)
+	ListLatestSubmissionTexts(ctx context.Context, arg ListLatestSubmissionTextsParams) ([]SubmissionText, error)
 	ListMarks(ctx context.Context, arg ListMarksParams) ([]Mark, error)
 	ListMaterial(ctx context.Context, courseID int64) ([]ListMaterialRow, error)
 	ListMaterialByCourse(ctx context.Context, courseID int64) ([]Material, error)
+	ListNotificationPreferences(ctx context.Context, userID int64) ([]NotificationPreference, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOrdersByCourse(ctx context.Context, courseID int64) ([]Order, error)
+	ListOrdersByUser(ctx context.Context, userID int64) ([]Order, error)
+	ListPeerReviewSettingsToAssign(ctx context.Context) ([]PeerReviewSetting, error)
+	ListPeerReviewSettingsToFinalize(ctx context.Context) ([]PeerReviewSetting, error)
+	ListPeerReviewSettingsToRemind(ctx context.Context, reviewDueAt time.Time) ([]PeerReviewSetting, error)
+	ListPeerReviewsByAssignment(ctx context.Context, assignmentID int64) ([]PeerReview, error)
+	ListPeerReviewsByReviewer(ctx context.Context, arg ListPeerReviewsByReviewerParams) ([]ListPeerReviewsByReviewerRow, error)
+	ListPeerReviewsBySubmission(ctx context.Context, submissionID int64) ([]PeerReview, error)
+	ListPendingPeerReviewers(ctx context.Context, assignmentID int64) ([]ListPendingPeerReviewersRow, error)
+	ListPendingSubscriptions(ctx context.Context, courseID int64) ([]ListPendingSubscriptionsRow, error)
+	ListQuestionBanks(ctx context.Context, courseID int64) ([]QuestionBank, error)
+	ListQuestionIDsByBank(ctx context.Context, bankID int64) ([]int64, error)
+	ListQuestionsByBank(ctx context.Context, bankID int64) ([]Question, error)
+	ListQuestionsByIDs(ctx context.Context, questionIds []int64) ([]Question, error)
+	ListQuizAttemptsByUser(ctx context.Context, arg ListQuizAttemptsByUserParams) ([]QuizAttempt, error)
+	ListQuizPools(ctx context.Context, quizID int64) ([]QuizPool, error)
+	ListQuizzesByCourse(ctx context.Context, courseID int64) ([]Quiz, error)
+	ListReferencedDirs(ctx context.Context) ([]string, error)
+	ListReferencedFiles(ctx context.Context) ([]string, error)
+	ListReferencedImageVariants(ctx context.Context) ([]string, error)
+	ListRevokedCredentials(ctx context.Context) ([]ListRevokedCredentialsRow, error)
+	ListSimilarityReports(ctx context.Context, assignmentID int64) ([]SimilarityReport, error)
+	ListSimilarityReportsByUser(ctx context.Context, arg ListSimilarityReportsByUserParams) ([]SimilarityReport, error)
+	ListSubmissionAdjustments(ctx context.Context, submissionID int64) ([]SubmissionAdjustment, error)
+	ListSubmissionAttempts(ctx context.Context, submissionID int64) ([]SubmissionAttempt, error)
+	ListSubmittedSubmissionsByAssignment(ctx context.Context, assignmentID int64) ([]Submission, error)
 	ListSubscriptionsByCourse(ctx context.Context, arg ListSubscriptionsByCourseParams) ([]Subscription, error)
 	ListSubscriptionsByUser(ctx context.Context, arg ListSubscriptionsByUserParams) ([]Subscription, error)
+	ListSubscriptionsToRemind(ctx context.Context, remindBefore pgtype.Timestamptz) ([]ListSubscriptionsToRemindRow, error)
+	ListUngroupedStudents(ctx context.Context, courseID int64) ([]int64, error)
 	ListUser(ctx context.Context, arg ListUserParams) ([]User, error)
 	ListUserStatus(ctx context.Context, arg ListUserStatusParams) ([]UserStatus, error)
+	ListVideoDropOff(ctx context.Context, courseID int64) ([]ListVideoDropOffRow, error)
+	ListWaitlistedSubscriptions(ctx context.Context, courseID int64) ([]ListWaitlistedSubscriptionsRow, error)
+	ListXAPIStatements(ctx context.Context, arg ListXAPIStatementsParams) ([]XapiStatement, error)
 	Listsubmissions(ctx context.Context, arg ListsubmissionsParams) ([]Submission, error)
+	MarkAllNotificationsRead(ctx context.Context, userID int64) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	MarkOrderPaid(ctx context.Context, arg MarkOrderPaidParams) (Order, error)
+	MarkOrderRefunded(ctx context.Context, orderID int64) (Order, error)
+	RemoveCourseGroupMember(ctx context.Context, arg RemoveCourseGroupMemberParams) error
+	RenewSubscription(ctx context.Context, subscriptionID int64) (Subscription, error)
+	RevertSubscriptionRenewal(ctx context.Context, arg RevertSubscriptionRenewalParams) (Subscription, error)
+	RevokeCourseInvite(ctx context.Context, inviteID int64) (CourseInvite, error)
+	RevokeCredential(ctx context.Context, arg RevokeCredentialParams) (Credential, error)
+	SetAssignmentGradesPublished(ctx context.Context, arg SetAssignmentGradesPublishedParams) (Assignment, error)
+	SetCertificateEmailed(ctx context.Context, certificateID int64) (Certificate, error)
+	SetNotificationEmailed(ctx context.Context, notificationID int64) error
+	SetOrderCheckoutSession(ctx context.Context, arg SetOrderCheckoutSessionParams) (Order, error)
+	SetOrderRenewal(ctx context.Context, arg SetOrderRenewalParams) (Order, error)
+	SetPeerReviewsAssigned(ctx context.Context, assignmentID int64) error
+	SetPeerReviewsFinalized(ctx context.Context, assignmentID int64) error
+	SetPeerReviewsReminded(ctx context.Context, assignmentID int64) error
+	SetSubscriptionExpiryReminded(ctx context.Context, subscriptionID int64) error
+	StartEnrollmentImport(ctx context.Context, importID int64) (EnrollmentImport, error)
 	StudentCount(ctx context.Context, role string) (int64, error)
+	SubmitPeerReview(ctx context.Context, arg SubmitPeerReviewParams) (PeerReview, error)
+	SubmitQuizAttempt(ctx context.Context, arg SubmitQuizAttemptParams) (QuizAttempt, error)
 	UpdateAssignment(ctx context.Context, arg UpdateAssignmentParams) (Assignment, error)
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
+	UpdateCourseGroup(ctx context.Context, arg UpdateCourseGroupParams) (CourseGroup, error)
 	UpdateCourseProgress(ctx context.Context, arg UpdateCourseProgressParams) (CourseProgress, error)
 	UpdateCourses(ctx context.Context, arg UpdateCoursesParams) (Course, error)
+	UpdateGradeCategory(ctx context.Context, arg UpdateGradeCategoryParams) (GradeCategory, error)
+	UpdateGradeItem(ctx context.Context, arg UpdateGradeItemParams) (GradeItem, error)
+	UpdateLTIPlatform(ctx context.Context, arg UpdateLTIPlatformParams) (LtiPlatform, error)
 	UpdateLessonCompletion(ctx context.Context, arg UpdateLessonCompletionParams) (LessonCompletion, error)
 	UpdateMark(ctx context.Context, arg UpdateMarkParams) (Mark, error)
 	UpdateMaterial(ctx context.Context, arg UpdateMaterialParams) (Material, error)
 	UpdateProfilePicture(ctx context.Context, arg UpdateProfilePictureParams) (ProfilePicture, error)
 	UpdateRequest(ctx context.Context, arg UpdateRequestParams) (Request, error)
-	UpdateSubmission(ctx context.Context, arg UpdateSubmissionParams) (Submission, error)
+	UpdateSCORMAttempt(ctx context.Context, arg UpdateSCORMAttemptParams) (ScormAttempt, error)
+	UpdateSCORMPackage(ctx context.Context, arg UpdateSCORMPackageParams) (ScormPackage, error)
+	UpdateSubmissionFromAttempt(ctx context.Context, arg UpdateSubmissionFromAttemptParams) (Submission, error)
 	UpdateSubscriptions(ctx context.Context, arg UpdateSubscriptionsParams) (Subscription, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserLocale(ctx context.Context, arg UpdateUserLocaleParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (UserStatus, error)
 	UpdateUserStatusByAdmin(ctx context.Context, arg UpdateUserStatusByAdminParams) (UserStatus, error)
 	UpdateUsersPassword(ctx context.Context, arg UpdateUsersPasswordParams) (User, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
+	UpdateVideoWatch(ctx context.Context, arg UpdateVideoWatchParams) (VideoWatch, error)
+	UpsertCourseEnrollmentSettings(ctx context.Context, arg UpsertCourseEnrollmentSettingsParams) (CourseEnrollmentSetting, error)
+	UpsertCoursePrice(ctx context.Context, arg UpsertCoursePriceParams) (CoursePrice, error)
+	UpsertCourseProgress(ctx context.Context, arg UpsertCourseProgressParams) (CourseProgress, error)
+	UpsertCourseProgressWeights(ctx context.Context, arg UpsertCourseProgressWeightsParams) (CourseProgressWeight, error)
+	UpsertGradeItemScore(ctx context.Context, arg UpsertGradeItemScoreParams) (GradeItemScore, error)
+	UpsertGradeScale(ctx context.Context, arg UpsertGradeScaleParams) (GradeScale, error)
+	UpsertLTIResourceLink(ctx context.Context, arg UpsertLTIResourceLinkParams) (LtiResourceLink, error)
+	UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error)
+	UpsertPeerReviewSettings(ctx context.Context, arg UpsertPeerReviewSettingsParams) (PeerReviewSetting, error)
+	UpsertRubric(ctx context.Context, arg UpsertRubricParams) (Rubric, error)
+	UpsertSimilarityReport(ctx context.Context, arg UpsertSimilarityReportParams) (SimilarityReport, error)
+	UpsertSubmissionAdjustment(ctx context.Context, arg UpsertSubmissionAdjustmentParams) (SubmissionAdjustment, error)
+	UpsertSubmissionText(ctx context.Context, arg UpsertSubmissionTextParams) (SubmissionText, error)
+	UseCoupon(ctx context.Context, couponID int64) (Coupon, error)
+	UseCourseInvite(ctx context.Context, inviteID int64) error
+	VoidXAPIStatement(ctx context.Context, statementID pgtype.UUID) error
+	WaitlistSubscription(ctx context.Context, subscriptionID int64) (Subscription, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -17,6 +17,24 @@ type Store interface {
 	CheckEmailTx(ctx context.Context, arg CheckEmailTxParams) (CheckEmailTxResult, error)
 	UpdateRequestTx(ctx context.Context, arg UpdateRequestTxParams) (UpdateRequestTxResult, error)
 	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	StartQuizAttemptTx(ctx context.Context, arg StartQuizAttemptTxParams) (StartQuizAttemptTxResult, error)
+	SubmitQuizAttemptTx(ctx context.Context, arg SubmitQuizAttemptTxParams) (SubmitQuizAttemptTxResult, error)
+	CreateSubmissionAttemptTx(ctx context.Context, arg CreateSubmissionAttemptTxParams) (CreateSubmissionAttemptTxResult, error)
+	ImportGradeItemScoresTx(ctx context.Context, arg ImportGradeItemScoresTxParams) (ImportGradeItemScoresTxResult, error)
+	AssignPeerReviewsTx(ctx context.Context, arg AssignPeerReviewsTxParams) (AssignPeerReviewsTxResult, error)
+	CreateCourseGroupsTx(ctx context.Context, arg CreateCourseGroupsTxParams) (CreateCourseGroupsTxResult, error)
+	DecideSubscriptionTx(ctx context.Context, arg DecideSubscriptionTxParams) (DecideSubscriptionTxResult, error)
+	PromoteWaitlistTx(ctx context.Context, courseID int64) (PromoteWaitlistTxResult, error)
+	RedeemCourseInviteTx(ctx context.Context, arg RedeemCourseInviteTxParams) (RedeemCourseInviteTxResult, error)
+	CreateOrderTx(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CompleteOrderTx(ctx context.Context, arg CompleteOrderTxParams) (CompleteOrderTxResult, error)
+	RefundOrderTx(ctx context.Context, orderID int64) (RefundOrderTxResult, error)
+	RecomputeCourseProgressTx(ctx context.Context, arg RecomputeCourseProgressTxParams) (RecomputeCourseProgressTxResult, error)
+	SetCourseProgressWeightsTx(ctx context.Context, arg SetCourseProgressWeightsTxParams) (SetCourseProgressWeightsTxResult, error)
+	DeleteMaterialTx(ctx context.Context, arg DeleteMaterialTxParams) (DeleteMaterialTxResult, error)
+	RecordVideoHeartbeatTx(ctx context.Context, arg RecordVideoHeartbeatTxParams) (RecordVideoHeartbeatTxResult, error)
+	StoreXAPIStatementsTx(ctx context.Context, arg StoreXAPIStatementsTxParams) (StoreXAPIStatementsTxResult, error)
+	CommitSCORMRuntimeTx(ctx context.Context, arg CommitSCORMRuntimeTxParams) (CommitSCORMRuntimeTxResult, error)
 }
 
 // store provide all funtions to execute db queries and data trival and transfers
@@ -7,7 +7,10 @@ package db
 
 import (
 	"context"
+	"eduApp/typetext"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createSubmission = `-- name: CreateSubmission :one
@@ -17,19 +20,25 @@ INSERT INTO submission (
     grade,
     resource,
     date_of_submission,
-    submitted
+    submitted,
+    is_late,
+    penalty_percent,
+    group_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id
 `
 
 type CreateSubmissionParams struct {
-	AssignmentID     int64     `json:"assignment_id"`
-	UserID           int64     `json:"user_id"`
-	Grade            string    `json:"grade"`
-	Resource         string    `json:"resource"`
-	DateOfSubmission time.Time `json:"date_of_submission"`
-	Submitted        bool      `json:"submitted"`
+	AssignmentID     int64       `json:"assignment_id"`
+	UserID           int64       `json:"user_id"`
+	Grade            string      `json:"grade"`
+	Resource         string      `json:"resource"`
+	DateOfSubmission time.Time   `json:"date_of_submission"`
+	Submitted        bool        `json:"submitted"`
+	IsLate           bool        `json:"is_late"`
+	PenaltyPercent   int64       `json:"penalty_percent"`
+	GroupID          pgtype.Int8 `json:"group_id"`
 }
 
 func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (Submission, error) {
@@ -40,6 +49,9 @@ func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionPara
 		arg.Resource,
 		arg.DateOfSubmission,
 		arg.Submitted,
+		arg.IsLate,
+		arg.PenaltyPercent,
+		arg.GroupID,
 	)
 	var i Submission
 	err := row.Scan(
@@ -51,30 +63,109 @@ func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionPara
 		&i.Resource,
 		&i.DateOfSubmission,
 		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
 
-const deleteSubmission = `-- name: DeleteSubmission :exec
-DELETE FROM submission
-WHERE 
-    assignment_id = $1
-    AND user_id = $2
+const createSubmissionIfMissing = `-- name: CreateSubmissionIfMissing :exec
+INSERT INTO submission (
+    assignment_id,
+    user_id,
+    grade,
+    resource,
+    date_of_submission,
+    submitted,
+    is_late,
+    penalty_percent,
+    group_id
+) VALUES (
+    $1, $2, 'null', $3, $4, true, $5, $6, $7
+) ON CONFLICT DO NOTHING
 `
 
-type DeleteSubmissionParams struct {
-	AssignmentID int64 `json:"assignment_id"`
-	UserID       int64 `json:"user_id"`
+type CreateSubmissionIfMissingParams struct {
+	AssignmentID     int64       `json:"assignment_id"`
+	UserID           int64       `json:"user_id"`
+	Resource         string      `json:"resource"`
+	DateOfSubmission time.Time   `json:"date_of_submission"`
+	IsLate           bool        `json:"is_late"`
+	PenaltyPercent   int64       `json:"penalty_percent"`
+	GroupID          pgtype.Int8 `json:"group_id"`
 }
 
-func (q *Queries) DeleteSubmission(ctx context.Context, arg DeleteSubmissionParams) error {
-	_, err := q.db.Exec(ctx, deleteSubmission, arg.AssignmentID, arg.UserID)
+func (q *Queries) CreateSubmissionIfMissing(ctx context.Context, arg CreateSubmissionIfMissingParams) error {
+	_, err := q.db.Exec(ctx, createSubmissionIfMissing,
+		arg.AssignmentID,
+		arg.UserID,
+		arg.Resource,
+		arg.DateOfSubmission,
+		arg.IsLate,
+		arg.PenaltyPercent,
+		arg.GroupID,
+	)
 	return err
 }
 
+const deleteSubmission = `-- name: DeleteSubmission :execrows
+DELETE FROM submission
+WHERE submission_id = $1
+`
+
+func (q *Queries) DeleteSubmission(ctx context.Context, submissionID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSubmission, submissionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getGroupSubmissionForUpdate = `-- name: GetGroupSubmissionForUpdate :one
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
+WHERE assignment_id = $1 AND group_id = $2
+FOR UPDATE
+`
+
+type GetGroupSubmissionForUpdateParams struct {
+	AssignmentID int64       `json:"assignment_id"`
+	GroupID      pgtype.Int8 `json:"group_id"`
+}
+
+func (q *Queries) GetGroupSubmissionForUpdate(ctx context.Context, arg GetGroupSubmissionForUpdateParams) (Submission, error) {
+	row := q.db.QueryRow(ctx, getGroupSubmissionForUpdate, arg.AssignmentID, arg.GroupID)
+	var i Submission
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.AssignmentID,
+		&i.UserID,
+		&i.Submitted,
+		&i.Grade,
+		&i.Resource,
+		&i.DateOfSubmission,
+		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
+	)
+	return i, err
+}
+
 const getSubmission = `-- name: GetSubmission :one
-SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at FROM submission
-WHERE assignment_id = $1 AND user_id = $2
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
+WHERE assignment_id = $1
+    AND (user_id = $2 OR group_id IN (SELECT group_id FROM course_group_members WHERE user_id = $2))
+ORDER BY group_id IS NULL, submission_id
+LIMIT 1
 `
 
 type GetSubmissionParams struct {
@@ -94,12 +185,81 @@ func (q *Queries) GetSubmission(ctx context.Context, arg GetSubmissionParams) (S
 		&i.Resource,
 		&i.DateOfSubmission,
 		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
+	)
+	return i, err
+}
+
+const getSubmissionByID = `-- name: GetSubmissionByID :one
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
+WHERE submission_id = $1
+`
+
+func (q *Queries) GetSubmissionByID(ctx context.Context, submissionID int64) (Submission, error) {
+	row := q.db.QueryRow(ctx, getSubmissionByID, submissionID)
+	var i Submission
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.AssignmentID,
+		&i.UserID,
+		&i.Submitted,
+		&i.Grade,
+		&i.Resource,
+		&i.DateOfSubmission,
+		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
+	)
+	return i, err
+}
+
+const getSubmissionForUpdate = `-- name: GetSubmissionForUpdate :one
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
+WHERE assignment_id = $1 AND user_id = $2 AND group_id IS NULL
+FOR UPDATE
+`
+
+type GetSubmissionForUpdateParams struct {
+	AssignmentID int64 `json:"assignment_id"`
+	UserID       int64 `json:"user_id"`
+}
+
+func (q *Queries) GetSubmissionForUpdate(ctx context.Context, arg GetSubmissionForUpdateParams) (Submission, error) {
+	row := q.db.QueryRow(ctx, getSubmissionForUpdate, arg.AssignmentID, arg.UserID)
+	var i Submission
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.AssignmentID,
+		&i.UserID,
+		&i.Submitted,
+		&i.Grade,
+		&i.Resource,
+		&i.DateOfSubmission,
+		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
 
 const getsubmissionsByAssignment = `-- name: GetsubmissionsByAssignment :one
-SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at FROM submission
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
 WHERE assignment_id = $1 
 LIMIT 1
 `
@@ -116,12 +276,19 @@ func (q *Queries) GetsubmissionsByAssignment(ctx context.Context, assignmentID i
 		&i.Resource,
 		&i.DateOfSubmission,
 		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
 
 const getsubmissionsByUser = `-- name: GetsubmissionsByUser :one
-SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at FROM submission
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
 WHERE user_id = $1
 LIMIT 1
 `
@@ -138,12 +305,103 @@ func (q *Queries) GetsubmissionsByUser(ctx context.Context, userID int64) (Submi
 		&i.Resource,
 		&i.DateOfSubmission,
 		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
+	)
+	return i, err
+}
+
+const gradeSubmission = `-- name: GradeSubmission :one
+UPDATE submission
+SET grade = $2, rubric_scores = $3, feedback = $4, graded_at = now(), updated_at = now()
+WHERE submission_id = $1
+RETURNING submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id
+`
+
+type GradeSubmissionParams struct {
+	SubmissionID int64                 `json:"submission_id"`
+	Grade        string                `json:"grade"`
+	RubricScores typetext.RubricScores `json:"rubric_scores"`
+	Feedback     string                `json:"feedback"`
+}
+
+func (q *Queries) GradeSubmission(ctx context.Context, arg GradeSubmissionParams) (Submission, error) {
+	row := q.db.QueryRow(ctx, gradeSubmission,
+		arg.SubmissionID,
+		arg.Grade,
+		arg.RubricScores,
+		arg.Feedback,
+	)
+	var i Submission
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.AssignmentID,
+		&i.UserID,
+		&i.Submitted,
+		&i.Grade,
+		&i.Resource,
+		&i.DateOfSubmission,
+		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
 
+const listSubmittedSubmissionsByAssignment = `-- name: ListSubmittedSubmissionsByAssignment :many
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
+WHERE assignment_id = $1 AND submitted = true
+ORDER BY submission_id
+`
+
+func (q *Queries) ListSubmittedSubmissionsByAssignment(ctx context.Context, assignmentID int64) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSubmittedSubmissionsByAssignment, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Submission{}
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.AssignmentID,
+			&i.UserID,
+			&i.Submitted,
+			&i.Grade,
+			&i.Resource,
+			&i.DateOfSubmission,
+			&i.UpdatedAt,
+			&i.IsLate,
+			&i.PenaltyPercent,
+			&i.GradedAttemptID,
+			&i.RubricScores,
+			&i.Feedback,
+			&i.GradedAt,
+			&i.GroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listsubmissions = `-- name: Listsubmissions :many
-SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at FROM submission
+SELECT submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id FROM submission
 ORDER BY submission_id
 LIMIT $1
 OFFSET $2
@@ -172,6 +430,13 @@ func (q *Queries) Listsubmissions(ctx context.Context, arg ListsubmissionsParams
 			&i.Resource,
 			&i.DateOfSubmission,
 			&i.UpdatedAt,
+			&i.IsLate,
+			&i.PenaltyPercent,
+			&i.GradedAttemptID,
+			&i.RubricScores,
+			&i.Feedback,
+			&i.GradedAt,
+			&i.GroupID,
 		); err != nil {
 			return nil, err
 		}
@@ -183,21 +448,44 @@ func (q *Queries) Listsubmissions(ctx context.Context, arg ListsubmissionsParams
 	return items, nil
 }
 
-const updateSubmission = `-- name: UpdateSubmission :one
+const updateSubmissionFromAttempt = `-- name: UpdateSubmissionFromAttempt :one
 UPDATE submission
-SET assignment_id = $2 AND user_id = $3
+SET
+    submitted = true,
+    resource = $2,
+    date_of_submission = $3,
+    is_late = $4,
+    penalty_percent = $5,
+    graded_attempt_id = $6,
+    grade = CASE WHEN $7::boolean THEN 'null' ELSE grade END,
+    rubric_scores = CASE WHEN $7::boolean THEN '[]' ELSE rubric_scores END,
+    feedback = CASE WHEN $7::boolean THEN '' ELSE feedback END,
+    graded_at = CASE WHEN $7::boolean THEN NULL ELSE graded_at END,
+    updated_at = now()
 WHERE submission_id = $1
-RETURNING submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at
+RETURNING submission_id, assignment_id, user_id, submitted, grade, resource, date_of_submission, updated_at, is_late, penalty_percent, graded_attempt_id, rubric_scores, feedback, graded_at, group_id
 `
 
-type UpdateSubmissionParams struct {
-	SubmissionID int64 `json:"submission_id"`
-	AssignmentID int64 `json:"assignment_id"`
-	UserID       int64 `json:"user_id"`
+type UpdateSubmissionFromAttemptParams struct {
+	SubmissionID     int64       `json:"submission_id"`
+	Resource         string      `json:"resource"`
+	DateOfSubmission time.Time   `json:"date_of_submission"`
+	IsLate           bool        `json:"is_late"`
+	PenaltyPercent   int64       `json:"penalty_percent"`
+	GradedAttemptID  pgtype.Int8 `json:"graded_attempt_id"`
+	ClearGrade       bool        `json:"clear_grade"`
 }
 
-func (q *Queries) UpdateSubmission(ctx context.Context, arg UpdateSubmissionParams) (Submission, error) {
-	row := q.db.QueryRow(ctx, updateSubmission, arg.SubmissionID, arg.AssignmentID, arg.UserID)
+func (q *Queries) UpdateSubmissionFromAttempt(ctx context.Context, arg UpdateSubmissionFromAttemptParams) (Submission, error) {
+	row := q.db.QueryRow(ctx, updateSubmissionFromAttempt,
+		arg.SubmissionID,
+		arg.Resource,
+		arg.DateOfSubmission,
+		arg.IsLate,
+		arg.PenaltyPercent,
+		arg.GradedAttemptID,
+		arg.ClearGrade,
+	)
 	var i Submission
 	err := row.Scan(
 		&i.SubmissionID,
@@ -208,6 +496,13 @@ func (q *Queries) UpdateSubmission(ctx context.Context, arg UpdateSubmissionPara
 		&i.Resource,
 		&i.DateOfSubmission,
 		&i.UpdatedAt,
+		&i.IsLate,
+		&i.PenaltyPercent,
+		&i.GradedAttemptID,
+		&i.RubricScores,
+		&i.Feedback,
+		&i.GradedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
@@ -19,7 +19,7 @@ INSERT INTO subscriptions (
     pending
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING subscription_id, user_id, course_id, active, pending, created_at, updated_at
+) RETURNING subscription_id, user_id, course_id, active, pending, created_at, updated_at, waitlisted_at, denied_at, starts_at, expires_at, expiry_reminded_at, expired_at
 `
 
 type CreateSubscriptionParams struct {
@@ -45,6 +45,12 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		&i.Pending,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WaitlistedAt,
+		&i.DeniedAt,
+		&i.StartsAt,
+		&i.ExpiresAt,
+		&i.ExpiryRemindedAt,
+		&i.ExpiredAt,
 	)
 	return i, err
 }
@@ -77,7 +83,7 @@ func (q *Queries) GetStudentCountInCourse(ctx context.Context) ([]int64, error)
 }
 
 const getSubscription = `-- name: GetSubscription :one
-SELECT subscription_id, user_id, course_id, active, pending, created_at, updated_at FROM subscriptions
+SELECT subscription_id, user_id, course_id, active, pending, created_at, updated_at, waitlisted_at, denied_at, starts_at, expires_at, expiry_reminded_at, expired_at FROM subscriptions
 WHERE user_id = $1
 `
 
@@ -92,6 +98,12 @@ func (q *Queries) GetSubscription(ctx context.Context, userID int64) (Subscripti
 		&i.Pending,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WaitlistedAt,
+		&i.DeniedAt,
+		&i.StartsAt,
+		&i.ExpiresAt,
+		&i.ExpiryRemindedAt,
+		&i.ExpiredAt,
 	)
 	return i, err
 }
@@ -186,7 +198,7 @@ func (q *Queries) GetUserCountForCertianCourse(ctx context.Context, arg GetUserC
 }
 
 const listSubscriptionsByCourse = `-- name: ListSubscriptionsByCourse :many
-SELECT subscription_id, user_id, course_id, active, pending, created_at, updated_at FROM subscriptions
+SELECT subscription_id, user_id, course_id, active, pending, created_at, updated_at, waitlisted_at, denied_at, starts_at, expires_at, expiry_reminded_at, expired_at FROM subscriptions
 WHERE user_id = $1
 ORDER BY course_id
 LIMIT $2
@@ -216,6 +228,12 @@ func (q *Queries) ListSubscriptionsByCourse(ctx context.Context, arg ListSubscri
 			&i.Pending,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WaitlistedAt,
+			&i.DeniedAt,
+			&i.StartsAt,
+			&i.ExpiresAt,
+			&i.ExpiryRemindedAt,
+			&i.ExpiredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -228,7 +246,7 @@ func (q *Queries) ListSubscriptionsByCourse(ctx context.Context, arg ListSubscri
 }
 
 const listSubscriptionsByUser = `-- name: ListSubscriptionsByUser :many
-SELECT subscription_id, user_id, course_id, active, pending, created_at, updated_at FROM subscriptions
+SELECT subscription_id, user_id, course_id, active, pending, created_at, updated_at, waitlisted_at, denied_at, starts_at, expires_at, expiry_reminded_at, expired_at FROM subscriptions
 WHERE course_id = $1
 ORDER BY user_id
 LIMIT $2
@@ -258,6 +276,12 @@ func (q *Queries) ListSubscriptionsByUser(ctx context.Context, arg ListSubscript
 			&i.Pending,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WaitlistedAt,
+			&i.DeniedAt,
+			&i.StartsAt,
+			&i.ExpiresAt,
+			&i.ExpiryRemindedAt,
+			&i.ExpiredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -273,11 +297,23 @@ const updateSubscriptions = `-- name: UpdateSubscriptions :one
 UPDATE subscriptions
 SET 
     pending = COALESCE($1, pending),
-    active = COALESCE($2, active)
+    active = COALESCE($2, active),
+    waitlisted_at = CASE WHEN COALESCE($2, active) THEN NULL ELSE waitlisted_at END,
+    starts_at = CASE WHEN NOT active AND COALESCE($2, active) THEN GREATEST(now(), (
+        SELECT e.access_starts_at FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id
+    )) ELSE starts_at END,
+    expires_at = CASE WHEN NOT active AND COALESCE($2, active) THEN (
+        SELECT GREATEST(now(), e.access_starts_at) + make_interval(days => e.access_days::int)
+        FROM course_enrollment_settings e
+        WHERE e.course_id = subscriptions.course_id AND e.access_days > 0
+    ) ELSE expires_at END,
+    expiry_reminded_at = CASE WHEN NOT active AND COALESCE($2, active) THEN NULL ELSE expiry_reminded_at END,
+    expired_at = CASE WHEN COALESCE($2, active) THEN NULL ELSE expired_at END
 
 WHERE
     user_id = $3 AND course_id = $4
-RETURNING subscription_id, user_id, course_id, active, pending, created_at, updated_at
+RETURNING subscription_id, user_id, course_id, active, pending, created_at, updated_at, waitlisted_at, denied_at, starts_at, expires_at, expiry_reminded_at, expired_at
 `
 
 type UpdateSubscriptionsParams struct {
@@ -303,6 +339,12 @@ func (q *Queries) UpdateSubscriptions(ctx context.Context, arg UpdateSubscriptio
 		&i.Pending,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WaitlistedAt,
+		&i.DeniedAt,
+		&i.StartsAt,
+		&i.ExpiresAt,
+		&i.ExpiryRemindedAt,
+		&i.ExpiredAt,
 	)
 	return i, err
 }
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+)
+
+type AssignPeerReviewsTxParams struct {
+	AssignmentID int64
+	Reviews      []CreatePeerReviewParams
+}
+
+type AssignPeerReviewsTxResult struct {
+	Reviews []PeerReview
+	// Assigned is false when reviewers were already assigned by an earlier run
+	Assigned bool
+}
+
+// AssignPeerReviewsTx creates the peer reviews of an assignment and marks its reviewers as assigned,
+// the settings row is locked so two runs cannot assign the same assignment twice
+func (store *SQLStore) AssignPeerReviewsTx(ctx context.Context, arg AssignPeerReviewsTxParams) (AssignPeerReviewsTxResult, error) {
+	var result AssignPeerReviewsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		settings, err := q.GetPeerReviewSettingsForUpdate(ctx, arg.AssignmentID)
+		if err != nil {
+			return err
+		}
+		if settings.AssignedAt.Valid {
+			return nil
+		}
+
+		result.Reviews = make([]PeerReview, 0, len(arg.Reviews))
+		for _, review := range arg.Reviews {
+			created, err := q.CreatePeerReview(ctx, review)
+			if err != nil {
+				return err
+			}
+			result.Reviews = append(result.Reviews, created)
+		}
+
+		if err := q.SetPeerReviewsAssigned(ctx, arg.AssignmentID); err != nil {
+			return err
+		}
+		result.Assigned = true
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"eduApp/scorm"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrNotSCORM is returned when runtime data is committed for a material that is not a SCORM package
+var ErrNotSCORM = errors.New("material is not a SCORM package")
+
+type CommitSCORMRuntimeTxParams struct {
+	UserID     int64
+	MaterialID int64
+	// Values are the data model elements the SCO set since its previous commit
+	Values map[string]string
+	// Finish is true when the SCO terminated its session
+	Finish bool
+	// AfterComplete is called when completing the lesson completes the course for the student
+	AfterComplete func(courseProgress CourseProgress) error
+}
+
+type CommitSCORMRuntimeTxResult struct {
+	Attempt ScormAttempt
+	Result  scorm.Result
+	// Completed is true when this commit completed the lesson
+	Completed bool
+	// MarkUpdated is true when the score changed and the course mark was refreshed
+	MarkUpdated    bool
+	Mark           Mark
+	CourseProgress CourseProgress
+}
+
+// CommitSCORMRuntimeTx merges the values a SCO committed into the student's attempt. The best reported score counts
+// towards the course mark and the first time the SCO reports the lesson passed or completed the course progress is recomputed.
+func (store *SQLStore) CommitSCORMRuntimeTx(ctx context.Context, arg CommitSCORMRuntimeTxParams) (CommitSCORMRuntimeTxResult, error) {
+	var result CommitSCORMRuntimeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		material, err := q.GetMaterialByID(ctx, arg.MaterialID)
+		if err != nil {
+			return err
+		}
+		if material.MaterialType != "scorm" {
+			return ErrNotSCORM
+		}
+
+		pkg, err := q.GetSCORMPackage(ctx, arg.MaterialID)
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateSCORMAttempt(ctx, CreateSCORMAttemptParams{
+			UserID:     arg.UserID,
+			MaterialID: arg.MaterialID,
+		})
+		if err != nil {
+			return err
+		}
+
+		attempt, err := q.GetSCORMAttemptForUpdate(ctx, GetSCORMAttemptForUpdateParams{
+			UserID:     arg.UserID,
+			MaterialID: arg.MaterialID,
+		})
+		if err != nil {
+			return err
+		}
+
+		cmi, sessionSeconds, err := scorm.Commit(pkg.Version, attempt.Cmi, arg.Values, arg.Finish)
+		if err != nil {
+			return err
+		}
+		result.Result = scorm.Evaluate(pkg.Version, cmi)
+
+		// a later, worse try does not take away the best score reported
+		score := attempt.Score
+		if result.Result.Score != nil && (!score.Valid || *result.Result.Score > score.Float64) {
+			score = pgtype.Float8{Float64: *result.Result.Score, Valid: true}
+			result.MarkUpdated = true
+		}
+
+		completedAt := attempt.CompletedAt
+		if !completedAt.Valid && result.Result.Completed {
+			completedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			result.Completed = true
+		}
+
+		result.Attempt, err = q.UpdateSCORMAttempt(ctx, UpdateSCORMAttemptParams{
+			AttemptID:    attempt.AttemptID,
+			Cmi:          cmi,
+			LessonStatus: result.Result.Status,
+			Score:        score,
+			TotalSeconds: attempt.TotalSeconds + sessionSeconds,
+			CompletedAt:  completedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.MarkUpdated {
+			result.Mark, err = updateCourseMark(ctx, q, material.CourseID, arg.UserID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !result.Completed {
+			return nil
+		}
+
+		_, err = q.CreateLessonCompletion(ctx, CreateLessonCompletionParams{
+			CourseID:   material.CourseID,
+			UserID:     arg.UserID,
+			MaterialID: arg.MaterialID,
+			Completed:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		progress, err := recomputeCourseProgress(ctx, q, material.CourseID, []int64{arg.UserID}, arg.AfterComplete)
+		if err != nil {
+			return err
+		}
+		result.CourseProgress = progress[0]
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// statuses of an order
+const (
+	// OrderStatusPending is an order waiting for its checkout to be paid
+	OrderStatusPending = "pending"
+	// OrderStatusPaid is an order that was paid and enrolled the student
+	OrderStatusPaid = "paid"
+	// OrderStatusFailed is an order whose payment was declined
+	OrderStatusFailed = "failed"
+	// OrderStatusExpired is an order whose checkout was abandoned
+	OrderStatusExpired = "expired"
+	// OrderStatusRefunded is a paid order that was refunded, the student lost access to the course
+	OrderStatusRefunded = "refunded"
+)
+
+// ErrPaymentMismatch is returned when a payment does not match the amount, currency or checkout of its order
+var ErrPaymentMismatch = errors.New("payment does not match the order")
+
+type CompleteOrderTxParams struct {
+	OrderID   int64
+	SessionID string
+	PaymentID string
+	Amount    int64
+	Currency  string
+	// AfterCreate runs inside the transaction when a new subscription is created
+	AfterCreate func(subscription Subscription) error
+}
+
+type CompleteOrderTxResult struct {
+	Order        Order
+	Subscription Subscription
+	// Completed is false when the order was paid or refunded before, a provider may deliver an event more than once
+	Completed bool
+	// Created is true when the payment created the subscription rather than activating an existing one
+	Created bool
+}
+
+// CompleteOrderTx enrolls the student of an order after a verified successful payment. The student skips approval
+// and takes a seat, or joins the waitlist when the course is full. A student whose access is about to end renews it.
+// A late payment also completes a failed or expired order, unless the coupon it used was taken by others meanwhile,
+// then ErrCouponExhausted is returned and nothing changes.
+func (store *SQLStore) CompleteOrderTx(ctx context.Context, arg CompleteOrderTxParams) (CompleteOrderTxResult, error) {
+	var result CompleteOrderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Order, err = q.GetOrderForUpdate(ctx, arg.OrderID)
+		if err != nil {
+			return err
+		}
+		if result.Order.Status == OrderStatusPaid || result.Order.Status == OrderStatusRefunded {
+			return nil
+		}
+
+		if result.Order.Amount != arg.Amount || result.Order.Currency != arg.Currency {
+			return ErrPaymentMismatch
+		}
+		if result.Order.CheckoutSessionID.Valid && result.Order.CheckoutSessionID.String != arg.SessionID {
+			return ErrPaymentMismatch
+		}
+
+		// a pending order holds its coupon use, a closed one gave it back and takes it again if one is left
+		if result.Order.CouponID.Valid && result.Order.Status != OrderStatusPending {
+			_, err = q.UseCoupon(ctx, result.Order.CouponID.Int64)
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrCouponExhausted
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Order, err = q.MarkOrderPaid(ctx, MarkOrderPaidParams{
+			OrderID:   arg.OrderID,
+			PaymentID: arg.PaymentID,
+		})
+		if err != nil {
+			return err
+		}
+		result.Completed = true
+
+		settings, err := lockEnrollmentSettings(ctx, q, result.Order.CourseID)
+		if err != nil {
+			return err
+		}
+
+		subscription, err := q.GetCourseSubscriptionForUpdate(ctx, GetCourseSubscriptionForUpdateParams{
+			UserID:   result.Order.UserID,
+			CourseID: result.Order.CourseID,
+		})
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+		result.Created = errors.Is(err, ErrRecordNotFound)
+
+		// paying again while access lasts renews it for another access period,
+		// the order keeps the extension so a refund takes back only that
+		if !result.Created && subscription.Active && subscription.ExpiresAt.Valid {
+			result.Subscription, err = q.RenewSubscription(ctx, subscription.SubscriptionID)
+			if err != nil {
+				return err
+			}
+			result.Order, err = q.SetOrderRenewal(ctx, SetOrderRenewalParams{
+				OrderID:      result.Order.OrderID,
+				RenewedFrom:  subscription.ExpiresAt,
+				RenewedUntil: result.Subscription.ExpiresAt,
+			})
+			return err
+		}
+		if !result.Created && (subscription.Active || subscription.WaitlistedAt.Valid) {
+			result.Subscription = subscription
+			return nil
+		}
+
+		seat, err := hasOpenSeat(ctx, q, settings)
+		if err != nil {
+			return err
+		}
+
+		if result.Created {
+			subscription, err = q.CreateSubscription(ctx, CreateSubscriptionParams{
+				UserID:   result.Order.UserID,
+				CourseID: result.Order.CourseID,
+				Active:   false,
+				Pending:  false,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if seat {
+			result.Subscription, err = q.ActivateSubscription(ctx, subscription.SubscriptionID)
+		} else {
+			result.Subscription, err = q.WaitlistSubscription(ctx, subscription.SubscriptionID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !result.Created || arg.AfterCreate == nil {
+			return nil
+		}
+		return arg.AfterCreate(result.Subscription)
+	})
+
+	return result, err
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+)
+
+// CourseGroupTxParams is a group to create with its members
+type CourseGroupTxParams struct {
+	Name    string
+	UserIDs []int64
+}
+
+type CreateCourseGroupsTxParams struct {
+	CourseID int64
+	Groups   []CourseGroupTxParams
+}
+
+type CreateCourseGroupsTxResult struct {
+	Groups  []CourseGroup
+	Members []CourseGroupMember
+}
+
+// CreateCourseGroupsTx creates generated groups of a course with their members, either all of them or none
+func (store *SQLStore) CreateCourseGroupsTx(ctx context.Context, arg CreateCourseGroupsTxParams) (CreateCourseGroupsTxResult, error) {
+	var result CreateCourseGroupsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result.Groups = make([]CourseGroup, 0, len(arg.Groups))
+		result.Members = []CourseGroupMember{}
+		for _, group := range arg.Groups {
+			created, err := q.CreateCourseGroup(ctx, CreateCourseGroupParams{
+				CourseID: arg.CourseID,
+				Name:     group.Name,
+			})
+			if err != nil {
+				return err
+			}
+			result.Groups = append(result.Groups, created)
+
+			for _, userID := range group.UserIDs {
+				member, err := q.AddCourseGroupMember(ctx, AddCourseGroupMemberParams{
+					GroupID:  created.GroupID,
+					CourseID: arg.CourseID,
+					UserID:   userID,
+				})
+				if err != nil {
+					return err
+				}
+				result.Members = append(result.Members, member)
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
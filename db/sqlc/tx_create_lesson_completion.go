@@ -5,17 +5,28 @@ import "context"
 type CreateLessonCompletionTxParams struct {
 	CreateLessonCompletionParams
 	AfterCreate func(LessonCompletion) error
+	// AfterComplete is called when this lesson completes the course for the student
+	AfterComplete func(courseProgress CourseProgress) error
 }
 
 type CreateLessonCompletionTxResult struct {
 	LessonCompletion LessonCompletion
+	CourseProgress   CourseProgress
 }
 
+// CreateLessonCompletionTx marks a lesson completed and recomputes the student's course progress.
+// Completing a lesson again is not an error, it leaves the completion and the progress as they are.
 func (store *SQLStore) CreateLessonCompletionTx(ctx context.Context, arg CreateLessonCompletionTxParams) (CreateLessonCompletionTxResult, error) {
 	var result CreateLessonCompletionTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
+		material, err := q.GetMaterialByID(ctx, arg.MaterialID)
+		if err != nil {
+			return err
+		}
+		if material.CourseID != arg.CourseID {
+			return ErrRecordNotFound
+		}
 
 		result.LessonCompletion, err = q.CreateLessonCompletion(ctx, CreateLessonCompletionParams{
 			CourseID:   arg.CourseID,
@@ -23,10 +34,15 @@ func (store *SQLStore) CreateLessonCompletionTx(ctx context.Context, arg CreateL
 			MaterialID: arg.MaterialID,
 			Completed:  true,
 		})
+		if err != nil {
+			return err
+		}
 
+		progress, err := recomputeCourseProgress(ctx, q, arg.CourseID, []int64{arg.UserID}, arg.AfterComplete)
 		if err != nil {
 			return err
 		}
+		result.CourseProgress = progress[0]
 
 		if arg.AfterCreate != nil {
 			return arg.AfterCreate(result.LessonCompletion)
@@ -4,6 +4,9 @@ import "context"
 
 type CreateMaterialTxParams struct {
 	CreateMaterialParams
+	// SCORMPackage is stored for the new material when it is a SCORM package, MaterialID is filled in
+	SCORMPackage *CreateSCORMPackageParams
+	// AfterCreate queues the tasks of the new material, the material is rolled back when it fails
 	AfterCreate func(material Material) error
 }
 
@@ -11,6 +14,9 @@ type CreateMaterialTxResult struct {
 	Material Material
 }
 
+// CreateMaterialTx creates a material with its SCORM package. A new lesson lowers the progress of everyone taking
+// the course, which is recomputed from a task queued by AfterCreate once the material is committed so the transaction
+// stays short.
 func (store *SQLStore) CreateMaterialTx(ctx context.Context, arg CreateMaterialTxParams) (CreateMaterialTxResult, error) {
 	var result CreateMaterialTxResult
 
@@ -22,6 +28,15 @@ func (store *SQLStore) CreateMaterialTx(ctx context.Context, arg CreateMaterialT
 			return err
 		}
 
+		if arg.SCORMPackage != nil {
+			pkg := *arg.SCORMPackage
+			pkg.MaterialID = result.Material.MaterialID
+			_, err = q.CreateSCORMPackage(ctx, pkg)
+			if err != nil {
+				return err
+			}
+		}
+
 		return arg.AfterCreate(result.Material)
 	})
 
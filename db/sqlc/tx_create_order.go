@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCouponExhausted is returned when every use of a coupon is taken
+var ErrCouponExhausted = errors.New("coupon has been used up")
+
+// CreateOrderTx opens an order. An order with a coupon takes one of its uses, which it gives back when it is closed
+// unpaid, so concurrent checkouts cannot use a coupon more often than allowed.
+func (store *SQLStore) CreateOrderTx(ctx context.Context, arg CreateOrderParams) (Order, error) {
+	var order Order
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if arg.CouponID.Valid {
+			_, err = q.UseCoupon(ctx, arg.CouponID.Int64)
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrCouponExhausted
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		order, err = q.CreateOrder(ctx, arg)
+		return err
+	})
+
+	return order, err
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrMaxAttemptsReached is returned when a student has used every attempt of an assignment
+var ErrMaxAttemptsReached = errors.New("maximum number of attempts reached")
+
+type CreateSubmissionAttemptTxParams struct {
+	AssignmentID int64
+	// CourseID is the course of the assignment, whose progress is recomputed
+	CourseID int64
+	UserID   int64
+	// GroupID is set for group assignments, the group shares one submission
+	GroupID pgtype.Int8
+	// MaxAttempts limits the attempts per student, zero means unlimited
+	MaxAttempts    int64
+	Resource       string
+	Checksum       string
+	Size           int64
+	IsLate         bool
+	PenaltyPercent int64
+	SubmittedAt    time.Time
+	// AfterComplete is called for every student this submission completes the course for
+	AfterComplete func(courseProgress CourseProgress) error
+}
+
+type CreateSubmissionAttemptTxResult struct {
+	Submission        Submission
+	SubmissionAttempt SubmissionAttempt
+	// GradeCleared is set when the new attempt replaced graded work and its grade was removed
+	GradeCleared bool `json:"grade_cleared"`
+	// CourseProgress is the recomputed progress of the student, or of every member of the group
+	CourseProgress []CourseProgress `json:"-"`
+}
+
+// AttemptClearsGrade reports whether making attempt the graded work of submission drops its grade.
+// The grade, rubric scores and feedback belong to the file that was graded, they are kept only
+// when the submission already shows that attempt.
+func AttemptClearsGrade(submission Submission, attempt SubmissionAttempt) bool {
+	graded := submission.GradedAt.Valid || submission.Grade != "null" ||
+		len(submission.RubricScores) > 0 || submission.Feedback != ""
+	return graded && submission.Resource != attempt.Resource
+}
+
+// CreateSubmissionAttemptTx stores an upload as the next attempt of a student's submission.
+// Unless an instructor picked the graded attempt, the submission row follows the latest attempt
+// and a grade given to an earlier attempt is cleared, see AttemptClearsGrade.
+// For a group assignment the attempt is added to the group's submission whichever member uploads it.
+// The course progress of the student, or of every member of the group, is recomputed.
+func (store *SQLStore) CreateSubmissionAttemptTx(ctx context.Context, arg CreateSubmissionAttemptTxParams) (CreateSubmissionAttemptTxResult, error) {
+	var result CreateSubmissionAttemptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// the first upload creates the submission, the unique keys on (assignment, user) and
+		// (assignment, group) make a concurrent first upload add its attempt to the same row
+		err := q.CreateSubmissionIfMissing(ctx, CreateSubmissionIfMissingParams{
+			AssignmentID:     arg.AssignmentID,
+			UserID:           arg.UserID,
+			Resource:         arg.Resource,
+			DateOfSubmission: arg.SubmittedAt,
+			IsLate:           arg.IsLate,
+			PenaltyPercent:   arg.PenaltyPercent,
+			GroupID:          arg.GroupID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.GroupID.Valid {
+			result.Submission, err = q.GetGroupSubmissionForUpdate(ctx, GetGroupSubmissionForUpdateParams{
+				AssignmentID: arg.AssignmentID,
+				GroupID:      arg.GroupID,
+			})
+		} else {
+			result.Submission, err = q.GetSubmissionForUpdate(ctx, GetSubmissionForUpdateParams{
+				AssignmentID: arg.AssignmentID,
+				UserID:       arg.UserID,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		latest, err := q.GetLatestSubmissionAttemptNumber(ctx, result.Submission.SubmissionID)
+		if err != nil {
+			return err
+		}
+		if arg.MaxAttempts > 0 && latest >= arg.MaxAttempts {
+			return ErrMaxAttemptsReached
+		}
+
+		result.SubmissionAttempt, err = q.CreateSubmissionAttempt(ctx, CreateSubmissionAttemptParams{
+			SubmissionID:   result.Submission.SubmissionID,
+			AttemptNumber:  latest + 1,
+			Resource:       arg.Resource,
+			Checksum:       arg.Checksum,
+			Size:           arg.Size,
+			IsLate:         arg.IsLate,
+			PenaltyPercent: arg.PenaltyPercent,
+			SubmittedAt:    arg.SubmittedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !result.Submission.GradedAttemptID.Valid {
+			result.GradeCleared = AttemptClearsGrade(result.Submission, result.SubmissionAttempt)
+			result.Submission, err = q.UpdateSubmissionFromAttempt(ctx, UpdateSubmissionFromAttemptParams{
+				SubmissionID:     result.Submission.SubmissionID,
+				Resource:         result.SubmissionAttempt.Resource,
+				DateOfSubmission: result.SubmissionAttempt.SubmittedAt,
+				IsLate:           result.SubmissionAttempt.IsLate,
+				PenaltyPercent:   result.SubmissionAttempt.PenaltyPercent,
+				GradedAttemptID:  pgtype.Int8{},
+				ClearGrade:       result.GradeCleared,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		userIDs := []int64{arg.UserID}
+		if arg.GroupID.Valid {
+			members, err := q.ListCourseGroupMembers(ctx, arg.CourseID)
+			if err != nil {
+				return err
+			}
+			userIDs = userIDs[:0]
+			for _, member := range members {
+				if member.GroupID == arg.GroupID.Int64 {
+					userIDs = append(userIDs, member.UserID)
+				}
+			}
+			if len(userIDs) == 0 {
+				userIDs = append(userIDs, arg.UserID)
+			}
+		}
+		result.CourseProgress, err = recomputeCourseProgress(ctx, q, arg.CourseID, userIDs, arg.AfterComplete)
+		return err
+	})
+
+	return result, err
+}
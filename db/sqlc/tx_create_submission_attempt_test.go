@@ -0,0 +1,39 @@
+package db
+
+import (
+	"eduApp/typetext"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestAttemptClearsGrade(t *testing.T) {
+	gradedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	attempt := SubmissionAttempt{AttemptID: 2, Resource: "uploads/essay-2.pdf"}
+
+	testCases := []struct {
+		name       string
+		submission Submission
+		want       bool
+	}{
+		{name: "ungraded", submission: Submission{Grade: "null", Resource: "uploads/essay-1.pdf"}},
+		{name: "graded earlier attempt", want: true,
+			submission: Submission{Grade: "8", GradedAt: gradedAt, Resource: "uploads/essay-1.pdf"}},
+		{name: "feedback only", want: true,
+			submission: Submission{Grade: "null", Feedback: "Cite your sources", Resource: "uploads/essay-1.pdf"}},
+		{name: "rubric scores only", want: true,
+			submission: Submission{Grade: "null", Resource: "uploads/essay-1.pdf",
+				RubricScores: typetext.RubricScores{{Criterion: 0, Level: 1, Points: 3}}}},
+		{name: "graded the same attempt",
+			submission: Submission{Grade: "8", GradedAt: gradedAt, Feedback: "Good", Resource: "uploads/essay-2.pdf"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := AttemptClearsGrade(tc.submission, attempt); got != tc.want {
+				t.Errorf("AttemptClearsGrade() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,44 @@
 package db
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// enrollment modes of a course
+const (
+	// EnrollmentModeOpen enrolls students right away while there are seats
+	EnrollmentModeOpen = "open"
+	// EnrollmentModeApproval keeps requests pending until an admin approves or denies them
+	EnrollmentModeApproval = "approval"
+	// EnrollmentModeInvite only lets admins enroll students
+	EnrollmentModeInvite = "invite"
+	// EnrollmentModeClosed takes no new students
+	EnrollmentModeClosed = "closed"
+)
+
+var (
+	// ErrEnrollmentClosed is returned when a course does not take new students
+	ErrEnrollmentClosed = errors.New("enrollment in this course is closed")
+	// ErrEnrollmentInviteOnly is returned when a student asks to join an invite only course
+	ErrEnrollmentInviteOnly = errors.New("this course is invite only")
+	// ErrEnrollmentNotStarted is returned before the enrollment window of a course opens
+	ErrEnrollmentNotStarted = errors.New("enrollment in this course has not started yet")
+	// ErrEnrollmentEnded is returned after the enrollment window of a course closed
+	ErrEnrollmentEnded = errors.New("enrollment in this course has ended")
+	// ErrPaymentRequired is returned when a student asks to join a paid course without an order
+	ErrPaymentRequired = errors.New("this course has to be paid for before enrolling")
+)
 
 type CreateSubscriptionTxParams struct {
-	CreateSubscriptionParams
+	UserID   int64
+	CourseID int64
+	// Now is checked against the enrollment window of the course
+	Now time.Time
+	// Invited students skip the enrollment mode and window but still need a free seat
+	Invited bool
+	// AfterCreate runs inside the transaction when it is set
 	AfterCreate func(subscription Subscription) error
 }
 
@@ -11,19 +46,86 @@ type CreateSubscriptionTxResult struct {
 	Subscription Subscription
 }
 
+// CreateSubscriptionTx asks to enroll a student following the enrollment settings of the course.
+// In an open course the student gets a seat, or joins the end of the waitlist when the course is full;
+// in a course that needs approval the subscription stays pending for an admin. Admins enroll students as invited.
 func (store *SQLStore) CreateSubscriptionTx(ctx context.Context, arg CreateSubscriptionTxParams) (CreateSubscriptionTxResult, error) {
 	var result CreateSubscriptionTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
 
-		result.Subscription, err = q.CreateSubscription(ctx, arg.CreateSubscriptionParams)
+		result.Subscription, err = enrollStudent(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
+		if arg.AfterCreate == nil {
+			return nil
+		}
 		return arg.AfterCreate(result.Subscription)
 	})
 
 	return result, err
 }
+
+// enrollStudent enrolls a student within a transaction, see CreateSubscriptionTx
+func enrollStudent(ctx context.Context, q *Queries, arg CreateSubscriptionTxParams) (Subscription, error) {
+	settings, err := lockEnrollmentSettings(ctx, q, arg.CourseID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if !arg.Invited {
+		if err := CheckEnrollmentOpen(settings, arg.Now); err != nil {
+			return Subscription{}, err
+		}
+
+		_, err := q.GetCoursePrice(ctx, arg.CourseID)
+		if err == nil {
+			return Subscription{}, ErrPaymentRequired
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return Subscription{}, err
+		}
+	}
+
+	// invited students are approved already, only open courses and invitations take a seat right away
+	takesSeat := arg.Invited || settings.Mode == EnrollmentModeOpen
+	subscription, err := q.CreateSubscription(ctx, CreateSubscriptionParams{
+		UserID:   arg.UserID,
+		CourseID: arg.CourseID,
+		Active:   false,
+		Pending:  !takesSeat,
+	})
+	if err != nil || !takesSeat {
+		return subscription, err
+	}
+
+	seat, err := hasOpenSeat(ctx, q, settings)
+	if err != nil {
+		return Subscription{}, err
+	}
+	if !seat {
+		return q.WaitlistSubscription(ctx, subscription.SubscriptionID)
+	}
+	// activating starts the access window of the course
+	return q.ActivateSubscription(ctx, subscription.SubscriptionID)
+}
+
+// CheckEnrollmentOpen returns why the enrollment settings of a course turn a student away at now, if they do
+func CheckEnrollmentOpen(settings CourseEnrollmentSetting, now time.Time) error {
+	switch settings.Mode {
+	case EnrollmentModeClosed:
+		return ErrEnrollmentClosed
+	case EnrollmentModeInvite:
+		return ErrEnrollmentInviteOnly
+	}
+	if settings.EnrollmentStartsAt.Valid && now.Before(settings.EnrollmentStartsAt.Time) {
+		return ErrEnrollmentNotStarted
+	}
+	if settings.EnrollmentEndsAt.Valid && !now.Before(settings.EnrollmentEndsAt.Time) {
+		return ErrEnrollmentEnded
+	}
+	return nil
+}
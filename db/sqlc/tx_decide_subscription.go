@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSubscriptionNotPending is returned when deciding on a subscription that is not waiting for approval
+var ErrSubscriptionNotPending = errors.New("subscription is not waiting for approval")
+
+type DecideSubscriptionTxParams struct {
+	UserID   int64
+	CourseID int64
+	Approve  bool
+}
+
+type DecideSubscriptionTxResult struct {
+	Subscription Subscription
+}
+
+// DecideSubscriptionTx approves or denies a pending enrollment request. An approved student gets a seat,
+// or joins the end of the waitlist when the course is full. Waitlisted students can still be denied.
+func (store *SQLStore) DecideSubscriptionTx(ctx context.Context, arg DecideSubscriptionTxParams) (DecideSubscriptionTxResult, error) {
+	var result DecideSubscriptionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		settings, err := lockEnrollmentSettings(ctx, q, arg.CourseID)
+		if err != nil {
+			return err
+		}
+
+		subscription, err := q.GetCourseSubscriptionForUpdate(ctx, GetCourseSubscriptionForUpdateParams{
+			UserID:   arg.UserID,
+			CourseID: arg.CourseID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !arg.Approve {
+			if !subscription.Pending && !subscription.WaitlistedAt.Valid {
+				return ErrSubscriptionNotPending
+			}
+			result.Subscription, err = q.DenySubscription(ctx, subscription.SubscriptionID)
+			return err
+		}
+
+		if !subscription.Pending {
+			return ErrSubscriptionNotPending
+		}
+
+		seat, err := hasOpenSeat(ctx, q, settings)
+		if err != nil {
+			return err
+		}
+		if seat {
+			result.Subscription, err = q.ActivateSubscription(ctx, subscription.SubscriptionID)
+		} else {
+			result.Subscription, err = q.WaitlistSubscription(ctx, subscription.SubscriptionID)
+		}
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+type DeleteMaterialTxParams struct {
+	MaterialID int64
+	// AfterDelete queues the recomputation of the learners' progress, the deletion is rolled back when it fails
+	AfterDelete func(material Material) error
+}
+
+type DeleteMaterialTxResult struct {
+	Material Material
+	// SCORMPackage is the deleted package of a SCORM material, its files are left for the caller to remove
+	SCORMPackage *ScormPackage
+}
+
+// DeleteMaterialTx deletes a material with its lesson completions. The progress of the course's learners
+// is recomputed from a task queued by AfterDelete once the deletion is committed, so the transaction stays short.
+func (store *SQLStore) DeleteMaterialTx(ctx context.Context, arg DeleteMaterialTxParams) (DeleteMaterialTxResult, error) {
+	var result DeleteMaterialTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Material, err = q.GetMaterialByID(ctx, arg.MaterialID)
+		if err != nil {
+			return err
+		}
+
+		if result.Material.MaterialType == "scorm" {
+			pkg, err := q.GetSCORMPackage(ctx, arg.MaterialID)
+			if err != nil && !errors.Is(err, ErrRecordNotFound) {
+				return err
+			}
+			if err == nil {
+				result.SCORMPackage = &pkg
+			}
+		}
+
+		if err := q.DeleteMaterial(ctx, arg.MaterialID); err != nil {
+			return err
+		}
+
+		return arg.AfterDelete(result.Material)
+	})
+
+	return result, err
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+)
+
+type ImportGradeItemScoresTxParams struct {
+	Scores []UpsertGradeItemScoreParams
+}
+
+type ImportGradeItemScoresTxResult struct {
+	Scores []GradeItemScore
+}
+
+// ImportGradeItemScoresTx stores a batch of manual scores, either all of them are saved or none
+func (store *SQLStore) ImportGradeItemScoresTx(ctx context.Context, arg ImportGradeItemScoresTxParams) (ImportGradeItemScoresTxResult, error) {
+	var result ImportGradeItemScoresTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result.Scores = make([]GradeItemScore, 0, len(arg.Scores))
+		for _, score := range arg.Scores {
+			saved, err := q.UpsertGradeItemScore(ctx, score)
+			if err != nil {
+				return err
+			}
+			result.Scores = append(result.Scores, saved)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+type PromoteWaitlistTxResult struct {
+	// Promoted are the waitlisted subscriptions that got a seat, in waitlist order
+	Promoted []Subscription
+}
+
+// PromoteWaitlistTx gives the free seats of a course to the waitlist, first come first served
+func (store *SQLStore) PromoteWaitlistTx(ctx context.Context, courseID int64) (PromoteWaitlistTxResult, error) {
+	var result PromoteWaitlistTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		settings, err := lockEnrollmentSettings(ctx, q, courseID)
+		if err != nil {
+			return err
+		}
+
+		result.Promoted, err = promoteWaitlist(ctx, q, settings)
+		return err
+	})
+
+	return result, err
+}
+
+// lockEnrollmentSettings locks the enrollment settings of a course so seats are counted by one transaction at a time,
+// a course without settings keeps the original behaviour of admins approving every request with no capacity limit
+func lockEnrollmentSettings(ctx context.Context, q *Queries, courseID int64) (CourseEnrollmentSetting, error) {
+	settings, err := q.GetCourseEnrollmentSettingsForUpdate(ctx, courseID)
+	if errors.Is(err, ErrRecordNotFound) {
+		return CourseEnrollmentSetting{
+			CourseID: courseID,
+			Mode:     EnrollmentModeApproval,
+		}, nil
+	}
+	return settings, err
+}
+
+// hasOpenSeat reports whether a new student can take a seat right away, nobody may skip ahead of the waitlist
+func hasOpenSeat(ctx context.Context, q *Queries, settings CourseEnrollmentSetting) (bool, error) {
+	if settings.Capacity == 0 {
+		return true, nil
+	}
+
+	waitlisted, err := q.CountWaitlistedSubscriptions(ctx, settings.CourseID)
+	if err != nil {
+		return false, err
+	}
+	if waitlisted > 0 {
+		return false, nil
+	}
+
+	active, err := q.CountActiveSubscriptions(ctx, settings.CourseID)
+	if err != nil {
+		return false, err
+	}
+	return active < settings.Capacity, nil
+}
+
+// promoteWaitlist activates waitlisted subscriptions in order until the course is full or the waitlist is empty
+func promoteWaitlist(ctx context.Context, q *Queries, settings CourseEnrollmentSetting) ([]Subscription, error) {
+	active, err := q.CountActiveSubscriptions(ctx, settings.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := []Subscription{}
+	for settings.Capacity == 0 || active < settings.Capacity {
+		next, err := q.GetNextWaitlistedSubscription(ctx, settings.CourseID)
+		if errors.Is(err, ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		subscription, err := q.ActivateSubscription(ctx, next.SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, subscription)
+		active++
+	}
+
+	return promoted, nil
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultCourseProgressWeights is used for courses without weights of their own:
+// lessons, assignments and quizzes count the same
+var DefaultCourseProgressWeights = CourseProgressWeight{
+	MaterialWeight:   1,
+	AssignmentWeight: 1,
+	QuizWeight:       1,
+}
+
+// ComputeCourseProgress is the weighted share of the course a student has done, as a whole percentage.
+// Each kind of work counts by its weight, kinds the course has none of are left out,
+// so a course without any work is at 0 and a student only reaches 100 once everything is done.
+func ComputeCourseProgress(weights CourseProgressWeight, counts GetCourseProgressCountsRow) int64 {
+	parts := []struct {
+		weight, done, total int64
+	}{
+		{weights.MaterialWeight, counts.CompletedMaterials, counts.TotalMaterials},
+		{weights.AssignmentWeight, counts.SubmittedAssignments, counts.TotalAssignments},
+		{weights.QuizWeight, counts.SubmittedQuizzes, counts.TotalQuizzes},
+	}
+
+	var weighted, totalWeight float64
+	for _, part := range parts {
+		if part.total <= 0 || part.weight <= 0 {
+			continue
+		}
+		done := min(max(part.done, 0), part.total)
+		weighted += float64(part.weight) * float64(done) / float64(part.total)
+		totalWeight += float64(part.weight)
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+
+	return min(int64(math.Floor(weighted/totalWeight*100)), 100)
+}
+
+// courseProgressWeights returns the weights of a course, or the defaults when it has none
+func courseProgressWeights(ctx context.Context, q *Queries, courseID int64) (CourseProgressWeight, error) {
+	weights, err := q.GetCourseProgressWeights(ctx, courseID)
+	if errors.Is(err, ErrRecordNotFound) {
+		weights = DefaultCourseProgressWeights
+		weights.CourseID = courseID
+		return weights, nil
+	}
+	return weights, err
+}
+
+// recomputeCourseProgress stores the progress of students in a course from what they have done so far.
+// It is idempotent: running it again without new work stores the same progress. A student is marked
+// completed the first time they reach 100 and stays completed, afterComplete is only called then.
+// Without userIDs every learner of the course is recomputed.
+func recomputeCourseProgress(ctx context.Context, q *Queries, courseID int64, userIDs []int64, afterComplete func(CourseProgress) error) ([]CourseProgress, error) {
+	weights, err := courseProgressWeights(ctx, q, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		userIDs, err = q.ListCourseLearners(ctx, courseID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]CourseProgress, 0, len(userIDs))
+	for _, userID := range userIDs {
+		counts, err := q.GetCourseProgressCounts(ctx, GetCourseProgressCountsParams{
+			CourseID: courseID,
+			UserID:   userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		previous, err := q.GetCourseProgress(ctx, GetCourseProgressParams{
+			UserID:   userID,
+			CourseID: courseID,
+		})
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return nil, err
+		}
+
+		progress := ComputeCourseProgress(weights, counts)
+		var completedAt pgtype.Timestamptz
+		if progress >= 100 {
+			completedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+
+		courseProgress, err := q.UpsertCourseProgress(ctx, UpsertCourseProgressParams{
+			CourseID:    courseID,
+			UserID:      userID,
+			Progress:    progress,
+			CompletedAt: completedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, courseProgress)
+
+		if !previous.CompletedAt.Valid && courseProgress.CompletedAt.Valid && afterComplete != nil {
+			if err := afterComplete(courseProgress); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+type RecomputeCourseProgressTxParams struct {
+	CourseID int64
+	// UserIDs limits the recomputation to these students, every learner of the course is recomputed when empty
+	UserIDs       []int64
+	AfterComplete func(courseProgress CourseProgress) error
+}
+
+type RecomputeCourseProgressTxResult struct {
+	CourseProgress []CourseProgress
+}
+
+// RecomputeCourseProgressTx stores the progress of students in a course after its work or their work changed
+func (store *SQLStore) RecomputeCourseProgressTx(ctx context.Context, arg RecomputeCourseProgressTxParams) (RecomputeCourseProgressTxResult, error) {
+	var result RecomputeCourseProgressTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.CourseProgress, err = recomputeCourseProgress(ctx, q, arg.CourseID, arg.UserIDs, arg.AfterComplete)
+		return err
+	})
+
+	return result, err
+}
+
+type SetCourseProgressWeightsTxParams struct {
+	UpsertCourseProgressWeightsParams
+	AfterComplete func(courseProgress CourseProgress) error
+}
+
+type SetCourseProgressWeightsTxResult struct {
+	Weights        CourseProgressWeight
+	CourseProgress []CourseProgress
+}
+
+// SetCourseProgressWeightsTx changes how a course's work is weighted and recomputes the progress of its learners
+func (store *SQLStore) SetCourseProgressWeightsTx(ctx context.Context, arg SetCourseProgressWeightsTxParams) (SetCourseProgressWeightsTxResult, error) {
+	var result SetCourseProgressWeightsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Weights, err = q.UpsertCourseProgressWeights(ctx, arg.UpsertCourseProgressWeightsParams)
+		if err != nil {
+			return err
+		}
+
+		result.CourseProgress, err = recomputeCourseProgress(ctx, q, arg.CourseID, nil, arg.AfterComplete)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"eduApp/video"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrNotVideo is returned when watch time is recorded for a material that is not a video
+var ErrNotVideo = errors.New("material is not a video")
+
+// ErrVideoDurationUnset is returned for a heartbeat of a video whose length the instructor has not set yet
+var ErrVideoDurationUnset = errors.New("the length of this video has not been set yet")
+
+// ErrHeartbeatTooSoon is returned for a heartbeat sent less than video.MinHeartbeatInterval after the previous one
+var ErrHeartbeatTooSoon = errors.New("heartbeat sent too soon after the previous one")
+
+type RecordVideoHeartbeatTxParams struct {
+	UserID     int64
+	MaterialID int64
+	// Segment is the part of the video played since the previous heartbeat
+	Segment video.Segment
+	// Position is where playback is now, the student resumes from there
+	Position float64
+	// CompletePercent is the share of the video that has to be watched to complete the lesson
+	CompletePercent int64
+	// AfterComplete is called when completing the lesson completes the course for the student
+	AfterComplete func(courseProgress CourseProgress) error
+}
+
+type RecordVideoHeartbeatTxResult struct {
+	VideoWatch     VideoWatch
+	WatchedPercent int64
+	// Completed is true when this heartbeat completed the lesson
+	Completed      bool
+	CourseProgress CourseProgress
+}
+
+// RecordVideoHeartbeatTx merges a played segment into the student's watched segments and stores the resume position.
+// Watch time is measured against the length of the video set on the material, never against what the player reports.
+// Reported playback is checked against the real time that passed: a segment only counts as far as it could have been
+// played since the previous heartbeat, and the lesson cannot be completed sooner than the video can be watched.
+// The first time the watched share reaches CompletePercent the lesson is marked completed and the course progress recomputed.
+func (store *SQLStore) RecordVideoHeartbeatTx(ctx context.Context, arg RecordVideoHeartbeatTxParams) (RecordVideoHeartbeatTxResult, error) {
+	var result RecordVideoHeartbeatTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		material, err := q.GetMaterialByID(ctx, arg.MaterialID)
+		if err != nil {
+			return err
+		}
+		if material.MaterialType != "video" {
+			return ErrNotVideo
+		}
+		duration := material.DurationSeconds
+		if duration <= 0 {
+			return ErrVideoDurationUnset
+		}
+
+		err = q.CreateVideoWatch(ctx, CreateVideoWatchParams{
+			UserID:     arg.UserID,
+			MaterialID: arg.MaterialID,
+		})
+		if err != nil {
+			return err
+		}
+
+		watch, err := q.GetVideoWatchForUpdate(ctx, GetVideoWatchForUpdateParams{
+			UserID:     arg.UserID,
+			MaterialID: arg.MaterialID,
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var elapsed time.Duration
+		if watch.LastHeartbeatAt.Valid {
+			elapsed = now.Sub(watch.LastHeartbeatAt.Time)
+			if elapsed < video.MinHeartbeatInterval {
+				return ErrHeartbeatTooSoon
+			}
+		}
+		startedAt := now
+		if watch.StartedAt.Valid {
+			startedAt = watch.StartedAt.Time
+		}
+
+		segment := arg.Segment.Clamp(duration).Limit(elapsed)
+		segments := watch.Segments.Add(segment)
+		watched := segments.Watched()
+		result.WatchedPercent = video.WatchedPercent(watched, duration)
+
+		// completing the lesson takes at least as long as watching that share of the video
+		watchedLongEnough := now.Sub(startedAt) >= video.MinWatchTime(duration, arg.CompletePercent)
+
+		completedAt := watch.CompletedAt
+		if !completedAt.Valid && result.WatchedPercent >= arg.CompletePercent && watchedLongEnough {
+			completedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			result.Completed = true
+		}
+
+		result.VideoWatch, err = q.UpdateVideoWatch(ctx, UpdateVideoWatchParams{
+			WatchID:         watch.WatchID,
+			Segments:        segments,
+			WatchedSeconds:  watched,
+			DurationSeconds: duration,
+			PositionSeconds: math.Min(math.Max(arg.Position, 0), duration),
+			FurthestSeconds: segments.Furthest(),
+			CompletedAt:     completedAt,
+			LastHeartbeatAt: pgtype.Timestamptz{Time: now, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		if !result.Completed {
+			return nil
+		}
+
+		_, err = q.CreateLessonCompletion(ctx, CreateLessonCompletionParams{
+			CourseID:   material.CourseID,
+			UserID:     arg.UserID,
+			MaterialID: arg.MaterialID,
+			Completed:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		progress, err := recomputeCourseProgress(ctx, q, material.CourseID, []int64{arg.UserID}, arg.AfterComplete)
+		if err != nil {
+			return err
+		}
+		result.CourseProgress = progress[0]
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInviteNotFound is returned for a code that does not belong to any invite
+	ErrInviteNotFound = errors.New("invite code not found")
+	// ErrInviteRevoked is returned for a code an admin took back
+	ErrInviteRevoked = errors.New("invite code was revoked")
+	// ErrInviteExpired is returned for a code past its expiry
+	ErrInviteExpired = errors.New("invite code has expired")
+	// ErrInviteUsedUp is returned for a code that enrolled as many students as it allows
+	ErrInviteUsedUp = errors.New("invite code has no uses left")
+)
+
+type RedeemCourseInviteTxParams struct {
+	Code   string
+	UserID int64
+	Now    time.Time
+	// AfterCreate runs inside the transaction when it is set
+	AfterCreate func(subscription Subscription) error
+}
+
+type RedeemCourseInviteTxResult struct {
+	Invite       CourseInvite
+	Subscription Subscription
+}
+
+// RedeemCourseInviteTx enrolls a student with an invite code. The invite row is locked so a code
+// with limited uses cannot be redeemed more often than allowed, the student still needs a free seat.
+func (store *SQLStore) RedeemCourseInviteTx(ctx context.Context, arg RedeemCourseInviteTxParams) (RedeemCourseInviteTxResult, error) {
+	var result RedeemCourseInviteTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		invite, err := q.GetCourseInviteByCodeForUpdate(ctx, arg.Code)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrInviteNotFound
+			}
+			return err
+		}
+
+		switch {
+		case invite.RevokedAt.Valid:
+			return ErrInviteRevoked
+		case invite.ExpiresAt.Valid && !arg.Now.Before(invite.ExpiresAt.Time):
+			return ErrInviteExpired
+		case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+			return ErrInviteUsedUp
+		}
+
+		result.Subscription, err = enrollStudent(ctx, q, CreateSubscriptionTxParams{
+			UserID:   arg.UserID,
+			CourseID: invite.CourseID,
+			Now:      arg.Now,
+			Invited:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.UseCourseInvite(ctx, invite.InviteID); err != nil {
+			return err
+		}
+		invite.Uses++
+		result.Invite = invite
+
+		if arg.AfterCreate == nil {
+			return nil
+		}
+		return arg.AfterCreate(result.Subscription)
+	})
+
+	return result, err
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrOrderNotPaid is returned when refunding an order that was never paid
+var ErrOrderNotPaid = errors.New("order was not paid")
+
+type RefundOrderTxResult struct {
+	Order Order
+	// Refunded is false when the order was refunded before
+	Refunded bool
+	// Promoted are the waitlisted subscriptions that got the seat of the refunded student
+	Promoted []Subscription
+}
+
+// RefundOrderTx marks an order refunded and takes the course away from its student, the freed seat goes to the waitlist.
+// Refunding a renewal only takes back the access period it added.
+func (store *SQLStore) RefundOrderTx(ctx context.Context, orderID int64) (RefundOrderTxResult, error) {
+	result := RefundOrderTxResult{Promoted: []Subscription{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Order, err = q.GetOrderForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		switch result.Order.Status {
+		case OrderStatusRefunded:
+			return nil
+		case OrderStatusPaid:
+		default:
+			return ErrOrderNotPaid
+		}
+
+		result.Order, err = q.MarkOrderRefunded(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		result.Refunded = true
+
+		settings, err := lockEnrollmentSettings(ctx, q, result.Order.CourseID)
+		if err != nil {
+			return err
+		}
+
+		subscription, err := q.GetCourseSubscriptionForUpdate(ctx, GetCourseSubscriptionForUpdateParams{
+			UserID:   result.Order.UserID,
+			CourseID: result.Order.CourseID,
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// a refunded renewal only takes back the access it added, the student keeps what was paid for before
+		if result.Order.RenewedFrom.Valid {
+			_, err = q.RevertSubscriptionRenewal(ctx, RevertSubscriptionRenewalParams{
+				RenewedUntil:   result.Order.RenewedUntil,
+				RenewedFrom:    result.Order.RenewedFrom.Time,
+				SubscriptionID: subscription.SubscriptionID,
+			})
+			return err
+		}
+
+		if _, err := q.DeactivateSubscription(ctx, subscription.SubscriptionID); err != nil {
+			return err
+		}
+
+		result.Promoted, err = promoteWaitlist(ctx, q, settings)
+		return err
+	})
+
+	return result, err
+}
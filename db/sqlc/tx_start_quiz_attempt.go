@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNoQuizAttemptsLeft is returned when the student used every attempt the quiz allows
+	ErrNoQuizAttemptsLeft = errors.New("no attempts left for this quiz")
+	// ErrQuizAttemptInProgress is returned when another request started an attempt at the same time
+	ErrQuizAttemptInProgress = errors.New("an attempt of this quiz is already in progress")
+)
+
+type StartQuizAttemptTxParams struct {
+	CreateQuizAttemptParams
+	CourseID int64
+	// MaxAttempts limits the attempts of the student, zero allows any number
+	MaxAttempts int64
+}
+
+type StartQuizAttemptTxResult struct {
+	QuizAttempt QuizAttempt
+	// Resumed is set when the student already had an attempt in progress, which is returned instead
+	Resumed bool
+}
+
+// StartQuizAttemptTx opens a new attempt unless one is in progress. The student's subscription row is locked, so
+// concurrent starts are counted one after the other, and the unique index on attempts in progress stops a second
+// one for students without a subscription row.
+func (store *SQLStore) StartQuizAttemptTx(ctx context.Context, arg StartQuizAttemptTxParams) (StartQuizAttemptTxResult, error) {
+	var result StartQuizAttemptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetCourseSubscriptionForUpdate(ctx, GetCourseSubscriptionForUpdateParams{
+			UserID:   arg.UserID,
+			CourseID: arg.CourseID,
+		})
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		attempt, err := q.GetInProgressQuizAttempt(ctx, GetInProgressQuizAttemptParams{
+			QuizID: arg.QuizID,
+			UserID: arg.UserID,
+		})
+		if err == nil {
+			result.QuizAttempt = attempt
+			result.Resumed = true
+			return nil
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		if arg.MaxAttempts > 0 {
+			count, err := q.CountQuizAttempts(ctx, CountQuizAttemptsParams{
+				QuizID: arg.QuizID,
+				UserID: arg.UserID,
+			})
+			if err != nil {
+				return err
+			}
+			if count >= arg.MaxAttempts {
+				return ErrNoQuizAttemptsLeft
+			}
+		}
+
+		result.QuizAttempt, err = q.CreateQuizAttempt(ctx, arg.CreateQuizAttemptParams)
+		if ErrorCode(err) == UniqueViolations {
+			return ErrQuizAttemptInProgress
+		}
+		return err
+	})
+
+	return result, err
+}
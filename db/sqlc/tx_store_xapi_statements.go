@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"eduApp/xapi"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrStatementConflict is returned for a statement whose ID the LRS already stores for a different statement
+var ErrStatementConflict = errors.New("a different statement with this id is stored already")
+
+// XAPIStatementRecord is a statement ready to be stored by the LRS
+type XAPIStatementRecord struct {
+	CreateXAPIStatementParams
+	// Voids is the statement a voiding statement voids
+	Voids pgtype.UUID
+}
+
+// NewXAPIStatementRecord indexes a statement prepared by xapi.Prepare for storage
+func NewXAPIStatementRecord(statement xapi.Statement, document []byte, stored time.Time) (XAPIStatementRecord, error) {
+	id, err := uuid.Parse(statement.ID)
+	if err != nil {
+		return XAPIStatementRecord{}, err
+	}
+
+	record := XAPIStatementRecord{
+		CreateXAPIStatementParams: CreateXAPIStatementParams{
+			StatementID: pgtype.UUID{Bytes: id, Valid: true},
+			Actor:       xapi.AgentIdentifier(statement.Actor),
+			VerbID:      statement.Verb.ID,
+			Statement:   document,
+			Stored:      stored,
+		},
+	}
+
+	switch statement.Object.ObjectType {
+	case "", xapi.ObjectActivity:
+		record.ActivityID = statement.Object.ID
+	case xapi.ObjectStatementRef:
+		if statement.Verb.ID == xapi.VerbVoided.ID {
+			target, err := uuid.Parse(statement.Object.ID)
+			if err != nil {
+				return XAPIStatementRecord{}, err
+			}
+			record.Voids = pgtype.UUID{Bytes: target, Valid: true}
+		}
+	}
+
+	return record, nil
+}
+
+type StoreXAPIStatementsTxParams struct {
+	Statements []XAPIStatementRecord
+}
+
+type StoreXAPIStatementsTxResult struct {
+	// Stored is how many of the statements were new, the others were stored already
+	Stored int
+}
+
+// StoreXAPIStatementsTx stores a batch of statements, all of them or none. A statement stored already is skipped,
+// a different statement with the same ID fails the batch. Voiding statements void the statement they reference.
+func (store *SQLStore) StoreXAPIStatementsTx(ctx context.Context, arg StoreXAPIStatementsTxParams) (StoreXAPIStatementsTxResult, error) {
+	var result StoreXAPIStatementsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, record := range arg.Statements {
+			existing, err := q.GetXAPIStatement(ctx, record.StatementID)
+			if err == nil {
+				if existing.Actor != record.Actor || existing.VerbID != record.VerbID || existing.ActivityID != record.ActivityID {
+					return ErrStatementConflict
+				}
+				continue
+			}
+			if !errors.Is(err, ErrRecordNotFound) {
+				return err
+			}
+
+			if err := q.CreateXAPIStatement(ctx, record.CreateXAPIStatementParams); err != nil {
+				return err
+			}
+			result.Stored++
+
+			if record.Voids.Valid {
+				if err := q.VoidXAPIStatement(ctx, record.Voids); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+type SubmitQuizAttemptTxParams struct {
+	SubmitQuizAttemptParams
+	CourseID int64
+	// AfterComplete is called when this quiz completes the course for the student
+	AfterComplete func(courseProgress CourseProgress) error
+}
+
+type SubmitQuizAttemptTxResult struct {
+	QuizAttempt    QuizAttempt
+	Mark           Mark
+	CourseProgress CourseProgress
+}
+
+// SubmitQuizAttemptTx stores a graded attempt, refreshes the student's course mark from their best assessment scores
+// and recomputes their course progress
+func (store *SQLStore) SubmitQuizAttemptTx(ctx context.Context, arg SubmitQuizAttemptTxParams) (SubmitQuizAttemptTxResult, error) {
+	var result SubmitQuizAttemptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.QuizAttempt, err = q.SubmitQuizAttempt(ctx, arg.SubmitQuizAttemptParams)
+		if err != nil {
+			return err
+		}
+
+		result.Mark, err = updateCourseMark(ctx, q, arg.CourseID, result.QuizAttempt.UserID)
+		if err != nil {
+			return err
+		}
+
+		progress, err := recomputeCourseProgress(ctx, q, arg.CourseID, []int64{result.QuizAttempt.UserID}, arg.AfterComplete)
+		if err != nil {
+			return err
+		}
+		result.CourseProgress = progress[0]
+		return nil
+	})
+
+	return result, err
+}
+
+// updateCourseMark sets the student's course mark to the average of their best quiz scores and SCORM package scores
+func updateCourseMark(ctx context.Context, q *Queries, courseID int64, userID int64) (Mark, error) {
+	marks, err := q.GetCourseAssessmentMark(ctx, GetCourseAssessmentMarkParams{
+		CourseID: courseID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return Mark{}, err
+	}
+
+	mark, err := q.GetMarkByCourseAndUser(ctx, GetMarkByCourseAndUserParams{
+		CourseID: courseID,
+		UserID:   userID,
+	})
+	if errors.Is(err, ErrRecordNotFound) {
+		return q.CreateMark(ctx, CreateMarkParams{
+			CourseID: courseID,
+			UserID:   userID,
+			Marks:    marks,
+		})
+	}
+	if err != nil {
+		return Mark{}, err
+	}
+
+	return q.UpdateMark(ctx, UpdateMarkParams{
+		MarkID:   mark.MarkID,
+		Marks:    marks,
+		UserID:   mark.UserID,
+		CourseID: mark.CourseID,
+	})
+}
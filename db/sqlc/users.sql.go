@@ -38,7 +38,7 @@ INSERT INTO users (
     is_email_verified
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7
-) RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at
+) RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale
 `
 
 type CreateUserParams struct {
@@ -74,6 +74,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -113,7 +114,7 @@ func (q *Queries) GetTotalUserCount(ctx context.Context, role string) (int64, er
 }
 
 const getUser = `-- name: GetUser :one
-SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at FROM users
+SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale FROM users
 WHERE user_name = $1 LIMIT 1
 `
 
@@ -132,12 +133,40 @@ func (q *Queries) GetUser(ctx context.Context, userName string) (User, error) {
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale FROM users
+WHERE lower(email) = lower($1)
+ORDER BY user_id
+LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.IsEmailVerified,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at FROM users
+SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale FROM users
 WHERE user_id = $1 LIMIT 1
 `
 
@@ -156,12 +185,13 @@ func (q *Queries) GetUserByID(ctx context.Context, userID int64) (User, error) {
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const listUser = `-- name: ListUser :many
-SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at FROM users
+SELECT user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale FROM users
 WHERE role = $1
 ORDER BY user_id
 LIMIT $2
@@ -195,6 +225,7 @@ func (q *Queries) ListUser(ctx context.Context, arg ListUserParams) ([]User, err
 			&i.Role,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Locale,
 		); err != nil {
 			return nil, err
 		}
@@ -232,7 +263,7 @@ SET
     role = COALESCE($8, role)
 WHERE
     user_id = $9
-RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at
+RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale
 `
 
 type UpdateUserParams struct {
@@ -272,6 +303,39 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const updateUserLocale = `-- name: UpdateUserLocale :one
+UPDATE users
+SET locale = $1
+WHERE user_id = $2
+RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale
+`
+
+type UpdateUserLocaleParams struct {
+	Locale string `json:"locale"`
+	UserID int64  `json:"user_id"`
+}
+
+func (q *Queries) UpdateUserLocale(ctx context.Context, arg UpdateUserLocaleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserLocale, arg.Locale, arg.UserID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.IsEmailVerified,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -284,7 +348,7 @@ SET
     updated_at = COALESCE($3, updated_at)
 WHERE 
     email = $4
-RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at
+RETURNING user_id, user_name, first_name, last_name, email, is_email_verified, hashed_password, password_changed_at, role, created_at, updated_at, locale
 `
 
 type UpdateUsersPasswordParams struct {
@@ -314,6 +378,7 @@ func (q *Queries) UpdateUsersPassword(ctx context.Context, arg UpdateUsersPasswo
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
 	)
 	return i, err
 }
@@ -0,0 +1,39 @@
+package enrollment
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// inviteAlphabet leaves out letters and digits that are easily mixed up when a code is typed in
+const inviteAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// inviteCodeLength gives about 50 bits of randomness
+const inviteCodeLength = 10
+
+// NewInviteCode returns a random course invite code
+func NewInviteCode() (string, error) {
+	max := big.NewInt(int64(len(inviteAlphabet)))
+
+	var sb strings.Builder
+	for i := 0; i < inviteCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(inviteAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// NormalizeInviteCode turns a code as typed or pasted by a student into the stored form
+func NormalizeInviteCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(code))
+}
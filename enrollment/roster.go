@@ -0,0 +1,151 @@
+package enrollment
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"unicode"
+)
+
+// maxRosterRows limits the size of a single bulk enrollment
+const maxRosterRows = 5000
+
+// states of a bulk enrollment job
+const (
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// outcomes of a roster row
+const (
+	RowEnrolled        = "enrolled"
+	RowWaitlisted      = "waitlisted"
+	RowAlreadyEnrolled = "already_enrolled"
+	RowFailed          = "failed"
+)
+
+// RosterRow is a student read from a roster file, rows are counted from 1 with the header as row 1
+type RosterRow struct {
+	Row       int    `json:"row"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	UserName  string `json:"user_name,omitempty"`
+}
+
+// ImportRows are the rows of a roster waiting to be enrolled
+type ImportRows []RosterRow
+
+// RowResult reports what a bulk enrollment did with a roster row
+type RowResult struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	UserID int64  `json:"user_id,omitempty"`
+	Status string `json:"status"`
+	// AccountCreated is set when the student had no account and was invited by email
+	AccountCreated bool   `json:"account_created"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ImportResults are the row results of a bulk enrollment in row order
+type ImportResults []RowResult
+
+// ParseRosterCSV reads students from a CSV file with a header row. The email column is required, first_name,
+// last_name and user_name are used when an account has to be created. Rows that cannot be used are returned
+// as failed results, an error is returned only when the file itself is unusable.
+func ParseRosterCSV(r io.Reader) (ImportRows, ImportResults, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("the file is empty")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, nil, errors.New("missing email column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := ImportRows{}
+	failed := ImportResults{}
+	seen := make(map[string]int)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				failed = append(failed, RowResult{Row: row, Status: RowFailed, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		if row-1 > maxRosterRows {
+			return nil, nil, fmt.Errorf("a bulk enrollment is limited to %d rows", maxRosterRows)
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		email := strings.ToLower(field(record, "email"))
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			failed = append(failed, RowResult{Row: row, Email: email, Status: RowFailed, Error: fmt.Sprintf("invalid email %q", email)})
+			continue
+		}
+		if first, ok := seen[email]; ok {
+			failed = append(failed, RowResult{Row: row, Email: email, Status: RowFailed, Error: fmt.Sprintf("the same email is already on row %d", first)})
+			continue
+		}
+		seen[email] = row
+
+		rows = append(rows, RosterRow{
+			Row:       row,
+			Email:     email,
+			FirstName: field(record, "first_name"),
+			LastName:  field(record, "last_name"),
+			UserName:  field(record, "user_name"),
+		})
+	}
+
+	return rows, failed, nil
+}
+
+// UserNameFromEmail suggests a user name for an invited student from the letters and digits of their email address
+func UserNameFromEmail(email string) string {
+	local, _, _ := strings.Cut(email, "@")
+
+	var sb strings.Builder
+	for _, r := range local {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		}
+	}
+	for sb.Len() < 3 {
+		sb.WriteString("user")
+	}
+	return sb.String()
+}
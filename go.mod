@@ -14,6 +14,7 @@ require (
 	github.com/jackc/pgx v3.6.2+incompatible
 	github.com/jackc/pgx/v5 v5.5.5
 	github.com/jordan-wright/email v4.0.1-0.20210109023952-943e75fe5223+incompatible
+	github.com/ledongthuc/pdf v0.0.0-20220302134840-0c2507a12d80
 	github.com/lib/pq v1.10.9 // indirect
 	github.com/rs/zerolog v1.32.0
 	github.com/spf13/viper v1.19.0
@@ -22,7 +23,9 @@ require (
 
 require (
 	github.com/gofrs/uuid v4.0.0+incompatible
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/swaggo/swag v1.16.3
+	golang.org/x/image v0.18.0
 )
 
 require (
@@ -89,7 +92,7 @@ require (
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/arch v0.8.0 // indirect
 	golang.org/x/exp v0.0.0-20240613232115-7f521ea00fb8 // indirect
-	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/net v0.26.0
 	golang.org/x/sync v0.7.0
 	golang.org/x/sys v0.21.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
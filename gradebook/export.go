@@ -0,0 +1,63 @@
+package gradebook
+
+import (
+	"strconv"
+)
+
+// Student is a row of the gradebook
+type Student struct {
+	UserID    int64  `json:"user_id"`
+	UserName  string `json:"user_name"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Scores    Scores `json:"scores"`
+	Result    Result `json:"result"`
+}
+
+// Sheet lays the gradebook out as rows of cells: one column per item, then the category and final percentages
+func Sheet(categories []Category, items []Item, students []Student) [][]string {
+	header := []string{"user_id", "user_name", "first_name", "last_name"}
+	for _, item := range items {
+		header = append(header, item.Title+" ("+formatNumber(item.MaxPoints)+")")
+	}
+	for _, category := range categories {
+		header = append(header, category.Title+" %")
+	}
+	header = append(header, "final %", "letter")
+
+	rows := make([][]string, 0, len(students)+1)
+	rows = append(rows, header)
+	for _, student := range students {
+		row := []string{
+			strconv.FormatInt(student.UserID, 10),
+			student.UserName,
+			student.FirstName,
+			student.LastName,
+		}
+		for _, item := range items {
+			if points, ok := student.Scores[item.ID]; ok {
+				row = append(row, formatNumber(points))
+			} else {
+				row = append(row, "")
+			}
+		}
+		for _, category := range student.Result.Categories {
+			row = append(row, formatPercentage(category.Percentage))
+		}
+		row = append(row, formatPercentage(student.Result.Percentage), student.Result.Letter)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatPercentage(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatNumber(*value)
+}
@@ -0,0 +1,143 @@
+package gradebook
+
+import (
+	"math"
+	"sort"
+)
+
+// grade item types
+const (
+	ItemAssignment = "assignment"
+	ItemQuiz       = "quiz"
+	ItemManual     = "manual"
+)
+
+// Category groups grade items, its weight is its share of the final grade
+type Category struct {
+	ID     int64
+	Title  string
+	Weight float64
+	// DropLowest is how many of the lowest graded items are left out, at least one item is always kept
+	DropLowest int
+}
+
+// Item is a graded piece of work worth MaxPoints
+type Item struct {
+	ID         int64
+	CategoryID int64
+	Title      string
+	MaxPoints  float64
+}
+
+// Scores are the points a student earned, keyed by item ID. Items without a score are not graded yet
+type Scores map[int64]float64
+
+// CategoryResult is a student's standing in one category
+type CategoryResult struct {
+	CategoryID int64    `json:"category_id"`
+	Title      string   `json:"title"`
+	Weight     float64  `json:"weight"`
+	Percentage *float64 `json:"percentage"`
+	Dropped    []int64  `json:"dropped_item_ids"`
+}
+
+// Result is a student's computed grade, percentages are nil until something is graded
+type Result struct {
+	Categories []CategoryResult `json:"categories"`
+	Percentage *float64         `json:"percentage"`
+	Letter     string           `json:"letter"`
+}
+
+// Compute works out a student's grade. Within a category the earned points of the kept items are divided by
+// their possible points. Categories are then combined by weight, leaving out categories with nothing graded.
+// When no graded category has a weight the categories count equally.
+func Compute(categories []Category, items []Item, scores Scores, scale Scale) Result {
+	byCategory := make(map[int64][]Item, len(categories))
+	for _, item := range items {
+		byCategory[item.CategoryID] = append(byCategory[item.CategoryID], item)
+	}
+
+	result := Result{Categories: make([]CategoryResult, 0, len(categories))}
+
+	var weighted, weights, plain float64
+	var graded int
+	for _, category := range categories {
+		categoryResult := CategoryResult{
+			CategoryID: category.ID,
+			Title:      category.Title,
+			Weight:     category.Weight,
+			Dropped:    []int64{},
+		}
+
+		percentage, dropped, ok := categoryPercentage(category, byCategory[category.ID], scores)
+		if ok {
+			categoryResult.Percentage = &percentage
+			categoryResult.Dropped = dropped
+
+			weighted += category.Weight * percentage
+			weights += category.Weight
+			plain += percentage
+			graded++
+		}
+
+		result.Categories = append(result.Categories, categoryResult)
+	}
+
+	if graded == 0 {
+		return result
+	}
+
+	var percentage float64
+	if weights > 0 {
+		percentage = round(weighted / weights)
+	} else {
+		percentage = round(plain / float64(graded))
+	}
+	result.Percentage = &percentage
+	result.Letter = scale.Letter(percentage)
+
+	return result
+}
+
+type gradedItem struct {
+	id        int64
+	points    float64
+	maxPoints float64
+}
+
+func categoryPercentage(category Category, items []Item, scores Scores) (float64, []int64, bool) {
+	graded := make([]gradedItem, 0, len(items))
+	for _, item := range items {
+		points, ok := scores[item.ID]
+		if !ok || item.MaxPoints <= 0 {
+			continue
+		}
+		graded = append(graded, gradedItem{id: item.ID, points: points, maxPoints: item.MaxPoints})
+	}
+	if len(graded) == 0 {
+		return 0, nil, false
+	}
+
+	sort.SliceStable(graded, func(i, j int) bool {
+		return graded[i].points/graded[i].maxPoints < graded[j].points/graded[j].maxPoints
+	})
+
+	drop := min(max(category.DropLowest, 0), len(graded)-1)
+	dropped := make([]int64, 0, drop)
+	for _, item := range graded[:drop] {
+		dropped = append(dropped, item.id)
+	}
+
+	var points, maxPoints float64
+	for _, item := range graded[drop:] {
+		points += item.points
+		maxPoints += item.maxPoints
+	}
+
+	return round(points * 100 / maxPoints), dropped, true
+}
+
+// round keeps two decimals
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
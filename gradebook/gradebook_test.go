@@ -0,0 +1,221 @@
+package gradebook
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	testCases := []struct {
+		name           string
+		categories     []Category
+		items          []Item
+		scores         Scores
+		wantPercentage *float64
+		wantLetter     string
+		// wantCategories are the category percentages in order, nil for a category with nothing graded
+		wantCategories []*float64
+		wantDropped    [][]int64
+	}{
+		{
+			name:       "weights not summing to 100 are normalised",
+			categories: []Category{{ID: 1, Weight: 30}, {ID: 2, Weight: 30}},
+			items:      []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 20, CategoryID: 2, MaxPoints: 10}},
+			scores:     Scores{10: 8, 20: 5},
+			// (30*80 + 30*50) / 60
+			wantPercentage: percent(65), wantLetter: "D",
+			wantCategories: []*float64{percent(80), percent(50)},
+		},
+		{
+			name:       "weights over 100",
+			categories: []Category{{ID: 1, Weight: 150}, {ID: 2, Weight: 50}},
+			items:      []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 20, CategoryID: 2, MaxPoints: 10}},
+			scores:     Scores{10: 10, 20: 6},
+			// (150*100 + 50*60) / 200
+			wantPercentage: percent(90), wantLetter: "A",
+			wantCategories: []*float64{percent(100), percent(60)},
+		},
+		{
+			name:           "no weights count categories equally",
+			categories:     []Category{{ID: 1}, {ID: 2}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 20, CategoryID: 2, MaxPoints: 10}},
+			scores:         Scores{10: 8, 20: 5},
+			wantPercentage: percent(65), wantLetter: "D",
+			wantCategories: []*float64{percent(80), percent(50)},
+		},
+		{
+			name:           "ungraded category is left out",
+			categories:     []Category{{ID: 1, Weight: 40}, {ID: 2, Weight: 60}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 20, CategoryID: 2, MaxPoints: 10}},
+			scores:         Scores{10: 8},
+			wantPercentage: percent(80), wantLetter: "B",
+			wantCategories: []*float64{percent(80), nil},
+		},
+		{
+			name:       "drop lowest by percentage, not by points",
+			categories: []Category{{ID: 1, Weight: 100, DropLowest: 1}},
+			items: []Item{
+				{ID: 10, CategoryID: 1, MaxPoints: 10},
+				{ID: 11, CategoryID: 1, MaxPoints: 20},
+				{ID: 12, CategoryID: 1, MaxPoints: 10},
+			},
+			scores: Scores{10: 5, 11: 18, 12: 7},
+			// 5/10 is dropped although 7 out of 10 has more points, (18+7) / 30
+			wantPercentage: percent(83.33), wantLetter: "B",
+			wantCategories: []*float64{percent(83.33)},
+			wantDropped:    [][]int64{{10}},
+		},
+		{
+			name:           "drop lowest with fewer items than drops keeps the best item",
+			categories:     []Category{{ID: 1, Weight: 100, DropLowest: 3}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 11, CategoryID: 1, MaxPoints: 10}},
+			scores:         Scores{10: 4, 11: 9},
+			wantPercentage: percent(90), wantLetter: "A",
+			wantCategories: []*float64{percent(90)},
+			wantDropped:    [][]int64{{10}},
+		},
+		{
+			name:           "drop lowest with a single graded item drops nothing",
+			categories:     []Category{{ID: 1, Weight: 100, DropLowest: 2}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 11, CategoryID: 1, MaxPoints: 10}},
+			scores:         Scores{11: 3},
+			wantPercentage: percent(30), wantLetter: "F",
+			wantCategories: []*float64{percent(30)},
+			wantDropped:    [][]int64{{}},
+		},
+		{
+			name:           "missing scores are not counted as zero",
+			categories:     []Category{{ID: 1, Weight: 100}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}, {ID: 11, CategoryID: 1, MaxPoints: 20}},
+			scores:         Scores{10: 7},
+			wantPercentage: percent(70), wantLetter: "C",
+			wantCategories: []*float64{percent(70)},
+		},
+		{
+			name:           "items worth nothing are ignored",
+			categories:     []Category{{ID: 1, Weight: 100}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 0}, {ID: 11, CategoryID: 1, MaxPoints: 10}},
+			scores:         Scores{10: 5, 11: 6},
+			wantPercentage: percent(60), wantLetter: "D",
+			wantCategories: []*float64{percent(60)},
+		},
+		{
+			name:           "nothing graded",
+			categories:     []Category{{ID: 1, Weight: 50}, {ID: 2, Weight: 50}},
+			items:          []Item{{ID: 10, CategoryID: 1, MaxPoints: 10}},
+			scores:         Scores{},
+			wantCategories: []*float64{nil, nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Compute(tc.categories, tc.items, tc.scores, DefaultScale)
+
+			if !equalPercent(result.Percentage, tc.wantPercentage) {
+				t.Errorf("percentage = %v, want %v", show(result.Percentage), show(tc.wantPercentage))
+			}
+			if result.Letter != tc.wantLetter {
+				t.Errorf("letter = %q, want %q", result.Letter, tc.wantLetter)
+			}
+			if len(result.Categories) != len(tc.wantCategories) {
+				t.Fatalf("got %d categories, want %d", len(result.Categories), len(tc.wantCategories))
+			}
+			for i, category := range result.Categories {
+				if category.CategoryID != tc.categories[i].ID {
+					t.Errorf("category %d is %d, want %d", i, category.CategoryID, tc.categories[i].ID)
+				}
+				if !equalPercent(category.Percentage, tc.wantCategories[i]) {
+					t.Errorf("category %d percentage = %v, want %v", category.CategoryID, show(category.Percentage), show(tc.wantCategories[i]))
+				}
+				wantDropped := []int64{}
+				if i < len(tc.wantDropped) {
+					wantDropped = tc.wantDropped[i]
+				}
+				if !reflect.DeepEqual(category.Dropped, wantDropped) {
+					t.Errorf("category %d dropped = %v, want %v", category.CategoryID, category.Dropped, wantDropped)
+				}
+			}
+		})
+	}
+}
+
+func TestScaleLetter(t *testing.T) {
+	scale := Scale{{Letter: "Pass", MinPercent: 50}, {Letter: "Fail", MinPercent: 0}}
+
+	testCases := []struct {
+		scale      Scale
+		percentage float64
+		want       string
+	}{
+		{scale: scale, percentage: 50, want: "Pass"},
+		{scale: scale, percentage: 49.99, want: "Fail"},
+		{scale: scale, percentage: -5, want: "Fail"},
+		{scale: nil, percentage: 89.99, want: "B"},
+		{scale: nil, percentage: 100, want: "A"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.scale.Letter(tc.percentage); got != tc.want {
+			t.Errorf("Letter(%v) = %q, want %q", tc.percentage, got, tc.want)
+		}
+	}
+}
+
+func TestScaleValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		scale   Scale
+		wantErr bool
+	}{
+		{name: "default", scale: DefaultScale},
+		{name: "empty", scale: Scale{}, wantErr: true},
+		{name: "no zero", scale: Scale{{Letter: "A", MinPercent: 50}}, wantErr: true},
+		{name: "duplicate letter", scale: Scale{{Letter: "A", MinPercent: 50}, {Letter: "A", MinPercent: 0}}, wantErr: true},
+		{name: "duplicate minimum", scale: Scale{{Letter: "A", MinPercent: 0}, {Letter: "B", MinPercent: 0}}, wantErr: true},
+		{name: "blank letter", scale: Scale{{Letter: " ", MinPercent: 0}}, wantErr: true},
+		{name: "above 100", scale: Scale{{Letter: "A", MinPercent: 101}, {Letter: "F", MinPercent: 0}}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.scale.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSheet(t *testing.T) {
+	categories := []Category{{ID: 1, Title: "Homework", Weight: 100}}
+	items := []Item{{ID: 10, CategoryID: 1, Title: "Essay", MaxPoints: 12.5}, {ID: 11, CategoryID: 1, Title: "Lab", MaxPoints: 10}}
+	scores := Scores{10: 10}
+	students := []Student{{UserID: 7, UserName: "ada", FirstName: "Ada", LastName: "Lovelace", Scores: scores,
+		Result: Compute(categories, items, scores, DefaultScale)}}
+
+	want := [][]string{
+		{"user_id", "user_name", "first_name", "last_name", "Essay (12.5)", "Lab (10)", "Homework %", "final %", "letter"},
+		{"7", "ada", "Ada", "Lovelace", "10", "", "80", "80", "B"},
+	}
+	if got := Sheet(categories, items, students); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sheet() = %q, want %q", got, want)
+	}
+}
+
+func percent(value float64) *float64 {
+	return &value
+}
+
+func equalPercent(got, want *float64) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return *got == *want
+}
+
+func show(value *float64) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
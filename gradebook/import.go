@@ -0,0 +1,132 @@
+package gradebook
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxImportRows limits the size of a single score import
+const maxImportRows = 10000
+
+// ImportRow is a manual score read from an import file. The student is identified by UserID or Email
+type ImportRow struct {
+	Row     int
+	UserID  int64
+	Email   string
+	ItemID  int64
+	Points  float64
+	Comment string
+}
+
+// RowError reports why a row of an import file was rejected, rows are the line of the file the record starts on
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ParseScoresCSV reads manual scores from a CSV file with a header row. The columns item_id and points are
+// required together with user_id or email, a comment column is optional. Rows that cannot be read are
+// reported as row errors, an error is returned only when the file itself is unusable.
+func ParseScoresCSV(r io.Reader) ([]ImportRow, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("the file is empty")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"item_id", "points"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("missing %s column", name)
+		}
+	}
+	_, hasUserID := columns["user_id"]
+	_, hasEmail := columns["email"]
+	if !hasUserID && !hasEmail {
+		return nil, nil, errors.New("missing user_id or email column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []ImportRow
+	var rowErrors []RowError
+	for count := 1; ; count++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, RowError{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		if count > maxImportRows {
+			return nil, nil, fmt.Errorf("an import is limited to %d rows", maxImportRows)
+		}
+
+		// the reader skips blank lines, rows are reported by the line they start on
+		row, _ := reader.FieldPos(0)
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		importRow := ImportRow{
+			Row:     row,
+			Email:   strings.ToLower(field(record, "email")),
+			Comment: field(record, "comment"),
+		}
+
+		if value := field(record, "user_id"); value != "" {
+			importRow.UserID, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || importRow.UserID < 1 {
+				rowErrors = append(rowErrors, RowError{Row: row, Error: fmt.Sprintf("invalid user_id %q", value)})
+				continue
+			}
+		}
+		if importRow.UserID == 0 && importRow.Email == "" {
+			rowErrors = append(rowErrors, RowError{Row: row, Error: "user_id or email is required"})
+			continue
+		}
+
+		value := field(record, "item_id")
+		importRow.ItemID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || importRow.ItemID < 1 {
+			rowErrors = append(rowErrors, RowError{Row: row, Error: fmt.Sprintf("invalid item_id %q", value)})
+			continue
+		}
+
+		value = field(record, "points")
+		importRow.Points, err = strconv.ParseFloat(value, 64)
+		if err != nil || importRow.Points < 0 {
+			rowErrors = append(rowErrors, RowError{Row: row, Error: fmt.Sprintf("invalid points %q", value)})
+			continue
+		}
+
+		rows = append(rows, importRow)
+	}
+
+	return rows, rowErrors, nil
+}
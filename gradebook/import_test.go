@@ -0,0 +1,90 @@
+package gradebook
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScoresCSV(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		wantRows   []ImportRow
+		wantErrors []RowError
+		wantErr    string
+	}{
+		{
+			name:  "user ids and emails",
+			input: "\ufeffUser_ID, Email ,item_id,points,comment\n5,,10,7.5,Well done\n,Ada@Example.com,11,0,\n",
+			wantRows: []ImportRow{
+				{Row: 2, UserID: 5, ItemID: 10, Points: 7.5, Comment: "Well done"},
+				{Row: 3, Email: "ada@example.com", ItemID: 11, Points: 0},
+			},
+		},
+		{
+			name:     "columns in any order and blank lines skipped",
+			input:    "points,item_id,email\n\n3,10,bob@example.com\n",
+			wantRows: []ImportRow{{Row: 3, Email: "bob@example.com", ItemID: 10, Points: 3}},
+		},
+		{
+			name:     "invalid rows are reported and the rest imported",
+			input:    "user_id,item_id,points\nabc,10,1\n0,10,1\n5,x,1\n5,10,-1\n5,10,many\n,10,1\n5,10,2\n",
+			wantRows: []ImportRow{{Row: 8, UserID: 5, ItemID: 10, Points: 2}},
+			wantErrors: []RowError{
+				{Row: 2, Error: `invalid user_id "abc"`},
+				{Row: 3, Error: `invalid user_id "0"`},
+				{Row: 4, Error: `invalid item_id "x"`},
+				{Row: 5, Error: `invalid points "-1"`},
+				{Row: 6, Error: `invalid points "many"`},
+				{Row: 7, Error: "user_id or email is required"},
+			},
+		},
+		{
+			name:  "multi-line comment",
+			input: "user_id,item_id,points,comment\n5,10,1,\"two\nlines\"\n6,10,2,\n",
+			wantRows: []ImportRow{
+				{Row: 2, UserID: 5, ItemID: 10, Points: 1, Comment: "two\nlines"},
+				{Row: 4, UserID: 6, ItemID: 10, Points: 2},
+			},
+		},
+		{
+			name:       "short row",
+			input:      "user_id,item_id,points\n5,10\n",
+			wantErrors: []RowError{{Row: 2, Error: `invalid points ""`}},
+		},
+		{
+			name:       "malformed quoting",
+			input:      "user_id,item_id,points\n5,1\"0,1\n6,10,4\n",
+			wantRows:   []ImportRow{{Row: 3, UserID: 6, ItemID: 10, Points: 4}},
+			wantErrors: []RowError{{Row: 2, Error: `bare " in non-quoted-field`}},
+		},
+		{name: "empty file", input: "", wantErr: "the file is empty"},
+		{name: "missing points", input: "user_id,item_id\n5,10\n", wantErr: "missing points column"},
+		{name: "missing item_id", input: "user_id,points\n5,10\n", wantErr: "missing item_id column"},
+		{name: "missing student", input: "item_id,points\n10,5\n", wantErr: "missing user_id or email column"},
+		{name: "too many rows", input: "user_id,item_id,points\n" + strings.Repeat("5,10,1\n", maxImportRows+1),
+			wantErr: "an import is limited to 10000 rows"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, rowErrors, err := ParseScoresCSV(strings.NewReader(tc.input))
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tc.wantRows) {
+				t.Errorf("rows = %+v, want %+v", rows, tc.wantRows)
+			}
+			if !reflect.DeepEqual(rowErrors, tc.wantErrors) {
+				t.Errorf("row errors = %+v, want %+v", rowErrors, tc.wantErrors)
+			}
+		})
+	}
+}
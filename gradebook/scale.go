@@ -0,0 +1,86 @@
+package gradebook
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScaleLetter is the lowest percentage that earns a letter
+type ScaleLetter struct {
+	Letter     string  `json:"letter"`
+	MinPercent float64 `json:"min_percent"`
+}
+
+// Scale maps final percentages to letter grades
+type Scale []ScaleLetter
+
+// DefaultScale is used by courses that have not set their own scale
+var DefaultScale = Scale{
+	{Letter: "A", MinPercent: 90},
+	{Letter: "B", MinPercent: 80},
+	{Letter: "C", MinPercent: 70},
+	{Letter: "D", MinPercent: 60},
+	{Letter: "F", MinPercent: 0},
+}
+
+// Validate checks that letters are named and unique and that every percentage from 0 up gets a letter
+func (scale Scale) Validate() error {
+	if len(scale) == 0 {
+		return errors.New("a grade scale needs at least one letter")
+	}
+
+	letters := make(map[string]bool, len(scale))
+	minimums := make(map[float64]bool, len(scale))
+	hasZero := false
+	for _, letter := range scale {
+		name := strings.TrimSpace(letter.Letter)
+		if name == "" {
+			return errors.New("every grade scale entry needs a letter")
+		}
+		if letters[name] {
+			return fmt.Errorf("letter %q appears more than once", name)
+		}
+		if minimums[letter.MinPercent] {
+			return fmt.Errorf("more than one letter starts at %v percent", letter.MinPercent)
+		}
+		if letter.MinPercent < 0 || letter.MinPercent > 100 {
+			return fmt.Errorf("letter %q must start between 0 and 100 percent", name)
+		}
+		letters[name] = true
+		minimums[letter.MinPercent] = true
+		hasZero = hasZero || letter.MinPercent == 0
+	}
+
+	if !hasZero {
+		return errors.New("a grade scale needs a letter starting at 0 percent")
+	}
+
+	return nil
+}
+
+// Sorted returns the scale ordered from the highest letter down
+func (scale Scale) Sorted() Scale {
+	sorted := make(Scale, len(scale))
+	copy(sorted, scale)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinPercent > sorted[j].MinPercent
+	})
+	return sorted
+}
+
+// Letter returns the letter earned by a percentage, falling back to the default scale when the scale is empty
+func (scale Scale) Letter(percentage float64) string {
+	if len(scale) == 0 {
+		scale = DefaultScale
+	}
+
+	sorted := scale.Sorted()
+	for _, letter := range sorted {
+		if percentage >= letter.MinPercent {
+			return letter.Letter
+		}
+	}
+	return sorted[len(sorted)-1].Letter
+}
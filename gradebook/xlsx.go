@@ -0,0 +1,111 @@
+package gradebook
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// the fixed parts of a workbook with a single worksheet
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// WriteXLSX writes rows as a workbook with one worksheet. Cells that hold a number are stored as numbers,
+// everything else as inline text.
+func WriteXLSX(w io.Writer, sheetName string, rows [][]string) error {
+	archive := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return err
+		}
+	}
+
+	file, err := archive.Create("xl/workbook.xml")
+	if err != nil {
+		return err
+	}
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` +
+		escapeXML(sheetName) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if _, err := io.WriteString(file, workbook); err != nil {
+		return err
+	}
+
+	file, err = archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if err := writeWorksheet(file, rows); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func writeWorksheet(w io.Writer, rows [][]string) error {
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for i, row := range rows {
+		rowNumber := strconv.Itoa(i + 1)
+		sheet.WriteString(`<row r="` + rowNumber + `">`)
+		for j, value := range row {
+			ref := columnName(j) + rowNumber
+			if i > 0 && isNumber(value) {
+				sheet.WriteString(`<c r="` + ref + `"><v>` + value + `</v></c>`)
+				continue
+			}
+			if value == "" {
+				continue
+			}
+			sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t>` + escapeXML(value) + `</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+
+	sheet.WriteString(`</sheetData></worksheet>`)
+	_, err := io.WriteString(w, sheet.String())
+	return err
+}
+
+// isNumber reports whether a cell holds a plain decimal number
+func isNumber(value string) bool {
+	if strings.Trim(value, "0123456789.-") != "" {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// columnName turns a zero based column index into a spreadsheet column such as A, Z or AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escapeXML(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
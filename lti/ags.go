@@ -0,0 +1,139 @@
+package lti
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+// Assignment and Grade Services scope and progress values used by the tool
+const (
+	ScopeScore               = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+	ActivityProgressComplete = "Completed"
+	GradingProgressGraded    = "FullyGraded"
+	scoreContentType         = "application/vnd.ims.lis.v1.score+json"
+	clientAssertionType      = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// Score is a result posted to a platform line item
+type Score struct {
+	UserID           string  `json:"userId"`
+	ScoreGiven       float64 `json:"scoreGiven"`
+	ScoreMaximum     float64 `json:"scoreMaximum"`
+	ActivityProgress string  `json:"activityProgress"`
+	GradingProgress  string  `json:"gradingProgress"`
+	Timestamp        string  `json:"timestamp"`
+}
+
+// NewScore returns the fully graded score of a user out of maximum
+func NewScore(userID string, given, maximum float64, at time.Time) Score {
+	return Score{
+		UserID:           userID,
+		ScoreGiven:       given,
+		ScoreMaximum:     maximum,
+		ActivityProgress: ActivityProgressComplete,
+		GradingProgress:  GradingProgressGraded,
+		Timestamp:        at.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// PostScore posts a score to the scores endpoint of a line item, authorizing with the platform token endpoint
+func (tool *Tool) PostScore(ctx context.Context, platform Platform, lineItem string, score Score) error {
+	accessToken, err := tool.accessToken(ctx, platform, ScopeScore)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(score)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scoresURL(lineItem), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", scoreContentType)
+
+	resp, err := tool.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot post score: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("cannot post score: %s %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// scoresURL appends /scores to the path of a line item URL, keeping its query
+func scoresURL(lineItem string) string {
+	parsed, err := url.Parse(lineItem)
+	if err != nil {
+		return strings.TrimSuffix(lineItem, "/") + "/scores"
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/scores"
+	return parsed.String()
+}
+
+// accessToken obtains a token with the client credentials grant, the tool authenticates with a signed JWT assertion
+func (tool *Tool) accessToken(ctx context.Context, platform Platform, scope string) (string, error) {
+	now := time.Now()
+	assertion, err := tool.sign(jwt.MapClaims{
+		"iss": platform.ClientID,
+		"sub": platform.ClientID,
+		"aud": platform.AuthTokenURL,
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+		"jti": uuid.NewString(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_assertion_type", clientAssertionType)
+	form.Set("client_assertion", assertion)
+	form.Set("scope", scope)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, platform.AuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := tool.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cannot get platform access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot get platform access token: %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid platform access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("platform returned no access token")
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,62 @@
+package lti
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+// ContentItemResourceLink is the only content item type the tool offers
+const ContentItemResourceLink = "ltiResourceLink"
+
+// deepLinkResponseTTL is how long the platform accepts a deep linking response
+const deepLinkResponseTTL = 5 * time.Minute
+
+// LineItem asks the platform to create a gradebook column along with the resource link
+type LineItem struct {
+	Label        string  `json:"label,omitempty"`
+	ScoreMaximum float64 `json:"scoreMaximum"`
+	ResourceID   string  `json:"resourceId,omitempty"`
+}
+
+// ContentItem is a link to a course or material returned from deep linking
+type ContentItem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Custom   map[string]string `json:"custom,omitempty"`
+	LineItem *LineItem         `json:"lineItem,omitempty"`
+}
+
+// ResourceLinkItem returns a content item launching the tool with custom parameters
+func (tool *Tool) ResourceLinkItem(title string, custom map[string]string, lineItem *LineItem) ContentItem {
+	return ContentItem{
+		Type:     ContentItemResourceLink,
+		Title:    title,
+		URL:      tool.LaunchURL(),
+		Custom:   custom,
+		LineItem: lineItem,
+	}
+}
+
+// DeepLinkResponse signs the message carrying the selected content items back to the platform.
+// It is posted by the browser as the JWT form field to the return URL of the deep linking settings.
+func (tool *Tool) DeepLinkResponse(platform Platform, deploymentID string, settings DeepLinkingSettings, items []ContentItem) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   platform.ClientID,
+		"aud":   platform.Issuer,
+		"iat":   now.Unix(),
+		"exp":   now.Add(deepLinkResponseTTL).Unix(),
+		"nonce": uuid.NewString(),
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id":    deploymentID,
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":     MessageDeepLinkingResponse,
+		"https://purl.imsglobal.org/spec/lti/claim/version":          Version,
+		"https://purl.imsglobal.org/spec/lti-dl/claim/content_items": items,
+	}
+	if settings.Data != "" {
+		claims["https://purl.imsglobal.org/spec/lti-dl/claim/data"] = settings.Data
+	}
+	return tool.sign(claims)
+}
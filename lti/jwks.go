@@ -0,0 +1,160 @@
+package lti
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// keySetTTL is how long a platform key set is used before it is fetched again
+	keySetTTL = time.Hour
+	// keySetRefetchInterval is the least time between two fetches for an unknown key ID, platforms rotate keys rarely
+	keySetRefetchInterval = time.Minute
+)
+
+// JWK is an RSA public key as a JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// KeySet is a JSON Web Key Set
+type KeySet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK returns the JSON Web Key of an RSA public key
+func NewJWK(key *rsa.PublicKey, kid string) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key, used as its key ID
+func (jwk JWK) Thumbprint() string {
+	canonical := fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, jwk.E, jwk.Kty, jwk.N)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// PublicKey decodes the RSA public key
+func (jwk JWK) PublicKey() (*rsa.PublicKey, error) {
+	if jwk.Kty != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// Key returns the key with the given ID, or the only key of a set whose keys have no IDs
+func (set KeySet) Key(kid string) (*rsa.PublicKey, bool) {
+	for _, jwk := range set.Keys {
+		if jwk.Kid == kid || (kid == "" && len(set.Keys) == 1) {
+			key, err := jwk.PublicKey()
+			return key, err == nil
+		}
+	}
+	return nil, false
+}
+
+type cachedKeySet struct {
+	set       KeySet
+	fetchedAt time.Time
+}
+
+// KeySetCache fetches platform key sets and keeps them for an hour
+type KeySetCache struct {
+	client *http.Client
+	mu     sync.Mutex
+	sets   map[string]cachedKeySet
+}
+
+// NewKeySetCache returns an empty cache fetching with client
+func NewKeySetCache(client *http.Client) *KeySetCache {
+	return &KeySetCache{client: client, sets: make(map[string]cachedKeySet)}
+}
+
+// Key returns the key with the given ID from the key set at url. An unknown key ID fetches the set again,
+// at most once a minute, so keys a platform rotates in are picked up.
+func (cache *KeySetCache) Key(ctx context.Context, url string, kid string) (*rsa.PublicKey, error) {
+	cache.mu.Lock()
+	cached, ok := cache.sets[url]
+	cache.mu.Unlock()
+
+	if ok && time.Since(cached.fetchedAt) < keySetTTL {
+		if key, found := cached.set.Key(kid); found {
+			return key, nil
+		}
+		if time.Since(cached.fetchedAt) < keySetRefetchInterval {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidLaunch, kid)
+		}
+	}
+
+	set, err := cache.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	cache.sets[url] = cachedKeySet{set: set, fetchedAt: time.Now()}
+	cache.mu.Unlock()
+
+	key, found := set.Key(kid)
+	if !found {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidLaunch, kid)
+	}
+	return key, nil
+}
+
+func (cache *KeySetCache) fetch(ctx context.Context, url string) (KeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return KeySet{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := cache.client.Do(req)
+	if err != nil {
+		return KeySet{}, fmt.Errorf("cannot fetch platform keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return KeySet{}, fmt.Errorf("cannot fetch platform keys: %s", resp.Status)
+	}
+
+	var set KeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return KeySet{}, fmt.Errorf("invalid platform keys: %w", err)
+	}
+	return set, nil
+}
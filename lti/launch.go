@@ -0,0 +1,194 @@
+package lti
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Message types and version of LTI 1.3 handled by the tool
+const (
+	Version                     = "1.3.0"
+	MessageResourceLink         = "LtiResourceLinkRequest"
+	MessageDeepLinking          = "LtiDeepLinkingRequest"
+	MessageDeepLinkingResponse  = "LtiDeepLinkingResponse"
+	roleInstructor              = "http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"
+	roleInstitutionAdmin        = "http://purl.imsglobal.org/vocab/lis/v2/institution/person#Administrator"
+	roleSystemAdmin             = "http://purl.imsglobal.org/vocab/lis/v2/system/person#Administrator"
+	roleMembershipAdministrator = "http://purl.imsglobal.org/vocab/lis/v2/membership#Administrator"
+)
+
+// ResourceLink is the placement in the platform a launch came from
+type ResourceLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// Endpoint is the Assignment and Grade Services claim of a launch
+type Endpoint struct {
+	Scope     []string `json:"scope"`
+	LineItems string   `json:"lineitems,omitempty"`
+	LineItem  string   `json:"lineitem,omitempty"`
+}
+
+// DeepLinkingSettings is where and what a deep linking request accepts back
+type DeepLinkingSettings struct {
+	ReturnURL   string   `json:"deep_link_return_url"`
+	AcceptTypes []string `json:"accept_types"`
+	Data        string   `json:"data,omitempty"`
+}
+
+// Launch is a verified launch message of a platform
+type Launch struct {
+	Subject      string            `json:"sub"`
+	Name         string            `json:"name"`
+	GivenName    string            `json:"given_name"`
+	FamilyName   string            `json:"family_name"`
+	Email        string            `json:"email"`
+	MessageType  string            `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version      string            `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentID string            `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	Roles        []string          `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	ResourceLink ResourceLink      `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link"`
+	Custom       map[string]string `json:"https://purl.imsglobal.org/spec/lti/claim/custom"`
+	// AGS is set when the platform offers grade passback for the resource link
+	AGS *Endpoint `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"`
+	// DeepLinking is set on deep linking requests
+	DeepLinking *DeepLinkingSettings `json:"https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings"`
+}
+
+// IsInstructor tells whether the launching user teaches the context or administers the platform
+func (launch Launch) IsInstructor() bool {
+	for _, role := range launch.Roles {
+		switch role {
+		case roleInstructor, roleMembershipAdministrator, roleInstitutionAdmin, roleSystemAdmin:
+			return true
+		}
+	}
+	return false
+}
+
+// CanPostScores tells whether the launch allows posting scores to its line item
+func (launch Launch) CanPostScores() bool {
+	if launch.AGS == nil || launch.AGS.LineItem == "" {
+		return false
+	}
+	for _, scope := range launch.AGS.Scope {
+		if scope == ScopeScore {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyLaunch validates the id_token a platform posted to the launch URL: its signature against the platform keys,
+// the issuer, audience, expiry, the nonce sent in the login request and the LTI claims
+func (tool *Tool) VerifyLaunch(ctx context.Context, idToken string, platform Platform, nonce string) (Launch, error) {
+	parsed, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return tool.keys.Key(ctx, platform.JWKSURL, kid)
+	})
+	if err != nil {
+		return Launch{}, fmt.Errorf("%w: %v", ErrInvalidLaunch, err)
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid {
+		return Launch{}, ErrInvalidLaunch
+	}
+
+	if iss, _ := claims["iss"].(string); iss != platform.Issuer {
+		return Launch{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidLaunch, iss)
+	}
+	if err := verifyAudience(claims, platform.ClientID); err != nil {
+		return Launch{}, err
+	}
+	if _, ok := claims["exp"]; !ok {
+		return Launch{}, fmt.Errorf("%w: missing expiry", ErrInvalidLaunch)
+	}
+	if claimNonce, _ := claims["nonce"].(string); nonce == "" || claimNonce != nonce {
+		return Launch{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidLaunch)
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return Launch{}, err
+	}
+	var launch Launch
+	if err := json.Unmarshal(data, &launch); err != nil {
+		return Launch{}, fmt.Errorf("%w: %v", ErrInvalidLaunch, err)
+	}
+
+	switch {
+	case launch.Version != Version:
+		return Launch{}, fmt.Errorf("%w: unsupported version %q", ErrInvalidLaunch, launch.Version)
+	case launch.Subject == "":
+		return Launch{}, fmt.Errorf("%w: missing subject", ErrInvalidLaunch)
+	case launch.DeploymentID == "" || (platform.DeploymentID != "" && launch.DeploymentID != platform.DeploymentID):
+		return Launch{}, fmt.Errorf("%w: unknown deployment %q", ErrInvalidLaunch, launch.DeploymentID)
+	}
+
+	switch launch.MessageType {
+	case MessageResourceLink:
+		if launch.ResourceLink.ID == "" {
+			return Launch{}, fmt.Errorf("%w: missing resource link", ErrInvalidLaunch)
+		}
+	case MessageDeepLinking:
+		if launch.DeepLinking == nil || launch.DeepLinking.ReturnURL == "" {
+			return Launch{}, fmt.Errorf("%w: missing deep linking settings", ErrInvalidLaunch)
+		}
+	default:
+		return Launch{}, fmt.Errorf("%w: unsupported message type %q", ErrInvalidLaunch, launch.MessageType)
+	}
+
+	return launch, nil
+}
+
+// verifyAudience checks the token is meant for the client, a token with several audiences must name it as authorized party
+func verifyAudience(claims jwt.MapClaims, clientID string) error {
+	var audience []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audience = []string{aud}
+	case []interface{}:
+		for _, value := range aud {
+			if s, ok := value.(string); ok {
+				audience = append(audience, s)
+			}
+		}
+	}
+
+	found := false
+	for _, aud := range audience {
+		if aud == clientID {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: not issued for client %q", ErrInvalidLaunch, clientID)
+	}
+
+	azp, hasAzp := claims["azp"].(string)
+	if (len(audience) > 1 || hasAzp) && azp != clientID {
+		return fmt.Errorf("%w: unexpected authorized party %q", ErrInvalidLaunch, azp)
+	}
+	return nil
+}
+
+// CustomID reads a numeric custom parameter such as the course_id a deep link placed on the resource link
+func (launch Launch) CustomID(name string) (int64, bool) {
+	value := strings.TrimSpace(launch.Custom[name])
+	if value == "" {
+		return 0, false
+	}
+	var id int64
+	if _, err := fmt.Sscan(value, &id); err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,242 @@
+package lti
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestVerifyLaunch(t *testing.T) {
+	tool := newTestTool(t)
+	platform := newFakePlatform(t, tool)
+	const nonce = "nonce-1"
+
+	with := func(changes map[string]any) jwt.MapClaims {
+		claims := launchClaims(nonce)
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+				continue
+			}
+			claims[name] = value
+		}
+		return claims
+	}
+
+	otherKey := generateKey(t)
+	testCases := []struct {
+		name      string
+		token     string
+		platform  func(Platform) Platform
+		nonce     string
+		wantError bool
+	}{
+		{name: "good launch", token: platform.idToken(launchClaims(nonce), testKeyID, nil)},
+		{name: "audience list with azp", token: platform.idToken(with(map[string]any{"aud": []string{testClientID, "other"}, "azp": testClientID}), testKeyID, nil)},
+		{name: "platform without deployment", token: platform.idToken(with(map[string]any{
+			"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "deployment-2",
+		}), testKeyID, nil), platform: func(p Platform) Platform { p.DeploymentID = ""; return p }},
+
+		{name: "wrong issuer", token: platform.idToken(with(map[string]any{"iss": "https://evil.example.com"}), testKeyID, nil), wantError: true},
+		{name: "wrong audience", token: platform.idToken(with(map[string]any{"aud": "other-client"}), testKeyID, nil), wantError: true},
+		{name: "audience list without azp", token: platform.idToken(with(map[string]any{"aud": []string{testClientID, "other"}}), testKeyID, nil), wantError: true},
+		{name: "wrong azp", token: platform.idToken(with(map[string]any{"azp": "other-client"}), testKeyID, nil), wantError: true},
+		{name: "wrong nonce", token: platform.idToken(with(map[string]any{"nonce": "nonce-2"}), testKeyID, nil), wantError: true},
+		{name: "missing nonce", token: platform.idToken(with(map[string]any{"nonce": nil}), testKeyID, nil), wantError: true},
+		{name: "no expected nonce", token: platform.idToken(with(map[string]any{"nonce": ""}), testKeyID, nil), nonce: "-", wantError: true},
+		{name: "unknown kid", token: platform.idToken(launchClaims(nonce), "rotated-key", nil), wantError: true},
+		{name: "signed by another key", token: platform.idToken(launchClaims(nonce), testKeyID, otherKey), wantError: true},
+		{name: "HMAC signed", token: func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, launchClaims(nonce))
+			token.Header["kid"] = testKeyID
+			signed, _ := token.SignedString([]byte("secret"))
+			return signed
+		}(), wantError: true},
+		{name: "expired", token: platform.idToken(with(map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}), testKeyID, nil), wantError: true},
+		{name: "missing expiry", token: platform.idToken(with(map[string]any{"exp": nil}), testKeyID, nil), wantError: true},
+		{name: "wrong deployment", token: platform.idToken(with(map[string]any{
+			"https://purl.imsglobal.org/spec/lti/claim/deployment_id": "deployment-2",
+		}), testKeyID, nil), wantError: true},
+		{name: "missing deployment", token: platform.idToken(with(map[string]any{
+			"https://purl.imsglobal.org/spec/lti/claim/deployment_id": nil,
+		}), testKeyID, nil), wantError: true},
+		{name: "wrong version", token: platform.idToken(with(map[string]any{
+			"https://purl.imsglobal.org/spec/lti/claim/version": "1.1",
+		}), testKeyID, nil), wantError: true},
+		{name: "missing subject", token: platform.idToken(with(map[string]any{"sub": nil}), testKeyID, nil), wantError: true},
+		{name: "missing resource link", token: platform.idToken(with(map[string]any{
+			"https://purl.imsglobal.org/spec/lti/claim/resource_link": nil,
+		}), testKeyID, nil), wantError: true},
+		{name: "unsupported message type", token: platform.idToken(with(map[string]any{
+			"https://purl.imsglobal.org/spec/lti/claim/message_type": "LtiSubmissionReviewRequest",
+		}), testKeyID, nil), wantError: true},
+		{name: "not a JWT", token: "not-a-token", wantError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registration := platform.registration()
+			if tc.platform != nil {
+				registration = tc.platform(registration)
+			}
+			expectedNonce := nonce
+			if tc.nonce == "-" {
+				expectedNonce = ""
+			}
+
+			launch, err := tool.VerifyLaunch(context.Background(), tc.token, registration, expectedNonce)
+			if tc.wantError {
+				if !errors.Is(err, ErrInvalidLaunch) {
+					t.Errorf("VerifyLaunch() error = %v, want ErrInvalidLaunch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyLaunch() error = %v", err)
+			}
+
+			if launch.Subject != "user-42" || launch.Email != "ada@example.com" || launch.ResourceLink.ID != "link-1" {
+				t.Errorf("launch = %+v, want the user and resource link of the token", launch)
+			}
+			if launch.IsInstructor() {
+				t.Error("IsInstructor() = true for a learner")
+			}
+			if courseID, ok := launch.CustomID("course_id"); !ok || courseID != 12 {
+				t.Errorf("CustomID(course_id) = %d, %v, want 12", courseID, ok)
+			}
+			if !launch.CanPostScores() {
+				t.Error("CanPostScores() = false for a launch with the score scope")
+			}
+		})
+	}
+}
+
+func TestVerifyDeepLinkingLaunch(t *testing.T) {
+	tool := newTestTool(t)
+	platform := newFakePlatform(t, tool)
+	const nonce = "nonce-dl"
+
+	deepLinking := func(settings any) jwt.MapClaims {
+		claims := launchClaims(nonce)
+		delete(claims, "https://purl.imsglobal.org/spec/lti/claim/resource_link")
+		claims["https://purl.imsglobal.org/spec/lti/claim/message_type"] = MessageDeepLinking
+		claims["https://purl.imsglobal.org/spec/lti/claim/roles"] = []string{roleInstructor}
+		if settings != nil {
+			claims["https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings"] = settings
+		}
+		return claims
+	}
+
+	settings := map[string]any{
+		"deep_link_return_url": platform.server.URL + "/deeplink/return",
+		"accept_types":         []string{ContentItemResourceLink},
+		"data":                 "opaque-data",
+	}
+	launch, err := tool.VerifyLaunch(context.Background(), platform.idToken(deepLinking(settings), testKeyID, nil), platform.registration(), nonce)
+	if err != nil {
+		t.Fatalf("VerifyLaunch() error = %v", err)
+	}
+	if launch.MessageType != MessageDeepLinking || launch.DeepLinking == nil || launch.DeepLinking.Data != "opaque-data" {
+		t.Fatalf("launch = %+v, want the deep linking settings", launch)
+	}
+	if !launch.IsInstructor() {
+		t.Error("IsInstructor() = false for an instructor")
+	}
+
+	for name, settings := range map[string]any{
+		"missing settings":   nil,
+		"missing return URL": map[string]any{"accept_types": []string{ContentItemResourceLink}},
+	} {
+		_, err := tool.VerifyLaunch(context.Background(), platform.idToken(deepLinking(settings), testKeyID, nil), platform.registration(), nonce)
+		if !errors.Is(err, ErrInvalidLaunch) {
+			t.Errorf("%s: VerifyLaunch() error = %v, want ErrInvalidLaunch", name, err)
+		}
+	}
+
+	// the response goes back to the platform signed with the tool key
+	item := tool.ResourceLinkItem("Week 1", map[string]string{"course_id": "12"}, &LineItem{Label: "Week 1", ScoreMaximum: 100})
+	response, err := tool.DeepLinkResponse(platform.registration(), launch.DeploymentID, *launch.DeepLinking, []ContentItem{item})
+	if err != nil {
+		t.Fatalf("DeepLinkResponse() error = %v", err)
+	}
+	parsed, err := jwt.Parse(response, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := tool.JWKS().Key(kid)
+		if !ok {
+			return nil, jwt.ErrInvalidKey
+		}
+		return key, nil
+	})
+	if err != nil || !parsed.Valid {
+		t.Fatalf("deep linking response does not verify with the tool keys: %v", err)
+	}
+	claims := parsed.Claims.(jwt.MapClaims)
+	if claims["iss"] != testClientID || claims["aud"] != testIssuer {
+		t.Errorf("iss, aud = %v, %v, want %s, %s", claims["iss"], claims["aud"], testClientID, testIssuer)
+	}
+	if claims["https://purl.imsglobal.org/spec/lti/claim/message_type"] != MessageDeepLinkingResponse ||
+		claims["https://purl.imsglobal.org/spec/lti/claim/deployment_id"] != testDeploymentID ||
+		claims["https://purl.imsglobal.org/spec/lti-dl/claim/data"] != "opaque-data" {
+		t.Errorf("claims = %v, want a deep linking response echoing the data", claims)
+	}
+	items, _ := claims["https://purl.imsglobal.org/spec/lti-dl/claim/content_items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("content items = %v, want one", items)
+	}
+	first := items[0].(map[string]any)
+	if first["type"] != ContentItemResourceLink || first["url"] != tool.LaunchURL() {
+		t.Errorf("content item = %v, want a resource link to the launch URL", first)
+	}
+}
+
+func TestPostScore(t *testing.T) {
+	tool := newTestTool(t)
+	platform := newFakePlatform(t, tool)
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	lineItem := platform.server.URL + "/lineitems/1?type=graded"
+
+	score := NewScore("user-42", 8, 10, at)
+	if err := tool.PostScore(context.Background(), platform.registration(), lineItem, score); err != nil {
+		t.Fatalf("PostScore() error = %v", err)
+	}
+
+	if len(platform.scores) != 1 {
+		t.Fatalf("platform received %d scores, want 1", len(platform.scores))
+	}
+	got := platform.scores[0]
+	if got != score {
+		t.Errorf("score = %+v, want %+v", got, score)
+	}
+	if got.ActivityProgress != ActivityProgressComplete || got.GradingProgress != GradingProgressGraded || got.Timestamp != "2024-05-01T10:00:00Z" {
+		t.Errorf("score = %+v, want a fully graded, completed score", got)
+	}
+
+	platform.tokenStatus = 401
+	if err := tool.PostScore(context.Background(), platform.registration(), lineItem, score); err == nil {
+		t.Error("PostScore() error = nil when the platform refuses a token")
+	}
+	platform.tokenStatus = 0
+
+	if err := tool.PostScore(context.Background(), platform.registration(), platform.server.URL+"/lineitems/2", score); err == nil {
+		t.Error("PostScore() error = nil for a line item the platform does not know")
+	}
+	if len(platform.scores) != 1 {
+		t.Errorf("platform received %d scores, want 1", len(platform.scores))
+	}
+}
+
+func TestScoresURL(t *testing.T) {
+	testCases := map[string]string{
+		"https://platform.example.com/lineitems/1":           "https://platform.example.com/lineitems/1/scores",
+		"https://platform.example.com/lineitems/1/":          "https://platform.example.com/lineitems/1/scores",
+		"https://platform.example.com/lineitems/1?type=quiz": "https://platform.example.com/lineitems/1/scores?type=quiz",
+	}
+	for lineItem, want := range testCases {
+		if got := scoresURL(lineItem); got != want {
+			t.Errorf("scoresURL(%q) = %q, want %q", lineItem, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package lti
+
+import (
+	"errors"
+	"net/url"
+)
+
+// LoginRequest is the third-party initiated login a platform starts a launch with
+type LoginRequest struct {
+	Issuer         string `form:"iss" binding:"required"`
+	LoginHint      string `form:"login_hint" binding:"required"`
+	TargetLinkURI  string `form:"target_link_uri"`
+	LTIMessageHint string `form:"lti_message_hint"`
+	ClientID       string `form:"client_id"`
+	DeploymentID   string `form:"lti_deployment_id"`
+}
+
+// AuthRedirect returns the URL of the platform authorization endpoint the browser is sent to after a login request.
+// The platform answers it by posting the id_token to the launch URL along with state.
+func (tool *Tool) AuthRedirect(platform Platform, req LoginRequest, state, nonce string) (string, error) {
+	endpoint, err := url.Parse(platform.AuthLoginURL)
+	if err != nil || endpoint.Scheme == "" {
+		return "", errors.New("invalid platform login URL")
+	}
+
+	query := endpoint.Query()
+	query.Set("scope", "openid")
+	query.Set("response_type", "id_token")
+	query.Set("response_mode", "form_post")
+	query.Set("prompt", "none")
+	query.Set("client_id", platform.ClientID)
+	query.Set("redirect_uri", tool.LaunchURL())
+	query.Set("login_hint", req.LoginHint)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	if req.LTIMessageHint != "" {
+		query.Set("lti_message_hint", req.LTIMessageHint)
+	}
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String(), nil
+}
@@ -0,0 +1,195 @@
+package lti
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const (
+	testIssuer       = "https://platform.example.com"
+	testClientID     = "tool-client"
+	testDeploymentID = "deployment-1"
+	testKeyID        = "platform-key"
+	testAccessToken  = "platform-access-token"
+)
+
+// fakePlatform is an LTI platform serving its key set, a token endpoint and a line item, and signing id_tokens
+type fakePlatform struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	tool   *Tool
+
+	mu     sync.Mutex
+	scores []Score
+	// tokenStatus replaces a successful token response when set
+	tokenStatus int
+}
+
+func newFakePlatform(t *testing.T, tool *Tool) *fakePlatform {
+	t.Helper()
+
+	platform := &fakePlatform{t: t, key: generateKey(t), tool: tool}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(KeySet{Keys: []JWK{NewJWK(&platform.key.PublicKey, testKeyID)}})
+	})
+	mux.HandleFunc("/token", platform.handleToken)
+	mux.HandleFunc("/lineitems/1/scores", platform.handleScore)
+	platform.server = httptest.NewServer(mux)
+	t.Cleanup(platform.server.Close)
+	return platform
+}
+
+func (platform *fakePlatform) registration() Platform {
+	return Platform{
+		Issuer:       testIssuer,
+		ClientID:     testClientID,
+		DeploymentID: testDeploymentID,
+		AuthLoginURL: platform.server.URL + "/auth",
+		AuthTokenURL: platform.server.URL + "/token",
+		JWKSURL:      platform.server.URL + "/jwks",
+	}
+}
+
+// handleToken grants an access token to a client assertion signed with the tool key
+func (platform *fakePlatform) handleToken(w http.ResponseWriter, r *http.Request) {
+	if platform.tokenStatus != 0 {
+		w.WriteHeader(platform.tokenStatus)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_assertion_type") != clientAssertionType ||
+		r.PostForm.Get("scope") != ScopeScore {
+		http.Error(w, "unexpected grant", http.StatusBadRequest)
+		return
+	}
+
+	assertion, err := jwt.Parse(r.PostForm.Get("client_assertion"), func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := platform.tool.JWKS().Key(kid)
+		if !ok {
+			return nil, jwt.ErrInvalidKey
+		}
+		return key, nil
+	})
+	if err != nil || !assertion.Valid {
+		http.Error(w, "invalid client assertion", http.StatusUnauthorized)
+		return
+	}
+	claims := assertion.Claims.(jwt.MapClaims)
+	if claims["iss"] != testClientID || claims["sub"] != testClientID || claims["aud"] != platform.server.URL+"/token" {
+		http.Error(w, "client assertion is not for this platform", http.StatusUnauthorized)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"access_token": testAccessToken, "token_type": "Bearer"})
+}
+
+// handleScore records a score posted with the access token
+func (platform *fakePlatform) handleScore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("Content-Type") != scoreContentType || r.URL.Query().Get("type") != "graded" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	var score Score
+	if err := json.Unmarshal(body, &score); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	platform.mu.Lock()
+	platform.scores = append(platform.scores, score)
+	platform.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// idToken signs launch claims with the platform key, or with key when it is set
+func (platform *fakePlatform) idToken(claims jwt.MapClaims, kid string, key *rsa.PrivateKey) string {
+	platform.t.Helper()
+
+	if key == nil {
+		key = platform.key
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		platform.t.Fatal(err)
+	}
+	return signed
+}
+
+// launchClaims returns the claims of a valid resource link launch
+func launchClaims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testClientID,
+		"sub":   "user-42",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": nonce,
+		"name":  "Ada Lovelace",
+		"email": "ada@example.com",
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":  MessageResourceLink,
+		"https://purl.imsglobal.org/spec/lti/claim/version":       Version,
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": testDeploymentID,
+		"https://purl.imsglobal.org/spec/lti/claim/roles": []string{
+			"http://purl.imsglobal.org/vocab/lis/v2/membership#Learner",
+		},
+		"https://purl.imsglobal.org/spec/lti/claim/resource_link": map[string]string{"id": "link-1", "title": "Week 1"},
+		"https://purl.imsglobal.org/spec/lti/claim/custom":        map[string]string{"course_id": "12"},
+		"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint": map[string]any{
+			"scope":    []string{ScopeScore},
+			"lineitem": "https://platform.example.com/lineitems/1",
+		},
+	}
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newTestTool(t *testing.T) *Tool {
+	t.Helper()
+
+	key := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(generateKey(t))})
+	tool, err := NewTool("https://tool.example.com/", string(key))
+	if err != nil {
+		t.Fatalf("NewTool() error = %v", err)
+	}
+	if !strings.HasSuffix(tool.LaunchURL(), "tool.example.com/lti/launch") {
+		t.Fatalf("LaunchURL() = %q", tool.LaunchURL())
+	}
+	return tool
+}
@@ -0,0 +1,119 @@
+package lti
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Different types of error returned by the tool
+var (
+	ErrNotConfigured = errors.New("LTI is not configured")
+	ErrInvalidLaunch = errors.New("invalid LTI launch")
+)
+
+// Platform is a registration of an LMS, such as Moodle or Canvas, that launches the tool
+type Platform struct {
+	Issuer       string
+	ClientID     string
+	DeploymentID string
+	// AuthLoginURL is the OIDC authorization endpoint launches are requested from
+	AuthLoginURL string
+	// AuthTokenURL is the OAuth 2 token endpoint the grade services are authorized by
+	AuthTokenURL string
+	// JWKSURL is where the platform publishes the keys it signs launches with
+	JWKSURL string
+}
+
+// Tool is this app as an LTI 1.3 tool
+type Tool struct {
+	url    string
+	key    *rsa.PrivateKey
+	kid    string
+	keys   *KeySetCache
+	client *http.Client
+}
+
+// NewTool creates the tool served at toolURL, signing with an RSA private key given as PEM or as base64 encoded PEM.
+// ErrNotConfigured is returned when no key is set, the LTI endpoints are then unavailable.
+func NewTool(toolURL string, key string) (*Tool, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, ErrNotConfigured
+	}
+	toolURL = strings.TrimSuffix(strings.TrimSpace(toolURL), "/")
+	if toolURL == "" {
+		return nil, errors.New("a tool URL is required for LTI")
+	}
+
+	data := []byte(key)
+	if !strings.HasPrefix(key, "-----BEGIN") {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LTI key: %w", err)
+		}
+		data = decoded
+	}
+	privateKey, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LTI key: %w", err)
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	return &Tool{
+		url:    toolURL,
+		key:    privateKey,
+		kid:    NewJWK(&privateKey.PublicKey, "").Thumbprint(),
+		keys:   NewKeySetCache(client),
+		client: client,
+	}, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return key, nil
+}
+
+// LoginURL is where platforms initiate the OIDC login
+func (tool *Tool) LoginURL() string {
+	return tool.url + "/lti/login"
+}
+
+// LaunchURL is where platforms post launches
+func (tool *Tool) LaunchURL() string {
+	return tool.url + "/lti/launch"
+}
+
+// JWKS returns the public key set platforms verify the messages of the tool with
+func (tool *Tool) JWKS() KeySet {
+	return KeySet{Keys: []JWK{NewJWK(&tool.key.PublicKey, tool.kid)}}
+}
+
+// sign signs claims with the tool key
+func (tool *Tool) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = tool.kid
+	return token.SignedString(tool.key)
+}
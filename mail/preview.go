@@ -0,0 +1,63 @@
+package mail
+
+import "time"
+
+// PreviewData is made-up data to preview a template with, nil for an unknown template
+func PreviewData(name string) any {
+	switch name {
+	case TemplateVerifyEmail:
+		return VerifyEmailData{
+			FirstName:  "Ada",
+			Role:       "student",
+			SecretCode: "x7Kp2q",
+			VerifyURL:  "https://eduapp.example/verifyemail?email_id=1&secret_code=x7Kp2q",
+		}
+	case TemplateResetPassword:
+		return ResetPasswordData{
+			FirstName: "Ada",
+			ResetURL:  "https://eduapp.example/reset/password",
+		}
+	case TemplateAccountInvite:
+		return AccountInviteData{
+			FirstName:   "Ada",
+			UserName:    "ada.lovelace",
+			CourseTitle: "Introduction to Algorithms",
+			Event:       "approved",
+			VerifyURL:   "https://eduapp.example/verifyemail?email_id=1&secret_code=x7Kp2q",
+			ResetURL:    "https://eduapp.example/reset/password",
+		}
+	case TemplateEnrollment:
+		return NotificationData{
+			FirstName: "Ada",
+			Title:     "You are enrolled in Introduction to Algorithms",
+			Message:   "You are now enrolled in Introduction to Algorithms, you can start learning right away.",
+			Values:    map[string]string{"Event": "approved", "CourseTitle": "Introduction to Algorithms"},
+		}
+	case TemplateGrade:
+		return NotificationData{
+			FirstName: "Ada",
+			Title:     "Sorting lab was graded",
+			Message:   "Your submission for Sorting lab was graded, the grade and feedback are ready to view.",
+			Values:    map[string]string{"AssignmentTitle": "Sorting lab"},
+		}
+	case TemplateNotification:
+		return NotificationData{
+			FirstName: "Ada",
+			Title:     "Introduction to Algorithms: Exam moved",
+			Message:   "The final exam moves to Friday.\nThe room stays the same.",
+		}
+	case TemplateExpiryReminder:
+		return ExpiryReminderData{
+			FirstName:   "Ada",
+			CourseTitle: "Introduction to Algorithms",
+			ExpiresAt:   time.Date(2024, time.March, 15, 9, 30, 0, 0, time.UTC),
+		}
+	case TemplateCertificate:
+		return CertificateData{
+			FirstName:   "Ada",
+			CourseTitle: "Introduction to Algorithms",
+			VerifyURL:   "https://eduapp.example/certificates/verify/7QH3-K2MF",
+		}
+	}
+	return nil
+}
@@ -21,6 +21,14 @@ type EmailSender interface {
 		bcc []string,
 		attachFiles []string,
 	) error
+	// SendMessage sends a rendered template, with its plain text variant as the alternative to the HTML
+	SendMessage(
+		message Message,
+		to []string,
+		cc []string,
+		bcc []string,
+		attachFiles []string,
+	) error
 }
 
 type GmailSender struct {
@@ -44,11 +52,24 @@ func (sender *GmailSender) SendEmail(
 	cc []string,
 	bcc []string,
 	attachFiles []string,
+) error {
+	return sender.SendMessage(Message{Subject: subject, HTML: content}, to, cc, bcc, attachFiles)
+}
+
+func (sender *GmailSender) SendMessage(
+	message Message,
+	to []string,
+	cc []string,
+	bcc []string,
+	attachFiles []string,
 ) error {
 	e := email.NewEmail()
 	e.From = fmt.Sprintf("%s <%s>", sender.name, sender.fromEmailAddress)
-	e.Subject = subject
-	e.HTML = []byte(content)
+	e.Subject = message.Subject
+	e.HTML = []byte(message.HTML)
+	if message.Text != "" {
+		e.Text = []byte(message.Text)
+	}
 	e.To = to
 	e.Cc = cc
 	e.Bcc = bcc
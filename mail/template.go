@@ -0,0 +1,234 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// names of the email templates, every one of them has an HTML and a plain text variant per locale
+const (
+	TemplateVerifyEmail    = "verify_email"
+	TemplateResetPassword  = "reset_password"
+	TemplateAccountInvite  = "account_invite"
+	TemplateEnrollment     = "enrollment"
+	TemplateGrade          = "grade"
+	TemplateNotification   = "notification"
+	TemplateExpiryReminder = "expiry_reminder"
+	TemplateCertificate    = "certificate"
+)
+
+// Templates lists every email template in the order they are shown for preview
+var Templates = []string{
+	TemplateVerifyEmail,
+	TemplateResetPassword,
+	TemplateAccountInvite,
+	TemplateEnrollment,
+	TemplateGrade,
+	TemplateNotification,
+	TemplateExpiryReminder,
+	TemplateCertificate,
+}
+
+// DefaultLocale has every template, the other locales fall back to it for the templates they lack
+const DefaultLocale = "en"
+
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// templateFS holds templates/layout.{html,txt} and a folder per locale with partials.{html,txt}
+// and the <name>.{html,txt} of each template. The subject is the "subject" block of the plain text variant.
+//
+//go:embed templates
+var templateFS embed.FS
+
+// Message is a rendered email
+type Message struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+type templateSet struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// templates maps a locale and a template name to the parsed template
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]map[string]templateSet {
+	parsed, err := parseTemplates(templateFS)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func parseTemplates(fsys fs.FS) (map[string]map[string]templateSet, error) {
+	entries, err := fs.ReadDir(fsys, "templates")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]map[string]templateSet)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		parsed[locale] = make(map[string]templateSet)
+
+		for _, name := range Templates {
+			htmlFile := path.Join("templates", locale, name+".html")
+			if _, err := fs.Stat(fsys, htmlFile); errors.Is(err, fs.ErrNotExist) && locale != DefaultLocale {
+				continue
+			}
+
+			funcs := templateFuncs(locale)
+			html, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(funcs)).ParseFS(fsys,
+				"templates/layout.html", path.Join("templates", locale, "partials.html"), htmlFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s/%s: %w", locale, name, err)
+			}
+			text, err := texttemplate.New(name).Funcs(funcs).ParseFS(fsys,
+				"templates/layout.txt", path.Join("templates", locale, "partials.txt"), path.Join("templates", locale, name+".txt"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s/%s: %w", locale, name, err)
+			}
+			parsed[locale][name] = templateSet{html: html, text: text}
+		}
+	}
+
+	if len(parsed[DefaultLocale]) != len(Templates) {
+		return nil, fmt.Errorf("the %s locale must have every email template", DefaultLocale)
+	}
+	return parsed, nil
+}
+
+func templateFuncs(locale string) texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"locale": func() string { return locale },
+		// lines splits a message typed by a person, so its line breaks survive in HTML
+		"lines": func(s string) []string { return strings.Split(strings.TrimSpace(s), "\n") },
+		// date writes a point in time the way the locale does, in UTC as the recipient's time zone is unknown
+		"date": func(t time.Time) string {
+			if locale == "fr" {
+				return t.UTC().Format("02/01/2006 à 15:04 UTC")
+			}
+			return t.UTC().Format("January 2, 2006 at 15:04 UTC")
+		},
+	}
+}
+
+// Locales lists the locales emails can be sent in
+func Locales() []string {
+	locales := make([]string, 0, len(templates))
+	for locale := range templates {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// SupportedLocale reports whether emails can be sent in locale
+func SupportedLocale(locale string) bool {
+	_, ok := templates[locale]
+	return ok
+}
+
+// ResolveLocale picks the supported locale for a stored or requested one such as fr-CA, DefaultLocale when there is none
+func ResolveLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if SupportedLocale(locale) {
+		return locale
+	}
+	if language, _, found := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-"); found && SupportedLocale(language) {
+		return language
+	}
+	return DefaultLocale
+}
+
+// Render fills in the named template in the locale of the recipient, or in DefaultLocale when the locale lacks it
+func Render(name string, locale string, data any) (Message, error) {
+	set, ok := templates[ResolveLocale(locale)][name]
+	if !ok {
+		set, ok = templates[DefaultLocale][name]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject of %s: %w", name, err)
+	}
+	if err := set.text.ExecuteTemplate(&text, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render text of %s: %w", name, err)
+	}
+	if err := set.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render HTML of %s: %w", name, err)
+	}
+
+	return Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
+
+// VerifyEmailData fills in TemplateVerifyEmail, the welcome line depends on the role
+type VerifyEmailData struct {
+	FirstName  string
+	Role       string
+	SecretCode string
+	VerifyURL  string
+}
+
+// ResetPasswordData fills in TemplateResetPassword
+type ResetPasswordData struct {
+	FirstName string
+	ResetURL  string
+}
+
+// AccountInviteData fills in TemplateAccountInvite, sent to a student whose account was created by a bulk enrollment
+type AccountInviteData struct {
+	FirstName   string
+	UserName    string
+	CourseTitle string
+	// Event is the enrollment event, such as approved or waitlisted
+	Event     string
+	VerifyURL string
+	ResetURL  string
+}
+
+// NotificationData fills in the templates of notification emails: TemplateEnrollment, TemplateGrade and
+// TemplateNotification. Title and Message are the in-app notification, Values what a template words itself,
+// such as the Event and CourseTitle of an enrollment or the AssignmentTitle of a grade.
+type NotificationData struct {
+	FirstName string
+	Title     string
+	Message   string
+	Values    map[string]string
+}
+
+// ExpiryReminderData fills in TemplateExpiryReminder, sent to a student whose access to a course ends soon
+type ExpiryReminderData struct {
+	FirstName   string
+	CourseTitle string
+	ExpiresAt   time.Time
+}
+
+// CertificateData fills in TemplateCertificate, sent with the certificate of a completed course attached
+type CertificateData struct {
+	FirstName   string
+	CourseTitle string
+	VerifyURL   string
+}
@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata with the rendered emails")
+
+// goldenCase is a template rendered with some data, named after its golden files
+type goldenCase struct {
+	name     string
+	template string
+	data     any
+}
+
+func goldenCases() []goldenCase {
+	cases := make([]goldenCase, 0, len(Templates)+1)
+	for _, name := range Templates {
+		cases = append(cases, goldenCase{name: name, template: name, data: PreviewData(name)})
+	}
+
+	// the welcome of the teaching committee is worded apart from the one of students
+	admin := PreviewData(TemplateVerifyEmail).(VerifyEmailData)
+	admin.Role = "admin"
+	return append(cases, goldenCase{name: TemplateVerifyEmail + "_admin", template: TemplateVerifyEmail, data: admin})
+}
+
+func TestRenderGolden(t *testing.T) {
+	for _, locale := range Locales() {
+		for _, tc := range goldenCases() {
+			t.Run(locale+"/"+tc.name, func(t *testing.T) {
+				message, err := Render(tc.template, locale, tc.data)
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if message.Subject == "" {
+					t.Error("Render() subject is empty")
+				}
+
+				golden := filepath.Join("testdata", locale+"_"+tc.name)
+				checkGolden(t, golden+".html.golden", message.HTML)
+				checkGolden(t, golden+".txt.golden", "Subject: "+message.Subject+"\n\n"+message.Text)
+			})
+		}
+	}
+}
+
+// checkGolden compares got with the golden file, or rewrites the file when the test runs with -update
+func checkGolden(t *testing.T, path string, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run the test with -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from the rendered email, run the test with -update if the change is intended\ngot:\n%s\nwant:\n%s",
+			path, got, want)
+	}
+}
+
+func TestRenderLocale(t *testing.T) {
+	data := PreviewData(TemplateResetPassword)
+	french, err := Render(TemplateResetPassword, "fr", data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	english, err := Render(TemplateResetPassword, DefaultLocale, data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if french.Subject == english.Subject {
+		t.Fatalf("fr subject = %q, want a translation", french.Subject)
+	}
+
+	testCases := map[string]Message{
+		"fr-CA": french,
+		"FR_fr": french,
+		"de":    english,
+		"":      english,
+	}
+	for locale, want := range testCases {
+		got, err := Render(TemplateResetPassword, locale, data)
+		if err != nil {
+			t.Fatalf("Render(%q) error = %v", locale, err)
+		}
+		if got != want {
+			t.Errorf("Render(%q) subject = %q, want %q", locale, got.Subject, want.Subject)
+		}
+	}
+
+	if _, err := Render("newsletter", DefaultLocale, nil); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("Render() error = %v, want ErrUnknownTemplate", err)
+	}
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	data := PreviewData(TemplateNotification).(NotificationData)
+	data.Message = "<script>alert(1)</script>"
+
+	message, err := Render(TemplateNotification, DefaultLocale, data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := message.HTML; strings.Contains(got, "<script>") || !strings.Contains(got, "&lt;script&gt;") {
+		t.Errorf("HTML does not escape the message:\n%s", got)
+	}
+	if !strings.Contains(message.Text, "<script>") {
+		t.Errorf("text = %q, want the message as typed", message.Text)
+	}
+}
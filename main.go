@@ -54,6 +54,7 @@ func main() {
 
 	waitgroup, ctx := errgroup.WithContext(ctx)
 	go runTaskProcessor(ctx, waitgroup, config, redisOpt, store)
+	runTaskScheduler(ctx, waitgroup, config, redisOpt)
 	runGinServer(ctx, waitgroup, config, store, taskDistributor)
 
 	err = waitgroup.Wait()
@@ -99,7 +100,7 @@ func runTaskProcessor(
 	store db.Store,
 ) {
 	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+	taskProcessor := worker.NewRedisTaskProcessor(config, redisOpt, store, mailer)
 	log.Info().Msg("start task processor")
 	err := taskProcessor.Start()
 	if err != nil {
@@ -115,6 +116,28 @@ func runTaskProcessor(
 	})
 }
 
+func runTaskScheduler(
+	ctx context.Context,
+	waitgroup *errgroup.Group,
+	config util.Config,
+	redisOpt asynq.RedisClientOpt,
+) {
+	taskScheduler := worker.NewRedisTaskScheduler(config, redisOpt)
+	log.Info().Msg("start task scheduler")
+	err := taskScheduler.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start task scheduler")
+	}
+	waitgroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown task scheduler")
+
+		taskScheduler.Shutdown()
+		log.Info().Msg("task scheduler is stopped")
+		return nil
+	})
+}
+
 // runDBMigration runs db migration when server starts
 func runDBMigration(migrationURL string, dbSource string) {
 	migration, err := migrate.New(migrationURL, dbSource)
@@ -0,0 +1,235 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fencePattern         = regexp.MustCompile("^(```+|~~~+)\\s*([\\w+-]*)")
+	rulePattern          = regexp.MustCompile(`^ {0,3}([-*_])(\s*([-*_])){2,}\s*$`)
+	unorderedPattern     = regexp.MustCompile(`^ {0,3}[-*+]\s+(.*)$`)
+	orderedPattern       = regexp.MustCompile(`^ {0,3}\d{1,9}[.)]\s+(.*)$`)
+	blockquotePattern    = regexp.MustCompile(`^ {0,3}>\s?(.*)$`)
+	indentedPattern      = regexp.MustCompile(`^( {4}|\t)(.*)$`)
+	codeSpanPattern      = regexp.MustCompile("(`+)(.+?)(`+)")
+	imagePattern         = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	linkPattern          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	autolinkPattern      = regexp.MustCompile(`&lt;((?:https?|mailto):[^\s&]+)&gt;`)
+	strongPattern        = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	emphasisPattern      = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*|\b_(\S(?:.*?\S)?)_\b`)
+	strikethroughPattern = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	placeholderPattern   = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// Render converts Markdown into sanitized HTML.
+// Raw HTML in the source is escaped, and the result is passed through the allow-list sanitizer.
+func Render(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	// NUL marks inline placeholders while rendering
+	source = strings.ReplaceAll(source, "\x00", "")
+	lines := strings.Split(source, "\n")
+
+	var out strings.Builder
+	renderBlocks(&out, lines)
+
+	return Sanitize(out.String())
+}
+
+// renderBlocks renders block level elements: headings, code blocks, quotes, lists, rules and paragraphs
+func renderBlocks(out *strings.Builder, lines []string) {
+	var paragraph []string
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+
+		case fencePattern.MatchString(trimmed):
+			flushParagraph()
+			match := fencePattern.FindStringSubmatch(trimmed)
+			fence := match[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			writeCodeBlock(out, code, match[2])
+
+		case len(paragraph) == 0 && indentedPattern.MatchString(line):
+			var code []string
+			for ; i < len(lines); i++ {
+				if match := indentedPattern.FindStringSubmatch(lines[i]); match != nil {
+					code = append(code, match[2])
+				} else if strings.TrimSpace(lines[i]) == "" {
+					code = append(code, "")
+				} else {
+					break
+				}
+			}
+			i--
+			writeCodeBlock(out, trimTrailingBlank(code), "")
+
+		case headingPattern.MatchString(trimmed):
+			flushParagraph()
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := strconv.Itoa(len(match[1]))
+			out.WriteString("<h" + level + ">" + renderInline(match[2]) + "</h" + level + ">\n")
+
+		case rulePattern.MatchString(line):
+			flushParagraph()
+			out.WriteString("<hr>\n")
+
+		case blockquotePattern.MatchString(line):
+			flushParagraph()
+			var quote []string
+			for ; i < len(lines); i++ {
+				match := blockquotePattern.FindStringSubmatch(lines[i])
+				if match == nil {
+					break
+				}
+				quote = append(quote, match[1])
+			}
+			i--
+			out.WriteString("<blockquote>\n")
+			renderBlocks(out, quote)
+			out.WriteString("</blockquote>\n")
+
+		case unorderedPattern.MatchString(line), orderedPattern.MatchString(line):
+			flushParagraph()
+			pattern, tag := unorderedPattern, "ul"
+			if orderedPattern.MatchString(line) {
+				pattern, tag = orderedPattern, "ol"
+			}
+
+			var items []string
+			for ; i < len(lines); i++ {
+				if match := pattern.FindStringSubmatch(lines[i]); match != nil {
+					items = append(items, match[1])
+				} else if strings.TrimSpace(lines[i]) != "" && len(items) > 0 && strings.HasPrefix(lines[i], " ") {
+					// an indented line continues the previous item
+					items[len(items)-1] += "\n" + strings.TrimSpace(lines[i])
+				} else {
+					break
+				}
+			}
+			i--
+
+			out.WriteString("<" + tag + ">\n")
+			for _, item := range items {
+				out.WriteString("<li>" + renderInline(item) + "</li>\n")
+			}
+			out.WriteString("</" + tag + ">\n")
+
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+
+	flushParagraph()
+}
+
+func writeCodeBlock(out *strings.Builder, code []string, language string) {
+	out.WriteString("<pre><code")
+	if language != "" {
+		out.WriteString(` class="language-` + html.EscapeString(language) + `"`)
+	}
+	out.WriteString(">")
+	for _, line := range code {
+		out.WriteString(html.EscapeString(line) + "\n")
+	}
+	out.WriteString("</code></pre>\n")
+}
+
+func trimTrailingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// renderInline renders code spans, images, links, emphasis and line breaks.
+// The text is escaped first, so any raw HTML in it ends up as literal text.
+func renderInline(text string) string {
+	text = html.EscapeString(text)
+
+	// every rendered element is swapped for a placeholder, so later patterns never look inside
+	// its tags and emphasis cannot end up in an href or close outside the element it opened in
+	var spans []string
+	keep := func(rendered string) string {
+		spans = append(spans, rendered)
+		return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+	}
+
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := codeSpanPattern.FindStringSubmatch(match)
+		if parts[1] != parts[3] {
+			return match
+		}
+		return keep("<code>" + strings.TrimSpace(parts[2]) + "</code>")
+	})
+
+	text = imagePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := imagePattern.FindStringSubmatch(match)
+		image := `<img src="` + parts[2] + `" alt="` + parts[1] + `"`
+		if parts[3] != "" {
+			image += ` title="` + parts[3] + `"`
+		}
+		return keep(image + ">")
+	})
+
+	var emphasize func(text string) string
+	emphasize = func(text string) string {
+		for _, element := range []struct {
+			pattern *regexp.Regexp
+			tag     string
+		}{
+			{strikethroughPattern, "del"},
+			{strongPattern, "strong"},
+			{emphasisPattern, "em"},
+		} {
+			text = element.pattern.ReplaceAllStringFunc(text, func(match string) string {
+				parts := element.pattern.FindStringSubmatch(match)
+				inner := strings.Join(parts[1:], "")
+				return keep("<" + element.tag + ">" + emphasize(inner) + "</" + element.tag + ">")
+			})
+		}
+		return text
+	}
+
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := linkPattern.FindStringSubmatch(match)
+		link := `<a href="` + parts[2] + `"`
+		if parts[3] != "" {
+			link += ` title="` + parts[3] + `"`
+		}
+		return keep(link + ">" + emphasize(parts[1]) + "</a>")
+	})
+
+	text = autolinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		link := autolinkPattern.FindStringSubmatch(match)[1]
+		return keep(`<a href="` + link + `">` + link + "</a>")
+	})
+	text = emphasize(text)
+	text = strings.ReplaceAll(text, "  \n", "<br>\n")
+
+	var expand func(text string) string
+	expand = func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+			index, _ := strconv.Atoi(placeholderPattern.FindStringSubmatch(match)[1])
+			return expand(spans[index])
+		})
+	}
+	return expand(text)
+}
@@ -0,0 +1,107 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	nethtml "golang.org/x/net/html"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one\ntwo</p>\n<p>three</p>\n"},
+		{"heading", "## Title ##", "<h2>Title</h2>\n"},
+		{"rule", "---", "<hr>\n"},
+		{"strong, emphasis and strike", "**a** *b* _c_ ~~d~~", "<p><strong>a</strong> <em>b</em> <em>c</em> <del>d</del></p>\n"},
+		{"emphasis inside strong", "**a *b* c**", "<p><strong>a <em>b</em> c</strong></p>\n"},
+		{"crossed markers stay balanced", "**a *b** c*", "<p><strong>a *b</strong> c*</p>\n"},
+		{"snake case is not emphasis", "snake_case_name", "<p>snake_case_name</p>\n"},
+		{"code span is literal", "`**x** <b>`", "<p><code>**x** &lt;b&gt;</code></p>\n"},
+		{"line break", "a  \nb", "<p>a<br>\nb</p>\n"},
+		{"link", `[site](https://example.com "Home")`,
+			`<p><a href="https://example.com" title="Home" rel="nofollow noopener noreferrer">site</a></p>` + "\n"},
+		{"emphasis in link text", "[**bold**](https://example.com)",
+			`<p><a href="https://example.com" rel="nofollow noopener noreferrer"><strong>bold</strong></a></p>` + "\n"},
+		{"markers in a URL are left alone", "[x](https://example.com/a_b_c/*d*/~~e~~) and *f*",
+			`<p><a href="https://example.com/a_b_c/*d*/~~e~~" rel="nofollow noopener noreferrer">x</a> and <em>f</em></p>` + "\n"},
+		{"markers in an image URL are left alone", "![a_b](https://example.com/**x**.png)",
+			`<p><img src="https://example.com/**x**.png" alt="a_b"></p>` + "\n"},
+		{"emphasis around a link", "*see [x](https://example.com/_y_)*",
+			`<p><em>see <a href="https://example.com/_y_" rel="nofollow noopener noreferrer">x</a></em></p>` + "\n"},
+		{"autolink", "<https://example.com/a_b_>",
+			`<p><a href="https://example.com/a_b_" rel="nofollow noopener noreferrer">https://example.com/a_b_</a></p>` + "\n"},
+		{"fenced code", "```go\nfmt.Println(\"<x>\")\n```", "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;x&gt;&#34;)\n</code></pre>\n"},
+		{"indented code", "    a := 1\n    b := 2", "<pre><code>a := 1\nb := 2\n</code></pre>\n"},
+		{"quote", "> quoted *text*", "<blockquote>\n<p>quoted <em>text</em></p>\n</blockquote>\n"},
+		{"unordered list", "- one\n- two\n  more", "<ul>\n<li>one</li>\n<li>two\nmore</li>\n</ul>\n"},
+		{"ordered list", "1. one\n2) two", "<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"NUL is dropped", "a\x000\x00b", "<p>a0b</p>\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Render(tc.source); got != tc.want {
+				t.Errorf("Render(%q) =\n%q\nwant\n%q", tc.source, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderXSS(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+	}{
+		{"raw script", "<script>alert(1)</script>"},
+		{"raw event attribute", `<img src=x onerror="alert(1)">`},
+		{"raw svg", `<svg onload="alert(1)"><circle r="1"/></svg>`},
+		{"javascript link", "[x](javascript:alert(1))"},
+		{"javascript link in upper case", "[x](JaVaScRiPt:alert(1))"},
+		{"javascript image", "![x](javascript:alert(1))"},
+		{"data link", "[x](data:text/html;base64,PHNjcmlwdD4=)"},
+		{"quote breaking out of the href", `[x](https://example.com/"onmouseover="alert(1))`},
+		{"quote breaking out of the title", `[x](https://example.com "a" onmouseover="alert(1)")`},
+		{"entity encoded scheme", "[x](jav&#x09;ascript:alert(1))"},
+		{"emphasis in a javascript URL", "[x](javascript:*alert(1)*)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Render(tc.source)
+			if problem := unsafeMarkup(got); problem != "" {
+				t.Errorf("Render(%q) = %q: %s", tc.source, got, problem)
+			}
+		})
+	}
+}
+
+// unsafeMarkup reads HTML the way a browser would and describes the first element or attribute that could run script
+func unsafeMarkup(fragment string) string {
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			return ""
+		}
+		if tokenType != nethtml.StartTagToken && tokenType != nethtml.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		if _, ok := allowedElements[token.Data]; !ok {
+			return "element " + token.Data
+		}
+		for _, attr := range token.Attr {
+			if strings.HasPrefix(attr.Key, "on") {
+				return "attribute " + attr.Key
+			}
+			value := strings.ToLower(strings.Join(strings.Fields(attr.Val), ""))
+			if (attr.Key == "href" || attr.Key == "src") && (strings.HasPrefix(value, "javascript:") || strings.HasPrefix(value, "data:")) {
+				return attr.Key + " " + attr.Val
+			}
+		}
+	}
+}
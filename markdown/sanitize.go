@@ -0,0 +1,163 @@
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// allowedElements lists the tags kept by Sanitize together with the attributes allowed on them
+var allowedElements = map[string]map[string]bool{
+	"p":          {},
+	"br":         {},
+	"hr":         {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"h5":         {},
+	"h6":         {},
+	"strong":     {},
+	"em":         {},
+	"del":        {},
+	"blockquote": {},
+	"ul":         {},
+	"ol":         {},
+	"li":         {},
+	"pre":        {},
+	"code":       {"class": true},
+	"a":          {"href": true, "title": true},
+	"img":        {"src": true, "alt": true, "title": true},
+}
+
+// droppedElements are removed together with everything inside them
+var droppedElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"template": true,
+	"noscript": true,
+}
+
+// urlAttributes hold links that must use a safe scheme
+var urlAttributes = map[string]bool{
+	"href": true,
+	"src":  true,
+}
+
+// codeClassPattern matches the language class of fenced code blocks
+var codeClassPattern = regexp.MustCompile(`^language-[\w+-]+$`)
+
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// Sanitize keeps only allow-listed elements and attributes of an HTML fragment.
+// Other tags are removed while their text is kept, and links with unsafe schemes are dropped.
+// Elements are balanced: stray end tags are dropped and elements left open are closed at the end,
+// so the fragment cannot leave a link or emphasis open in the page it is embedded in.
+func Sanitize(fragment string) string {
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(fragment))
+
+	var out strings.Builder
+	var open []string
+	skipDepth := 0
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			for i := len(open) - 1; i >= 0; i-- {
+				out.WriteString("</" + open[i] + ">")
+			}
+			return out.String()
+		}
+		token := tokenizer.Token()
+
+		switch tokenType {
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedElements[token.Data] {
+				if tokenType == nethtml.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			attributes, ok := allowedElements[token.Data]
+			if !ok || skipDepth > 0 {
+				continue
+			}
+			writeStartTag(&out, token, attributes)
+			if tokenType == nethtml.StartTagToken && !isVoidElement(token.Data) {
+				open = append(open, token.Data)
+			}
+
+		case nethtml.EndTagToken:
+			if droppedElements[token.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if _, ok := allowedElements[token.Data]; !ok || skipDepth > 0 || isVoidElement(token.Data) {
+				continue
+			}
+			// close the element together with the ones opened inside it, an end tag of an element that is not open is dropped
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.Data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+
+		case nethtml.TextToken:
+			if skipDepth == 0 {
+				out.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+}
+
+func writeStartTag(out *strings.Builder, token nethtml.Token, attributes map[string]bool) {
+	out.WriteString("<" + token.Data)
+	for _, attr := range token.Attr {
+		if !attributes[attr.Key] {
+			continue
+		}
+		if urlAttributes[attr.Key] && !isSafeURL(attr.Val) {
+			continue
+		}
+		if attr.Key == "class" && !codeClassPattern.MatchString(attr.Val) {
+			continue
+		}
+		out.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	if token.Data == "a" {
+		out.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	out.WriteString(">")
+}
+
+// isSafeURL allows relative links and absolute links with an allow-listed scheme
+func isSafeURL(rawURL string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	if parsed.Scheme == "" {
+		return !strings.HasPrefix(strings.TrimSpace(rawURL), "//")
+	}
+	return allowedSchemes[strings.ToLower(parsed.Scheme)]
+}
+
+func isVoidElement(tag string) bool {
+	return tag == "br" || tag == "hr" || tag == "img"
+}
@@ -0,0 +1,46 @@
+package markdown
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{"allowed markup", "<p><strong>a</strong> <em>b</em></p>", "<p><strong>a</strong> <em>b</em></p>"},
+		{"unknown tag keeps its text", "<div><span>text</span></div>", "text"},
+		{"script is dropped with its content", "a<script>alert(1)</script>b", "ab"},
+		{"style is dropped with its content", "<style>p{color:red}</style>text", "text"},
+		{"nested dropped elements", "<iframe><script>x</script>y</iframe>z", "z"},
+		{"event attributes are dropped", `<img src="a.png" onerror="alert(1)" alt="a">`, `<img src="a.png" alt="a">`},
+		{"svg is removed", `<svg onload="alert(1)"><a href="https://example.com">x</a></svg>`,
+			`<a href="https://example.com" rel="nofollow noopener noreferrer">x</a>`},
+		{"svg script is dropped", "<svg><script>alert(1)</script></svg>", ""},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript href with spaces and case", `<a href="  JavaScript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript href with a tab", "<a href=\"java\tscript:alert(1)\">x</a>", `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"entity encoded javascript href", `<a href="&#106;avascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"data src", `<img src="data:image/svg+xml;base64,PHN2Zz4=">`, "<img>"},
+		{"protocol relative src", `<img src="//evil.example/x.png">`, "<img>"},
+		{"relative and mailto links", `<a href="/course/1">a</a><a href="mailto:a@example.com">b</a>`,
+			`<a href="/course/1" rel="nofollow noopener noreferrer">a</a><a href="mailto:a@example.com" rel="nofollow noopener noreferrer">b</a>`},
+		{"rel cannot be set", `<a href="/x" rel="opener" target="_blank">x</a>`, `<a href="/x" rel="nofollow noopener noreferrer">x</a>`},
+		{"code class must be a language", `<code class="language-go">a</code><code class="x onclick">b</code>`,
+			`<code class="language-go">a</code><code>b</code>`},
+		{"unclosed link is closed", `<p><a href="/x">link`, `<p><a href="/x" rel="nofollow noopener noreferrer">link</a></p>`},
+		{"unclosed strong is closed", "<strong>bold", "<strong>bold</strong>"},
+		{"stray end tags are dropped", "</a></strong>text</p>", "text"},
+		{"misnested tags are closed in order", "<strong><em>a</strong>b</em>", "<strong><em>a</em></strong>b"},
+		{"void elements are never closed", "<br><hr></br><img src=\"a.png\">", `<br><hr><img src="a.png">`},
+		{"text is escaped", "a &lt; b &amp; \"c\"", "a &lt; b &amp; &#34;c&#34;"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Sanitize(tc.fragment); got != tc.want {
+				t.Errorf("Sanitize(%q) =\n%q\nwant\n%q", tc.fragment, got, tc.want)
+			}
+		})
+	}
+}
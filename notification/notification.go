@@ -0,0 +1,70 @@
+package notification
+
+// types of notifications, a user sets their preferences per type
+const (
+	TypeGrade        = "grade"
+	TypeEnrollment   = "enrollment"
+	TypeMaterial     = "material"
+	TypeAnnouncement = "announcement"
+	TypeDueDate      = "due_date"
+)
+
+// Types lists every notification type in the order preferences are shown
+var Types = []string{TypeGrade, TypeEnrollment, TypeMaterial, TypeAnnouncement, TypeDueDate}
+
+// ValidType reports whether t is one of Types
+func ValidType(t string) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is a message to one user
+type Notification struct {
+	UserID int64
+	Type   string
+	// CourseID is the course the notification is about, zero when it is about none
+	CourseID int64
+	Title    string
+	Message  string
+	// Template is the email template the notification is emailed with, the generic notification template when empty.
+	// Data holds the values it words the email with, such as the CourseTitle of an enrollment.
+	Template string
+	Data     map[string]string
+	// Key identifies the notification, delivering a notification with a key the user already has is a no-op.
+	// It keeps a retried task from notifying twice.
+	Key string
+}
+
+// Preference is how a user wants to receive a type of notification
+type Preference struct {
+	Type  string `json:"type"`
+	InApp bool   `json:"in_app"`
+	Email bool   `json:"email"`
+}
+
+// DefaultPreference is used for the types a user has not set, every notification is shown in the app and emailed
+func DefaultPreference(t string) Preference {
+	return Preference{Type: t, InApp: true, Email: true}
+}
+
+// Preferences returns a preference for every type in Types, the stored ones override the defaults
+func Preferences(stored []Preference) []Preference {
+	byType := make(map[string]Preference, len(stored))
+	for _, preference := range stored {
+		byType[preference.Type] = preference
+	}
+
+	preferences := make([]Preference, 0, len(Types))
+	for _, t := range Types {
+		preference, ok := byType[t]
+		if !ok {
+			preference = DefaultPreference(t)
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences
+}
@@ -0,0 +1,51 @@
+package payments
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kinds of coupon discounts
+const (
+	// CouponPercent takes a percentage off the price
+	CouponPercent = "percent"
+	// CouponFixed takes a fixed amount off the price, in the smallest unit of the coupon's currency
+	CouponFixed = "fixed"
+)
+
+// Discount returns how much a coupon takes off an amount, never more than the amount itself
+func Discount(amount int64, kind string, value int64) int64 {
+	var discount int64
+	switch kind {
+	case CouponPercent:
+		discount = amount * value / 100
+	case CouponFixed:
+		discount = value
+	}
+	if discount > amount {
+		return amount
+	}
+	if discount < 0 {
+		return 0
+	}
+	return discount
+}
+
+// NormalizeCurrency checks a three letter ISO 4217 currency code and returns it in lower case
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToLower(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return "", fmt.Errorf("invalid currency %q, expected a three letter code", currency)
+	}
+	for _, r := range currency {
+		if r < 'a' || r > 'z' {
+			return "", fmt.Errorf("invalid currency %q, expected a three letter code", currency)
+		}
+	}
+	return currency, nil
+}
+
+// NormalizeCouponCode makes coupon codes case insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,34 @@
+package payments
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFakeProviderSignedEvent(t *testing.T) {
+	provider := NewFakeProvider("secret")
+	event := Event{ID: "evt_1", Type: EventPaymentSucceeded, OrderID: 7, SessionID: "fake_cs_7", PaymentID: "pay_7", Amount: 1000, Currency: "usd"}
+
+	payload, header, err := provider.SignedEvent(event)
+	if err != nil {
+		t.Fatalf("SignedEvent() error = %v", err)
+	}
+
+	got, err := provider.ParseWebhook(payload, header)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if got != event {
+		t.Errorf("ParseWebhook() = %+v, want %+v", got, event)
+	}
+
+	if _, err := NewFakeProvider("other").ParseWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseWebhook() with another secret error = %v, want ErrInvalidSignature", err)
+	}
+
+	tampered := append([]byte(nil), payload...)
+	tampered[len(tampered)-2] = '9'
+	if _, err := provider.ParseWebhook(tampered, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseWebhook() of a tampered payload error = %v, want ErrInvalidSignature", err)
+	}
+}
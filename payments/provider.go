@@ -0,0 +1,89 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// kinds of payment events a provider reports through its webhook
+const (
+	// EventPaymentSucceeded is a checkout that was paid in full
+	EventPaymentSucceeded = "payment_succeeded"
+	// EventPaymentFailed is a checkout whose payment was declined
+	EventPaymentFailed = "payment_failed"
+	// EventCheckoutExpired is a checkout that was abandoned
+	EventCheckoutExpired = "checkout_expired"
+	// EventRefunded is a payment that was refunded in full
+	EventRefunded = "refunded"
+	// EventIgnored is any other event, it is acknowledged and not acted on
+	EventIgnored = "ignored"
+)
+
+// ErrInvalidSignature is returned for a webhook request that was not signed by the provider
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Provider takes payments for orders through a hosted checkout page
+type Provider interface {
+	// Name identifies the provider on the orders it created
+	Name() string
+
+	// CreateCheckoutSession starts a hosted checkout for an order
+	CreateCheckoutSession(ctx context.Context, req CheckoutRequest) (CheckoutSession, error)
+
+	// ParseWebhook verifies the signature of a webhook request and reads the event it carries
+	ParseWebhook(payload []byte, header http.Header) (Event, error)
+
+	// Refund gives back the full amount of a payment
+	Refund(ctx context.Context, paymentID string) error
+}
+
+// CheckoutRequest describes what a student is about to pay for, amounts are in the smallest unit of the currency
+type CheckoutRequest struct {
+	OrderID       int64
+	Title         string
+	Currency      string
+	Amount        int64
+	CustomerEmail string
+	SuccessURL    string
+	CancelURL     string
+}
+
+// CheckoutSession is a hosted checkout page the student is sent to
+type CheckoutSession struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// Event is a payment event read from a webhook. Checkout events carry the order and session,
+// refunds only carry the payment.
+type Event struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	OrderID   int64  `json:"order_id"`
+	SessionID string `json:"session_id"`
+	PaymentID string `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+// NewProvider returns the provider configured by name, an empty name turns payments off
+func NewProvider(name, secretKey, webhookSecret string) (Provider, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "stripe":
+		if secretKey == "" || webhookSecret == "" {
+			return nil, errors.New("the stripe provider needs a secret key and a webhook secret")
+		}
+		return NewStripeProvider(secretKey, webhookSecret), nil
+	case "fake":
+		if webhookSecret == "" {
+			return nil, errors.New("the fake provider needs a webhook secret")
+		}
+		return NewFakeProvider(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+}
@@ -0,0 +1,246 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeAPIURL = "https://api.stripe.com"
+	// stripeSignatureTolerance is how old a signed webhook may be before it is treated as a replay
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeProvider takes payments with Stripe Checkout, or any service that speaks the same API
+type StripeProvider struct {
+	secretKey     string
+	webhookSecret string
+	baseURL       string
+	client        *http.Client
+	now           func() time.Time
+}
+
+// NewStripeProvider creates a provider for the Stripe API
+func NewStripeProvider(secretKey, webhookSecret string) *StripeProvider {
+	return &StripeProvider{
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		baseURL:       stripeAPIURL,
+		client:        &http.Client{Timeout: 15 * time.Second},
+		now:           time.Now,
+	}
+}
+
+// WithBaseURL points the provider at a Stripe compatible API, such as a mock server
+func (provider *StripeProvider) WithBaseURL(baseURL string) *StripeProvider {
+	provider.baseURL = strings.TrimRight(baseURL, "/")
+	return provider
+}
+
+func (provider *StripeProvider) Name() string {
+	return "stripe"
+}
+
+func (provider *StripeProvider) CreateCheckoutSession(ctx context.Context, req CheckoutRequest) (CheckoutSession, error) {
+	orderID := strconv.FormatInt(req.OrderID, 10)
+
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", req.SuccessURL)
+	form.Set("cancel_url", req.CancelURL)
+	form.Set("client_reference_id", orderID)
+	form.Set("metadata[order_id]", orderID)
+	form.Set("payment_intent_data[metadata][order_id]", orderID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", req.Currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(req.Amount, 10))
+	form.Set("line_items[0][price_data][product_data][name]", req.Title)
+	if req.CustomerEmail != "" {
+		form.Set("customer_email", req.CustomerEmail)
+	}
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	// the order id keeps a retried request from opening a second checkout
+	err := provider.post(ctx, "/v1/checkout/sessions", form, "checkout-order-"+orderID, &session)
+	if err != nil {
+		return CheckoutSession{}, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	return CheckoutSession{ID: session.ID, URL: session.URL}, nil
+}
+
+func (provider *StripeProvider) Refund(ctx context.Context, paymentID string) error {
+	form := url.Values{}
+	form.Set("payment_intent", paymentID)
+
+	err := provider.post(ctx, "/v1/refunds", form, "refund-"+paymentID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+	return nil
+}
+
+// post sends a form encoded request to the API and decodes the JSON response into out when it is set
+func (provider *StripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+provider.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+
+	rsp, err := provider.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+
+	if rsp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe: unexpected status %d", rsp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+// stripeEvent is the part of a Stripe event the webhook needs, the object is either a checkout session or a charge
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID                string `json:"id"`
+			ClientReferenceID string `json:"client_reference_id"`
+			PaymentStatus     string `json:"payment_status"`
+			PaymentIntent     string `json:"payment_intent"`
+			AmountTotal       int64  `json:"amount_total"`
+			Currency          string `json:"currency"`
+			Refunded          bool   `json:"refunded"`
+			AmountRefunded    int64  `json:"amount_refunded"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+func (provider *StripeProvider) ParseWebhook(payload []byte, header http.Header) (Event, error) {
+	if err := provider.verifySignature(payload, header.Get("Stripe-Signature")); err != nil {
+		return Event{}, err
+	}
+
+	var raw stripeEvent
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return Event{}, fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	object := raw.Data.Object
+	event := Event{
+		ID:       raw.ID,
+		Type:     EventIgnored,
+		Currency: object.Currency,
+	}
+
+	switch raw.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		// a delayed payment method completes the session unpaid and succeeds later
+		if object.PaymentStatus == "paid" {
+			event.Type = EventPaymentSucceeded
+		}
+	case "checkout.session.async_payment_failed":
+		event.Type = EventPaymentFailed
+	case "checkout.session.expired":
+		event.Type = EventCheckoutExpired
+	case "charge.refunded":
+		// partial refunds keep access to the course
+		if object.Refunded {
+			event.Type = EventRefunded
+		}
+		event.PaymentID = object.PaymentIntent
+		event.Amount = object.AmountRefunded
+		return event, nil
+	}
+	if event.Type == EventIgnored {
+		return event, nil
+	}
+
+	orderID, err := strconv.ParseInt(object.ClientReferenceID, 10, 64)
+	if err != nil {
+		return Event{}, fmt.Errorf("checkout session %s has no order reference", object.ID)
+	}
+	event.OrderID = orderID
+	event.SessionID = object.ID
+	event.PaymentID = object.PaymentIntent
+	event.Amount = object.AmountTotal
+	return event, nil
+}
+
+// verifySignature checks a Stripe-Signature header of the form t=<unix time>,v1=<hex hmac>[,v1=...]
+func (provider *StripeProvider) verifySignature(payload []byte, signatureHeader string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := provider.now().Sub(time.Unix(unix, 0))
+	if age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(provider.webhookSecret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
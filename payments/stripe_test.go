@@ -0,0 +1,146 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec_test"
+
+func newTestStripeProvider(now time.Time) *StripeProvider {
+	provider := NewStripeProvider("sk_test", testWebhookSecret)
+	provider.now = func() time.Time { return now }
+	return provider
+}
+
+func stripeSignature(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestStripeVerifySignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	payload := []byte(`{"id":"evt_1"}`)
+	good := stripeSignature(testWebhookSecret, now.Unix(), payload)
+
+	testCases := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{"valid", fmt.Sprintf("t=%d,v1=%s", now.Unix(), good), false},
+		{"valid with spaces and v0", fmt.Sprintf("t=%d, v0=abc, v1=%s", now.Unix(), good), false},
+		{"second of several v1 values", fmt.Sprintf("t=%d,v1=%s,v1=%s", now.Unix(), stripeSignature("old_secret", now.Unix(), payload), good), false},
+		{"first of several v1 values", fmt.Sprintf("t=%d,v1=%s,v1=00", now.Unix(), good), false},
+		{"bad hex next to a good value", fmt.Sprintf("t=%d,v1=zz,v1=%s", now.Unix(), good), false},
+		{
+			"inside the tolerance window",
+			fmt.Sprintf("t=%d,v1=%s", now.Add(-4*time.Minute).Unix(), stripeSignature(testWebhookSecret, now.Add(-4*time.Minute).Unix(), payload)),
+			false,
+		},
+		{
+			"too old",
+			fmt.Sprintf("t=%d,v1=%s", now.Add(-6*time.Minute).Unix(), stripeSignature(testWebhookSecret, now.Add(-6*time.Minute).Unix(), payload)),
+			true,
+		},
+		{
+			"too far in the future",
+			fmt.Sprintf("t=%d,v1=%s", now.Add(6*time.Minute).Unix(), stripeSignature(testWebhookSecret, now.Add(6*time.Minute).Unix(), payload)),
+			true,
+		},
+		{"timestamp not signed", fmt.Sprintf("t=%d,v1=%s", now.Unix()+1, good), true},
+		{"wrong secret", fmt.Sprintf("t=%d,v1=%s", now.Unix(), stripeSignature("other", now.Unix(), payload)), true},
+		{"bad hex", fmt.Sprintf("t=%d,v1=not-hex", now.Unix()), true},
+		{"only bad values", fmt.Sprintf("t=%d,v1=zz,v1=00ff", now.Unix()), true},
+		{"missing timestamp", "v1=" + good, true},
+		{"missing signature", fmt.Sprintf("t=%d", now.Unix()), true},
+		{"non numeric timestamp", "t=yesterday,v1=" + good, true},
+		{"empty header", "", true},
+	}
+
+	provider := newTestStripeProvider(now)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := provider.verifySignature(payload, tc.header)
+			if tc.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("verifySignature() error = %v, want ErrInvalidSignature", err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("verifySignature() error = %v, want nil", err)
+			}
+		})
+	}
+
+	if err := provider.verifySignature([]byte(`{"id":"evt_2"}`), fmt.Sprintf("t=%d,v1=%s", now.Unix(), good)); err == nil {
+		t.Error("verifySignature() accepted a signature of another payload")
+	}
+}
+
+func TestStripeParseWebhook(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	provider := newTestStripeProvider(now)
+
+	session := func(eventType, paymentStatus, reference string) string {
+		return fmt.Sprintf(`{"id":"evt_1","type":%q,"data":{"object":{"id":"cs_1","client_reference_id":%q,`+
+			`"payment_status":%q,"payment_intent":"pi_1","amount_total":4900,"currency":"eur"}}}`,
+			eventType, reference, paymentStatus)
+	}
+	charge := func(refunded bool) string {
+		return fmt.Sprintf(`{"id":"evt_2","type":"charge.refunded","data":{"object":{"id":"ch_1",`+
+			`"payment_intent":"pi_1","currency":"eur","refunded":%t,"amount_refunded":4900}}}`, refunded)
+	}
+	checkout := func(eventType string) Event {
+		return Event{ID: "evt_1", Type: eventType, OrderID: 42, SessionID: "cs_1", PaymentID: "pi_1", Amount: 4900, Currency: "eur"}
+	}
+
+	testCases := []struct {
+		name    string
+		payload string
+		want    Event
+		wantErr bool
+	}{
+		{"completed and paid", session("checkout.session.completed", "paid", "42"), checkout(EventPaymentSucceeded), false},
+		{"completed unpaid", session("checkout.session.completed", "unpaid", "42"), Event{ID: "evt_1", Type: EventIgnored, Currency: "eur"}, false},
+		{"async payment succeeded", session("checkout.session.async_payment_succeeded", "paid", "42"), checkout(EventPaymentSucceeded), false},
+		{"async payment failed", session("checkout.session.async_payment_failed", "unpaid", "42"), checkout(EventPaymentFailed), false},
+		{"expired", session("checkout.session.expired", "unpaid", "42"), checkout(EventCheckoutExpired), false},
+		{"full refund", charge(true), Event{ID: "evt_2", Type: EventRefunded, PaymentID: "pi_1", Amount: 4900, Currency: "eur"}, false},
+		{"partial refund", charge(false), Event{ID: "evt_2", Type: EventIgnored, PaymentID: "pi_1", Amount: 4900, Currency: "eur"}, false},
+		{"other event", session("customer.created", "", ""), Event{ID: "evt_1", Type: EventIgnored, Currency: "eur"}, false},
+		{"missing order reference", session("checkout.session.completed", "paid", ""), Event{}, true},
+		{"invalid order reference", session("checkout.session.expired", "unpaid", "order-42"), Event{}, true},
+		{"invalid json", `{"id":`, Event{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := []byte(tc.payload)
+			header := http.Header{}
+			header.Set("Stripe-Signature", "t="+strconv.FormatInt(now.Unix(), 10)+",v1="+stripeSignature(testWebhookSecret, now.Unix(), payload))
+
+			event, err := provider.ParseWebhook(payload, header)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseWebhook() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if event != tc.want {
+				t.Errorf("ParseWebhook() = %+v, want %+v", event, tc.want)
+			}
+		})
+	}
+
+	t.Run("unsigned", func(t *testing.T) {
+		_, err := provider.ParseWebhook([]byte(session("checkout.session.completed", "paid", "42")), http.Header{})
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("ParseWebhook() error = %v, want ErrInvalidSignature", err)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package peerreview
+
+import (
+	"sort"
+)
+
+// Score is the peer score of a submission
+type Score struct {
+	// Points is the mean of the reviews that were kept
+	Points float64 `json:"points"`
+	// Reviews is the number of submitted reviews
+	Reviews int `json:"reviews"`
+	// Trimmed is the number of reviews dropped as outliers
+	Trimmed int `json:"trimmed"`
+}
+
+// Aggregate averages the points given by the reviewers of a submission. With three or more reviews the
+// highest and lowest fifth of them, at least one at each end, are dropped so a single careless or hostile
+// reviewer cannot move the score. ok is false when there are no reviews.
+func Aggregate(points []int64) (score Score, ok bool) {
+	score.Reviews = len(points)
+	if len(points) == 0 {
+		return score, false
+	}
+
+	sorted := make([]int64, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	trim := 0
+	if len(sorted) >= 3 {
+		trim = max(1, len(sorted)/5)
+	}
+	kept := sorted[trim : len(sorted)-trim]
+	score.Trimmed = 2 * trim
+
+	var total int64
+	for _, p := range kept {
+		total += p
+	}
+	score.Points = float64(total) / float64(len(kept))
+
+	return score, true
+}
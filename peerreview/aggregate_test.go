@@ -0,0 +1,42 @@
+package peerreview
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		points []int64
+		want   Score
+		wantOK bool
+	}{
+		{name: "no reviews", points: nil, want: Score{}},
+		{name: "one review", points: []int64{7}, want: Score{Points: 7, Reviews: 1}, wantOK: true},
+		{name: "two reviews are averaged", points: []int64{4, 9}, want: Score{Points: 6.5, Reviews: 2}, wantOK: true},
+		{name: "three reviews keep the median", points: []int64{10, 0, 6}, want: Score{Points: 6, Reviews: 3, Trimmed: 2}, wantOK: true},
+		{name: "hostile reviewer is dropped", points: []int64{8, 0, 9, 8, 10}, want: Score{Points: 25.0 / 3, Reviews: 5, Trimmed: 2}, wantOK: true},
+		{name: "a fifth is dropped at each end", points: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			want: Score{Points: 5.5, Reviews: 10, Trimmed: 4}, wantOK: true},
+		{name: "equal points", points: []int64{5, 5, 5, 5}, want: Score{Points: 5, Reviews: 4, Trimmed: 2}, wantOK: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			points := append([]int64(nil), tc.points...)
+			score, ok := Aggregate(points)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if math.Abs(score.Points-tc.want.Points) > 1e-9 || score.Reviews != tc.want.Reviews || score.Trimmed != tc.want.Trimmed {
+				t.Errorf("Aggregate(%v) = %+v, want %+v", tc.points, score, tc.want)
+			}
+			for i := range points {
+				if points[i] != tc.points[i] {
+					t.Fatalf("Aggregate reordered its input to %v", points)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package peerreview
+
+import (
+	"math/rand"
+)
+
+// Submission is a submission that takes part in peer review, its author reviews the work of others
+type Submission struct {
+	SubmissionID int64
+	UserID       int64
+}
+
+// Pair asks a reviewer to review a submission
+type Pair struct {
+	SubmissionID int64
+	ReviewerID   int64
+}
+
+// Assign shuffles the submissions into a ring and asks every author to review the next reviewsPerSubmission
+// submissions of the ring. Each submission gets the same number of reviewers, each author reviews that many
+// submissions and nobody reviews their own work. With fewer submissions than reviewsPerSubmission+1 every author
+// reviews all the others.
+func Assign(submissions []Submission, reviewsPerSubmission int, rng *rand.Rand) []Pair {
+	if len(submissions) < 2 || reviewsPerSubmission < 1 {
+		return []Pair{}
+	}
+	reviews := min(reviewsPerSubmission, len(submissions)-1)
+
+	ring := make([]Submission, len(submissions))
+	copy(ring, submissions)
+	rng.Shuffle(len(ring), func(i, j int) {
+		ring[i], ring[j] = ring[j], ring[i]
+	})
+
+	pairs := make([]Pair, 0, len(ring)*reviews)
+	for i, reviewer := range ring {
+		for offset := 1; offset <= reviews; offset++ {
+			pairs = append(pairs, Pair{
+				SubmissionID: ring[(i+offset)%len(ring)].SubmissionID,
+				ReviewerID:   reviewer.UserID,
+			})
+		}
+	}
+
+	return pairs
+}
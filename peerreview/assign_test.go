@@ -0,0 +1,88 @@
+package peerreview
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestAssign(t *testing.T) {
+	submissions := func(n int) []Submission {
+		list := make([]Submission, n)
+		for i := range list {
+			list[i] = Submission{SubmissionID: int64(100 + i), UserID: int64(i + 1)}
+		}
+		return list
+	}
+
+	testCases := []struct {
+		name                 string
+		submissions          []Submission
+		reviewsPerSubmission int
+		wantReviews          int
+	}{
+		{name: "three reviews each", submissions: submissions(10), reviewsPerSubmission: 3, wantReviews: 3},
+		{name: "one review each", submissions: submissions(2), reviewsPerSubmission: 1, wantReviews: 1},
+		{name: "fewer submissions than reviews", submissions: submissions(3), reviewsPerSubmission: 5, wantReviews: 2},
+		{name: "a single submission", submissions: submissions(1), reviewsPerSubmission: 3},
+		{name: "no submissions", submissions: nil, reviewsPerSubmission: 3},
+		{name: "no reviews asked", submissions: submissions(4), reviewsPerSubmission: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pairs := Assign(tc.submissions, tc.reviewsPerSubmission, rand.New(rand.NewSource(7)))
+			if pairs == nil || len(pairs) != len(tc.submissions)*tc.wantReviews {
+				t.Fatalf("got %d pairs, want %d", len(pairs), len(tc.submissions)*tc.wantReviews)
+			}
+
+			authors := make(map[int64]int64, len(tc.submissions))
+			for _, submission := range tc.submissions {
+				authors[submission.SubmissionID] = submission.UserID
+			}
+			reviewers := make(map[int64]int)
+			reviewed := make(map[int64]int)
+			seen := make(map[Pair]bool)
+			for _, pair := range pairs {
+				author, ok := authors[pair.SubmissionID]
+				if !ok {
+					t.Fatalf("pair %+v names an unknown submission", pair)
+				}
+				if author == pair.ReviewerID {
+					t.Errorf("user %d reviews their own submission %d", pair.ReviewerID, pair.SubmissionID)
+				}
+				if seen[pair] {
+					t.Errorf("pair %+v is assigned twice", pair)
+				}
+				seen[pair] = true
+				reviewers[pair.SubmissionID]++
+				reviewed[pair.ReviewerID]++
+			}
+			for _, submission := range tc.submissions {
+				if tc.wantReviews == 0 {
+					break
+				}
+				if reviewers[submission.SubmissionID] != tc.wantReviews {
+					t.Errorf("submission %d has %d reviewers, want %d", submission.SubmissionID, reviewers[submission.SubmissionID], tc.wantReviews)
+				}
+				if reviewed[submission.UserID] != tc.wantReviews {
+					t.Errorf("user %d reviews %d submissions, want %d", submission.UserID, reviewed[submission.UserID], tc.wantReviews)
+				}
+			}
+		})
+	}
+}
+
+func TestAssignDeterministic(t *testing.T) {
+	list := []Submission{{1, 11}, {2, 12}, {3, 13}, {4, 14}, {5, 15}}
+	input := append([]Submission(nil), list...)
+
+	first := Assign(list, 2, rand.New(rand.NewSource(42)))
+	second := Assign(list, 2, rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("the same seed gave %v and %v", first, second)
+	}
+	if !reflect.DeepEqual(list, input) {
+		t.Errorf("Assign shuffled its input to %v", list)
+	}
+}
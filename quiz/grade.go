@@ -0,0 +1,114 @@
+package quiz
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Question is a bank question as needed for grading
+type Question struct {
+	ID        int64
+	Type      string
+	AnswerKey AnswerKey
+	Points    int64
+}
+
+// Result is the outcome of grading an attempt
+type Result struct {
+	Score    int64 `json:"score"`
+	MaxScore int64 `json:"max_score"`
+	// Correct maps question IDs to whether they were answered correctly
+	Correct map[int64]bool `json:"correct"`
+}
+
+// Percentage returns the score as a whole percentage of the maximum score
+func (result Result) Percentage() int64 {
+	if result.MaxScore == 0 {
+		return 0
+	}
+	return int64(math.Round(float64(result.Score) * 100 / float64(result.MaxScore)))
+}
+
+// Grade scores the responses of an attempt. Questions are graded all or nothing,
+// unanswered questions score zero.
+func Grade(questions []Question, responses Responses) Result {
+	result := Result{Correct: make(map[int64]bool, len(questions))}
+
+	for _, question := range questions {
+		result.MaxScore += question.Points
+
+		response, ok := responses[question.ID]
+		correct := ok && IsCorrect(question.Type, question.AnswerKey, response)
+		if correct {
+			result.Score += question.Points
+		}
+		result.Correct[question.ID] = correct
+	}
+
+	return result
+}
+
+// IsCorrect tells whether a response matches the answer key of a question
+func IsCorrect(questionType string, key AnswerKey, response Response) bool {
+	switch questionType {
+	case SingleChoice, MultipleChoice:
+		return sameChoices(key.Choices, response.Choices)
+	case TrueFalse:
+		return key.Correct != nil && response.Correct != nil && *key.Correct == *response.Correct
+	case ShortAnswer:
+		return matchesPattern(key.Patterns, response.Text)
+	case Numeric:
+		if key.Value == nil || response.Value == nil || math.IsNaN(*response.Value) {
+			return false
+		}
+		return math.Abs(*key.Value-*response.Value) <= key.Tolerance
+	}
+	return false
+}
+
+// sameChoices compares the selected options ignoring order and repeats
+func sameChoices(expected, selected []int) bool {
+	if len(selected) == 0 {
+		return false
+	}
+	want := slices.Clone(expected)
+	got := slices.Clone(selected)
+	slices.Sort(want)
+	slices.Sort(got)
+	return slices.Equal(slices.Compact(want), slices.Compact(got))
+}
+
+func matchesPattern(patterns []string, text string) bool {
+	answer := normalizeText(text)
+	if answer == "" {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			continue
+		}
+		if re != nil && re.MatchString(answer) {
+			return true
+		}
+		if re == nil && normalizeText(pattern) == answer {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw picks count random question IDs from a pool, or all of them in random order when count is zero
+// or not smaller than the pool
+func Draw(pool []int64, count int, rng *rand.Rand) []int64 {
+	drawn := slices.Clone(pool)
+	rng.Shuffle(len(drawn), func(i, j int) {
+		drawn[i], drawn[j] = drawn[j], drawn[i]
+	})
+	if count > 0 && count < len(drawn) {
+		drawn = drawn[:count]
+	}
+	return drawn
+}
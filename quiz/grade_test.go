@@ -0,0 +1,207 @@
+package quiz
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestIsCorrect(t *testing.T) {
+	testCases := []struct {
+		name         string
+		questionType string
+		key          AnswerKey
+		response     Response
+		want         bool
+	}{
+		{"single choice right", SingleChoice, AnswerKey{Choices: []int{2}}, Response{Choices: []int{2}}, true},
+		{"single choice wrong", SingleChoice, AnswerKey{Choices: []int{2}}, Response{Choices: []int{1}}, false},
+		{"single choice extra", SingleChoice, AnswerKey{Choices: []int{2}}, Response{Choices: []int{1, 2}}, false},
+		{"choice without selection", SingleChoice, AnswerKey{Choices: []int{2}}, Response{}, false},
+		{"choice with empty selection", MultipleChoice, AnswerKey{Choices: []int{0}}, Response{Choices: []int{}}, false},
+		{"choice with empty key", MultipleChoice, AnswerKey{}, Response{}, false},
+		{"multiple choice any order", MultipleChoice, AnswerKey{Choices: []int{0, 2}}, Response{Choices: []int{2, 0}}, true},
+		{"multiple choice repeats", MultipleChoice, AnswerKey{Choices: []int{0, 2}}, Response{Choices: []int{2, 0, 2}}, true},
+		{"multiple choice partial", MultipleChoice, AnswerKey{Choices: []int{0, 2}}, Response{Choices: []int{0}}, false},
+		{"multiple choice superset", MultipleChoice, AnswerKey{Choices: []int{0, 2}}, Response{Choices: []int{0, 1, 2}}, false},
+		{"true/false right", TrueFalse, AnswerKey{Correct: boolPtr(false)}, Response{Correct: boolPtr(false)}, true},
+		{"true/false wrong", TrueFalse, AnswerKey{Correct: boolPtr(true)}, Response{Correct: boolPtr(false)}, false},
+		{"true/false unanswered", TrueFalse, AnswerKey{Correct: boolPtr(false)}, Response{}, false},
+		{"true/false without key", TrueFalse, AnswerKey{}, Response{Correct: boolPtr(false)}, false},
+		{"short answer exact", ShortAnswer, AnswerKey{Patterns: []string{"Paris"}}, Response{Text: "Paris"}, true},
+		{"short answer normalized", ShortAnswer, AnswerKey{Patterns: []string{"New  York"}}, Response{Text: "  new\tyork "}, true},
+		{"short answer second pattern", ShortAnswer, AnswerKey{Patterns: []string{"NYC", "new york"}}, Response{Text: "New York"}, true},
+		{"short answer wrong", ShortAnswer, AnswerKey{Patterns: []string{"Paris"}}, Response{Text: "Lyon"}, false},
+		{"short answer substring", ShortAnswer, AnswerKey{Patterns: []string{"Paris"}}, Response{Text: "Paris, France"}, false},
+		{"short answer blank", ShortAnswer, AnswerKey{Patterns: []string{"Paris"}}, Response{Text: "   "}, false},
+		{"short answer regex", ShortAnswer, AnswerKey{Patterns: []string{"/^colou?r$/"}}, Response{Text: "Color"}, true},
+		{"short answer regex other spelling", ShortAnswer, AnswerKey{Patterns: []string{"/^colou?r$/"}}, Response{Text: "COLOUR"}, true},
+		{"short answer regex miss", ShortAnswer, AnswerKey{Patterns: []string{"/^colou?r$/"}}, Response{Text: "colours"}, false},
+		{"short answer unanchored regex", ShortAnswer, AnswerKey{Patterns: []string{"/gr[ae]y/"}}, Response{Text: "dark grey"}, true},
+		{"short answer regex sees normalized text", ShortAnswer, AnswerKey{Patterns: []string{"/^a b$/"}}, Response{Text: " a   b "}, true},
+		{"short answer invalid regex skipped", ShortAnswer, AnswerKey{Patterns: []string{"/(/", "paris"}}, Response{Text: "paris"}, true},
+		{"short answer empty regex never matches", ShortAnswer, AnswerKey{Patterns: []string{"//"}}, Response{Text: "anything"}, false},
+		{"numeric exact", Numeric, AnswerKey{Value: floatPtr(42)}, Response{Value: floatPtr(42)}, true},
+		{"numeric without tolerance", Numeric, AnswerKey{Value: floatPtr(42)}, Response{Value: floatPtr(42.001)}, false},
+		{"numeric within tolerance", Numeric, AnswerKey{Value: floatPtr(3.14), Tolerance: 0.01}, Response{Value: floatPtr(3.145)}, true},
+		{"numeric on tolerance edge", Numeric, AnswerKey{Value: floatPtr(10), Tolerance: 0.5}, Response{Value: floatPtr(9.5)}, true},
+		{"numeric outside tolerance", Numeric, AnswerKey{Value: floatPtr(3.14), Tolerance: 0.01}, Response{Value: floatPtr(3.2)}, false},
+		{"numeric NaN", Numeric, AnswerKey{Value: floatPtr(1), Tolerance: math.Inf(1)}, Response{Value: floatPtr(math.NaN())}, false},
+		{"numeric infinity", Numeric, AnswerKey{Value: floatPtr(1), Tolerance: 1e9}, Response{Value: floatPtr(math.Inf(1))}, false},
+		{"numeric unanswered", Numeric, AnswerKey{Value: floatPtr(1)}, Response{}, false},
+		{"numeric without key", Numeric, AnswerKey{}, Response{Value: floatPtr(1)}, false},
+		{"unknown type", "essay", AnswerKey{Patterns: []string{"x"}}, Response{Text: "x"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsCorrect(tc.questionType, tc.key, tc.response); got != tc.want {
+				t.Errorf("IsCorrect() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGrade(t *testing.T) {
+	questions := []Question{
+		{ID: 1, Type: SingleChoice, AnswerKey: AnswerKey{Choices: []int{1}}, Points: 2},
+		{ID: 2, Type: TrueFalse, AnswerKey: AnswerKey{Correct: boolPtr(true)}, Points: 1},
+		{ID: 3, Type: Numeric, AnswerKey: AnswerKey{Value: floatPtr(10), Tolerance: 1}, Points: 3},
+	}
+
+	testCases := []struct {
+		name           string
+		questions      []Question
+		responses      Responses
+		wantScore      int64
+		wantMaxScore   int64
+		wantPercentage int64
+		wantCorrect    map[int64]bool
+	}{
+		{
+			name:      "all correct",
+			questions: questions,
+			responses: Responses{
+				1: {Choices: []int{1}},
+				2: {Correct: boolPtr(true)},
+				3: {Value: floatPtr(10.5)},
+			},
+			wantScore:      6,
+			wantMaxScore:   6,
+			wantPercentage: 100,
+			wantCorrect:    map[int64]bool{1: true, 2: true, 3: true},
+		},
+		{
+			name:      "partly correct",
+			questions: questions,
+			responses: Responses{
+				1: {Choices: []int{0}},
+				2: {Correct: boolPtr(true)},
+				3: {Value: floatPtr(10)},
+			},
+			wantScore:      4,
+			wantMaxScore:   6,
+			wantPercentage: 67,
+			wantCorrect:    map[int64]bool{1: false, 2: true, 3: true},
+		},
+		{
+			name:           "unanswered",
+			questions:      questions,
+			responses:      Responses{},
+			wantScore:      0,
+			wantMaxScore:   6,
+			wantPercentage: 0,
+			wantCorrect:    map[int64]bool{1: false, 2: false, 3: false},
+		},
+		{
+			name:      "responses to unknown questions are ignored",
+			questions: questions[:1],
+			responses: Responses{
+				1: {Choices: []int{1}},
+				9: {Choices: []int{1}},
+			},
+			wantScore:      2,
+			wantMaxScore:   2,
+			wantPercentage: 100,
+			wantCorrect:    map[int64]bool{1: true},
+		},
+		{
+			name:           "no questions",
+			responses:      Responses{1: {Choices: []int{1}}},
+			wantScore:      0,
+			wantMaxScore:   0,
+			wantPercentage: 0,
+			wantCorrect:    map[int64]bool{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Grade(tc.questions, tc.responses)
+			if result.Score != tc.wantScore || result.MaxScore != tc.wantMaxScore {
+				t.Errorf("Grade() score = %d/%d, want %d/%d", result.Score, result.MaxScore, tc.wantScore, tc.wantMaxScore)
+			}
+			if got := result.Percentage(); got != tc.wantPercentage {
+				t.Errorf("Percentage() = %d, want %d", got, tc.wantPercentage)
+			}
+			if len(result.Correct) != len(tc.wantCorrect) {
+				t.Fatalf("Grade() correct = %v, want %v", result.Correct, tc.wantCorrect)
+			}
+			for id, want := range tc.wantCorrect {
+				if got, ok := result.Correct[id]; !ok || got != want {
+					t.Errorf("Grade() correct[%d] = %v, want %v", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDraw(t *testing.T) {
+	pool := []int64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	testCases := []struct {
+		name     string
+		pool     []int64
+		count    int
+		wantSize int
+	}{
+		{"subset", pool, 3, 3},
+		{"all when zero", pool, 0, len(pool)},
+		{"all when negative", pool, -1, len(pool)},
+		{"all when equal", pool, len(pool), len(pool)},
+		{"all when larger", pool, 20, len(pool)},
+		{"empty pool", nil, 3, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := slices.Clone(tc.pool)
+			drawn := Draw(tc.pool, tc.count, rand.New(rand.NewSource(7)))
+
+			if len(drawn) != tc.wantSize {
+				t.Fatalf("Draw() returned %d questions, want %d", len(drawn), tc.wantSize)
+			}
+			if !slices.Equal(tc.pool, original) {
+				t.Errorf("Draw() modified the pool: %v", tc.pool)
+			}
+
+			seen := make(map[int64]bool, len(drawn))
+			for _, id := range drawn {
+				if !slices.Contains(tc.pool, id) {
+					t.Errorf("Draw() returned %d, which is not in the pool", id)
+				}
+				if seen[id] {
+					t.Errorf("Draw() returned %d twice", id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+
+	first := Draw(pool, 0, rand.New(rand.NewSource(7)))
+	second := Draw(pool, 0, rand.New(rand.NewSource(7)))
+	if !slices.Equal(first, second) {
+		t.Errorf("Draw() with the same seed = %v and %v, want the same order", first, second)
+	}
+}
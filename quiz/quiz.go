@@ -0,0 +1,119 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// question types supported by the question bank
+const (
+	SingleChoice   = "single_choice"
+	MultipleChoice = "multiple_choice"
+	TrueFalse      = "true_false"
+	ShortAnswer    = "short_answer"
+	Numeric        = "numeric"
+)
+
+// Options are the choices shown for single and multiple choice questions
+type Options []string
+
+// AnswerKey holds the correct answer of a question, only the fields of its type are used.
+//
+// Short answer patterns are compared case-insensitively after trimming and collapsing spaces,
+// a pattern wrapped in slashes such as /^colou?r$/ is used as a regular expression instead.
+type AnswerKey struct {
+	Choices   []int    `json:"choices,omitempty"`
+	Correct   *bool    `json:"correct,omitempty"`
+	Patterns  []string `json:"patterns,omitempty"`
+	Value     *float64 `json:"value,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+}
+
+// Response is a student's answer to a single question
+type Response struct {
+	Choices []int    `json:"choices,omitempty"`
+	Correct *bool    `json:"correct,omitempty"`
+	Text    string   `json:"text,omitempty"`
+	Value   *float64 `json:"value,omitempty"`
+}
+
+// Responses maps question IDs to the answers of an attempt
+type Responses map[int64]Response
+
+// Validate checks that the answer key and options fit the question type
+func Validate(questionType string, options Options, key AnswerKey) error {
+	switch questionType {
+	case SingleChoice, MultipleChoice:
+		if len(options) < 2 {
+			return errors.New("choice questions need at least two options")
+		}
+		if len(key.Choices) == 0 {
+			return errors.New("answer key must mark at least one correct option")
+		}
+		if questionType == SingleChoice && len(key.Choices) != 1 {
+			return errors.New("single choice questions have exactly one correct option")
+		}
+		seen := make(map[int]bool, len(key.Choices))
+		for _, choice := range key.Choices {
+			if choice < 0 || choice >= len(options) {
+				return fmt.Errorf("answer key option %d is out of range", choice)
+			}
+			if seen[choice] {
+				return fmt.Errorf("answer key option %d is repeated", choice)
+			}
+			seen[choice] = true
+		}
+	case TrueFalse:
+		if key.Correct == nil {
+			return errors.New("answer key must set correct for true/false questions")
+		}
+	case ShortAnswer:
+		if len(key.Patterns) == 0 {
+			return errors.New("answer key must list at least one accepted pattern")
+		}
+		for _, pattern := range key.Patterns {
+			if _, err := compilePattern(pattern); err != nil {
+				return err
+			}
+		}
+	case Numeric:
+		if key.Value == nil {
+			return errors.New("answer key must set value for numeric questions")
+		}
+		if key.Tolerance < 0 {
+			return errors.New("tolerance cannot be negative")
+		}
+	default:
+		return fmt.Errorf("unsupported question type: %s", questionType)
+	}
+
+	return nil
+}
+
+// compilePattern returns the regular expression of a short answer pattern,
+// or nil when the pattern is compared as plain text
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) < 2 || !strings.HasPrefix(pattern, "/") || !strings.HasSuffix(pattern, "/") {
+		if normalizeText(pattern) == "" {
+			return nil, errors.New("accepted patterns cannot be empty")
+		}
+		return nil, nil
+	}
+
+	expr := pattern[1 : len(pattern)-1]
+	if expr == "" {
+		return nil, errors.New("accepted patterns cannot be empty")
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	return re, nil
+}
+
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,88 @@
+package quiz
+
+import (
+	"testing"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestValidate(t *testing.T) {
+	options := Options{"a", "b", "c"}
+
+	testCases := []struct {
+		name         string
+		questionType string
+		options      Options
+		key          AnswerKey
+		wantErr      bool
+	}{
+		{"single choice", SingleChoice, options, AnswerKey{Choices: []int{1}}, false},
+		{"single choice with two answers", SingleChoice, options, AnswerKey{Choices: []int{0, 1}}, true},
+		{"single choice without answer", SingleChoice, options, AnswerKey{}, true},
+		{"single choice with one option", SingleChoice, Options{"a"}, AnswerKey{Choices: []int{0}}, true},
+		{"multiple choice", MultipleChoice, options, AnswerKey{Choices: []int{0, 2}}, false},
+		{"multiple choice out of range", MultipleChoice, options, AnswerKey{Choices: []int{0, 3}}, true},
+		{"multiple choice negative", MultipleChoice, options, AnswerKey{Choices: []int{-1}}, true},
+		{"multiple choice repeated", MultipleChoice, options, AnswerKey{Choices: []int{1, 1}}, true},
+		{"true/false", TrueFalse, nil, AnswerKey{Correct: boolPtr(false)}, false},
+		{"true/false without answer", TrueFalse, nil, AnswerKey{}, true},
+		{"short answer", ShortAnswer, nil, AnswerKey{Patterns: []string{"paris", "/^colou?r$/"}}, false},
+		{"short answer without patterns", ShortAnswer, nil, AnswerKey{}, true},
+		{"short answer blank pattern", ShortAnswer, nil, AnswerKey{Patterns: []string{"paris", "  "}}, true},
+		{"short answer invalid regex", ShortAnswer, nil, AnswerKey{Patterns: []string{"/(/"}}, true},
+		{"short answer empty regex", ShortAnswer, nil, AnswerKey{Patterns: []string{"//"}}, true},
+		{"numeric", Numeric, nil, AnswerKey{Value: floatPtr(3.14), Tolerance: 0.01}, false},
+		{"numeric without value", Numeric, nil, AnswerKey{Tolerance: 1}, true},
+		{"numeric negative tolerance", Numeric, nil, AnswerKey{Value: floatPtr(1), Tolerance: -1}, true},
+		{"unknown type", "essay", nil, AnswerKey{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.questionType, tc.options, tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	testCases := []struct {
+		name      string
+		pattern   string
+		wantRegex bool
+		wantErr   bool
+	}{
+		{"plain text", "Paris", false, false},
+		{"single slash", "/", false, false},
+		{"slash inside text", "and/or", false, false},
+		{"regex", "/^colou?r$/", true, false},
+		{"empty", "", false, true},
+		{"blank", " \t ", false, true},
+		{"empty regex", "//", false, true},
+		{"invalid regex", "/[a-/", false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			re, err := compilePattern(tc.pattern)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("compilePattern(%q) error = %v, wantErr %v", tc.pattern, err, tc.wantErr)
+			}
+			if (re != nil) != tc.wantRegex {
+				t.Errorf("compilePattern(%q) regex = %v, want regex %v", tc.pattern, re, tc.wantRegex)
+			}
+		})
+	}
+
+	re, err := compilePattern("/^colou?r$/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("COLOUR") {
+		t.Error("compiled patterns should match case-insensitively")
+	}
+}
@@ -0,0 +1,125 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// Publisher sends events to the users they are about
+type Publisher interface {
+	Publish(ctx context.Context, userID int64, eventType string, data any) error
+}
+
+// Broker publishes events and lets a connection listen to the events of a user
+type Broker interface {
+	Publisher
+	Tickets
+	Subscribe(ctx context.Context, userID int64) (Subscription, error)
+}
+
+// Subscription is one connection's listener to the events of a user
+type Subscription interface {
+	// Events delivers the events of the user, it is closed when the connection to the broker is closed
+	Events() <-chan Event
+	// Close stops listening, Events is not read anymore afterwards
+	Close() error
+}
+
+// RedisBroker passes events through Redis pub/sub with a channel per user,
+// so an event published by any API or worker process reaches every connection of the user
+type RedisBroker struct {
+	client *redis.Client
+}
+
+func NewRedisBroker(opt *redis.Options) *RedisBroker {
+	return &RedisBroker{
+		client: redis.NewClient(opt),
+	}
+}
+
+// userChannel is the Redis channel the events of a user are published on
+func userChannel(userID int64) string {
+	return fmt.Sprintf("realtime:user:%d", userID)
+}
+
+// Publish sends an event to the connections of a user, a user without connections misses it
+func (broker *RedisBroker) Publish(ctx context.Context, userID int64, eventType string, data any) error {
+	event, err := NewEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	if err := broker.client.Publish(ctx, userChannel(userID), payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// Subscribe listens to the events of a user until the subscription is closed
+func (broker *RedisBroker) Subscribe(ctx context.Context, userID int64) (Subscription, error) {
+	pubsub := broker.client.Subscribe(ctx, userChannel(userID))
+	// the first reply confirms the subscription, so no event published after Subscribe returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	subscription := &redisSubscription{
+		pubsub: pubsub,
+		events: make(chan Event),
+		done:   make(chan struct{}),
+	}
+	go subscription.receive()
+	return subscription, nil
+}
+
+// redisSubscription listens to the Redis channel of a user
+type redisSubscription struct {
+	pubsub    *redis.PubSub
+	events    chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (subscription *redisSubscription) Events() <-chan Event {
+	return subscription.events
+}
+
+func (subscription *redisSubscription) Close() error {
+	subscription.closeOnce.Do(func() { close(subscription.done) })
+	return subscription.pubsub.Close()
+}
+
+func (subscription *redisSubscription) receive() {
+	messages := subscription.pubsub.Channel()
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				close(subscription.events)
+				return
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				log.Error().Err(err).Str("channel", message.Channel).Msg("failed to decode event")
+				continue
+			}
+			select {
+			case subscription.events <- event:
+			case <-subscription.done:
+				return
+			}
+		case <-subscription.done:
+			return
+		}
+	}
+}
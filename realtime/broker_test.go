@@ -0,0 +1,119 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestBroker(t *testing.T) (*RedisBroker, *fakeRedis) {
+	server := newFakeRedis(t)
+	broker := NewRedisBroker(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { broker.client.Close() })
+	return broker, server
+}
+
+// receive waits for the next event of a subscription
+func receive(t *testing.T, subscription Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-subscription.Events():
+		if !ok {
+			t.Fatal("events closed")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+	return Event{}
+}
+
+func TestRedisBrokerDeliversEventsOfTheUser(t *testing.T) {
+	broker, _ := newTestBroker(t)
+	ctx := context.Background()
+
+	subscription, err := broker.Subscribe(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscription.Close()
+
+	if err := broker.Publish(ctx, 2, EventProgressChanged, ProgressChanged{CourseID: 8}); err != nil {
+		t.Fatal(err)
+	}
+	// an event that does not decode is skipped, the next one still arrives
+	if err := broker.client.Publish(ctx, userChannel(1), "not an event").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.Publish(ctx, 1, EventProgressChanged, ProgressChanged{CourseID: 3, Progress: 50}); err != nil {
+		t.Fatal(err)
+	}
+
+	event := receive(t, subscription)
+	if event.Type != EventProgressChanged {
+		t.Fatalf("type = %q, want %q", event.Type, EventProgressChanged)
+	}
+	var data ProgressChanged
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.CourseID != 3 || data.Progress != 50 {
+		t.Errorf("data = %+v, want the progress of course 3 published to user 1", data)
+	}
+}
+
+func TestRedisSubscriptionClose(t *testing.T) {
+	broker, server := newTestBroker(t)
+	ctx := context.Background()
+
+	subscription, err := broker.Subscribe(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := server.subscriberCount(userChannel(1)); n != 1 {
+		t.Fatalf("subscribers = %d after Subscribe, want 1", n)
+	}
+
+	// the receiver is blocked handing over an event nobody reads, closing must not wait for a reader
+	if err := broker.Publish(ctx, 1, EventTaskFinished, TaskFinished{Task: "delete_course", ID: 4, Status: TaskDone}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() { closed <- subscription.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close blocked")
+	}
+
+	// a second close, as a deferred one after an explicit one, does not panic
+	subscription.Close()
+
+	// the connection is released, so events of the user are no longer sent anywhere
+	deadline := time.Now().Add(2 * time.Second)
+	for server.subscriberCount(userChannel(1)) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription still listening after Close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRedisBrokerSubscribeFails(t *testing.T) {
+	server := newFakeRedis(t)
+	server.listener.Close()
+	broker := NewRedisBroker(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer broker.client.Close()
+
+	if _, err := broker.Subscribe(context.Background(), 1); err == nil {
+		t.Fatal("Subscribe succeeded without Redis")
+	}
+}
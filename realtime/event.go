@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// types of events pushed to a signed in user, the frontend refetches what an event is about instead of polling
+const (
+	EventProgressChanged     = "progress_changed"
+	EventSubmissionGraded    = "submission_graded"
+	EventNotificationCreated = "notification_created"
+	EventTaskFinished        = "task_finished"
+)
+
+// Event is something that changed for a user
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	At   time.Time       `json:"at"`
+}
+
+// NewEvent encodes data as the payload of an event of eventType
+func NewEvent(eventType string, data any) (Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return Event{Type: eventType, Data: encoded, At: time.Now()}, nil
+}
+
+// ProgressChanged is the data of an EventProgressChanged
+type ProgressChanged struct {
+	CourseID  int64 `json:"course_id"`
+	Progress  int64 `json:"progress"`
+	Completed bool  `json:"completed"`
+}
+
+// SubmissionGraded is the data of an EventSubmissionGraded, it is only sent once the grade is published
+type SubmissionGraded struct {
+	CourseID     int64 `json:"course_id"`
+	AssignmentID int64 `json:"assignment_id"`
+}
+
+// NotificationCreated is the data of an EventNotificationCreated
+type NotificationCreated struct {
+	NotificationID int64  `json:"notification_id"`
+	Type           string `json:"type"`
+	Title          string `json:"title"`
+}
+
+// TaskFinished is the data of an EventTaskFinished, sent to the user who queued a background task
+type TaskFinished struct {
+	Task string `json:"task"`
+	// ID is the course, request or lesson the task worked on
+	ID int64 `json:"id"`
+	// Status is "done", or what the task did instead, such as "requested" for a delete that needs the creator's approval
+	Status string `json:"status"`
+}
+
+// task statuses of a TaskFinished
+const (
+	TaskDone      = "done"
+	TaskRequested = "requested"
+)
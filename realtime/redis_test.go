@@ -0,0 +1,221 @@
+package realtime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis speaks enough of the Redis protocol for the broker: SET with an expiry, GETDEL, SUBSCRIBE and PUBLISH.
+// Its clock only moves when the test advances it, so expiring keys takes no waiting.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	elapsed     time.Duration
+	values      map[string]fakeValue
+	subscribers map[string]map[*fakeConn]bool
+}
+
+type fakeValue struct {
+	data      string
+	expiresAt time.Duration
+}
+
+type fakeConn struct {
+	mu       sync.Mutex
+	conn     net.Conn
+	channels map[string]bool
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeRedis{
+		listener:    listener,
+		values:      map[string]fakeValue{},
+		subscribers: map[string]map[*fakeConn]bool{},
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(&fakeConn{conn: conn, channels: map[string]bool{}})
+		}
+	}()
+	return server
+}
+
+func (server *fakeRedis) Addr() string {
+	return server.listener.Addr().String()
+}
+
+// advance moves the clock keys expire by
+func (server *fakeRedis) advance(d time.Duration) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.elapsed += d
+}
+
+// ttl is the time left before key expires, zero for a missing key
+func (server *fakeRedis) ttl(key string) time.Duration {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	value, ok := server.values[key]
+	if !ok {
+		return 0
+	}
+	return value.expiresAt - server.elapsed
+}
+
+// subscriberCount is the number of connections subscribed to channel
+func (server *fakeRedis) subscriberCount(channel string) int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return len(server.subscribers[channel])
+}
+
+func (server *fakeRedis) serve(conn *fakeConn) {
+	defer func() {
+		server.mu.Lock()
+		for channel := range conn.channels {
+			delete(server.subscribers[channel], conn)
+		}
+		server.mu.Unlock()
+		conn.conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn.conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		server.handle(conn, args)
+	}
+}
+
+func (server *fakeRedis) handle(conn *fakeConn, args []string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	switch strings.ToLower(args[0]) {
+	case "ping":
+		if len(conn.channels) > 0 {
+			conn.write(array("pong", ""))
+			return
+		}
+		conn.write("+PONG\r\n")
+	case "set":
+		value := fakeValue{data: args[2], expiresAt: time.Duration(1<<63 - 1)}
+		for i := 3; i+1 < len(args); i += 2 {
+			n, _ := strconv.Atoi(args[i+1])
+			switch strings.ToLower(args[i]) {
+			case "ex":
+				value.expiresAt = server.elapsed + time.Duration(n)*time.Second
+			case "px":
+				value.expiresAt = server.elapsed + time.Duration(n)*time.Millisecond
+			}
+		}
+		server.values[args[1]] = value
+		conn.write("+OK\r\n")
+	case "getdel":
+		value, ok := server.values[args[1]]
+		delete(server.values, args[1])
+		if !ok || value.expiresAt <= server.elapsed {
+			conn.write("$-1\r\n")
+			return
+		}
+		conn.write(bulk(value.data))
+	case "subscribe":
+		for _, channel := range args[1:] {
+			if server.subscribers[channel] == nil {
+				server.subscribers[channel] = map[*fakeConn]bool{}
+			}
+			server.subscribers[channel][conn] = true
+			conn.channels[channel] = true
+			conn.write(array("subscribe", channel) + ":" + strconv.Itoa(len(conn.channels)) + "\r\n")
+		}
+	case "unsubscribe":
+		for _, channel := range args[1:] {
+			delete(server.subscribers[channel], conn)
+			delete(conn.channels, channel)
+			conn.write(array("unsubscribe", channel) + ":" + strconv.Itoa(len(conn.channels)) + "\r\n")
+		}
+	case "publish":
+		for subscriber := range server.subscribers[args[1]] {
+			subscriber.write(array("message", args[1], args[2]))
+		}
+		conn.write(":" + strconv.Itoa(len(server.subscribers[args[1]])) + "\r\n")
+	default:
+		conn.write("-ERR unknown command '" + args[0] + "'\r\n")
+	}
+}
+
+func (conn *fakeConn) write(reply string) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	io.WriteString(conn.conn, reply)
+}
+
+// array encodes a reply of bulk strings, a subscribe reply gets its count appended as the last element
+func array(items ...string) string {
+	length := len(items)
+	if items[0] == "subscribe" || items[0] == "unsubscribe" {
+		length++
+	}
+	reply := "*" + strconv.Itoa(length) + "\r\n"
+	for _, item := range items {
+		reply += bulk(item)
+	}
+	return reply
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected argument %q", header)
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
@@ -0,0 +1,71 @@
+package realtime
+
+import (
+	"context"
+	"crypto/rand"
+	"eduApp/token"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// TicketDuration is how long a stream ticket can be redeemed after it was issued
+const TicketDuration = 30 * time.Second
+
+// ErrInvalidTicket is returned for a ticket that was never issued, expired or was redeemed already
+var ErrInvalidTicket = errors.New("stream ticket is invalid or was used already")
+
+// Tickets hands out the tickets that open an event stream. A browser EventSource cannot set headers,
+// so it passes a ticket in its URL instead of the access token, which is redeemed once and expires quickly.
+type Tickets interface {
+	IssueTicket(ctx context.Context, payload *token.Payload) (string, error)
+	RedeemTicket(ctx context.Context, ticket string) (*token.Payload, error)
+}
+
+// ticketKey is the Redis key the access token payload of a ticket is kept under
+func ticketKey(ticket string) string {
+	return "realtime:ticket:" + ticket
+}
+
+// IssueTicket stores the access token payload of a user under a new random ticket
+func (broker *RedisBroker) IssueTicket(ctx context.Context, payload *token.Payload) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate ticket: %w", err)
+	}
+	ticket := base64.RawURLEncoding.EncodeToString(random)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ticket: %w", err)
+	}
+	if err := broker.client.Set(ctx, ticketKey(ticket), data, TicketDuration).Err(); err != nil {
+		return "", fmt.Errorf("failed to store ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+// RedeemTicket returns the access token payload a ticket was issued for and deletes the ticket in the same command,
+// so two connections cannot redeem it both. The stream it opens still ends when the access token expires.
+func (broker *RedisBroker) RedeemTicket(ctx context.Context, ticket string) (*token.Payload, error) {
+	data, err := broker.client.GetDel(ctx, ticketKey(ticket)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInvalidTicket
+		}
+		return nil, fmt.Errorf("failed to redeem ticket: %w", err)
+	}
+
+	var payload token.Payload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode ticket: %w", err)
+	}
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
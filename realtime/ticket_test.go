@@ -0,0 +1,107 @@
+package realtime
+
+import (
+	"context"
+	"eduApp/token"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisBrokerTickets(t *testing.T) {
+	ctx := context.Background()
+	valid := &token.Payload{UserName: "ana", Role: "student", UserID: 7, ExpiredAt: time.Now().Add(15 * time.Minute)}
+
+	testCases := []struct {
+		name string
+		// redeem redeems the ticket issued for payload, or another one, and returns what the last redemption returned
+		redeem  func(broker *RedisBroker, server *fakeRedis, ticket string) (*token.Payload, error)
+		payload *token.Payload
+		err     error
+	}{
+		{
+			name:    "Redeemed",
+			payload: valid,
+			redeem: func(broker *RedisBroker, server *fakeRedis, ticket string) (*token.Payload, error) {
+				return broker.RedeemTicket(ctx, ticket)
+			},
+		},
+		{
+			name:    "RedeemedTwice",
+			payload: valid,
+			redeem: func(broker *RedisBroker, server *fakeRedis, ticket string) (*token.Payload, error) {
+				if _, err := broker.RedeemTicket(ctx, ticket); err != nil {
+					return nil, err
+				}
+				return broker.RedeemTicket(ctx, ticket)
+			},
+			err: ErrInvalidTicket,
+		},
+		{
+			name:    "Unknown",
+			payload: valid,
+			redeem: func(broker *RedisBroker, server *fakeRedis, ticket string) (*token.Payload, error) {
+				return broker.RedeemTicket(ctx, ticket+"x")
+			},
+			err: ErrInvalidTicket,
+		},
+		{
+			name:    "TicketExpired",
+			payload: valid,
+			redeem: func(broker *RedisBroker, server *fakeRedis, ticket string) (*token.Payload, error) {
+				server.advance(TicketDuration + time.Second)
+				return broker.RedeemTicket(ctx, ticket)
+			},
+			err: ErrInvalidTicket,
+		},
+		{
+			name:    "AccessTokenExpired",
+			payload: &token.Payload{UserName: "ana", Role: "student", UserID: 7, ExpiredAt: time.Now().Add(-time.Second)},
+			redeem: func(broker *RedisBroker, server *fakeRedis, ticket string) (*token.Payload, error) {
+				return broker.RedeemTicket(ctx, ticket)
+			},
+			err: token.ErrExpiredToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			broker, server := newTestBroker(t)
+			ticket, err := broker.IssueTicket(ctx, tc.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ttl := server.ttl(ticketKey(ticket)); ttl != TicketDuration {
+				t.Fatalf("ticket expires in %v, want %v", ttl, TicketDuration)
+			}
+
+			payload, err := tc.redeem(broker, server, ticket)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("RedeemTicket error = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if payload.UserID != tc.payload.UserID || payload.Role != tc.payload.Role || !payload.ExpiredAt.Equal(tc.payload.ExpiredAt) {
+				t.Errorf("payload = %+v, want %+v", payload, tc.payload)
+			}
+		})
+	}
+}
+
+func TestIssueTicketIsRandom(t *testing.T) {
+	broker, _ := newTestBroker(t)
+	payload := &token.Payload{UserID: 7, ExpiredAt: time.Now().Add(time.Minute)}
+
+	seen := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		ticket, err := broker.IssueTicket(context.Background(), payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ticket) < 40 || seen[ticket] {
+			t.Fatalf("ticket %q is short or repeated", ticket)
+		}
+		seen[ticket] = true
+	}
+}
@@ -0,0 +1,200 @@
+// SCORM run-time API for the SCOs played by the app, served from the content origin next to the package files. The player
+// sends the data model with postMessage before the SCO launches, reads are answered from it and the values a SCO sets are
+// sent back to the player on commit and finish. The adapter never holds the session of the student.
+(function (window) {
+  "use strict";
+
+  var children = {
+    "1.2": {
+      "cmi.core._children": "student_id,student_name,lesson_location,credit,lesson_status,entry,score,total_time,lesson_mode,exit,session_time",
+      "cmi.core.score._children": "raw,min,max",
+      "cmi.objectives._children": "id,score,status",
+      "cmi.interactions._children": "id,objectives,time,type,correct_responses,weighting,student_response,result,latency",
+      "cmi.student_preference._children": "audio,language,speed,text"
+    },
+    "2004": {
+      "cmi._version": "1.0",
+      "cmi.score._children": "scaled,raw,min,max",
+      "cmi.objectives._children": "id,score,success_status,completion_status,progress_measure,description",
+      "cmi.interactions._children": "id,type,objectives,timestamp,correct_responses,weighting,learner_response,result,latency,description",
+      "cmi.comments_from_learner._children": "comment,location,timestamp",
+      "cmi.learner_preference._children": "audio_level,language,delivery_speed,audio_captioning"
+    }
+  };
+
+  var readOnly = {
+    "1.2": /^cmi\.(core\.(student_id|student_name|credit|entry|total_time|lesson_mode)|launch_data|student_data\..*)$/,
+    "2004": /^cmi\.(learner_id|learner_name|credit|entry|total_time|mode|launch_data|completion_threshold|scaled_passing_score|max_time_allowed|time_limit_action)$/
+  };
+
+  var errors = {
+    "0": "No error",
+    "101": "General exception",
+    "103": "Already initialized",
+    "104": "Content instance terminated",
+    "112": "Termination before initialization",
+    "113": "Termination after termination",
+    "122": "Retrieve data before initialization",
+    "123": "Retrieve data after termination",
+    "132": "Store data before initialization",
+    "133": "Store data after termination",
+    "142": "Commit before initialization",
+    "143": "Commit after termination",
+    "201": "Invalid argument",
+    "301": "Not initialized",
+    "401": "Undefined data model element",
+    "403": "Element is read only",
+    "404": "Element is read only"
+  };
+
+  function start(config) {
+    var version, values = {}, dirty = {}, state = "new", lastError = "0";
+    var player = null, playerOrigin = null;
+
+    function post(finish) {
+      if (player) {
+        player.postMessage({ type: "scorm:commit", values: dirty, finish: finish }, playerOrigin);
+      }
+      dirty = {};
+      return true;
+    }
+
+    function count(name) {
+      var prefix = name.slice(0, -"_count".length);
+      var seen = {};
+      Object.keys(values).forEach(function (key) {
+        if (key.indexOf(prefix) === 0) {
+          seen[key.slice(prefix.length).split(".")[0]] = true;
+        }
+      });
+      return String(Object.keys(seen).length);
+    }
+
+    function fail(code) {
+      lastError = code;
+      return "false";
+    }
+
+    function initialize() {
+      if (state === "running") {
+        return fail(version === "2004" ? "103" : "101");
+      }
+      if (state === "terminated") {
+        return fail(version === "2004" ? "104" : "101");
+      }
+      state = "running";
+      lastError = "0";
+      return "true";
+    }
+
+    function terminate() {
+      if (state !== "running") {
+        return fail(version === "2004" ? (state === "new" ? "112" : "113") : "301");
+      }
+      state = "terminated";
+      lastError = "0";
+      return String(post(true));
+    }
+
+    function getValue(name) {
+      if (state !== "running") {
+        lastError = version === "2004" ? (state === "new" ? "122" : "123") : "301";
+        return "";
+      }
+      lastError = "0";
+      if (name in children[version]) {
+        return children[version][name];
+      }
+      if (/\._count$/.test(name)) {
+        return count(name);
+      }
+      if (name in values) {
+        return values[name];
+      }
+      if (name.indexOf("cmi.") !== 0) {
+        lastError = "401";
+      } else if (version === "2004") {
+        lastError = "403";
+      }
+      return "";
+    }
+
+    function setValue(name, value) {
+      if (state !== "running") {
+        return fail(version === "2004" ? (state === "new" ? "132" : "133") : "301");
+      }
+      if (readOnly[version].test(name) || name in children[version] || /\._count$/.test(name)) {
+        return fail(version === "2004" ? "404" : "403");
+      }
+      if (name.indexOf("cmi.") !== 0) {
+        return fail(version === "2004" ? "401" : "201");
+      }
+      value = String(value);
+      values[name] = value;
+      dirty[name] = value;
+      lastError = "0";
+      return "true";
+    }
+
+    function commit() {
+      if (state !== "running") {
+        return fail(version === "2004" ? (state === "new" ? "142" : "143") : "301");
+      }
+      lastError = "0";
+      return String(post(false));
+    }
+
+    function errorString(code) {
+      return errors[String(code)] || "";
+    }
+
+    function install() {
+      if (version === "2004") {
+        window.API_1484_11 = {
+          Initialize: initialize,
+          Terminate: terminate,
+          GetValue: getValue,
+          SetValue: setValue,
+          Commit: commit,
+          GetLastError: function () { return lastError; },
+          GetErrorString: errorString,
+          GetDiagnostic: errorString
+        };
+        return;
+      }
+      window.API = {
+        LMSInitialize: initialize,
+        LMSFinish: terminate,
+        LMSGetValue: getValue,
+        LMSSetValue: setValue,
+        LMSCommit: commit,
+        LMSGetLastError: function () { return lastError; },
+        LMSGetErrorString: errorString,
+        LMSGetDiagnostic: errorString
+      };
+    }
+
+    // a SCO closed without finishing still keeps what it set
+    window.addEventListener("pagehide", function () {
+      if (state === "running" && Object.keys(dirty).length > 0) {
+        post(false);
+      }
+    });
+
+    // the player embedding the adapter answers with the data model, it is the only window the adapter talks to
+    window.addEventListener("message", function (event) {
+      if (event.source !== window.parent || player || !event.data || event.data.type !== "scorm:init") {
+        return;
+      }
+      player = event.source;
+      playerOrigin = event.origin;
+      version = event.data.version;
+      values = event.data.values || {};
+      install();
+      document.getElementById("sco").src = config.launchURL;
+    });
+    window.parent.postMessage({ type: "scorm:ready" }, "*");
+  }
+
+  window.SCORMAdapter = { start: start };
+})(window);
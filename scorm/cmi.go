@@ -0,0 +1,348 @@
+package scorm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Different types of error returned when a SCO sets an element
+var (
+	ErrInvalidElement = errors.New("element is not writable")
+	ErrInvalidValue   = errors.New("invalid value")
+)
+
+// lesson statuses that complete a lesson
+const (
+	StatusPassed    = "passed"
+	StatusCompleted = "completed"
+)
+
+// maxValueLength bounds elements without a length of their own in the data model
+const maxValueLength = 4096
+
+// CMI is the run-time data model of a SCO for one learner, element names such as cmi.core.lesson_status map to their values
+type CMI map[string]string
+
+// Learner is who the run-time reports as the learner
+type Learner struct {
+	ID   string
+	Name string
+}
+
+// Result is what the data model says about the learner's attempt
+type Result struct {
+	// Status is the lesson status for SCORM 1.2. For SCORM 2004 it is the success status when known, else the completion status.
+	Status    string
+	Completed bool
+	// Score is a percentage, nil while the SCO has not reported one
+	Score *float64
+}
+
+type element struct {
+	pattern *regexp.Regexp
+	valid   func(value string) bool
+	max     int
+}
+
+func vocabulary(words ...string) func(string) bool {
+	return func(value string) bool {
+		for _, word := range words {
+			if value == word {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func decimal(value string) bool {
+	if value == "" {
+		return true
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func between(min, max float64) func(string) bool {
+	return func(value string) bool {
+		number, err := strconv.ParseFloat(value, 64)
+		return err == nil && number >= min && number <= max
+	}
+}
+
+func anything(string) bool { return true }
+
+func rule(pattern string, valid func(string) bool, max int) element {
+	return element{pattern: regexp.MustCompile("^" + pattern + "$"), valid: valid, max: max}
+}
+
+// writable lists the elements a SCO may set, every other element is read only or unknown
+var writable = map[string][]element{
+	Version12: {
+		rule(`cmi\.core\.lesson_location`, anything, 255),
+		rule(`cmi\.core\.lesson_status`, vocabulary("passed", "completed", "failed", "incomplete", "browsed"), 0),
+		rule(`cmi\.core\.score\.(raw|min|max)`, decimal, 0),
+		rule(`cmi\.core\.exit`, vocabulary("time-out", "suspend", "logout", ""), 0),
+		rule(`cmi\.core\.session_time`, func(value string) bool { _, ok := parseTime12(value); return ok }, 0),
+		rule(`cmi\.suspend_data`, anything, 4096),
+		rule(`cmi\.comments`, anything, 4096),
+		rule(`cmi\.student_preference\.(audio|language|speed|text)`, anything, 255),
+		rule(`cmi\.objectives\.\d+\.(id|status)`, anything, 255),
+		rule(`cmi\.objectives\.\d+\.score\.(raw|min|max)`, decimal, 0),
+		rule(`cmi\.interactions\.\d+\.(id|time|type|weighting|student_response|result|latency)`, anything, 0),
+		rule(`cmi\.interactions\.\d+\.objectives\.\d+\.id`, anything, 255),
+		rule(`cmi\.interactions\.\d+\.correct_responses\.\d+\.pattern`, anything, 0),
+	},
+	Version2004: {
+		rule(`cmi\.location`, anything, 1000),
+		rule(`cmi\.completion_status`, vocabulary("completed", "incomplete", "not attempted", "unknown"), 0),
+		rule(`cmi\.success_status`, vocabulary("passed", "failed", "unknown"), 0),
+		rule(`cmi\.score\.scaled`, between(-1, 1), 0),
+		rule(`cmi\.score\.(raw|min|max)`, decimal, 0),
+		rule(`cmi\.progress_measure`, between(0, 1), 0),
+		rule(`cmi\.exit`, vocabulary("time-out", "suspend", "logout", "normal", ""), 0),
+		rule(`cmi\.session_time`, func(value string) bool { _, ok := parseDuration(value); return ok }, 0),
+		rule(`cmi\.suspend_data`, anything, 64000),
+		rule(`cmi\.comments_from_learner\.\d+\.(comment|location|timestamp)`, anything, 4000),
+		rule(`cmi\.learner_preference\.(audio_level|language|delivery_speed|audio_captioning)`, anything, 250),
+		rule(`cmi\.objectives\.\d+\.(id|success_status|completion_status|description)`, anything, 0),
+		rule(`cmi\.objectives\.\d+\.(score\.scaled|score\.raw|score\.min|score\.max|progress_measure)`, decimal, 0),
+		rule(`cmi\.interactions\.\d+\.(id|type|timestamp|weighting|learner_response|result|latency|description)`, anything, 0),
+		rule(`cmi\.interactions\.\d+\.objectives\.\d+\.id`, anything, 0),
+		rule(`cmi\.interactions\.\d+\.correct_responses\.\d+\.pattern`, anything, 0),
+	},
+}
+
+// element names that differ between the versions
+var names = map[string]struct {
+	status, exit, sessionTime, totalTime, scoreRaw, scoreMin, scoreMax string
+}{
+	Version12: {
+		status:      "cmi.core.lesson_status",
+		exit:        "cmi.core.exit",
+		sessionTime: "cmi.core.session_time",
+		totalTime:   "cmi.core.total_time",
+		scoreRaw:    "cmi.core.score.raw",
+		scoreMin:    "cmi.core.score.min",
+		scoreMax:    "cmi.core.score.max",
+	},
+	Version2004: {
+		status:      "cmi.completion_status",
+		exit:        "cmi.exit",
+		sessionTime: "cmi.session_time",
+		totalTime:   "cmi.total_time",
+		scoreRaw:    "cmi.score.raw",
+		scoreMin:    "cmi.score.min",
+		scoreMax:    "cmi.score.max",
+	},
+}
+
+// Initial returns the data model handed to a SCO when it initializes: the stored values,
+// with the elements the LMS owns filled in for the learner
+func Initial(version string, stored CMI, learner Learner, totalSeconds float64) CMI {
+	cmi := make(CMI, len(stored)+8)
+	for name, value := range stored {
+		cmi[name] = value
+	}
+	n := names[version]
+	delete(cmi, n.sessionTime)
+
+	entry := ""
+	switch {
+	case len(stored) == 0:
+		entry = "ab-initio"
+	case stored[n.exit] == "suspend":
+		entry = "resume"
+	}
+
+	if version == Version2004 {
+		cmi["cmi.learner_id"] = learner.ID
+		cmi["cmi.learner_name"] = learner.Name
+		cmi["cmi.mode"] = "normal"
+		cmi["cmi.credit"] = "credit"
+		cmi["cmi.entry"] = entry
+		cmi["cmi.total_time"] = formatDuration(totalSeconds)
+		setDefault(cmi, "cmi.completion_status", "unknown")
+		setDefault(cmi, "cmi.success_status", "unknown")
+		return cmi
+	}
+
+	cmi["cmi.core.student_id"] = learner.ID
+	cmi["cmi.core.student_name"] = learner.Name
+	cmi["cmi.core.lesson_mode"] = "normal"
+	cmi["cmi.core.credit"] = "credit"
+	cmi["cmi.core.entry"] = entry
+	cmi["cmi.core.total_time"] = formatTime12(totalSeconds)
+	setDefault(cmi, "cmi.core.lesson_status", "not attempted")
+	return cmi
+}
+
+func setDefault(cmi CMI, name, value string) {
+	if _, ok := cmi[name]; !ok {
+		cmi[name] = value
+	}
+}
+
+// Commit checks the values a SCO set and merges them into the stored data model. Elements the LMS owns are refused.
+// When the SCO finished, the session time is taken out of the data model and returned in seconds to add to the total time.
+func Commit(version string, stored CMI, values map[string]string, finish bool) (CMI, float64, error) {
+	rules, ok := writable[version]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported SCORM version %q", version)
+	}
+
+	cmi := make(CMI, len(stored)+len(values))
+	for name, value := range stored {
+		cmi[name] = value
+	}
+
+	for name, value := range values {
+		if err := validate(rules, name, value); err != nil {
+			return nil, 0, err
+		}
+		cmi[name] = value
+	}
+
+	if !finish {
+		return cmi, 0, nil
+	}
+
+	n := names[version]
+	sessionTime, ok := cmi[n.sessionTime]
+	delete(cmi, n.sessionTime)
+	if !ok {
+		return cmi, 0, nil
+	}
+	if version == Version2004 {
+		seconds, _ := parseDuration(sessionTime)
+		return cmi, seconds, nil
+	}
+	seconds, _ := parseTime12(sessionTime)
+	return cmi, seconds, nil
+}
+
+func validate(rules []element, name, value string) error {
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(name) {
+			continue
+		}
+		max := rule.max
+		if max == 0 {
+			max = maxValueLength
+		}
+		if len(value) > max || !rule.valid(value) {
+			return fmt.Errorf("%w for %s: %q", ErrInvalidValue, name, value)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidElement, name)
+}
+
+// Evaluate reads the status and score of an attempt from its data model
+func Evaluate(version string, cmi CMI) Result {
+	n := names[version]
+	var result Result
+
+	if version == Version2004 {
+		completion := cmi["cmi.completion_status"]
+		success := cmi["cmi.success_status"]
+		result.Completed = completion == StatusCompleted || success == StatusPassed
+		result.Status = completion
+		if success == StatusPassed || success == "failed" {
+			result.Status = success
+		}
+		if result.Status == "" {
+			result.Status = "unknown"
+		}
+		if scaled, err := strconv.ParseFloat(cmi["cmi.score.scaled"], 64); err == nil {
+			score := clampPercent(scaled * 100)
+			result.Score = &score
+			return result
+		}
+	} else {
+		result.Status = cmi[n.status]
+		if result.Status == "" {
+			result.Status = "not attempted"
+		}
+		result.Completed = result.Status == StatusPassed || result.Status == StatusCompleted
+	}
+
+	raw, err := strconv.ParseFloat(cmi[n.scoreRaw], 64)
+	if err != nil {
+		return result
+	}
+	score := raw
+	min, _ := strconv.ParseFloat(cmi[n.scoreMin], 64)
+	if max, err := strconv.ParseFloat(cmi[n.scoreMax], 64); err == nil && max > min {
+		score = (raw - min) * 100 / (max - min)
+	}
+	score = clampPercent(score)
+	result.Score = &score
+	return result
+}
+
+func clampPercent(score float64) float64 {
+	return math.Round(math.Min(math.Max(score, 0), 100)*100) / 100
+}
+
+var (
+	time12Pattern   = regexp.MustCompile(`^(\d{2,4}):(\d{2}):(\d{2})(\.\d{1,2})?$`)
+	durationPattern = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d{1,2})?)S)?)?$`)
+)
+
+// parseTime12 reads a SCORM 1.2 CMITimespan such as 0012:30:05.5
+func parseTime12(value string) (float64, bool) {
+	match := time12Pattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, false
+	}
+	hours, _ := strconv.ParseFloat(match[1], 64)
+	minutes, _ := strconv.ParseFloat(match[2], 64)
+	seconds, _ := strconv.ParseFloat(match[3]+match[4], 64)
+	if minutes >= 60 || seconds >= 60 {
+		return 0, false
+	}
+	return hours*3600 + minutes*60 + seconds, true
+}
+
+// formatTime12 writes seconds as a SCORM 1.2 CMITimespan
+func formatTime12(seconds float64) string {
+	whole := int64(seconds)
+	hours := whole / 3600
+	if hours > 9999 {
+		hours = 9999
+	}
+	return fmt.Sprintf("%04d:%02d:%02d", hours, whole/60%60, whole%60)
+}
+
+// parseDuration reads a SCORM 2004 timeinterval, an ISO 8601 duration such as PT1H5M30S.
+// Years are taken as 365 days and months as 30.
+func parseDuration(value string) (float64, bool) {
+	match := durationPattern.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, false
+	}
+	units := []float64{365 * 86400, 30 * 86400, 86400, 3600, 60, 1}
+	var seconds float64
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		number, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		seconds += number * unit
+	}
+	return seconds, true
+}
+
+// formatDuration writes seconds as a SCORM 2004 timeinterval
+func formatDuration(seconds float64) string {
+	whole := int64(seconds)
+	return fmt.Sprintf("PT%dH%dM%dS", whole/3600, whole/60%60, whole%60)
+}
@@ -0,0 +1,155 @@
+package scorm
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// limits of an uploaded package, they keep a crafted archive from filling the disk
+const (
+	MaxPackageSize  = 512 << 20
+	maxPackageFiles = 20000
+)
+
+// Extract validates a package archive and unpacks it into dir. The manifest may sit in a single top level folder,
+// as archives made by zipping the package folder do; everything outside the folder of the manifest is skipped.
+func Extract(archive string, dir string) (Manifest, error) {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer reader.Close()
+
+	if len(reader.File) > maxPackageFiles {
+		return Manifest{}, fmt.Errorf("%w: more than %d files", ErrInvalidPackage, maxPackageFiles)
+	}
+
+	manifestFile, root := findManifest(reader.File)
+	if manifestFile == nil {
+		return Manifest{}, fmt.Errorf("%w: %s not found", ErrInvalidPackage, ManifestName)
+	}
+
+	data, err := readZipFile(manifestFile, 4<<20)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	manifest, err := ParseManifest(data)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	launchFile, _, _ := strings.Cut(manifest.LaunchPath, "?")
+	files := make(map[string]*zip.File, len(reader.File))
+	var total uint64
+	for _, file := range reader.File {
+		name, ok := packagePath(file.Name, root)
+		if !ok {
+			continue
+		}
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		total += file.UncompressedSize64
+		if total > MaxPackageSize {
+			return Manifest{}, fmt.Errorf("%w: larger than %d bytes unpacked", ErrInvalidPackage, MaxPackageSize)
+		}
+		files[name] = file
+	}
+	if files[launchFile] == nil {
+		return Manifest{}, fmt.Errorf("%w: launch file %s not found", ErrInvalidPackage, launchFile)
+	}
+
+	for name, file := range files {
+		if err := extractFile(file, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// findManifest returns the manifest closest to the root of the archive and the folder it is in
+func findManifest(files []*zip.File) (*zip.File, string) {
+	var found *zip.File
+	var root string
+	for _, file := range files {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if path.Base(name) != ManifestName || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		dir := path.Dir(name)
+		if found == nil || depth(dir) < depth(root) {
+			found, root = file, dir
+		}
+	}
+	return found, root
+}
+
+func depth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// packagePath returns the path of an archive entry relative to the package root,
+// ok is false for entries outside the root and for names escaping it
+func packagePath(name, root string) (string, bool) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") || filepath.IsAbs(name) {
+		return "", false
+	}
+	name = path.Clean(name)
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	if root != "." {
+		if !strings.HasPrefix(name, root+"/") {
+			return "", false
+		}
+		name = strings.TrimPrefix(name, root+"/")
+	}
+	return name, name != "" && name != "."
+}
+
+func readZipFile(file *zip.File, limit int64) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, limit))
+}
+
+func extractFile(file *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer rc.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// the declared size can lie, never write more than it
+	written, err := io.Copy(out, io.LimitReader(rc, int64(file.UncompressedSize64)+1))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	if uint64(written) > file.UncompressedSize64 {
+		return fmt.Errorf("%w: %s is larger than declared", ErrInvalidPackage, file.Name)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package scorm
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SCORM versions a package can be written for
+const (
+	Version12   = "1.2"
+	Version2004 = "2004"
+)
+
+// ManifestName is the file every package has at its root
+const ManifestName = "imsmanifest.xml"
+
+// ErrInvalidPackage is returned for archives that are not a playable SCORM package
+var ErrInvalidPackage = errors.New("invalid SCORM package")
+
+// Manifest is what the player needs from imsmanifest.xml
+type Manifest struct {
+	Version string
+	Title   string
+	// LaunchPath is the entry point of the first SCO, relative to the package root and possibly with a query
+	LaunchPath string
+}
+
+type manifestXML struct {
+	Base     string `xml:"base,attr"`
+	Metadata struct {
+		SchemaVersion string `xml:"schemaversion"`
+	} `xml:"metadata"`
+	Organizations struct {
+		Default       string            `xml:"default,attr"`
+		Organizations []organizationXML `xml:"organization"`
+	} `xml:"organizations"`
+	Resources struct {
+		Base      string        `xml:"base,attr"`
+		Resources []resourceXML `xml:"resource"`
+	} `xml:"resources"`
+}
+
+type organizationXML struct {
+	Identifier string    `xml:"identifier,attr"`
+	Title      string    `xml:"title"`
+	Items      []itemXML `xml:"item"`
+}
+
+type itemXML struct {
+	IdentifierRef string    `xml:"identifierref,attr"`
+	Parameters    string    `xml:"parameters,attr"`
+	Items         []itemXML `xml:"item"`
+}
+
+type resourceXML struct {
+	Identifier string `xml:"identifier,attr"`
+	Href       string `xml:"href,attr"`
+	Base       string `xml:"base,attr"`
+	// SCORM 1.2 spells the attribute scormtype, SCORM 2004 scormType
+	ScormType12   string `xml:"scormtype,attr"`
+	ScormType2004 string `xml:"scormType,attr"`
+}
+
+// ParseManifest reads imsmanifest.xml and finds the launch path of the first item of the default organization
+func ParseManifest(data []byte) (Manifest, error) {
+	var doc manifestXML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+
+	organization, ok := defaultOrganization(doc)
+	if !ok {
+		return Manifest{}, fmt.Errorf("%w: no organization", ErrInvalidPackage)
+	}
+
+	resources := make(map[string]resourceXML, len(doc.Resources.Resources))
+	for _, resource := range doc.Resources.Resources {
+		resources[resource.Identifier] = resource
+	}
+
+	item, resource, ok := firstLaunchable(organization.Items, resources)
+	if !ok {
+		return Manifest{}, fmt.Errorf("%w: no launchable item", ErrInvalidPackage)
+	}
+
+	launchPath, err := joinLaunchPath(doc.Base, doc.Resources.Base, resource.Base, resource.Href, item.Parameters)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return Manifest{
+		Version:    detectVersion(doc.Metadata.SchemaVersion, data),
+		Title:      strings.TrimSpace(organization.Title),
+		LaunchPath: launchPath,
+	}, nil
+}
+
+func defaultOrganization(doc manifestXML) (organizationXML, bool) {
+	organizations := doc.Organizations.Organizations
+	for _, organization := range organizations {
+		if organization.Identifier == doc.Organizations.Default {
+			return organization, true
+		}
+	}
+	if len(organizations) > 0 {
+		return organizations[0], true
+	}
+	return organizationXML{}, false
+}
+
+// firstLaunchable walks the item tree depth first for the first item pointing to a resource with an entry point
+func firstLaunchable(items []itemXML, resources map[string]resourceXML) (itemXML, resourceXML, bool) {
+	for _, item := range items {
+		if resource, ok := resources[item.IdentifierRef]; ok && resource.Href != "" {
+			return item, resource, true
+		}
+		if child, resource, ok := firstLaunchable(item.Items, resources); ok {
+			return child, resource, true
+		}
+	}
+	return itemXML{}, resourceXML{}, false
+}
+
+// joinLaunchPath resolves the href of a resource against the xml:base of the manifest, the resources and the resource,
+// and appends the parameters of the item. Entry points outside the package are refused.
+func joinLaunchPath(manifestBase, resourcesBase, resourceBase, href, parameters string) (string, error) {
+	href, query, _ := strings.Cut(href, "?")
+	for _, part := range []string{manifestBase, resourcesBase, resourceBase, href} {
+		if strings.Contains(part, "://") || strings.HasPrefix(part, "/") {
+			return "", fmt.Errorf("%w: launch path %q is outside the package", ErrInvalidPackage, part)
+		}
+	}
+
+	launch := path.Clean(path.Join(manifestBase, resourcesBase, resourceBase, href))
+	if launch == "." || launch == ".." || strings.HasPrefix(launch, "../") {
+		return "", fmt.Errorf("%w: launch path %q is outside the package", ErrInvalidPackage, launch)
+	}
+
+	parameters = strings.TrimLeft(parameters, "?&")
+	switch {
+	case query != "" && parameters != "":
+		launch += "?" + query + "&" + parameters
+	case query != "":
+		launch += "?" + query
+	case parameters != "":
+		launch += "?" + parameters
+	}
+	return launch, nil
+}
+
+// detectVersion tells SCORM 2004 packages from SCORM 1.2 by the schema version, or else by the namespaces they declare
+func detectVersion(schemaVersion string, data []byte) string {
+	schemaVersion = strings.ToLower(schemaVersion)
+	switch {
+	case strings.Contains(schemaVersion, "2004"), strings.Contains(schemaVersion, "1.3"):
+		return Version2004
+	case strings.Contains(schemaVersion, "1.2"):
+		return Version12
+	case bytes.Contains(data, []byte("adlcp_v1p3")):
+		return Version2004
+	}
+	return Version12
+}
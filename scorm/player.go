@@ -0,0 +1,85 @@
+package scorm
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+)
+
+//go:embed player.js
+var playerJS string
+
+//go:embed adapter.js
+var adapterJS string
+
+// PlayerConfig tells the player which package it plays, where the runtime endpoint is and which origins it talks to
+type PlayerConfig struct {
+	MaterialID int64  `json:"materialID"`
+	RuntimeURL string `json:"runtimeURL"`
+	// FrontEndOrigin embeds the player and hands it the session of the student
+	FrontEndOrigin string `json:"frontEndOrigin"`
+	// ContentOrigin serves the adapter and the package files
+	ContentOrigin string `json:"contentOrigin"`
+}
+
+// AdapterConfig tells the adapter which SCO to launch once it has the data model
+type AdapterConfig struct {
+	LaunchURL string `json:"launchURL"`
+}
+
+var playerTemplate = template.Must(template.New("player").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>html,body{margin:0;height:100%}iframe{border:0;width:100%;height:100%}#error{font-family:sans-serif;padding:1em}</style>
+<script>{{.Script}}</script>
+</head>
+<body>
+<div id="error"></div>
+<iframe id="sco" title="{{.Title}}" sandbox="allow-scripts allow-same-origin allow-forms allow-popups"></iframe>
+<script>window.SCORMPlayer.start({{.Config}});</script>
+</body>
+</html>
+`))
+
+var adapterTemplate = template.Must(template.New("adapter").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>SCORM</title>
+<style>html,body{margin:0;height:100%}iframe{border:0;width:100%;height:100%}</style>
+<script>{{.Script}}</script>
+</head>
+<body>
+<iframe id="sco" title="SCORM content"></iframe>
+<script>window.SCORMAdapter.start({{.Config}});</script>
+</body>
+</html>
+`))
+
+// WritePlayer writes the page a package plays in. The player holds the session of the student and calls the runtime
+// endpoints, the package plays in a sandboxed frame on the content origin and never sees the session.
+func WritePlayer(w io.Writer, title string, config PlayerConfig) error {
+	return playerTemplate.Execute(w, struct {
+		Title  string
+		Script template.JS
+		Config PlayerConfig
+	}{
+		Title:  title,
+		Script: template.JS(playerJS),
+		Config: config,
+	})
+}
+
+// WriteAdapter writes the page that provides the run-time API the SCO looks up in its parent window,
+// so it has to be served from the content origin the package files are served from
+func WriteAdapter(w io.Writer, config AdapterConfig) error {
+	return adapterTemplate.Execute(w, struct {
+		Script template.JS
+		Config AdapterConfig
+	}{
+		Script: template.JS(adapterJS),
+		Config: config,
+	})
+}
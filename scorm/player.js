@@ -0,0 +1,120 @@
+// SCORM player, served from the API origin inside the front end. The front end hands it the access token of the student
+// with postMessage, the package plays in a frame on the content origin whose adapter gets the data model and sends the values
+// a SCO sets back with postMessage. Only the player calls the runtime endpoints, the package never sees the session.
+(function (window) {
+  "use strict";
+
+  function start(config) {
+    var frame = document.getElementById("sco");
+    var accessToken = null, waiting = [];
+    var pending = {}, queue = Promise.resolve();
+    var data = null;
+
+    function fail(message) {
+      document.getElementById("error").textContent = "This package cannot be played: " + message;
+    }
+
+    // the front end answers with the access token of the student, it is the only window trusted to do so
+    window.addEventListener("message", function (event) {
+      if (event.source !== window.parent || event.origin !== config.frontEndOrigin) {
+        return;
+      }
+      if (!event.data || event.data.type !== "scorm:token" || typeof event.data.access_token !== "string") {
+        return;
+      }
+      accessToken = event.data.access_token;
+      waiting.splice(0).forEach(function (resolve) { resolve(accessToken); });
+    });
+
+    function token() {
+      if (accessToken) {
+        return Promise.resolve(accessToken);
+      }
+      return new Promise(function (resolve) {
+        waiting.push(resolve);
+        if (waiting.length === 1) {
+          window.parent.postMessage({ type: "scorm:token" }, config.frontEndOrigin);
+        }
+      });
+    }
+
+    function authorized(url, options, retried) {
+      return token().then(function (current) {
+        options.headers = options.headers || {};
+        options.headers.Authorization = "Bearer " + current;
+        return window.fetch(url, options);
+      }).then(function (rsp) {
+        if (rsp.status !== 401 || retried) {
+          return rsp;
+        }
+        // the token expired, the front end renews it
+        accessToken = null;
+        return authorized(url, options, true);
+      });
+    }
+
+    function commit(values, finish) {
+      Object.keys(values).forEach(function (name) {
+        pending[name] = String(values[name]);
+      });
+      var body = { material_id: config.materialID, values: pending, finish: finish };
+      pending = {};
+      queue = queue.then(function () {
+        return authorized(config.runtimeURL, {
+          method: "PUT",
+          keepalive: true,
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify(body)
+        });
+      }).then(function (rsp) {
+        if (rsp.status === 400) {
+          // values the data model refuses are dropped, sending them again would fail again
+          window.console.error("SCORM commit refused", body.values);
+          return;
+        }
+        if (!rsp.ok) {
+          throw new Error("runtime answered " + rsp.status);
+        }
+      }).catch(function (err) {
+        // keep the values for the next commit unless the SCO changed them since
+        Object.keys(body.values).forEach(function (name) {
+          if (!(name in pending)) {
+            pending[name] = body.values[name];
+          }
+        });
+        window.console.error("SCORM commit failed", err);
+      });
+    }
+
+    // the adapter in the frame asks for the data model and sends what the SCO sets, nothing else is listened to
+    window.addEventListener("message", function (event) {
+      if (event.source !== frame.contentWindow || event.origin !== config.contentOrigin || !event.data) {
+        return;
+      }
+      if (event.data.type === "scorm:ready" && data) {
+        frame.contentWindow.postMessage({ type: "scorm:init", version: data.version, values: data.values }, config.contentOrigin);
+        return;
+      }
+      if (event.data.type === "scorm:commit" && data && event.data.values && typeof event.data.values === "object") {
+        commit(event.data.values, event.data.finish === true);
+      }
+    });
+
+    authorized(config.runtimeURL + "?material_id=" + encodeURIComponent(config.materialID), { method: "GET" })
+      .then(function (rsp) {
+        if (!rsp.ok) {
+          throw new Error("runtime answered " + rsp.status);
+        }
+        return rsp.json();
+      })
+      .then(function (loaded) {
+        data = { version: loaded.version, values: loaded.values || {} };
+        frame.src = loaded.launch_url;
+      })
+      .catch(function (err) {
+        fail(err.message);
+      });
+  }
+
+  window.SCORMPlayer = { start: start };
+})(window);
@@ -0,0 +1,146 @@
+package similarity
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// MaxTextSize limits how much text is read from a single file
+const MaxTextSize = 2 << 20
+
+// ErrUnsupportedFile is returned for files that hold no extractable text, such as images
+var ErrUnsupportedFile = errors.New("file type has no extractable text")
+
+// ExtractText reads the text of a .txt, .docx or .pdf file as valid UTF-8 without NUL bytes, so it can be stored
+func ExtractText(path string) (string, error) {
+	var text string
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt":
+		text, err = extractPlainText(path)
+	case ".docx":
+		text, err = extractDocxText(path)
+	case ".pdf":
+		text, err = extractPDFText(path)
+	default:
+		return "", ErrUnsupportedFile
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(text, ""), "\x00", ""), nil
+}
+
+func extractPlainText(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, MaxTextSize))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// extractDocxText reads the paragraphs of word/document.xml, the main part of a docx package
+func extractDocxText(path string) (string, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open docx: %w", err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.Name != "word/document.xml" {
+			continue
+		}
+
+		document, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer document.Close()
+
+		return readDocxXML(io.LimitReader(document, 4*MaxTextSize))
+	}
+
+	return "", errors.New("docx has no word/document.xml")
+}
+
+func readDocxXML(r io.Reader) (string, error) {
+	decoder := xml.NewDecoder(r)
+
+	var text strings.Builder
+	inText := false
+	for text.Len() < MaxTextSize {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read docx: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+		}
+	}
+
+	return text.String(), nil
+}
+
+func extractPDFText(path string) (text string, err error) {
+	// the PDF reader panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("failed to read pdf: %v", r)
+		}
+	}()
+
+	file, reader, err := pdf.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open pdf: %w", err)
+	}
+	defer file.Close()
+
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("failed to read pdf: %w", err)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(plain, MaxTextSize))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
@@ -0,0 +1,121 @@
+package similarity
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const docxDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>First</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve"> paragraph</w:t></w:r></w:p>
+<w:p><w:r><w:t>Second</w:t><w:br/><w:t>line &amp; more</w:t></w:r><w:r><w:instrText>PAGE</w:instrText></w:r></w:p>
+</w:body></w:document>`
+
+func TestExtractText(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content []byte
+		want    string
+		wantErr string
+	}{
+		{name: "plain text", file: "essay.TXT", content: []byte("héllo\x00 wor\xffld\n"), want: "héllo world\n"},
+		{name: "plain text limit", file: "long.txt", content: bytes.Repeat([]byte("a"), MaxTextSize+10), want: strings.Repeat("a", MaxTextSize)},
+		{name: "docx", file: "essay.docx", content: docx(t, map[string]string{"word/document.xml": docxDocument}),
+			want: "First\t paragraph\nSecond\nline & more\n"},
+		{name: "docx without document", file: "empty.docx", content: docx(t, map[string]string{"word/styles.xml": "<w:styles/>"}),
+			wantErr: "docx has no word/document.xml"},
+		{name: "docx with broken xml", file: "broken.docx", content: docx(t, map[string]string{"word/document.xml": "<w:document><w:t>text</w:p>"}),
+			wantErr: "failed to read docx"},
+		{name: "docx that is not a zip", file: "fake.docx", content: []byte("plain text"), wantErr: "failed to open docx"},
+		{name: "pdf", file: "essay.pdf", content: pdfFile("<< /Type /Pages /Kids [3 0 R] /Count 1 >>", "BT /F1 12 Tf 72 720 Td (Hello from a pdf) Tj ET"),
+			want: "Hello from a pdf"},
+		{name: "pdf that is not a pdf", file: "fake.pdf", content: []byte("plain text"), wantErr: "failed to open pdf"},
+		// the page tree is read outside the PDF reader's own recover, a broken one panics
+		{name: "pdf with a broken page tree", file: "broken.pdf", content: pdfFile(") ] >>", "BT (x) Tj ET"),
+			wantErr: "failed to read pdf: unexpected delimiter"},
+		{name: "image", file: "photo.png", content: []byte("\x89PNG"), wantErr: ErrUnsupportedFile.Error()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, tc.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			text, err := ExtractText(path)
+			if tc.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				if text != "" {
+					t.Errorf("text = %q on error", text)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tc.want {
+				t.Errorf("text = %.80q, want %.80q", text, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractTextUnsupported(t *testing.T) {
+	if _, err := ExtractText("notes.odt"); !errors.Is(err, ErrUnsupportedFile) {
+		t.Errorf("error = %v, want ErrUnsupportedFile", err)
+	}
+}
+
+// docx zips the given parts into a docx package
+func docx(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pdfFile lays out a single page PDF with a correct cross-reference table around the page tree and content stream
+func pdfFile(pages, content string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		pages,
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, xref)
+	return buf.Bytes()
+}
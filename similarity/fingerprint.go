@@ -0,0 +1,193 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// ShingleSize is the number of words hashed together, shorter runs of shared words are not reported
+	ShingleSize = 5
+	// WindowSize is the winnowing window, any shared run of ShingleSize+WindowSize-1 words is found
+	WindowSize = 4
+	// maxPassageLength limits the text quoted for a matched passage
+	maxPassageLength = 500
+)
+
+type word struct {
+	start int
+	end   int
+}
+
+// Document is the text of a submission with its winnowed fingerprints
+type Document struct {
+	text  string
+	words []word
+	// fingerprints maps each selected shingle hash to the word positions it starts at
+	fingerprints map[uint64][]int
+	selected     int
+}
+
+// NewDocument normalises text into lower case words and selects its fingerprints by winnowing
+// the hashes of every ShingleSize word shingle.
+func NewDocument(text string) *Document {
+	doc := &Document{
+		text:         text,
+		fingerprints: make(map[uint64][]int),
+	}
+
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			doc.words = append(doc.words, word{start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		doc.words = append(doc.words, word{start: start, end: len(text)})
+	}
+
+	if len(doc.words) < ShingleSize {
+		return doc
+	}
+
+	hashes := make([]uint64, len(doc.words)-ShingleSize+1)
+	for i := range hashes {
+		hash := fnv.New64a()
+		for _, w := range doc.words[i : i+ShingleSize] {
+			hash.Write([]byte(strings.ToLower(text[w.start:w.end])))
+			hash.Write([]byte{' '})
+		}
+		hashes[i] = hash.Sum64()
+	}
+
+	// winnowing keeps the smallest hash of every window, the rightmost one on ties
+	window := min(WindowSize, len(hashes))
+	last := -1
+	for i := 0; i+window <= len(hashes); i++ {
+		smallest := i
+		for j := i + 1; j < i+window; j++ {
+			if hashes[j] <= hashes[smallest] {
+				smallest = j
+			}
+		}
+		if smallest != last {
+			doc.fingerprints[hashes[smallest]] = append(doc.fingerprints[hashes[smallest]], smallest)
+			doc.selected++
+			last = smallest
+		}
+	}
+
+	return doc
+}
+
+// Fingerprints is the number of fingerprints selected for the document
+func (doc *Document) Fingerprints() int {
+	return doc.selected
+}
+
+// Match is a passage of a document that also appears in the document it was compared with.
+// Offsets are byte offsets into the extracted text of each document.
+type Match struct {
+	Start        int    `json:"start"`
+	End          int    `json:"end"`
+	Text         string `json:"text"`
+	MatchedStart int    `json:"matched_start"`
+	MatchedEnd   int    `json:"matched_end"`
+	MatchedText  string `json:"matched_text"`
+}
+
+// Matches are the matched passages of a comparison
+type Matches []Match
+
+// Result is the outcome of comparing a document with another one
+type Result struct {
+	// Score is the share of the document's fingerprints found in the other document, from 0 to 1
+	Score   float64 `json:"score"`
+	Matches Matches `json:"matches"`
+}
+
+type position struct {
+	doc   int
+	other int
+}
+
+// Compare finds the passages of doc that also appear in other
+func Compare(doc, other *Document) Result {
+	result := Result{Matches: Matches{}}
+	if doc.selected == 0 || other.selected == 0 {
+		return result
+	}
+
+	var shared int
+	var positions []position
+	for hash, docPositions := range doc.fingerprints {
+		otherPositions, ok := other.fingerprints[hash]
+		if !ok {
+			continue
+		}
+		shared += len(docPositions)
+		for _, docPosition := range docPositions {
+			positions = append(positions, position{doc: docPosition, other: otherPositions[0]})
+		}
+	}
+	if shared == 0 {
+		return result
+	}
+	result.Score = float64(shared) / float64(doc.selected)
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].doc != positions[j].doc {
+			return positions[i].doc < positions[j].doc
+		}
+		return positions[i].other < positions[j].other
+	})
+
+	// neighbouring fingerprints of a copied passage are at most a window apart in both documents
+	type span struct{ start, end, otherStart, otherEnd int }
+	var spans []span
+	for _, p := range positions {
+		if n := len(spans); n > 0 {
+			current := &spans[n-1]
+			if p.doc <= current.end+WindowSize && p.other >= current.otherStart && p.other <= current.otherEnd+WindowSize {
+				current.end = max(current.end, p.doc+ShingleSize)
+				current.otherEnd = max(current.otherEnd, p.other+ShingleSize)
+				continue
+			}
+		}
+		spans = append(spans, span{start: p.doc, end: p.doc + ShingleSize, otherStart: p.other, otherEnd: p.other + ShingleSize})
+	}
+
+	for _, s := range spans {
+		start, end := doc.words[s.start].start, doc.words[s.end-1].end
+		otherStart, otherEnd := other.words[s.otherStart].start, other.words[s.otherEnd-1].end
+		result.Matches = append(result.Matches, Match{
+			Start:        start,
+			End:          end,
+			Text:         passage(doc.text[start:end]),
+			MatchedStart: otherStart,
+			MatchedEnd:   otherEnd,
+			MatchedText:  passage(other.text[otherStart:otherEnd]),
+		})
+	}
+
+	return result
+}
+
+// passage shortens long matched text on a rune boundary
+func passage(text string) string {
+	if len(text) <= maxPassageLength {
+		return text
+	}
+	cut := maxPassageLength
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "…"
+}
@@ -0,0 +1,155 @@
+package similarity
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const (
+	firstParagraph  = "The industrial revolution began in Britain during the eighteenth century and spread across Europe within a few decades."
+	secondParagraph = "Steam engines powered factories, railways and ships, which changed how people worked and where they chose to live."
+	thirdParagraph  = "Photosynthesis converts light energy into chemical energy stored in glucose molecules inside the leaves of green plants."
+)
+
+func TestCompareIdentical(t *testing.T) {
+	text := firstParagraph + " " + secondParagraph
+	doc := NewDocument(text)
+	if doc.Fingerprints() == 0 {
+		t.Fatal("no fingerprints selected")
+	}
+
+	result := Compare(doc, NewDocument(text))
+	if result.Score != 1 {
+		t.Errorf("score = %v, want 1", result.Score)
+	}
+	if len(result.Matches) != 1 {
+		t.Fatalf("got %d matches, want the whole text as one: %+v", len(result.Matches), result.Matches)
+	}
+	match := result.Matches[0]
+	checkCovers(t, text, match.Start, match.End)
+	if match.Text != text[match.Start:match.End] {
+		t.Errorf("match text = %q, want the text between its offsets", match.Text)
+	}
+	if match.MatchedStart != match.Start || match.MatchedEnd != match.End {
+		t.Errorf("matched offsets = %d-%d, want %d-%d", match.MatchedStart, match.MatchedEnd, match.Start, match.End)
+	}
+}
+
+func TestCompareNormalisesCaseAndPunctuation(t *testing.T) {
+	doc := NewDocument(firstParagraph)
+	other := NewDocument(strings.ToUpper(strings.NewReplacer(",", "", ".", " !", " ", "  ").Replace(firstParagraph)))
+
+	result := Compare(doc, other)
+	if result.Score != 1 {
+		t.Errorf("score = %v, want 1", result.Score)
+	}
+	if len(result.Matches) != 1 {
+		t.Fatalf("matches = %+v, want the paragraph", result.Matches)
+	}
+	match := result.Matches[0]
+	checkCovers(t, firstParagraph, match.Start, match.End)
+	if !strings.EqualFold(strings.Join(strings.Fields(match.Text), " "), strings.Join(strings.Fields(match.MatchedText), " ")) {
+		t.Errorf("match %q was matched with %q", match.Text, match.MatchedText)
+	}
+}
+
+func TestCompareReordered(t *testing.T) {
+	doc := NewDocument(firstParagraph + " " + secondParagraph)
+	other := NewDocument(secondParagraph + " " + firstParagraph)
+
+	result := Compare(doc, other)
+	// only shingles crossing the paragraph boundary are not shared
+	if result.Score < 0.7 || result.Score >= 1 {
+		t.Errorf("score = %v, want most but not all fingerprints shared", result.Score)
+	}
+	if len(result.Matches) != 2 {
+		t.Fatalf("got %d matches, want one per paragraph: %+v", len(result.Matches), result.Matches)
+	}
+	for _, match := range result.Matches {
+		if match.Text != match.MatchedText {
+			t.Errorf("match %q was matched with %q", match.Text, match.MatchedText)
+		}
+	}
+	first, second := result.Matches[0], result.Matches[1]
+	if !strings.Contains(firstParagraph, first.Text) || first.MatchedStart <= len(secondParagraph) {
+		t.Errorf("first match = %+v, want the first paragraph found after the second one", first)
+	}
+	checkCovers(t, firstParagraph, first.Start, first.End)
+	offset := len(firstParagraph) + 1
+	if !strings.Contains(secondParagraph, second.Text) || second.MatchedEnd > len(secondParagraph) {
+		t.Errorf("second match = %+v, want the second paragraph found at the start", second)
+	}
+	checkCovers(t, secondParagraph, second.Start-offset, second.End-offset)
+}
+
+func TestCompareDisjoint(t *testing.T) {
+	result := Compare(NewDocument(firstParagraph), NewDocument(thirdParagraph))
+	if result.Score != 0 {
+		t.Errorf("score = %v, want 0", result.Score)
+	}
+	if result.Matches == nil || len(result.Matches) != 0 {
+		t.Errorf("matches = %#v, want an empty list", result.Matches)
+	}
+}
+
+func TestComparePartialCopy(t *testing.T) {
+	doc := NewDocument(thirdParagraph + " " + secondParagraph)
+	other := NewDocument(firstParagraph + " " + secondParagraph)
+
+	result := Compare(doc, other)
+	if result.Score <= 0.3 || result.Score >= 0.7 {
+		t.Errorf("score = %v, want about half", result.Score)
+	}
+	if len(result.Matches) != 1 || !strings.Contains(secondParagraph, result.Matches[0].Text) {
+		t.Fatalf("matches = %+v, want the copied paragraph", result.Matches)
+	}
+	offset := len(thirdParagraph) + 1
+	checkCovers(t, secondParagraph, result.Matches[0].Start-offset, result.Matches[0].End-offset)
+}
+
+func TestCompareShortText(t *testing.T) {
+	short := NewDocument("only four words here")
+	if short.Fingerprints() != 0 {
+		t.Errorf("fingerprints = %d, want none below %d words", short.Fingerprints(), ShingleSize)
+	}
+
+	for _, result := range []Result{
+		Compare(short, NewDocument("only four words here")),
+		Compare(NewDocument(firstParagraph), short),
+		Compare(NewDocument(""), NewDocument("")),
+	} {
+		if result.Score != 0 || len(result.Matches) != 0 {
+			t.Errorf("result = %+v, want no match", result)
+		}
+	}
+}
+
+// checkCovers fails unless start and end lie within text and leave out fewer than WindowSize words at
+// either edge, winnowing finds a shared passage only from its first selected fingerprint to its last.
+func checkCovers(t *testing.T, text string, start, end int) {
+	t.Helper()
+	if start < 0 || end > len(text) || start >= end {
+		t.Fatalf("match %d-%d is outside the %d bytes of %q", start, end, len(text), text)
+	}
+	before, after := len(strings.Fields(text[:start])), len(strings.Fields(text[end:]))
+	if before >= WindowSize || after >= WindowSize {
+		t.Errorf("match %q leaves out %d words before and %d after", text[start:end], before, after)
+	}
+}
+
+func TestPassage(t *testing.T) {
+	if got := passage("short"); got != "short" {
+		t.Errorf("passage(short) = %q", got)
+	}
+
+	// a two byte rune straddles the cut
+	long := strings.Repeat("a", maxPassageLength-1) + "é" + "tail"
+	got := passage(long)
+	if !utf8.ValidString(got) {
+		t.Fatalf("passage cut inside a rune: %q", got[len(got)-8:])
+	}
+	if want := strings.Repeat("a", maxPassageLength-1) + "…"; got != want {
+		t.Errorf("passage = ...%q, want ...%q", got[len(got)-8:], want[len(want)-8:])
+	}
+}
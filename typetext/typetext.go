@@ -44,3 +44,50 @@ type WhoShouldLearn struct {
 	Person2 string `form:"person2" json:"person2"`
 	Person3 string `form:"person3" json:"person3"`
 }
+
+// ImageVariants holds the resized copies generated from an uploaded image.
+type ImageVariants struct {
+	Thumb  ImageVariant `json:"thumb"`
+	Medium ImageVariant `json:"medium"`
+	Full   ImageVariant `json:"full"`
+}
+
+// ImageVariant is one size of an uploaded image with its URL in each encoded format.
+type ImageVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	JPEG   string `json:"jpeg,omitempty"`
+	WebP   string `json:"webp,omitempty"`
+}
+
+// RubricCriteria are the criteria of a rubric in the order they are graded.
+type RubricCriteria []RubricCriterion
+
+// RubricCriterion is one aspect of the work, graded by choosing one of its performance levels.
+type RubricCriterion struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Levels      []RubricLevel `json:"levels"`
+}
+
+// RubricLevel is a performance level of a criterion and the points it is worth.
+type RubricLevel struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Points      int64  `json:"points"`
+}
+
+// RubricScores are the levels chosen for each criterion when a submission is graded.
+type RubricScores []RubricScore
+
+// RubricScore records the level chosen for a criterion with the grader's comment.
+// Titles and points are copied so the feedback stays readable if the rubric changes later.
+type RubricScore struct {
+	Criterion  int    `json:"criterion"`
+	Title      string `json:"title"`
+	Level      int    `json:"level"`
+	LevelTitle string `json:"level_title"`
+	Points     int64  `json:"points"`
+	MaxPoints  int64  `json:"max_points"`
+	Comment    string `json:"comment"`
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/spf13/viper"
@@ -23,6 +24,33 @@ type Config struct {
 	EmailSenderAddress   string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
 	EmailSenderPassword  string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
 	VerifyEmailBaseURL   string        `mapstructure:"VERIFY_EMAIL_BASE_URL"`
+	FileGCSchedule       string        `mapstructure:"FILE_GC_SCHEDULE"`
+	FileGCGracePeriod    time.Duration `mapstructure:"FILE_GC_GRACE_PERIOD"`
+	FileGCDryRun         bool          `mapstructure:"FILE_GC_DRY_RUN"`
+	PeerReviewSchedule   string        `mapstructure:"PEER_REVIEW_SCHEDULE"`
+	ExpirySchedule       string        `mapstructure:"SUBSCRIPTION_EXPIRY_SCHEDULE"`
+	ExpiryReminderDays   int           `mapstructure:"SUBSCRIPTION_REMINDER_DAYS"`
+	DueDateSchedule      string        `mapstructure:"DUE_DATE_REMINDER_SCHEDULE"`
+	PaymentProvider      string        `mapstructure:"PAYMENT_PROVIDER"`
+	PaymentSecretKey     string        `mapstructure:"PAYMENT_SECRET_KEY"`
+	PaymentWebhookSecret string        `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	PaymentSuccessURL    string        `mapstructure:"PAYMENT_SUCCESS_URL"`
+	PaymentCancelURL     string        `mapstructure:"PAYMENT_CANCEL_URL"`
+	CertificateTemplate  string        `mapstructure:"CERTIFICATE_TEMPLATE"`
+	CertificateVerifyURL string        `mapstructure:"CERTIFICATE_VERIFY_URL"`
+	BadgeBaseURL         string        `mapstructure:"BADGE_BASE_URL"`
+	BadgeIssuerName      string        `mapstructure:"BADGE_ISSUER_NAME"`
+	BadgeSigningKey      string        `mapstructure:"BADGE_SIGNING_KEY"`
+	VideoCompletePercent int64         `mapstructure:"VIDEO_COMPLETE_PERCENT"`
+	XAPIBaseURL          string        `mapstructure:"XAPI_BASE_URL"`
+	XAPIEndpoint         string        `mapstructure:"XAPI_LRS_ENDPOINT"`
+	XAPIUsername         string        `mapstructure:"XAPI_LRS_USERNAME"`
+	XAPIPassword         string        `mapstructure:"XAPI_LRS_PASSWORD"`
+	XAPIKey              string        `mapstructure:"XAPI_KEY"`
+	XAPISecret           string        `mapstructure:"XAPI_SECRET"`
+	LTIToolURL           string        `mapstructure:"LTI_TOOL_URL"`
+	LTIPrivateKey        string        `mapstructure:"LTI_PRIVATE_KEY"`
+	SCORMContentOrigin   string        `mapstructure:"SCORM_CONTENT_ORIGIN"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -32,6 +60,18 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigType("env")
 
 	viper.AutomaticEnv()
+	// AutomaticEnv only overrides keys viper already knows, so every key is bound to be settable
+	// from the environment even when app.env leaves it out
+	configType := reflect.TypeOf(config)
+	for i := 0; i < configType.NumField(); i++ {
+		if key := configType.Field(i).Tag.Get("mapstructure"); key != "" {
+			if err = viper.BindEnv(key); err != nil {
+				return
+			}
+		}
+	}
+	// deleting files has to be turned on deliberately
+	viper.SetDefault("FILE_GC_DRY_RUN", true)
 
 	err = viper.ReadInConfig()
 	if err != nil {
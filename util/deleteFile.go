@@ -2,36 +2,59 @@ package util
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
-func DeleteFileByURL(fileURL string) error {
-	// Extract the file path from the URL
-	parts := strings.Split(fileURL, "/")
-	println(parts)
+// UploadDir is the directory every uploaded file is stored under
+const UploadDir = "uploads"
+
+// FilePathFromURL maps a stored file URL back to its path below UploadDir.
+// Files are served either as /static/... or /uploads/..., both of which point to UploadDir.
+func FilePathFromURL(fileURL string) (string, error) {
+	urlPath := fileURL
+	if parsed, err := url.Parse(fileURL); err == nil && parsed.Path != "" {
+		urlPath = parsed.Path
+	}
 
-	if len(parts) > 2 && parts[3] == "static" {
-		parts[3] = "uploads"
+	parts := strings.Split(strings.TrimPrefix(filepath.ToSlash(urlPath), "/"), "/")
+	for i, part := range parts {
+		if (part == "static" || part == UploadDir) && i < len(parts)-1 {
+			return filepath.Join(append([]string{UploadDir}, parts[i+1:]...)...), nil
+		}
 	}
-	newURl := strings.Join(parts, "/")
-	println(newURl)
 
-	//trim the server address and port 8080
-	trimmedPath := filepath.Join("", filepath.Clean(strings.TrimPrefix(newURl, "http://172.17.249.61:8080")))
-	println(trimmedPath)
+	return "", fmt.Errorf("not an uploaded file url: %s", fileURL)
+}
+
+// StaticFileURL returns the public URL of a file stored below UploadDir, the reverse of FilePathFromURL
+func StaticFileURL(fileSource string, filePath string) string {
+	parts := strings.Split(filepath.ToSlash(filePath), "/")
+
+	// Replace "uploads" with "static"
+	if len(parts) > 2 && parts[0] == UploadDir {
+		parts[0] = "static"
+	}
+
+	return fmt.Sprintf("%s/%s", fileSource, strings.Join(parts, "/"))
+}
+
+func DeleteFileByURL(fileURL string) error {
+	filePath, err := FilePathFromURL(fileURL)
+	if err != nil {
+		return err
+	}
 
-	newpath := "." + trimmedPath
 	// Delete the file
-	err := os.Remove(newpath)
+	err = os.Remove(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("file not found")
 		}
 		return err
 	}
-	println("success")
 
 	return nil
 }
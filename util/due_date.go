@@ -0,0 +1,123 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// late policies of an assignment
+const (
+	LatePolicyAccept  = "accept"
+	LatePolicyPenalty = "penalty"
+	LatePolicyReject  = "reject"
+)
+
+// dueDateLayouts are the formats accepted for due dates, the same ones the
+// assignment due date migration converts from the old varchar column
+var dueDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// dateOnlyLayouts describe a day without a time, which is due at the end of that day
+var dateOnlyLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"01/02/2006",
+}
+
+// ParseDueDate parses a due date sent by a client or stored in the old varchar column.
+// Values without a timezone are read in loc, dates without a time are due at the end of the day.
+func ParseDueDate(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range dueDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, layout := range dateOnlyLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.AddDate(0, 0, 1).Add(-time.Second), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid due date %q, expected a date such as 2006-01-02 or 2006-01-02T15:04:05Z07:00", value)
+}
+
+// LatePolicy describes how an assignment treats work submitted after its due date
+type LatePolicy struct {
+	Policy         string
+	PenaltyPercent int64
+	// Cutoff is the last moment late work is accepted, the zero time means no cutoff
+	Cutoff time.Time
+}
+
+// Validate checks the policy name and penalty range
+func (policy LatePolicy) Validate() error {
+	switch policy.Policy {
+	case LatePolicyAccept, LatePolicyReject:
+	case LatePolicyPenalty:
+		if policy.PenaltyPercent < 0 || policy.PenaltyPercent > 100 {
+			return fmt.Errorf("late penalty must be between 0 and 100 percent")
+		}
+	default:
+		return fmt.Errorf("unsupported late policy: %s", policy.Policy)
+	}
+	return nil
+}
+
+// LateSubmission is the outcome of checking a submission time against a due date
+type LateSubmission struct {
+	IsLate         bool
+	PenaltyPercent int64
+}
+
+// CheckSubmissionTime applies the late policy to a submission made at submittedAt.
+// A zero due date means the assignment has no deadline. The reject policy refuses late work after
+// the cutoff, or right after the due date when there is no cutoff, and the penalty policy refuses
+// it only after a cutoff.
+func (policy LatePolicy) CheckSubmissionTime(dueDate, submittedAt time.Time) (LateSubmission, error) {
+	if dueDate.IsZero() || !submittedAt.After(dueDate) {
+		return LateSubmission{}, nil
+	}
+
+	cutoff := policy.Cutoff
+	if !cutoff.IsZero() && cutoff.Before(dueDate) {
+		cutoff = dueDate
+	}
+
+	switch policy.Policy {
+	case LatePolicyReject:
+		if cutoff.IsZero() || submittedAt.After(cutoff) {
+			return LateSubmission{}, fmt.Errorf("submissions closed at %s", maxTime(cutoff, dueDate).Format(time.RFC3339))
+		}
+		return LateSubmission{IsLate: true}, nil
+	case LatePolicyPenalty:
+		if !cutoff.IsZero() && submittedAt.After(cutoff) {
+			return LateSubmission{}, fmt.Errorf("submissions closed at %s", cutoff.Format(time.RFC3339))
+		}
+		return LateSubmission{IsLate: true, PenaltyPercent: policy.PenaltyPercent}, nil
+	}
+
+	return LateSubmission{IsLate: true}, nil
+}
+
+// ApplyLatePenalty reduces a score by a penalty percentage
+func ApplyLatePenalty(score float64, penaltyPercent int64) float64 {
+	if penaltyPercent <= 0 {
+		return score
+	}
+	return score * float64(100-min(penaltyPercent, 100)) / 100
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
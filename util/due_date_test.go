@@ -0,0 +1,158 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueDate(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+
+	// the assignment due date migration accepts exactly these values, keep db/migration/000005 in step
+	testCases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-03-10T14:30:00Z", time.Date(2024, 3, 10, 14, 30, 0, 0, time.UTC)},
+		{"2024-03-10T14:30:00.250+02:00", time.Date(2024, 3, 10, 12, 30, 0, 250e6, time.UTC)},
+		{"2024-03-10T14:30:00-05:30", time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC)},
+		{"2024-03-10T14:30", time.Date(2024, 3, 10, 14, 30, 0, 0, paris)},
+		{"2024-03-10 14:30:15", time.Date(2024, 3, 10, 14, 30, 15, 0, paris)},
+		{"2024-03-10 14:30", time.Date(2024, 3, 10, 14, 30, 0, 0, paris)},
+		{"2024-03-10 9:05", time.Date(2024, 3, 10, 9, 5, 0, 0, paris)},
+		{"  2024-03-10 14:30  ", time.Date(2024, 3, 10, 14, 30, 0, 0, paris)},
+		{"2024-07-01", time.Date(2024, 7, 1, 23, 59, 59, 0, paris)},
+		{"2024/07/01", time.Date(2024, 7, 1, 23, 59, 59, 0, paris)},
+		{"07/01/2024", time.Date(2024, 7, 1, 23, 59, 59, 0, paris)},
+		{"2024-02-29", time.Date(2024, 2, 29, 23, 59, 59, 0, paris)},
+	}
+	for _, tc := range testCases {
+		got, err := ParseDueDate(tc.value, paris)
+		if err != nil {
+			t.Errorf("ParseDueDate(%q) error = %v", tc.value, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParseDueDate(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+
+	for _, value := range []string{
+		"",
+		"tomorrow",
+		"2024-02-30",
+		"2023-02-29",
+		"2024-13-01",
+		"13/01/2024",
+		"2024-03-10 24:00",
+		"2024-03-10 14:60",
+		"2024-03-10 14:30:60",
+		"2024-03-10T14:30:00",
+		"2024-03-10T14:30:00+0200",
+		"2024-3-10",
+		"10 March 2024",
+		"2024-03-10 14:30 UTC",
+	} {
+		if got, err := ParseDueDate(value, paris); err == nil {
+			t.Errorf("ParseDueDate(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+func TestCheckSubmissionTime(t *testing.T) {
+	due := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cutoff := due.Add(48 * time.Hour)
+	onTime := due.Add(-time.Minute)
+	late := due.Add(time.Hour)
+	afterCutoff := cutoff.Add(time.Second)
+
+	testCases := []struct {
+		name      string
+		policy    LatePolicy
+		due       time.Time
+		submitted time.Time
+		want      LateSubmission
+		wantError bool
+	}{
+		{name: "no deadline", policy: LatePolicy{Policy: LatePolicyReject}, submitted: afterCutoff},
+		{name: "on time", policy: LatePolicy{Policy: LatePolicyReject}, due: due, submitted: onTime},
+		{name: "right at the deadline", policy: LatePolicy{Policy: LatePolicyReject}, due: due, submitted: due},
+		{name: "accept late", policy: LatePolicy{Policy: LatePolicyAccept}, due: due, submitted: afterCutoff,
+			want: LateSubmission{IsLate: true}},
+		{name: "accept ignores the cutoff", policy: LatePolicy{Policy: LatePolicyAccept, Cutoff: cutoff}, due: due, submitted: afterCutoff,
+			want: LateSubmission{IsLate: true}},
+		{name: "reject without cutoff", policy: LatePolicy{Policy: LatePolicyReject}, due: due, submitted: late, wantError: true},
+		{name: "reject before the cutoff", policy: LatePolicy{Policy: LatePolicyReject, Cutoff: cutoff}, due: due, submitted: late,
+			want: LateSubmission{IsLate: true}},
+		{name: "reject after the cutoff", policy: LatePolicy{Policy: LatePolicyReject, Cutoff: cutoff}, due: due, submitted: afterCutoff,
+			wantError: true},
+		{name: "cutoff before the deadline closes at the deadline", policy: LatePolicy{Policy: LatePolicyReject, Cutoff: due.Add(-time.Hour)},
+			due: due, submitted: late, wantError: true},
+		{name: "penalty", policy: LatePolicy{Policy: LatePolicyPenalty, PenaltyPercent: 20}, due: due, submitted: afterCutoff,
+			want: LateSubmission{IsLate: true, PenaltyPercent: 20}},
+		{name: "penalty before the cutoff", policy: LatePolicy{Policy: LatePolicyPenalty, PenaltyPercent: 20, Cutoff: cutoff}, due: due,
+			submitted: cutoff, want: LateSubmission{IsLate: true, PenaltyPercent: 20}},
+		{name: "penalty after the cutoff", policy: LatePolicy{Policy: LatePolicyPenalty, PenaltyPercent: 20, Cutoff: cutoff}, due: due,
+			submitted: afterCutoff, wantError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.policy.CheckSubmissionTime(tc.due, tc.submitted)
+			if (err != nil) != tc.wantError {
+				t.Fatalf("CheckSubmissionTime() error = %v, want error %v", err, tc.wantError)
+			}
+			if got != tc.want {
+				t.Errorf("CheckSubmissionTime() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLatePolicyValidate(t *testing.T) {
+	valid := []LatePolicy{
+		{Policy: LatePolicyAccept},
+		{Policy: LatePolicyReject},
+		{Policy: LatePolicyPenalty, PenaltyPercent: 0},
+		{Policy: LatePolicyPenalty, PenaltyPercent: 100},
+	}
+	for _, policy := range valid {
+		if err := policy.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", policy, err)
+		}
+	}
+
+	invalid := []LatePolicy{
+		{Policy: ""},
+		{Policy: "forgive"},
+		{Policy: LatePolicyPenalty, PenaltyPercent: -1},
+		{Policy: LatePolicyPenalty, PenaltyPercent: 101},
+	}
+	for _, policy := range invalid {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil", policy)
+		}
+	}
+}
+
+func TestApplyLatePenalty(t *testing.T) {
+	testCases := []struct {
+		score   float64
+		percent int64
+		want    float64
+	}{
+		{80, 0, 80},
+		{80, -10, 80},
+		{80, 25, 60},
+		{80, 100, 0},
+		{80, 150, 0},
+	}
+	for _, tc := range testCases {
+		if got := ApplyLatePenalty(tc.score, tc.percent); got != tc.want {
+			t.Errorf("ApplyLatePenalty(%v, %d) = %v, want %v", tc.score, tc.percent, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// strategies for generating course groups
+const (
+	// GroupStrategyRandom fills groups of exactly the requested size, the last group takes the students left over
+	GroupStrategyRandom = "random"
+	// GroupStrategyBalanced makes as many groups as the random strategy but spreads students so sizes differ by at most one
+	GroupStrategyBalanced = "balanced"
+)
+
+// SplitIntoGroups shuffles the students and splits them into groups of about groupSize
+func SplitIntoGroups(userIDs []int64, groupSize int, strategy string, rng *rand.Rand) ([][]int64, error) {
+	if groupSize < 1 {
+		return nil, fmt.Errorf("group size must be at least 1")
+	}
+	if strategy != GroupStrategyRandom && strategy != GroupStrategyBalanced {
+		return nil, fmt.Errorf("unknown group strategy %q, expected %s or %s", strategy, GroupStrategyRandom, GroupStrategyBalanced)
+	}
+	if len(userIDs) == 0 {
+		return [][]int64{}, nil
+	}
+
+	students := make([]int64, len(userIDs))
+	copy(students, userIDs)
+	rng.Shuffle(len(students), func(i, j int) {
+		students[i], students[j] = students[j], students[i]
+	})
+
+	count := (len(students) + groupSize - 1) / groupSize
+	groups := make([][]int64, count)
+	for i, student := range students {
+		if strategy == GroupStrategyBalanced {
+			groups[i%count] = append(groups[i%count], student)
+		} else {
+			groups[i/groupSize] = append(groups[i/groupSize], student)
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,182 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// MaxImageUploadSize is the largest image accepted for processing
+const MaxImageUploadSize = 20 << 20
+
+// MaxImagePixels caps the declared size of an image, a small file can claim dimensions that need gigabytes to decode
+const MaxImagePixels = 40_000_000
+
+// ImageEncoder writes an image in one output format
+type ImageEncoder func(w io.Writer, img image.Image) error
+
+// imageEncoders holds the output formats variants are generated in, keyed by file extension
+var imageEncoders = map[string]ImageEncoder{
+	"jpg": func(w io.Writer, img image.Image) error {
+		// JPEG has no alpha channel, so transparent areas are flattened onto white
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+		return jpeg.Encode(w, flat, &jpeg.Options{Quality: 82})
+	},
+	"webp": EncodeWebP,
+}
+
+// ImageEncoders returns the registered output formats keyed by file extension
+func ImageEncoders() map[string]ImageEncoder {
+	return imageEncoders
+}
+
+// DecodeImage decodes a JPEG or PNG image of at most MaxImagePixels and rotates it upright according to its EXIF orientation.
+// It returns the decoded format ("jpeg" or "png") so callers can check it against the file name.
+// The decoded image carries no metadata, so EXIF data (including GPS) is dropped once it is encoded again.
+func DecodeImage(r io.Reader) (image.Image, string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxImageUploadSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > MaxImageUploadSize {
+		return nil, "", fmt.Errorf("image is larger than %d MB", MaxImageUploadSize>>20)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return nil, "", fmt.Errorf("image of %dx%d pixels is larger than %d megapixels", config.Width, config.Height, MaxImagePixels/1_000_000)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if format == "jpeg" {
+		img = orientImage(img, ExifOrientation(data))
+	}
+
+	return img, format, nil
+}
+
+// orientImage applies an EXIF orientation so the image is displayed upright
+func orientImage(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, src.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
+
+// FitImage scales an image down to fit inside maxSide x maxSide, keeping its aspect ratio.
+// Images that already fit are only converted, never scaled up.
+func FitImage(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSide && h <= maxSide {
+		return resizeImage(src, bounds, w, h)
+	}
+
+	if w >= h {
+		return resizeImage(src, bounds, maxSide, max(1, h*maxSide/w))
+	}
+	return resizeImage(src, bounds, max(1, w*maxSide/h), maxSide)
+}
+
+// CropImage crops the centre square of an image and scales it to side x side
+func CropImage(src image.Image, side int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	square := min(w, h)
+
+	crop := image.Rect(0, 0, square, square).Add(image.Pt(
+		bounds.Min.X+(w-square)/2,
+		bounds.Min.Y+(h-square)/2,
+	))
+	if square < side {
+		side = square
+	}
+
+	return resizeImage(src, crop, side, side)
+}
+
+// resizeImage scales the area r of src to w x h by averaging the source pixels under each target pixel
+func resizeImage(src image.Image, r image.Rectangle, w, h int) *image.RGBA {
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(src.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	srcW, srcH := r.Dx(), r.Dy()
+	for y := 0; y < h; y++ {
+		y0 := r.Min.Y + y*srcH/h
+		y1 := max(y0+1, r.Min.Y+(y+1)*srcH/h)
+		for x := 0; x < w; x++ {
+			x0 := r.Min.X + x*srcW/w
+			x1 := max(x0+1, r.Min.X+(x+1)*srcW/w)
+
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					sum[0] += uint64(rgba.Pix[row])
+					sum[1] += uint64(rgba.Pix[row+1])
+					sum[2] += uint64(rgba.Pix[row+2])
+					sum[3] += uint64(rgba.Pix[row+3])
+					row += 4
+				}
+			}
+
+			count := uint64((y1 - y0) * (x1 - x0))
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(sum[0] / count)
+			dst.Pix[offset+1] = uint8(sum[1] / count)
+			dst.Pix[offset+2] = uint8(sum[2] / count)
+			dst.Pix[offset+3] = uint8(sum[3] / count)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,163 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testImage is a w x h image with a red top left pixel, so rotations can be told apart
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.SetNRGBA(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withExifOrientation inserts an APP1 segment holding an orientation right after the start of a JPEG
+func withExifOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+// withPNGSize rewrites the dimensions a PNG declares in its header, leaving the pixel data as it is
+func withPNGSize(data []byte, width, height uint32) []byte {
+	out := append([]byte{}, data...)
+	// signature (8), IHDR length (4) and type (4), then width and height
+	binary.BigEndian.PutUint32(out[16:], width)
+	binary.BigEndian.PutUint32(out[20:], height)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func TestDecodeImage(t *testing.T) {
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, testImage(40, 20), nil); err != nil {
+		t.Fatal(err)
+	}
+	pngData := encodePNG(t, testImage(40, 20))
+
+	testCases := []struct {
+		name       string
+		data       []byte
+		wantFormat string
+		wantSize   image.Point
+		wantError  string
+	}{
+		{name: "png", data: pngData, wantFormat: "png", wantSize: image.Pt(40, 20)},
+		{name: "jpeg", data: jpegData.Bytes(), wantFormat: "jpeg", wantSize: image.Pt(40, 20)},
+		{name: "jpeg turned upright", data: withExifOrientation(jpegData.Bytes(), 6), wantFormat: "jpeg", wantSize: image.Pt(20, 40)},
+		{name: "png keeps its orientation", data: pngData, wantFormat: "png", wantSize: image.Pt(40, 20)},
+		{name: "declared size over the pixel cap", data: withPNGSize(pngData, 50000, 50000), wantError: "megapixels"},
+		{name: "declared size just over the pixel cap", data: withPNGSize(pngData, 8000, 5001), wantError: "megapixels"},
+		{name: "larger than the upload limit", data: make([]byte, MaxImageUploadSize+1), wantError: "MB"},
+		{name: "not an image", data: []byte("GIF89a not really"), wantError: "decode"},
+		{name: "empty", wantError: "decode"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, format, err := DecodeImage(bytes.NewReader(tc.data))
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("DecodeImage() error = %v, want one mentioning %q", err, tc.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeImage() error = %v", err)
+			}
+			if format != tc.wantFormat {
+				t.Errorf("format = %q, want %q", format, tc.wantFormat)
+			}
+			if size := img.Bounds().Size(); size != tc.wantSize {
+				t.Errorf("size = %v, want %v", size, tc.wantSize)
+			}
+		})
+	}
+}
+
+func TestOrientImage(t *testing.T) {
+	src := testImage(3, 2)
+
+	// where the red top left pixel of the source ends up for every orientation
+	testCases := []struct {
+		orientation int
+		wantSize    image.Point
+		wantRed     image.Point
+	}{
+		{1, image.Pt(3, 2), image.Pt(0, 0)},
+		{2, image.Pt(3, 2), image.Pt(2, 0)},
+		{3, image.Pt(3, 2), image.Pt(2, 1)},
+		{4, image.Pt(3, 2), image.Pt(0, 1)},
+		{5, image.Pt(2, 3), image.Pt(0, 0)},
+		{6, image.Pt(2, 3), image.Pt(1, 0)},
+		{7, image.Pt(2, 3), image.Pt(1, 2)},
+		{8, image.Pt(2, 3), image.Pt(0, 2)},
+		{9, image.Pt(3, 2), image.Pt(0, 0)},
+	}
+
+	for _, tc := range testCases {
+		dst := orientImage(src, tc.orientation)
+		if size := dst.Bounds().Size(); size != tc.wantSize {
+			t.Errorf("orientation %d: size = %v, want %v", tc.orientation, size, tc.wantSize)
+			continue
+		}
+		if r, g, _, _ := dst.At(tc.wantRed.X, tc.wantRed.Y).RGBA(); r != 0xffff || g != 0 {
+			t.Errorf("orientation %d: pixel %v is not the red corner", tc.orientation, tc.wantRed)
+		}
+	}
+}
+
+func TestFitAndCropImage(t *testing.T) {
+	testCases := []struct {
+		name string
+		img  image.Image
+		want image.Point
+	}{
+		{"fit wide", FitImage(testImage(400, 100), 200), image.Pt(200, 50)},
+		{"fit tall", FitImage(testImage(100, 400), 200), image.Pt(50, 200)},
+		{"fit never scales up", FitImage(testImage(40, 30), 200), image.Pt(40, 30)},
+		{"fit keeps a pixel of a thin image", FitImage(testImage(1000, 1), 100), image.Pt(100, 1)},
+		{"crop wide", CropImage(testImage(400, 100), 50), image.Pt(50, 50)},
+		{"crop smaller than the side", CropImage(testImage(30, 60), 50), image.Pt(30, 30)},
+	}
+
+	for _, tc := range testCases {
+		if size := tc.img.Bounds().Size(); size != tc.want {
+			t.Errorf("%s: size = %v, want %v", tc.name, size, tc.want)
+		}
+	}
+}
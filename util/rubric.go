@@ -0,0 +1,103 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"eduApp/typetext"
+)
+
+// RubricSelection is the level a grader picked for a criterion
+type RubricSelection struct {
+	Criterion int    `json:"criterion"`
+	Level     int    `json:"level"`
+	Comment   string `json:"comment"`
+}
+
+// ValidateRubric checks that every criterion has a title and at least one level with non-negative points
+func ValidateRubric(criteria typetext.RubricCriteria) error {
+	if len(criteria) == 0 {
+		return errors.New("a rubric needs at least one criterion")
+	}
+
+	for i, criterion := range criteria {
+		if strings.TrimSpace(criterion.Title) == "" {
+			return fmt.Errorf("criterion %d needs a title", i)
+		}
+		if len(criterion.Levels) == 0 {
+			return fmt.Errorf("criterion %q needs at least one level", criterion.Title)
+		}
+		for _, level := range criterion.Levels {
+			if strings.TrimSpace(level.Title) == "" {
+				return fmt.Errorf("every level of criterion %q needs a title", criterion.Title)
+			}
+			if level.Points < 0 {
+				return fmt.Errorf("level %q of criterion %q cannot have negative points", level.Title, criterion.Title)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ScoreRubric turns the grader's selections into scores, one for every criterion of the rubric,
+// and returns them with the total and the highest possible total
+func ScoreRubric(criteria typetext.RubricCriteria, selections []RubricSelection) (typetext.RubricScores, int64, int64, error) {
+	chosen := make(map[int]RubricSelection, len(selections))
+	for _, selection := range selections {
+		if selection.Criterion < 0 || selection.Criterion >= len(criteria) {
+			return nil, 0, 0, fmt.Errorf("criterion %d does not exist", selection.Criterion)
+		}
+		if _, ok := chosen[selection.Criterion]; ok {
+			return nil, 0, 0, fmt.Errorf("criterion %d is scored twice", selection.Criterion)
+		}
+		chosen[selection.Criterion] = selection
+	}
+
+	scores := make(typetext.RubricScores, 0, len(criteria))
+	var total, maxTotal int64
+	for i, criterion := range criteria {
+		maxPoints := criterionMaxPoints(criterion)
+		maxTotal += maxPoints
+
+		selection, ok := chosen[i]
+		if !ok {
+			return nil, 0, 0, fmt.Errorf("criterion %q has not been scored", criterion.Title)
+		}
+		if selection.Level < 0 || selection.Level >= len(criterion.Levels) {
+			return nil, 0, 0, fmt.Errorf("level %d does not exist for criterion %q", selection.Level, criterion.Title)
+		}
+
+		level := criterion.Levels[selection.Level]
+		total += level.Points
+		scores = append(scores, typetext.RubricScore{
+			Criterion:  i,
+			Title:      criterion.Title,
+			Level:      selection.Level,
+			LevelTitle: level.Title,
+			Points:     level.Points,
+			MaxPoints:  maxPoints,
+			Comment:    strings.TrimSpace(selection.Comment),
+		})
+	}
+
+	return scores, total, maxTotal, nil
+}
+
+// RubricMaxPoints is the highest total a rubric can give
+func RubricMaxPoints(criteria typetext.RubricCriteria) int64 {
+	var total int64
+	for _, criterion := range criteria {
+		total += criterionMaxPoints(criterion)
+	}
+	return total
+}
+
+func criterionMaxPoints(criterion typetext.RubricCriterion) int64 {
+	var points int64
+	for _, level := range criterion.Levels {
+		points = max(points, level.Points)
+	}
+	return points
+}
@@ -0,0 +1,336 @@
+package util
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// maxWebPSide is the largest width or height a lossless WebP image can have
+const maxWebPSide = 1 << 14
+
+// vp8l alphabet sizes: green also holds the 24 length prefixes of backward references, which are never used here
+const (
+	webpGreenAlphabet    = 256 + 24
+	webpLiteralAlphabet  = 256
+	webpDistanceAlphabet = 40
+	webpMaxCodeLength    = 15
+	webpMaxCodeLenLength = 7
+)
+
+// webpCodeLengthOrder is the order the lengths of the code length code are stored in
+var webpCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebP writes img as a lossless WebP (VP8L) image. It stores every pixel as literals with a prefix code
+// per channel and no transforms or backward references, a simple bitstream every WebP decoder reads.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > maxWebPSide || height > maxWebPSide {
+		return errors.New("image size is out of the WebP range")
+	}
+
+	// WebP stores straight alpha, unlike the premultiplied image.RGBA
+	pixels := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(pixels, pixels.Bounds(), img, bounds.Min, draw.Src)
+
+	green := make([]int, webpGreenAlphabet)
+	red := make([]int, webpLiteralAlphabet)
+	blue := make([]int, webpLiteralAlphabet)
+	alpha := make([]int, webpLiteralAlphabet)
+	alphaUsed := uint32(0)
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		red[pixels.Pix[i]]++
+		green[pixels.Pix[i+1]]++
+		blue[pixels.Pix[i+2]]++
+		alpha[pixels.Pix[i+3]]++
+		if pixels.Pix[i+3] != 0xff {
+			alphaUsed = 1
+		}
+	}
+
+	var bw webpBitWriter
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.write(alphaUsed, 1)
+	bw.write(0, 3) // version
+	bw.write(0, 1) // no transform
+	bw.write(0, 1) // no color cache
+	bw.write(0, 1) // a single group of prefix codes for the whole image
+
+	greenCode := bw.writePrefixCode(green)
+	redCode := bw.writePrefixCode(red)
+	blueCode := bw.writePrefixCode(blue)
+	alphaCode := bw.writePrefixCode(alpha)
+	bw.writePrefixCode(make([]int, webpDistanceAlphabet))
+
+	for i := 0; i < len(pixels.Pix); i += 4 {
+		greenCode.write(&bw, pixels.Pix[i+1])
+		redCode.write(&bw, pixels.Pix[i])
+		blueCode.write(&bw, pixels.Pix[i+2])
+		alphaCode.write(&bw, pixels.Pix[i+3])
+	}
+	data := bw.bytes()
+
+	padding := len(data) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padding == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// webpBitWriter packs bits least significant first, as VP8L reads them
+type webpBitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (bw *webpBitWriter) write(bits uint32, n uint) {
+	bw.acc |= uint64(bits) << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+func (bw *webpBitWriter) bytes() []byte {
+	if bw.nbits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf
+}
+
+// webpPrefixCode holds the bit-reversed canonical code and the length of every symbol
+type webpPrefixCode struct {
+	codes   []uint32
+	lengths []uint8
+}
+
+func (code webpPrefixCode) write(bw *webpBitWriter, symbol uint8) {
+	if n := code.lengths[symbol]; n > 0 {
+		bw.write(code.codes[symbol], uint(n))
+	}
+}
+
+// writePrefixCode stores the prefix code for a histogram and returns it. Up to two symbols below 256 are
+// stored as a simple code, a single symbol then takes no bits per pixel. Other codes store their code lengths.
+func (bw *webpBitWriter) writePrefixCode(histogram []int) webpPrefixCode {
+	var symbols []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		symbols = []int{0}
+	}
+
+	lengths := make([]uint8, len(histogram))
+	if len(symbols) <= 2 && symbols[len(symbols)-1] < 256 {
+		bw.write(1, 1)
+		bw.write(uint32(len(symbols)-1), 1)
+		if symbols[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbols[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbols[0]), 8)
+		}
+		if len(symbols) == 2 {
+			bw.write(uint32(symbols[1]), 8)
+			lengths[symbols[0]], lengths[symbols[1]] = 1, 1
+		}
+		return webpPrefixCode{codes: canonicalCodes(lengths), lengths: lengths}
+	}
+
+	lengths = limitedCodeLengths(histogram, webpMaxCodeLength)
+	bw.write(0, 1)
+	bw.writeCodeLengths(lengths)
+	return webpPrefixCode{codes: canonicalCodes(lengths), lengths: lengths}
+}
+
+// writeCodeLengths stores the code lengths of a normal prefix code, themselves prefix coded
+func (bw *webpBitWriter) writeCodeLengths(lengths []uint8) {
+	histogram := make([]int, len(webpCodeLengthOrder))
+	for _, length := range lengths {
+		histogram[length]++
+	}
+
+	codeLengths := limitedCodeLengths(histogram, webpMaxCodeLenLength)
+	used := 0
+	for _, length := range codeLengths {
+		if length > 0 {
+			used++
+		}
+	}
+	if used == 0 {
+		// a single code length value: it is stored with length 1 and decoders read it from zero bits
+		for value, count := range histogram {
+			if count > 0 {
+				codeLengths[value] = 1
+			}
+		}
+	}
+
+	stored := 4
+	for i, value := range webpCodeLengthOrder {
+		if codeLengths[value] > 0 {
+			stored = max(stored, i+1)
+		}
+	}
+	bw.write(uint32(stored-4), 4)
+	for _, value := range webpCodeLengthOrder[:stored] {
+		bw.write(uint32(codeLengths[value]), 3)
+	}
+	bw.write(0, 1) // the lengths of every symbol of the alphabet follow
+
+	if used == 0 {
+		return
+	}
+	code := webpPrefixCode{codes: canonicalCodes(codeLengths), lengths: codeLengths}
+	for _, length := range lengths {
+		code.write(bw, length)
+	}
+}
+
+// limitedCodeLengths builds Huffman code lengths of at most maxLength bits. A code that comes out too deep
+// is rebuilt from flattened counts, which keeps it complete. A lone symbol gets no length.
+func limitedCodeLengths(histogram []int, maxLength uint8) []uint8 {
+	counts := append([]int(nil), histogram...)
+	for {
+		lengths := huffmanCodeLengths(counts)
+		deepest := uint8(0)
+		for _, length := range lengths {
+			deepest = max(deepest, length)
+		}
+		if deepest <= maxLength {
+			return lengths
+		}
+		for i, count := range counts {
+			if count > 0 {
+				counts[i] = count/2 + 1
+			}
+		}
+	}
+}
+
+type huffmanNode struct {
+	count  int
+	symbol int
+	parent int
+}
+
+// huffmanHeap orders node indexes by count, ties by symbol so the result does not depend on heap internals
+type huffmanHeap struct {
+	nodes   []huffmanNode
+	indexes []int
+}
+
+func (h *huffmanHeap) Len() int { return len(h.indexes) }
+func (h *huffmanHeap) Less(i, j int) bool {
+	a, b := h.nodes[h.indexes[i]], h.nodes[h.indexes[j]]
+	if a.count != b.count {
+		return a.count < b.count
+	}
+	return a.symbol < b.symbol
+}
+func (h *huffmanHeap) Swap(i, j int) { h.indexes[i], h.indexes[j] = h.indexes[j], h.indexes[i] }
+func (h *huffmanHeap) Push(x any)    { h.indexes = append(h.indexes, x.(int)) }
+func (h *huffmanHeap) Pop() any {
+	last := h.indexes[len(h.indexes)-1]
+	h.indexes = h.indexes[:len(h.indexes)-1]
+	return last
+}
+
+// huffmanCodeLengths returns the depth of every symbol in a Huffman tree of the counts
+func huffmanCodeLengths(counts []int) []uint8 {
+	h := &huffmanHeap{}
+	for symbol, count := range counts {
+		if count > 0 {
+			h.nodes = append(h.nodes, huffmanNode{count: count, symbol: symbol, parent: -1})
+			h.indexes = append(h.indexes, len(h.nodes)-1)
+		}
+	}
+	lengths := make([]uint8, len(counts))
+	leaves := len(h.nodes)
+	if leaves < 2 {
+		return lengths
+	}
+
+	heap.Init(h)
+	for h.Len() > 1 {
+		a := heap.Pop(h).(int)
+		b := heap.Pop(h).(int)
+		h.nodes = append(h.nodes, huffmanNode{
+			count:  h.nodes[a].count + h.nodes[b].count,
+			symbol: len(counts) + len(h.nodes),
+			parent: -1,
+		})
+		h.nodes[a].parent = len(h.nodes) - 1
+		h.nodes[b].parent = len(h.nodes) - 1
+		heap.Push(h, len(h.nodes)-1)
+	}
+
+	for i := 0; i < leaves; i++ {
+		depth := uint8(0)
+		for node := i; h.nodes[node].parent >= 0; node = h.nodes[node].parent {
+			depth++
+		}
+		lengths[h.nodes[i].symbol] = depth
+	}
+	return lengths
+}
+
+// canonicalCodes assigns canonical prefix codes to code lengths, bit-reversed since codes are read first bit first
+func canonicalCodes(lengths []uint8) []uint32 {
+	var lengthCount [webpMaxCodeLength + 1]uint32
+	for _, length := range lengths {
+		if length > 0 {
+			lengthCount[length]++
+		}
+	}
+
+	var next [webpMaxCodeLength + 1]uint32
+	code := uint32(0)
+	for length := 1; length <= webpMaxCodeLength; length++ {
+		code = (code + lengthCount[length-1]) << 1
+		next[length] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		c := next[length]
+		next[length]++
+		reversed := uint32(0)
+		for i := uint8(0); i < length; i++ {
+			reversed = reversed<<1 | c&1
+			c >>= 1
+		}
+		codes[symbol] = reversed
+	}
+	return codes
+}
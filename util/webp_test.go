@@ -0,0 +1,102 @@
+package util
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	noise := image.NewNRGBA(image.Rect(0, 0, 67, 41))
+	rng := rand.New(rand.NewSource(1))
+	rng.Read(noise.Pix)
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 0xff})
+		}
+	}
+
+	twoColors := image.NewNRGBA(image.Rect(0, 0, 9, 9))
+	for i := range twoColors.Pix {
+		twoColors.Pix[i] = uint8(i%2) * 0xff
+	}
+
+	// a skewed histogram forces code lengths past the 15 bit limit before they are flattened
+	skewed := image.NewNRGBA(image.Rect(0, 0, 512, 512))
+	for i := 0; i < len(skewed.Pix); i += 4 {
+		n := i / 4
+		value := uint8(0)
+		for bit := 0; bit < 24 && n%(1<<bit) == 0; bit++ {
+			value = uint8(bit)
+		}
+		skewed.Pix[i], skewed.Pix[i+1], skewed.Pix[i+2], skewed.Pix[i+3] = value, value, value, 0xff
+	}
+
+	testCases := []struct {
+		name string
+		img  image.Image
+	}{
+		{"single pixel", image.NewUniform(color.NRGBA{R: 10, G: 20, B: 30, A: 40})},
+		{"solid", image.NewUniform(color.White)},
+		{"two colors", twoColors},
+		{"noise with alpha", noise},
+		{"gradient", gradient},
+		{"skewed histogram", skewed},
+		{"offset bounds", gradient.SubImage(image.Rect(10, 20, 110, 70))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := tc.img
+			if _, ok := img.(*image.Uniform); ok {
+				solid := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+				if tc.name == "solid" {
+					solid = image.NewNRGBA(image.Rect(0, 0, 33, 17))
+				}
+				for y := 0; y < solid.Rect.Dy(); y++ {
+					for x := 0; x < solid.Rect.Dx(); x++ {
+						solid.Set(x, y, img.At(x, y))
+					}
+				}
+				img = solid
+			}
+
+			var buf bytes.Buffer
+			if err := EncodeWebP(&buf, img); err != nil {
+				t.Fatalf("EncodeWebP() error = %v", err)
+			}
+
+			decoded, err := webp.Decode(&buf)
+			if err != nil {
+				t.Fatalf("webp.Decode() error = %v", err)
+			}
+
+			bounds := img.Bounds()
+			if decoded.Bounds().Dx() != bounds.Dx() || decoded.Bounds().Dy() != bounds.Dy() {
+				t.Fatalf("decoded size = %v, want %v", decoded.Bounds().Size(), bounds.Size())
+			}
+			for y := 0; y < bounds.Dy(); y++ {
+				for x := 0; x < bounds.Dx(); x++ {
+					want := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+					got := color.NRGBAModel.Convert(decoded.At(x, y))
+					if got != want {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeWebPRejectsEmptyImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeWebP(&buf, image.NewNRGBA(image.Rect(0, 0, 0, 0))); err == nil {
+		t.Fatal("EncodeWebP() error = nil, want an error for an empty image")
+	}
+}
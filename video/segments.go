@@ -0,0 +1,116 @@
+package video
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultCompletePercent is the share of a video that has to be watched to complete it when VIDEO_COMPLETE_PERCENT is not configured
+const DefaultCompletePercent = 90
+
+// MaxHeartbeatSeconds is the longest segment one heartbeat may report, players send a heartbeat every few seconds
+// so a longer segment means the student skipped ahead rather than watched
+const MaxHeartbeatSeconds = 120
+
+// MinHeartbeatInterval is the shortest time between two heartbeats of a student for the same video,
+// players send one every few seconds so faster heartbeats are refused
+const MinHeartbeatInterval = 2 * time.Second
+
+// MaxPlaybackRate is the fastest playback speed players offer, nobody watches more of a video than this
+// many times the real time that passed
+const MaxPlaybackRate = 2
+
+// HeartbeatGraceSeconds is how much more than the real time since the previous heartbeat a segment may cover,
+// it allows for network delay and for the first heartbeat, which has no previous one
+const HeartbeatGraceSeconds = 5
+
+// Segment is a stretch of a video a student played, in seconds from the start
+type Segment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// Length returns how many seconds the segment covers
+func (segment Segment) Length() float64 {
+	return math.Max(segment.End-segment.Start, 0)
+}
+
+// Clamp keeps a segment within a video of the given duration
+func (segment Segment) Clamp(duration float64) Segment {
+	return Segment{
+		Start: math.Max(segment.Start, 0),
+		End:   math.Min(segment.End, duration),
+	}
+}
+
+// Limit shortens a segment to what can have been played in the real time elapsed since the previous heartbeat
+func (segment Segment) Limit(elapsed time.Duration) Segment {
+	playable := math.Max(elapsed.Seconds(), 0)*MaxPlaybackRate + HeartbeatGraceSeconds
+	if segment.Length() > playable {
+		segment.End = segment.Start + playable
+	}
+	return segment
+}
+
+// Segments are the parts of a video a student watched, sorted by start and never overlapping
+type Segments []Segment
+
+// Add merges a segment into the watched segments. Overlapping and touching segments become one,
+// so playing the same part twice or scrubbing back and forth does not count twice.
+func (segments Segments) Add(segment Segment) Segments {
+	if segment.Length() == 0 {
+		return segments
+	}
+
+	merged := make(Segments, 0, len(segments)+1)
+	merged = append(merged, segments...)
+	merged = append(merged, segment)
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Start < merged[j].Start
+	})
+
+	result := merged[:1]
+	for _, next := range merged[1:] {
+		last := &result[len(result)-1]
+		if next.Start <= last.End {
+			last.End = math.Max(last.End, next.End)
+			continue
+		}
+		result = append(result, next)
+	}
+
+	return result
+}
+
+// Watched returns how many distinct seconds of the video were watched
+func (segments Segments) Watched() float64 {
+	var watched float64
+	for _, segment := range segments {
+		watched += segment.Length()
+	}
+	return watched
+}
+
+// Furthest returns the furthest point of the video the student reached
+func (segments Segments) Furthest() float64 {
+	if len(segments) == 0 {
+		return 0
+	}
+	return segments[len(segments)-1].End
+}
+
+// WatchedPercent returns the share of a video of the given duration that was watched as a whole percentage.
+// A video with an unknown duration counts as not watched.
+func WatchedPercent(watched, duration float64) int64 {
+	if duration <= 0 {
+		return 0
+	}
+	return int64(math.Min(math.Floor(watched*100/duration), 100))
+}
+
+// MinWatchTime returns the least real time it takes to watch percent of a video of the given duration
+func MinWatchTime(duration float64, percent int64) time.Duration {
+	seconds := duration * float64(percent) / 100 / MaxPlaybackRate
+	return time.Duration(seconds * float64(time.Second))
+}
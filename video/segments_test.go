@@ -0,0 +1,221 @@
+package video
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSegmentsAdd(t *testing.T) {
+	testCases := []struct {
+		name     string
+		segments Segments
+		add      Segment
+		want     Segments
+	}{
+		{
+			name: "First",
+			add:  Segment{Start: 10, End: 20},
+			want: Segments{{Start: 10, End: 20}},
+		},
+		{
+			name:     "Disjoint",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 30, End: 40},
+			want:     Segments{{Start: 10, End: 20}, {Start: 30, End: 40}},
+		},
+		{
+			name:     "DisjointBefore",
+			segments: Segments{{Start: 30, End: 40}},
+			add:      Segment{Start: 0, End: 5},
+			want:     Segments{{Start: 0, End: 5}, {Start: 30, End: 40}},
+		},
+		{
+			name:     "OverlapEnd",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 15, End: 25},
+			want:     Segments{{Start: 10, End: 25}},
+		},
+		{
+			name:     "OverlapStart",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 5, End: 12},
+			want:     Segments{{Start: 5, End: 20}},
+		},
+		{
+			name:     "Contained",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 12, End: 18},
+			want:     Segments{{Start: 10, End: 20}},
+		},
+		{
+			name:     "Replayed",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 10, End: 20},
+			want:     Segments{{Start: 10, End: 20}},
+		},
+		{
+			name:     "AdjacentAfter",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 20, End: 30},
+			want:     Segments{{Start: 10, End: 30}},
+		},
+		{
+			name:     "AdjacentBefore",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 0, End: 10},
+			want:     Segments{{Start: 0, End: 20}},
+		},
+		{
+			name:     "Bridges",
+			segments: Segments{{Start: 0, End: 10}, {Start: 20, End: 30}, {Start: 50, End: 60}},
+			add:      Segment{Start: 8, End: 22},
+			want:     Segments{{Start: 0, End: 30}, {Start: 50, End: 60}},
+		},
+		{
+			name:     "CoversSeveral",
+			segments: Segments{{Start: 5, End: 10}, {Start: 20, End: 30}},
+			add:      Segment{Start: 0, End: 40},
+			want:     Segments{{Start: 0, End: 40}},
+		},
+		{
+			name:     "Empty",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 25, End: 25},
+			want:     Segments{{Start: 10, End: 20}},
+		},
+		{
+			name:     "Backwards",
+			segments: Segments{{Start: 10, End: 20}},
+			add:      Segment{Start: 40, End: 30},
+			want:     Segments{{Start: 10, End: 20}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := append(Segments(nil), tc.segments...)
+			got := tc.segments.Add(tc.add)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Add(%v) = %v, want %v", tc.add, got, tc.want)
+			}
+			if !reflect.DeepEqual(tc.segments, before) {
+				t.Errorf("Add changed the segments it was called on: %v, was %v", tc.segments, before)
+			}
+		})
+	}
+}
+
+func TestSegmentClamp(t *testing.T) {
+	testCases := []struct {
+		name     string
+		segment  Segment
+		duration float64
+		want     Segment
+	}{
+		{name: "Inside", segment: Segment{Start: 10, End: 20}, duration: 60, want: Segment{Start: 10, End: 20}},
+		{name: "PastEnd", segment: Segment{Start: 50, End: 70}, duration: 60, want: Segment{Start: 50, End: 60}},
+		{name: "BeforeStart", segment: Segment{Start: -5, End: 10}, duration: 60, want: Segment{Start: 0, End: 10}},
+		{name: "Beyond", segment: Segment{Start: 70, End: 80}, duration: 60, want: Segment{Start: 70, End: 60}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.segment.Clamp(tc.duration)
+			if got != tc.want {
+				t.Errorf("Clamp(%v) = %v, want %v", tc.duration, got, tc.want)
+			}
+			// a segment past the end of the video adds nothing
+			if watched := (Segments{}).Add(got).Watched(); watched > tc.duration {
+				t.Errorf("clamped segment adds %v seconds to a %v second video", watched, tc.duration)
+			}
+		})
+	}
+}
+
+func TestSegmentLimit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		segment Segment
+		elapsed time.Duration
+		want    Segment
+	}{
+		{
+			name:    "WithinRealTime",
+			segment: Segment{Start: 10, End: 20},
+			elapsed: 10 * time.Second,
+			want:    Segment{Start: 10, End: 20},
+		},
+		{
+			name:    "AtMaxPlaybackRate",
+			segment: Segment{Start: 0, End: 25},
+			elapsed: 10 * time.Second,
+			want:    Segment{Start: 0, End: 25},
+		},
+		{
+			name:    "ClampedToPlaybackRate",
+			segment: Segment{Start: 0, End: 60},
+			elapsed: 10 * time.Second,
+			want:    Segment{Start: 0, End: 10*MaxPlaybackRate + HeartbeatGraceSeconds},
+		},
+		{
+			name:    "FirstHeartbeatGetsGrace",
+			segment: Segment{Start: 30, End: 90},
+			want:    Segment{Start: 30, End: 30 + HeartbeatGraceSeconds},
+		},
+		{
+			name:    "NegativeElapsed",
+			segment: Segment{Start: 0, End: 30},
+			elapsed: -time.Minute,
+			want:    Segment{Start: 0, End: HeartbeatGraceSeconds},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.segment.Limit(tc.elapsed); got != tc.want {
+				t.Errorf("Limit(%v) = %v, want %v", tc.elapsed, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSegmentsWatched(t *testing.T) {
+	segments := Segments{}.Add(Segment{Start: 0, End: 30}).Add(Segment{Start: 20, End: 50}).Add(Segment{Start: 80, End: 90})
+	if watched := segments.Watched(); watched != 60 {
+		t.Errorf("Watched = %v, want 60", watched)
+	}
+	if furthest := segments.Furthest(); furthest != 90 {
+		t.Errorf("Furthest = %v, want 90", furthest)
+	}
+	if furthest := (Segments{}).Furthest(); furthest != 0 {
+		t.Errorf("Furthest of nothing watched = %v, want 0", furthest)
+	}
+}
+
+func TestWatchedPercent(t *testing.T) {
+	testCases := []struct {
+		watched  float64
+		duration float64
+		want     int64
+	}{
+		{watched: 0, duration: 100, want: 0},
+		{watched: 89.9, duration: 100, want: 89},
+		{watched: 90, duration: 100, want: 90},
+		{watched: 150, duration: 100, want: 100},
+		{watched: 50, duration: 0, want: 0},
+		{watched: 50, duration: -10, want: 0},
+	}
+
+	for _, tc := range testCases {
+		if got := WatchedPercent(tc.watched, tc.duration); got != tc.want {
+			t.Errorf("WatchedPercent(%v, %v) = %d, want %d", tc.watched, tc.duration, got, tc.want)
+		}
+	}
+}
+
+func TestMinWatchTime(t *testing.T) {
+	if got, want := MinWatchTime(600, 90), 270*time.Second; got != want {
+		t.Errorf("MinWatchTime(600, 90) = %v, want %v", got, want)
+	}
+}
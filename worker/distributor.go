@@ -47,6 +47,51 @@ type TaskDistributor interface {
 		payload *PayloadCreateMaterials,
 		opts ...asynq.Option,
 	) error
+	DistributeTaskCollectOrphanFiles(
+		ctx context.Context,
+		payload *PayloadCollectOrphanFiles,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskCheckSubmissionSimilarity(
+		ctx context.Context,
+		payload *PayloadCheckSubmissionSimilarity,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskSendEnrollmentEmail(
+		ctx context.Context,
+		payload *PayloadSendEnrollmentEmail,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskProcessEnrollmentImport(
+		ctx context.Context,
+		payload *PayloadProcessEnrollmentImport,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskIssueCertificate(
+		ctx context.Context,
+		payload *PayloadIssueCertificate,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskIssueCredential(
+		ctx context.Context,
+		payload *PayloadIssueCredential,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskSendXAPIStatement(
+		ctx context.Context,
+		payload *PayloadSendXAPIStatement,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskPostLTIScore(
+		ctx context.Context,
+		payload *PayloadPostLTIScore,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskSendNotification(
+		ctx context.Context,
+		payload *PayloadSendNotification,
+		opts ...asynq.Option,
+	) error
 }
 
 type RedisTaskDistributor struct {
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/realtime"
+
+	"github.com/rs/zerolog/log"
+)
+
+// publishEvent pushes an event to the connections of a user. Nobody may be listening,
+// so a failure is only logged and never fails or retries the task.
+func (processor *RedisTaskProcessor) publishEvent(ctx context.Context, userID int64, eventType string, data any) {
+	err := processor.events.Publish(ctx, userID, eventType, data)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("event_type", eventType).Msg("failed to publish event")
+	}
+}
+
+// publishProgress tells students their course progress was recomputed
+func (processor *RedisTaskProcessor) publishProgress(ctx context.Context, progress ...db.CourseProgress) {
+	for _, courseProgress := range progress {
+		if courseProgress.UserID == 0 {
+			continue
+		}
+		processor.publishEvent(ctx, courseProgress.UserID, realtime.EventProgressChanged, realtime.ProgressChanged{
+			CourseID:  courseProgress.CourseID,
+			Progress:  courseProgress.Progress,
+			Completed: courseProgress.CompletedAt.Valid,
+		})
+	}
+}
+
+// publishTaskFinished tells the user who queued a task that it is done, so they stop polling for its result
+func (processor *RedisTaskProcessor) publishTaskFinished(ctx context.Context, userID int64, taskType string, id int64, status string) {
+	processor.publishEvent(ctx, userID, realtime.EventTaskFinished, realtime.TaskFinished{
+		Task:   taskType,
+		ID:     id,
+		Status: status,
+	})
+}
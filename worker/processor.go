@@ -4,6 +4,8 @@ import (
 	"context"
 
 	db "eduApp/db/sqlc"
+	"eduApp/realtime"
+	"eduApp/util"
 
 	"eduApp/mail"
 
@@ -28,15 +30,32 @@ type TaskProcessor interface {
 	ProcessTaskResetpassword(ctx context.Context, task *asynq.Task) error
 	ProcessTaskCreateLessonCompletion(ctx context.Context, task *asynq.Task) error
 	ProcessTaskCreateMaterial(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCollectOrphanFiles(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCheckSubmissionSimilarity(ctx context.Context, task *asynq.Task) error
+	ProcessTaskAdvancePeerReviews(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendEnrollmentEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskProcessEnrollmentImport(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExpireSubscriptions(ctx context.Context, task *asynq.Task) error
+	ProcessTaskIssueCertificate(ctx context.Context, task *asynq.Task) error
+	ProcessTaskIssueCredential(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendXAPIStatement(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPostLTIScore(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendNotification(ctx context.Context, task *asynq.Task) error
+	ProcessTaskRemindDueDates(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
 	server *asynq.Server
-	store  db.Store
-	mailer mail.EmailSender
+	// distributor lets a task enqueue follow-up tasks, such as a certificate once a course is completed
+	distributor TaskDistributor
+	config      util.Config
+	store       db.Store
+	mailer      mail.EmailSender
+	// events pushes what tasks changed to the open tabs of the users concerned
+	events realtime.Publisher
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
 	logger := NewLogger()
 	redis.SetLogger(logger)
 
@@ -56,9 +75,17 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer
 	)
 
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		mailer: mailer,
+		server:      server,
+		distributor: NewRedisTaskDistributor(redisOpt),
+		config:      config,
+		store:       store,
+		mailer:      mailer,
+		events: realtime.NewRedisBroker(&redis.Options{
+			Addr:     redisOpt.Addr,
+			Username: redisOpt.Username,
+			Password: redisOpt.Password,
+			DB:       redisOpt.DB,
+		}),
 	}
 }
 
@@ -73,6 +100,18 @@ func (processor *RedisTaskProcessor) Start() error {
 	mux.HandleFunc(TaskResetPassword, processor.ProcessTaskResetpassword)
 	mux.HandleFunc(TaskCreateLessonCompletion, processor.ProcessTaskCreateLessonCompletion)
 	mux.HandleFunc(TaskCreateMaterials, processor.ProcessTaskCreateMaterial)
+	mux.HandleFunc(TaskCollectOrphanFiles, processor.ProcessTaskCollectOrphanFiles)
+	mux.HandleFunc(TaskCheckSubmissionSimilarity, processor.ProcessTaskCheckSubmissionSimilarity)
+	mux.HandleFunc(TaskAdvancePeerReviews, processor.ProcessTaskAdvancePeerReviews)
+	mux.HandleFunc(TaskSendEnrollmentEmail, processor.ProcessTaskSendEnrollmentEmail)
+	mux.HandleFunc(TaskProcessEnrollmentImport, processor.ProcessTaskProcessEnrollmentImport)
+	mux.HandleFunc(TaskExpireSubscriptions, processor.ProcessTaskExpireSubscriptions)
+	mux.HandleFunc(TaskIssueCertificate, processor.ProcessTaskIssueCertificate)
+	mux.HandleFunc(TaskIssueCredential, processor.ProcessTaskIssueCredential)
+	mux.HandleFunc(TaskSendXAPIStatement, processor.ProcessTaskSendXAPIStatement)
+	mux.HandleFunc(TaskPostLTIScore, processor.ProcessTaskPostLTIScore)
+	mux.HandleFunc(TaskSendNotification, processor.ProcessTaskSendNotification)
+	mux.HandleFunc(TaskRemindDueDates, processor.ProcessTaskRemindDueDates)
 
 	return processor.server.Start(mux)
 }
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"eduApp/util"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultFileGCSchedule is the cron spec used when FILE_GC_SCHEDULE is not configured
+const DefaultFileGCSchedule = "@daily"
+
+type TaskScheduler interface {
+	Start() error
+	Shutdown()
+}
+
+// RedisTaskScheduler enqueues periodic maintenance tasks
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+	config    util.Config
+}
+
+func NewRedisTaskScheduler(config util.Config, redisOpt asynq.RedisClientOpt) TaskScheduler {
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
+		Logger: NewLogger(),
+	})
+
+	return &RedisTaskScheduler{
+		scheduler: scheduler,
+		config:    config,
+	}
+}
+
+func (scheduler *RedisTaskScheduler) Start() error {
+	fileGCSchedule := scheduler.config.FileGCSchedule
+	if fileGCSchedule == "" {
+		fileGCSchedule = DefaultFileGCSchedule
+	}
+	err := scheduler.register(fileGCSchedule, TaskCollectOrphanFiles, &PayloadCollectOrphanFiles{},
+		asynq.MaxRetry(3),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return err
+	}
+
+	peerReviewSchedule := scheduler.config.PeerReviewSchedule
+	if peerReviewSchedule == "" {
+		peerReviewSchedule = DefaultPeerReviewSchedule
+	}
+	err = scheduler.register(peerReviewSchedule, TaskAdvancePeerReviews, &PayloadAdvancePeerReviews{},
+		asynq.MaxRetry(3),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return err
+	}
+
+	expirySchedule := scheduler.config.ExpirySchedule
+	if expirySchedule == "" {
+		expirySchedule = DefaultExpirySchedule
+	}
+	err = scheduler.register(expirySchedule, TaskExpireSubscriptions, &PayloadExpireSubscriptions{},
+		asynq.MaxRetry(3),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return err
+	}
+
+	dueDateReminderSchedule := scheduler.config.DueDateSchedule
+	if dueDateReminderSchedule == "" {
+		dueDateReminderSchedule = DefaultDueDateReminderSchedule
+	}
+	err = scheduler.register(dueDateReminderSchedule, TaskRemindDueDates, &PayloadRemindDueDates{},
+		asynq.MaxRetry(3),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		return err
+	}
+
+	return scheduler.scheduler.Start()
+}
+
+func (scheduler *RedisTaskScheduler) Shutdown() {
+	scheduler.scheduler.Shutdown()
+}
+
+// register adds a periodic task to the scheduler
+func (scheduler *RedisTaskScheduler) register(cronspec string, taskType string, payload interface{}, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	entryID, err := scheduler.scheduler.Register(cronspec, asynq.NewTask(taskType, jsonPayload, opts...))
+	if err != nil {
+		return fmt.Errorf("failed to register periodic task %s: %w", taskType, err)
+	}
+
+	log.Info().Str("type", taskType).Str("cronspec", cronspec).Str("entry_id", entryID).Msg("registered periodic task")
+	return nil
+}
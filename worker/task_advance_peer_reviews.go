@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/notification"
+	"eduApp/peerreview"
+	"eduApp/typetext"
+	"eduApp/util"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskAdvancePeerReviews = "task:advance_peer_reviews"
+
+// DefaultPeerReviewSchedule is the cron spec used when PEER_REVIEW_SCHEDULE is not configured
+const DefaultPeerReviewSchedule = "@every 15m"
+
+// PeerReviewReminderLead is how long before the review deadline reviewers with pending reviews are reminded
+const PeerReviewReminderLead = 24 * time.Hour
+
+type PayloadAdvancePeerReviews struct{}
+
+// ProcessTaskAdvancePeerReviews moves every peer reviewed assignment to its next stage: reviewers are assigned once
+// no more submissions are accepted, reminded a day before the review deadline, and the peer scores become grades
+// when the deadline passes. Submissions the instructor graded before then keep the instructor's grade.
+func (processor *RedisTaskProcessor) ProcessTaskAdvancePeerReviews(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadAdvancePeerReviews
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	failed := 0
+
+	toAssign, err := processor.store.ListPeerReviewSettingsToAssign(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list peer reviews to assign: %w", err)
+	}
+	for _, settings := range toAssign {
+		if err := processor.assignPeerReviews(ctx, settings); err != nil {
+			log.Error().Err(err).Int64("assignment_id", settings.AssignmentID).Msg("failed to assign peer reviews")
+			failed++
+		}
+	}
+
+	toRemind, err := processor.store.ListPeerReviewSettingsToRemind(ctx, time.Now().Add(PeerReviewReminderLead))
+	if err != nil {
+		return fmt.Errorf("failed to list peer reviews to remind: %w", err)
+	}
+	for _, settings := range toRemind {
+		if err := processor.remindPeerReviewers(ctx, settings); err != nil {
+			log.Error().Err(err).Int64("assignment_id", settings.AssignmentID).Msg("failed to remind peer reviewers")
+			failed++
+		}
+	}
+
+	toFinalize, err := processor.store.ListPeerReviewSettingsToFinalize(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list peer reviews to finalize: %w", err)
+	}
+	for _, settings := range toFinalize {
+		if err := processor.finalizePeerReviews(ctx, settings); err != nil {
+			log.Error().Err(err).Int64("assignment_id", settings.AssignmentID).Msg("failed to finalize peer reviews")
+			failed++
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("assigned", len(toAssign)).Int("reminded", len(toRemind)).
+		Int("finalized", len(toFinalize)).Int("failed", failed).Msg("processed task")
+	if failed > 0 {
+		return fmt.Errorf("failed to advance %d peer reviewed assignments", failed)
+	}
+	return nil
+}
+
+// assignPeerReviews gives every submitted piece of work its reviewers, work submitted later is not reviewed
+func (processor *RedisTaskProcessor) assignPeerReviews(ctx context.Context, settings db.PeerReviewSetting) error {
+	submissions, err := processor.store.ListSubmittedSubmissionsByAssignment(ctx, settings.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to list submissions: %w", err)
+	}
+
+	authors := make([]peerreview.Submission, 0, len(submissions))
+	for _, submission := range submissions {
+		authors = append(authors, peerreview.Submission{
+			SubmissionID: submission.SubmissionID,
+			UserID:       submission.UserID,
+		})
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	pairs := peerreview.Assign(authors, int(settings.ReviewsPerSubmission), rng)
+
+	reviews := make([]db.CreatePeerReviewParams, 0, len(pairs))
+	for _, pair := range pairs {
+		reviews = append(reviews, db.CreatePeerReviewParams{
+			AssignmentID: settings.AssignmentID,
+			SubmissionID: pair.SubmissionID,
+			ReviewerID:   pair.ReviewerID,
+		})
+	}
+
+	result, err := processor.store.AssignPeerReviewsTx(ctx, db.AssignPeerReviewsTxParams{
+		AssignmentID: settings.AssignmentID,
+		Reviews:      reviews,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create peer reviews: %w", err)
+	}
+
+	log.Info().Int64("assignment_id", settings.AssignmentID).Int("submissions", len(submissions)).
+		Int("reviews", len(result.Reviews)).Bool("assigned", result.Assigned).Msg("assigned peer reviews")
+	return nil
+}
+
+// remindPeerReviewers notifies every reviewer who still has reviews to submit
+func (processor *RedisTaskProcessor) remindPeerReviewers(ctx context.Context, settings db.PeerReviewSetting) error {
+	assignment, err := processor.store.GetAssignment(ctx, settings.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get assignment: %w", err)
+	}
+
+	reviewers, err := processor.store.ListPendingPeerReviewers(ctx, settings.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to list pending reviewers: %w", err)
+	}
+
+	subject := fmt.Sprintf("Peer reviews due for %s", assignment.Title)
+	for _, reviewer := range reviewers {
+		message := fmt.Sprintf("You still have %d peer review(s) to submit for %s.\nReviews are due by %s.",
+			reviewer.Pending, assignment.Title, settings.ReviewDueAt.UTC().Format(time.RFC1123))
+
+		// a failed notification is logged and not resent, so the other reviewers are not reminded twice on a retry
+		err := processor.notify(ctx, notification.Notification{
+			UserID:   reviewer.UserID,
+			Type:     notification.TypeDueDate,
+			CourseID: assignment.CourseID,
+			Title:    subject,
+			Message:  message,
+			Key:      taskKey(ctx, "peer_review", strconv.FormatInt(settings.AssignmentID, 10)),
+		})
+		if err != nil {
+			log.Error().Err(err).Int64("assignment_id", settings.AssignmentID).
+				Str("email", reviewer.Email).Msg("failed to send peer review reminder")
+		}
+	}
+
+	if err := processor.store.SetPeerReviewsReminded(ctx, settings.AssignmentID); err != nil {
+		return fmt.Errorf("failed to mark reviewers reminded: %w", err)
+	}
+	return nil
+}
+
+// finalizePeerReviews grades every reviewed submission with its trimmed peer score less the late penalty
+func (processor *RedisTaskProcessor) finalizePeerReviews(ctx context.Context, settings db.PeerReviewSetting) error {
+	reviews, err := processor.store.ListPeerReviewsByAssignment(ctx, settings.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to list peer reviews: %w", err)
+	}
+
+	points := make(map[int64][]int64)
+	for _, review := range reviews {
+		if review.SubmittedAt.Valid {
+			points[review.SubmissionID] = append(points[review.SubmissionID], review.Points)
+		}
+	}
+
+	submissions, err := processor.store.ListSubmittedSubmissionsByAssignment(ctx, settings.AssignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to list submissions: %w", err)
+	}
+
+	graded := 0
+	for _, submission := range submissions {
+		if submission.GradedAt.Valid {
+			continue
+		}
+		score, ok := peerreview.Aggregate(points[submission.SubmissionID])
+		if !ok {
+			continue
+		}
+
+		grade := math.Round(util.ApplyLatePenalty(score.Points, submission.PenaltyPercent)*100) / 100
+		_, err := processor.store.GradeSubmission(ctx, db.GradeSubmissionParams{
+			SubmissionID: submission.SubmissionID,
+			Grade:        strconv.FormatFloat(grade, 'f', -1, 64),
+			RubricScores: typetext.RubricScores{},
+			Feedback:     "",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to grade submission %d: %w", submission.SubmissionID, err)
+		}
+		graded++
+	}
+
+	if err := processor.store.SetPeerReviewsFinalized(ctx, settings.AssignmentID); err != nil {
+		return fmt.Errorf("failed to mark peer reviews finalized: %w", err)
+	}
+
+	log.Info().Int64("assignment_id", settings.AssignmentID).Int("reviews", len(reviews)).
+		Int("graded", graded).Msg("finalized peer reviews")
+	return nil
+}
@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/similarity"
+	"eduApp/util"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskCheckSubmissionSimilarity = "task:check_submission_similarity"
+
+type PayloadCheckSubmissionSimilarity struct {
+	AttemptID int64 `json:"attempt_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskCheckSubmissionSimilarity(
+	ctx context.Context,
+	payload *PayloadCheckSubmissionSimilarity,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskCheckSubmissionSimilarity, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskCheckSubmissionSimilarity extracts the text of an uploaded attempt and compares it with the latest
+// attempt of every other student of the assignment. A report is stored in both directions for each pair that
+// shares a passage.
+func (processor *RedisTaskProcessor) ProcessTaskCheckSubmissionSimilarity(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadCheckSubmissionSimilarity
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	attempt, err := processor.store.GetSubmissionAttemptByID(ctx, payload.AttemptID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("submission attempt doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get submission attempt: %w", err)
+	}
+
+	submission, err := processor.store.GetSubmissionByID(ctx, attempt.SubmissionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("submission doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get submission: %w", err)
+	}
+
+	filePath, err := util.FilePathFromURL(attempt.Resource)
+	if err != nil {
+		return fmt.Errorf("failed to locate submission file: %w", asynq.SkipRetry)
+	}
+
+	text, err := similarity.ExtractText(filePath)
+	if err != nil {
+		if errors.Is(err, similarity.ErrUnsupportedFile) {
+			log.Info().Str("type", task.Type()).Int64("attempt_id", attempt.AttemptID).
+				Str("path", filePath).Msg("skipped similarity check")
+			return nil
+		}
+		// a file that cannot be read now will not be readable on a retry either
+		return fmt.Errorf("failed to extract text: %v: %w", err, asynq.SkipRetry)
+	}
+
+	_, err = processor.store.UpsertSubmissionText(ctx, db.UpsertSubmissionTextParams{
+		AttemptID:    attempt.AttemptID,
+		AssignmentID: submission.AssignmentID,
+		UserID:       submission.UserID,
+		Content:      text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store submission text: %w", err)
+	}
+
+	others, err := processor.store.ListLatestSubmissionTexts(ctx, db.ListLatestSubmissionTextsParams{
+		AssignmentID: submission.AssignmentID,
+		UserID:       submission.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list submission texts: %w", err)
+	}
+
+	doc := similarity.NewDocument(text)
+	reports := 0
+	for _, other := range others {
+		otherDoc := similarity.NewDocument(other.Content)
+
+		result := similarity.Compare(doc, otherDoc)
+		if len(result.Matches) == 0 {
+			continue
+		}
+		_, err = processor.store.UpsertSimilarityReport(ctx, db.UpsertSimilarityReportParams{
+			AssignmentID:     submission.AssignmentID,
+			AttemptID:        attempt.AttemptID,
+			UserID:           submission.UserID,
+			MatchedAttemptID: other.AttemptID,
+			MatchedUserID:    other.UserID,
+			Score:            result.Score,
+			Matches:          result.Matches,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store similarity report: %w", err)
+		}
+
+		reverse := similarity.Compare(otherDoc, doc)
+		_, err = processor.store.UpsertSimilarityReport(ctx, db.UpsertSimilarityReportParams{
+			AssignmentID:     submission.AssignmentID,
+			AttemptID:        other.AttemptID,
+			UserID:           other.UserID,
+			MatchedAttemptID: attempt.AttemptID,
+			MatchedUserID:    submission.UserID,
+			Score:            reverse.Score,
+			Matches:          reverse.Matches,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store similarity report: %w", err)
+		}
+		reports++
+	}
+
+	log.Info().Str("type", task.Type()).Int64("attempt_id", attempt.AttemptID).
+		Int("fingerprints", doc.Fingerprints()).Int("compared", len(others)).
+		Int("matched", reports).Msg("processed task")
+	return nil
+}
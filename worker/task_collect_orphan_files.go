@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/util"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskCollectOrphanFiles = "task:collect_orphan_files"
+
+// DefaultFileGCGracePeriod keeps freshly uploaded files that are not yet saved on a row
+const DefaultFileGCGracePeriod = 24 * time.Hour
+
+type PayloadCollectOrphanFiles struct {
+	DryRun bool `json:"dry_run"`
+}
+
+// OrphanFile is a file under the upload directory that no row refers to
+type OrphanFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// OrphanFilesReport summarises one reconciliation run of the upload directory
+type OrphanFilesReport struct {
+	DryRun         bool         `json:"dry_run"`
+	GracePeriod    string       `json:"grace_period"`
+	ScannedFiles   int          `json:"scanned_files"`
+	ReferencedURLs int          `json:"referenced_urls"`
+	ReferencedDirs int          `json:"referenced_dirs"`
+	Orphans        []OrphanFile `json:"orphans"`
+	OrphanBytes    int64        `json:"orphan_bytes"`
+	DeletedFiles   int          `json:"deleted_files"`
+}
+
+// CollectOrphanFiles compares the upload directory against every file column in the database.
+// Directories a row refers to as a whole, like extracted SCORM packages, are kept with everything below them.
+// Unreferenced files older than gracePeriod are reported, and removed unless dryRun is set.
+func CollectOrphanFiles(ctx context.Context, store db.Store, gracePeriod time.Duration, dryRun bool) (OrphanFilesReport, error) {
+	report := OrphanFilesReport{
+		DryRun:      dryRun,
+		GracePeriod: gracePeriod.String(),
+		Orphans:     []OrphanFile{},
+	}
+
+	fileURLs, err := store.ListReferencedFiles(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list referenced files: %w", err)
+	}
+	// every size and format of a course image or profile picture is a file of its own
+	variantURLs, err := store.ListReferencedImageVariants(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list referenced image variants: %w", err)
+	}
+	fileURLs = append(fileURLs, variantURLs...)
+	report.ReferencedURLs = len(fileURLs)
+
+	referenced := make(map[string]bool, len(fileURLs))
+	for _, fileURL := range fileURLs {
+		filePath, err := util.FilePathFromURL(fileURL)
+		if err != nil {
+			log.Warn().Err(err).Str("url", fileURL).Msg("skipping file url outside upload directory")
+			continue
+		}
+		referenced[filePath] = true
+	}
+
+	dirs, err := store.ListReferencedDirs(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list referenced directories: %w", err)
+	}
+	report.ReferencedDirs = len(dirs)
+
+	referencedDirs := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		dirPath := filepath.Clean(filepath.FromSlash(dir))
+		// a stored path must name a folder below the upload directory, never the directory itself
+		if !strings.HasPrefix(dirPath, util.UploadDir+string(filepath.Separator)) {
+			log.Warn().Str("dir", dir).Msg("skipping directory outside upload directory")
+			continue
+		}
+		referencedDirs[dirPath] = true
+	}
+
+	cutoff := time.Now().Add(-gracePeriod)
+	err = filepath.WalkDir(util.UploadDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if referencedDirs[filepath.Clean(path)] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		report.ScannedFiles++
+
+		if referenced[filepath.Clean(path)] {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(cutoff) {
+			return nil
+		}
+
+		report.Orphans = append(report.Orphans, OrphanFile{
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		report.OrphanBytes += info.Size()
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return report, fmt.Errorf("failed to scan upload directory: %w", err)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	for _, orphan := range report.Orphans {
+		if err := os.Remove(orphan.Path); err != nil && !os.IsNotExist(err) {
+			log.Error().Err(err).Str("path", orphan.Path).Msg("failed to delete orphan file")
+			continue
+		}
+		report.DeletedFiles++
+	}
+
+	return report, nil
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskCollectOrphanFiles(
+	ctx context.Context,
+	payload *PayloadCollectOrphanFiles,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskCollectOrphanFiles, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskCollectOrphanFiles(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadCollectOrphanFiles
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	gracePeriod := processor.config.FileGCGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultFileGCGracePeriod
+	}
+
+	report, err := CollectOrphanFiles(ctx, processor.store, gracePeriod, payload.DryRun || processor.config.FileGCDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to collect orphan files: %w", err)
+	}
+
+	for _, orphan := range report.Orphans {
+		log.Info().Str("path", orphan.Path).Int64("size", orphan.Size).
+			Time("mod_time", orphan.ModTime).Bool("dry_run", report.DryRun).Msg("orphan file")
+	}
+
+	log.Info().Str("type", task.Type()).Bool("dry_run", report.DryRun).
+		Int("scanned_files", report.ScannedFiles).Int("orphans", len(report.Orphans)).
+		Int64("orphan_bytes", report.OrphanBytes).Int("deleted_files", report.DeletedFiles).
+		Msg("processed task")
+	return nil
+}
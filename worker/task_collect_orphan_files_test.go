@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// referenceStore returns fixed file URLs and directories to the collector
+type referenceStore struct {
+	db.Store
+
+	files    []string
+	variants []string
+	dirs     []string
+}
+
+func (store *referenceStore) ListReferencedFiles(ctx context.Context) ([]string, error) {
+	return store.files, nil
+}
+
+func (store *referenceStore) ListReferencedImageVariants(ctx context.Context) ([]string, error) {
+	return store.variants, nil
+}
+
+func (store *referenceStore) ListReferencedDirs(ctx context.Context) ([]string, error) {
+	return store.dirs, nil
+}
+
+// inTempDir runs a test from an empty directory, the upload directory is relative to the working directory
+func inTempDir(t *testing.T) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func writeFile(t *testing.T, path string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollectOrphanFiles(t *testing.T) {
+	inTempDir(t)
+	old := time.Now().Add(-48 * time.Hour)
+
+	files := []string{
+		"uploads/materials/kept.pdf",
+		"uploads/materials/orphan.pdf",
+		"uploads/materials/fresh.pdf",
+		"uploads/course/intro-1-thumb.webp",
+		"uploads/course/intro-1-full.jpg",
+		"uploads/scorm/1-a/imsmanifest.xml",
+		"uploads/scorm/1-a/content/index.html",
+		"uploads/scorm/2-b/imsmanifest.xml",
+		"uploads/scorm/3-c/index.html",
+	}
+	for _, file := range files {
+		writeFile(t, filepath.FromSlash(file), old)
+	}
+	writeFile(t, filepath.FromSlash("uploads/materials/fresh.pdf"), time.Now())
+
+	store := &referenceStore{
+		files:    []string{"http://localhost:8080/static/materials/kept.pdf"},
+		variants: []string{"http://localhost:8080/static/course/intro-1-thumb.webp"},
+		// 2-b is a package whose row is gone, the other paths do not name a folder below the upload directory
+		dirs: []string{"uploads/scorm/1-a", "../uploads/scorm/3-c", "uploads"},
+	}
+
+	report, err := CollectOrphanFiles(context.Background(), store, 24*time.Hour, false)
+	if err != nil {
+		t.Fatalf("CollectOrphanFiles() error = %v", err)
+	}
+
+	var orphans []string
+	for _, orphan := range report.Orphans {
+		orphans = append(orphans, filepath.ToSlash(orphan.Path))
+	}
+	sort.Strings(orphans)
+	want := []string{"uploads/course/intro-1-full.jpg", "uploads/materials/orphan.pdf", "uploads/scorm/2-b/imsmanifest.xml", "uploads/scorm/3-c/index.html"}
+	if len(orphans) != len(want) {
+		t.Fatalf("orphans = %v, want %v", orphans, want)
+	}
+	for i := range want {
+		if orphans[i] != want[i] {
+			t.Fatalf("orphans = %v, want %v", orphans, want)
+		}
+	}
+	if report.DeletedFiles != len(want) || report.ReferencedDirs != 3 {
+		t.Errorf("report = %+v, want %d deleted files and 3 referenced dirs", report, len(want))
+	}
+
+	for _, file := range []string{"uploads/materials/kept.pdf", "uploads/course/intro-1-thumb.webp", "uploads/materials/fresh.pdf", "uploads/scorm/1-a/imsmanifest.xml", "uploads/scorm/1-a/content/index.html"} {
+		if _, err := os.Stat(filepath.FromSlash(file)); err != nil {
+			t.Errorf("%s was removed: %v", file, err)
+		}
+	}
+	for _, file := range want {
+		if _, err := os.Stat(filepath.FromSlash(file)); !os.IsNotExist(err) {
+			t.Errorf("%s was kept, want it removed", file)
+		}
+	}
+}
+
+func TestCollectOrphanFilesDryRun(t *testing.T) {
+	inTempDir(t)
+	writeFile(t, filepath.FromSlash("uploads/scorm/2-b/imsmanifest.xml"), time.Now().Add(-48*time.Hour))
+
+	report, err := CollectOrphanFiles(context.Background(), &referenceStore{}, 24*time.Hour, true)
+	if err != nil {
+		t.Fatalf("CollectOrphanFiles() error = %v", err)
+	}
+	if len(report.Orphans) != 1 || report.DeletedFiles != 0 {
+		t.Errorf("report = %+v, want one orphan and nothing deleted", report)
+	}
+	if _, err := os.Stat(filepath.FromSlash("uploads/scorm/2-b/imsmanifest.xml")); err != nil {
+		t.Errorf("dry run removed a file: %v", err)
+	}
+}
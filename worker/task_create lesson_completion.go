@@ -3,11 +3,12 @@ package worker
 import (
 	"context"
 	db "eduApp/db/sqlc"
+	"eduApp/realtime"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/hibiken/asynq"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/rs/zerolog/log"
 )
 
@@ -40,65 +41,34 @@ func (distributor *RedisTaskDistributor) DistributeTaskCreateLessonCompletion(
 	return nil
 }
 
+// ProcessTaskCreateLessonCompletion marks a lesson completed for a student, it is idempotent
 func (processor *RedisTaskProcessor) ProcessTaskCreateLessonCompletion(ctx context.Context, task *asynq.Task) error {
 	var payload PayloadCreateLessonCompletion
 	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
 	}
 
-	params := db.CreateLessonCompletionParams{
-		UserID:     payload.UserID,
-		CourseID:   payload.CourseID,
-		MaterialID: payload.MaterialID,
-		Completed:  true,
-	}
-	_, err := processor.store.CreateLessonCompletion(ctx, params)
+	result, err := processor.store.CreateLessonCompletionTx(ctx, db.CreateLessonCompletionTxParams{
+		CreateLessonCompletionParams: db.CreateLessonCompletionParams{
+			UserID:     payload.UserID,
+			CourseID:   payload.CourseID,
+			MaterialID: payload.MaterialID,
+			Completed:  true,
+		},
+		AfterComplete: processor.awardCourseCompletion(ctx),
+	})
 	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("material doesn't exist in the course: %w", asynq.SkipRetry)
+		}
 		log.Error().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("failed to create lesson Completion")
 		return fmt.Errorf("failed to create lesson Completion: %w", err)
 	}
 
-	count1, err := processor.store.GetTotalMaterialsInCourse(ctx, payload.CourseID)
-
-	if err != nil {
-		log.Error().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("failed to get number of materials in a course ")
-		return fmt.Errorf("failed to get number of materials in a course : %w", err)
-	}
-
-	// Ensure count1 is not 0 to avoid division by zero
-	if count1 == 0 {
-		return fmt.Errorf("total materials count is 0")
-	}
-
-	count2, err := processor.store.GetCompletedLessonsCount(ctx, db.GetCompletedLessonsCountParams{
-		CourseID: payload.CourseID,
-		UserID:   payload.UserID,
-	})
-	if err != nil {
-		log.Error().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("failed to get number of lessons in a course")
-		return fmt.Errorf("failed to get number of lessons in a course: %w", err)
-	}
-
-	// Calculate progress as a float64
-	progress := float64(count2) / float64(count1) * 100
-	println("count2", count2)
-	println("count1", count1)
-
-	// Ensure progress is within the expected range
-	if progress < 0 || progress > 100 {
-		return fmt.Errorf("invalid progress value: %f", progress)
-	}
-
-	_, err = processor.store.UpdateCourseProgress(ctx, db.UpdateCourseProgressParams{
-		CourseID: pgtype.Int8{Int64: payload.CourseID, Valid: true},
-		UserID:   payload.UserID,
-		Progress: pgtype.Int8{Int64: int64(progress), Valid: true},
-	})
+	processor.publishProgress(ctx, result.CourseProgress)
+	processor.publishTaskFinished(ctx, payload.UserID, task.Type(), payload.MaterialID, realtime.TaskDone)
 
-	if err != nil {
-		return fmt.Errorf("failed to update course progress: %w", err)
-	}
-
-	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("processed task successfully")
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Int64("progress", result.CourseProgress.Progress).Msg("processed task successfully")
 	return nil
 }
@@ -44,20 +44,10 @@ func (processor *RedisTaskProcessor) ProcessTaskCreateSubscription(ctx context.C
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
 	}
 
-	params := db.CreateSubscriptionParams{
-		UserID:   payload.UserID,
+	// the subscription itself is created by the request, the task only starts the student's progress
+	_, err := processor.store.CreateCourseProgress(ctx, db.CreateCourseProgressParams{
 		CourseID: payload.CourseID,
-	}
-
-	subscription, err := processor.store.CreateSubscription(ctx, params)
-	if err != nil {
-		log.Error().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("failed to create subscription")
-		return fmt.Errorf("failed to create subscription: %w", err)
-	}
-
-	_, err = processor.store.CreateCourseProgress(ctx, db.CreateCourseProgressParams{
-		CourseID: subscription.CourseID,
-		UserID:   params.UserID,
+		UserID:   payload.UserID,
 		Progress: 0,
 	})
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	db "eduApp/db/sqlc"
+	"eduApp/realtime"
 	"eduApp/token"
 	"encoding/json"
 	"fmt"
@@ -111,6 +112,8 @@ func (processor *RedisTaskProcessor) ProcessTaskDeleteCourse(ctx context.Context
 			return fmt.Errorf("failed to send email: %w", err)
 		}
 
+		processor.publishTaskFinished(ctx, AuthPayload.UserID, task.Type(), payload.CourseID, realtime.TaskRequested)
+
 		log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
 			Str("email", email).Msg("processed_task")
 
@@ -123,5 +126,6 @@ func (processor *RedisTaskProcessor) ProcessTaskDeleteCourse(ctx context.Context
 		return fmt.Errorf("failed to delete course: %w", err)
 	}
 
+	processor.publishTaskFinished(ctx, AuthPayload.UserID, task.Type(), payload.CourseID, realtime.TaskDone)
 	return nil
 }
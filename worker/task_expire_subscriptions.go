@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExpireSubscriptions = "task:expire_subscriptions"
+
+// DefaultExpirySchedule is the cron spec used when SUBSCRIPTION_EXPIRY_SCHEDULE is not configured
+const DefaultExpirySchedule = "@hourly"
+
+// DefaultExpiryReminderDays is how many days before expiry students are reminded when SUBSCRIPTION_REMINDER_DAYS is not configured
+const DefaultExpiryReminderDays = 7
+
+type PayloadExpireSubscriptions struct{}
+
+// ProcessTaskExpireSubscriptions deactivates the subscriptions whose access ended and gives their seats to the waitlist,
+// then reminds the students whose access ends within the reminder period that it is time to renew.
+func (processor *RedisTaskProcessor) ProcessTaskExpireSubscriptions(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExpireSubscriptions
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	expired, err := processor.store.ExpireSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to expire subscriptions: %w", err)
+	}
+
+	// the subscriptions are expired already, a failed notification is logged and not resent
+	courses := make(map[int64]bool)
+	for _, subscription := range expired {
+		processor.notifyEnrollment(ctx, subscription, EnrollmentEventExpired)
+		courses[subscription.CourseID] = true
+	}
+
+	failed := 0
+	promoted := 0
+	for courseID := range courses {
+		result, err := processor.store.PromoteWaitlistTx(ctx, courseID)
+		if err != nil {
+			log.Error().Err(err).Int64("course_id", courseID).Msg("failed to promote waitlist")
+			failed++
+			continue
+		}
+		for _, subscription := range result.Promoted {
+			processor.notifyEnrollment(ctx, subscription, EnrollmentEventPromoted)
+			processor.distributeCourseLaunched(ctx, subscription)
+		}
+		promoted += len(result.Promoted)
+	}
+
+	reminderDays := processor.config.ExpiryReminderDays
+	if reminderDays <= 0 {
+		reminderDays = DefaultExpiryReminderDays
+	}
+	remindBefore := time.Now().AddDate(0, 0, reminderDays)
+
+	toRemind, err := processor.store.ListSubscriptionsToRemind(ctx, pgtype.Timestamptz{Time: remindBefore, Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions to remind: %w", err)
+	}
+	reminded := 0
+	for _, subscription := range toRemind {
+		if err := processor.remindExpiry(ctx, subscription); err != nil {
+			log.Error().Err(err).Int64("subscription_id", subscription.SubscriptionID).Msg("failed to send expiry reminder")
+			failed++
+			continue
+		}
+		reminded++
+	}
+
+	log.Info().Str("type", task.Type()).Int("expired", len(expired)).Int("promoted", promoted).
+		Int("reminded", reminded).Int("failed", failed).Msg("processed task")
+	if failed > 0 {
+		return fmt.Errorf("failed to process %d subscriptions", failed)
+	}
+	return nil
+}
+
+// remindExpiry emails a student that their access ends soon, unless they turned off enrollment emails.
+// A student is only marked reminded once the email is sent or turned off, so the next run does not ask again.
+func (processor *RedisTaskProcessor) remindExpiry(ctx context.Context, subscription db.ListSubscriptionsToRemindRow) error {
+	preference, err := processor.preference(ctx, subscription.UserID, notification.TypeEnrollment)
+	if err != nil {
+		return err
+	}
+
+	if preference.Email {
+		message, err := mail.Render(mail.TemplateExpiryReminder, subscription.Locale, mail.ExpiryReminderData{
+			FirstName:   subscription.FirstName,
+			CourseTitle: subscription.Title,
+			ExpiresAt:   subscription.ExpiresAt.Time,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render expiry reminder: %w", err)
+		}
+
+		err = processor.mailer.SendMessage(message, []string{subscription.Email}, nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+	}
+
+	if err := processor.store.SetSubscriptionExpiryReminded(ctx, subscription.SubscriptionID); err != nil {
+		return fmt.Errorf("failed to mark subscription reminded: %w", err)
+	}
+	return nil
+}
+
+// notifyEnrollment notifies a student about an enrollment event from within a task, failures are only logged
+func (processor *RedisTaskProcessor) notifyEnrollment(ctx context.Context, subscription db.Subscription, event string) {
+	course, err := processor.store.GetCourses(ctx, subscription.CourseID)
+	if err != nil {
+		log.Error().Err(err).Int64("subscription_id", subscription.SubscriptionID).Msg("failed to get course")
+		return
+	}
+
+	key := taskKey(ctx, event, strconv.FormatInt(subscription.SubscriptionID, 10))
+	if err := processor.notifyEnrollmentEvent(ctx, subscription.UserID, course, event, key); err != nil {
+		log.Error().Err(err).Int64("subscription_id", subscription.SubscriptionID).
+			Str("event", event).Msg("failed to send enrollment notification")
+	}
+}
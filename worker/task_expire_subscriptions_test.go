@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// sentMail is a message handed to the mailer
+type sentMail struct {
+	message     mail.Message
+	to          []string
+	attachFiles []string
+}
+
+// recordingMailer keeps the messages it is asked to send, or fails to send them
+type recordingMailer struct {
+	mail.EmailSender
+
+	err  error
+	sent []sentMail
+}
+
+func (mailer *recordingMailer) SendMessage(message mail.Message, to []string, cc []string, bcc []string, attachFiles []string) error {
+	if mailer.err != nil {
+		return mailer.err
+	}
+	mailer.sent = append(mailer.sent, sentMail{message: message, to: to, attachFiles: attachFiles})
+	return nil
+}
+
+// preferenceStore holds the email preferences a user set per notification type, the other types use the default
+type preferenceStore struct {
+	db.Store
+
+	emails map[string]bool
+}
+
+func (store *preferenceStore) GetNotificationPreference(ctx context.Context, arg db.GetNotificationPreferenceParams) (db.NotificationPreference, error) {
+	email, ok := store.emails[arg.Type]
+	if !ok {
+		return db.NotificationPreference{}, db.ErrRecordNotFound
+	}
+	return db.NotificationPreference{UserID: arg.UserID, Type: arg.Type, InApp: true, Email: email}, nil
+}
+
+// reminderStore records the subscriptions marked reminded
+type reminderStore struct {
+	preferenceStore
+
+	reminded []int64
+}
+
+func (store *reminderStore) SetSubscriptionExpiryReminded(ctx context.Context, subscriptionID int64) error {
+	store.reminded = append(store.reminded, subscriptionID)
+	return nil
+}
+
+func TestRemindExpiry(t *testing.T) {
+	subscription := db.ListSubscriptionsToRemindRow{
+		SubscriptionID: 4,
+		UserID:         7,
+		CourseID:       3,
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Date(2024, time.March, 15, 9, 30, 0, 0, time.UTC), Valid: true},
+		Email:          "ada@example.com",
+		FirstName:      "Ada",
+		Locale:         "fr",
+		Title:          "<b>Algorithms</b> & Data",
+	}
+
+	testCases := []struct {
+		name     string
+		emails   map[string]bool
+		mailErr  error
+		sent     bool
+		reminded bool
+	}{
+		{name: "Default", sent: true, reminded: true},
+		{name: "EnrollmentEmailsOn", emails: map[string]bool{notification.TypeEnrollment: true}, sent: true, reminded: true},
+		{name: "EnrollmentEmailsOff", emails: map[string]bool{notification.TypeEnrollment: false}, reminded: true},
+		{name: "OtherTypeOff", emails: map[string]bool{notification.TypeGrade: false}, sent: true, reminded: true},
+		{name: "SendFails", mailErr: errors.New("smtp is down")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &reminderStore{preferenceStore: preferenceStore{emails: tc.emails}}
+			mailer := &recordingMailer{err: tc.mailErr}
+			processor := &RedisTaskProcessor{store: store, mailer: mailer}
+
+			err := processor.remindExpiry(context.Background(), subscription)
+			if (err != nil) != (tc.mailErr != nil) {
+				t.Fatalf("remindExpiry() error = %v, want %v", err, tc.mailErr)
+			}
+			if reminded := len(store.reminded) == 1; reminded != tc.reminded {
+				t.Errorf("reminded = %v, want %v", reminded, tc.reminded)
+			}
+			if sent := len(mailer.sent) == 1; sent != tc.sent {
+				t.Fatalf("sent = %d emails, want sent %v", len(mailer.sent), tc.sent)
+			}
+			if !tc.sent {
+				return
+			}
+
+			want, err := mail.Render(mail.TemplateExpiryReminder, "fr", mail.ExpiryReminderData{
+				FirstName:   "Ada",
+				CourseTitle: subscription.Title,
+				ExpiresAt:   subscription.ExpiresAt.Time,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sent := mailer.sent[0]
+			if sent.message != want || len(sent.to) != 1 || sent.to[0] != subscription.Email {
+				t.Errorf("sent %+v to %v, want the French expiry reminder to %s", sent.message, sent.to, subscription.Email)
+			}
+			if strings.Contains(sent.message.HTML, "<b>Algorithms</b>") {
+				t.Errorf("HTML does not escape the course title:\n%s", sent.message.HTML)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package worker
+
+import (
+	"context"
+	"eduApp/certificate"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"eduApp/util"
+	"eduApp/xapi"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskIssueCertificate = "task:issue_certificate"
+
+// certificateDir is where certificate PDFs are stored below the upload directory
+var certificateDir = filepath.Join(util.UploadDir, "certificates")
+
+type PayloadIssueCertificate struct {
+	UserID   int64 `json:"user_id"`
+	CourseID int64 `json:"course_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskIssueCertificate(
+	ctx context.Context,
+	payload *PayloadIssueCertificate,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskIssueCertificate, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskIssueCertificate renders the completion certificate of a student who finished a course and emails it to them.
+// A student gets one certificate per course, a retry reuses the stored certificate and only resends the email.
+func (processor *RedisTaskProcessor) ProcessTaskIssueCertificate(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadIssueCertificate
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	cert, err := processor.store.GetCourseCertificate(ctx, db.GetCourseCertificateParams{
+		UserID:   payload.UserID,
+		CourseID: payload.CourseID,
+	})
+	if err != nil {
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get certificate: %w", err)
+		}
+		cert, err = processor.createCertificate(ctx, user, payload.CourseID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cert.EmailedAt.Valid {
+		log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+			Str("code", cert.Code).Msg("certificate already issued")
+		return nil
+	}
+
+	// certificates are emailed like grades, a student who turned grade emails off downloads theirs in the app
+	preference, err := processor.preference(ctx, user.UserID, notification.TypeGrade)
+	if err != nil {
+		return err
+	}
+	if !preference.Email {
+		log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+			Str("code", cert.Code).Msg("certificate issued, email turned off")
+		return nil
+	}
+
+	message, err := mail.Render(mail.TemplateCertificate, user.Locale, mail.CertificateData{
+		FirstName:   user.FirstName,
+		CourseTitle: cert.CourseTitle,
+		VerifyURL:   processor.certificateVerifyURL(cert.Code),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render certificate email: %w", err)
+	}
+
+	filePath, err := util.FilePathFromURL(cert.File)
+	if err != nil {
+		return fmt.Errorf("invalid certificate file: %w", err)
+	}
+	err = processor.mailer.SendMessage(message, []string{user.Email}, nil, nil, []string{filePath})
+	if err != nil {
+		return fmt.Errorf("failed to send certificate email: %w", err)
+	}
+
+	if _, err := processor.store.SetCertificateEmailed(ctx, cert.CertificateID); err != nil {
+		return fmt.Errorf("failed to mark certificate emailed: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("code", cert.Code).Str("email", user.Email).Msg("processed task")
+	return nil
+}
+
+// createCertificate checks the student completed every lesson of the course, renders their certificate
+// and stores it. The names are copied into the certificate so it still verifies after a course is renamed.
+func (processor *RedisTaskProcessor) createCertificate(ctx context.Context, user db.User, courseID int64) (db.Certificate, error) {
+	course, err := processor.store.GetCourses(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return db.Certificate{}, fmt.Errorf("course doesn't exist: %w", asynq.SkipRetry)
+		}
+		return db.Certificate{}, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	if err := processor.checkCourseCompleted(ctx, user.UserID, courseID); err != nil {
+		return db.Certificate{}, err
+	}
+
+	instructorName := ""
+	instructor, err := processor.store.GetUserByID(ctx, course.UserID)
+	if err == nil {
+		instructorName = fullName(instructor)
+	} else if !errors.Is(err, db.ErrRecordNotFound) {
+		return db.Certificate{}, fmt.Errorf("failed to get instructor: %w", err)
+	}
+
+	tmpl, err := certificate.LoadTemplate(processor.config.CertificateTemplate)
+	if err != nil {
+		return db.Certificate{}, err
+	}
+
+	code, err := certificate.NewCode()
+	if err != nil {
+		return db.Certificate{}, fmt.Errorf("failed to generate certificate code: %w", err)
+	}
+
+	data := certificate.Data{
+		StudentName: fullName(user),
+		CourseTitle: course.Title,
+		Instructor:  instructorName,
+		IssuedAt:    time.Now().UTC(),
+		Code:        code,
+		VerifyURL:   processor.certificateVerifyURL(code),
+	}
+
+	if err := os.MkdirAll(certificateDir, 0755); err != nil {
+		return db.Certificate{}, err
+	}
+	filePath := filepath.Join(certificateDir, strings.ToLower(code)+".pdf")
+	out, err := os.Create(filePath)
+	if err != nil {
+		return db.Certificate{}, err
+	}
+	defer out.Close()
+
+	if err := tmpl.Render(out, data); err != nil {
+		os.Remove(filePath)
+		return db.Certificate{}, err
+	}
+
+	cert, err := processor.store.CreateCertificate(ctx, db.CreateCertificateParams{
+		Code:           code,
+		UserID:         user.UserID,
+		CourseID:       course.CourseID,
+		StudentName:    data.StudentName,
+		CourseTitle:    data.CourseTitle,
+		InstructorName: data.Instructor,
+		File:           util.StaticFileURL(processor.config.FileSource, filePath),
+		IssuedAt:       data.IssuedAt,
+	})
+	if err != nil {
+		// the orphan file collector cleans up the PDF of a certificate that was not stored
+		return db.Certificate{}, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// certificateVerifyURL is the public page where a certificate code can be checked
+func (processor *RedisTaskProcessor) certificateVerifyURL(code string) string {
+	baseURL := strings.TrimSpace(processor.config.CertificateVerifyURL)
+	if baseURL == "" {
+		baseURL = strings.TrimSpace(processor.config.FileSource) + "/certificates/verify"
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + code
+}
+
+// fullName is how a user is named on a certificate
+func fullName(user db.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+// checkCourseCompleted makes sure the student completed every lesson of the course before they are awarded for it
+func (processor *RedisTaskProcessor) checkCourseCompleted(ctx context.Context, userID, courseID int64) error {
+	total, err := processor.store.GetTotalMaterialsInCourse(ctx, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to get number of materials in a course: %w", err)
+	}
+	completed, err := processor.store.GetCompletedLessonsCount(ctx, db.GetCompletedLessonsCountParams{
+		CourseID: courseID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get number of lessons in a course: %w", err)
+	}
+	if total == 0 || completed < total {
+		return fmt.Errorf("course is not completed: %w", asynq.SkipRetry)
+	}
+	return nil
+}
+
+// DistributeCourseCompletion enqueues the certificate, the verifiable credential and the xAPI passed statement
+// of a student who completed a course
+func DistributeCourseCompletion(ctx context.Context, distributor TaskDistributor, activities xapi.Activities, courseProgress db.CourseProgress) error {
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(QueueDefault),
+	}
+
+	err := distributor.DistributeTaskIssueCertificate(ctx, &PayloadIssueCertificate{
+		UserID:   courseProgress.UserID,
+		CourseID: courseProgress.CourseID,
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	err = distributor.DistributeTaskIssueCredential(ctx, &PayloadIssueCredential{
+		UserID:   courseProgress.UserID,
+		CourseID: courseProgress.CourseID,
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	passed := true
+	statement := xapi.NewStatement(
+		activities.Agent(courseProgress.UserID, ""),
+		xapi.VerbPassed,
+		activities.Course(courseProgress.CourseID, ""),
+	).WithCompletion(true, &passed)
+	return DistributeXAPIStatement(ctx, distributor, statement)
+}
+
+// awardCourseCompletion is the AfterComplete hook of the progress transactions run by tasks
+func (processor *RedisTaskProcessor) awardCourseCompletion(ctx context.Context) func(db.CourseProgress) error {
+	return func(courseProgress db.CourseProgress) error {
+		return DistributeCourseCompletion(ctx, processor.distributor, XAPIActivities(processor.config), courseProgress)
+	}
+}
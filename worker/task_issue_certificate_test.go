@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"eduApp/util"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hibiken/asynq"
+)
+
+// certificateStore holds the stored, not yet emailed certificate of user 7 for course 3
+type certificateStore struct {
+	preferenceStore
+
+	emailed []int64
+}
+
+func (store *certificateStore) GetUserByID(ctx context.Context, userID int64) (db.User, error) {
+	return db.User{UserID: userID, FirstName: "Ada", Email: "ada@example.com", Locale: "en"}, nil
+}
+
+func (store *certificateStore) GetCourseCertificate(ctx context.Context, arg db.GetCourseCertificateParams) (db.Certificate, error) {
+	return db.Certificate{
+		CertificateID: 11,
+		Code:          "7QH3-K2MF",
+		UserID:        arg.UserID,
+		CourseID:      arg.CourseID,
+		CourseTitle:   "Algorithms",
+		File:          "http://localhost:3390/static/certificates/7QH3-K2MF.pdf",
+	}, nil
+}
+
+func (store *certificateStore) SetCertificateEmailed(ctx context.Context, certificateID int64) (db.Certificate, error) {
+	store.emailed = append(store.emailed, certificateID)
+	return db.Certificate{CertificateID: certificateID}, nil
+}
+
+func TestIssueCertificateEmail(t *testing.T) {
+	payload, err := json.Marshal(PayloadIssueCertificate{UserID: 7, CourseID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := util.Config{CertificateVerifyURL: "https://eduapp.example/certificates/verify"}
+
+	testCases := []struct {
+		name    string
+		emails  map[string]bool
+		mailErr error
+		sent    bool
+	}{
+		{name: "Default", sent: true},
+		{name: "GradeEmailsOff", emails: map[string]bool{notification.TypeGrade: false}},
+		{name: "OtherTypeOff", emails: map[string]bool{notification.TypeEnrollment: false}, sent: true},
+		{name: "SendFails", mailErr: errors.New("smtp is down")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &certificateStore{preferenceStore: preferenceStore{emails: tc.emails}}
+			mailer := &recordingMailer{err: tc.mailErr}
+			processor := &RedisTaskProcessor{config: config, store: store, mailer: mailer}
+
+			err := processor.ProcessTaskIssueCertificate(context.Background(), asynq.NewTask(TaskIssueCertificate, payload))
+			if (err != nil) != (tc.mailErr != nil) {
+				t.Fatalf("ProcessTaskIssueCertificate() error = %v, want %v", err, tc.mailErr)
+			}
+			if emailed := len(store.emailed) == 1; emailed != tc.sent {
+				t.Errorf("marked emailed = %v, want %v", emailed, tc.sent)
+			}
+			if sent := len(mailer.sent) == 1; sent != tc.sent {
+				t.Fatalf("sent = %d emails, want sent %v", len(mailer.sent), tc.sent)
+			}
+			if !tc.sent {
+				return
+			}
+
+			want, err := mail.Render(mail.TemplateCertificate, "en", mail.CertificateData{
+				FirstName:   "Ada",
+				CourseTitle: "Algorithms",
+				VerifyURL:   "https://eduapp.example/certificates/verify/7QH3-K2MF",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sent := mailer.sent[0]
+			if sent.message != want {
+				t.Errorf("sent %+v, want the certificate email %+v", sent.message, want)
+			}
+			attachment := filepath.Join(util.UploadDir, "certificates", "7QH3-K2MF.pdf")
+			if len(sent.attachFiles) != 1 || sent.attachFiles[0] != attachment {
+				t.Errorf("attachments = %v, want %s", sent.attachFiles, attachment)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"eduApp/credential"
+	db "eduApp/db/sqlc"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskIssueCredential = "task:issue_credential"
+
+type PayloadIssueCredential struct {
+	UserID   int64 `json:"user_id"`
+	CourseID int64 `json:"course_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskIssueCredential(
+	ctx context.Context,
+	payload *PayloadIssueCredential,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskIssueCredential, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskIssueCredential signs an Open Badges credential for a student who completed a course.
+// Nothing is issued when no signing key is configured, and a student gets one credential per course.
+func (processor *RedisTaskProcessor) ProcessTaskIssueCredential(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadIssueCredential
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	issuer, err := credential.NewIssuer(processor.config.BadgeBaseURL, processor.config.BadgeIssuerName, processor.config.BadgeSigningKey)
+	if err != nil {
+		if errors.Is(err, credential.ErrNotConfigured) {
+			log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("credentials are not configured, skipped")
+			return nil
+		}
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	_, err = processor.store.GetCourseCredential(ctx, db.GetCourseCredentialParams{
+		UserID:   payload.UserID,
+		CourseID: payload.CourseID,
+	})
+	if err == nil {
+		log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("credential already issued")
+		return nil
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get credential: %w", err)
+	}
+
+	user, err := processor.store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	course, err := processor.store.GetCourses(ctx, payload.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("course doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get course: %w", err)
+	}
+
+	if err := processor.checkCourseCompleted(ctx, payload.UserID, payload.CourseID); err != nil {
+		return err
+	}
+
+	issuedAt := time.Now().UTC()
+	signed, err := issuer.Issue(user.Email, issuer.Achievement(course.CourseID, course.Title, course.Description), issuedAt)
+	if err != nil {
+		return fmt.Errorf("failed to issue credential: %w", err)
+	}
+	document, err := json.Marshal(signed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential: %w", err)
+	}
+
+	_, err = processor.store.CreateCredential(ctx, db.CreateCredentialParams{
+		CredentialUri: signed.ID,
+		UserID:        user.UserID,
+		CourseID:      course.CourseID,
+		Document:      document,
+		IssuedAt:      issuedAt,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolations {
+			// issued by a concurrent task
+			return nil
+		}
+		return fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("credential", signed.ID).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/lti"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskPostLTIScore = "task:post_lti_score"
+
+// ltiScoreMaximum is the maximum of the marks posted to platform gradebooks, marks are a percentage
+const ltiScoreMaximum = 100
+
+type PayloadPostLTIScore struct {
+	CourseID int64 `json:"course_id"`
+	UserID   int64 `json:"user_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskPostLTIScore(
+	ctx context.Context,
+	payload *PayloadPostLTIScore,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskPostLTIScore, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// DistributeLTIScore queues posting the marks of a student in a course to the gradebooks of the platforms linking the course
+func DistributeLTIScore(ctx context.Context, distributor TaskDistributor, courseID, userID int64) error {
+	return distributor.DistributeTaskPostLTIScore(ctx, &PayloadPostLTIScore{CourseID: courseID, UserID: userID},
+		asynq.MaxRetry(10), asynq.Queue(QueueDefault))
+}
+
+// ProcessTaskPostLTIScore posts the marks of a student to every line item of a platform resource link to the course
+// the student launched from. The score carries the time the marks were last updated, so a retry of an unchanged mark
+// is ignored by platforms that already have it.
+func (processor *RedisTaskProcessor) ProcessTaskPostLTIScore(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadPostLTIScore
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	tool, err := lti.NewTool(processor.config.LTIToolURL, processor.config.LTIPrivateKey)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	targets, err := processor.store.ListLTIScoreTargets(ctx, db.ListLTIScoreTargetsParams{
+		CourseID: payload.CourseID,
+		UserID:   payload.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list score targets: %w", err)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	mark, err := processor.store.GetMarkByCourseAndUser(ctx, db.GetMarkByCourseAndUserParams{
+		CourseID: payload.CourseID,
+		UserID:   payload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("no marks to post: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get marks: %w", err)
+	}
+
+	var failed error
+	for _, target := range targets {
+		platform := lti.Platform{
+			Issuer:       target.Issuer,
+			ClientID:     target.ClientID,
+			DeploymentID: target.DeploymentID,
+			AuthLoginURL: target.AuthLoginURL,
+			AuthTokenURL: target.AuthTokenURL,
+			JWKSURL:      target.JwksURL,
+		}
+		score := lti.NewScore(target.Subject, float64(mark.Marks), ltiScoreMaximum, mark.UpdatedAt)
+
+		if err := tool.PostScore(ctx, platform, target.LineitemURL, score); err != nil {
+			log.Error().Err(err).Int64("platform_id", target.PlatformID).Str("lineitem", target.LineitemURL).
+				Msg("failed to post LTI score")
+			failed = err
+		}
+	}
+	if failed != nil {
+		return fmt.Errorf("failed to post score: %w", failed)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("course_id", payload.CourseID).Int64("user_id", payload.UserID).
+		Msg("processed task successfully")
+	return nil
+}
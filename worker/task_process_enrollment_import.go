@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/enrollment"
+	"eduApp/mail"
+	"eduApp/util"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskProcessEnrollmentImport = "task:process_enrollment_import"
+
+// maxUserNameAttempts is how often a taken user name is retried with a random suffix for an invited student
+const maxUserNameAttempts = 5
+
+type PayloadProcessEnrollmentImport struct {
+	ImportID int64 `json:"import_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskProcessEnrollmentImport(
+	ctx context.Context,
+	payload *PayloadProcessEnrollmentImport,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskProcessEnrollmentImport, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskProcessEnrollmentImport enrolls the students of a roster one row at a time. Every row records its
+// result as it finishes, so progress can be polled and a retried task picks up after the last finished row.
+func (processor *RedisTaskProcessor) ProcessTaskProcessEnrollmentImport(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadProcessEnrollmentImport
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	job, err := processor.store.StartEnrollmentImport(ctx, payload.ImportID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			log.Info().Int64("import_id", payload.ImportID).Msg("enrollment import is already finished")
+			return nil
+		}
+		return fmt.Errorf("failed to start enrollment import: %w", err)
+	}
+
+	course, err := processor.store.GetCourses(ctx, job.CourseID)
+	if err != nil {
+		return processor.failEnrollmentImport(ctx, job, fmt.Errorf("failed to get course: %w", err))
+	}
+
+	for i := job.ProcessedRows; i < int64(len(job.Rows)); i++ {
+		result := processor.enrollRosterRow(ctx, course, job.Rows[i])
+
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return processor.failEnrollmentImport(ctx, job, fmt.Errorf("failed to encode row result: %w", err))
+		}
+		err = processor.store.AddEnrollmentImportResult(ctx, db.AddEnrollmentImportResultParams{
+			Result:   encoded,
+			ImportID: job.ImportID,
+		})
+		if err != nil {
+			return processor.failEnrollmentImport(ctx, job, fmt.Errorf("failed to save row result: %w", err))
+		}
+	}
+
+	err = processor.store.FinishEnrollmentImport(ctx, db.FinishEnrollmentImportParams{
+		ImportID: job.ImportID,
+		Status:   enrollment.ImportCompleted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to finish enrollment import: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("import_id", job.ImportID).
+		Int("rows", len(job.Rows)).Msg("processed task")
+	return nil
+}
+
+// failEnrollmentImport marks the job failed once the task is out of retries, until then the task is retried
+func (processor *RedisTaskProcessor) failEnrollmentImport(ctx context.Context, job db.EnrollmentImport, cause error) error {
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	if retried < maxRetry {
+		return cause
+	}
+
+	err := processor.store.FinishEnrollmentImport(ctx, db.FinishEnrollmentImportParams{
+		ImportID: job.ImportID,
+		Status:   enrollment.ImportFailed,
+		Error:    cause.Error(),
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("import_id", job.ImportID).Msg("failed to mark enrollment import failed")
+	}
+	return cause
+}
+
+// enrollRosterRow enrolls one student of a roster, creating and inviting an account for an unknown email.
+// Enrollment skips the mode and window of the course like any admin enrollment, a full course waitlists the student.
+func (processor *RedisTaskProcessor) enrollRosterRow(ctx context.Context, course db.Course, row enrollment.RosterRow) enrollment.RowResult {
+	result := enrollment.RowResult{Row: row.Row, Email: row.Email}
+
+	user, err := processor.store.GetUserByEmail(ctx, row.Email)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		user, err = processor.createInvitedUser(ctx, row)
+		result.AccountCreated = err == nil
+	}
+	if err != nil {
+		result.Status = enrollment.RowFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.UserID = user.UserID
+
+	txResult, err := processor.store.CreateSubscriptionTx(ctx, db.CreateSubscriptionTxParams{
+		UserID:   user.UserID,
+		CourseID: course.CourseID,
+		Now:      time.Now(),
+		Invited:  true,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolations {
+			result.Status = enrollment.RowAlreadyEnrolled
+			return result
+		}
+		result.Status = enrollment.RowFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	event := EnrollmentEventApproved
+	result.Status = enrollment.RowEnrolled
+	if txResult.Subscription.WaitlistedAt.Valid {
+		event = EnrollmentEventWaitlisted
+		result.Status = enrollment.RowWaitlisted
+	} else {
+		processor.distributeCourseLaunched(ctx, txResult.Subscription)
+	}
+
+	_, err = processor.store.CreateCourseProgress(ctx, db.CreateCourseProgressParams{
+		CourseID: course.CourseID,
+		UserID:   user.UserID,
+		Progress: 0,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", user.UserID).Int64("course_id", course.CourseID).Msg("failed to create course progress")
+	}
+
+	if result.AccountCreated {
+		err = processor.sendAccountInvite(ctx, user, course, event)
+	} else {
+		err = processor.notifyEnrollmentEvent(ctx, user.UserID, course, event, taskKey(ctx, event))
+	}
+	if err != nil {
+		log.Error().Err(err).Str("email", user.Email).Msg("failed to send enrollment notification")
+		result.Error = "enrolled, but the notification could not be sent"
+	}
+
+	return result
+}
+
+// createInvitedUser creates a student account with an unusable random password, the student sets a password from the invite
+func (processor *RedisTaskProcessor) createInvitedUser(ctx context.Context, row enrollment.RosterRow) (db.User, error) {
+	hashedPassword, err := util.HashPassword(util.RandomString(32))
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	userName := row.UserName
+	if userName == "" {
+		userName = enrollment.UserNameFromEmail(row.Email)
+	}
+
+	for attempt := 1; ; attempt++ {
+		user, err := processor.store.CreateUser(ctx, db.CreateUserParams{
+			UserName:        userName,
+			FirstName:       row.FirstName,
+			LastName:        row.LastName,
+			HashedPassword:  hashedPassword,
+			Email:           row.Email,
+			Role:            "student",
+			IsEmailVerified: false,
+		})
+		if err == nil {
+			return user, nil
+		}
+		if db.ErrorCode(err) != db.UniqueViolations || attempt == maxUserNameAttempts {
+			return db.User{}, fmt.Errorf("failed to create user: %w", err)
+		}
+		userName = fmt.Sprintf("%s%s", userName, util.RandomString(4))
+	}
+}
+
+// sendAccountInvite welcomes a student whose account was created by a bulk enrollment,
+// with a link to verify the email address and one to choose a password
+func (processor *RedisTaskProcessor) sendAccountInvite(ctx context.Context, user db.User, course db.Course, event string) error {
+	verifyEmail, err := processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
+		UserID:     user.UserID,
+		Email:      user.Email,
+		SecretCode: util.RandomString(6),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verify email: %w", err)
+	}
+
+	_, err = processor.store.CreateUserStatus(ctx, db.CreateUserStatusParams{
+		UserID:  user.UserID,
+		Active:  false,
+		Pending: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user status: %w", err)
+	}
+
+	baseUrl := processor.frontendURL()
+	message, err := mail.Render(mail.TemplateAccountInvite, user.Locale, mail.AccountInviteData{
+		FirstName:   user.FirstName,
+		UserName:    user.UserName,
+		CourseTitle: course.Title,
+		Event:       event,
+		VerifyURL:   fmt.Sprintf("%s/verifyemail?email_id=%d&secret_code=%s", baseUrl, verifyEmail.EmailID, verifyEmail.SecretCode),
+		ResetURL:    fmt.Sprintf("%s/reset/password", baseUrl),
+	})
+	if err != nil {
+		return err
+	}
+
+	return processor.mailer.SendMessage(message, []string{user.Email}, nil, nil, nil)
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"eduApp/notification"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskRemindDueDates = "task:remind_due_dates"
+
+// DefaultDueDateReminderSchedule is the cron spec used when DUE_DATE_REMINDER_SCHEDULE is not configured
+const DefaultDueDateReminderSchedule = "@hourly"
+
+// DueDateReminderLead is how long before an assignment is due students who have not submitted are reminded
+const DueDateReminderLead = 24 * time.Hour
+
+type PayloadRemindDueDates struct{}
+
+// ProcessTaskRemindDueDates reminds students of the assignments they have not submitted that are due soon.
+// A deadline moved by an extension is reminded of again, every run after the first finds the students reminded already.
+func (processor *RedisTaskProcessor) ProcessTaskRemindDueDates(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadRemindDueDates
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	reminders, err := processor.store.ListDueAssignmentReminders(ctx, time.Now().Add(DueDateReminderLead))
+	if err != nil {
+		return fmt.Errorf("failed to list due assignments: %w", err)
+	}
+
+	failed := 0
+	for _, reminder := range reminders {
+		err := processor.notify(ctx, notification.Notification{
+			UserID:   reminder.UserID,
+			Type:     notification.TypeDueDate,
+			CourseID: reminder.CourseID,
+			Title:    fmt.Sprintf("%s is due soon", reminder.Title),
+			Message: fmt.Sprintf("You have not submitted %s yet. It is due by %s.",
+				reminder.Title, reminder.DueDate.UTC().Format(time.RFC1123)),
+			// the deadline is part of the key, so the reminder is sent once per deadline and not once per run
+			Key: fmt.Sprintf("due_date:%d:%d", reminder.AssignmentID, reminder.DueDate.Unix()),
+		})
+		if err != nil {
+			log.Error().Err(err).Int64("assignment_id", reminder.AssignmentID).
+				Int64("user_id", reminder.UserID).Msg("failed to send due date reminder")
+			failed++
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Int("reminders", len(reminders)).Int("failed", failed).Msg("processed task")
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d due date reminders", failed)
+	}
+	return nil
+}
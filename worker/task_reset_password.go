@@ -2,8 +2,10 @@ package worker
 
 import (
 	"context"
-	"database/sql"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/hibiken/asynq"
@@ -47,32 +49,31 @@ func (processor *RedisTaskProcessor) ProcessTaskResetpassword(ctx context.Contex
 	}
 
 	//checking the email exist in db or not
-	email, err := processor.store.CheckEmail(ctx, payload.Email)
+	user, err := processor.store.GetUserByEmail(ctx, payload.Email)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			return fmt.Errorf("invalied email address: %w", err)
 		}
 		return fmt.Errorf("failed to get data: %w", err)
 	}
 
-	subject := "Welcome to EduApp"
 	// front-end URL to reset Password
-	verifyUrl := " http://172.17.249.61:3390/reset/password"
-	content := fmt.Sprintf(`Hello ,<br/>
-	We received a request to reset your account password.<br/>
-	If you initiated this request, <br/>
-	Please <a href="%s">click here</a> to reset your password.<br/>
-	Thankyou!
-	`, verifyUrl)
-	to := []string{email}
+	message, err := mail.Render(mail.TemplateResetPassword, user.Locale, mail.ResetPasswordData{
+		FirstName: user.FirstName,
+		ResetURL:  fmt.Sprintf("%s/reset/password", processor.frontendURL()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render password reset email: %v: %w", err, asynq.SkipRetry)
+	}
+	to := []string{user.Email}
 
-	err = processor.mailer.SendEmail(subject, content, to, nil, nil, nil)
+	err = processor.mailer.SendMessage(message, to, nil, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to send password reset request")
 	}
 
 	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
-		Str("email", email).Msg("processed_task")
+		Str("email", user.Email).Msg("processed_task")
 
 	return nil
 }
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendEnrollmentEmail = "task:send_enrollment_email"
+
+// enrollment events a student is told about by email
+const (
+	EnrollmentEventApproved   = "approved"
+	EnrollmentEventDenied     = "denied"
+	EnrollmentEventWaitlisted = "waitlisted"
+	EnrollmentEventPromoted   = "promoted"
+	EnrollmentEventExpired    = "expired"
+)
+
+type PayloadSendEnrollmentEmail struct {
+	UserID   int64  `json:"user_id"`
+	CourseID int64  `json:"course_id"`
+	Event    string `json:"event"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendEnrollmentEmail(
+	ctx context.Context,
+	payload *PayloadSendEnrollmentEmail,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendEnrollmentEmail, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskSendEnrollmentEmail tells a student what happened to their enrollment request, in the app and by email
+func (processor *RedisTaskProcessor) ProcessTaskSendEnrollmentEmail(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendEnrollmentEmail
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	user, err := processor.store.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	course, err := processor.store.GetCourses(ctx, payload.CourseID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("course doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get course: %w", err)
+	}
+
+	if _, _, err := enrollmentMessage(payload.Event, course); err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	err = processor.notifyEnrollmentEvent(ctx, user.UserID, course, payload.Event, taskKey(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to send enrollment notification: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("email", user.Email).Msg("processed task")
+	return nil
+}
+
+// notifyEnrollmentEvent notifies a student about an enrollment event
+func (processor *RedisTaskProcessor) notifyEnrollmentEvent(ctx context.Context, userID int64, course db.Course, event string, key string) error {
+	subject, message, err := enrollmentMessage(event, course)
+	if err != nil {
+		return err
+	}
+
+	return processor.notify(ctx, notification.Notification{
+		UserID:   userID,
+		Type:     notification.TypeEnrollment,
+		CourseID: course.CourseID,
+		Title:    subject,
+		Message:  message,
+		Template: mail.TemplateEnrollment,
+		Data:     map[string]string{"Event": event, "CourseTitle": course.Title},
+		Key:      key,
+	})
+}
+
+// enrollmentMessage words the notification about an enrollment event in a course
+func enrollmentMessage(event string, course db.Course) (subject, message string, err error) {
+	switch event {
+	case EnrollmentEventApproved:
+		subject = fmt.Sprintf("You are enrolled in %s", course.Title)
+		message = fmt.Sprintf("You are now enrolled in %s, you can start learning right away.", course.Title)
+	case EnrollmentEventDenied:
+		subject = fmt.Sprintf("Your request to join %s", course.Title)
+		message = fmt.Sprintf("Sorry, your request to join %s was not approved.", course.Title)
+	case EnrollmentEventWaitlisted:
+		subject = fmt.Sprintf("You are on the waitlist for %s", course.Title)
+		message = fmt.Sprintf("%s is full right now. You are on the waitlist and will be enrolled as soon as a seat frees up.", course.Title)
+	case EnrollmentEventPromoted:
+		subject = fmt.Sprintf("A seat opened up in %s", course.Title)
+		message = fmt.Sprintf("A seat opened up in %s and you have been enrolled from the waitlist.", course.Title)
+	case EnrollmentEventExpired:
+		subject = fmt.Sprintf("Your access to %s has ended", course.Title)
+		message = fmt.Sprintf("Your access to %s has ended. Contact us or enroll again to renew it.", course.Title)
+	default:
+		return "", "", fmt.Errorf("unknown enrollment event %q", event)
+	}
+	return subject, message, nil
+}
@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/mail"
+	"eduApp/notification"
+	"eduApp/realtime"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendNotification = "task:send_notification"
+
+type PayloadSendNotification struct {
+	// UserIDs are the recipients, or with ToCourse the students of CourseID are
+	UserIDs  []int64 `json:"user_ids"`
+	ToCourse bool    `json:"to_course"`
+	CourseID int64   `json:"course_id"`
+	Type     string  `json:"type"`
+	Title    string  `json:"title"`
+	Message  string  `json:"message"`
+	// Template and Data word the email, see notification.Notification
+	Template string            `json:"template,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendNotification(
+	ctx context.Context,
+	payload *PayloadSendNotification,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendNotification, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskSendNotification notifies every recipient, a retry skips the ones already notified
+func (processor *RedisTaskProcessor) ProcessTaskSendNotification(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+	if !notification.ValidType(payload.Type) {
+		return fmt.Errorf("unknown notification type %q: %w", payload.Type, asynq.SkipRetry)
+	}
+
+	userIDs := payload.UserIDs
+	if payload.ToCourse {
+		var err error
+		userIDs, err = processor.store.ListCourseStudentIDs(ctx, payload.CourseID)
+		if err != nil {
+			return fmt.Errorf("failed to list course students: %w", err)
+		}
+	}
+
+	key := taskKey(ctx)
+	failed := 0
+	for _, userID := range userIDs {
+		err := processor.notify(ctx, notification.Notification{
+			UserID:   userID,
+			Type:     payload.Type,
+			CourseID: payload.CourseID,
+			Title:    payload.Title,
+			Message:  payload.Message,
+			Template: payload.Template,
+			Data:     payload.Data,
+			Key:      key,
+		})
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Str("notification_type", payload.Type).Msg("failed to notify user")
+			failed++
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Int("recipients", len(userIDs)).Int("failed", failed).Msg("processed task")
+	if failed > 0 {
+		return fmt.Errorf("failed to notify %d users", failed)
+	}
+	return nil
+}
+
+// notify stores a notification and emails it, as far as the user's preference for its type allows.
+// The notification is stored even when it is not shown in the app, so a retry finds it and does not email twice.
+func (processor *RedisTaskProcessor) notify(ctx context.Context, n notification.Notification) error {
+	preference, err := processor.preference(ctx, n.UserID, n.Type)
+	if err != nil {
+		return err
+	}
+
+	created, err := processor.store.CreateNotification(ctx, db.CreateNotificationParams{
+		UserID:   n.UserID,
+		Type:     n.Type,
+		CourseID: pgtype.Int8{Int64: n.CourseID, Valid: n.CourseID != 0},
+		Title:    n.Title,
+		Message:  n.Message,
+		DedupKey: n.Key,
+		InApp:    preference.InApp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	if preference.InApp {
+		processor.publishEvent(ctx, n.UserID, realtime.EventNotificationCreated, realtime.NotificationCreated{
+			NotificationID: created.NotificationID,
+			Type:           created.Type,
+			Title:          created.Title,
+		})
+	}
+
+	if !preference.Email || created.EmailedAt.Valid {
+		return nil
+	}
+
+	user, err := processor.store.GetUserByID(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	template := n.Template
+	if template == "" {
+		template = mail.TemplateNotification
+	}
+	message, err := mail.Render(template, user.Locale, mail.NotificationData{
+		FirstName: user.FirstName,
+		Title:     n.Title,
+		Message:   n.Message,
+		Values:    n.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render notification email: %w", err)
+	}
+
+	err = processor.mailer.SendMessage(message, []string{user.Email}, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	if err := processor.store.SetNotificationEmailed(ctx, created.NotificationID); err != nil {
+		return fmt.Errorf("failed to mark notification emailed: %w", err)
+	}
+	return nil
+}
+
+// preference is how a user wants to receive a type of notification, the default for a type they have not set
+func (processor *RedisTaskProcessor) preference(ctx context.Context, userID int64, notificationType string) (notification.Preference, error) {
+	stored, err := processor.store.GetNotificationPreference(ctx, db.GetNotificationPreferenceParams{
+		UserID: userID,
+		Type:   notificationType,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return notification.DefaultPreference(notificationType), nil
+		}
+		return notification.Preference{}, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	return notification.Preference{Type: stored.Type, InApp: stored.InApp, Email: stored.Email}, nil
+}
+
+// taskKey is a notification key that stays the same when the running task is retried, parts tell apart
+// the notifications a task sends to the same user
+func taskKey(ctx context.Context, parts ...string) string {
+	id, ok := asynq.GetTaskID(ctx)
+	if !ok {
+		id = uuid.NewString()
+	}
+	return strings.Join(append([]string{"task", id}, parts...), ":")
+}
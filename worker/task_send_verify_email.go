@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 
 	db "eduApp/db/sqlc"
+	"eduApp/mail"
 	"eduApp/util"
 
 	"github.com/hibiken/asynq"
@@ -71,28 +71,23 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return fmt.Errorf("failed to create user status: %w", err)
 	}
 
-	baseUrl := os.Getenv("VERIFY_EMAIL_BASE_URL")
-	if baseUrl == "" {
-		baseUrl = "http://172.17.249.61:3390"
-	}
-	verifyUrl := fmt.Sprintf("%s/verifyemail?email_id=%d&secret_code=%s", baseUrl, verifyEmail.EmailID, verifyEmail.SecretCode)
-	commonContent := fmt.Sprintf(`Hello %s,<br/>
-	Your OTP is %s. Don't share it with others!<br/>
-	Please <a href="%s">click here</a> to verify your email address.<br/>`, user.FirstName, verifyEmail.SecretCode, verifyUrl)
-
-	var subject, content string
-	if user.Role == "admin" {
-		subject = "Welcome to EduApp Teaching Committee"
-		content = fmt.Sprintf(`%s<br/>Welcome to EduApp Teaching committee!<br/>`, commonContent)
-	} else if user.Role == "student" {
-		subject = "Welcome to EduApp"
-		content = fmt.Sprintf(`%s<br/>Thank you for registering with us!<br/>`, commonContent)
-	} else {
+	if user.Role != "admin" && user.Role != "student" {
 		return fmt.Errorf("unknown user role: %s", user.Role)
 	}
 
+	verifyUrl := fmt.Sprintf("%s/verifyemail?email_id=%d&secret_code=%s", processor.frontendURL(), verifyEmail.EmailID, verifyEmail.SecretCode)
+	message, err := mail.Render(mail.TemplateVerifyEmail, user.Locale, mail.VerifyEmailData{
+		FirstName:  user.FirstName,
+		Role:       user.Role,
+		SecretCode: verifyEmail.SecretCode,
+		VerifyURL:  verifyUrl,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render verify email: %v: %w", err, asynq.SkipRetry)
+	}
+
 	to := []string{user.Email}
-	err = processor.mailer.SendEmail(subject, content, to, nil, nil, nil)
+	err = processor.mailer.SendMessage(message, to, nil, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to send verify email: %w", err)
 	}
@@ -101,3 +96,11 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		Str("email", user.Email).Msg("processed task")
 	return nil
 }
+
+// frontendURL is where the links in emails point to, the VERIFY_EMAIL_BASE_URL of the frontend
+func (processor *RedisTaskProcessor) frontendURL() string {
+	if processor.config.VerifyEmailBaseURL != "" {
+		return processor.config.VerifyEmailBaseURL
+	}
+	return "http://172.17.249.61:3390"
+}
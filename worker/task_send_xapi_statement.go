@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	db "eduApp/db/sqlc"
+	"eduApp/util"
+	"eduApp/xapi"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendXAPIStatement = "task:send_xapi_statement"
+
+// XAPIAuthorityName is the account the app vouches for the statements it stores with
+const XAPIAuthorityName = "eduApp"
+
+type PayloadSendXAPIStatement struct {
+	Statement xapi.Statement `json:"statement"`
+}
+
+// XAPIActivities returns the activity IRIs of the app, they live under XAPI_BASE_URL or else the front end origin
+func XAPIActivities(config util.Config) xapi.Activities {
+	baseURL := config.XAPIBaseURL
+	if baseURL == "" {
+		baseURL = config.FrontEndOrigin
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost"
+	}
+	return xapi.Activities{BaseURL: baseURL}
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendXAPIStatement(
+	ctx context.Context,
+	payload *PayloadSendXAPIStatement,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendXAPIStatement, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("enqueued task")
+	return nil
+}
+
+// DistributeXAPIStatement checks a statement and queues it for the LRS. The statement ID is set before it is queued,
+// so a retried task never stores the statement twice.
+func DistributeXAPIStatement(ctx context.Context, distributor TaskDistributor, statement xapi.Statement) error {
+	if err := statement.Validate(); err != nil {
+		return err
+	}
+
+	return distributor.DistributeTaskSendXAPIStatement(ctx, &PayloadSendXAPIStatement{Statement: statement},
+		asynq.MaxRetry(10), asynq.Queue(QueueDefault))
+}
+
+// DistributeCourseLaunched queues the xAPI launched statement of a student who got a seat in a course
+func DistributeCourseLaunched(ctx context.Context, distributor TaskDistributor, activities xapi.Activities, subscription db.Subscription) error {
+	statement := xapi.NewStatement(
+		activities.Agent(subscription.UserID, ""),
+		xapi.VerbLaunched,
+		activities.Course(subscription.CourseID, ""),
+	)
+	return DistributeXAPIStatement(ctx, distributor, statement)
+}
+
+// distributeCourseLaunched is DistributeCourseLaunched for tasks, a failure is only logged
+func (processor *RedisTaskProcessor) distributeCourseLaunched(ctx context.Context, subscription db.Subscription) {
+	err := DistributeCourseLaunched(ctx, processor.distributor, XAPIActivities(processor.config), subscription)
+	if err != nil {
+		log.Error().Err(err).Int64("subscription_id", subscription.SubscriptionID).Msg("failed to enqueue xAPI statement")
+	}
+}
+
+// ProcessTaskSendXAPIStatement sends a statement to the LRS at XAPI_LRS_ENDPOINT,
+// the statement is kept by the built-in LRS when no endpoint is configured
+func (processor *RedisTaskProcessor) ProcessTaskSendXAPIStatement(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendXAPIStatement
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	if processor.config.XAPIEndpoint != "" {
+		client := xapi.NewClient(processor.config.XAPIEndpoint, processor.config.XAPIUsername, processor.config.XAPIPassword)
+		if err := client.Send(ctx, payload.Statement); err != nil {
+			return fmt.Errorf("failed to send statement: %w", err)
+		}
+
+		log.Info().Str("type", task.Type()).Str("statement_id", payload.Statement.ID).Msg("processed task successfully")
+		return nil
+	}
+
+	data, err := json.Marshal(payload.Statement)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	stored := time.Now()
+	authority := XAPIActivities(processor.config).Authority(XAPIAuthorityName)
+	statement, document, err := xapi.Prepare(data, authority, stored)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	record, err := db.NewXAPIStatementRecord(statement, document, stored)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	_, err = processor.store.StoreXAPIStatementsTx(ctx, db.StoreXAPIStatementsTxParams{
+		Statements: []db.XAPIStatementRecord{record},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrStatementConflict) {
+			return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to store statement: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("statement_id", statement.ID).Msg("processed task successfully")
+	return nil
+}
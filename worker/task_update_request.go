@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	db "eduApp/db/sqlc"
+	"eduApp/realtime"
 	"eduApp/token"
 	"encoding/json"
 	"fmt"
@@ -94,6 +95,8 @@ func (processor *RedisTaskProcessor) ProcessTaskUpdateRequest(ctx context.Contex
 		}
 	}
 
+	processor.publishTaskFinished(ctx, AuthPayload.UserID, task.Type(), payload.RequestID, realtime.TaskDone)
+
 	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Msg("processed task")
 	return nil
 }
@@ -0,0 +1,64 @@
+package xapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client sends statements to a learning record store
+type Client struct {
+	endpoint string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewClient returns a client of the LRS at endpoint, the base URL the statements resource lives under
+func NewClient(endpoint, username, password string) *Client {
+	return &Client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Send stores a statement in the LRS. A statement the LRS already has is not an error,
+// so sending the same statement again after a failure is safe.
+func (client *Client) Send(ctx context.Context, statement Statement) error {
+	body, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+
+	url := client.endpoint + "/statements?statementId=" + statement.ID
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(VersionHeader, Version)
+	if client.username != "" {
+		req.SetBasicAuth(client.username, client.password)
+	}
+
+	resp, err := client.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 409 means the LRS has a statement with this ID, it was stored by an earlier try
+	if resp.StatusCode == http.StatusConflict || resp.StatusCode/100 == 2 {
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("LRS answered %s: %s", resp.Status, strings.TrimSpace(string(message)))
+}
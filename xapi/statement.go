@@ -0,0 +1,174 @@
+package xapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version is the xAPI version statements are written in and the LRS answers with
+const Version = "1.0.3"
+
+// VersionHeader is the header carrying the xAPI version of every LRS request and response
+const VersionHeader = "X-Experience-API-Version"
+
+// Object types of the statement object
+const (
+	ObjectActivity     = "Activity"
+	ObjectAgent        = "Agent"
+	ObjectGroup        = "Group"
+	ObjectStatementRef = "StatementRef"
+	ObjectSubStatement = "SubStatement"
+)
+
+// LanguageMap maps RFC 5646 language tags to text in that language
+type LanguageMap map[string]string
+
+// Account identifies an agent by its account on a system
+type Account struct {
+	HomePage string `json:"homePage"`
+	Name     string `json:"name"`
+}
+
+// Agent is the actor of a statement, a group has members instead of, or as well as, an identifier
+type Agent struct {
+	ObjectType  string   `json:"objectType,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Mbox        string   `json:"mbox,omitempty"`
+	MboxSHA1Sum string   `json:"mbox_sha1sum,omitempty"`
+	OpenID      string   `json:"openid,omitempty"`
+	Account     *Account `json:"account,omitempty"`
+	Member      []Agent  `json:"member,omitempty"`
+}
+
+// Verb is what the actor did
+type Verb struct {
+	ID      string      `json:"id"`
+	Display LanguageMap `json:"display,omitempty"`
+}
+
+// Definition describes an activity
+type Definition struct {
+	Name        LanguageMap `json:"name,omitempty"`
+	Description LanguageMap `json:"description,omitempty"`
+	Type        string      `json:"type,omitempty"`
+}
+
+// Object is what the statement is about. Activities and statement references are modelled,
+// agents and sub-statements are validated from the raw statement, see Parse.
+type Object struct {
+	ObjectType string      `json:"objectType,omitempty"`
+	ID         string      `json:"id,omitempty"`
+	Definition *Definition `json:"definition,omitempty"`
+}
+
+// Score is the score of a result, Scaled is between -1 and 1
+type Score struct {
+	Scaled *float64 `json:"scaled,omitempty"`
+	Raw    *float64 `json:"raw,omitempty"`
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+}
+
+// Result is the outcome of the statement
+type Result struct {
+	Score      *Score `json:"score,omitempty"`
+	Success    *bool  `json:"success,omitempty"`
+	Completion *bool  `json:"completion,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Duration   string `json:"duration,omitempty"`
+}
+
+// ContextActivities are the activities a statement relates to
+type ContextActivities struct {
+	Parent   []Object `json:"parent,omitempty"`
+	Grouping []Object `json:"grouping,omitempty"`
+	Category []Object `json:"category,omitempty"`
+	Other    []Object `json:"other,omitempty"`
+}
+
+// Context gives the statement its setting
+type Context struct {
+	Registration      string             `json:"registration,omitempty"`
+	Instructor        *Agent             `json:"instructor,omitempty"`
+	ContextActivities *ContextActivities `json:"contextActivities,omitempty"`
+	Revision          string             `json:"revision,omitempty"`
+	Platform          string             `json:"platform,omitempty"`
+	Language          string             `json:"language,omitempty"`
+	Statement         *Object            `json:"statement,omitempty"`
+}
+
+// Statement is an xAPI statement: an actor did a verb to an object
+type Statement struct {
+	ID        string   `json:"id,omitempty"`
+	Actor     Agent    `json:"actor"`
+	Verb      Verb     `json:"verb"`
+	Object    Object   `json:"object"`
+	Result    *Result  `json:"result,omitempty"`
+	Context   *Context `json:"context,omitempty"`
+	Timestamp string   `json:"timestamp,omitempty"`
+	Stored    string   `json:"stored,omitempty"`
+	Authority *Agent   `json:"authority,omitempty"`
+	Version   string   `json:"version,omitempty"`
+}
+
+// StatementResult is the answer of the LRS to a statement query, More is the URL of the next page
+type StatementResult struct {
+	Statements []json.RawMessage `json:"statements"`
+	More       string            `json:"more"`
+}
+
+// NewStatement returns a statement with a fresh ID timestamped now
+func NewStatement(actor Agent, verb Verb, object Object) Statement {
+	return Statement{
+		ID:        uuid.NewString(),
+		Actor:     actor,
+		Verb:      verb,
+		Object:    object,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Version:   Version,
+	}
+}
+
+// WithScore sets the score of the statement, scaled from raw between zero and max when max is known
+func (statement Statement) WithScore(raw, max float64) Statement {
+	score := &Score{Raw: &raw}
+	if max > 0 {
+		min := 0.0
+		scaled := raw / max
+		if scaled > 1 {
+			scaled = 1
+		}
+		score.Min, score.Max, score.Scaled = &min, &max, &scaled
+	}
+
+	result := Result{}
+	if statement.Result != nil {
+		result = *statement.Result
+	}
+	result.Score = score
+	statement.Result = &result
+	return statement
+}
+
+// WithCompletion sets whether the object was completed and, when success is known, whether it was passed
+func (statement Statement) WithCompletion(completion bool, success *bool) Statement {
+	result := Result{}
+	if statement.Result != nil {
+		result = *statement.Result
+	}
+	result.Completion = &completion
+	result.Success = success
+	statement.Result = &result
+	return statement
+}
+
+// WithParent places the statement inside a parent activity, such as the course of a lesson
+func (statement Statement) WithParent(parent Object, platform string) Statement {
+	statement.Context = &Context{
+		ContextActivities: &ContextActivities{Parent: []Object{parent}},
+		Platform:          platform,
+	}
+	return statement
+}
@@ -0,0 +1,172 @@
+package xapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewStatement(t *testing.T) {
+	activities := Activities{BaseURL: "https://lms.example.com/"}
+	before := time.Now().UTC().Add(-time.Second)
+
+	statement := NewStatement(activities.Agent(7, "Ada"), VerbLaunched, activities.Course(3, "Go"))
+
+	if !isUUID(statement.ID) {
+		t.Errorf("ID = %q, want a UUID", statement.ID)
+	}
+	if other := NewStatement(statement.Actor, statement.Verb, statement.Object); other.ID == statement.ID {
+		t.Error("NewStatement() reused an ID")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, statement.Timestamp)
+	if err != nil || timestamp.Before(before) || timestamp.After(time.Now().Add(time.Second)) {
+		t.Errorf("Timestamp = %q, want now", statement.Timestamp)
+	}
+	if statement.Version != Version {
+		t.Errorf("Version = %q, want %q", statement.Version, Version)
+	}
+	if statement.Result != nil || statement.Context != nil {
+		t.Errorf("NewStatement() set a result or context: %+v", statement)
+	}
+	if err := statement.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	data, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	actor := raw["actor"].(map[string]any)
+	account := actor["account"].(map[string]any)
+	if account["homePage"] != "https://lms.example.com" || account["name"] != "7" || actor["name"] != "Ada" {
+		t.Errorf("actor = %v, want the account 7 on https://lms.example.com", actor)
+	}
+	object := raw["object"].(map[string]any)
+	if object["id"] != "https://lms.example.com/xapi/activities/course/3" || object["objectType"] != ObjectActivity {
+		t.Errorf("object = %v, want the course activity", object)
+	}
+	if raw["verb"].(map[string]any)["id"] != VerbLaunched.ID {
+		t.Errorf("verb = %v, want %s", raw["verb"], VerbLaunched.ID)
+	}
+	for _, property := range []string{"result", "context", "stored", "authority"} {
+		if _, ok := raw[property]; ok {
+			t.Errorf("statement has %s, want it omitted", property)
+		}
+	}
+}
+
+func TestWithScore(t *testing.T) {
+	activities := Activities{BaseURL: "https://lms.example.com"}
+	base := NewStatement(activities.Agent(1, ""), VerbScored, activities.Quiz(2, ""))
+
+	testCases := []struct {
+		name       string
+		raw, max   float64
+		wantScaled *float64
+		wantMax    *float64
+	}{
+		{"scaled", 8, 10, floatPtr(0.8), floatPtr(10)},
+		{"full marks", 10, 10, floatPtr(1), floatPtr(10)},
+		{"capped above max", 12, 10, floatPtr(1), floatPtr(10)},
+		{"zero", 0, 10, floatPtr(0), floatPtr(10)},
+		{"no max", 7, 0, nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statement := base.WithScore(tc.raw, tc.max)
+			if base.Result != nil {
+				t.Fatal("WithScore() modified the original statement")
+			}
+
+			score := statement.Result.Score
+			if score.Raw == nil || *score.Raw != tc.raw {
+				t.Errorf("raw = %v, want %v", score.Raw, tc.raw)
+			}
+			if !sameFloat(score.Scaled, tc.wantScaled) {
+				t.Errorf("scaled = %v, want %v", deref(score.Scaled), deref(tc.wantScaled))
+			}
+			if !sameFloat(score.Max, tc.wantMax) {
+				t.Errorf("max = %v, want %v", deref(score.Max), deref(tc.wantMax))
+			}
+			if tc.wantMax != nil && (score.Min == nil || *score.Min != 0) {
+				t.Errorf("min = %v, want 0", deref(score.Min))
+			}
+			if tc.wantMax == nil && score.Min != nil {
+				t.Errorf("min = %v, want none without a max", *score.Min)
+			}
+		})
+	}
+
+	// a score above max is still a statement the app can send: raw is kept, so validation rejects it
+	if err := base.WithScore(12, 10).Validate(); err == nil {
+		t.Error("Validate() accepted a raw score above max")
+	}
+	if err := base.WithScore(8, 10).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	completed := base.WithCompletion(true, nil).WithScore(5, 10)
+	if completed.Result.Completion == nil || !*completed.Result.Completion {
+		t.Error("WithScore() dropped the completion of the result")
+	}
+}
+
+func TestWithParent(t *testing.T) {
+	activities := Activities{BaseURL: "https://lms.example.com"}
+	course := activities.Course(3, "Go")
+	statement := NewStatement(activities.Agent(1, ""), VerbCompleted, activities.Lesson(9, "Intro")).
+		WithCompletion(true, nil).
+		WithParent(course, "eduApp")
+
+	if statement.Context == nil || statement.Context.ContextActivities == nil {
+		t.Fatal("WithParent() did not set context activities")
+	}
+	parents := statement.Context.ContextActivities.Parent
+	if len(parents) != 1 || parents[0].ID != course.ID {
+		t.Errorf("parent = %+v, want %s", parents, course.ID)
+	}
+	if statement.Context.Platform != "eduApp" {
+		t.Errorf("platform = %q, want eduApp", statement.Context.Platform)
+	}
+	if statement.Result == nil || statement.Result.Completion == nil {
+		t.Error("WithParent() dropped the result")
+	}
+	if err := statement.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	data, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Context map[string]json.RawMessage `json:"context"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw.Context["contextActivities"]; !ok {
+		t.Errorf("context = %s, want contextActivities", data)
+	}
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func deref(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,49 @@
+package xapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Prepare checks a statement sent to the LRS and fills in what the LRS sets on the statements it stores:
+// an ID when the client gave none, the stored time, the timestamp, the authority and the version.
+// Properties the app does not model, such as extensions, are kept as they were sent.
+func Prepare(data []byte, authority Agent, stored time.Time) (Statement, []byte, error) {
+	statement, err := Parse(data)
+	if err != nil {
+		return Statement{}, nil, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Statement{}, nil, err
+	}
+
+	if statement.ID == "" {
+		statement.ID = uuid.NewString()
+	}
+	statement.Stored = stored.UTC().Format(time.RFC3339Nano)
+	if statement.Timestamp == "" {
+		statement.Timestamp = statement.Stored
+	}
+	if statement.Authority == nil {
+		statement.Authority = &authority
+	}
+	if statement.Version == "" {
+		statement.Version = Version
+	}
+
+	raw["id"] = statement.ID
+	raw["stored"] = statement.Stored
+	raw["timestamp"] = statement.Timestamp
+	raw["authority"] = statement.Authority
+	raw["version"] = statement.Version
+
+	document, err := json.Marshal(raw)
+	if err != nil {
+		return Statement{}, nil, err
+	}
+	return statement, document, nil
+}
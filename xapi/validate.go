@@ -0,0 +1,287 @@
+package xapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidStatement wraps every reason a statement is rejected
+var ErrInvalidStatement = errors.New("invalid statement")
+
+// statementProperties are the properties a statement may have
+var statementProperties = map[string]bool{
+	"id": true, "actor": true, "verb": true, "object": true, "result": true, "context": true,
+	"timestamp": true, "stored": true, "authority": true, "version": true, "attachments": true,
+}
+
+// subStatementProperties are the properties a sub-statement may have
+var subStatementProperties = map[string]bool{
+	"objectType": true, "actor": true, "verb": true, "object": true, "result": true, "context": true,
+	"timestamp": true, "attachments": true,
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidStatement, fmt.Sprintf(format, args...))
+}
+
+// Parse decodes a statement and checks it against the xAPI 1.0.3 data model
+func Parse(data []byte) (Statement, error) {
+	return parse(data, statementProperties, false)
+}
+
+func parse(data []byte, properties map[string]bool, sub bool) (Statement, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Statement{}, invalid("statement is not a JSON object")
+	}
+	for property := range raw {
+		if !properties[property] {
+			return Statement{}, invalid("unknown property %q", property)
+		}
+	}
+
+	var statement Statement
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&statement); err != nil {
+		return Statement{}, invalid("%v", err)
+	}
+
+	if _, ok := raw["actor"]; !ok {
+		return Statement{}, invalid("actor is required")
+	}
+	if _, ok := raw["verb"]; !ok {
+		return Statement{}, invalid("verb is required")
+	}
+	if _, ok := raw["object"]; !ok {
+		return Statement{}, invalid("object is required")
+	}
+
+	if statement.ID != "" && !isUUID(statement.ID) {
+		return Statement{}, invalid("id must be a UUID")
+	}
+	if err := validateAgent(statement.Actor, "actor"); err != nil {
+		return Statement{}, err
+	}
+	if !isIRI(statement.Verb.ID) {
+		return Statement{}, invalid("verb id must be an IRI")
+	}
+	if err := validateObject(statement, raw["object"], sub); err != nil {
+		return Statement{}, err
+	}
+	if err := validateResult(statement.Result); err != nil {
+		return Statement{}, err
+	}
+	if err := validateContext(statement); err != nil {
+		return Statement{}, err
+	}
+	if statement.Timestamp != "" && !isTimestamp(statement.Timestamp) {
+		return Statement{}, invalid("timestamp must be an ISO 8601 date time")
+	}
+	if statement.Stored != "" && !isTimestamp(statement.Stored) {
+		return Statement{}, invalid("stored must be an ISO 8601 date time")
+	}
+	if statement.Authority != nil {
+		if err := validateAgent(*statement.Authority, "authority"); err != nil {
+			return Statement{}, err
+		}
+	}
+	if statement.Version != "" && !strings.HasPrefix(statement.Version, "1.0") {
+		return Statement{}, invalid("version %q is not supported", statement.Version)
+	}
+
+	return statement, nil
+}
+
+// Validate checks a statement built by the app against the xAPI 1.0.3 data model
+func (statement Statement) Validate() error {
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+	_, err = Parse(data)
+	return err
+}
+
+func validateAgent(agent Agent, field string) error {
+	identifiers := 0
+	if agent.Mbox != "" {
+		if !strings.HasPrefix(agent.Mbox, "mailto:") {
+			return invalid("%s mbox must be a mailto IRI", field)
+		}
+		identifiers++
+	}
+	if agent.MboxSHA1Sum != "" {
+		identifiers++
+	}
+	if agent.OpenID != "" {
+		if !isIRI(agent.OpenID) {
+			return invalid("%s openid must be an IRI", field)
+		}
+		identifiers++
+	}
+	if agent.Account != nil {
+		if !isIRI(agent.Account.HomePage) || agent.Account.Name == "" {
+			return invalid("%s account needs a homePage IRI and a name", field)
+		}
+		identifiers++
+	}
+	if identifiers > 1 {
+		return invalid("%s must have exactly one identifier", field)
+	}
+
+	switch agent.ObjectType {
+	case "", ObjectAgent:
+		if identifiers == 0 {
+			return invalid("%s must have exactly one identifier", field)
+		}
+		if len(agent.Member) > 0 {
+			return invalid("%s is an agent and cannot have members", field)
+		}
+	case ObjectGroup:
+		if identifiers == 0 && len(agent.Member) == 0 {
+			return invalid("anonymous group %s needs members", field)
+		}
+		for _, member := range agent.Member {
+			if member.ObjectType == ObjectGroup {
+				return invalid("%s members must be agents", field)
+			}
+			if err := validateAgent(member, field+" member"); err != nil {
+				return err
+			}
+		}
+	default:
+		return invalid("%s objectType must be Agent or Group", field)
+	}
+	return nil
+}
+
+func validateObject(statement Statement, raw json.RawMessage, sub bool) error {
+	object := statement.Object
+	switch object.ObjectType {
+	case "", ObjectActivity:
+		if !isIRI(object.ID) {
+			return invalid("activity id must be an IRI")
+		}
+		if object.Definition != nil && object.Definition.Type != "" && !isIRI(object.Definition.Type) {
+			return invalid("activity type must be an IRI")
+		}
+	case ObjectAgent, ObjectGroup:
+		var agent Agent
+		if err := json.Unmarshal(raw, &agent); err != nil {
+			return invalid("object is not an agent")
+		}
+		if err := validateAgent(agent, "object"); err != nil {
+			return err
+		}
+	case ObjectStatementRef:
+		if !isUUID(object.ID) {
+			return invalid("statement reference id must be a UUID")
+		}
+	case ObjectSubStatement:
+		if sub {
+			return invalid("a sub-statement cannot contain a sub-statement")
+		}
+		if _, err := parse(raw, subStatementProperties, true); err != nil {
+			return err
+		}
+	default:
+		return invalid("unknown object type %q", object.ObjectType)
+	}
+
+	if statement.Verb.ID == VerbVoided.ID && object.ObjectType != ObjectStatementRef {
+		return invalid("a voiding statement must reference a statement")
+	}
+	return nil
+}
+
+func validateResult(result *Result) error {
+	if result == nil || result.Score == nil {
+		return nil
+	}
+	score := result.Score
+	if score.Scaled != nil && (*score.Scaled < -1 || *score.Scaled > 1) {
+		return invalid("scaled score must be between -1 and 1")
+	}
+	if score.Min != nil && score.Max != nil && *score.Min >= *score.Max {
+		return invalid("score min must be less than max")
+	}
+	if score.Raw != nil {
+		if score.Min != nil && *score.Raw < *score.Min {
+			return invalid("raw score is below min")
+		}
+		if score.Max != nil && *score.Raw > *score.Max {
+			return invalid("raw score is above max")
+		}
+	}
+	return nil
+}
+
+func validateContext(statement Statement) error {
+	context := statement.Context
+	if context == nil {
+		return nil
+	}
+	if context.Registration != "" && !isUUID(context.Registration) {
+		return invalid("context registration must be a UUID")
+	}
+	isActivity := statement.Object.ObjectType == "" || statement.Object.ObjectType == ObjectActivity
+	if !isActivity && (context.Revision != "" || context.Platform != "") {
+		return invalid("context revision and platform only apply to activities")
+	}
+	if context.Instructor != nil {
+		if err := validateAgent(*context.Instructor, "instructor"); err != nil {
+			return err
+		}
+	}
+	if context.ContextActivities != nil {
+		activities := context.ContextActivities
+		for _, list := range [][]Object{activities.Parent, activities.Grouping, activities.Category, activities.Other} {
+			for _, activity := range list {
+				if !isIRI(activity.ID) {
+					return invalid("context activity id must be an IRI")
+				}
+			}
+		}
+	}
+	if context.Statement != nil && !isUUID(context.Statement.ID) {
+		return invalid("context statement must reference a statement")
+	}
+	return nil
+}
+
+// AgentIdentifier returns the inverse functional identifier of an agent, the key agents are matched on
+func AgentIdentifier(agent Agent) string {
+	switch {
+	case agent.Mbox != "":
+		return "mbox:" + agent.Mbox
+	case agent.MboxSHA1Sum != "":
+		return "mbox_sha1sum:" + agent.MboxSHA1Sum
+	case agent.OpenID != "":
+		return "openid:" + agent.OpenID
+	case agent.Account != nil:
+		return "account:" + agent.Account.HomePage + "|" + agent.Account.Name
+	}
+	return ""
+}
+
+func isUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil && len(value) == 36
+}
+
+func isIRI(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && parsed.Scheme != ""
+}
+
+func isTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil
+}
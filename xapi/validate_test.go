@@ -0,0 +1,133 @@
+package xapi
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	validActor = `{"account":{"homePage":"https://lms.example.com","name":"7"}}`
+	validVerb  = `{"id":"http://adlnet.gov/expapi/verbs/completed"}`
+	validID    = "8f3c2a4e-6b1d-4c5e-9f7a-1b2c3d4e5f60"
+)
+
+func statementJSON(actor, verb, object string, extra string) string {
+	data := `{"actor":` + actor + `,"verb":` + verb + `,"object":` + object
+	if extra != "" {
+		data += "," + extra
+	}
+	return data + "}"
+}
+
+func TestParse(t *testing.T) {
+	activity := `{"id":"https://lms.example.com/xapi/activities/course/1"}`
+
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"minimal", statementJSON(validActor, validVerb, activity, ""), false},
+		{"with id and timestamp", statementJSON(validActor, validVerb, activity, `"id":"`+validID+`","timestamp":"2024-05-01T10:00:00Z"`), false},
+		{"mbox actor", statementJSON(`{"mbox":"mailto:ada@example.com"}`, validVerb, activity, ""), false},
+		{"openid actor", statementJSON(`{"openid":"https://id.example.com/ada"}`, validVerb, activity, ""), false},
+		{"sha1 actor", statementJSON(`{"mbox_sha1sum":"ebd31e95054c018b10727ccffd2ef2ec3a016ee9"}`, validVerb, activity, ""), false},
+		{"anonymous group", statementJSON(`{"objectType":"Group","member":[`+validActor+`]}`, validVerb, activity, ""), false},
+		{"identified group", statementJSON(`{"objectType":"Group","mbox":"mailto:team@example.com"}`, validVerb, activity, ""), false},
+		{"agent object", statementJSON(validActor, validVerb, `{"objectType":"Agent","mbox":"mailto:bob@example.com"}`, ""), false},
+		{"statement ref", statementJSON(validActor, validVerb, `{"objectType":"StatementRef","id":"`+validID+`"}`, ""), false},
+		{"sub-statement", statementJSON(validActor, validVerb, `{"objectType":"SubStatement","actor":`+validActor+`,"verb":`+validVerb+`,"object":`+activity+`}`, ""), false},
+		{"version 1.0.0", statementJSON(validActor, validVerb, activity, `"version":"1.0.0"`), false},
+		{"score", statementJSON(validActor, validVerb, activity, `"result":{"score":{"scaled":0.5,"raw":5,"min":0,"max":10}}`), false},
+		{"context", statementJSON(validActor, validVerb, activity, `"context":{"registration":"`+validID+`","platform":"eduApp","contextActivities":{"parent":[`+activity+`]}}`), false},
+
+		{"not an object", `[]`, true},
+		{"invalid json", `{"actor":`, true},
+		{"unknown property", statementJSON(validActor, validVerb, activity, `"extra":1`), true},
+		{"missing actor", `{"verb":` + validVerb + `,"object":` + activity + `}`, true},
+		{"missing verb", `{"actor":` + validActor + `,"object":` + activity + `}`, true},
+		{"missing object", `{"actor":` + validActor + `,"verb":` + validVerb + `}`, true},
+		{"actor without identifier", statementJSON(`{"name":"Ada"}`, validVerb, activity, ""), true},
+		{"actor with two identifiers", statementJSON(`{"mbox":"mailto:ada@example.com","openid":"https://id.example.com/ada"}`, validVerb, activity, ""), true},
+		{"mbox without mailto", statementJSON(`{"mbox":"ada@example.com"}`, validVerb, activity, ""), true},
+		{"account without name", statementJSON(`{"account":{"homePage":"https://lms.example.com"}}`, validVerb, activity, ""), true},
+		{"agent with members", statementJSON(`{"mbox":"mailto:ada@example.com","member":[`+validActor+`]}`, validVerb, activity, ""), true},
+		{"empty anonymous group", statementJSON(`{"objectType":"Group"}`, validVerb, activity, ""), true},
+		{"nested group", statementJSON(`{"objectType":"Group","member":[{"objectType":"Group","mbox":"mailto:team@example.com"}]}`, validVerb, activity, ""), true},
+		{"unknown actor type", statementJSON(`{"objectType":"Robot","mbox":"mailto:r2@example.com"}`, validVerb, activity, ""), true},
+		{"verb without IRI", statementJSON(validActor, `{"id":"completed"}`, activity, ""), true},
+		{"activity without IRI", statementJSON(validActor, validVerb, `{"id":"course-1"}`, ""), true},
+		{"activity type without IRI", statementJSON(validActor, validVerb, `{"id":"https://x.example.com/1","definition":{"type":"course"}}`, ""), true},
+		{"unknown object type", statementJSON(validActor, validVerb, `{"objectType":"Thing","id":"https://x.example.com/1"}`, ""), true},
+		{"statement ref without UUID", statementJSON(validActor, validVerb, `{"objectType":"StatementRef","id":"123"}`, ""), true},
+		{"nested sub-statement", statementJSON(validActor, validVerb, `{"objectType":"SubStatement","actor":`+validActor+`,"verb":`+validVerb+`,"object":{"objectType":"SubStatement","actor":`+validActor+`,"verb":`+validVerb+`,"object":`+activity+`}}`, ""), true},
+		{"sub-statement with id", statementJSON(validActor, validVerb, `{"objectType":"SubStatement","id":"`+validID+`","actor":`+validActor+`,"verb":`+validVerb+`,"object":`+activity+`}`, ""), true},
+		{"id without UUID", statementJSON(validActor, validVerb, activity, `"id":"abc"`), true},
+		{"bad timestamp", statementJSON(validActor, validVerb, activity, `"timestamp":"yesterday"`), true},
+		{"unsupported version", statementJSON(validActor, validVerb, activity, `"version":"2.0.0"`), true},
+		{"scaled above one", statementJSON(validActor, validVerb, activity, `"result":{"score":{"scaled":1.5}}`), true},
+		{"min not below max", statementJSON(validActor, validVerb, activity, `"result":{"score":{"min":10,"max":10}}`), true},
+		{"raw below min", statementJSON(validActor, validVerb, activity, `"result":{"score":{"raw":-1,"min":0,"max":10}}`), true},
+		{"registration without UUID", statementJSON(validActor, validVerb, activity, `"context":{"registration":"abc"}`), true},
+		{"platform on an agent object", statementJSON(validActor, validVerb, `{"objectType":"Agent","mbox":"mailto:bob@example.com"}`, `"context":{"platform":"eduApp"}`), true},
+		{"context activity without IRI", statementJSON(validActor, validVerb, activity, `"context":{"contextActivities":{"parent":[{"id":"course"}]}}`), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if tc.wantErr && !errors.Is(err, ErrInvalidStatement) {
+				t.Errorf("Parse() error = %v, want ErrInvalidStatement", err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Parse() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestParseVoiding(t *testing.T) {
+	voided := `{"id":"http://adlnet.gov/expapi/verbs/voided"}`
+
+	testCases := []struct {
+		name    string
+		object  string
+		wantErr bool
+	}{
+		{"voids a statement", `{"objectType":"StatementRef","id":"` + validID + `"}`, false},
+		{"voids an activity", `{"id":"https://lms.example.com/xapi/activities/course/1"}`, true},
+		{"voids an agent", `{"objectType":"Agent","mbox":"mailto:bob@example.com"}`, true},
+		{"voids a bad reference", `{"objectType":"StatementRef","id":"not-a-uuid"}`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statement, err := Parse([]byte(statementJSON(validActor, voided, tc.object, "")))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && statement.Object.ID != validID {
+				t.Errorf("voided statement = %q, want %q", statement.Object.ID, validID)
+			}
+		})
+	}
+}
+
+func TestAgentIdentifier(t *testing.T) {
+	testCases := []struct {
+		agent Agent
+		want  string
+	}{
+		{Agent{Mbox: "mailto:ada@example.com"}, "mbox:mailto:ada@example.com"},
+		{Agent{MboxSHA1Sum: "abc"}, "mbox_sha1sum:abc"},
+		{Agent{OpenID: "https://id.example.com/ada"}, "openid:https://id.example.com/ada"},
+		{Agent{Account: &Account{HomePage: "https://lms.example.com", Name: "7"}}, "account:https://lms.example.com|7"},
+		{Agent{Name: "Ada"}, ""},
+	}
+
+	for _, tc := range testCases {
+		if got := AgentIdentifier(tc.agent); got != tc.want {
+			t.Errorf("AgentIdentifier(%+v) = %q, want %q", tc.agent, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package xapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Verbs of the ADL vocabulary emitted by the app
+var (
+	VerbLaunched = Verb{
+		ID:      "http://adlnet.gov/expapi/verbs/launched",
+		Display: LanguageMap{"en-US": "launched"},
+	}
+	VerbCompleted = Verb{
+		ID:      "http://adlnet.gov/expapi/verbs/completed",
+		Display: LanguageMap{"en-US": "completed"},
+	}
+	VerbPassed = Verb{
+		ID:      "http://adlnet.gov/expapi/verbs/passed",
+		Display: LanguageMap{"en-US": "passed"},
+	}
+	VerbScored = Verb{
+		ID:      "http://adlnet.gov/expapi/verbs/scored",
+		Display: LanguageMap{"en-US": "scored"},
+	}
+	VerbVoided = Verb{
+		ID:      "http://adlnet.gov/expapi/verbs/voided",
+		Display: LanguageMap{"en-US": "voided"},
+	}
+)
+
+// Activity types of the ADL vocabulary
+const (
+	ActivityCourse     = "http://adlnet.gov/expapi/activities/course"
+	ActivityLesson     = "http://adlnet.gov/expapi/activities/lesson"
+	ActivityAssessment = "http://adlnet.gov/expapi/activities/assessment"
+)
+
+// Activities builds the activity IRIs and the agents of one app, all of them live under its base URL
+type Activities struct {
+	BaseURL string
+}
+
+func (activities Activities) iri(kind string, id int64) string {
+	return fmt.Sprintf("%s/xapi/activities/%s/%d", strings.TrimRight(activities.BaseURL, "/"), kind, id)
+}
+
+func activity(id, activityType, name string) Object {
+	definition := &Definition{Type: activityType}
+	if name != "" {
+		definition.Name = LanguageMap{"en-US": name}
+	}
+	return Object{ObjectType: ObjectActivity, ID: id, Definition: definition}
+}
+
+// Course is the activity of a course
+func (activities Activities) Course(courseID int64, title string) Object {
+	return activity(activities.iri("course", courseID), ActivityCourse, title)
+}
+
+// Lesson is the activity of a course material
+func (activities Activities) Lesson(materialID int64, title string) Object {
+	return activity(activities.iri("material", materialID), ActivityLesson, title)
+}
+
+// Assignment is the activity of an assignment
+func (activities Activities) Assignment(assignmentID int64, title string) Object {
+	return activity(activities.iri("assignment", assignmentID), ActivityAssessment, title)
+}
+
+// Quiz is the activity of a quiz
+func (activities Activities) Quiz(quizID int64, title string) Object {
+	return activity(activities.iri("quiz", quizID), ActivityAssessment, title)
+}
+
+// Agent identifies a user by their account in the app rather than their email address
+func (activities Activities) Agent(userID int64, name string) Agent {
+	return Agent{
+		ObjectType: ObjectAgent,
+		Name:       name,
+		Account: &Account{
+			HomePage: strings.TrimRight(activities.BaseURL, "/"),
+			Name:     fmt.Sprint(userID),
+		},
+	}
+}
+
+// Authority is the agent the app vouches for statements with
+func (activities Activities) Authority(name string) Agent {
+	return Agent{
+		ObjectType: ObjectAgent,
+		Name:       name,
+		Account: &Account{
+			HomePage: strings.TrimRight(activities.BaseURL, "/"),
+			Name:     name,
+		},
+	}
+}